@@ -13,6 +13,7 @@ var adminCmd = cli.Command{
 
 func adminCommands() []*cobra.Command {
 	return []*cobra.Command{
+		adminAudit(),
 		adminDatabase(),
 		adminServices(),
 		adminHooks(),
@@ -25,6 +26,8 @@ func adminCommands() []*cobra.Command {
 		adminErrors(),
 		adminCurl(),
 		adminFeatures(),
+		adminGroupSync(),
+		adminUsage(),
 	}
 }
 
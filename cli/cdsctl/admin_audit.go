@@ -0,0 +1,81 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ovh/cds/cli"
+	"github.com/ovh/cds/sdk"
+)
+
+var adminAuditCmd = cli.Command{
+	Name:  "audit",
+	Short: "Manage CDS audits",
+}
+
+func adminAudit() *cobra.Command {
+	return cli.NewCommand(adminAuditCmd, nil, []*cobra.Command{
+		cli.NewListCommand(adminAuditAPICallsCmd, adminAuditAPICallsFunc, nil),
+	})
+}
+
+var adminAuditAPICallsCmd = cli.Command{
+	Name:    "api",
+	Short:   "List audited API calls (POST, PUT and DELETE)",
+	Example: "cdsctl admin audit api --project MYPROJ --since 2020-01-01T00:00:00Z",
+	Flags: []cli.Flag{
+		{
+			Name:  "project",
+			Usage: "Filter on project key",
+		},
+		{
+			Name:  "username",
+			Usage: "Filter on username",
+		},
+		{
+			Name:  "since",
+			Usage: "Filter on calls after given date (RFC3339)",
+		},
+		{
+			Name:  "until",
+			Usage: "Filter on calls before given date (RFC3339)",
+		},
+		{
+			Name:    "limit",
+			Usage:   "Maximum number of results",
+			Default: "100",
+		},
+	},
+}
+
+func adminAuditAPICallsFunc(v cli.Values) (cli.ListResult, error) {
+	filter := sdk.AuditAPICallFilter{
+		ProjectKey: v.GetString("project"),
+		Username:   v.GetString("username"),
+	}
+	limit, err := v.GetInt64("limit")
+	if err != nil {
+		return nil, err
+	}
+	filter.Limit = int(limit)
+	if since := v.GetString("since"); since != "" {
+		filter.Since, err = time.Parse(time.RFC3339, since)
+		if err != nil {
+			return nil, fmt.Errorf("invalid given since date: %v", err)
+		}
+	}
+	if until := v.GetString("until"); until != "" {
+		filter.Until, err = time.Parse(time.RFC3339, until)
+		if err != nil {
+			return nil, fmt.Errorf("invalid given until date: %v", err)
+		}
+	}
+
+	as, err := client.AdminAuditAPICalls(filter)
+	if err != nil {
+		return nil, err
+	}
+	return cli.AsListResult(as), nil
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ovh/cds/cli"
+	"github.com/ovh/cds/sdk"
+)
+
+var adminDatabaseRotationCmd = cli.Command{
+	Name:  "rotation",
+	Short: "Manage database keys rotation",
+	Long: `Re-encrypt and re-sign all the data of the API and CDN databases with the latest configured keys.
+
+Add a new key with a greater timestamp to the database encryption or signature rolling keys of the services, restart them, then run:
+
+	$ cdsctl admin database rotation start
+
+The rotation runs in background, by batches, and is resumed if the service restarts. When it is done, the retired keys can be removed from the configuration.`,
+}
+
+func adminDatabaseRotation() *cobra.Command {
+	return cli.NewCommand(adminDatabaseRotationCmd, nil, []*cobra.Command{
+		cli.NewListCommand(adminDatabaseRotationStartCmd, adminDatabaseRotationStartFunc, nil),
+		cli.NewListCommand(adminDatabaseRotationStatusCmd, adminDatabaseRotationStatusFunc, nil),
+	})
+}
+
+var adminDatabaseRotationServiceFlag = cli.Flag{
+	Name:  "service",
+	Usage: "Only manage the database of given service: api or cdn",
+	IsValid: func(s string) bool {
+		return s == "" || s == sdk.TypeAPI || s == sdk.TypeCDN
+	},
+}
+
+type databaseRotationDisplay struct {
+	Database     string `cli:"database,key"`
+	ID           int64  `cli:"id"`
+	Status       string `cli:"status"`
+	Progress     string `cli:"progress"`
+	Tuples       int64  `cli:"tuples"`
+	RetiredKeys  string `cli:"retired_keys"`
+	Error        string `cli:"error"`
+	Started      string `cli:"started"`
+	LastModified string `cli:"last_modified"`
+}
+
+func newDatabaseRotationDisplay(r sdk.DatabaseKeyRotation) databaseRotationDisplay {
+	done, total := r.Progress()
+	d := databaseRotationDisplay{
+		Database:     r.Database,
+		ID:           r.ID,
+		Status:       r.Status,
+		Progress:     fmt.Sprintf("%d/%d entities", done, total),
+		Error:        r.Error,
+		Started:      r.Started.Format("2006-01-02 15:04:05"),
+		LastModified: r.LastModified.Format("2006-01-02 15:04:05"),
+	}
+	for _, e := range r.Entities {
+		d.Tuples += e.Count
+	}
+	if len(r.RetiredKeys) > 0 {
+		d.RetiredKeys = fmt.Sprintf("%v", []int64(r.RetiredKeys))
+	}
+	return d
+}
+
+func adminDatabaseRotationServices(v cli.Values) []string {
+	if s := v.GetString("service"); s != "" {
+		return []string{s}
+	}
+	return []string{sdk.TypeAPI, sdk.TypeCDN}
+}
+
+var adminDatabaseRotationStartCmd = cli.Command{
+	Name:  "start",
+	Short: "Start the rotation of database keys, or display the running one",
+	Flags: []cli.Flag{adminDatabaseRotationServiceFlag},
+}
+
+func adminDatabaseRotationStartFunc(v cli.Values) (cli.ListResult, error) {
+	var res []databaseRotationDisplay
+	for _, s := range adminDatabaseRotationServices(v) {
+		r, err := client.AdminDatabaseKeyRotationStart(s)
+		if err != nil {
+			if s != sdk.TypeAPI && v.GetString("service") == "" {
+				fmt.Fprintf(os.Stderr, "unable to start rotation on %s database: %v\n", s, err)
+				continue
+			}
+			return nil, err
+		}
+		res = append(res, newDatabaseRotationDisplay(r))
+	}
+	return cli.AsListResult(res), nil
+}
+
+var adminDatabaseRotationStatusCmd = cli.Command{
+	Name:  "status",
+	Short: "Display the status of the last rotation of database keys",
+	Flags: []cli.Flag{adminDatabaseRotationServiceFlag},
+}
+
+func adminDatabaseRotationStatusFunc(v cli.Values) (cli.ListResult, error) {
+	var res []databaseRotationDisplay
+	for _, s := range adminDatabaseRotationServices(v) {
+		r, err := client.AdminDatabaseKeyRotationStatus(s)
+		if err != nil {
+			if sdk.ErrorIs(err, sdk.ErrNotFound) {
+				continue
+			}
+			if s != sdk.TypeAPI && v.GetString("service") == "" {
+				fmt.Fprintf(os.Stderr, "unable to get rotation status of %s database: %v\n", s, err)
+				continue
+			}
+			return nil, err
+		}
+		res = append(res, newDatabaseRotationDisplay(r))
+	}
+	return cli.AsListResult(res), nil
+}
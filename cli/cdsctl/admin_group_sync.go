@@ -0,0 +1,46 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ovh/cds/cli"
+	"github.com/ovh/cds/sdk"
+)
+
+var adminGroupSyncCmd = cli.Command{
+	Name:  "group-sync",
+	Short: "Synchronize CDS groups with external groups given by an auth driver",
+	Long: `Compute changes on CDS groups from external groups for all users of given consumer type (ex: ldap).
+By default changes are not applied, use --apply flag to apply them.`,
+	Args: []cli.Arg{
+		{Name: "consumer-type"},
+	},
+	Flags: []cli.Flag{
+		{
+			Name:  "apply",
+			Usage: "apply computed changes",
+			Type:  cli.FlagBool,
+		},
+	},
+}
+
+func adminGroupSync() *cobra.Command {
+	return cli.NewListCommand(adminGroupSyncCmd, adminGroupSyncFunc, nil)
+}
+
+func adminGroupSyncFunc(v cli.Values) (cli.ListResult, error) {
+	report, err := client.AdminAuthGroupSync(sdk.AuthConsumerType(v.GetString("consumer-type")), !v.GetBool("apply"))
+	if err != nil {
+		return nil, err
+	}
+	if len(report.Errors) > 0 {
+		for _, e := range report.Errors {
+			fmt.Fprintln(os.Stderr, "error:", e)
+		}
+		return nil, fmt.Errorf("%d error(s) occurred during the group synchronization", len(report.Errors))
+	}
+	return cli.AsListResult(report.Changes), nil
+}
@@ -1,6 +1,10 @@
 package main
 
 import (
+	"fmt"
+	"os"
+	"time"
+
 	"github.com/spf13/cobra"
 
 	"github.com/ovh/cds/cli"
@@ -9,12 +13,24 @@ import (
 var adminMaintenancesCmd = cli.Command{
 	Name:  "maintenance",
 	Short: "Manage CDS maintenance",
+	Long: `Enable a maintenance to block new workflow runs.
+
+To restart CDS without interrupting running jobs, enable a maintenance in drain mode: hatcheries stop spawning workers
+and no new job is dispatched, but running jobs and jobs already booked by a hatchery are given a max wait to finish.
+Then wait until CDS is drained:
+
+	$ cdsctl admin maintenance enable --hooks --drain --max-wait 30m
+	$ cdsctl admin maintenance status --wait
+
+The max wait is advisory: jobs still running when it is reached are not stopped by CDS, they are interrupted by the
+restart. Waiting jobs and hook events received during the maintenance are processed once it is disabled.`,
 }
 
 func adminMaintenance() *cobra.Command {
 	return cli.NewCommand(adminMaintenancesCmd, nil, []*cobra.Command{
 		cli.NewCommand(adminMaintenanceEnableCmd, adminMaintenanceEnable, nil),
 		cli.NewCommand(adminMaintenanceDisableCmd, adminMaintenanceDisable, nil),
+		cli.NewGetCommand(adminMaintenanceStatusCmd, adminMaintenanceStatus, nil),
 	})
 }
 
@@ -28,11 +44,33 @@ var adminMaintenanceEnableCmd = cli.Command{
 			Default: "false",
 			Type:    cli.FlagBool,
 		},
+		{
+			Name:    "drain",
+			Usage:   "stop dispatching new jobs and let running jobs finish",
+			Default: "false",
+			Type:    cli.FlagBool,
+		},
+		{
+			Name:    "max-wait",
+			Usage:   "maximum time given to running jobs to finish in drain mode",
+			Default: "1h",
+			IsValid: func(s string) bool {
+				d, err := time.ParseDuration(s)
+				return err == nil && d > 0
+			},
+		},
 	},
 }
 
 func adminMaintenanceEnable(v cli.Values) error {
-	return client.Maintenance(true, v.GetBool("hooks"))
+	if !v.GetBool("drain") {
+		return client.Maintenance(true, v.GetBool("hooks"))
+	}
+	maxWait, err := time.ParseDuration(v.GetString("max-wait"))
+	if err != nil {
+		return fmt.Errorf("invalid given max wait: %v", err)
+	}
+	return client.MaintenanceDrain(maxWait, v.GetBool("hooks"))
 }
 
 var adminMaintenanceDisableCmd = cli.Command{
@@ -51,3 +89,63 @@ var adminMaintenanceDisableCmd = cli.Command{
 func adminMaintenanceDisable(v cli.Values) error {
 	return client.Maintenance(false, v.GetBool("hooks"))
 }
+
+var adminMaintenanceStatusCmd = cli.Command{
+	Name:  "status",
+	Short: "Display CDS maintenance status and remaining work",
+	Flags: []cli.Flag{
+		{
+			Name:    "wait",
+			Usage:   "in drain mode, wait until running and booked jobs are done or max wait is reached",
+			Default: "false",
+			Type:    cli.FlagBool,
+		},
+	},
+}
+
+type maintenanceStatusDisplay struct {
+	Enable             bool   `cli:"enable"`
+	Drain              bool   `cli:"drain"`
+	Deadline           string `cli:"deadline"`
+	Drained            bool   `cli:"drained"`
+	BuildingJobs       int64  `cli:"building_jobs"`
+	WaitingJobs        int64  `cli:"waiting_jobs"`
+	BookedJobs         int64  `cli:"booked_jobs"`
+	BufferedHookEvents int64  `cli:"buffered_hook_events"`
+}
+
+func adminMaintenanceStatus(v cli.Values) (interface{}, error) {
+	status, err := client.MaintenanceStatus()
+	if err != nil {
+		return nil, err
+	}
+
+	if v.GetBool("wait") {
+		for !status.Drained() {
+			// The drain could have been stopped while waiting
+			if status.Drain == nil {
+				return nil, fmt.Errorf("no maintenance drain in progress")
+			}
+			fmt.Fprintf(os.Stderr, "%d running jobs, %d booked jobs, waiting until %s...\n", status.BuildingJobs, status.BookedJobs, status.Drain.Deadline.Format(time.RFC3339))
+			time.Sleep(5 * time.Second)
+			status, err = client.MaintenanceStatus()
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	d := maintenanceStatusDisplay{
+		Enable:             status.Enable,
+		Drain:              status.Drain != nil,
+		Drained:            status.Drained(),
+		BuildingJobs:       status.BuildingJobs,
+		WaitingJobs:        status.WaitingJobs,
+		BookedJobs:         status.BookedJobs,
+		BufferedHookEvents: status.BufferedHookEvents,
+	}
+	if status.Drain != nil {
+		d.Deadline = status.Drain.Deadline.Format(time.RFC3339)
+	}
+	return d, nil
+}
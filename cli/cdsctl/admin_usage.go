@@ -0,0 +1,71 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ovh/cds/cli"
+	"github.com/ovh/cds/sdk"
+)
+
+var adminUsageCmd = cli.Command{
+	Name:    "usage",
+	Short:   "List jobs resource usage per day, project, worker model group and worker model",
+	Long:    "Wall and CPU times are in seconds, memory peak in bytes. Usage is computed on the last 30 days by default.",
+	Example: "cdsctl admin usage --project MYPROJ --since 2020-01-01T00:00:00Z",
+	Flags: []cli.Flag{
+		{
+			Name:  "project",
+			Usage: "Filter on project key",
+		},
+		{
+			Name:  "group",
+			Usage: "Filter on worker model group name",
+		},
+		{
+			Name:  "model",
+			Usage: "Filter on worker model name",
+		},
+		{
+			Name:  "since",
+			Usage: "Filter on jobs done after given date (RFC3339)",
+		},
+		{
+			Name:  "until",
+			Usage: "Filter on jobs done before given date (RFC3339)",
+		},
+	},
+}
+
+func adminUsage() *cobra.Command {
+	return cli.NewListCommand(adminUsageCmd, adminUsageFunc, nil)
+}
+
+func adminUsageFunc(v cli.Values) (cli.ListResult, error) {
+	filter := sdk.JobUsageFilter{
+		ProjectKey: v.GetString("project"),
+		GroupName:  v.GetString("group"),
+		ModelName:  v.GetString("model"),
+	}
+	var err error
+	if since := v.GetString("since"); since != "" {
+		filter.Since, err = time.Parse(time.RFC3339, since)
+		if err != nil {
+			return nil, fmt.Errorf("invalid given since date: %v", err)
+		}
+	}
+	if until := v.GetString("until"); until != "" {
+		filter.Until, err = time.Parse(time.RFC3339, until)
+		if err != nil {
+			return nil, fmt.Errorf("invalid given until date: %v", err)
+		}
+	}
+
+	ss, err := client.AdminJobUsages(filter)
+	if err != nil {
+		return nil, err
+	}
+	return cli.AsListResult(ss), nil
+}
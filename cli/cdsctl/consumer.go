@@ -2,6 +2,7 @@ package main
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
@@ -25,6 +26,8 @@ func consumer() *cobra.Command {
 			cli.NewCommand(authConsumerNewCmd, authConsumerNewRun, nil),
 			cli.NewCommand(authConsumerDeleteCmd, authConsumerDeleteRun, nil),
 			cli.NewCommand(authConsumerRegenCmd, authConsumerRegenRun, nil),
+			cli.NewCommand(authConsumerCIDRCmd, authConsumerCIDRRun, nil),
+			cli.NewListCommand(authConsumerRejectionListCmd, authConsumerRejectionListRun, nil),
 		},
 	)
 }
@@ -87,6 +90,21 @@ var authConsumerNewCmd = cli.Command{
 			Type:  cli.FlagSlice,
 			Usage: "Define the list of scopes for the consumer",
 		},
+		{
+			Name:  "project-keys",
+			Type:  cli.FlagSlice,
+			Usage: "Restrict all given scopes to this list of project keys",
+		},
+		{
+			Name:  "workflow-names",
+			Type:  cli.FlagSlice,
+			Usage: "Restrict all given scopes to this list of workflow names",
+		},
+		{
+			Name:  "allowed-cidrs",
+			Type:  cli.FlagSlice,
+			Usage: "Define the list of CIDRs from which the consumer can be used (ex: 10.0.0.0/8)",
+		},
 	},
 }
 
@@ -154,11 +172,18 @@ func authConsumerNewRun(v cli.Values) error {
 		}
 	}
 
+	scopeDetails := sdk.NewAuthConsumerScopeDetails(scopes...)
+	for i := range scopeDetails {
+		scopeDetails[i].ProjectKeys = v.GetStringSlice("project-keys")
+		scopeDetails[i].WorkflowNames = v.GetStringSlice("workflow-names")
+	}
+
 	res, err := client.AuthConsumerCreateForUser(username, sdk.AuthConsumer{
 		Name:         name,
 		Description:  description,
 		GroupIDs:     groupIDs,
-		ScopeDetails: sdk.NewAuthConsumerScopeDetails(scopes...),
+		ScopeDetails: scopeDetails,
+		AllowedCIDRs: v.GetStringSlice("allowed-cidrs"),
 	})
 	if err != nil {
 		return err
@@ -232,3 +257,75 @@ func authConsumerRegenRun(v cli.Values) error {
 
 	return nil
 }
+
+var authConsumerCIDRCmd = cli.Command{
+	Name:  "cidr",
+	Short: "Set the list of CIDRs from which an auth consumer can be used",
+	Long:  "Set the list of CIDRs from which an auth consumer can be used, if no CIDR is given the consumer can be used from anywhere.",
+	OptionalArgs: []cli.Arg{
+		{
+			Name: "username",
+		},
+	},
+	Args: []cli.Arg{
+		{
+			Name: consumerIDArg,
+		},
+	},
+	Flags: []cli.Flag{
+		{
+			Name:  "allowed-cidrs",
+			Type:  cli.FlagSlice,
+			Usage: "Define the list of CIDRs from which the consumer can be used (ex: 10.0.0.0/8)",
+		},
+	},
+}
+
+func authConsumerCIDRRun(v cli.Values) error {
+	username := v.GetString("username")
+	if username == "" {
+		username = "me"
+	}
+
+	consumerID := v.GetString(consumerIDArg)
+	consumer, err := client.AuthConsumerSetAllowedCIDRs(username, consumerID, v.GetStringSlice("allowed-cidrs"))
+	if err != nil {
+		return err
+	}
+	if len(consumer.AllowedCIDRs) == 0 {
+		fmt.Printf("Consumer '%s' can now be used from anywhere.\n", consumerID)
+	} else {
+		fmt.Printf("Consumer '%s' can now be used from: %s.\n", consumerID, strings.Join(consumer.AllowedCIDRs, ", "))
+	}
+
+	return nil
+}
+
+var authConsumerRejectionListCmd = cli.Command{
+	Name:    "rejection",
+	Aliases: []string{"rejections"},
+	Short:   "List last authentication attempts rejected for an auth consumer because of its allowed CIDRs",
+	OptionalArgs: []cli.Arg{
+		{
+			Name: "username",
+		},
+	},
+	Args: []cli.Arg{
+		{
+			Name: consumerIDArg,
+		},
+	},
+}
+
+func authConsumerRejectionListRun(v cli.Values) (cli.ListResult, error) {
+	username := v.GetString("username")
+	if username == "" {
+		username = "me"
+	}
+
+	rs, err := client.AuthConsumerIPRejectionList(username, v.GetString(consumerIDArg))
+	if err != nil {
+		return nil, err
+	}
+	return cli.AsListResult(rs), nil
+}
@@ -76,6 +76,7 @@ func rootFromSubCommands(cmds []*cobra.Command) *cobra.Command {
 			cmd.Name() == "reset-password" ||
 			cmd.Name() == "confirm" ||
 			cmd.Name() == "version" ||
+			(cmd.Name() == "exec" && cmd.Parent() != nil && cmd.Parent().Name() == "pipeline") ||
 			cmd.Name() == "doc" || strings.HasPrefix(cmd.Use, "doc ") || (cmd.Run == nil && cmd.RunE == nil) {
 			return
 		}
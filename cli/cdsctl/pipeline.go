@@ -1,16 +1,22 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 
 	"github.com/ovh/cds/cli"
+	"github.com/ovh/cds/engine/worker/pkg/localexec"
 	"github.com/ovh/cds/sdk"
 	"github.com/ovh/cds/sdk/cdsclient"
 	"github.com/ovh/cds/sdk/exportentities"
+	"github.com/ovh/cds/sdk/log"
 )
 
 var pipelineCmd = cli.Command{
@@ -26,6 +32,10 @@ func pipeline() *cobra.Command {
 		cli.NewDeleteCommand(pipelineDeleteCmd, pipelineDeleteRun, nil, withAllCommandModifiers()...),
 		cli.NewCommand(pipelineExportCmd, pipelineExportRun, nil, withAllCommandModifiers()...),
 		cli.NewCommand(pipelineImportCmd, pipelineImportRun, nil, withAllCommandModifiers()...),
+		cli.NewCommand(pipelineExecCmd, pipelineExecRun, nil),
+		cli.NewCommand(pipelinePublishCmd, pipelinePublishRun, nil, withAllCommandModifiers()...),
+		cli.NewCommand(pipelineUseCmd, pipelineUseRun, nil, withAllCommandModifiers()...),
+		pipelineShared(),
 	})
 }
 
@@ -173,3 +183,130 @@ func pipelineDeleteRun(v cli.Values) error {
 	}
 	return err
 }
+
+var pipelineExecCmd = cli.Command{
+	Name:  "exec",
+	Short: "Run a CDS pipeline locally",
+	Long: `PATH: Path of the pipeline file to run
+
+Run a pipeline on your machine without CDS API: stages are executed in order and jobs of a stage one after the other.
+Only script, artifactUpload, artifactDownload, jUnitReport and coverage steps are supported.
+Artifacts are stored in a local directory, one sub directory by tag.
+
+	cdsctl pipeline exec build.pip.yml -p version=1.0.0 --docker-image golang:1.14
+`,
+	Args: []cli.Arg{
+		{Name: "path"},
+	},
+	Flags: []cli.Flag{
+		{
+			Name:      "parameter",
+			ShortHand: "p",
+			Usage:     "Set a pipeline parameter value (name=value)",
+			Type:      cli.FlagSlice,
+			IsValid: func(s string) bool {
+				return s == "" || strings.Contains(s, "=")
+			},
+		},
+		{
+			Name:    "artifacts-dir",
+			Usage:   "Directory where artifacts are stored",
+			Default: "artifacts",
+		},
+		{
+			Name:  "workspace",
+			Usage: "Directory where jobs are executed, a temporary directory is used and removed if not set",
+		},
+		{
+			Name:  "docker-image",
+			Usage: "Execute script steps in a container of the given docker image",
+		},
+	},
+}
+
+func pipelineExecRun(v cli.Values) error {
+	if !v.GetBool("verbose") && os.Getenv("CDS_VERBOSE") != "true" {
+		log.Initialize(context.Background(), &log.Conf{Level: "error"})
+	}
+
+	path := v.GetString("path")
+	format, err := exportentities.GetFormatFromPath(path)
+	if err != nil {
+		return err
+	}
+	btes, err := ioutil.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("unable to read file %s: %v", path, err)
+	}
+	payload, err := exportentities.ParsePipeline(format, btes)
+	if err != nil {
+		return err
+	}
+	pip, err := payload.Pipeline()
+	if err != nil {
+		return err
+	}
+
+	params := make(map[string]string)
+	for _, p := range v.GetStringSlice("parameter") {
+		if p == "" {
+			continue
+		}
+		splitted := strings.SplitN(p, "=", 2)
+		params[splitted[0]] = splitted[1]
+	}
+
+	workspace := v.GetString("workspace")
+	if workspace == "" {
+		workspace, err = ioutil.TempDir("", "cdsctl-pipeline-exec")
+		if err != nil {
+			return err
+		}
+		defer os.RemoveAll(workspace) // nolint
+	}
+
+	res, err := localexec.Run(context.Background(), *pip, localexec.Options{
+		Workspace:    workspace,
+		ArtifactsDir: v.GetString("artifacts-dir"),
+		DockerImage:  v.GetString("docker-image"),
+		Parameters:   params,
+		Output:       os.Stdout,
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Println()
+	var failed bool
+	for _, r := range res {
+		char := cli.OKChar
+		switch r.Status {
+		case sdk.StatusFail:
+			char = cli.KOChar
+			failed = true
+		case sdk.StatusNeverBuilt, sdk.StatusDisabled:
+			char = "-"
+		}
+		line := fmt.Sprintf("%s %s: %s", char, r.FullName(), r.Status)
+		if r.Duration > 0 {
+			line += fmt.Sprintf(" (%s)", sdk.Round(r.Duration, time.Second))
+		}
+		if r.Tests != nil {
+			line += fmt.Sprintf(" - tests: %d ok, %d ko, %d skipped", r.Tests.TotalOK, r.Tests.TotalKO, r.Tests.TotalSkipped)
+		}
+		if r.Coverage != nil && r.Coverage.TotalLines > 0 {
+			line += fmt.Sprintf(" - coverage: %.2f%%", float64(r.Coverage.CoveredLines)*100/float64(r.Coverage.TotalLines))
+		}
+		fmt.Println(line)
+		for _, s := range r.Steps {
+			if s.Status == sdk.StatusFail {
+				fmt.Printf("    %s step %s: %s\n", cli.KOChar, s.Name, s.Reason)
+			}
+		}
+	}
+
+	if failed {
+		return fmt.Errorf("pipeline %s failed", pip.Name)
+	}
+	return nil
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ovh/cds/cli"
+	"github.com/ovh/cds/sdk"
+)
+
+var pipelinePublishCmd = cli.Command{
+	Name:  "publish",
+	Short: "Publish a new version of a pipeline as a shared pipeline of a group",
+	Ctx: []cli.Arg{
+		{Name: _ProjectKey},
+	},
+	Args: []cli.Arg{
+		{Name: "pipeline-name"},
+		{Name: "group-name"},
+	},
+	Flags: []cli.Flag{
+		{
+			Name:  "name",
+			Usage: "Name of the shared pipeline, default to the pipeline name",
+		},
+		{
+			Name:  "description",
+			Usage: "Description of the shared pipeline",
+		},
+	},
+}
+
+func pipelinePublishRun(v cli.Values) error {
+	sp, err := client.PipelinePublish(v.GetString(_ProjectKey), v.GetString("pipeline-name"), sdk.SharedPipelinePublishRequest{
+		GroupName:   v.GetString("group-name"),
+		Name:        v.GetString("name"),
+		Description: v.GetString("description"),
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Shared pipeline %s/%s@%d published\n", v.GetString("group-name"), sp.Name, sp.Version)
+	return nil
+}
+
+var pipelineUseCmd = cli.Command{
+	Name:  "use",
+	Short: "Create or upgrade a project pipeline from a shared pipeline",
+	Long:  "REF: Shared pipeline reference as group/name@version, the latest version is used if no version is given",
+	Ctx: []cli.Arg{
+		{Name: _ProjectKey},
+	},
+	Args: []cli.Arg{
+		{Name: "ref"},
+	},
+	Flags: []cli.Flag{
+		{
+			Name:  "force",
+			Usage: "Overwrite the changes made to the project pipeline since it was created from the shared pipeline without confirmation",
+			Type:  cli.FlagBool,
+		},
+	},
+}
+
+func pipelineUseRun(v cli.Values) error {
+	req := sdk.SharedPipelineUseRequest{Ref: v.GetString("ref"), Force: v.GetBool("force")}
+	pip, err := client.PipelineUseShared(v.GetString(_ProjectKey), req)
+	if !req.Force && sdk.ErrorIs(err, sdk.ErrConflictData) {
+		fmt.Println(err)
+		if !cli.AskConfirm("Do you want to overwrite the changes?") {
+			return nil
+		}
+		req.Force = true
+		pip, err = client.PipelineUseShared(v.GetString(_ProjectKey), req)
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Pipeline %s created from %s\n", pip.Name, v.GetString("ref"))
+	return nil
+}
+
+var pipelineSharedCmd = cli.Command{
+	Name:  "shared",
+	Short: "Manage CDS shared pipelines",
+}
+
+func pipelineShared() *cobra.Command {
+	return cli.NewCommand(pipelineSharedCmd, nil, []*cobra.Command{
+		cli.NewListCommand(pipelineSharedListCmd, pipelineSharedListRun, nil),
+		cli.NewListCommand(pipelineSharedUsageCmd, pipelineSharedUsageRun, nil),
+	})
+}
+
+var pipelineSharedListCmd = cli.Command{
+	Name:  "list",
+	Short: "List shared pipelines available for your groups",
+}
+
+type sharedPipelineDisplay struct {
+	Group       string `cli:"group,key"`
+	Name        string `cli:"name,key"`
+	Version     int64  `cli:"version"`
+	Description string `cli:"description"`
+	From        string `cli:"from"`
+	Author      string `cli:"author"`
+}
+
+func pipelineSharedListRun(v cli.Values) (cli.ListResult, error) {
+	sps, err := client.SharedPipelineList()
+	if err != nil {
+		return nil, err
+	}
+	res := make([]sharedPipelineDisplay, len(sps))
+	for i, sp := range sps {
+		res[i] = sharedPipelineDisplay{
+			Name:        sp.Name,
+			Version:     sp.Version,
+			Description: sp.Description,
+			From:        sp.FromProjectKey + "/" + sp.FromPipelineName,
+			Author:      sp.Author,
+		}
+		if sp.Group != nil {
+			res[i].Group = sp.Group.Name
+		}
+	}
+	return cli.AsListResult(res), nil
+}
+
+var pipelineSharedUsageCmd = cli.Command{
+	Name:  "usage",
+	Short: "List project pipelines and workflows that use a shared pipeline",
+	Args: []cli.Arg{
+		{Name: "group-name"},
+		{Name: "name"},
+	},
+}
+
+func pipelineSharedUsageRun(v cli.Values) (cli.ListResult, error) {
+	us, err := client.SharedPipelineUsage(v.GetString("group-name"), v.GetString("name"))
+	if err != nil {
+		return nil, err
+	}
+	return cli.AsListResult(us), nil
+}
@@ -26,6 +26,8 @@ func projectCommands() []*cobra.Command {
 		cli.NewCommand(projectCreateCmd, projectCreateRun, nil),
 		cli.NewDeleteCommand(projectDeleteCmd, projectDeleteRun, nil, withAllCommandModifiers()...),
 		cli.NewCommand(projectFavoriteCmd, projectFavoriteRun, nil, withAllCommandModifiers()...),
+		cli.NewCommand(projectExportCmd, projectExportRun, nil, withAllCommandModifiers()...),
+		cli.NewListCommand(projectImportCmd, projectImportRun, nil, withAllCommandModifiers()...),
 		projectKey(),
 		projectGroup(),
 		projectVariable(),
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/ovh/cds/cli"
+	"github.com/ovh/cds/sdk/exportentities"
+)
+
+var projectExportCmd = cli.Command{
+	Name:  "export",
+	Short: "Export a CDS project with all its entities in a tar bundle",
+	Long: `Export the project settings, variables, keys, integrations, groups, applications, environments, pipelines and workflows
+in a tar bundle. Secrets are encrypted with the given passphrase, the same passphrase should be used to import the bundle.
+
+	cdsctl project export MYPROJECT --passphrase mysecret --output myproject.tar`,
+	Ctx: []cli.Arg{
+		{Name: _ProjectKey},
+	},
+	Flags: []cli.Flag{
+		{
+			Name:      "output",
+			ShortHand: "o",
+			Usage:     "Output file, default is <project key>.tar",
+		},
+		{
+			Name:  "passphrase",
+			Usage: "Passphrase used to encrypt the secrets, can also be set with CDS_PROJECT_BUNDLE_PASSPHRASE",
+		},
+	},
+}
+
+func projectExportRun(v cli.Values) error {
+	key := v.GetString(_ProjectKey)
+	output := v.GetString("output")
+	if output == "" {
+		output = key + ".tar"
+	}
+
+	btes, err := client.ProjectExport(key, projectBundlePassphrase(v))
+	if err != nil {
+		return err
+	}
+
+	if err := ioutil.WriteFile(output, btes, os.FileMode(0600)); err != nil {
+		return fmt.Errorf("cannot write file %s: %v", output, err)
+	}
+	fmt.Println(output)
+	return nil
+}
+
+var projectImportCmd = cli.Command{
+	Name:  "import",
+	Short: "Import a CDS project from a tar bundle",
+	Long: `Create or update a project from a bundle generated by "cdsctl project export". The project key of the bundle is used
+unless another key is given. Entities of an existing project that are not in the bundle are kept.
+
+	cdsctl project import myproject.tar --passphrase mysecret --key MYNEWPROJECT --dry-run`,
+	Args: []cli.Arg{
+		{Name: "path"},
+	},
+	Flags: []cli.Flag{
+		{
+			Name:  "key",
+			Usage: "Key of the imported project, default is the key of the bundle",
+		},
+		{
+			Name:  "passphrase",
+			Usage: "Passphrase used to decrypt the secrets, can also be set with CDS_PROJECT_BUNDLE_PASSPHRASE",
+		},
+		{
+			Type:  cli.FlagBool,
+			Name:  "dry-run",
+			Usage: "Check the import and display the changes that would be made on the project without saving them",
+		},
+	},
+}
+
+func projectImportRun(v cli.Values) (cli.ListResult, error) {
+	btes, err := ioutil.ReadFile(v.GetString("path"))
+	if err != nil {
+		return nil, fmt.Errorf("cannot read file %s: %v", v.GetString("path"), err)
+	}
+
+	key := v.GetString("key")
+	if key == "" {
+		b, err := exportentities.UntarProjectBundle(context.Background(), tar.NewReader(bytes.NewReader(btes)))
+		if err != nil {
+			return nil, err
+		}
+		key = b.Project.Key
+	}
+
+	res, err := client.ProjectImport(key, projectBundlePassphrase(v), bytes.NewReader(btes), v.GetBool("dry-run"))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, w := range res.Warnings {
+		fmt.Fprintln(os.Stderr, cli.Yellow("Warning: "+w))
+	}
+	return cli.AsListResult(res.Changes), nil
+}
+
+func projectBundlePassphrase(v cli.Values) string {
+	if p := v.GetString("passphrase"); p != "" {
+		return p
+	}
+	if p := os.Getenv("CDS_PROJECT_BUNDLE_PASSPHRASE"); p != "" {
+		return p
+	}
+	if v.GetBool("no-interactive") {
+		return ""
+	}
+	return cli.AskPassword("Bundle passphrase")
+}
@@ -23,6 +23,7 @@ func workflow() *cobra.Command {
 		cli.NewCommand(templateApplyCmd("applyTemplate"), templateApplyRun, nil, withAllCommandModifiers()...),
 		cli.NewListCommand(workflowListCmd, workflowListRun, nil, withAllCommandModifiers()...),
 		cli.NewListCommand(workflowHistoryCmd, workflowHistoryRun, nil, withAllCommandModifiers()...),
+		cli.NewListCommand(workflowTestsCmd, workflowTestsRun, nil, withAllCommandModifiers()...),
 		cli.NewGetCommand(workflowShowCmd, workflowShowRun, nil, withAllCommandModifiers()...),
 		cli.NewGetCommand(workflowStatusCmd, workflowStatusRun, nil, withAllCommandModifiers()...),
 		cli.NewCommand(workflowRunManualCmd, workflowRunManualRun, nil, withAllCommandModifiers()...),
@@ -7,6 +7,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/ovh/cds/cli"
+	"github.com/ovh/cds/sdk"
 )
 
 var workflowAdvancedCmd = cli.Command{
@@ -18,6 +19,7 @@ func workflowAdvanced() *cobra.Command {
 	return cli.NewCommand(workflowAdvancedCmd, nil, []*cobra.Command{
 		cli.NewDeleteCommand(workflowDeleteCmd, workflowDeleteRun, nil, withAllCommandModifiers()...),
 		workflowAdvancedRunNumber(),
+		cli.NewListCommand(workflowPurgeDryRunCmd, workflowPurgeDryRunRun, nil, withAllCommandModifiers()...),
 	})
 }
 
@@ -68,3 +70,66 @@ func workflowRunNumberSetRun(v cli.Values) error {
 
 	return client.WorkflowRunNumberSet(v.GetString(_ProjectKey), v.GetString(_WorkflowName), number)
 }
+
+var workflowPurgeDryRunCmd = cli.Command{
+	Name:  "purge-dry-run",
+	Short: "List the runs that would be deleted by the purge policy of a workflow",
+	Long: `List the runs that would be deleted by the purge policy saved on the workflow.
+
+Use flags to preview another purge policy before saving it on the workflow.`,
+	Example: `cdsctl workflow advanced purge-dry-run MYPROJECT my-workflow --keep-last-per-branch 5 --keep-releases`,
+	Ctx: []cli.Arg{
+		{Name: _ProjectKey},
+		{Name: _WorkflowName},
+	},
+	Flags: []cli.Flag{
+		{
+			Name:  "keep-last-per-branch",
+			Usage: "Number of runs kept for each git branch",
+		},
+		{
+			Name:  "keep-releases",
+			Type:  cli.FlagBool,
+			Usage: "Keep all the runs triggered on a git tag",
+		},
+		{
+			Name:  "keep-days",
+			Usage: "Keep all the runs younger than given number of days",
+		},
+		{
+			Name:  "delete-failed-after-days",
+			Usage: "Delete failed runs older than given number of days",
+		},
+	},
+}
+
+func workflowPurgeDryRunRun(v cli.Values) (cli.ListResult, error) {
+	var policy sdk.WorkflowPurgePolicy
+	var err error
+	if v.GetString("keep-last-per-branch") != "" {
+		if policy.KeepLastPerBranch, err = v.GetInt64("keep-last-per-branch"); err != nil {
+			return nil, err
+		}
+	}
+	policy.KeepReleases = v.GetBool("keep-releases")
+	if v.GetString("keep-days") != "" {
+		if policy.KeepDays, err = v.GetInt64("keep-days"); err != nil {
+			return nil, err
+		}
+	}
+	if v.GetString("delete-failed-after-days") != "" {
+		if policy.DeleteFailedAfterDays, err = v.GetInt64("delete-failed-after-days"); err != nil {
+			return nil, err
+		}
+	}
+
+	var p *sdk.WorkflowPurgePolicy
+	if !policy.IsEmpty() {
+		p = &policy
+	}
+	runs, err := client.WorkflowPurgeDryRun(v.GetString(_ProjectKey), v.GetString(_WorkflowName), p)
+	if err != nil {
+		return nil, err
+	}
+	return cli.AsListResult(runs), nil
+}
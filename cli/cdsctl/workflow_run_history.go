@@ -29,6 +29,13 @@ var workflowHistoryCmd = cli.Command{
 			},
 		},
 	},
+	Flags: []cli.Flag{
+		{
+			Name:  "archived",
+			Type:  cli.FlagBool,
+			Usage: "List the runs archived by the purge, use 'cdsctl workflow status <run-number> --archived' to display one of them",
+		},
+	},
 }
 
 func workflowHistoryRun(v cli.Values) (cli.ListResult, error) {
@@ -50,6 +57,14 @@ func workflowHistoryRun(v cli.Values) (cli.ListResult, error) {
 		}
 	}
 
+	if v.GetBool("archived") {
+		archives, err := client.WorkflowRunArchiveList(v.GetString(_ProjectKey), v.GetString(_WorkflowName), offset, limit)
+		if err != nil {
+			return nil, err
+		}
+		return cli.AsListResult(archives), nil
+	}
+
 	w, err := client.WorkflowRunList(v.GetString(_ProjectKey), v.GetString(_WorkflowName), offset, limit)
 	if err != nil {
 		return nil, err
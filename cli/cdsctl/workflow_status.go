@@ -31,10 +31,23 @@ var workflowStatusCmd = cli.Command{
 			Type:  cli.FlagBool,
 			Usage: "Wait the workflow to be over",
 		},
+		{
+			Name:  "archived",
+			Type:  cli.FlagBool,
+			Usage: "Display a run archived by the purge, the run number is mandatory",
+		},
 	},
 }
 
 func workflowStatusRun(v cli.Values) (interface{}, error) {
+	if v.GetBool("archived") {
+		if v.GetBool("track") {
+			return nil, fmt.Errorf("an archived run cannot be tracked")
+		}
+		if v.GetString("run-number") == "" {
+			return nil, fmt.Errorf("run-number is mandatory to display an archived run")
+		}
+	}
 	if !v.GetBool("track") {
 		return workflowStatusRunWithoutTrack(v)
 	}
@@ -154,9 +167,19 @@ func workflowStatusRunWithoutTrack(v cli.Values) (interface{}, error) {
 		runNumber = runs[0].Number
 	}
 
-	run, err := client.WorkflowRunGet(v.GetString(_ProjectKey), v.GetString(_WorkflowName), runNumber)
-	if err != nil {
-		return nil, err
+	var run *sdk.WorkflowRun
+	if v.GetBool("archived") {
+		data, err := client.WorkflowRunArchiveGet(v.GetString(_ProjectKey), v.GetString(_WorkflowName), runNumber)
+		if err != nil {
+			return nil, err
+		}
+		run = &data.Run
+	} else {
+		var err error
+		run, err = client.WorkflowRunGet(v.GetString(_ProjectKey), v.GetString(_WorkflowName), runNumber)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	var tags []string
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/ovh/cds/cli"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/cdsclient"
+)
+
+var workflowTestsCmd = cli.Command{
+	Name:  "tests",
+	Short: "List the slowest, failing and flaky tests of the last workflow runs",
+	Long: `Test results are grouped by pipeline, test suite and test name.
+
+A test is flaky if it passed and failed on the same commit, or if its status often changed on the last runs.`,
+	Ctx: []cli.Arg{
+		{Name: _ProjectKey},
+		{Name: _WorkflowName},
+	},
+	Flags: []cli.Flag{
+		{
+			Name:  "branch",
+			Usage: "Filter on git branch",
+		},
+		{
+			Name:  "node",
+			Usage: "Filter on pipeline name in the workflow",
+		},
+		{
+			Name:    "runs",
+			Usage:   "Number of workflow runs to check (max 50)",
+			Default: "20",
+		},
+		{
+			Name:    "limit",
+			Usage:   "Maximum number of tests listed for each category",
+			Default: "10",
+		},
+	},
+}
+
+type workflowTestsItem struct {
+	Category        string `cli:"category"`
+	NodeName        string `cli:"node"`
+	Suite           string `cli:"suite"`
+	Name            string `cli:"name"`
+	Runs            int    `cli:"runs"`
+	Failures        int    `cli:"failures"`
+	LastStatus      string `cli:"last_status"`
+	AverageDuration string `cli:"average_duration"`
+	FlipRate        string `cli:"flip_rate"`
+	FlakyReason     string `cli:"flaky_reason"`
+}
+
+func workflowTestsRun(v cli.Values) (cli.ListResult, error) {
+	summary, err := client.WorkflowTests(v.GetString(_ProjectKey), v.GetString(_WorkflowName),
+		cdsclient.WithQueryParameter("branch", v.GetString("branch")),
+		cdsclient.WithQueryParameter("node", v.GetString("node")),
+		cdsclient.WithQueryParameter("runs", v.GetString("runs")),
+		cdsclient.WithQueryParameter("limit", v.GetString("limit")),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	var items []workflowTestsItem
+	for _, c := range []struct {
+		name  string
+		tests []sdk.TestCaseHistory
+	}{
+		{"slowest", summary.Slowest},
+		{"failing", summary.Failing},
+		{"flaky", summary.Flaky},
+	} {
+		for _, t := range c.tests {
+			items = append(items, workflowTestsItem{
+				Category:        c.name,
+				NodeName:        t.NodeName,
+				Suite:           t.Suite,
+				Name:            t.Name,
+				Runs:            t.Runs,
+				Failures:        t.Failures,
+				LastStatus:      t.LastStatus,
+				AverageDuration: fmt.Sprintf("%.3fs", t.AverageDuration),
+				FlipRate:        fmt.Sprintf("%.0f%%", t.FlipRate*100),
+				FlakyReason:     t.FlakyReason,
+			})
+		}
+	}
+
+	return cli.AsListResult(items), nil
+}
@@ -66,6 +66,59 @@ func CheckChildrenForGroupIDs(ctx context.Context, db gorp.SqlExecutor, a *sdk.A
 	return handleChildrenError(a, children)
 }
 
+// CompleteChildrenForGroupIDs replaces given action children by the actions loaded for given group ids, useful
+// for actions that are not stored in database. Children attributes and parameters values are kept.
+func CompleteChildrenForGroupIDs(ctx context.Context, db gorp.SqlExecutor, a *sdk.Action, groupIDs []int64) error {
+	if len(a.Actions) == 0 {
+		return nil
+	}
+
+	children, err := LoadAllByIDsWithTypeBuiltinOrPluginOrDefaultInGroupIDs(ctx, db, a.ToUniqueChildrenIDs(), groupIDs, LoadOptions.Default)
+	if err != nil {
+		return err
+	}
+	if err := handleChildrenError(a, children); err != nil {
+		return err
+	}
+
+	mChildren := make(map[int64]sdk.Action, len(children))
+	for i := range children {
+		mChildren[children[i].ID] = children[i]
+	}
+
+	for i := range a.Actions {
+		step := a.Actions[i]
+		child := mChildren[step.ID]
+		child.StepName = step.StepName
+		child.Optional = step.Optional
+		child.AlwaysExecuted = step.AlwaysExecuted
+		child.Enabled = step.Enabled
+
+		// replace action parameter with value given in the step
+		params := make([]sdk.Parameter, len(child.Parameters))
+		for j := range child.Parameters {
+			params[j] = child.Parameters[j]
+			// default value for parameter type list should be the first item ("aa;bb;cc" -> "aa")
+			if params[j].Type == sdk.ListParameter && strings.Contains(params[j].Value, ";") {
+				params[j].Value = strings.Split(params[j].Value, ";")[0]
+			}
+			for k := range step.Parameters {
+				if strings.ToLower(step.Parameters[k].Name) == strings.ToLower(params[j].Name) {
+					params[j].Value = step.Parameters[k].Value
+					break
+				}
+			}
+		}
+		child.Parameters = params
+
+		a.Actions[i] = child
+	}
+
+	a.Requirements = a.FlattenRequirements()
+
+	return nil
+}
+
 // CheckChildrenForGroupIDsWithLoop return an error if given children not found or tree loop detected.
 func CheckChildrenForGroupIDsWithLoop(ctx context.Context, db gorp.SqlExecutor, a *sdk.Action, groupIDs []int64) error {
 	return checkChildrenForGroupIDsWithLoopStep(ctx, db, a, a, groupIDs)
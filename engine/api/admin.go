@@ -6,21 +6,38 @@ import (
 	"io/ioutil"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/gorilla/mux"
 
 	"github.com/ovh/cds/engine/api/database/gorpmapping"
 	"github.com/ovh/cds/engine/api/services"
+	"github.com/ovh/cds/engine/api/workflow"
 	"github.com/ovh/cds/engine/featureflipping"
+	"github.com/ovh/cds/engine/gorpmapper"
 	"github.com/ovh/cds/engine/service"
 	"github.com/ovh/cds/sdk"
 	"github.com/ovh/cds/sdk/log"
 )
 
+// Running jobs are given one hour to finish by default when a maintenance is enabled in drain mode, the max wait is
+// advisory and jobs still running after it are not stopped
+const maintenanceDrainDefaultMaxWait = time.Hour
+
 func (api *API) postMaintenanceHandler() service.Handler {
 	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
 		enable := FormBool(r, "enable")
 		hook := FormBool(r, "withHook")
+		drain := FormBool(r, "drain")
+
+		maxWait := maintenanceDrainDefaultMaxWait
+		if v := FormString(r, "maxWait"); v != "" {
+			d, err := time.ParseDuration(v)
+			if err != nil || d <= 0 {
+				return sdk.NewErrorFrom(sdk.ErrWrongRequest, "invalid given max wait %q", v)
+			}
+			maxWait = d
+		}
 
 		if hook {
 			srvs, err := services.LoadAllByType(ctx, api.mustDB(), sdk.TypeHooks)
@@ -34,6 +51,16 @@ func (api *API) postMaintenanceHandler() service.Handler {
 			}
 		}
 
+		// The drain state should be saved before publishing the maintenance state to all API instances
+		if enable && drain {
+			now := time.Now()
+			if err := api.Cache.SetWithTTL(sdk.MaintenanceDrainAPIKey, sdk.MaintenanceDrain{Since: now, Deadline: now.Add(maxWait)}, 0); err != nil {
+				return err
+			}
+		} else if err := api.Cache.Delete(sdk.MaintenanceDrainAPIKey); err != nil {
+			return err
+		}
+
 		if err := api.Cache.SetWithTTL(sdk.MaintenanceAPIKey, enable, 0); err != nil {
 			return err
 		}
@@ -41,6 +68,43 @@ func (api *API) postMaintenanceHandler() service.Handler {
 	}
 }
 
+func (api *API) getMaintenanceHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		status := sdk.MaintenanceStatus{
+			Enable: api.Maintenance,
+			Drain:  api.MaintenanceDrain,
+		}
+
+		counts, err := workflow.CountNodeJobRunsByStatus(api.mustDB())
+		if err != nil {
+			return err
+		}
+		status.BuildingJobs = counts[sdk.StatusBuilding]
+		status.WaitingJobs = counts[sdk.StatusWaiting]
+
+		// Booked jobs will be taken by the workers that are being spawned
+		status.BookedJobs, err = workflow.CountBookedNodeJobRuns(api.mustDB(), api.Cache)
+		if err != nil {
+			return err
+		}
+
+		// All hooks services share the same queue, asking one of them is enough
+		srvs, err := services.LoadAllByType(ctx, api.mustDB(), sdk.TypeHooks)
+		if err != nil {
+			return err
+		}
+		if len(srvs) > 0 {
+			var hooksStatus sdk.MaintenanceStatus
+			if _, _, err := services.NewClient(api.mustDB(), srvs).DoJSONRequest(ctx, http.MethodGet, "/admin/maintenance", nil, &hooksStatus); err != nil {
+				log.Warning(ctx, "unable to get hooks maintenance status: %v", err)
+			}
+			status.BufferedHookEvents = hooksStatus.BufferedHookEvents
+		}
+
+		return service.WriteJSON(w, status, http.StatusOK)
+	}
+}
+
 func (api *API) getAdminServicesHandler() service.Handler {
 	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
 		srvs := []sdk.Service{}
@@ -267,6 +331,38 @@ func (api *API) postAdminDatabaseRollEncryptedEntityByPrimaryKey() service.Handl
 	}
 }
 
+func (api *API) getAdminDatabaseKeyRotationHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		rotation, err := gorpmapping.Mapper.LoadLastKeyRotation(api.mustDB())
+		if err != nil {
+			return err
+		}
+		if rotation == nil {
+			return sdk.WithStack(sdk.ErrNotFound)
+		}
+		rotation.Database = sdk.TypeAPI
+		return service.WriteJSON(w, rotation, http.StatusOK)
+	}
+}
+
+func (api *API) postAdminDatabaseKeyRotationHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		rotation, err := gorpmapping.Mapper.StartKeyRotation(api.mustDB())
+		if err != nil {
+			return err
+		}
+
+		sdk.GoRoutine(api.Router.Background, "gorpmapping.RunKeyRotation", func(ctx context.Context) {
+			if err := gorpmapping.Mapper.RunKeyRotation(ctx, api.mustDB(), gorpmapper.DefaultKeyRotationOptions); err != nil {
+				log.Error(ctx, "database key rotation failed: %v", err)
+			}
+		}, api.PanicDump())
+
+		rotation.Database = sdk.TypeAPI
+		return service.WriteJSON(w, rotation, http.StatusAccepted)
+	}
+}
+
 func (api *API) getAdminFeatureFlipping() service.Handler {
 	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
 		all, err := featureflipping.LoadAll(ctx, gorpmapping.Mapper, api.mustDB())
@@ -34,6 +34,7 @@ import (
 	"github.com/ovh/cds/engine/api/database/gorpmapping"
 	"github.com/ovh/cds/engine/api/event"
 	"github.com/ovh/cds/engine/api/integration"
+	"github.com/ovh/cds/engine/api/leader"
 	"github.com/ovh/cds/engine/api/mail"
 	"github.com/ovh/cds/engine/api/metrics"
 	"github.com/ovh/cds/engine/api/migrate"
@@ -87,20 +88,23 @@ type Configuration struct {
 		InsecureSkipVerifyTLS bool `toml:"insecureSkipVerifyTLS" json:"insecureSkipVerifyTLS" default:"false"`
 	} `toml:"internalServiceMesh" json:"internalServiceMesh"`
 	Auth struct {
-		DefaultGroup  string `toml:"defaultGroup" default:"" comment:"The default group is the group in which every new user will be granted at signup" json:"defaultGroup"`
-		RSAPrivateKey string `toml:"rsaPrivateKey" default:"" comment:"The RSA Private Key used to sign and verify the JWT Tokens issued by the API \nThis is mandatory." json:"-"`
-		LDAP          struct {
-			Enabled         bool   `toml:"enabled" default:"false" json:"enabled"`
-			SignupDisabled  bool   `toml:"signupDisabled" default:"false" json:"signupDisabled"`
-			Host            string `toml:"host" json:"host"`
-			Port            int    `toml:"port" default:"636" json:"port"`
-			SSL             bool   `toml:"ssl" default:"true" json:"ssl"`
-			RootDN          string `toml:"rootDN" default:"dc=myorganization,dc=com" json:"rootDN"`
-			UserSearchBase  string `toml:"userSearchBase" default:"ou=people" json:"userSearchBase"`
-			UserSearch      string `toml:"userSearch" default:"uid={0}" json:"userSearch"`
-			UserFullname    string `toml:"userFullname" default:"{{.givenName}} {{.sn}}" json:"userFullname"`
-			ManagerDN       string `toml:"managerDN" default:"cn=admin,dc=myorganization,dc=com" comment:"Define it if ldapsearch need to be authenticated" json:"managerDN"`
-			ManagerPassword string `toml:"managerPassword" default:"SECRET_PASSWORD_MANAGER" comment:"Define it if ldapsearch need to be authenticated" json:"-"`
+		DefaultGroup   string `toml:"defaultGroup" default:"" comment:"The default group is the group in which every new user will be granted at signup" json:"defaultGroup"`
+		RSAPrivateKey  string `toml:"rsaPrivateKey" default:"" comment:"The RSA Private Key used to sign and verify the JWT Tokens issued by the API \nThis is mandatory." json:"-"`
+		ClientIPHeader string `toml:"clientIPHeader" default:"" comment:"The HTTP header used to get the client IP when the API is behind a reverse proxy (ex: X-Forwarded-For)\nIf empty, the remote address of the connection is used to check consumers allowed CIDRs." json:"clientIPHeader"`
+		ClientIPHops   int    `toml:"clientIPHops" default:"1" comment:"The number of trusted reverse proxies that append an ip to the client IP header\nThe client IP is the one added by the farthest trusted proxy, counting from the right of the header." json:"clientIPHops"`
+		LDAP           struct {
+			Enabled         bool                       `toml:"enabled" default:"false" json:"enabled"`
+			SignupDisabled  bool                       `toml:"signupDisabled" default:"false" json:"signupDisabled"`
+			Host            string                     `toml:"host" json:"host"`
+			Port            int                        `toml:"port" default:"636" json:"port"`
+			SSL             bool                       `toml:"ssl" default:"true" json:"ssl"`
+			RootDN          string                     `toml:"rootDN" default:"dc=myorganization,dc=com" json:"rootDN"`
+			UserSearchBase  string                     `toml:"userSearchBase" default:"ou=people" json:"userSearchBase"`
+			UserSearch      string                     `toml:"userSearch" default:"uid={0}" json:"userSearch"`
+			UserFullname    string                     `toml:"userFullname" default:"{{.givenName}} {{.sn}}" json:"userFullname"`
+			ManagerDN       string                     `toml:"managerDN" default:"cn=admin,dc=myorganization,dc=com" comment:"Define it if ldapsearch need to be authenticated" json:"managerDN"`
+			ManagerPassword string                     `toml:"managerPassword" default:"SECRET_PASSWORD_MANAGER" comment:"Define it if ldapsearch need to be authenticated" json:"-"`
+			GroupSync       sdk.GroupSyncConfiguration `toml:"groupSync" comment:"Synchronize LDAP groups (memberOf common names) with CDS groups" json:"groupSync"`
 		} `toml:"ldap" json:"ldap"`
 		Local struct {
 			Enabled              bool   `toml:"enabled" default:"true" json:"enabled"`
@@ -138,11 +142,13 @@ type Configuration struct {
 			Secret         string `toml:"secret" json:"-" comment:"GitLab OAuth Application Secret"`
 		} `toml:"gitlab" json:"gitlab" comment:"#######\n CDS <-> GitLab Auth. Documentation on https://ovh.github.io/cds/docs/integrations/gitlab/gitlab_authentication/ \n######"`
 		OIDC struct {
-			Enabled        bool   `toml:"enabled" default:"false" json:"enabled"`
-			SignupDisabled bool   `toml:"signupDisabled" default:"false" json:"signupDisabled"`
-			URL            string `toml:"url" json:"url" default:"" comment:"Open ID connect config URL"`
-			ClientID       string `toml:"clientId" json:"-" comment:"OIDC Client ID"`
-			ClientSecret   string `toml:"clientSecret" json:"-" comment:"OIDC Client Secret"`
+			Enabled        bool                       `toml:"enabled" default:"false" json:"enabled"`
+			SignupDisabled bool                       `toml:"signupDisabled" default:"false" json:"signupDisabled"`
+			URL            string                     `toml:"url" json:"url" default:"" comment:"Open ID connect config URL"`
+			ClientID       string                     `toml:"clientId" json:"-" comment:"OIDC Client ID"`
+			ClientSecret   string                     `toml:"clientSecret" json:"-" comment:"OIDC Client Secret"`
+			GroupsClaim    string                     `toml:"groupsClaim" json:"groupsClaim" default:"groups" comment:"Name of the token claim that contains user's groups"`
+			GroupSync      sdk.GroupSyncConfiguration `toml:"groupSync" comment:"Synchronize OIDC groups claim with CDS groups at signin" json:"groupSync"`
 		} `toml:"oidc" json:"oidc" comment:"#######\n CDS <-> Open ID Connect Auth. Documentation on https://ovh.github.io/cds/docs/integrations/openid-connect/ \n######"`
 	} `toml:"auth" comment:"##############################\n CDS Authentication Settings# \n#############################" json:"auth"`
 	SMTP struct {
@@ -196,6 +202,25 @@ type Configuration struct {
 		StepMaxSize    int64 `toml:"stepMaxSize" default:"15728640" comment:"Max step logs size in bytes (default: 15MB)" json:"stepMaxSize"`
 		ServiceMaxSize int64 `toml:"serviceMaxSize" default:"15728640" comment:"Max service logs size in bytes (default: 15MB)" json:"serviceMaxSize"`
 	} `toml:"log" json:"log" comment:"###########################\n Log settings.\n##########################"`
+	WorkerCache struct {
+		MaxSize        int64 `toml:"maxSize" default:"524288000" comment:"Max size of a worker cache in bytes (default: 500MB)" json:"maxSize"`
+		ProjectMaxSize int64 `toml:"projectMaxSize" default:"5368709120" comment:"Max size of all worker caches of a project in bytes, least recently used caches are removed when exceeded (default: 5GB)" json:"projectMaxSize"`
+	} `toml:"workerCache" json:"workerCache" comment:"###########################\n Worker cache settings.\n##########################"`
+	ChildPipelines struct {
+		MaxDepth     int64 `toml:"maxDepth" default:"3" comment:"Max depth of child pipelines added at run time, a child pipeline can add child pipelines" json:"maxDepth"`
+		MaxPipelines int64 `toml:"maxPipelines" default:"20" comment:"Max number of child pipelines added to a workflow run" json:"maxPipelines"`
+		MaxJobs      int64 `toml:"maxJobs" default:"50" comment:"Max number of jobs of all child pipelines added to a workflow run" json:"maxJobs"`
+	} `toml:"childPipelines" json:"childPipelines" comment:"###########################\n Child pipelines generated at run time settings.\n##########################"`
+	Purge struct {
+		ArchiveRuns bool `toml:"archiveRuns" default:"false" comment:"Archive workflow runs in the artifact storage before deleting them from the database, archived runs can be loaded read-only but their artifacts are deleted" json:"archiveRuns"`
+	} `toml:"purge" comment:"###########################\n Workflow runs purge settings.\n##########################" json:"purge"`
+	Audit struct {
+		Enabled       bool  `toml:"enabled" default:"true" comment:"Record all POST, PUT and DELETE API calls in database and send them to the event integrations" json:"enabled"`
+		RetentionDays int64 `toml:"retentionDays" default:"90" comment:"Number of days API call audits are kept in database, 0 means no purge" json:"retentionDays"`
+	} `toml:"audit" comment:"###########################\n API calls audit settings.\n##########################" json:"audit"`
+	BackgroundRoutines struct {
+		WorkflowRunCraftShards int `toml:"workflowRunCraftShards" default:"8" comment:"Number of shards of the workflow run craft routine spread across the API instances" json:"workflowRunCraftShards"`
+	} `toml:"backgroundRoutines" comment:"###########################\n Background routines assignment settings. Singleton routines run on only one API instance,\n sharded routines are spread across all the API instances.\n##########################" json:"backgroundRoutines"`
 	Help struct {
 		Content string `toml:"content" comment:"Help Content. Warning: this message could be view by anonymous user. Markdown accepted." json:"content" default:""`
 		Error   string `toml:"error" comment:"Help displayed to user on each error. Warning: this message could be view by anonymous user. Markdown accepted." json:"error" default:""`
@@ -244,6 +269,7 @@ type API struct {
 	SharedStorage       objectstore.Driver
 	StartupTime         time.Time
 	Maintenance         bool
+	MaintenanceDrain    *sdk.MaintenanceDrain
 	websocketBroker     *websocketBroker
 	Cache               cache.Store
 	Metrics             struct {
@@ -265,8 +291,16 @@ type API struct {
 		WorkflowRunsMarkToDelete *stats.Int64Measure
 		WorkflowRunsDeleted      *stats.Int64Measure
 		DatabaseConns            *stats.Int64Measure
+		AuditAPICallsDropped     *stats.Int64Measure
+		DORADeploymentFrequency  *stats.Float64Measure
+		DORALeadTimeForChanges   *stats.Float64Measure
+		DORAChangeFailureRate    *stats.Float64Measure
+		DORATimeToRestore        *stats.Float64Measure
 	}
 	AuthenticationDrivers map[sdk.AuthConsumerType]sdk.AuthDriver
+	GroupSyncConfigs      map[sdk.AuthConsumerType]sdk.GroupSyncConfiguration
+	auditAPICalls         chan sdk.AuditAPICall
+	LeaderElector         *leader.Elector
 }
 
 // ApplyConfiguration apply an object of type api.Configuration after checking it
@@ -359,6 +393,13 @@ func (a *API) CheckConfiguration(config interface{}) error {
 		return errors.New("invalid given authentication rsa private key")
 	}
 
+	if err := aConfig.Auth.LDAP.GroupSync.IsValid(); err != nil {
+		return fmt.Errorf("invalid LDAP group sync configuration: %v", err)
+	}
+	if err := aConfig.Auth.OIDC.GroupSync.IsValid(); err != nil {
+		return fmt.Errorf("invalid OIDC group sync configuration: %v", err)
+	}
+
 	return nil
 }
 
@@ -504,6 +545,12 @@ func (a *API) Serve(ctx context.Context) error {
 	if err != nil {
 		return fmt.Errorf("cannot connect to database: %v", err)
 	}
+	if a.Config.Database.Replica.Enabled {
+		log.Info(ctx, "Initializing database replica connection...")
+		if err := a.DBConnectionFactory.InitReplica(ctx, a.Config.Database.Replica); err != nil {
+			return fmt.Errorf("cannot connect to database replica: %v", err)
+		}
+	}
 
 	log.Info(ctx, "Setting up database keys...")
 	encryptionKeyConfig := a.Config.Database.EncryptionKey.GetKeys(gorpmapper.KeyEcnryptionIdentifier)
@@ -531,6 +578,10 @@ func (a *API) Serve(ctx context.Context) error {
 		Background: ctx,
 	}
 	a.InitRouter()
+	if a.Config.Audit.Enabled {
+		a.auditAPICalls = make(chan sdk.AuditAPICall, 1000)
+		a.Router.AuditFunc = a.auditAPICall
+	}
 	if err := InitRouterMetrics(ctx, a); err != nil {
 		log.Error(ctx, "unable to init router metrics: %v", err)
 	}
@@ -602,12 +653,21 @@ func (a *API) Serve(ctx context.Context) error {
 			a.Config.Auth.OIDC.URL,
 			a.Config.Auth.OIDC.ClientID,
 			a.Config.Auth.OIDC.ClientSecret,
+			a.Config.Auth.OIDC.GroupsClaim,
 		)
 		if err != nil {
 			return err
 		}
 	}
 
+	a.GroupSyncConfigs = make(map[sdk.AuthConsumerType]sdk.GroupSyncConfiguration)
+	if a.Config.Auth.LDAP.Enabled && a.Config.Auth.LDAP.GroupSync.Enabled {
+		a.GroupSyncConfigs[sdk.ConsumerLDAP] = a.Config.Auth.LDAP.GroupSync
+	}
+	if a.Config.Auth.OIDC.Enabled && a.Config.Auth.OIDC.GroupSync.Enabled {
+		a.GroupSyncConfigs[sdk.ConsumerOIDC] = a.Config.Auth.OIDC.GroupSync
+	}
+
 	if a.Config.Auth.CorporateSSO.Enabled {
 		driverConfig := corpsso.Config{
 			MailDomain: a.Config.Auth.CorporateSSO.MailDomain,
@@ -657,26 +717,50 @@ func (a *API) Serve(ctx context.Context) error {
 	sdk.GoRoutine(ctx, "audit.ComputeWorkflowAudit", func(ctx context.Context) {
 		audit.ComputeWorkflowAudit(ctx, a.DBConnectionFactory.GetDBMap(gorpmapping.Mapper))
 	}, a.PanicDump())
-	sdk.GoRoutine(ctx, "auditCleanerRoutine(ctx", func(ctx context.Context) {
-		auditCleanerRoutine(ctx, a.DBConnectionFactory.GetDBMap(gorpmapping.Mapper))
-	})
+	if a.Config.Audit.Enabled {
+		sdk.GoRoutine(ctx, "audit.RecordAPICalls", func(ctx context.Context) {
+			audit.RecordAPICalls(ctx, a.DBConnectionFactory.GetDBMap(gorpmapping.Mapper), a.auditAPICalls,
+				time.Duration(a.Config.Audit.RetentionDays)*24*time.Hour)
+		}, a.PanicDump())
+	}
 	sdk.GoRoutine(ctx, "repositoriesmanager.ReceiveEvents", func(ctx context.Context) {
 		repositoriesmanager.ReceiveEvents(ctx, a.DBConnectionFactory.GetDBMap(gorpmapping.Mapper), a.Cache)
 	}, a.PanicDump())
-	sdk.GoRoutine(ctx, "services.KillDeadServices", func(ctx context.Context) {
-		services.KillDeadServices(ctx, a.mustDB)
-	}, a.PanicDump())
 	sdk.GoRoutine(ctx, "broadcast.Initialize", func(ctx context.Context) {
 		broadcast.Initialize(ctx, a.DBConnectionFactory.GetDBMap(gorpmapping.Mapper))
 	}, a.PanicDump())
+	sdk.GoRoutine(ctx, "database.MonitorReplicaLag", func(ctx context.Context) {
+		a.DBConnectionFactory.MonitorReplicaLag(ctx, 5*time.Second)
+	}, a.PanicDump())
 	sdk.GoRoutine(ctx, "api.serviceAPIHeartbeat", func(ctx context.Context) {
 		a.serviceAPIHeartbeat(ctx)
 	}, a.PanicDump())
-	sdk.GoRoutine(ctx, "authentication.SessionCleaner", func(ctx context.Context) {
+
+	// Routines that should not run on every API instance are assigned by the leader elector
+	a.LeaderElector = leader.New(a.Cache, fmt.Sprintf("%s@%s:%d", a.ServiceName, event.GetHostname(), os.Getpid()), a.PanicDump())
+	a.LeaderElector.Register(leader.Routine{Name: "api.auditCleanerRoutine", Mode: leader.ModeSingleton, Func: func(ctx context.Context, _ leader.Shard) {
+		auditCleanerRoutine(ctx, a.DBConnectionFactory.GetDBMap(gorpmapping.Mapper))
+	}})
+	a.LeaderElector.Register(leader.Routine{Name: "services.KillDeadServices", Mode: leader.ModeSingleton, Func: func(ctx context.Context, _ leader.Shard) {
+		services.KillDeadServices(ctx, a.mustDB)
+	}})
+	a.LeaderElector.Register(leader.Routine{Name: "authentication.SessionCleaner", Mode: leader.ModeSingleton, Func: func(ctx context.Context, _ leader.Shard) {
 		authentication.SessionCleaner(ctx, a.mustDB, 10*time.Second)
-	}, a.PanicDump())
+	}})
+	for _, r := range a.groupSyncRoutines(ctx) {
+		a.LeaderElector.Register(r)
+	}
+	// The shards owned by the instance are crafted by a single poller
+	craftShards := new(leader.ShardSet)
+	a.LeaderElector.Register(leader.Routine{Name: "api.WorkflowRunCraft", Mode: leader.ModeSharded, Shards: a.Config.BackgroundRoutines.WorkflowRunCraftShards, Func: craftShards.Run})
 	sdk.GoRoutine(ctx, "api.WorkflowRunCraft", func(ctx context.Context) {
-		a.WorkflowRunCraft(ctx, 100*time.Millisecond)
+		a.WorkflowRunCraft(ctx, 100*time.Millisecond, craftShards) // nolint
+	}, a.PanicDump())
+	sdk.GoRoutine(ctx, "gorpmapping.RunKeyRotation", func(ctx context.Context) {
+		// Resume the database key rotation if it was interrupted by a restart
+		if err := gorpmapping.Mapper.RunKeyRotation(ctx, a.mustDB(), gorpmapper.DefaultKeyRotationOptions); err != nil {
+			log.Error(ctx, "database key rotation failed: %v", err)
+		}
 	}, a.PanicDump())
 
 	migrate.Add(ctx, sdk.Migration{Name: "RunsSecrets", Release: "0.47.0", Blocker: false, Automatic: true, ExecFunc: func(ctx context.Context) error {
@@ -780,15 +864,21 @@ func (a *API) Serve(ctx context.Context) error {
 		func(ctx context.Context) {
 			metrics.Init(ctx, a.DBConnectionFactory.GetDBMap(gorpmapping.Mapper))
 		}, a.PanicDump())
-	sdk.GoRoutine(ctx, "Purge",
-		func(ctx context.Context) {
-			purge.Initialize(ctx, a.Cache, a.DBConnectionFactory.GetDBMap(gorpmapping.Mapper), a.SharedStorage, a.Metrics.WorkflowRunsMarkToDelete, a.Metrics.WorkflowRunsDeleted)
-		}, a.PanicDump())
+	a.LeaderElector.Register(leader.Routine{Name: "api.computeDORAMetrics", Mode: leader.ModeSingleton, Func: func(ctx context.Context, _ leader.Shard) {
+		a.computeDORAMetrics(ctx, 5*time.Minute)
+	}})
+	a.LeaderElector.Register(leader.Routine{Name: "purge.Initialize", Mode: leader.ModeSingleton, Func: func(ctx context.Context, _ leader.Shard) {
+		purge.Initialize(ctx, a.Cache, a.DBConnectionFactory.GetDBMap(gorpmapping.Mapper), a.SharedStorage, a.Config.Purge.ArchiveRuns, a.Metrics.WorkflowRunsMarkToDelete, a.Metrics.WorkflowRunsDeleted)
+	}})
+	sdk.GoRoutine(ctx, "leader.Run", a.LeaderElector.Run, a.PanicDump())
 
 	// Check maintenance on redis
 	if _, err := a.Cache.Get(sdk.MaintenanceAPIKey, &a.Maintenance); err != nil {
 		return err
 	}
+	if err := a.loadMaintenanceDrain(); err != nil {
+		return err
+	}
 
 	s := &http.Server{
 		Addr:           fmt.Sprintf("%s:%d", a.Config.HTTP.Addr, a.Config.HTTP.Port),
@@ -801,6 +891,8 @@ func (a *API) Serve(ctx context.Context) error {
 	go func() {
 		select {
 		case <-ctx.Done():
+			// Give back the background routines to the other API instances before stopping
+			a.LeaderElector.Release()
 			log.Warning(ctx, "Cleanup SQL connections")
 			s.Shutdown(ctx)
 			a.DBConnectionFactory.Close()
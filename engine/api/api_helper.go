@@ -3,6 +3,9 @@ package api
 import (
 	"context"
 	"fmt"
+	"net"
+	"net/http"
+	"strings"
 	"time"
 
 	"github.com/go-gorp/gorp"
@@ -122,6 +125,30 @@ func getAuthSession(c context.Context) *sdk.AuthSession {
 	return u
 }
 
+// clientIP returns the ip of the client that sent the request. If a client ip header is set in
+// configuration, the ip added by the farthest trusted proxy is used else the remote address of the request.
+// Values on the left of this ip are set by the client and can't be trusted.
+func (a *API) clientIP(req *http.Request) net.IP {
+	if a.Config.Auth.ClientIPHeader != "" {
+		if v := req.Header.Get(a.Config.Auth.ClientIPHeader); v != "" {
+			ips := strings.Split(v, ",")
+			hops := a.Config.Auth.ClientIPHops
+			if hops < 1 {
+				hops = 1
+			}
+			if hops > len(ips) {
+				hops = len(ips)
+			}
+			return net.ParseIP(strings.TrimSpace(ips[len(ips)-hops]))
+		}
+	}
+	host, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		return net.ParseIP(req.RemoteAddr)
+	}
+	return net.ParseIP(host)
+}
+
 func (a *API) mustDB() *gorp.DbMap {
 	db := a.DBConnectionFactory.GetDBMap(gorpmapping.Mapper)()
 	if db == nil {
@@ -130,6 +157,16 @@ func (a *API) mustDB() *gorp.DbMap {
 	return db
 }
 
+// mustDBReplica returns a database connection on the read-only replica if its lag is below given staleness, else on
+// the primary. It should only be used for read queries.
+func (a *API) mustDBReplica(maxStaleness time.Duration) *gorp.DbMap {
+	db := a.DBConnectionFactory.GetReadDBMap(gorpmapping.Mapper, maxStaleness)()
+	if db == nil {
+		panic(fmt.Errorf("Database unavailable"))
+	}
+	return db
+}
+
 func (a *API) mustDBWithCtx(ctx context.Context) *gorp.DbMap {
 	db := a.DBConnectionFactory.GetDBMap(gorpmapping.Mapper)()
 	db = db.WithContext(ctx).(*gorp.DbMap)
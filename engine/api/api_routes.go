@@ -73,7 +73,7 @@ func (api *API) InitRouter() {
 	r.Handle("/actionBuiltin/{permActionBuiltinName}/usage", Scope(sdk.AuthConsumerScopeAdmin), r.GET(api.getActionBuiltinUsageHandler))
 
 	// Admin
-	r.Handle("/admin/maintenance", Scope(sdk.AuthConsumerScopeAdmin), r.POST(api.postMaintenanceHandler, NeedAdmin(true)))
+	r.Handle("/admin/maintenance", Scope(sdk.AuthConsumerScopeAdmin), r.GET(api.getMaintenanceHandler, NeedAdmin(true)), r.POST(api.postMaintenanceHandler, NeedAdmin(true)))
 	r.Handle("/admin/cds/migration", Scope(sdk.AuthConsumerScopeAdmin), r.GET(api.getAdminMigrationsHandler, NeedAdmin(true)))
 	r.Handle("/admin/cds/migration/{id}/cancel", Scope(sdk.AuthConsumerScopeAdmin), r.POST(api.postAdminMigrationCancelHandler, NeedAdmin(true)))
 	r.Handle("/admin/cds/migration/{id}/todo", Scope(sdk.AuthConsumerScopeAdmin), r.POST(api.postAdminMigrationTodoHandler, NeedAdmin(true)))
@@ -102,10 +102,20 @@ func (api *API) InitRouter() {
 	r.Handle("/admin/database/signature", Scope(sdk.AuthConsumerScopeAdmin), r.GET(api.getAdminDatabaseSignatureResume, NeedAdmin(true)))
 	r.Handle("/admin/database/signature/{entity}/roll/{pk}", Scope(sdk.AuthConsumerScopeAdmin), r.POST(api.postAdminDatabaseSignatureRollEntityByPrimaryKey, NeedAdmin(true)))
 	r.Handle("/admin/database/signature/{entity}/{signer}", Scope(sdk.AuthConsumerScopeAdmin), r.GET(api.getAdminDatabaseSignatureTuplesBySigner, NeedAdmin(true)))
+	r.Handle("/admin/database/rotation", Scope(sdk.AuthConsumerScopeAdmin), r.GET(api.getAdminDatabaseKeyRotationHandler, NeedAdmin(true)), r.POST(api.postAdminDatabaseKeyRotationHandler, NeedAdmin(true)))
 	r.Handle("/admin/database/encryption", Scope(sdk.AuthConsumerScopeAdmin), r.GET(api.getAdminDatabaseEncryptedEntities, NeedAdmin(true)))
 	r.Handle("/admin/database/encryption/{entity}", Scope(sdk.AuthConsumerScopeAdmin), r.GET(api.getAdminDatabaseEncryptedTuplesByEntity, NeedAdmin(true)))
 	r.Handle("/admin/database/encryption/{entity}/roll/{pk}", Scope(sdk.AuthConsumerScopeAdmin), r.POST(api.postAdminDatabaseRollEncryptedEntityByPrimaryKey, NeedAdmin(true)))
 
+	// Admin audit
+	r.Handle("/admin/audit/api", Scope(sdk.AuthConsumerScopeAdmin), r.GET(api.getAdminAuditAPICallsHandler, NeedAdmin(true)))
+
+	// Admin usage
+	r.Handle("/admin/usage", Scope(sdk.AuthConsumerScopeAdmin), r.GET(api.getAdminJobUsagesHandler, NeedAdmin(true)))
+
+	// Admin auth
+	r.Handle("/admin/auth/{consumerType}/group/sync", Scope(sdk.AuthConsumerScopeAdmin), r.GET(api.getAdminAuthGroupSyncHandler, NeedAdmin(true)), r.POST(api.postAdminAuthGroupSyncHandler, NeedAdmin(true)))
+
 	// Feature flipping
 	r.Handle("/admin/features", Scope(sdk.AuthConsumerScopeAdmin), r.GET(api.getAdminFeatureFlipping, NeedAdmin(true)), r.POST(api.postAdminFeatureFlipping, NeedAdmin(true)))
 	r.Handle("/admin/features/{name}", Scope(sdk.AuthConsumerScopeAdmin), r.GET(api.getAdminFeatureFlippingByName, NeedAdmin(true)), r.PUT(api.putAdminFeatureFlipping, NeedAdmin(true)), r.DELETE(api.deleteAdminFeatureFlipping, NeedAdmin(true)))
@@ -123,11 +133,13 @@ func (api *API) InitRouter() {
 	// Group
 	r.Handle("/group", Scope(sdk.AuthConsumerScopeGroup), r.GET(api.getGroupsHandler), r.POST(api.postGroupHandler))
 	r.Handle("/group/{permGroupName}", Scope(sdk.AuthConsumerScopeGroup), r.GET(api.getGroupHandler), r.PUT(api.putGroupHandler), r.DELETE(api.deleteGroupHandler))
+	r.Handle("/group/{permGroupName}/audit", Scope(sdk.AuthConsumerScopeGroup), r.GET(api.getGroupAuditsHandler))
 	r.Handle("/group/{permGroupName}/user", Scope(sdk.AuthConsumerScopeGroup), r.POST(api.postGroupUserHandler))
 	r.Handle("/group/{permGroupName}/user/{username}", Scope(sdk.AuthConsumerScopeGroup), r.PUT(api.putGroupUserHandler), r.DELETE(api.deleteGroupUserHandler))
 
 	// Hooks
 	r.Handle("/hook/{uuid}/workflow/{workflowID}/vcsevent/{vcsServer}", Scope(sdk.AuthConsumerScopeRun), r.GET(api.getHookPollingVCSEvents))
+	r.Handle("/hook/{uuid}/changes", Scope(sdk.AuthConsumerScopeRun), r.GET(api.getHookChangedFilesHandler))
 
 	// Integration
 	r.Handle("/integration/models", ScopeNone(), r.GET(api.getIntegrationModelsHandler), r.POST(api.postIntegrationModelHandler, NeedAdmin(true)))
@@ -163,6 +175,9 @@ func (api *API) InitRouter() {
 	// Project
 	r.Handle("/project", Scope(sdk.AuthConsumerScopeProject), r.GET(api.getProjectsHandler, AllowProvider(true)), r.POST(api.postProjectHandler))
 	r.Handle("/project/{permProjectKey}", Scope(sdk.AuthConsumerScopeProject), r.GET(api.getProjectHandler), r.PUT(api.updateProjectHandler), r.DELETE(api.deleteProjectHandler))
+	r.Handle("/project/{permProjectKey}/dora", Scope(sdk.AuthConsumerScopeProject), r.GET(api.getProjectDORAMetricsHandler))
+	r.Handle("/project/{permProjectKey}/export", Scope(sdk.AuthConsumerScopeProject), r.POST(api.postProjectExportHandler))
+	r.Handle("/project/{key}/import/bundle", Scope(sdk.AuthConsumerScopeProject), r.POST(api.postProjectImportHandler))
 	r.Handle("/project/{permProjectKey}/labels", Scope(sdk.AuthConsumerScopeProject), r.PUT(api.putProjectLabelsHandler))
 	r.Handle("/project/{permProjectKey}/group", Scope(sdk.AuthConsumerScopeProject), r.POST(api.postGroupInProjectHandler))
 	r.Handle("/project/{permProjectKey}/group/import", Scope(sdk.AuthConsumerScopeProject), r.POST(api.postImportGroupsInProjectHandler))
@@ -186,6 +201,7 @@ func (api *API) InitRouter() {
 
 	// Application
 	r.Handle("/project/{permProjectKey}/application/{applicationName}", Scope(sdk.AuthConsumerScopeProject), r.GET(api.getApplicationHandler), r.PUT(api.updateApplicationHandler), r.DELETE(api.deleteApplicationHandler))
+	r.Handle("/project/{permProjectKey}/application/{applicationName}/dora", Scope(sdk.AuthConsumerScopeProject), r.GET(api.getApplicationDORAMetricsHandler))
 	r.Handle("/project/{permProjectKey}/application/{applicationName}/ascode", Scope(sdk.AuthConsumerScopeProject), r.PUT(api.updateAsCodeApplicationHandler))
 	r.Handle("/project/{permProjectKey}/application/{applicationName}/metrics/{metricName}", Scope(sdk.AuthConsumerScopeProject), r.GET(api.getApplicationMetricHandler))
 	r.Handle("/project/{permProjectKey}/application/{applicationName}/keys", Scope(sdk.AuthConsumerScopeProject), r.GET(api.getKeysInApplicationHandler), r.POST(api.addKeyInApplicationHandler))
@@ -209,6 +225,7 @@ func (api *API) InitRouter() {
 	r.Handle("/project/{permProjectKey}/pipeline/{pipelineKey}", Scope(sdk.AuthConsumerScopeProject), r.GET(api.getPipelineHandler), r.PUT(api.updatePipelineHandler), r.DELETE(api.deletePipelineHandler))
 	r.Handle("/project/{permProjectKey}/pipeline/{pipelineKey}/ascode", Scope(sdk.AuthConsumerScopeProject), r.PUT(api.updateAsCodePipelineHandler))
 	r.Handle("/project/{permProjectKey}/pipeline/{pipelineKey}/rollback/{auditID}", Scope(sdk.AuthConsumerScopeProject), r.POST(api.postPipelineRollbackHandler))
+	r.Handle("/project/{permProjectKey}/pipeline/{pipelineKey}/publish", Scope(sdk.AuthConsumerScopeProject), r.POST(api.postPipelinePublishHandler))
 	r.Handle("/project/{permProjectKey}/pipeline/{pipelineKey}/audits", Scope(sdk.AuthConsumerScopeProject), r.GET(api.getPipelineAuditHandler))
 	r.Handle("/project/{permProjectKey}/pipeline/{pipelineKey}/stage", Scope(sdk.AuthConsumerScopeProject), r.POST(api.addStageHandler))
 	r.Handle("/project/{permProjectKey}/pipeline/{pipelineKey}/stage/condition", Scope(sdk.AuthConsumerScopeProject), r.GET(api.getStageConditionsHandler))
@@ -217,6 +234,13 @@ func (api *API) InitRouter() {
 	r.Handle("/project/{permProjectKey}/pipeline/{pipelineKey}/stage/{stageID}/job", Scope(sdk.AuthConsumerScopeProject), r.POST(api.addJobToStageHandler))
 	r.Handle("/project/{permProjectKey}/pipeline/{pipelineKey}/stage/{stageID}/job/{jobID}", Scope(sdk.AuthConsumerScopeProject), r.PUT(api.updateJobHandler), r.DELETE(api.deleteJobHandler))
 
+	r.Handle("/project/{permProjectKey}/sharedPipeline", Scope(sdk.AuthConsumerScopeProject), r.POST(api.postUseSharedPipelineHandler))
+
+	// Shared pipelines
+	r.Handle("/sharedPipeline", Scope(sdk.AuthConsumerScopeProject), r.GET(api.getSharedPipelinesHandler))
+	r.Handle("/sharedPipeline/{groupName}/{name}", Scope(sdk.AuthConsumerScopeProject), r.GET(api.getSharedPipelineHandler))
+	r.Handle("/sharedPipeline/{groupName}/{name}/usage", Scope(sdk.AuthConsumerScopeProject), r.GET(api.getSharedPipelineUsageHandler))
+
 	// Preview pipeline
 	r.Handle("/project/{permProjectKey}/preview/pipeline", Scope(sdk.AuthConsumerScopeProject), r.POST(api.postPipelinePreviewHandler))
 	// Import pipeline
@@ -270,11 +294,17 @@ func (api *API) InitRouter() {
 	r.Handle("/project/{key}/workflows/{permWorkflowName}/runs/latest", Scope(sdk.AuthConsumerScopeRun), r.GET(api.getLatestWorkflowRunHandler))
 	r.Handle("/project/{key}/workflows/{permWorkflowName}/runs/tags", Scope(sdk.AuthConsumerScopeRun), r.GET(api.getWorkflowRunTagsHandler))
 	r.Handle("/project/{key}/workflows/{permWorkflowName}/runs/num", Scope(sdk.AuthConsumerScopeRun), r.GET(api.getWorkflowRunNumHandler), r.POST(api.postWorkflowRunNumHandler))
+	r.Handle("/project/{key}/workflows/{permWorkflowName}/purge/dryrun", Scope(sdk.AuthConsumerScopeProject), r.GET(api.getWorkflowPurgeDryRunHandler), r.POST(api.postWorkflowPurgeDryRunHandler))
+	r.Handle("/project/{key}/workflows/{permWorkflowName}/archives", Scope(sdk.AuthConsumerScopeRun), r.GET(api.getWorkflowRunArchivesHandler))
+	r.Handle("/project/{key}/workflows/{permWorkflowName}/archives/{number}", Scope(sdk.AuthConsumerScopeRun), r.GET(api.getWorkflowRunArchiveHandler))
 	r.Handle("/project/{key}/workflows/{permWorkflowName}/runs/{number}", Scope(sdk.AuthConsumerScopeRun), r.GET(api.getWorkflowRunHandler), r.DELETE(api.deleteWorkflowRunHandler))
 	r.Handle("/project/{key}/workflows/{permWorkflowName}/runs/{number}/stop", Scope(sdk.AuthConsumerScopeRun), r.POSTEXECUTE(api.stopWorkflowRunHandler, MaintenanceAware()))
 	r.Handle("/project/{key}/workflows/{permWorkflowName}/runs/{number}/vcs/resync", Scope(sdk.AuthConsumerScopeRun), r.POSTEXECUTE(api.postResyncVCSWorkflowRunHandler))
 	r.Handle("/project/{key}/workflows/{permWorkflowName}/runs/{number}/artifacts", Scope(sdk.AuthConsumerScopeRun), r.GET(api.getWorkflowRunArtifactsHandler))
+	r.Handle("/project/{key}/workflows/{permWorkflowName}/tests", Scope(sdk.AuthConsumerScopeRun), r.GET(api.getWorkflowTestsHandler))
+	r.Handle("/project/{key}/workflows/{permWorkflowName}/tests/history", Scope(sdk.AuthConsumerScopeRun), r.GET(api.getWorkflowTestCaseHistoryHandler))
 	r.Handle("/project/{key}/workflows/{permWorkflowName}/runs/{number}/nodes/{nodeRunID}", Scope(sdk.AuthConsumerScopeRun), r.GET(api.getWorkflowNodeRunHandler))
+	r.Handle("/project/{key}/workflows/{permWorkflowName}/runs/{number}/nodes/{nodeRunID}/analyses", Scope(sdk.AuthConsumerScopeRun), r.GET(api.getWorkflowNodeRunAnalysesHandler))
 	r.Handle("/project/{key}/workflows/{permWorkflowName}/runs/{number}/nodes/{nodeRunID}/stop", Scope(sdk.AuthConsumerScopeRun), r.POSTEXECUTE(api.stopWorkflowNodeRunHandler, MaintenanceAware()))
 	r.Handle("/project/{key}/workflows/{permWorkflowName}/runs/{number}/nodes/{nodeID}/history", Scope(sdk.AuthConsumerScopeRun), r.GET(api.getWorkflowNodeRunHistoryHandler))
 	r.Handle("/project/{key}/workflows/{permWorkflowName}/runs/{number}/{nodeName}/commits", Scope(sdk.AuthConsumerScopeRun), r.GET(api.getWorkflowCommitsHandler))
@@ -293,6 +323,7 @@ func (api *API) InitRouter() {
 	r.Handle("/project/{permProjectKey}/environment/import", Scope(sdk.AuthConsumerScopeProject), r.POST(api.importNewEnvironmentHandler, DEPRECATED))
 	r.Handle("/project/{permProjectKey}/environment/import/{environmentName}", Scope(sdk.AuthConsumerScopeProject), r.POST(api.importIntoEnvironmentHandler, DEPRECATED))
 	r.Handle("/project/{permProjectKey}/environment/{environmentName}", Scope(sdk.AuthConsumerScopeProject), r.GET(api.getEnvironmentHandler), r.PUT(api.updateEnvironmentHandler), r.DELETE(api.deleteEnvironmentHandler))
+	r.Handle("/project/{permProjectKey}/environment/{environmentName}/dora", Scope(sdk.AuthConsumerScopeProject), r.GET(api.getEnvironmentDORAMetricsHandler))
 	r.Handle("/project/{permProjectKey}/environment/{environmentName}/ascode", Scope(sdk.AuthConsumerScopeProject), r.PUT(api.updateAsCodeEnvironmentHandler))
 	r.Handle("/project/{permProjectKey}/environment/{environmentName}/usage", Scope(sdk.AuthConsumerScopeProject), r.GET(api.getEnvironmentUsageHandler))
 	r.Handle("/project/{permProjectKey}/environment/{environmentName}/keys", Scope(sdk.AuthConsumerScopeProject), r.GET(api.getKeysInEnvironmentHandler), r.POST(api.addKeyInEnvironmentHandler))
@@ -315,8 +346,10 @@ func (api *API) InitRouter() {
 	r.Handle("/project/{permProjectKey}/storage/{integrationName}/staticfiles/{name}", Scope(sdk.AuthConsumerScopeRunExecution), r.POSTEXECUTE(api.postWorkflowJobStaticFilesHandler, MaintenanceAware()))
 
 	// Cache
+	r.Handle("/project/{permProjectKey}/storage/{integrationName}/cache", Scope(sdk.AuthConsumerScopeRunExecution), r.GET(api.getCacheLookupHandler))
 	r.Handle("/project/{permProjectKey}/storage/{integrationName}/cache/{tag}", Scope(sdk.AuthConsumerScopeRunExecution), r.POSTEXECUTE(api.postPushCacheHandler, MaintenanceAware()), r.GET(api.getPullCacheHandler))
 	r.Handle("/project/{permProjectKey}/storage/{integrationName}/cache/{tag}/url", Scope(sdk.AuthConsumerScopeRunExecution), r.POSTEXECUTE(api.postPushCacheWithTempURLHandler, MaintenanceAware()), r.GET(api.getPullCacheWithTempURLHandler))
+	r.Handle("/project/{permProjectKey}/storage/{integrationName}/cache/{tag}/url/callback", Scope(sdk.AuthConsumerScopeRunExecution), r.POSTEXECUTE(api.postPushCacheWithTempURLCallbackHandler, MaintenanceAware()))
 
 	//Workflow queue
 	r.Handle("/queue/workflows", Scope(sdk.AuthConsumerScopeRun, sdk.AuthConsumerScopeRunExecution), r.GET(api.getWorkflowJobQueueHandler, MaintenanceAware()))
@@ -324,6 +357,8 @@ func (api *API) InitRouter() {
 	r.Handle("/queue/workflows/{id}/take", Scope(sdk.AuthConsumerScopeRunExecution), r.POST(api.postTakeWorkflowJobHandler, MaintenanceAware()))
 	r.Handle("/queue/workflows/{permJobID}/book", Scope(sdk.AuthConsumerScopeRunExecution), r.POST(api.postBookWorkflowJobHandler, MaintenanceAware()), r.DELETE(api.deleteBookWorkflowJobHandler, MaintenanceAware()))
 	r.Handle("/queue/workflows/{permJobID}/infos", Scope(sdk.AuthConsumerScopeRunExecution), r.GET(api.getWorkflowJobHandler, MaintenanceAware()))
+	r.Handle("/queue/workflows/{permJobID}/analysis", Scope(sdk.AuthConsumerScopeRunExecution), r.POSTEXECUTE(api.postAnalysisReportHandler, MaintenanceAware()))
+	r.Handle("/queue/workflows/{permJobID}/pipelines", Scope(sdk.AuthConsumerScopeRunExecution), r.POSTEXECUTE(api.postChildPipelinesHandler, MaintenanceAware()))
 	r.Handle("/queue/workflows/{permJobID}/vulnerability", Scope(sdk.AuthConsumerScopeRunExecution), r.POSTEXECUTE(api.postVulnerabilityReportHandler, MaintenanceAware()))
 	r.Handle("/queue/workflows/{permJobID}/spawn/infos", Scope(sdk.AuthConsumerScopeRunExecution), r.POST(api.postSpawnInfosWorkflowJobHandler, MaintenanceAware()))
 	r.Handle("/queue/workflows/{permJobID}/result", Scope(sdk.AuthConsumerScopeRunExecution), r.POSTEXECUTE(api.postWorkflowJobResultHandler, MaintenanceAware()))
@@ -382,6 +417,8 @@ func (api *API) InitRouter() {
 	r.Handle("/user/{permUsername}/auth/consumer", Scope(sdk.AuthConsumerScopeAccessToken), r.GET(api.getConsumersByUserHandler), r.POST(api.postConsumerByUserHandler))
 	r.Handle("/user/{permUsername}/auth/consumer/{permConsumerID}", Scope(sdk.AuthConsumerScopeAccessToken), r.DELETE(api.deleteConsumerByUserHandler))
 	r.Handle("/user/{permUsername}/auth/consumer/{permConsumerID}/regen", Scope(sdk.AuthConsumerScopeAccessToken), r.POST(api.postConsumerRegenByUserHandler))
+	r.Handle("/user/{permUsername}/auth/consumer/{permConsumerID}/cidr", Scope(sdk.AuthConsumerScopeAccessToken), r.PUT(api.putConsumerAllowedCIDRsByUserHandler))
+	r.Handle("/user/{permUsername}/auth/consumer/{permConsumerID}/rejection", Scope(sdk.AuthConsumerScopeAccessToken), r.GET(api.getConsumerIPRejectionsByUserHandler))
 	r.Handle("/user/{permUsername}/auth/session", Scope(sdk.AuthConsumerScopeAccessToken), r.GET(api.getSessionsByUserHandler))
 	r.Handle("/user/{permUsername}/auth/session/{permSessionID}", Scope(sdk.AuthConsumerScopeAccessToken), r.DELETE(api.deleteSessionByUserHandler))
 
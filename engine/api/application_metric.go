@@ -19,12 +19,12 @@ func (api *API) getApplicationMetricHandler() service.Handler {
 		appName := vars["applicationName"]
 		metricName := vars["metricName"]
 
-		app, errA := application.LoadByName(api.mustDB(), key, appName)
+		app, errA := application.LoadByName(api.mustDBReplica(metricsReplicaStaleness), key, appName)
 		if errA != nil {
 			return sdk.WrapError(errA, "getApplicationMetricHandler> unable to load application")
 		}
 
-		result, err := metrics.GetMetrics(ctx, api.mustDB(), key, app.ID, metricName)
+		result, err := metrics.GetMetrics(ctx, api.mustDBReplica(metricsReplicaStaleness), key, app.ID, metricName)
 		if err != nil {
 			return sdk.WrapError(err, "Cannot get metrics")
 
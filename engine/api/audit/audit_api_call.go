@@ -0,0 +1,107 @@
+package audit
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/database/gorpmapping"
+	"github.com/ovh/cds/engine/api/event"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+func init() {
+	gorpmapping.Register(gorpmapping.New(sdk.AuditAPICall{}, "audit_api_call", true, "id"))
+}
+
+// InsertAPICall in database.
+func InsertAPICall(db gorp.SqlExecutor, a *sdk.AuditAPICall) error {
+	if a.Created.IsZero() {
+		a.Created = time.Now()
+	}
+	return sdk.WrapError(gorpmapping.Insert(db, a), "unable to insert audit for api call %s %s", a.Method, a.Route)
+}
+
+// LoadAPICalls returns API call audits for given filter, most recent first.
+func LoadAPICalls(ctx context.Context, db gorp.SqlExecutor, filter sdk.AuditAPICallFilter) ([]sdk.AuditAPICall, error) {
+	var clauses []string
+	var args []interface{}
+	addClause := func(clause string, arg interface{}) {
+		args = append(args, arg)
+		clauses = append(clauses, fmt.Sprintf(clause, len(args)))
+	}
+	if filter.ProjectKey != "" {
+		addClause("project_key = $%d", filter.ProjectKey)
+	}
+	if filter.Username != "" {
+		addClause("username = $%d", filter.Username)
+	}
+	if !filter.Since.IsZero() {
+		addClause("created >= $%d", filter.Since)
+	}
+	if !filter.Until.IsZero() {
+		addClause("created <= $%d", filter.Until)
+	}
+
+	q := "SELECT * FROM audit_api_call"
+	if len(clauses) > 0 {
+		q += " WHERE " + strings.Join(clauses, " AND ")
+	}
+	q += " ORDER BY created DESC"
+	if filter.Limit > 0 {
+		args = append(args, filter.Limit)
+		q += fmt.Sprintf(" LIMIT $%d", len(args))
+	}
+
+	var as []sdk.AuditAPICall
+	if err := gorpmapping.GetAll(ctx, db, gorpmapping.NewQuery(q).Args(args...), &as); err != nil {
+		return nil, sdk.WrapError(err, "cannot get api call audits")
+	}
+	return as, nil
+}
+
+// DeleteAPICallsOlderThan removes API call audits created before given date.
+func DeleteAPICallsOlderThan(db gorp.SqlExecutor, t time.Time) (int64, error) {
+	res, err := db.Exec("DELETE FROM audit_api_call WHERE created < $1", t)
+	if err != nil {
+		return 0, sdk.WrapError(err, "unable to delete api call audits")
+	}
+	n, _ := res.RowsAffected()
+	return n, nil
+}
+
+// RecordAPICalls stores API call audits received on given channel and publish
+// them as events. If retention is greater than zero, old audits are purged.
+func RecordAPICalls(ctx context.Context, DBFunc func() *gorp.DbMap, audits <-chan sdk.AuditAPICall, retention time.Duration) {
+	purgeTicker := time.NewTicker(time.Hour)
+	defer purgeTicker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			if ctx.Err() != nil {
+				log.Error(ctx, "RecordAPICalls> Exiting: %v", ctx.Err())
+			}
+			return
+		case <-purgeTicker.C:
+			if retention <= 0 {
+				continue
+			}
+			n, err := DeleteAPICallsOlderThan(DBFunc(), time.Now().Add(-retention))
+			if err != nil {
+				log.Error(ctx, "RecordAPICalls> Purge error: %v", err)
+				continue
+			}
+			log.Debug("RecordAPICalls> %d api call audits purged", n)
+		case a := <-audits:
+			if err := InsertAPICall(DBFunc(), &a); err != nil {
+				log.Error(ctx, "RecordAPICalls> %v", err)
+			}
+			event.PublishAuditAPICall(ctx, a)
+		}
+	}
+}
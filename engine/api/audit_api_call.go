@@ -0,0 +1,88 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/ovh/cds/engine/api/audit"
+	"github.com/ovh/cds/engine/service"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+	"github.com/ovh/cds/sdk/telemetry"
+)
+
+// auditAPICall sends an audit for given request to the audit recorder routine. To not slow down the request, the
+// audit is dropped if the recorder buffer is full.
+func (api *API) auditAPICall(ctx context.Context, req *http.Request, rc *service.HandlerConfig, statusCode int) {
+	a := sdk.AuditAPICall{
+		Created:    time.Now(),
+		IP:         api.clientIP(req).String(),
+		Method:     req.Method,
+		Route:      rc.CleanURL,
+		Handler:    rc.Name,
+		RouteVars:  mux.Vars(req),
+		StatusCode: statusCode,
+		Success:    statusCode < http.StatusBadRequest,
+	}
+
+	if requestID, ok := ctx.Value(log.ContextLoggingRequestIDKey).(string); ok {
+		a.RequestID = requestID
+	}
+
+	if consumer := getAPIConsumer(ctx); consumer != nil {
+		a.ConsumerID = consumer.ID
+		a.UserID = consumer.AuthentifiedUserID
+		if consumer.AuthentifiedUser != nil {
+			a.Username = consumer.AuthentifiedUser.Username
+		}
+	}
+
+	a.ProjectKey = a.RouteVars[permProjectKey]
+	if a.ProjectKey == "" {
+		a.ProjectKey = a.RouteVars["key"]
+	}
+	a.WorkflowName = a.RouteVars["permWorkflowName"]
+
+	select {
+	case api.auditAPICalls <- a:
+	default:
+		telemetry.Record(ctx, api.Metrics.AuditAPICallsDropped, 1)
+	}
+}
+
+func (api *API) getAdminAuditAPICallsHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		filter := sdk.AuditAPICallFilter{
+			ProjectKey: QueryString(r, "project"),
+			Username:   QueryString(r, "username"),
+			Limit:      FormInt(r, "limit"),
+		}
+		if filter.Limit <= 0 || filter.Limit > 1000 {
+			filter.Limit = 100
+		}
+
+		var err error
+		if since := QueryString(r, "since"); since != "" {
+			filter.Since, err = time.Parse(time.RFC3339, since)
+			if err != nil {
+				return sdk.NewErrorFrom(sdk.ErrWrongRequest, "invalid given since date %q, RFC3339 format expected", since)
+			}
+		}
+		if until := QueryString(r, "until"); until != "" {
+			filter.Until, err = time.Parse(time.RFC3339, until)
+			if err != nil {
+				return sdk.NewErrorFrom(sdk.ErrWrongRequest, "invalid given until date %q, RFC3339 format expected", until)
+			}
+		}
+
+		as, err := audit.LoadAPICalls(ctx, api.mustDB(), filter)
+		if err != nil {
+			return err
+		}
+
+		return service.WriteJSON(w, as, http.StatusOK)
+	}
+}
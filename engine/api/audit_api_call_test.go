@@ -0,0 +1,51 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ovh/cds/engine/service"
+	"github.com/ovh/cds/sdk"
+)
+
+func Test_auditAPICall(t *testing.T) {
+	api := &API{
+		Router:        &Router{Background: context.Background()},
+		auditAPICalls: make(chan sdk.AuditAPICall, 1),
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/project/MYPROJ/workflows/my-workflow/runs", nil)
+	req.RemoteAddr = "10.0.0.1:1234"
+	req = mux.SetURLVars(req, map[string]string{"key": "MYPROJ", "permWorkflowName": "my-workflow"})
+
+	ctx := context.WithValue(context.Background(), contextAPIConsumer, &sdk.AuthConsumer{
+		ID:                 "consumer-id",
+		AuthentifiedUserID: "user-id",
+		AuthentifiedUser:   &sdk.AuthentifiedUser{Username: "john"},
+	})
+	rc := &service.HandlerConfig{Name: "postWorkflowRunHandler", CleanURL: "/project/{key}/workflows/{permWorkflowName}/runs"}
+
+	api.auditAPICall(ctx, req, rc, http.StatusForbidden)
+
+	a := <-api.auditAPICalls
+	require.Equal(t, "consumer-id", a.ConsumerID)
+	require.Equal(t, "user-id", a.UserID)
+	require.Equal(t, "john", a.Username)
+	require.Equal(t, "10.0.0.1", a.IP)
+	require.Equal(t, http.MethodPost, a.Method)
+	require.Equal(t, rc.CleanURL, a.Route)
+	require.Equal(t, "MYPROJ", a.ProjectKey)
+	require.Equal(t, "my-workflow", a.WorkflowName)
+	require.Equal(t, http.StatusForbidden, a.StatusCode)
+	require.False(t, a.Success)
+
+	// When the buffer is full the audit should be dropped without blocking
+	api.auditAPICall(ctx, req, rc, http.StatusOK)
+	api.auditAPICall(ctx, req, rc, http.StatusOK)
+	require.Len(t, api.auditAPICalls, 1)
+}
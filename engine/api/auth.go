@@ -260,6 +260,17 @@ func (api *API) postAuthSigninHandler() service.Handler {
 			}
 		}
 
+		// Synchronize user's groups from external groups given by the driver
+		if cfg, ok := api.GroupSyncConfigs[consumerType]; ok && cfg.Enabled {
+			u, err := user.LoadByID(ctx, tx, consumer.AuthentifiedUserID)
+			if err != nil {
+				return err
+			}
+			if _, err := api.syncUserGroups(ctx, tx, consumerType, u, userInfo.Groups, false); err != nil {
+				return err
+			}
+		}
+
 		// Generate a new session for consumer
 		session, err := authentication.NewSession(ctx, tx, consumer, driver.GetSessionDuration(), userInfo.MFA)
 		if err != nil {
@@ -52,6 +52,11 @@ func (api *API) postAuthBuiltinSigninHandler() service.Handler {
 			return err
 		}
 
+		// Check that the signin request comes from an allowed network
+		if err := api.checkConsumerAllowedIP(ctx, r, r.URL.Path, consumer); err != nil {
+			return err
+		}
+
 		// Generate a new session for consumer
 		session, err := authentication.NewSession(ctx, tx, consumer, driver.GetSessionDuration(), false)
 		if err != nil {
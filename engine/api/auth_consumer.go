@@ -75,6 +75,9 @@ func (api *API) postConsumerByUserHandler() service.Handler {
 		if err := reqData.IsValid(api.Router.scopeDetails); err != nil {
 			return err
 		}
+		if err := consumer.CheckChildAllowedCIDRs(reqData.AllowedCIDRs); err != nil {
+			return err
+		}
 
 		// Create the new built in consumer from request data
 		newConsumer, token, err := builtin.NewConsumer(ctx, tx, reqData.Name, reqData.Description,
@@ -82,6 +85,12 @@ func (api *API) postConsumerByUserHandler() service.Handler {
 		if err != nil {
 			return err
 		}
+		if len(reqData.AllowedCIDRs) > 0 {
+			newConsumer.AllowedCIDRs = reqData.AllowedCIDRs
+			if err := authentication.UpdateConsumer(ctx, tx, newConsumer); err != nil {
+				return err
+			}
+		}
 		if err := authentication.LoadConsumerOptions.Default(ctx, tx, newConsumer); err != nil {
 			return err
 		}
@@ -184,6 +193,68 @@ func (api *API) postConsumerRegenByUserHandler() service.Handler {
 	}
 }
 
+func (api *API) putConsumerAllowedCIDRsByUserHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+
+		consumerID := vars["permConsumerID"]
+
+		var req sdk.AuthConsumerAllowedCIDRsRequest
+		if err := service.UnmarshalBody(r, &req); err != nil {
+			return err
+		}
+		if err := sdk.IsValidCIDRs(req.AllowedCIDRs); err != nil {
+			return err
+		}
+		if err := getAPIConsumer(ctx).CheckChildAllowedCIDRs(req.AllowedCIDRs); err != nil {
+			return err
+		}
+
+		tx, err := api.mustDB().Begin()
+		if err != nil {
+			return sdk.WithStack(err)
+		}
+		defer tx.Rollback() // nolint
+
+		consumer, err := authentication.LoadConsumerByID(ctx, tx, consumerID)
+		if err != nil {
+			return err
+		}
+		if consumer.Type != sdk.ConsumerBuiltin {
+			return sdk.NewErrorFrom(sdk.ErrForbidden, "can't set allowed CIDRs on a no builtin consumer")
+		}
+
+		consumer.AllowedCIDRs = req.AllowedCIDRs
+		if err := authentication.UpdateConsumer(ctx, tx, consumer); err != nil {
+			return err
+		}
+		if err := authentication.LoadConsumerOptions.Default(ctx, tx, consumer); err != nil {
+			return err
+		}
+
+		if err := tx.Commit(); err != nil {
+			return sdk.WithStack(err)
+		}
+
+		return service.WriteJSON(w, consumer, http.StatusOK)
+	}
+}
+
+func (api *API) getConsumerIPRejectionsByUserHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+
+		consumerID := vars["permConsumerID"]
+
+		rs, err := authentication.LoadConsumerIPRejectionsByConsumerID(ctx, api.mustDB(), consumerID, 100)
+		if err != nil {
+			return err
+		}
+
+		return service.WriteJSON(w, rs, http.StatusOK)
+	}
+}
+
 func (api *API) getSessionsByUserHandler() service.Handler {
 	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
 		vars := mux.Vars(r)
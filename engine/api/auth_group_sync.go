@@ -0,0 +1,278 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/ovh/cds/engine/api/authentication"
+	"github.com/ovh/cds/engine/api/group"
+	"github.com/ovh/cds/engine/api/leader"
+	"github.com/ovh/cds/engine/api/user"
+	"github.com/ovh/cds/engine/gorpmapper"
+	"github.com/ovh/cds/engine/service"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// syncUserGroups computes and applies (if not dry run) changes on managed groups for given user
+// from the external groups returned by an auth driver.
+func (api *API) syncUserGroups(ctx context.Context, tx gorpmapper.SqlExecutorWithTx, consumerType sdk.AuthConsumerType,
+	u *sdk.AuthentifiedUser, externalGroups []string, dryRun bool) ([]sdk.GroupSyncChange, error) {
+	cfg, ok := api.GroupSyncConfigs[consumerType]
+	if !ok || !cfg.Enabled {
+		return nil, nil
+	}
+	dryRun = dryRun || cfg.DryRun
+
+	managed := cfg.ManagedGroups()
+	gs, err := group.LoadAllByNames(ctx, tx, managed)
+	if err != nil {
+		return nil, err
+	}
+	groupsByName := make(map[string]sdk.Group, len(gs))
+	groupNamesByID := make(map[int64]string, len(gs))
+	existing := make(map[string]struct{}, len(gs))
+	for i := range gs {
+		groupsByName[gs[i].Name] = gs[i]
+		groupNamesByID[gs[i].ID] = gs[i].Name
+		existing[gs[i].Name] = struct{}{}
+	}
+
+	links, err := group.LoadLinksGroupUserForUserIDs(ctx, tx, []string{u.ID})
+	if err != nil {
+		return nil, err
+	}
+	current := make(map[string]bool)
+	linksByGroupName := make(map[string]group.LinkGroupUser)
+	for i := range links {
+		name, ok := groupNamesByID[links[i].GroupID]
+		if !ok {
+			continue
+		}
+		current[name] = links[i].Admin
+		linksByGroupName[name] = links[i]
+	}
+
+	changes := sdk.ComputeGroupSyncChanges(u.Username, managed, cfg.ComputeGroups(externalGroups), current, existing)
+
+	skippedGroups := make(map[string]string)
+	for i := range changes {
+		c := &changes[i]
+		if reason, ok := skippedGroups[c.GroupName]; ok {
+			c.Skipped = reason
+			continue
+		}
+		if c.Type == sdk.GroupSyncChangeCreateGroup && !cfg.CreateGroups {
+			c.Skipped = "group creation is disabled"
+			skippedGroups[c.GroupName] = fmt.Sprintf("group %s does not exist", c.GroupName)
+			continue
+		}
+		if c.Type == sdk.GroupSyncChangeUnsetAdmin || (c.Type == sdk.GroupSyncChangeRemoveMember && current[c.GroupName]) {
+			lastAdmin, err := isLastGroupAdmin(ctx, tx, groupsByName[c.GroupName].ID, u.ID)
+			if err != nil {
+				return nil, err
+			}
+			if lastAdmin {
+				c.Skipped = "user is the last admin of the group"
+				continue
+			}
+		}
+		if dryRun {
+			continue
+		}
+
+		switch c.Type {
+		case sdk.GroupSyncChangeCreateGroup:
+			g := sdk.Group{Name: c.GroupName}
+			if err := group.Insert(ctx, tx, &g); err != nil {
+				return nil, sdk.WrapError(err, "cannot create group %s", c.GroupName)
+			}
+			groupsByName[g.Name] = g
+		case sdk.GroupSyncChangeAddMember:
+			l := group.LinkGroupUser{
+				GroupID:            groupsByName[c.GroupName].ID,
+				AuthentifiedUserID: u.ID,
+			}
+			if err := group.InsertLinkGroupUser(ctx, tx, &l); err != nil {
+				return nil, sdk.WrapError(err, "cannot add user %s in group %s", u.Username, c.GroupName)
+			}
+			linksByGroupName[c.GroupName] = l
+			if err := authentication.ConsumerRestoreInvalidatedGroupForUser(ctx, tx, l.GroupID, u.ID); err != nil {
+				return nil, err
+			}
+		case sdk.GroupSyncChangeSetAdmin, sdk.GroupSyncChangeUnsetAdmin:
+			l := linksByGroupName[c.GroupName]
+			l.Admin = c.Type == sdk.GroupSyncChangeSetAdmin
+			if err := group.UpdateLinkGroupUser(ctx, tx, &l); err != nil {
+				return nil, err
+			}
+			linksByGroupName[c.GroupName] = l
+		case sdk.GroupSyncChangeRemoveMember:
+			l := linksByGroupName[c.GroupName]
+			if err := group.DeleteLinkGroupUser(tx, &l); err != nil {
+				return nil, err
+			}
+			g := groupsByName[c.GroupName]
+			if err := authentication.ConsumerInvalidateGroupForUser(ctx, tx, &g, u); err != nil {
+				return nil, err
+			}
+		}
+
+		if err := group.InsertAudit(tx, &sdk.AuditGroup{
+			AuditCommon: sdk.AuditCommon{
+				TriggeredBy: "group-sync:" + string(consumerType),
+				EventType:   "GroupSync" + string(c.Type),
+			},
+			GroupID:   groupsByName[c.GroupName].ID,
+			GroupName: c.GroupName,
+			Username:  u.Username,
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, c := range changes {
+		log.Info(ctx, "syncUserGroups> %s (dry run: %t) %s %s for user %s %s", consumerType, dryRun, c.Type, c.GroupName, c.Username, c.Skipped)
+	}
+
+	return changes, nil
+}
+
+func isLastGroupAdmin(ctx context.Context, db gorpmapper.SqlExecutorWithTx, groupID int64, userID string) (bool, error) {
+	links, err := group.LoadLinksGroupUserForGroupIDs(ctx, db, []int64{groupID})
+	if err != nil {
+		return false, err
+	}
+	for i := range links {
+		if links[i].AuthentifiedUserID != userID && links[i].Admin {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// syncGroupsForConsumerType synchronizes groups for all users that have a consumer for given type.
+// Only drivers that can list user's groups without signin are supported.
+func (api *API) syncGroupsForConsumerType(ctx context.Context, consumerType sdk.AuthConsumerType, dryRun bool) (sdk.GroupSyncReport, error) {
+	report := sdk.GroupSyncReport{ConsumerType: consumerType, DryRun: dryRun}
+
+	cfg, ok := api.GroupSyncConfigs[consumerType]
+	if !ok || !cfg.Enabled {
+		return report, sdk.NewErrorFrom(sdk.ErrNotFound, "group sync is not enabled for %s", consumerType)
+	}
+	report.DryRun = dryRun || cfg.DryRun
+
+	driver, ok := api.AuthenticationDrivers[consumerType].(sdk.AuthDriverWithUserGroups)
+	if !ok {
+		return report, sdk.NewErrorFrom(sdk.ErrNotImplemented, "driver %s cannot list user's groups", consumerType)
+	}
+
+	consumers, err := authentication.LoadConsumersByType(ctx, api.mustDB(), consumerType)
+	if err != nil {
+		return report, err
+	}
+
+	for i := range consumers {
+		changes, err := api.syncConsumerGroups(ctx, driver, &consumers[i], dryRun)
+		if err != nil {
+			log.Error(ctx, "syncGroupsForConsumerType> unable to sync groups for consumer %s: %v", consumers[i].ID, err)
+			report.Errors = append(report.Errors, fmt.Sprintf("consumer %s: %v", consumers[i].ID, sdk.Cause(err)))
+			continue
+		}
+		report.Changes = append(report.Changes, changes...)
+	}
+
+	return report, nil
+}
+
+func (api *API) syncConsumerGroups(ctx context.Context, driver sdk.AuthDriverWithUserGroups, consumer *sdk.AuthConsumer, dryRun bool) ([]sdk.GroupSyncChange, error) {
+	externalGroups, err := driver.GetUserGroups(ctx, consumer.Data["username"])
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := api.mustDB().Begin()
+	if err != nil {
+		return nil, sdk.WithStack(err)
+	}
+	defer tx.Rollback() // nolint
+
+	u, err := user.LoadByID(ctx, tx, consumer.AuthentifiedUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	changes, err := api.syncUserGroups(ctx, tx, consumer.Type, u, externalGroups, dryRun)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, sdk.WithStack(err)
+	}
+
+	return changes, nil
+}
+
+// groupSyncRoutines returns a singleton routine that periodically synchronizes groups for each consumer type that have
+// an interval configured.
+func (api *API) groupSyncRoutines(ctx context.Context) []leader.Routine {
+	var routines []leader.Routine
+	for consumerType, cfg := range api.GroupSyncConfigs {
+		if cfg.IntervalMinutes <= 0 {
+			continue
+		}
+		if _, ok := api.AuthenticationDrivers[consumerType].(sdk.AuthDriverWithUserGroups); !ok {
+			log.Warning(ctx, "groupSyncRoutine> periodic group sync is not supported by driver %s", consumerType)
+			continue
+		}
+
+		consumerType := consumerType
+		interval := time.Duration(cfg.IntervalMinutes) * time.Minute
+		routines = append(routines, leader.Routine{
+			Name: "api.groupSyncRoutine-" + string(consumerType),
+			Mode: leader.ModeSingleton,
+			Func: func(ctx context.Context, _ leader.Shard) {
+				ticker := time.NewTicker(interval)
+				defer ticker.Stop()
+				for {
+					select {
+					case <-ctx.Done():
+						return
+					case <-ticker.C:
+						if _, err := api.syncGroupsForConsumerType(ctx, consumerType, false); err != nil {
+							log.Error(ctx, "groupSyncRoutine> unable to sync groups for %s: %v", consumerType, err)
+						}
+					}
+				}
+			},
+		})
+	}
+	return routines
+}
+
+func (api *API) getAdminAuthGroupSyncHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		consumerType := sdk.AuthConsumerType(mux.Vars(r)["consumerType"])
+		report, err := api.syncGroupsForConsumerType(ctx, consumerType, true)
+		if err != nil {
+			return err
+		}
+		return service.WriteJSON(w, report, http.StatusOK)
+	}
+}
+
+func (api *API) postAdminAuthGroupSyncHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		consumerType := sdk.AuthConsumerType(mux.Vars(r)["consumerType"])
+		report, err := api.syncGroupsForConsumerType(ctx, consumerType, false)
+		if err != nil {
+			return err
+		}
+		return service.WriteJSON(w, report, http.StatusOK)
+	}
+}
@@ -131,6 +131,14 @@ func checkNewConsumerScopes(parentScopes, scopes sdk.AuthConsumerScopeDetails) e
 		var validScope bool
 		for j := range parentScopes {
 			if scopes[i].Scope == parentScopes[j].Scope {
+				// if parent has resource filters, child should contains only all or a subset of those filters
+				if !isSubsetOf(scopes[i].ProjectKeys, parentScopes[j].ProjectKeys) {
+					return sdk.NewErrorFrom(sdk.ErrWrongRequest, "invalid given project keys for scope %s when creating built in consumer", scopes[i].Scope)
+				}
+				if !isSubsetOf(scopes[i].WorkflowNames, parentScopes[j].WorkflowNames) {
+					return sdk.NewErrorFrom(sdk.ErrWrongRequest, "invalid given workflow names for scope %s when creating built in consumer", scopes[i].Scope)
+				}
+
 				// if no endpoint restrictions on parent scope is valid
 				if len(parentScopes[j].Endpoints) == 0 {
 					validScope = true
@@ -171,3 +179,19 @@ func checkNewConsumerScopes(parentScopes, scopes sdk.AuthConsumerScopeDetails) e
 
 	return nil
 }
+
+// isSubsetOf returns true if values is a subset of filters, an empty filters list means no restriction.
+func isSubsetOf(values, filters sdk.StringSlice) bool {
+	if len(filters) == 0 {
+		return true
+	}
+	if len(values) == 0 {
+		return false
+	}
+	for _, v := range values {
+		if !filters.Contains(v) {
+			return false
+		}
+	}
+	return true
+}
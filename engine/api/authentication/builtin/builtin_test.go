@@ -135,6 +135,52 @@ func Test_checkNewConsumerScopes(t *testing.T) {
 			},
 			Error: false,
 		},
+		{
+			Name: "Scope project keys are not in parent",
+			ParentScopes: sdk.AuthConsumerScopeDetails{
+				{
+					Scope:       sdk.AuthConsumerScopeRun,
+					ProjectKeys: []string{"PROJ1"},
+				},
+			},
+			Scopes: sdk.AuthConsumerScopeDetails{
+				{
+					Scope:       sdk.AuthConsumerScopeRun,
+					ProjectKeys: []string{"PROJ1", "PROJ2"},
+				},
+			},
+			Error: true,
+		},
+		{
+			Name: "Scope without workflow names when parent has some",
+			ParentScopes: sdk.AuthConsumerScopeDetails{
+				{
+					Scope:         sdk.AuthConsumerScopeRun,
+					WorkflowNames: []string{"my-workflow"},
+				},
+			},
+			Scopes: sdk.AuthConsumerScopeDetails{
+				{Scope: sdk.AuthConsumerScopeRun},
+			},
+			Error: true,
+		},
+		{
+			Name: "Scope resources are in parent",
+			ParentScopes: sdk.AuthConsumerScopeDetails{
+				{
+					Scope:       sdk.AuthConsumerScopeRun,
+					ProjectKeys: []string{"PROJ1", "PROJ2"},
+				},
+			},
+			Scopes: sdk.AuthConsumerScopeDetails{
+				{
+					Scope:         sdk.AuthConsumerScopeRun,
+					ProjectKeys:   []string{"PROJ1"},
+					WorkflowNames: []string{"my-workflow"},
+				},
+			},
+			Error: false,
+		},
 	}
 
 	for _, c := range cases {
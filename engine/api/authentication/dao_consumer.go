@@ -92,6 +92,12 @@ func LoadConsumersByGroupID(ctx context.Context, db gorp.SqlExecutor, groupID in
 	return getConsumers(ctx, db, query, opts...)
 }
 
+// LoadConsumersByType returns all auth consumers from database for given type.
+func LoadConsumersByType(ctx context.Context, db gorp.SqlExecutor, consumerType sdk.AuthConsumerType, opts ...LoadConsumerOptionFunc) (sdk.AuthConsumers, error) {
+	query := gorpmapping.NewQuery("SELECT * FROM auth_consumer WHERE type = $1 ORDER BY created ASC").Args(consumerType)
+	return getConsumers(ctx, db, query, opts...)
+}
+
 // LoadConsumerByID returns an auth consumer from database.
 func LoadConsumerByID(ctx context.Context, db gorp.SqlExecutor, id string, opts ...LoadConsumerOptionFunc) (*sdk.AuthConsumer, error) {
 	query := gorpmapping.NewQuery("SELECT * FROM auth_consumer WHERE id = $1").Args(id)
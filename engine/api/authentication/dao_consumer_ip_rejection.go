@@ -0,0 +1,42 @@
+package authentication
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/database/gorpmapping"
+	"github.com/ovh/cds/sdk"
+)
+
+// ConsumerIPRejectionRetention is the duration after which ip rejections are removed from database.
+const ConsumerIPRejectionRetention = 30 * 24 * time.Hour
+
+// InsertConsumerIPRejection in database.
+func InsertConsumerIPRejection(db gorp.SqlExecutor, r *sdk.AuthConsumerIPRejection) error {
+	r.Created = time.Now()
+	return sdk.WrapError(gorpmapping.Insert(db, r), "unable to insert auth consumer ip rejection")
+}
+
+// LoadConsumerIPRejectionsByConsumerID returns the last ip rejections for given consumer id.
+func LoadConsumerIPRejectionsByConsumerID(ctx context.Context, db gorp.SqlExecutor, consumerID string, limit int) ([]sdk.AuthConsumerIPRejection, error) {
+	query := gorpmapping.NewQuery(`
+		SELECT *
+		FROM auth_consumer_ip_rejection
+		WHERE consumer_id = $1
+		ORDER BY created DESC
+		LIMIT $2
+	`).Args(consumerID, limit)
+	var rs []sdk.AuthConsumerIPRejection
+	if err := gorpmapping.GetAll(ctx, db, query, &rs); err != nil {
+		return nil, sdk.WrapError(err, "cannot get auth consumer ip rejections")
+	}
+	return rs, nil
+}
+
+// DeleteConsumerIPRejectionsOlderThan removes ip rejections created before given date.
+func DeleteConsumerIPRejectionsOlderThan(db gorp.SqlExecutor, t time.Time) error {
+	_, err := db.Exec("DELETE FROM auth_consumer_ip_rejection WHERE created < $1", t)
+	return sdk.WrapError(err, "unable to delete auth consumer ip rejections")
+}
@@ -10,12 +10,42 @@ import (
 
 	"github.com/ovh/cds/engine/api/authentication"
 	"github.com/ovh/cds/engine/api/bootstrap"
+	"github.com/ovh/cds/engine/api/database/gorpmapping"
 	"github.com/ovh/cds/engine/api/test"
 	"github.com/ovh/cds/engine/api/test/assets"
 	"github.com/ovh/cds/engine/api/user"
+	"github.com/ovh/cds/engine/gorpmapper"
 	"github.com/ovh/cds/sdk"
 )
 
+// legacyAuthConsumer signs an auth consumer with the canonical form used before scope details had resource filters.
+type legacyAuthConsumer struct {
+	ID                 string               `db:"id"`
+	AuthentifiedUserID string               `db:"-"`
+	Type               sdk.AuthConsumerType `db:"-"`
+	Data               sdk.AuthConsumerData `db:"-"`
+	Created            time.Time            `db:"-"`
+	GroupIDs           sdk.Int64Slice       `db:"-"`
+	ScopeDetails       []legacyScopeDetail  `db:"-"`
+	Disabled           bool                 `db:"disabled"`
+	gorpmapper.SignedEntity
+}
+
+type legacyScopeDetail struct {
+	Scope     sdk.AuthConsumerScope
+	Endpoints sdk.AuthConsumerScopeEndpoints
+}
+
+func (c legacyAuthConsumer) Canonical() gorpmapper.CanonicalForms {
+	return []gorpmapper.CanonicalForm{
+		"{{.ID}}{{.AuthentifiedUserID}}{{print .Type}}{{print .Data}}{{printDate .Created}}{{print .GroupIDs}}{{print .ScopeDetails}}{{print .Disabled}}",
+	}
+}
+
+func init() {
+	gorpmapping.Register(gorpmapping.New(legacyAuthConsumer{}, "auth_consumer", false, "id"))
+}
+
 func TestLoadConsumer(t *testing.T) {
 	db, _ := test.SetupPG(t, bootstrap.InitiliazeDB)
 
@@ -87,6 +117,46 @@ func TestLoadConsumer(t *testing.T) {
 	require.Len(t, cs, 0)
 }
 
+func TestLoadConsumerSignedWithLegacyForm(t *testing.T) {
+	db, _ := test.SetupPG(t, bootstrap.InitiliazeDB)
+
+	u := sdk.AuthentifiedUser{
+		Username: sdk.RandomString(10),
+	}
+	require.NoError(t, user.Insert(context.TODO(), db, &u))
+
+	c := sdk.AuthConsumer{
+		Name:               sdk.RandomString(10),
+		Type:               sdk.ConsumerBuiltin,
+		ScopeDetails:       sdk.NewAuthConsumerScopeDetails(sdk.AuthConsumerScopeUser, sdk.AuthConsumerScopeProject),
+		GroupIDs:           []int64{5},
+		AuthentifiedUserID: u.ID,
+		IssuedAt:           time.Now(),
+	}
+	require.NoError(t, authentication.InsertConsumer(context.TODO(), db, &c))
+	res, err := authentication.LoadConsumerByID(context.TODO(), db, c.ID)
+	require.NoError(t, err)
+
+	// Sign again the consumer like it was before resource filters were added
+	legacy := legacyAuthConsumer{
+		ID:                 res.ID,
+		AuthentifiedUserID: res.AuthentifiedUserID,
+		Type:               res.Type,
+		Data:               res.Data,
+		Created:            res.Created,
+		GroupIDs:           res.GroupIDs,
+		Disabled:           res.Disabled,
+	}
+	for _, d := range res.ScopeDetails {
+		legacy.ScopeDetails = append(legacy.ScopeDetails, legacyScopeDetail{Scope: d.Scope, Endpoints: d.Endpoints})
+	}
+	require.NoError(t, gorpmapping.UpdateAndSign(context.TODO(), db, &legacy))
+
+	res, err = authentication.LoadConsumerByID(context.TODO(), db, c.ID)
+	require.NoError(t, err)
+	test.Equal(t, c, res)
+}
+
 func TestInsertConsumer(t *testing.T) {
 	db, _ := test.SetupPG(t, bootstrap.InitiliazeDB)
 
@@ -12,8 +12,9 @@ type authConsumer struct {
 }
 
 func (c authConsumer) Canonical() gorpmapper.CanonicalForms {
-	_ = []interface{}{c.ID, c.AuthentifiedUserID, c.Type, c.Data, c.Created, c.GroupIDs, c.ScopeDetails, c.Disabled} // Checks that fields exists at compilation
+	_ = []interface{}{c.ID, c.AuthentifiedUserID, c.Type, c.Data, c.Created, c.GroupIDs, c.ScopeDetails, c.Disabled, c.AllowedCIDRs} // Checks that fields exists at compilation
 	return []gorpmapper.CanonicalForm{
+		"{{.ID}}{{.AuthentifiedUserID}}{{print .Type}}{{print .Data}}{{printDate .Created}}{{print .GroupIDs}}{{print .ScopeDetails}}{{print .Disabled}}{{print .AllowedCIDRs}}",
 		"{{.ID}}{{.AuthentifiedUserID}}{{print .Type}}{{print .Data}}{{printDate .Created}}{{print .GroupIDs}}{{print .ScopeDetails}}{{print .Disabled}}",
 	}
 }
@@ -34,5 +35,6 @@ func init() {
 	gorpmapping.Register(
 		gorpmapping.New(authConsumer{}, "auth_consumer", false, "id"),
 		gorpmapping.New(authSession{}, "auth_session", false, "id"),
+		gorpmapping.New(sdk.AuthConsumerIPRejection{}, "auth_consumer_ip_rejection", true, "id"),
 	)
 }
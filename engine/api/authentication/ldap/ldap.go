@@ -7,6 +7,7 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"sync"
 	"text/template"
 	"time"
 
@@ -16,14 +17,16 @@ import (
 	"github.com/ovh/cds/sdk/log"
 )
 
-var _ sdk.AuthDriver = new(AuthDriver)
+var _ sdk.AuthDriverWithUserGroups = new(AuthDriver)
 
 const errUserNotFound = "ldap::user not found"
 
 type AuthDriver struct {
 	signupDisabled bool
 	conf           Config
-	conn           *ldap.Conn
+	// The connection is shared between requests, the mutex must be held from bind to search
+	mutex sync.Mutex
+	conn  *ldap.Conn
 }
 
 // Config handles all config to connect to the LDAP.
@@ -41,7 +44,7 @@ type Config struct {
 
 // NewDriver returns a new ldap auth driver.
 func NewDriver(ctx context.Context, signupDisabled bool, cfg Config) (sdk.AuthDriver, error) {
-	var d = AuthDriver{
+	var d = &AuthDriver{
 		signupDisabled: signupDisabled,
 		conf:           cfg,
 	}
@@ -53,18 +56,18 @@ func NewDriver(ctx context.Context, signupDisabled bool, cfg Config) (sdk.AuthDr
 	return d, nil
 }
 
-func (d AuthDriver) GetManifest() sdk.AuthDriverManifest {
+func (d *AuthDriver) GetManifest() sdk.AuthDriverManifest {
 	return sdk.AuthDriverManifest{
 		Type:           sdk.ConsumerLDAP,
 		SignupDisabled: d.signupDisabled,
 	}
 }
 
-func (d AuthDriver) GetSessionDuration() time.Duration {
+func (d *AuthDriver) GetSessionDuration() time.Duration {
 	return time.Hour * 24 * 30 // 1 month session
 }
 
-func (d AuthDriver) CheckSigninRequest(req sdk.AuthConsumerSigninRequest) error {
+func (d *AuthDriver) CheckSigninRequest(req sdk.AuthConsumerSigninRequest) error {
 	if bind, ok := req["bind"]; !ok || bind == "" {
 		return sdk.NewErrorFrom(sdk.ErrWrongRequest, "missing or invalid bind term for ldap signin")
 	}
@@ -74,11 +77,14 @@ func (d AuthDriver) CheckSigninRequest(req sdk.AuthConsumerSigninRequest) error
 	return nil
 }
 
-func (d AuthDriver) GetUserInfo(ctx context.Context, req sdk.AuthConsumerSigninRequest) (sdk.AuthDriverUserInfo, error) {
+func (d *AuthDriver) GetUserInfo(ctx context.Context, req sdk.AuthConsumerSigninRequest) (sdk.AuthDriverUserInfo, error) {
 	var userInfo sdk.AuthDriverUserInfo
 	var bind = req["bind"]
 	var password = req["password"]
 
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
 	if err := d.bind(ctx, bind, password); err != nil {
 		return userInfo, sdk.NewError(sdk.ErrUnauthorized, err)
 	}
@@ -113,10 +119,38 @@ func (d AuthDriver) GetUserInfo(ctx context.Context, req sdk.AuthConsumerSigninR
 	userInfo.Email = entry[0].Attributes["mail"]
 	userInfo.ExternalID = entry[0].Attributes["uid"]
 	userInfo.Username = req["bind"]
+	userInfo.Groups = entry[0].Groups
 
 	return userInfo, nil
 }
 
+// GetUserGroups returns the names of the LDAP groups (memberOf) for given user.
+func (d *AuthDriver) GetUserGroups(ctx context.Context, username string) ([]string, error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if d.conf.ManagerDN != "" {
+		if err := d.conn.Bind(d.conf.ManagerDN, d.conf.ManagerPassword); err != nil {
+			if !shoudRetry(ctx, err) {
+				return nil, sdk.WithStack(err)
+			}
+			if err := d.openLDAP(ctx, d.conf); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	entry, err := d.search(ctx, username, "uid", "memberOf")
+	if err != nil {
+		return nil, sdk.WrapError(err, "cannot find ldap user %s", username)
+	}
+	if len(entry) > 1 {
+		return nil, fmt.Errorf("LDAP Search error multiple values")
+	}
+
+	return entry[0].Groups, nil
+}
+
 func (d *AuthDriver) openLDAP(ctx context.Context, conf Config) error {
 	if d.conn != nil {
 		d.conn.Close()
@@ -236,6 +270,11 @@ func (d *AuthDriver) search(ctx context.Context, term string, attributes ...stri
 
 		for _, a := range attributes {
 			entry.Attributes[a] = e.GetAttributeValue(a)
+			if a == "memberOf" {
+				for _, dn := range e.GetAttributeValues(a) {
+					entry.Groups = append(entry.Groups, groupNameFromDN(dn))
+				}
+			}
 		}
 		entries = append(entries, entry)
 	}
@@ -63,3 +63,10 @@ func TestGetUserInfo(t *testing.T) {
 	require.NotEmpty(t, info.Fullname, "Fullname")
 	require.NotEmpty(t, info.ExternalID, "ExternalID")
 }
+
+func TestGroupNameFromDN(t *testing.T) {
+	require.Equal(t, "devs", groupNameFromDN("cn=devs,ou=groups,dc=myorganization,dc=com"))
+	require.Equal(t, "devs", groupNameFromDN("CN=devs, OU=groups,DC=myorganization,DC=com"))
+	require.Equal(t, "ou=groups,dc=com", groupNameFromDN("ou=groups,dc=com"))
+	require.Equal(t, "devs", groupNameFromDN("devs"))
+}
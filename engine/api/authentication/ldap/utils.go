@@ -2,6 +2,7 @@ package ldap
 
 import (
 	"context"
+	"strings"
 
 	"gopkg.in/ldap.v2"
 
@@ -27,4 +28,16 @@ func shoudRetry(ctx context.Context, err error) bool {
 type Entry struct {
 	DN         string
 	Attributes map[string]string
+	Groups     []string
+}
+
+// groupNameFromDN returns the common name from a group DN (ex: cn=devs,ou=groups,dc=org,dc=com gives devs).
+// If the DN does not start with a common name, it is returned as is.
+func groupNameFromDN(dn string) string {
+	first := strings.SplitN(dn, ",", 2)[0]
+	kv := strings.SplitN(first, "=", 2)
+	if len(kv) == 2 && strings.EqualFold(strings.TrimSpace(kv[0]), "cn") {
+		return strings.TrimSpace(kv[1])
+	}
+	return dn
 }
@@ -18,7 +18,7 @@ var _ sdk.AuthDriverWithRedirect = (*authDriver)(nil)
 var _ sdk.AuthDriverWithSigninStateToken = (*authDriver)(nil)
 
 // NewDriver returns a new OIDC auth driver for given config.
-func NewDriver(signupDisabled bool, cdsURL, url, clientID, clientSecret string) (sdk.AuthDriver, error) {
+func NewDriver(signupDisabled bool, cdsURL, url, clientID, clientSecret, groupsClaim string) (sdk.AuthDriver, error) {
 	provider, err := oidc.NewProvider(context.Background(), url)
 	if err != nil {
 		return nil, sdk.WrapError(err, "failed to initialize OIDC driver")
@@ -43,6 +43,7 @@ func NewDriver(signupDisabled bool, cdsURL, url, clientID, clientSecret string)
 		cdsURL:         cdsURL,
 		OAuth2Config:   oauth2Config,
 		Verifier:       verifier,
+		groupsClaim:    groupsClaim,
 	}, nil
 }
 
@@ -51,6 +52,7 @@ type authDriver struct {
 	cdsURL         string
 	OAuth2Config   oauth2.Config
 	Verifier       *oidc.IDTokenVerifier
+	groupsClaim    string
 }
 
 func (d authDriver) GetManifest() sdk.AuthDriverManifest {
@@ -136,5 +138,27 @@ func (d authDriver) GetUserInfo(ctx context.Context, req sdk.AuthConsumerSigninR
 		return info, sdk.WithStack(errors.New("missing user's email in OIDC token claim"))
 	}
 
+	info.Groups = groupsFromClaim(tokenClaim, d.groupsClaim)
+
 	return info, nil
 }
+
+// groupsFromClaim returns the list of group names found in given claim, the claim
+// can be a list of strings or a single string.
+func groupsFromClaim(tokenClaim map[string]interface{}, claim string) []string {
+	if claim == "" {
+		return nil
+	}
+	var groups []string
+	switch v := tokenClaim[claim].(type) {
+	case string:
+		groups = append(groups, v)
+	case []interface{}:
+		for i := range v {
+			if s, ok := v[i].(string); ok {
+				groups = append(groups, s)
+			}
+		}
+	}
+	return groups
+}
@@ -109,6 +109,9 @@ func SessionCleaner(ctx context.Context, dbFunc func() *gorp.DbMap, tickerDurati
 				}
 				log.Debug("SessionCleaner> corrupted session %s deleted", s.ID)
 			}
+			if err := DeleteConsumerIPRejectionsOlderThan(db, time.Now().Add(-ConsumerIPRejectionRetention)); err != nil {
+				log.Error(ctx, "SessionCleaner> unable to delete old consumer ip rejections: %v", err)
+			}
 		}
 	}
 }
@@ -8,10 +8,97 @@ import (
 	"github.com/gorilla/mux"
 
 	"github.com/ovh/cds/engine/api/objectstore"
+	"github.com/ovh/cds/engine/api/project"
+	"github.com/ovh/cds/engine/api/workercache"
 	"github.com/ovh/cds/engine/service"
 	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
 )
 
+// checkCacheSize returns an error if given size exceeds the configured max size for a cache.
+func (api *API) checkCacheSize(size int64) error {
+	if api.Config.WorkerCache.MaxSize > 0 && size > api.Config.WorkerCache.MaxSize {
+		return sdk.NewErrorFrom(sdk.ErrWrongRequest, "cache size %d exceeds the limit of %d bytes", size, api.Config.WorkerCache.MaxSize)
+	}
+	return nil
+}
+
+// registerCache saves a cache entry for a pushed cache then evicts least recently
+// used caches of the project in background if the project size limit is exceeded.
+// Workers that don't send a cache key use the tag as key.
+func (api *API) registerCache(ctx context.Context, projectKey, integrationName, tag, key string, size int64) error {
+	if key == "" {
+		key = tag
+	}
+
+	proj, err := project.Load(ctx, api.mustDB(), projectKey)
+	if err != nil {
+		return err
+	}
+
+	entry := sdk.CacheEntry{
+		ProjectID:       proj.ID,
+		IntegrationName: sdk.DefaultIfEmptyStorage(integrationName),
+		Tag:             tag,
+		Key:             key,
+		Size:            size,
+	}
+	if err := workercache.Upsert(ctx, api.mustDB(), &entry); err != nil {
+		return err
+	}
+
+	if api.Config.WorkerCache.ProjectMaxSize > 0 {
+		sdk.GoRoutine(api.Router.Background, "workercache.Evict", func(ctx context.Context) {
+			if err := workercache.Evict(ctx, api.mustDB(), api.SharedStorage, proj.Key, proj.ID, api.Config.WorkerCache.ProjectMaxSize, entry.ID); err != nil {
+				log.Error(ctx, "registerCache> unable to evict caches for project %s: %v", proj.Key, err)
+			}
+		}, api.PanicDump())
+	}
+
+	return nil
+}
+
+// touchCache updates the last access date of a pulled cache, it is not an error
+// if the cache was pushed before caches were recorded.
+func (api *API) touchCache(ctx context.Context, projectKey, integrationName, tag string) {
+	proj, err := project.Load(ctx, api.mustDB(), projectKey)
+	if err != nil {
+		log.Error(ctx, "touchCache> unable to load project %s: %v", projectKey, err)
+		return
+	}
+	entry, err := workercache.LoadByTag(ctx, api.mustDB(), proj.ID, sdk.DefaultIfEmptyStorage(integrationName), tag)
+	if err != nil {
+		if !sdk.ErrorIs(err, sdk.ErrNotFound) {
+			log.Error(ctx, "touchCache> unable to load cache %s: %v", tag, err)
+		}
+		return
+	}
+	if err := workercache.UpdateLastAccess(api.mustDB(), entry.ID); err != nil {
+		log.Error(ctx, "touchCache> %v", err)
+	}
+}
+
+// sizeLimitedReader counts read bytes and fails when more than max bytes are read.
+type sizeLimitedReader struct {
+	r    io.ReadCloser
+	n    int64
+	max  int64
+	errF func(int64) error
+}
+
+func (l *sizeLimitedReader) Read(p []byte) (int, error) {
+	n, err := l.r.Read(p)
+	l.n += int64(n)
+	if l.max > 0 && l.n > l.max {
+		return n, l.errF(l.n)
+	}
+	return n, err
+}
+
+func (l *sizeLimitedReader) Close() error {
+	return l.r.Close()
+}
+
 func (api *API) postPushCacheHandler() service.Handler {
 	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
 		if isWorker := isWorker(ctx); !isWorker {
@@ -32,6 +119,10 @@ func (api *API) postPushCacheHandler() service.Handler {
 		}
 		defer r.Body.Close()
 
+		if err := api.checkCacheSize(r.ContentLength); err != nil {
+			return err
+		}
+
 		cacheObject := sdk.Cache{
 			Name:    "cache.tar",
 			Project: vars[permProjectKey],
@@ -43,11 +134,16 @@ func (api *API) postPushCacheHandler() service.Handler {
 			return err
 		}
 
-		if _, err := storageDriver.Store(&cacheObject, r.Body); err != nil {
+		body := &sizeLimitedReader{r: r.Body, max: api.Config.WorkerCache.MaxSize, errF: api.checkCacheSize}
+		if _, err := storageDriver.Store(&cacheObject, body); err != nil {
+			if sdk.ErrorIs(err, sdk.ErrWrongRequest) {
+				_ = storageDriver.Delete(ctx, &cacheObject)
+				return err
+			}
 			return sdk.WrapError(err, "cannot store cache")
 		}
 
-		return nil
+		return api.registerCache(ctx, vars[permProjectKey], vars["integrationName"], tag, r.FormValue("key"), body.n)
 	}
 }
 
@@ -77,6 +173,8 @@ func (api *API) getPullCacheHandler() service.Handler {
 			return err
 		}
 
+		api.touchCache(ctx, vars[permProjectKey], vars["integrationName"], tag)
+
 		s, temporaryURLSupported := storageDriver.(objectstore.DriverWithRedirect)
 		if storageDriver.TemporaryURLSupported() && temporaryURLSupported { // with temp URL
 			fURL, _, err := s.FetchURL(&cacheObject)
@@ -131,6 +229,17 @@ func (api *API) postPushCacheWithTempURLHandler() service.Handler {
 			return sdk.WrapError(sdk.ErrNotImplemented, "cast error")
 		}
 
+		// The declared size allows to reject a cache early, the real size is checked by the callback
+		var req sdk.Cache
+		if r.ContentLength > 0 {
+			if err := service.UnmarshalBody(r, &req); err != nil {
+				return err
+			}
+		}
+		if err := api.checkCacheSize(req.Size); err != nil {
+			return err
+		}
+
 		cacheObject := sdk.Cache{
 			Name:    "cache.tar",
 			Project: vars[permProjectKey],
@@ -148,6 +257,54 @@ func (api *API) postPushCacheWithTempURLHandler() service.Handler {
 	}
 }
 
+// postPushCacheWithTempURLCallbackHandler is called by the worker when a cache was uploaded to a temporary url.
+// The size of the stored object is checked before the cache is registered.
+func (api *API) postPushCacheWithTempURLCallbackHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		if isWorker := isWorker(ctx); !isWorker {
+			return sdk.WithStack(sdk.ErrForbidden)
+		}
+
+		vars := mux.Vars(r)
+		tag := vars["tag"]
+
+		// check tag name pattern
+		regexp := sdk.NamePatternRegex
+		if !regexp.MatchString(tag) {
+			return sdk.WithStack(sdk.ErrInvalidName)
+		}
+
+		storageDriver, err := objectstore.GetDriver(ctx, api.mustDB(), api.SharedStorage, vars[permProjectKey], vars["integrationName"])
+		if err != nil {
+			return err
+		}
+
+		store, ok := storageDriver.(objectstore.DriverWithRedirect)
+		if !ok {
+			return sdk.WrapError(sdk.ErrNotImplemented, "cast error")
+		}
+
+		cacheObject := sdk.Cache{
+			Name:    "cache.tar",
+			Project: vars[permProjectKey],
+			Tag:     tag,
+		}
+
+		size, err := store.ObjectSize(&cacheObject)
+		if err != nil {
+			return err
+		}
+		if err := api.checkCacheSize(size); err != nil {
+			if errD := storageDriver.Delete(ctx, &cacheObject); errD != nil {
+				log.Error(ctx, "postPushCacheWithTempURLCallbackHandler> unable to delete cache %s: %v", tag, errD)
+			}
+			return err
+		}
+
+		return api.registerCache(ctx, vars[permProjectKey], vars["integrationName"], tag, r.FormValue("key"), size)
+	}
+}
+
 func (api *API) getPullCacheWithTempURLHandler() service.Handler {
 	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
 		if isWorker := isWorker(ctx); !isWorker {
@@ -183,9 +340,54 @@ func (api *API) getPullCacheWithTempURLHandler() service.Handler {
 		if err != nil {
 			return sdk.WrapError(err, "cannot get tmp URL")
 		}
+		api.touchCache(ctx, vars[permProjectKey], vars["integrationName"], tag)
 		cacheObject.TmpURL = url
 		cacheObject.SecretKey = key
 
 		return service.WriteJSON(w, cacheObject, http.StatusOK)
 	}
 }
+
+// getCacheLookupHandler returns the cache matching the given key, or the most recent cache
+// with a key starting with one of the given restore keys.
+func (api *API) getCacheLookupHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		if isWorker := isWorker(ctx); !isWorker {
+			return sdk.WithStack(sdk.ErrForbidden)
+		}
+
+		vars := mux.Vars(r)
+		integrationName := sdk.DefaultIfEmptyStorage(vars["integrationName"])
+		key := r.FormValue("key")
+		if key == "" {
+			return sdk.NewErrorFrom(sdk.ErrWrongRequest, "missing cache key")
+		}
+
+		proj, err := project.Load(ctx, api.mustDB(), vars[permProjectKey])
+		if err != nil {
+			return err
+		}
+
+		entry, err := workercache.LoadByKey(ctx, api.mustDB(), proj.ID, integrationName, key)
+		if err != nil && !sdk.ErrorIs(err, sdk.ErrNotFound) {
+			return err
+		}
+		if entry == nil {
+			entry, err = workercache.LoadLatestByKeyPrefixes(ctx, api.mustDB(), proj.ID, integrationName, r.Form["restoreKey"])
+			if err != nil {
+				if sdk.ErrorIs(err, sdk.ErrNotFound) {
+					return sdk.NewErrorFrom(sdk.ErrNotFound, "no cache found for key %s", key)
+				}
+				return err
+			}
+		}
+
+		return service.WriteJSON(w, sdk.Cache{
+			Project:         proj.Key,
+			IntegrationName: entry.IntegrationName,
+			Tag:             entry.Tag,
+			Key:             entry.Key,
+			Size:            entry.Size,
+		}, http.StatusOK)
+	}
+}
@@ -0,0 +1,175 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	"go.opencensus.io/stats"
+	"go.opencensus.io/tag"
+
+	"github.com/ovh/cds/engine/api/application"
+	"github.com/ovh/cds/engine/api/dora"
+	"github.com/ovh/cds/engine/api/environment"
+	"github.com/ovh/cds/engine/api/project"
+	"github.com/ovh/cds/engine/service"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+	"github.com/ovh/cds/sdk/telemetry"
+)
+
+// DORA metrics are computed on the last 30 days by default
+const doraDefaultRange = 30 * 24 * time.Hour
+
+// doraFilterFromRequest returns the time range given by the from and to query params.
+func doraFilterFromRequest(r *http.Request) (sdk.ApplicationDeploymentFilter, error) {
+	filter := sdk.ApplicationDeploymentFilter{To: time.Now()}
+
+	var err error
+	if to := QueryString(r, "to"); to != "" {
+		filter.To, err = time.Parse(time.RFC3339, to)
+		if err != nil {
+			return filter, sdk.NewErrorFrom(sdk.ErrWrongRequest, "invalid given to date %q, RFC3339 format expected", to)
+		}
+	}
+	filter.From = filter.To.Add(-doraDefaultRange)
+	if from := QueryString(r, "from"); from != "" {
+		filter.From, err = time.Parse(time.RFC3339, from)
+		if err != nil {
+			return filter, sdk.NewErrorFrom(sdk.ErrWrongRequest, "invalid given from date %q, RFC3339 format expected", from)
+		}
+	}
+	if !filter.From.Before(filter.To) {
+		return filter, sdk.NewErrorFrom(sdk.ErrWrongRequest, "from date should be before to date")
+	}
+	return filter, nil
+}
+
+func (api *API) getProjectDORAMetricsHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		key := vars[permProjectKey]
+
+		filter, err := doraFilterFromRequest(r)
+		if err != nil {
+			return err
+		}
+
+		proj, err := project.Load(ctx, api.mustDB(), key)
+		if err != nil {
+			return err
+		}
+		filter.ProjectID = proj.ID
+
+		m, err := dora.Load(ctx, api.mustDBReplica(metricsReplicaStaleness), filter)
+		if err != nil {
+			return err
+		}
+		return service.WriteJSON(w, m, http.StatusOK)
+	}
+}
+
+func (api *API) getApplicationDORAMetricsHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		key := vars[permProjectKey]
+		appName := vars["applicationName"]
+
+		filter, err := doraFilterFromRequest(r)
+		if err != nil {
+			return err
+		}
+
+		app, err := application.LoadByName(api.mustDB(), key, appName)
+		if err != nil {
+			return err
+		}
+		filter.ProjectID = app.ProjectID
+		filter.ApplicationID = app.ID
+
+		m, err := dora.Load(ctx, api.mustDBReplica(metricsReplicaStaleness), filter)
+		if err != nil {
+			return err
+		}
+		return service.WriteJSON(w, m, http.StatusOK)
+	}
+}
+
+func (api *API) getEnvironmentDORAMetricsHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		key := vars[permProjectKey]
+		envName := vars["environmentName"]
+
+		filter, err := doraFilterFromRequest(r)
+		if err != nil {
+			return err
+		}
+
+		env, err := environment.LoadEnvironmentByName(api.mustDB(), key, envName)
+		if err != nil {
+			return err
+		}
+		filter.ProjectID = env.ProjectID
+		filter.EnvironmentID = env.ID
+
+		m, err := dora.Load(ctx, api.mustDBReplica(metricsReplicaStaleness), filter)
+		if err != nil {
+			return err
+		}
+		return service.WriteJSON(w, m, http.StatusOK)
+	}
+}
+
+// computeDORAMetrics records the DORA metrics of each application and environment on the last 30 days. It runs
+// on the leader only, metrics of targets without deployments in the window anymore are reset.
+func (api *API) computeDORAMetrics(ctx context.Context, tick time.Duration) {
+	tagProjectKey := telemetry.MustNewKey(telemetry.TagProjectKey)
+	tagApplication := telemetry.MustNewKey("application")
+	tagEnvironment := telemetry.MustNewKey("environment")
+
+	record := func(t dora.Target, m sdk.DORAMetrics) {
+		tagCtx, err := tag.New(ctx,
+			tag.Upsert(tagProjectKey, t.ProjectKey),
+			tag.Upsert(tagApplication, t.ApplicationName),
+			tag.Upsert(tagEnvironment, t.EnvironmentName),
+		)
+		if err != nil {
+			log.Error(ctx, "api.computeDORAMetrics> unable to tag context: %v", err)
+			return
+		}
+		stats.Record(tagCtx,
+			api.Metrics.DORADeploymentFrequency.M(m.DeploymentFrequency),
+			api.Metrics.DORALeadTimeForChanges.M(m.LeadTimeForChanges),
+			api.Metrics.DORAChangeFailureRate.M(m.ChangeFailureRate),
+			api.Metrics.DORATimeToRestore.M(m.TimeToRestore),
+		)
+	}
+
+	var previous map[dora.Target]sdk.DORAMetrics
+	ticker := time.NewTicker(tick)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			now := time.Now()
+			metrics, err := dora.LoadMetricsByTarget(ctx, api.mustDBReplica(metricsReplicaStaleness), now.Add(-doraDefaultRange), now)
+			if err != nil {
+				log.Error(ctx, "api.computeDORAMetrics> %v", err)
+				continue
+			}
+			for t, m := range metrics {
+				record(t, m)
+			}
+			for t := range previous {
+				if _, has := metrics[t]; !has {
+					record(t, sdk.DORAMetrics{})
+				}
+			}
+			previous = metrics
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package dora
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/database/gorpmapping"
+	"github.com/ovh/cds/sdk"
+)
+
+type dbApplicationDeployment sdk.ApplicationDeployment
+
+func init() {
+	gorpmapping.Register(gorpmapping.New(dbApplicationDeployment{}, "application_deployment", true, "id"))
+}
+
+// InsertDeployment in database, a previous deployment for the same node run is replaced.
+func InsertDeployment(db gorp.SqlExecutor, d *sdk.ApplicationDeployment) error {
+	if _, err := db.Exec("DELETE FROM application_deployment WHERE workflow_node_run_id = $1", d.WorkflowNodeRunID); err != nil {
+		return sdk.WrapError(err, "unable to delete deployment for node run %d", d.WorkflowNodeRunID)
+	}
+	dbd := dbApplicationDeployment(*d)
+	if err := gorpmapping.Insert(db, &dbd); err != nil {
+		return sdk.WrapError(err, "unable to insert deployment for node run %d", d.WorkflowNodeRunID)
+	}
+	d.ID = dbd.ID
+	return nil
+}
+
+// LoadDeployments returns the deployments done in the time range of given filter, oldest first.
+func LoadDeployments(ctx context.Context, db gorp.SqlExecutor, filter sdk.ApplicationDeploymentFilter) ([]sdk.ApplicationDeployment, error) {
+	var clauses = []string{"application_deployment.done >= $1", "application_deployment.done <= $2"}
+	var args = []interface{}{filter.From, filter.To}
+	addClause := func(clause string, arg interface{}) {
+		args = append(args, arg)
+		clauses = append(clauses, fmt.Sprintf(clause, len(args)))
+	}
+	if filter.ProjectID > 0 {
+		addClause("application_deployment.project_id = $%d", filter.ProjectID)
+	}
+	if filter.ApplicationID > 0 {
+		addClause("application_deployment.application_id = $%d", filter.ApplicationID)
+	}
+	if filter.EnvironmentID > 0 {
+		addClause("application_deployment.environment_id = $%d", filter.EnvironmentID)
+	}
+
+	query := `
+		SELECT application_deployment.id, application_deployment.project_id, application_deployment.application_id,
+			application_deployment.environment_id, application_deployment.workflow_id, application_deployment.workflow_run_id,
+			application_deployment.workflow_node_run_id, application_deployment.num, application_deployment.status,
+			COALESCE(application_deployment.vcs_hash, '') AS vcs_hash, application_deployment.commit_date,
+			application_deployment.start, application_deployment.done,
+			project.projectkey, application.name AS application_name, environment.name AS environment_name
+		FROM application_deployment
+		JOIN project ON project.id = application_deployment.project_id
+		JOIN application ON application.id = application_deployment.application_id
+		JOIN environment ON environment.id = application_deployment.environment_id
+		WHERE ` + strings.Join(clauses, " AND ") + `
+		ORDER BY application_deployment.done ASC, application_deployment.id ASC`
+
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, sdk.WrapError(err, "cannot load deployments")
+	}
+	defer rows.Close() // nolint
+
+	var res []sdk.ApplicationDeployment
+	for rows.Next() {
+		var d sdk.ApplicationDeployment
+		var commitDate *time.Time
+		if err := rows.Scan(&d.ID, &d.ProjectID, &d.ApplicationID, &d.EnvironmentID, &d.WorkflowID, &d.WorkflowRunID,
+			&d.WorkflowNodeRunID, &d.Number, &d.Status, &d.VCSHash, &commitDate, &d.Start, &d.Done,
+			&d.ProjectKey, &d.ApplicationName, &d.EnvironmentName); err != nil {
+			return nil, sdk.WrapError(err, "cannot scan deployment")
+		}
+		d.CommitDate = commitDate
+		res = append(res, d)
+	}
+	return res, sdk.WithStack(rows.Err())
+}
+
+// LoadMetricsByTarget returns the DORA metrics of each application and environment that have deployments in the
+// time range of given filter. Metrics are aggregated in database, deployments are never loaded.
+func LoadMetricsByTarget(ctx context.Context, db gorp.SqlExecutor, from, to time.Time) (map[Target]sdk.DORAMetrics, error) {
+	// restored is the number of successful deployments before each deployment of a target, failures that follow a
+	// successful deployment share its value with the next successful deployment that restores the target.
+	query := `
+		WITH deployments AS (
+			SELECT project_id, application_id, environment_id, status, commit_date, done,
+				COALESCE(SUM(CASE WHEN status = $3 THEN 1 ELSE 0 END) OVER (
+					PARTITION BY application_id, environment_id ORDER BY done, id
+					ROWS BETWEEN UNBOUNDED PRECEDING AND 1 PRECEDING
+				), 0) AS restored
+			FROM application_deployment
+			WHERE done >= $1 AND done <= $2 AND status IN ($3, $4)
+		), restores AS (
+			SELECT deployments.*,
+				MIN(done) FILTER (WHERE status = $4) OVER (PARTITION BY application_id, environment_id, restored) AS failed_since
+			FROM deployments
+		)
+		SELECT project.projectkey, application.name, environment.name,
+			COUNT(*) AS deployments,
+			COUNT(*) FILTER (WHERE restores.status = $4) AS failed_deployments,
+			COUNT(*) FILTER (WHERE restores.status = $3) AS successful_deployments,
+			COALESCE(percentile_cont(0.5) WITHIN GROUP (ORDER BY EXTRACT(EPOCH FROM restores.done - restores.commit_date))
+				FILTER (WHERE restores.status = $3 AND restores.commit_date <= restores.done), 0) AS lead_time,
+			COALESCE(percentile_cont(0.5) WITHIN GROUP (ORDER BY EXTRACT(EPOCH FROM restores.done - restores.failed_since))
+				FILTER (WHERE restores.status = $3 AND restores.failed_since IS NOT NULL), 0) AS time_to_restore
+		FROM restores
+		JOIN project ON project.id = restores.project_id
+		JOIN application ON application.id = restores.application_id
+		JOIN environment ON environment.id = restores.environment_id
+		GROUP BY project.projectkey, application.name, environment.name`
+
+	rows, err := db.Query(query, from, to, sdk.StatusSuccess, sdk.StatusFail)
+	if err != nil {
+		return nil, sdk.WrapError(err, "cannot load deployment metrics")
+	}
+	defer rows.Close() // nolint
+
+	res := make(map[Target]sdk.DORAMetrics)
+	for rows.Next() {
+		var t Target
+		var successes int64
+		m := sdk.DORAMetrics{From: from, To: to}
+		if err := rows.Scan(&t.ProjectKey, &t.ApplicationName, &t.EnvironmentName, &m.Deployments, &m.FailedDeployments,
+			&successes, &m.LeadTimeForChanges, &m.TimeToRestore); err != nil {
+			return nil, sdk.WrapError(err, "cannot scan deployment metrics")
+		}
+		computeRates(&m, successes)
+		res[t] = m
+	}
+	return res, sdk.WithStack(rows.Err())
+}
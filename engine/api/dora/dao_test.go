@@ -0,0 +1,70 @@
+package dora_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ovh/cds/engine/api/application"
+	"github.com/ovh/cds/engine/api/bootstrap"
+	"github.com/ovh/cds/engine/api/dora"
+	"github.com/ovh/cds/engine/api/environment"
+	"github.com/ovh/cds/engine/api/test"
+	"github.com/ovh/cds/engine/api/test/assets"
+	"github.com/ovh/cds/sdk"
+)
+
+func TestLoadMetricsByTarget(t *testing.T) {
+	db, cache := test.SetupPG(t, bootstrap.InitiliazeDB)
+
+	key := sdk.RandomString(10)
+	proj := assets.InsertTestProject(t, db, cache, key, key)
+	app := sdk.Application{Name: sdk.RandomString(10)}
+	require.NoError(t, application.Insert(db, *proj, &app))
+	env := sdk.Environment{ProjectID: proj.ID, ProjectKey: proj.Key, Name: sdk.RandomString(10)}
+	require.NoError(t, environment.InsertEnvironment(db, &env))
+
+	to := time.Now()
+	from := to.Add(-10 * 24 * time.Hour)
+	day := func(d int, h int) time.Time {
+		return from.Add(time.Duration(d)*24*time.Hour + time.Duration(h)*time.Hour)
+	}
+	commit := func(t time.Time) *time.Time { return &t }
+
+	// Success, fail, fail, success (restored in 5h after the first failure), fail, success (restored in 1h)
+	deployments := []sdk.ApplicationDeployment{
+		{Status: sdk.StatusSuccess, Done: day(1, 0), CommitDate: commit(day(0, 22))},
+		{Status: sdk.StatusFail, Done: day(2, 0)},
+		{Status: sdk.StatusFail, Done: day(2, 2)},
+		{Status: sdk.StatusSuccess, Done: day(2, 5), CommitDate: commit(day(2, 1))},
+		{Status: sdk.StatusFail, Done: day(3, 0)},
+		{Status: sdk.StatusSuccess, Done: day(3, 1), CommitDate: commit(day(2, 23))},
+		{Status: sdk.StatusStopped, Done: day(4, 0)},
+	}
+	for i := range deployments {
+		d := &deployments[i]
+		d.ProjectID, d.ApplicationID, d.EnvironmentID = proj.ID, app.ID, env.ID
+		d.WorkflowNodeRunID = time.Now().UnixNano()
+		d.Start = d.Done.Add(-time.Minute)
+		require.NoError(t, dora.InsertDeployment(db, d))
+	}
+
+	metrics, err := dora.LoadMetricsByTarget(context.TODO(), db, from, to)
+	require.NoError(t, err)
+	m, has := metrics[dora.Target{ProjectKey: proj.Key, ApplicationName: app.Name, EnvironmentName: env.Name}]
+	require.True(t, has)
+
+	expected, err := dora.Load(context.TODO(), db, sdk.ApplicationDeploymentFilter{ProjectID: proj.ID, From: from, To: to})
+	require.NoError(t, err)
+	assert.Equal(t, int64(6), m.Deployments)
+	assert.Equal(t, expected.Deployments, m.Deployments)
+	assert.Equal(t, expected.FailedDeployments, m.FailedDeployments)
+	assert.InDelta(t, expected.DeploymentFrequency, m.DeploymentFrequency, 0.001)
+	assert.InDelta(t, expected.ChangeFailureRate, m.ChangeFailureRate, 0.001)
+	assert.InDelta(t, expected.LeadTimeForChanges, m.LeadTimeForChanges, 1)
+	assert.InDelta(t, expected.TimeToRestore, m.TimeToRestore, 1)
+	assert.InDelta(t, 3*3600, m.TimeToRestore, 1)
+}
@@ -0,0 +1,126 @@
+package dora
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// IsDeployment returns true if given node run status ends a deployment. Stopped, skipped and disabled node runs
+// are not deployments.
+func IsDeployment(status string) bool {
+	return status == sdk.StatusSuccess || status == sdk.StatusFail
+}
+
+// NewDeployment returns the deployment done by given node run. The commit date is the date of the oldest commit
+// deployed by the node run, if any.
+func NewDeployment(projectID, workflowID, applicationID, environmentID int64, nodeRun sdk.WorkflowNodeRun) sdk.ApplicationDeployment {
+	d := sdk.ApplicationDeployment{
+		ProjectID:         projectID,
+		ApplicationID:     applicationID,
+		EnvironmentID:     environmentID,
+		WorkflowID:        workflowID,
+		WorkflowRunID:     nodeRun.WorkflowRunID,
+		WorkflowNodeRunID: nodeRun.ID,
+		Number:            nodeRun.Number,
+		Status:            nodeRun.Status,
+		VCSHash:           nodeRun.VCSHash,
+		Start:             nodeRun.Start,
+		Done:              nodeRun.Done,
+	}
+	for _, c := range nodeRun.Commits {
+		if c.Timestamp <= 0 {
+			continue
+		}
+		t := time.Unix(0, c.Timestamp*int64(time.Millisecond))
+		if d.CommitDate == nil || t.Before(*d.CommitDate) {
+			d.CommitDate = &t
+		}
+	}
+	return d
+}
+
+// Compute returns the DORA metrics of given deployments for the time range. Deployments should be sorted oldest
+// first.
+func Compute(deployments []sdk.ApplicationDeployment, from, to time.Time) sdk.DORAMetrics {
+	m := sdk.DORAMetrics{From: from, To: to}
+
+	type target struct{ applicationID, environmentID int64 }
+	var successes int64
+	var leadTimes, restoreTimes []float64
+	var failedSince = make(map[target]time.Time)
+
+	for _, d := range deployments {
+		if d.Done.Before(from) || d.Done.After(to) || !IsDeployment(d.Status) {
+			continue
+		}
+		m.Deployments++
+		t := target{d.ApplicationID, d.EnvironmentID}
+
+		if d.Status == sdk.StatusFail {
+			m.FailedDeployments++
+			// Time to restore starts from the first failure
+			if _, has := failedSince[t]; !has {
+				failedSince[t] = d.Done
+			}
+			continue
+		}
+
+		successes++
+		if d.CommitDate != nil && !d.CommitDate.After(d.Done) {
+			leadTimes = append(leadTimes, d.Done.Sub(*d.CommitDate).Seconds())
+		}
+		if since, has := failedSince[t]; has {
+			restoreTimes = append(restoreTimes, d.Done.Sub(since).Seconds())
+			delete(failedSince, t)
+		}
+	}
+
+	computeRates(&m, successes)
+	m.LeadTimeForChanges = median(leadTimes)
+	m.TimeToRestore = median(restoreTimes)
+
+	return m
+}
+
+// computeRates sets the deployment frequency and the change failure rate from the deployments counts.
+func computeRates(m *sdk.DORAMetrics, successes int64) {
+	if days := m.To.Sub(m.From).Hours() / 24; days > 0 {
+		m.DeploymentFrequency = float64(successes) / days
+	}
+	if m.Deployments > 0 {
+		m.ChangeFailureRate = float64(m.FailedDeployments) / float64(m.Deployments)
+	}
+}
+
+func median(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sort.Float64s(values)
+	n := len(values)
+	if n%2 == 1 {
+		return values[n/2]
+	}
+	return (values[n/2-1] + values[n/2]) / 2
+}
+
+// Target is an application deployed on an environment.
+type Target struct {
+	ProjectKey      string
+	ApplicationName string
+	EnvironmentName string
+}
+
+// Load returns the DORA metrics of the deployments that match given filter.
+func Load(ctx context.Context, db gorp.SqlExecutor, filter sdk.ApplicationDeploymentFilter) (sdk.DORAMetrics, error) {
+	deployments, err := LoadDeployments(ctx, db, filter)
+	if err != nil {
+		return sdk.DORAMetrics{}, err
+	}
+	return Compute(deployments, filter.From, filter.To), nil
+}
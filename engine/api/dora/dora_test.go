@@ -0,0 +1,88 @@
+package dora
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func TestNewDeployment(t *testing.T) {
+	now := time.Now().Truncate(time.Millisecond)
+	nr := sdk.WorkflowNodeRun{
+		ID:            42,
+		WorkflowRunID: 12,
+		Number:        3,
+		Status:        sdk.StatusSuccess,
+		VCSHash:       "abcdef",
+		Start:         now.Add(-time.Minute),
+		Done:          now,
+		Commits: []sdk.VCSCommit{
+			{Hash: "abcdef", Timestamp: now.Add(-time.Hour).UnixNano() / int64(time.Millisecond)},
+			{Hash: "123456", Timestamp: now.Add(-2*time.Hour).UnixNano() / int64(time.Millisecond)},
+			{Hash: "unknown"},
+		},
+	}
+
+	d := NewDeployment(1, 2, 3, 4, nr)
+	assert.Equal(t, int64(1), d.ProjectID)
+	assert.Equal(t, int64(2), d.WorkflowID)
+	assert.Equal(t, int64(3), d.ApplicationID)
+	assert.Equal(t, int64(4), d.EnvironmentID)
+	assert.Equal(t, int64(12), d.WorkflowRunID)
+	assert.Equal(t, int64(42), d.WorkflowNodeRunID)
+	require.NotNil(t, d.CommitDate)
+	assert.True(t, now.Add(-2*time.Hour).Equal(*d.CommitDate))
+
+	nr.Commits = nil
+	d = NewDeployment(1, 2, 3, 4, nr)
+	assert.Nil(t, d.CommitDate)
+}
+
+func TestCompute(t *testing.T) {
+	to := time.Date(2020, 6, 11, 0, 0, 0, 0, time.UTC)
+	from := to.Add(-10 * 24 * time.Hour)
+	day := func(d int, h int) time.Time {
+		return from.Add(time.Duration(d)*24*time.Hour + time.Duration(h)*time.Hour)
+	}
+	commit := func(t time.Time) *time.Time { return &t }
+
+	deployments := []sdk.ApplicationDeployment{
+		// Out of range
+		{ApplicationID: 1, EnvironmentID: 1, Status: sdk.StatusSuccess, Done: from.Add(-time.Hour)},
+		// App 1 on env 1: success, fail, fail, success (restored in 5h after the first failure)
+		{ApplicationID: 1, EnvironmentID: 1, Status: sdk.StatusSuccess, Done: day(1, 0), CommitDate: commit(day(0, 22))},
+		{ApplicationID: 1, EnvironmentID: 1, Status: sdk.StatusFail, Done: day(2, 0)},
+		{ApplicationID: 1, EnvironmentID: 1, Status: sdk.StatusFail, Done: day(2, 2)},
+		{ApplicationID: 1, EnvironmentID: 1, Status: sdk.StatusSuccess, Done: day(2, 5), CommitDate: commit(day(2, 1))},
+		// App 1 on env 2: success, fail (restored in 1h)
+		{ApplicationID: 1, EnvironmentID: 2, Status: sdk.StatusFail, Done: day(3, 0)},
+		{ApplicationID: 1, EnvironmentID: 2, Status: sdk.StatusSuccess, Done: day(3, 1), CommitDate: commit(day(2, 19))},
+		// Ignored status
+		{ApplicationID: 2, EnvironmentID: 1, Status: sdk.StatusStopped, Done: day(4, 0)},
+		// Not restored
+		{ApplicationID: 2, EnvironmentID: 1, Status: sdk.StatusFail, Done: day(5, 0)},
+		// No commit date
+		{ApplicationID: 3, EnvironmentID: 1, Status: sdk.StatusSuccess, Done: day(6, 0)},
+	}
+
+	m := Compute(deployments, from, to)
+	assert.Equal(t, from, m.From)
+	assert.Equal(t, to, m.To)
+	assert.Equal(t, int64(8), m.Deployments)
+	assert.Equal(t, int64(4), m.FailedDeployments)
+	assert.Equal(t, 0.4, m.DeploymentFrequency)
+	assert.Equal(t, 0.5, m.ChangeFailureRate)
+	// Lead times: 2h, 4h, 6h
+	assert.Equal(t, (4 * time.Hour).Seconds(), m.LeadTimeForChanges)
+	// Times to restore: 5h, 1h
+	assert.Equal(t, (3 * time.Hour).Seconds(), m.TimeToRestore)
+
+	m = Compute(nil, from, to)
+	assert.Equal(t, int64(0), m.Deployments)
+	assert.Equal(t, float64(0), m.ChangeFailureRate)
+	assert.Equal(t, float64(0), m.LeadTimeForChanges)
+}
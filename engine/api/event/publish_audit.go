@@ -0,0 +1,27 @@
+package event
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// PublishAuditAPICall publish event for an audited API call
+func PublishAuditAPICall(ctx context.Context, a sdk.AuditAPICall) {
+	payload := sdk.EventAuditAPICall{AuditAPICall: a}
+	bts, _ := json.Marshal(payload)
+	event := sdk.Event{
+		Timestamp:    time.Now(),
+		Hostname:     hostname,
+		CDSName:      cdsname,
+		EventType:    fmt.Sprintf("%T", payload),
+		Payload:      bts,
+		Username:     a.Username,
+		ProjectKey:   a.ProjectKey,
+		WorkflowName: a.WorkflowName,
+	}
+	_ = publishEvent(ctx, event)
+}
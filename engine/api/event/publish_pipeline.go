@@ -157,3 +157,17 @@ func PublishPipelineJobDelete(ctx context.Context, key string, pipName string, s
 	}
 	publishPipelineEvent(ctx, e, key, pipName, u)
 }
+
+// PublishPipelineSharedVersion publishes an event when a new version of the shared pipeline
+// used by a project pipeline is available.
+func PublishPipelineSharedVersion(ctx context.Context, key string, pipName string, sp sdk.SharedPipeline, currentVersion int64, u sdk.Identifiable) {
+	e := sdk.EventPipelineSharedVersion{
+		Name:           sp.Name,
+		Version:        sp.Version,
+		CurrentVersion: currentVersion,
+	}
+	if sp.Group != nil {
+		e.GroupName = sp.Group.Name
+	}
+	publishPipelineEvent(ctx, e, key, pipName, u)
+}
@@ -59,6 +59,25 @@ func (api *API) getGroupHandler() service.Handler {
 	}
 }
 
+func (api *API) getGroupAuditsHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		name := vars["permGroupName"]
+
+		g, err := group.LoadByName(ctx, api.mustDB(), name)
+		if err != nil {
+			return err
+		}
+
+		as, err := group.LoadAuditsByGroupID(ctx, api.mustDB(), g.ID)
+		if err != nil {
+			return err
+		}
+
+		return service.WriteJSON(w, as, http.StatusOK)
+	}
+}
+
 func (api *API) postGroupHandler() service.Handler {
 	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
 		var newGroup sdk.Group
@@ -2,6 +2,7 @@ package group
 
 import (
 	"context"
+	"strings"
 
 	"github.com/go-gorp/gorp"
 
@@ -110,6 +111,17 @@ func LoadAllByUserID(ctx context.Context, db gorp.SqlExecutor, userID string, op
 	return getAll(ctx, db, query, opts...)
 }
 
+// LoadAllByNames returns all groups from database for given names.
+func LoadAllByNames(ctx context.Context, db gorp.SqlExecutor, names []string, opts ...LoadOptionFunc) (sdk.Groups, error) {
+	query := gorpmapping.NewQuery(`
+    SELECT *
+    FROM "group"
+    WHERE name = ANY(string_to_array($1, ','))
+    ORDER BY "group".name
+  `).Args(strings.Join(names, ","))
+	return getAll(ctx, db, query, opts...)
+}
+
 // LoadByName retrieves a group by name from database.
 func LoadByName(ctx context.Context, db gorp.SqlExecutor, name string, opts ...LoadOptionFunc) (*sdk.Group, error) {
 	query := gorpmapping.NewQuery(`
@@ -0,0 +1,34 @@
+package group
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/database/gorpmapping"
+	"github.com/ovh/cds/sdk"
+)
+
+// InsertAudit in database.
+func InsertAudit(db gorp.SqlExecutor, a *sdk.AuditGroup) error {
+	if a.Created.IsZero() {
+		a.Created = time.Now()
+	}
+	return sdk.WrapError(gorpmapping.Insert(db, a), "unable to insert audit for group %s", a.GroupName)
+}
+
+// LoadAuditsByGroupID returns all audits for given group id.
+func LoadAuditsByGroupID(ctx context.Context, db gorp.SqlExecutor, groupID int64) ([]sdk.AuditGroup, error) {
+	query := gorpmapping.NewQuery(`
+		SELECT *
+		FROM group_audit
+		WHERE group_id = $1
+		ORDER BY created DESC
+	`).Args(groupID)
+	var as []sdk.AuditGroup
+	if err := gorpmapping.GetAll(ctx, db, query, &as); err != nil {
+		return nil, sdk.WrapError(err, "cannot get group audits")
+	}
+	return as, nil
+}
@@ -112,3 +112,55 @@ func (api *API) getHookPollingVCSEvents() service.Handler {
 		return service.WriteJSON(w, repoEvents, http.StatusOK)
 	}
 }
+
+// getHookChangedFilesHandler returns the files changed between two refs on the repository of a repository webhook or
+// git poller, it is used by the hooks service to apply path filters when changed files are not given by the event.
+func (api *API) getHookChangedFilesHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		if !isService(ctx) {
+			return sdk.WithStack(sdk.ErrForbidden)
+		}
+
+		uuid := mux.Vars(r)["uuid"]
+		base := QueryString(r, "base")
+		head := QueryString(r, "head")
+		if base == "" || head == "" {
+			return sdk.NewErrorFrom(sdk.ErrWrongRequest, "base and head refs are mandatory")
+		}
+
+		h, err := workflow.LoadHookByUUID(api.mustDB(), uuid)
+		if err != nil {
+			return err
+		}
+		repoFullName := h.Config[sdk.HookConfigRepoFullName].Value
+		if repoFullName == "" {
+			return sdk.NewErrorFrom(sdk.ErrWrongRequest, "hook %s is not linked to a repository", uuid)
+		}
+
+		tx, err := api.mustDB().Begin()
+		if err != nil {
+			return sdk.WithStack(err)
+		}
+		defer tx.Rollback() // nolint
+
+		vcsServer, err := repositoriesmanager.LoadProjectVCSServerLinkByProjectKeyAndVCSServerName(ctx, tx, h.Config[sdk.HookConfigProject].Value, h.Config[sdk.HookConfigVCSServer].Value)
+		if err != nil {
+			return err
+		}
+		client, err := repositoriesmanager.AuthorizedClient(ctx, tx, api.Cache, h.Config[sdk.HookConfigProject].Value, vcsServer)
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Commit(); err != nil {
+			return sdk.WithStack(err)
+		}
+
+		files, err := client.FilesBetweenRefs(ctx, repoFullName, base, head)
+		if err != nil {
+			return err
+		}
+
+		return service.WriteJSON(w, files, http.StatusOK)
+	}
+}
@@ -0,0 +1,50 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/ovh/cds/engine/api/usage"
+	"github.com/ovh/cds/engine/service"
+	"github.com/ovh/cds/sdk"
+)
+
+// Job usages are aggregated on the last 30 days by default
+const jobUsageDefaultRange = 30 * 24 * time.Hour
+
+func (api *API) getAdminJobUsagesHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		filter := sdk.JobUsageFilter{
+			ProjectKey: QueryString(r, "project"),
+			GroupName:  QueryString(r, "group"),
+			ModelName:  QueryString(r, "model"),
+			Until:      time.Now(),
+		}
+
+		var err error
+		if until := QueryString(r, "until"); until != "" {
+			filter.Until, err = time.Parse(time.RFC3339, until)
+			if err != nil {
+				return sdk.NewErrorFrom(sdk.ErrWrongRequest, "invalid given until date %q, RFC3339 format expected", until)
+			}
+		}
+		filter.Since = filter.Until.Add(-jobUsageDefaultRange)
+		if since := QueryString(r, "since"); since != "" {
+			filter.Since, err = time.Parse(time.RFC3339, since)
+			if err != nil {
+				return sdk.NewErrorFrom(sdk.ErrWrongRequest, "invalid given since date %q, RFC3339 format expected", since)
+			}
+		}
+		if !filter.Since.Before(filter.Until) {
+			return sdk.NewErrorFrom(sdk.ErrWrongRequest, "since date should be before until date")
+		}
+
+		ss, err := usage.LoadSummaries(ctx, api.mustDBReplica(metricsReplicaStaleness), filter)
+		if err != nil {
+			return err
+		}
+
+		return service.WriteJSON(w, ss, http.StatusOK)
+	}
+}
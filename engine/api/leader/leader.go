@@ -0,0 +1,387 @@
+package leader
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ovh/cds/engine/cache"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// Mode of a background routine.
+type Mode string
+
+// Available routine modes.
+const (
+	// ModeSingleton routines run on only one API instance at a time.
+	ModeSingleton Mode = "singleton"
+	// ModeSharded routines are split in shards spread across all the API instances.
+	ModeSharded Mode = "sharded"
+)
+
+const (
+	defaultTTL      = 30 * time.Second
+	handoverTimeout = 30 * time.Second
+)
+
+// Store is the subset of cache.Store used by the elector.
+type Store interface {
+	cache.LockStore
+	ScanKeys(pattern string) ([]string, error)
+	Get(key string, value interface{}) (bool, error)
+	SetWithDuration(key string, value interface{}, duration time.Duration) error
+	ExpireIfEqual(key string, value interface{}, expiration time.Duration, otherKeys ...string) (bool, error)
+	Delete(key string) error
+	DeleteIfEqual(key string, value interface{}, otherKeys ...string) (bool, error)
+}
+
+// Shard is the part of the work assigned to a routine. A singleton routine always gets the only shard.
+type Shard struct {
+	Index int
+	Total int
+}
+
+// Owns returns true if the item with given id should be processed by the shard.
+func (s Shard) Owns(id int64) bool {
+	if s.Total <= 1 {
+		return true
+	}
+	return int(id%int64(s.Total)) == s.Index
+}
+
+// ShardSet is the set of the shards of a routine owned by the current instance. Its Run method can be used as the
+// routine Func so that a single poller processes the items of all the shards owned by the instance.
+type ShardSet struct {
+	mutex   sync.Mutex
+	total   int
+	indexes map[int]struct{}
+}
+
+// Run adds the shard to the set until the context is cancelled.
+func (s *ShardSet) Run(ctx context.Context, shard Shard) {
+	s.mutex.Lock()
+	if s.indexes == nil {
+		s.indexes = make(map[int]struct{})
+	}
+	s.total = shard.Total
+	s.indexes[shard.Index] = struct{}{}
+	s.mutex.Unlock()
+
+	<-ctx.Done()
+
+	s.mutex.Lock()
+	delete(s.indexes, shard.Index)
+	s.mutex.Unlock()
+}
+
+// Owned returns the sorted indexes of the owned shards and the total number of shards.
+func (s *ShardSet) Owned() ([]int64, int) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	indexes := make([]int64, 0, len(s.indexes))
+	for i := range s.indexes {
+		indexes = append(indexes, int64(i))
+	}
+	sort.Slice(indexes, func(i, j int) bool { return indexes[i] < indexes[j] })
+	return indexes, s.total
+}
+
+// Func of a background routine, it should run until the given context is cancelled. A routine that returns before
+// is restarted at the next election.
+type Func func(ctx context.Context, shard Shard)
+
+// Routine is a background routine managed by the elector.
+type Routine struct {
+	Name   string
+	Mode   Mode
+	Shards int
+	Func   Func
+}
+
+func (r Routine) total() int {
+	if r.Mode != ModeSharded || r.Shards < 1 {
+		return 1
+	}
+	return r.Shards
+}
+
+type ownedShard struct {
+	routine string
+	shard   Shard
+	cancel  context.CancelFunc
+	done    chan struct{}
+}
+
+// Elector assigns the background routines to the API instances. Each shard of a routine is owned by the instance
+// that holds its lock in the cache, locks are renewed at each election and released on shutdown so that another
+// instance can take over.
+type Elector struct {
+	store      Store
+	instanceID string
+	ttl        time.Duration
+	panicDump  []func(s string) (io.WriteCloser, error)
+	mutex      sync.Mutex
+	routines   []Routine
+	owned      map[string]*ownedShard
+	stopped    bool
+}
+
+// New returns an elector for given API instance.
+func New(store Store, instanceID string, panicDump ...func(s string) (io.WriteCloser, error)) *Elector {
+	return &Elector{
+		store:      store,
+		instanceID: instanceID,
+		ttl:        defaultTTL,
+		panicDump:  panicDump,
+		owned:      make(map[string]*ownedShard),
+	}
+}
+
+// InstanceID returns the identifier of the current API instance.
+func (e *Elector) InstanceID() string {
+	return e.instanceID
+}
+
+// Register adds a routine to the elector, it should be called before Run.
+func (e *Elector) Register(r Routine) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	e.routines = append(e.routines, r)
+}
+
+func lockKey(name string, index int) string {
+	return cache.Key("api:leader:lock", name, strconv.Itoa(index))
+}
+
+func ownerKey(name string, index int) string {
+	return cache.Key("api:leader:owner", name, strconv.Itoa(index))
+}
+
+func instanceKey(instanceID string) string {
+	return cache.Key("api:leader:instance", instanceID)
+}
+
+// Run elects the routines owned by the current instance until the context is cancelled, then releases them.
+func (e *Elector) Run(ctx context.Context) {
+	ticker := time.NewTicker(e.ttl / 3)
+	defer ticker.Stop()
+
+	e.elect(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			e.Release()
+			return
+		case <-ticker.C:
+			e.elect(ctx)
+		}
+	}
+}
+
+func (e *Elector) countInstances() int {
+	keys, err := e.store.ScanKeys(cache.Key("api:leader:instance", "*"))
+	if err != nil || len(keys) == 0 {
+		return 1
+	}
+	return len(keys)
+}
+
+func (e *Elector) elect(ctx context.Context) {
+	// Stopped routines can take up to the handover timeout to return, they are released after the mutex is unlocked
+	var stopped []*ownedShard
+	defer func() {
+		for _, o := range stopped {
+			e.release(ctx, o)
+		}
+	}()
+
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	if e.stopped {
+		return
+	}
+
+	if err := e.store.SetWithDuration(instanceKey(e.instanceID), e.instanceID, e.ttl); err != nil {
+		log.Error(ctx, "leader.elect> unable to register instance %s: %v", e.instanceID, err)
+		return
+	}
+	nbInstances := e.countInstances()
+
+	for _, r := range e.routines {
+		total := r.total()
+		share := (total + nbInstances - 1) / nbInstances
+
+		var owned []int
+		for i := 0; i < total; i++ {
+			o, ok := e.owned[lockKey(r.Name, i)]
+			if !ok {
+				continue
+			}
+			select {
+			case <-o.done:
+				log.Warning(ctx, "leader.elect> routine %s shard %d exited, it will be restarted", r.Name, i)
+				e.stop(o)
+				stopped = append(stopped, o)
+				continue
+			default:
+			}
+			if !e.renew(ctx, r.Name, i) {
+				log.Warning(ctx, "leader.elect> routine %s shard %d is not owned anymore by %s", r.Name, i, e.instanceID)
+				e.stop(o)
+				stopped = append(stopped, o)
+				continue
+			}
+			owned = append(owned, i)
+		}
+
+		// Give back the shards above the share of the instance so that new instances get work
+		for len(owned) > share {
+			o := e.owned[lockKey(r.Name, owned[len(owned)-1])]
+			e.stop(o)
+			stopped = append(stopped, o)
+			owned = owned[:len(owned)-1]
+		}
+
+		for i := 0; i < total && len(owned) < share; i++ {
+			if _, ok := e.owned[lockKey(r.Name, i)]; ok {
+				continue
+			}
+			if e.acquire(ctx, r, i) {
+				owned = append(owned, i)
+			}
+		}
+	}
+}
+
+// renew extends the owner and lock keys of the shard if it is still owned by the current instance, both are checked
+// and extended atomically so that a shard taken over by another instance is never renewed.
+func (e *Elector) renew(ctx context.Context, name string, index int) bool {
+	b, err := e.store.ExpireIfEqual(ownerKey(name, index), e.instanceID, e.ttl, lockKey(name, index))
+	if err != nil {
+		log.Error(ctx, "leader.renew> unable to renew %s shard %d: %v", name, index, err)
+		return false
+	}
+	return b
+}
+
+func (e *Elector) acquire(ctx context.Context, r Routine, index int) bool {
+	b, err := e.store.Lock(lockKey(r.Name, index), e.ttl, 0, 1)
+	if err != nil {
+		log.Error(ctx, "leader.acquire> unable to lock %s shard %d: %v", r.Name, index, err)
+		return false
+	}
+	if !b {
+		return false
+	}
+	if err := e.store.SetWithDuration(ownerKey(r.Name, index), e.instanceID, e.ttl); err != nil {
+		log.Error(ctx, "leader.acquire> unable to set owner of %s shard %d: %v", r.Name, index, err)
+		_ = e.store.Unlock(lockKey(r.Name, index))
+		return false
+	}
+
+	log.Info(ctx, "leader.acquire> routine %s shard %d/%d is now owned by %s", r.Name, index+1, r.total(), e.instanceID)
+
+	shard := Shard{Index: index, Total: r.total()}
+	routineCtx, cancel := context.WithCancel(ctx)
+	o := &ownedShard{routine: r.Name, shard: shard, cancel: cancel, done: make(chan struct{})}
+	e.owned[lockKey(r.Name, index)] = o
+
+	f := r.Func
+	sdk.GoRoutine(routineCtx, fmt.Sprintf("%s-%d", r.Name, index), func(ctx context.Context) {
+		defer close(o.done)
+		f(ctx, shard)
+	}, e.panicDump...)
+
+	return true
+}
+
+// stop cancels the routine shard, it should be released once the mutex is unlocked.
+func (e *Elector) stop(o *ownedShard) {
+	delete(e.owned, lockKey(o.routine, o.shard.Index))
+	o.cancel()
+}
+
+// release waits for the stopped routine shard to return then unlocks it if it is still owned by the current instance,
+// both owner and lock keys are checked and deleted atomically so that a shard taken over by another instance is never
+// unlocked.
+func (e *Elector) release(ctx context.Context, o *ownedShard) {
+	select {
+	case <-o.done:
+	case <-time.After(handoverTimeout):
+		log.Warning(ctx, "leader.release> routine %s shard %d did not stop after %v", o.routine, o.shard.Index, handoverTimeout)
+	}
+
+	if _, err := e.store.DeleteIfEqual(ownerKey(o.routine, o.shard.Index), e.instanceID, lockKey(o.routine, o.shard.Index)); err != nil {
+		log.Error(ctx, "leader.release> unable to unlock %s shard %d: %v", o.routine, o.shard.Index, err)
+	}
+}
+
+// Release stops all the routines owned by the current instance and releases their locks so that other instances can
+// take over without waiting for the locks to expire. The elector does not acquire routines anymore after.
+func (e *Elector) Release() {
+	e.mutex.Lock()
+	if e.stopped {
+		e.mutex.Unlock()
+		return
+	}
+	e.stopped = true
+	stopped := make([]*ownedShard, 0, len(e.owned))
+	for _, o := range e.owned {
+		e.stop(o)
+		stopped = append(stopped, o)
+	}
+	e.mutex.Unlock()
+
+	ctx := context.Background()
+	for _, o := range stopped {
+		e.release(ctx, o)
+	}
+	if err := e.store.Delete(instanceKey(e.instanceID)); err != nil {
+		log.Error(ctx, "leader.Release> unable to unregister instance %s: %v", e.instanceID, err)
+	}
+	log.Info(ctx, "leader.Release> all routines released by %s", e.instanceID)
+}
+
+// Status returns a monitoring line for each routine with its current owners.
+func (e *Elector) Status(ctx context.Context) []sdk.MonitoringStatusLine {
+	e.mutex.Lock()
+	routines := make([]Routine, len(e.routines))
+	copy(routines, e.routines)
+	e.mutex.Unlock()
+
+	sort.Slice(routines, func(i, j int) bool { return routines[i].Name < routines[j].Name })
+
+	lines := make([]sdk.MonitoringStatusLine, 0, len(routines))
+	for _, r := range routines {
+		status := sdk.MonitoringStatusOK
+		owners := make([]string, 0, r.total())
+		for i := 0; i < r.total(); i++ {
+			var owner string
+			found, err := e.store.Get(ownerKey(r.Name, i), &owner)
+			if err != nil {
+				log.Error(ctx, "leader.Status> unable to get owner of %s shard %d: %v", r.Name, i, err)
+			}
+			if !found || owner == "" {
+				status = sdk.MonitoringStatusWarn
+				owner = "no owner"
+			}
+			if r.Mode == ModeSharded {
+				owner = fmt.Sprintf("%d:%s", i+1, owner)
+			}
+			owners = append(owners, owner)
+		}
+		lines = append(lines, sdk.MonitoringStatusLine{
+			Component: "Leader/" + r.Name,
+			Value:     strings.Join(owners, " "),
+			Status:    status,
+		})
+	}
+	return lines
+}
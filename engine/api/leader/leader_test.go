@@ -0,0 +1,266 @@
+package leader
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// memoryStore keeps the expiration of the keys but never expires them, tests expire them explicitly.
+type memoryStore struct {
+	mutex      sync.Mutex
+	data       map[string][]byte
+	expiration map[string]time.Duration
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{data: make(map[string][]byte), expiration: make(map[string]time.Duration)}
+}
+
+func (s *memoryStore) Lock(key string, expiration time.Duration, _ int, _ int) (bool, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if _, ok := s.data[key]; ok {
+		return false, nil
+	}
+	s.data[key] = []byte("true")
+	s.expiration[key] = expiration
+	return true, nil
+}
+
+func (s *memoryStore) Unlock(key string) error { return s.Delete(key) }
+
+func (s *memoryStore) ScanKeys(pattern string) ([]string, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	var res []string
+	for k := range s.data {
+		if strings.HasPrefix(k, strings.TrimSuffix(pattern, "*")) {
+			res = append(res, k)
+		}
+	}
+	return res, nil
+}
+
+func (s *memoryStore) Get(key string, value interface{}) (bool, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	v, ok := s.data[key]
+	if !ok {
+		return false, nil
+	}
+	return true, json.Unmarshal(v, value)
+}
+
+func (s *memoryStore) SetWithDuration(key string, value interface{}, duration time.Duration) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	btes, err := json.Marshal(value)
+	s.data[key] = btes
+	s.expiration[key] = duration
+	return err
+}
+
+func (s *memoryStore) ExpireIfEqual(key string, value interface{}, expiration time.Duration, otherKeys ...string) (bool, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	btes, err := json.Marshal(value)
+	if err != nil {
+		return false, err
+	}
+	if v, ok := s.data[key]; !ok || string(v) != string(btes) {
+		return false, nil
+	}
+	for _, k := range append([]string{key}, otherKeys...) {
+		if _, ok := s.data[k]; ok {
+			s.expiration[k] = expiration
+		}
+	}
+	return true, nil
+}
+
+func (s *memoryStore) Delete(key string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	delete(s.data, key)
+	delete(s.expiration, key)
+	return nil
+}
+
+func (s *memoryStore) DeleteIfEqual(key string, value interface{}, otherKeys ...string) (bool, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	btes, err := json.Marshal(value)
+	if err != nil {
+		return false, err
+	}
+	if v, ok := s.data[key]; !ok || string(v) != string(btes) {
+		return false, nil
+	}
+	for _, k := range append([]string{key}, otherKeys...) {
+		delete(s.data, k)
+		delete(s.expiration, k)
+	}
+	return true, nil
+}
+
+func (s *memoryStore) expiresIn(key string) time.Duration {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.expiration[key]
+}
+
+func (s *memoryStore) expire(key string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	delete(s.data, key)
+	delete(s.expiration, key)
+}
+
+func blockingRoutine(name string, mode Mode, shards int) Routine {
+	return Routine{Name: name, Mode: mode, Shards: shards, Func: func(ctx context.Context, _ Shard) { <-ctx.Done() }}
+}
+
+func countOwned(e *Elector) int {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	return len(e.owned)
+}
+
+func TestElectorSingleton(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	store := newMemoryStore()
+	e1 := New(store, "api-1")
+	e1.Register(blockingRoutine("purge", ModeSingleton, 0))
+	e2 := New(store, "api-2")
+	e2.Register(blockingRoutine("purge", ModeSingleton, 0))
+
+	e1.elect(ctx)
+	e2.elect(ctx)
+	require.Equal(t, 1, countOwned(e1))
+	require.Equal(t, 0, countOwned(e2))
+
+	lines := e2.Status(ctx)
+	require.Len(t, lines, 1)
+	require.Equal(t, "Leader/purge", lines[0].Component)
+	require.Equal(t, "api-1", lines[0].Value)
+	require.Equal(t, sdk.MonitoringStatusOK, lines[0].Status)
+
+	// Graceful handover on shutdown
+	e1.Release()
+	e2.elect(ctx)
+	require.Equal(t, 0, countOwned(e1))
+	require.Equal(t, 1, countOwned(e2))
+	require.Equal(t, "api-2", e1.Status(ctx)[0].Value)
+}
+
+func TestElectorSharded(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	store := newMemoryStore()
+	e1 := New(store, "api-1")
+	e1.Register(blockingRoutine("craft", ModeSharded, 4))
+	e2 := New(store, "api-2")
+	e2.Register(blockingRoutine("craft", ModeSharded, 4))
+
+	e1.elect(ctx)
+	require.Equal(t, 4, countOwned(e1))
+
+	// A new instance joins, shards are given back at the next election of the first one
+	e2.elect(ctx)
+	require.Equal(t, 0, countOwned(e2))
+	e1.elect(ctx)
+	require.Equal(t, 2, countOwned(e1))
+	e2.elect(ctx)
+	require.Equal(t, 2, countOwned(e2))
+
+	lines := e1.Status(ctx)
+	require.Len(t, lines, 1)
+	require.Equal(t, "1:api-1 2:api-1 3:api-2 4:api-2", lines[0].Value)
+}
+
+func TestElectorTakeover(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	store := newMemoryStore()
+	e1 := New(store, "api-1")
+	e1.Register(blockingRoutine("purge", ModeSingleton, 0))
+	e1.elect(ctx)
+	require.Equal(t, 1, countOwned(e1))
+
+	// The lock expired and another instance took the routine, it is not renewed nor released by the first one
+	store.expire(lockKey("purge", 0))
+	store.expire(ownerKey("purge", 0))
+	e2 := New(store, "api-2")
+	e2.Register(blockingRoutine("purge", ModeSingleton, 0))
+	e2.elect(ctx)
+	require.Equal(t, 1, countOwned(e2))
+
+	e1.elect(ctx)
+	require.Equal(t, 0, countOwned(e1))
+	require.Equal(t, 1, countOwned(e2))
+	require.Equal(t, "api-2", e1.Status(ctx)[0].Value)
+	found, err := store.Get(lockKey("purge", 0), new(bool))
+	require.NoError(t, err)
+	require.True(t, found, "the lock of the new owner should not be released")
+}
+
+func TestElectorRenew(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	store := newMemoryStore()
+	e1 := New(store, "api-1")
+	e1.Register(blockingRoutine("purge", ModeSingleton, 0))
+	e1.elect(ctx)
+	require.Equal(t, 1, countOwned(e1))
+
+	// Owner and lock keys are both renewed
+	e1.ttl = time.Minute
+	e1.elect(ctx)
+	require.Equal(t, 1, countOwned(e1))
+	require.Equal(t, time.Minute, store.expiresIn(ownerKey("purge", 0)))
+	require.Equal(t, time.Minute, store.expiresIn(lockKey("purge", 0)))
+}
+
+func TestShardSet(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	var s ShardSet
+	indexes, total := s.Owned()
+	require.Empty(t, indexes)
+
+	done := make(chan struct{})
+	go func() {
+		s.Run(ctx, Shard{Index: 2, Total: 4})
+		close(done)
+	}()
+	require.Eventually(t, func() bool {
+		indexes, total = s.Owned()
+		return len(indexes) == 1
+	}, time.Second, 10*time.Millisecond)
+	require.Equal(t, []int64{2}, indexes)
+	require.Equal(t, 4, total)
+
+	cancel()
+	<-done
+	indexes, _ = s.Owned()
+	require.Empty(t, indexes)
+}
+
+func TestShardOwns(t *testing.T) {
+	require.True(t, Shard{Index: 0, Total: 1}.Owns(42))
+	require.True(t, Shard{Index: 2, Total: 4}.Owns(42))
+	require.False(t, Shard{Index: 1, Total: 4}.Owns(42))
+}
@@ -34,7 +34,30 @@ func (a *API) listenMaintenance(c context.Context) error {
 				log.Warning(c, "listenMaintenance> Cannot parse value %s: %s", msg, err)
 			}
 			a.Maintenance = b
+			if err := a.loadMaintenanceDrain(); err != nil {
+				log.Warning(c, "listenMaintenance> Cannot load maintenance drain: %v", err)
+			}
 			event.PublishMaintenanceEvent(c, sdk.EventMaintenance{Enable: b})
 		}
 	}
 }
+
+// loadMaintenanceDrain reads the maintenance drain state from cache, it is nil if no drain is in progress.
+func (a *API) loadMaintenanceDrain() error {
+	var drain sdk.MaintenanceDrain
+	find, err := a.Cache.Get(sdk.MaintenanceDrainAPIKey, &drain)
+	if err != nil {
+		return sdk.WrapError(err, "cannot get %s from cache", sdk.MaintenanceDrainAPIKey)
+	}
+	if !find {
+		a.MaintenanceDrain = nil
+		return nil
+	}
+	a.MaintenanceDrain = &drain
+	return nil
+}
+
+// isDraining returns true if a maintenance is enabled in drain mode, then no new job should be dispatched.
+func (a *API) isDraining() bool {
+	return a.Maintenance && a.MaintenanceDrain != nil
+}
@@ -189,6 +189,19 @@ func (s *AWSS3Store) DeleteContainer(ctx context.Context, path string) error {
 	return nil
 }
 
+// ObjectSize returns the size of an object stored in a bucket
+func (s *AWSS3Store) ObjectSize(o Object) (int64, error) {
+	s3n := s3.New(s.sess)
+	out, err := s3n.HeadObject(&s3.HeadObjectInput{
+		Key:    aws.String(s.getObjectPath(o)),
+		Bucket: aws.String(s.bucketName),
+	})
+	if err != nil {
+		return 0, sdk.WrapError(err, "AWS-S3-Store> Unable to get object %s", s.getObjectPath(o))
+	}
+	return aws.Int64Value(out.ContentLength), nil
+}
+
 // FetchURL returns a temporary url and a secret key to fetch an object
 func (s *AWSS3Store) FetchURL(o Object) (string, string, error) {
 	log.Debug("AWS-S3-Store> FetchURL")
@@ -36,6 +36,8 @@ type DriverWithRedirect interface {
 	FetchURL(o Object) (url string, key string, err error)
 	// ServeStaticFilesURL returns a temporary url and a secret key to serve static files in a container
 	ServeStaticFilesURL(o Object, entrypoint string) (string, string, error)
+	// ObjectSize returns the size of an object stored with a temporary url
+	ObjectSize(o Object) (int64, error)
 }
 
 // Kind will define const defining all supported objecstore drivers
@@ -197,6 +197,22 @@ func (s *SwiftStore) DeleteContainer(ctx context.Context, containerPath string)
 	return nil
 }
 
+// ObjectSize returns the size of an object stored in swift
+func (s *SwiftStore) ObjectSize(o Object) (int64, error) {
+	container := s.containerPrefix + o.GetPath()
+	object := o.GetName()
+	escape(container, object)
+
+	info, _, err := s.Object(container, object)
+	if err != nil {
+		if err.Error() == swift.ObjectNotFound.Text {
+			return 0, sdk.WithStack(sdk.ErrNotFound)
+		}
+		return 0, sdk.WrapError(err, "Unable to get object %s/%s", container, object)
+	}
+	return info.Bytes, nil
+}
+
 // StoreURL returns a temporary url and a secret key to store an object
 func (s *SwiftStore) StoreURL(o Object, contentType string) (string, string, error) {
 	container := s.containerPrefix + o.GetPath()
@@ -31,6 +31,7 @@ type pipelineAction struct {
 	Args            *string   `db:"args"`
 	Enabled         bool      `db:"enabled"`
 	LastModified    time.Time `db:"last_modified"`
+	Matrix          *string   `db:"matrix"`
 }
 
 func pipelineActionsToIDs(pas []pipelineAction) []int64 {
@@ -81,7 +81,7 @@ func LoadPipelineStage(ctx context.Context, db gorp.SqlExecutor, p *sdk.Pipeline
 	SELECT pipeline_stage_R.id as stage_id, pipeline_stage_R.pipeline_id, pipeline_stage_R.name, pipeline_stage_R.last_modified,
 			pipeline_stage_R.build_order, pipeline_stage_R.enabled, pipeline_stage_R.conditions,
 			pipeline_action_R.id as pipeline_action_id, pipeline_action_R.action_id, pipeline_action_R.action_last_modified,
-			pipeline_action_R.action_args, pipeline_action_R.action_enabled
+			pipeline_action_R.action_args, pipeline_action_R.action_enabled, pipeline_action_R.action_matrix
 	FROM (
 		SELECT pipeline_stage.id, pipeline_stage.pipeline_id,
 				pipeline_stage.name, pipeline_stage.last_modified, pipeline_stage.build_order,
@@ -93,7 +93,7 @@ func LoadPipelineStage(ctx context.Context, db gorp.SqlExecutor, p *sdk.Pipeline
 	LEFT OUTER JOIN (
 		SELECT pipeline_action.id, action.id as action_id, action.name as action_name, action.last_modified as action_last_modified,
 				pipeline_action.args as action_args, pipeline_action.enabled as action_enabled,
-				pipeline_action.pipeline_stage_id
+				pipeline_action.matrix as action_matrix, pipeline_action.pipeline_stage_id
 		FROM action
 		JOIN pipeline_action ON pipeline_action.action_id = action.id
 	) as pipeline_action_R ON pipeline_action_R.pipeline_stage_id = pipeline_stage_R.id
@@ -116,14 +116,14 @@ func LoadPipelineStage(ctx context.Context, db gorp.SqlExecutor, p *sdk.Pipeline
 		var stageBuildOrder int
 		var pipelineActionID, actionID sql.NullInt64
 		var stageName string
-		var stageConditions, actionArgs sql.NullString
+		var stageConditions, actionArgs, actionMatrix sql.NullString
 		var stageEnabled, actionEnabled sql.NullBool
 		var stageLastModified, actionLastModified pq.NullTime
 
 		err = rows.Scan(
 			&stageID, &pipelineID, &stageName, &stageLastModified,
 			&stageBuildOrder, &stageEnabled, &stageConditions, &pipelineActionID, &actionID, &actionLastModified,
-			&actionArgs, &actionEnabled)
+			&actionArgs, &actionEnabled, &actionMatrix)
 		if err != nil {
 			return sdk.WithStack(err)
 		}
@@ -160,6 +160,9 @@ func LoadPipelineStage(ctx context.Context, db gorp.SqlExecutor, p *sdk.Pipeline
 						ID: actionID.Int64,
 					},
 				}
+				if err := gorpmapping.JSONNullString(actionMatrix, &j.Matrix); err != nil {
+					return sdk.WrapError(err, "cannot unmarshal job matrix for pipeline action id %d", pipelineActionID.Int64)
+				}
 				mapAllActions[pipelineActionID.Int64] = j
 				mapActionsStages[stageID] = append(mapActionsStages[stageID], *j)
 
@@ -98,6 +98,8 @@ func (api *API) getProjectsHandler_FilterByRepo(ctx context.Context, w http.Resp
 		}
 	}
 
+	projects = filterProjectsByScopes(ctx, projects)
+
 	if strings.ToUpper(withPermissions) == "W" {
 		res := make([]sdk.Project, 0, len(projects))
 		for _, p := range projects {
@@ -199,6 +201,8 @@ func (api *API) getProjectsHandler() service.Handler {
 			}
 		}
 
+		projects = filterProjectsByScopes(ctx, projects)
+
 		if strings.ToUpper(withPermissions) == "W" {
 			res := make([]sdk.Project, 0, len(projects))
 			for _, p := range projects {
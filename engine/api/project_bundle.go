@@ -0,0 +1,107 @@
+package api
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/ovh/cds/engine/api/event"
+	"github.com/ovh/cds/engine/api/integration"
+	"github.com/ovh/cds/engine/api/project"
+	"github.com/ovh/cds/engine/api/projectbundle"
+	"github.com/ovh/cds/engine/gorpmapper"
+	"github.com/ovh/cds/engine/service"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/exportentities"
+)
+
+func (api *API) postProjectExportHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		key := vars[permProjectKey]
+
+		b, err := projectbundle.Export(ctx, api.mustDB(), api.Cache, key, r.Header.Get(sdk.ProjectBundlePassphraseHeader))
+		if err != nil {
+			return err
+		}
+
+		buf := new(bytes.Buffer)
+		if err := exportentities.TarProjectBundle(ctx, b, buf); err != nil {
+			return err
+		}
+
+		w.Header().Add("Content-Type", "application/tar")
+		w.WriteHeader(http.StatusOK)
+		_, err = io.Copy(w, buf)
+		return sdk.WrapError(err, "unable to copy content buffer in the response writer")
+	}
+}
+
+func (api *API) postProjectImportHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		key := vars["key"]
+		consumer := getAPIConsumer(ctx)
+
+		exist, err := project.Exist(api.mustDB(), key)
+		if err != nil {
+			return sdk.WrapError(err, "cannot check if project %s exist", key)
+		}
+		if exist {
+			if err := api.checkProjectPermissions(ctx, key, sdk.PermissionReadWriteExecute, vars); err != nil {
+				return err
+			}
+		} else {
+			// The project will be created, the consumer's scopes should allow to create a project like on POST /project
+			if _, err := consumerMatchingScopes(consumer, api.Router.getRouteConfig(http.MethodPost, api.postProjectHandler)); err != nil {
+				return sdk.NewErrorWithStack(err, sdk.ErrForbidden)
+			}
+		}
+
+		if r.Body == nil {
+			return sdk.WithStack(sdk.ErrWrongRequest)
+		}
+		defer r.Body.Close()
+
+		b, err := exportentities.UntarProjectBundle(ctx, tar.NewReader(r.Body))
+		if err != nil {
+			return err
+		}
+
+		opts := projectbundle.ImportOptions{
+			Key:    key,
+			DryRun: FormBool(r, "dryRun"),
+			OnCreate: func(ctx context.Context, db gorpmapper.SqlExecutorWithTx, p sdk.Project) error {
+				models, err := integration.LoadModels(db)
+				if err != nil {
+					return sdk.WrapError(err, "cannot load integration models")
+				}
+				for i := range models {
+					if err := propagatePublicIntegrationModelOnProject(ctx, db, api.Cache, models[i], p, consumer); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+		}
+
+		res, err := projectbundle.Import(ctx, api.mustDB(), api.Cache, b, r.Header.Get(sdk.ProjectBundlePassphraseHeader), opts, consumer)
+		if err != nil {
+			return err
+		}
+
+		if !opts.DryRun && !exist {
+			proj, err := project.Load(ctx, api.mustDB(), key)
+			if err != nil {
+				return err
+			}
+			event.PublishAddProject(ctx, proj, consumer)
+		}
+
+		return service.WriteJSON(w, res, http.StatusOK)
+	}
+}
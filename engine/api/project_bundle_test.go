@@ -0,0 +1,76 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ovh/cds/engine/api/authentication"
+	"github.com/ovh/cds/engine/api/authentication/builtin"
+	"github.com/ovh/cds/engine/api/project"
+	"github.com/ovh/cds/engine/api/test/assets"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/exportentities"
+)
+
+func Test_postProjectImportHandler_CreateProject(t *testing.T) {
+	api, db, _ := newTestAPI(t)
+
+	u, jwt := assets.InsertLambdaUser(t, db)
+	localConsumer, err := authentication.LoadConsumerByTypeAndUserID(context.TODO(), db, sdk.ConsumerLocal, u.ID, authentication.LoadConsumerOptions.WithAuthentifiedUser)
+	require.NoError(t, err)
+
+	// The consumer can only import bundles, it is not allowed to create projects
+	consumer, _, err := builtin.NewConsumer(context.TODO(), db, sdk.RandomString(10), "", localConsumer, u.GetGroupIDs(), []sdk.AuthConsumerScopeDetail{
+		{
+			Scope:     sdk.AuthConsumerScopeProject,
+			Endpoints: sdk.AuthConsumerScopeEndpoints{{Route: "/project/{key}/import/bundle", Methods: []string{http.MethodPost}}},
+		},
+	})
+	require.NoError(t, err)
+	session, err := authentication.NewSession(context.TODO(), db, consumer, time.Minute, false)
+	require.NoError(t, err)
+	jwtRestricted, err := authentication.NewSessionJWT(session)
+	require.NoError(t, err)
+
+	key := strings.ToUpper(sdk.RandomString(10))
+	buf := new(bytes.Buffer)
+	require.NoError(t, exportentities.TarProjectBundle(context.TODO(), exportentities.ProjectBundle{
+		Project: exportentities.Project{
+			Version: exportentities.ProjectVersion1,
+			Key:     key,
+			Name:    key,
+			Salt:    "c2FsdHNhbHRzYWx0c2FsdA==",
+		},
+	}, buf))
+	bundle := buf.Bytes()
+
+	uri := api.Router.GetRoute(http.MethodPost, api.postProjectImportHandler, map[string]string{"key": key})
+	req := assets.NewJWTAuthentifiedRequest(t, jwtRestricted, http.MethodPost, uri, nil)
+	req.Body = ioutil.NopCloser(bytes.NewReader(bundle))
+	req.Header.Set(sdk.ProjectBundlePassphraseHeader, "passphrase")
+	w := httptest.NewRecorder()
+	api.Router.Mux.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	exist, err := project.Exist(db, key)
+	require.NoError(t, err)
+	assert.False(t, exist)
+
+	req = assets.NewJWTAuthentifiedRequest(t, jwt, http.MethodPost, uri, nil)
+	req.Body = ioutil.NopCloser(bytes.NewReader(bundle))
+	req.Header.Set(sdk.ProjectBundlePassphraseHeader, "passphrase")
+	w = httptest.NewRecorder()
+	api.Router.Mux.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	exist, err = project.Exist(db, key)
+	require.NoError(t, err)
+	assert.True(t, exist)
+}
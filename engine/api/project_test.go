@@ -9,6 +9,7 @@ import (
 	"net/url"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -285,6 +286,46 @@ func Test_getProjectsHandler_WithWPermissionShouldReturnOneProject(t *testing.T)
 	assert.Len(t, projs, 1, "should have one project")
 }
 
+func Test_getProjectsHandler_WithScopedConsumer(t *testing.T) {
+	api, db, _ := newTestAPI(t)
+
+	// Both projects have the same name so they share the same group
+	name := sdk.RandomString(10)
+	proj1 := assets.InsertTestProject(t, db, api.Cache, sdk.RandomString(10), name)
+	assets.InsertTestProject(t, db, api.Cache, sdk.RandomString(10), name)
+	u, jwtLocal := assets.InsertLambdaUser(t, db, &proj1.ProjectGroups[0].Group)
+	localConsumer, err := authentication.LoadConsumerByTypeAndUserID(context.TODO(), db, sdk.ConsumerLocal, u.ID, authentication.LoadConsumerOptions.WithAuthentifiedUser)
+	require.NoError(t, err)
+
+	consumer, _, err := builtin.NewConsumer(context.TODO(), db, sdk.RandomString(10), "", localConsumer, u.GetGroupIDs(), []sdk.AuthConsumerScopeDetail{
+		{Scope: sdk.AuthConsumerScopeProject, ProjectKeys: []string{proj1.Key}},
+	})
+	require.NoError(t, err)
+	session, err := authentication.NewSession(context.TODO(), db, consumer, time.Minute, false)
+	require.NoError(t, err)
+	jwt, err := authentication.NewSessionJWT(session)
+	require.NoError(t, err)
+
+	uri := api.Router.GetRoute("GET", api.getProjectsHandler, nil)
+	req := assets.NewJWTAuthentifiedRequest(t, jwt, "GET", uri, nil)
+	w := httptest.NewRecorder()
+	api.Router.Mux.ServeHTTP(w, req)
+	require.Equal(t, 200, w.Code)
+
+	var projs []sdk.Project
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &projs))
+	require.Len(t, projs, 1, "the project not allowed by the token scopes should be filtered")
+	assert.Equal(t, proj1.Key, projs[0].Key)
+
+	// The user's token without scope lists both projects
+	req = assets.NewJWTAuthentifiedRequest(t, jwtLocal, "GET", uri, nil)
+	w = httptest.NewRecorder()
+	api.Router.Mux.ServeHTTP(w, req)
+	require.Equal(t, 200, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &projs))
+	assert.Len(t, projs, 2)
+}
+
 func Test_getProjectsHandler_AsProvider(t *testing.T) {
 	api, db, tsURL := newTestServer(t)
 
@@ -0,0 +1,73 @@
+package projectbundle
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"io"
+
+	"github.com/go-gorp/gorp"
+	"golang.org/x/crypto/nacl/secretbox"
+	"golang.org/x/crypto/scrypt"
+
+	"github.com/ovh/cds/engine/api/keys"
+	"github.com/ovh/cds/sdk"
+)
+
+// Secrets of a bundle can't be encrypted with the builtin key of the project because they have to be
+// readable by another CDS instance, so they are encrypted with a key derived from a passphrase.
+
+const (
+	saltSize  = 16
+	nonceSize = 24
+)
+
+func newSalt() (string, error) {
+	salt := make([]byte, saltSize)
+	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+		return "", sdk.WrapError(err, "cannot generate salt")
+	}
+	return base64.StdEncoding.EncodeToString(salt), nil
+}
+
+func deriveKey(passphrase, salt string) (*[32]byte, error) {
+	if passphrase == "" {
+		return nil, sdk.NewErrorFrom(sdk.ErrWrongRequest, "a passphrase is required to encrypt or decrypt the secrets of a project bundle")
+	}
+	s, err := base64.StdEncoding.DecodeString(salt)
+	if err != nil || len(s) != saltSize {
+		return nil, sdk.NewErrorFrom(sdk.ErrWrongRequest, "invalid project bundle salt")
+	}
+	k, err := scrypt.Key([]byte(passphrase), s, 1<<15, 8, 1, 32)
+	if err != nil {
+		return nil, sdk.WrapError(err, "cannot derive key from passphrase")
+	}
+	var key [32]byte
+	copy(key[:], k)
+	return &key, nil
+}
+
+func encryptFunc(key *[32]byte) sdk.EncryptFunc {
+	return func(_ gorp.SqlExecutor, _ int64, _ string, content string) (string, error) {
+		var nonce [nonceSize]byte
+		if _, err := io.ReadFull(rand.Reader, nonce[:]); err != nil {
+			return "", sdk.WrapError(err, "cannot generate nonce")
+		}
+		return base64.StdEncoding.EncodeToString(secretbox.Seal(nonce[:], []byte(content), &nonce, key)), nil
+	}
+}
+
+func decryptFunc(key *[32]byte) keys.DecryptFunc {
+	return func(_ gorp.SqlExecutor, _ int64, token string) (string, error) {
+		b, err := base64.StdEncoding.DecodeString(token)
+		if err != nil || len(b) < nonceSize+secretbox.Overhead {
+			return "", sdk.NewErrorFrom(sdk.ErrWrongRequest, "invalid encrypted value in project bundle")
+		}
+		var nonce [nonceSize]byte
+		copy(nonce[:], b[:nonceSize])
+		content, ok := secretbox.Open(nil, b[nonceSize:], &nonce, key)
+		if !ok {
+			return "", sdk.NewErrorFrom(sdk.ErrWrongRequest, "cannot decrypt project bundle secrets, check the passphrase")
+		}
+		return string(content), nil
+	}
+}
@@ -0,0 +1,48 @@
+package projectbundle
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func TestEncryptDecrypt(t *testing.T) {
+	salt, err := newSalt()
+	require.NoError(t, err)
+
+	k, err := deriveKey("my-passphrase", salt)
+	require.NoError(t, err)
+
+	token, err := encryptFunc(k)(nil, 0, "my-var", "my-secret")
+	require.NoError(t, err)
+	assert.NotContains(t, token, "my-secret")
+
+	// a key derived from the same passphrase and salt decrypts the value
+	k2, err := deriveKey("my-passphrase", salt)
+	require.NoError(t, err)
+	content, err := decryptFunc(k2)(nil, 0, token)
+	require.NoError(t, err)
+	assert.Equal(t, "my-secret", content)
+
+	// a wrong passphrase is detected
+	k3, err := deriveKey("wrong-passphrase", salt)
+	require.NoError(t, err)
+	_, err = decryptFunc(k3)(nil, 0, token)
+	require.Error(t, err)
+	assert.True(t, sdk.ErrorIs(err, sdk.ErrWrongRequest))
+
+	_, err = decryptFunc(k2)(nil, 0, "not-encrypted")
+	require.Error(t, err)
+}
+
+func TestDeriveKeyWithoutPassphrase(t *testing.T) {
+	salt, err := newSalt()
+	require.NoError(t, err)
+	_, err = deriveKey("", salt)
+	require.Error(t, err)
+	_, err = deriveKey("my-passphrase", "invalid")
+	require.Error(t, err)
+}
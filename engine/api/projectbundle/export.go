@@ -0,0 +1,164 @@
+package projectbundle
+
+import (
+	"context"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/application"
+	"github.com/ovh/cds/engine/api/environment"
+	"github.com/ovh/cds/engine/api/integration"
+	"github.com/ovh/cds/engine/api/pipeline"
+	"github.com/ovh/cds/engine/api/project"
+	"github.com/ovh/cds/engine/api/workflow"
+	"github.com/ovh/cds/engine/cache"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/exportentities"
+	"github.com/ovh/cds/sdk/telemetry"
+)
+
+// Export returns a bundle with all the entities of a project, secrets are encrypted with a key
+// derived from given passphrase.
+func Export(ctx context.Context, db gorp.SqlExecutor, cache cache.Store, key, passphrase string) (exportentities.ProjectBundle, error) {
+	ctx, end := telemetry.Span(ctx, "projectbundle.Export")
+	defer end()
+
+	var b exportentities.ProjectBundle
+
+	salt, err := newSalt()
+	if err != nil {
+		return b, err
+	}
+	k, err := deriveKey(passphrase, salt)
+	if err != nil {
+		return b, err
+	}
+	encrypt := encryptFunc(k)
+
+	proj, err := project.Load(ctx, db, key, project.LoadOptions.WithGroups, project.LoadOptions.WithIntegrations)
+	if err != nil {
+		return b, sdk.WrapError(err, "cannot load project %s", key)
+	}
+
+	p, encryptedKeys, err := exportProject(db, *proj, encrypt)
+	if err != nil {
+		return b, err
+	}
+	b.Project = exportentities.NewProject(p, encryptedKeys)
+	b.Project.Salt = salt
+
+	appNames, err := application.LoadAllNames(db, proj.ID)
+	if err != nil {
+		return b, err
+	}
+	for _, n := range appNames {
+		app, err := application.Export(db, proj.Key, n.Name, encrypt)
+		if err != nil {
+			return b, err
+		}
+		b.Applications = append(b.Applications, app)
+	}
+
+	envNames, err := environment.LoadAllNames(db, proj.ID)
+	if err != nil {
+		return b, err
+	}
+	for _, n := range envNames {
+		env, err := environment.Export(ctx, db, proj.Key, n.Name, encrypt)
+		if err != nil {
+			return b, err
+		}
+		b.Environments = append(b.Environments, env)
+	}
+
+	pipNames, err := pipeline.LoadAllNames(db, proj.ID)
+	if err != nil {
+		return b, err
+	}
+	for _, n := range pipNames {
+		pip, err := pipeline.Export(ctx, db, proj.Key, n.Name)
+		if err != nil {
+			return b, err
+		}
+		b.Pipelines = append(b.Pipelines, pip)
+	}
+
+	wfNames, err := workflow.LoadAllNames(db, proj.ID)
+	if err != nil {
+		return b, err
+	}
+	for _, n := range wfNames {
+		pull, err := workflow.Pull(ctx, db, cache, *proj, n.Name, encrypt)
+		if err != nil {
+			return b, err
+		}
+		// applications, environments and pipelines are already exported at the project level
+		b.Workflows = append(b.Workflows, exportentities.WorkflowComponents{
+			Template: pull.Template,
+			Workflow: pull.Workflow,
+		})
+	}
+
+	return b, nil
+}
+
+// exportProject returns the project with its secret variables and integrations configurations encrypted, and its
+// encrypted keys. Integrations of public models are not exported as they are added on all projects.
+func exportProject(db gorp.SqlExecutor, proj sdk.Project, encrypt sdk.EncryptFunc) (sdk.Project, []exportentities.EncryptedKey, error) {
+	vars, err := project.LoadAllVariablesWithDecrytion(db, proj.ID)
+	if err != nil {
+		return proj, nil, err
+	}
+	proj.Variables = nil
+	for _, v := range vars {
+		if v.Type == sdk.SecretVariable {
+			v.Value, err = encrypt(db, proj.ID, v.Name, v.Value)
+			if err != nil {
+				return proj, nil, sdk.WrapError(err, "unable to encrypt variable %s", v.Name)
+			}
+		}
+		proj.Variables = append(proj.Variables, v)
+	}
+
+	its, err := integration.LoadIntegrationsByProjectIDWithClearPassword(db, proj.ID)
+	if err != nil {
+		return proj, nil, err
+	}
+	proj.Integrations = nil
+	for _, it := range its {
+		if it.Model.Public {
+			continue
+		}
+		cfg := it.Config.Clone()
+		for name, v := range cfg {
+			if v.Type == sdk.IntegrationConfigTypePassword {
+				v.Value, err = encrypt(db, proj.ID, it.Name+":"+name, v.Value)
+				if err != nil {
+					return proj, nil, sdk.WrapError(err, "unable to encrypt integration %s config", it.Name)
+				}
+				cfg[name] = v
+			}
+		}
+		it.Config = cfg
+		proj.Integrations = append(proj.Integrations, it)
+	}
+
+	pkeys, err := project.LoadAllKeysWithPrivateContent(db, proj.ID)
+	if err != nil {
+		return proj, nil, err
+	}
+	encryptedKeys := make([]exportentities.EncryptedKey, 0, len(pkeys))
+	for _, k := range pkeys {
+		content, err := encrypt(db, proj.ID, k.Name, k.Private)
+		if err != nil {
+			return proj, nil, sdk.WrapError(err, "unable to encrypt key %s", k.Name)
+		}
+		encryptedKeys = append(encryptedKeys, exportentities.EncryptedKey{
+			Type:    string(k.Type),
+			Name:    k.Name,
+			Content: content,
+		})
+	}
+
+	return proj, encryptedKeys, nil
+}
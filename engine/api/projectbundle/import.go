@@ -0,0 +1,585 @@
+package projectbundle
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/application"
+	"github.com/ovh/cds/engine/api/environment"
+	"github.com/ovh/cds/engine/api/group"
+	"github.com/ovh/cds/engine/api/integration"
+	"github.com/ovh/cds/engine/api/keys"
+	"github.com/ovh/cds/engine/api/pipeline"
+	"github.com/ovh/cds/engine/api/project"
+	"github.com/ovh/cds/engine/api/repositoriesmanager"
+	"github.com/ovh/cds/engine/api/workflow"
+	"github.com/ovh/cds/engine/api/workflowtemplate"
+	"github.com/ovh/cds/engine/cache"
+	"github.com/ovh/cds/engine/gorpmapper"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/exportentities"
+	"github.com/ovh/cds/sdk/slug"
+	"github.com/ovh/cds/sdk/telemetry"
+)
+
+// Types of the changes made by a bundle import.
+const (
+	changeTypeProject     = "project"
+	changeTypeVariable    = "variable"
+	changeTypeKey         = "key"
+	changeTypeIntegration = "integration"
+	changeTypeGroup       = "group"
+	changeTypeApplication = "application"
+	changeTypeEnvironment = "environment"
+	changeTypePipeline    = "pipeline"
+	changeTypeWorkflow    = "workflow"
+)
+
+var changeTypes = []string{changeTypeProject, changeTypeVariable, changeTypeKey, changeTypeIntegration, changeTypeGroup,
+	changeTypeApplication, changeTypeEnvironment, changeTypePipeline, changeTypeWorkflow}
+
+// ImportOptions are options to import a project bundle.
+type ImportOptions struct {
+	// Key of the imported project, the key of the bundle is used if empty.
+	Key    string
+	DryRun bool
+	// OnCreate is called in the import transaction when a new project is created, before the import of its
+	// applications, environments and pipelines.
+	OnCreate func(ctx context.Context, db gorpmapper.SqlExecutorWithTx, p sdk.Project) error
+}
+
+// parsedProject contains the project settings of a bundle with decrypted secrets.
+type parsedProject struct {
+	project      sdk.Project
+	variables    []sdk.ProjectVariable
+	keys         []sdk.ProjectKey
+	integrations []sdk.ProjectIntegration
+	groups       []sdk.GroupPermission
+}
+
+// Import creates or updates a project from a bundle and returns the list of changes, with dry run the whole import
+// is executed then rolled back.
+// Entities of the project that are not in the bundle are kept. If the project already exists, the consumer
+// permission on it should be checked before.
+func Import(ctx context.Context, db *gorp.DbMap, cache cache.Store, b exportentities.ProjectBundle, passphrase string,
+	opts ImportOptions, consumer *sdk.AuthConsumer) (sdk.ProjectBundleImportResult, error) {
+	ctx, end := telemetry.Span(ctx, "projectbundle.Import")
+	defer end()
+
+	key := opts.Key
+	if key == "" {
+		key = b.Project.Key
+	}
+	res := sdk.ProjectBundleImportResult{Key: key, DryRun: opts.DryRun}
+
+	if !regexp.MustCompile(sdk.ProjectKeyPattern).MatchString(key) {
+		return res, sdk.NewErrorFrom(sdk.ErrInvalidProjectKey, "project key %s do not respect pattern %s", key, sdk.ProjectKeyPattern)
+	}
+	if b.Project.Name == "" {
+		return res, sdk.NewErrorFrom(sdk.ErrInvalidProjectName, "project name must no be empty")
+	}
+
+	k, err := deriveKey(passphrase, b.Project.Salt)
+	if err != nil {
+		return res, err
+	}
+	decrypt := decryptFunc(k)
+
+	exist, err := project.Exist(db, key)
+	if err != nil {
+		return res, sdk.WrapError(err, "cannot check if project %s exist", key)
+	}
+	var old *sdk.Project
+	if exist {
+		old, err = project.Load(ctx, db, key,
+			project.LoadOptions.WithVariables,
+			project.LoadOptions.WithKeys,
+			project.LoadOptions.WithIntegrations,
+			project.LoadOptions.WithGroups,
+			project.LoadOptions.WithApplicationNames,
+			project.LoadOptions.WithEnvironmentNames,
+			project.LoadOptions.WithPipelineNames,
+			project.LoadOptions.WithWorkflowNames,
+		)
+		if err != nil {
+			return res, sdk.WrapError(err, "cannot load project %s", key)
+		}
+	}
+
+	parsed, err := parseProject(ctx, db, b.Project, key, decrypt, consumer, old, &res)
+	if err != nil {
+		return res, err
+	}
+
+	// secrets of applications and environments are decrypted during the import, check them first
+	// to fail before saving anything if the passphrase is wrong
+	if err := checkSecrets(db, b, decrypt); err != nil {
+		return res, err
+	}
+
+	var vcsServers []sdk.ProjectVCSServerLink
+	if old != nil {
+		vcsServers, err = repositoriesmanager.LoadAllProjectVCSServerLinksByProjectID(ctx, db, old.ID)
+		if err != nil {
+			return res, err
+		}
+	}
+	computeEntitiesChanges(b, old, vcsServers, &res)
+	sortChanges(&res)
+
+	// the whole bundle is imported in one transaction, with dry run everything is checked then rolled back
+	tx, err := db.Begin()
+	if err != nil {
+		return res, sdk.WithStack(err)
+	}
+	defer tx.Rollback() // nolint
+
+	proj, err := importProject(ctx, tx, cache, b, parsed, old, opts, decrypt, consumer)
+	if err != nil {
+		return res, err
+	}
+
+	for _, w := range b.Workflows {
+		if err := importWorkflow(ctx, tx, cache, proj.Key, w, opts.DryRun, decrypt, consumer); err != nil {
+			return res, err
+		}
+	}
+
+	if opts.DryRun {
+		return res, nil
+	}
+
+	if err := tx.Commit(); err != nil {
+		return res, sdk.WithStack(err)
+	}
+
+	return res, nil
+}
+
+func addChange(res *sdk.ProjectBundleImportResult, changeType, name string, exist bool) {
+	action := sdk.ProjectBundleChangeCreate
+	if exist {
+		action = sdk.ProjectBundleChangeUpdate
+	}
+	res.Changes = append(res.Changes, sdk.ProjectBundleChange{Type: changeType, Name: name, Action: action})
+}
+
+// sortChanges sorts changes by type then by name, as bundle entities are read from maps.
+func sortChanges(res *sdk.ProjectBundleImportResult) {
+	order := make(map[string]int, len(changeTypes))
+	for i, t := range changeTypes {
+		order[t] = i
+	}
+	sort.Slice(res.Changes, func(i, j int) bool {
+		ci, cj := res.Changes[i], res.Changes[j]
+		if ci.Type != cj.Type {
+			return order[ci.Type] < order[cj.Type]
+		}
+		return ci.Name < cj.Name
+	})
+	sort.Strings(res.Warnings)
+}
+
+// parseProject decrypts the project settings of a bundle and computes their changes.
+func parseProject(ctx context.Context, db gorp.SqlExecutor, ep exportentities.Project, key string, decrypt keys.DecryptFunc,
+	consumer *sdk.AuthConsumer, old *sdk.Project, res *sdk.ProjectBundleImportResult) (parsedProject, error) {
+	var p parsedProject
+
+	p.project = sdk.Project{
+		Key:         key,
+		Name:        ep.Name,
+		Description: ep.Description,
+		Metadata:    ep.Metadata,
+	}
+	addChange(res, changeTypeProject, key, old != nil)
+
+	for name, v := range ep.Variables {
+		if v.Type == "" {
+			v.Type = sdk.StringVariable
+		}
+		if v.Type == sdk.SecretVariable {
+			clear, err := decrypt(db, 0, v.Value)
+			if err != nil {
+				return p, sdk.WrapError(err, "unable to decrypt variable %s", name)
+			}
+			v.Value = clear
+		}
+		p.variables = append(p.variables, sdk.ProjectVariable{Name: name, Type: v.Type, Value: v.Value})
+		addChange(res, changeTypeVariable, name, old != nil && projectVariable(*old, name) != nil)
+	}
+
+	for name, kv := range ep.Keys {
+		pk, err := keys.Parse(db, 0, name, kv, decrypt)
+		if err != nil {
+			return p, sdk.WrapError(err, "unable to parse key %s", name)
+		}
+		p.keys = append(p.keys, sdk.ProjectKey{
+			Name:    pk.Name,
+			Type:    pk.Type,
+			Public:  pk.Public,
+			Private: pk.Private,
+			KeyID:   pk.KeyID,
+		})
+		addChange(res, changeTypeKey, name, old != nil && hasProjectKey(*old, name))
+	}
+
+	for name, it := range ep.Integrations {
+		model, err := integration.LoadModelByName(db, it.Model)
+		if err != nil {
+			if !sdk.ErrorIs(err, sdk.ErrNotFound) {
+				return p, err
+			}
+			res.Warnings = append(res.Warnings, fmt.Sprintf("integration %s is ignored, model %s does not exist", name, it.Model))
+			continue
+		}
+		cfg := make(sdk.IntegrationConfig, len(it.Config))
+		for k, v := range it.Config {
+			if v.Type == sdk.IntegrationConfigTypePassword {
+				clear, err := decrypt(db, 0, v.Value)
+				if err != nil {
+					return p, sdk.WrapError(err, "unable to decrypt integration %s config", name)
+				}
+				v.Value = clear
+			}
+			cfg[k] = sdk.IntegrationConfigValue{Type: v.Type, Value: v.Value}
+		}
+		p.integrations = append(p.integrations, sdk.ProjectIntegration{
+			Name:               name,
+			Model:              model,
+			IntegrationModelID: model.ID,
+			Config:             cfg,
+		})
+		var exist bool
+		if old != nil {
+			_, exist = old.GetIntegration(name)
+		}
+		addChange(res, changeTypeIntegration, name, exist)
+	}
+
+	for name, perm := range ep.Permissions {
+		if old != nil && hasProjectGroup(*old, name) {
+			continue
+		}
+		grp, err := group.LoadByName(ctx, db, name, group.LoadOptions.WithMembers)
+		if err != nil {
+			if !sdk.ErrorIs(err, sdk.ErrNotFound) {
+				return p, err
+			}
+			res.Warnings = append(res.Warnings, fmt.Sprintf("group %s is ignored, it does not exist", name))
+			continue
+		}
+		if group.IsDefaultGroupID(grp.ID) {
+			res.Warnings = append(res.Warnings, fmt.Sprintf("group %s is ignored, default group could not be added on a project", name))
+			continue
+		}
+		if !grp.IsMember(consumer.GetGroupIDs()) && !consumer.Admin() {
+			res.Warnings = append(res.Warnings, fmt.Sprintf("group %s is ignored, you should be member of the group to add it on a project", name))
+			continue
+		}
+		p.groups = append(p.groups, sdk.GroupPermission{Group: *grp, Permission: perm})
+		addChange(res, changeTypeGroup, name, false)
+	}
+
+	// a new project needs at least one group, a group is created with the project name like on project creation
+	if old != nil || len(p.groups) > 0 {
+		return p, nil
+	}
+	groupSlug := slug.Convert(p.project.Name)
+	existingGroup, err := group.LoadByName(ctx, db, groupSlug)
+	if err != nil && !sdk.ErrorIs(err, sdk.ErrNotFound) {
+		return p, err
+	}
+	if existingGroup != nil {
+		return p, sdk.NewErrorFrom(sdk.ErrWrongRequest, "none of the bundle groups can be added to the project and a new group %s cannot be created for given project name", groupSlug)
+	}
+	p.groups = append(p.groups, sdk.GroupPermission{Group: sdk.Group{Name: groupSlug}, Permission: sdk.PermissionReadWriteExecute})
+	addChange(res, changeTypeGroup, groupSlug, false)
+
+	return p, nil
+}
+
+func projectVariable(p sdk.Project, name string) *sdk.ProjectVariable {
+	for i := range p.Variables {
+		if p.Variables[i].Name == name {
+			return &p.Variables[i]
+		}
+	}
+	return nil
+}
+
+func hasProjectKey(p sdk.Project, name string) bool {
+	for _, k := range p.Keys {
+		if k.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func hasProjectGroup(p sdk.Project, name string) bool {
+	for _, gp := range p.ProjectGroups {
+		if gp.Group.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// checkSecrets returns an error if a secret of an application or environment can't be decrypted.
+func checkSecrets(db gorp.SqlExecutor, b exportentities.ProjectBundle, decrypt keys.DecryptFunc) error {
+	for _, app := range b.Applications {
+		for name, v := range app.Variables {
+			if v.Type == sdk.SecretVariable {
+				if _, err := decrypt(db, 0, v.Value); err != nil {
+					return sdk.WrapError(err, "unable to decrypt variable %s of application %s", name, app.Name)
+				}
+			}
+		}
+		for name, kv := range app.Keys {
+			if kv.Value == "" {
+				continue
+			}
+			if _, err := decrypt(db, 0, kv.Value); err != nil {
+				return sdk.WrapError(err, "unable to decrypt key %s of application %s", name, app.Name)
+			}
+		}
+		if app.VCSPassword != "" {
+			if _, err := decrypt(db, 0, app.VCSPassword); err != nil {
+				return sdk.WrapError(err, "unable to decrypt repository password of application %s", app.Name)
+			}
+		}
+	}
+	for _, env := range b.Environments {
+		for name, v := range env.Values {
+			if v.Type == sdk.SecretVariable {
+				if _, err := decrypt(db, 0, v.Value); err != nil {
+					return sdk.WrapError(err, "unable to decrypt variable %s of environment %s", name, env.Name)
+				}
+			}
+		}
+		for name, kv := range env.Keys {
+			if kv.Value == "" {
+				continue
+			}
+			if _, err := decrypt(db, 0, kv.Value); err != nil {
+				return sdk.WrapError(err, "unable to decrypt key %s of environment %s", name, env.Name)
+			}
+		}
+	}
+	return nil
+}
+
+// computeEntitiesChanges adds the changes of applications, environments, pipelines and workflows.
+func computeEntitiesChanges(b exportentities.ProjectBundle, old *sdk.Project, vcsServers []sdk.ProjectVCSServerLink, res *sdk.ProjectBundleImportResult) {
+	for _, app := range b.Applications {
+		addChange(res, changeTypeApplication, app.Name, old != nil && hasIDName(old.ApplicationNames, app.Name))
+		if app.VCSServer == "" {
+			continue
+		}
+		var linked bool
+		for _, s := range vcsServers {
+			if s.Name == app.VCSServer {
+				linked = true
+				break
+			}
+		}
+		if !linked {
+			res.Warnings = append(res.Warnings, fmt.Sprintf("application %s uses repository manager %s that is not linked to project %s", app.Name, app.VCSServer, res.Key))
+		}
+	}
+	for _, env := range b.Environments {
+		addChange(res, changeTypeEnvironment, env.Name, old != nil && hasIDName(old.EnvironmentNames, env.Name))
+	}
+	for _, pip := range b.Pipelines {
+		addChange(res, changeTypePipeline, pip.Name, old != nil && hasIDName(old.PipelineNames, pip.Name))
+	}
+	for _, w := range b.Workflows {
+		name := w.Template.Name
+		if w.Workflow != nil {
+			name = w.Workflow.GetName()
+		}
+		addChange(res, changeTypeWorkflow, name, old != nil && hasIDName(old.WorkflowNames, name))
+	}
+}
+
+func hasIDName(names sdk.IDNames, name string) bool {
+	for _, n := range names {
+		if n.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// importProject saves the project with its settings, applications, environments and pipelines.
+func importProject(ctx context.Context, tx gorpmapper.SqlExecutorWithTx, cache cache.Store, b exportentities.ProjectBundle, parsed parsedProject,
+	old *sdk.Project, opts ImportOptions, decrypt keys.DecryptFunc, consumer *sdk.AuthConsumer) (*sdk.Project, error) {
+	p := parsed.project
+	if old != nil {
+		p.ID = old.ID
+		p.Icon = old.Icon
+		if err := project.Update(tx, &p); err != nil {
+			return nil, err
+		}
+	} else {
+		if err := project.Insert(tx, &p); err != nil {
+			return nil, err
+		}
+	}
+
+	for i := range parsed.variables {
+		v := &parsed.variables[i]
+		if old != nil {
+			if oldVar := projectVariable(*old, v.Name); oldVar != nil {
+				v.ID = oldVar.ID
+				if err := project.UpdateVariable(tx, p.ID, v, oldVar, consumer); err != nil {
+					return nil, sdk.WrapError(err, "cannot update variable %s", v.Name)
+				}
+				continue
+			}
+		}
+		if err := project.InsertVariable(tx, p.ID, v, consumer); err != nil {
+			return nil, sdk.WrapError(err, "cannot add variable %s", v.Name)
+		}
+	}
+
+	pkeys := parsed.keys
+	// default keys are added on new project if the bundle doesn't contain them
+	if old == nil {
+		for _, t := range []sdk.KeyType{sdk.KeyTypeSSH, sdk.KeyTypePGP} {
+			name := sdk.GenerateProjectDefaultKeyName(p.Key, t)
+			if _, ok := b.Project.Keys[name]; ok {
+				continue
+			}
+			k, err := keys.GenerateKey(name, t)
+			if err != nil {
+				return nil, err
+			}
+			pkeys = append(pkeys, sdk.ProjectKey{Name: k.Name, Type: k.Type, Public: k.Public, Private: k.Private, KeyID: k.KeyID})
+		}
+	}
+	for i := range pkeys {
+		k := &pkeys[i]
+		k.ProjectID = p.ID
+		if old != nil && hasProjectKey(*old, k.Name) {
+			if err := project.DeleteProjectKey(tx, p.ID, k.Name); err != nil {
+				return nil, err
+			}
+		}
+		if err := project.InsertKey(tx, k); err != nil {
+			return nil, sdk.WrapError(err, "cannot add key %s", k.Name)
+		}
+	}
+
+	for i := range parsed.integrations {
+		it := &parsed.integrations[i]
+		it.ProjectID = p.ID
+		if old != nil {
+			if oldIt, has := old.GetIntegration(it.Name); has {
+				it.ID = oldIt.ID
+				if err := integration.UpdateIntegration(tx, *it); err != nil {
+					return nil, err
+				}
+				continue
+			}
+		}
+		if err := integration.InsertIntegration(tx, it); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := linkGroups(ctx, tx, p, parsed.groups, consumer); err != nil {
+		return nil, err
+	}
+
+	if old == nil && opts.OnCreate != nil {
+		if err := opts.OnCreate(ctx, tx, p); err != nil {
+			return nil, err
+		}
+	}
+
+	// reload the project with its integrations and keys used by the applications import
+	proj, err := project.Load(ctx, tx, p.Key,
+		project.LoadOptions.WithGroups,
+		project.LoadOptions.WithIntegrations,
+		project.LoadOptions.WithKeys,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, env := range b.Environments {
+		if _, _, _, err := environment.ParseAndImport(tx, *proj, env, environment.ImportOptions{Force: true}, decrypt, consumer); err != nil {
+			return nil, sdk.ErrorWithFallback(err, sdk.ErrWrongRequest, "unable to import environment %s/%s", proj.Key, env.Name)
+		}
+	}
+	for _, pip := range b.Pipelines {
+		if _, _, err := pipeline.ParseAndImport(ctx, tx, cache, *proj, pip, consumer, pipeline.ImportOptions{Force: true}); err != nil {
+			return nil, sdk.ErrorWithFallback(err, sdk.ErrWrongRequest, "unable to import pipeline %s/%s", proj.Key, pip.Name)
+		}
+	}
+	for i := range b.Applications {
+		app := &b.Applications[i]
+		if _, _, _, err := application.ParseAndImport(ctx, tx, cache, *proj, app, application.ImportOptions{Force: true}, decrypt, consumer); err != nil {
+			return nil, sdk.ErrorWithFallback(err, sdk.ErrWrongRequest, "unable to import application %s/%s", proj.Key, app.Name)
+		}
+	}
+
+	return proj, nil
+}
+
+// linkGroups adds given groups on the project, a group without id is created.
+func linkGroups(ctx context.Context, db gorpmapper.SqlExecutorWithTx, p sdk.Project, gps []sdk.GroupPermission, consumer *sdk.AuthConsumer) error {
+	for _, gp := range gps {
+		grp := gp.Group
+		if grp.ID == 0 {
+			if err := group.Create(ctx, db, &grp, consumer.AuthentifiedUser.ID); err != nil {
+				return err
+			}
+		}
+		if err := group.InsertLinkGroupProject(ctx, db, &group.LinkGroupProject{
+			GroupID:   grp.ID,
+			ProjectID: p.ID,
+			Role:      gp.Permission,
+		}); err != nil {
+			return sdk.WrapError(err, "cannot add group %s in project %s", grp.Name, p.Key)
+		}
+	}
+	return nil
+}
+
+// importWorkflow pushes a workflow or executes a template instance, the project is reloaded as each push
+// may add entities on it. Hooks are not registered with dry run as the transaction will be rolled back.
+func importWorkflow(ctx context.Context, tx gorpmapper.SqlExecutorWithTx, cache cache.Store, key string, data exportentities.WorkflowComponents,
+	dryRun bool, decrypt keys.DecryptFunc, consumer *sdk.AuthConsumer) error {
+	proj, err := project.Load(ctx, tx, key,
+		project.LoadOptions.WithGroups,
+		project.LoadOptions.WithApplications,
+		project.LoadOptions.WithEnvironments,
+		project.LoadOptions.WithPipelines,
+		project.LoadOptions.WithApplicationWithDeploymentStrategies,
+		project.LoadOptions.WithIntegrations,
+		project.LoadOptions.WithKeys,
+	)
+	if err != nil {
+		return sdk.WrapError(err, "cannot load project %s", key)
+	}
+
+	_, wti, err := workflowtemplate.CheckAndExecuteTemplateWithTx(ctx, tx, cache, *consumer, *proj, &data,
+		workflowtemplate.TemplateRequestModifiers.DefaultKeys(*proj))
+	if err != nil {
+		return err
+	}
+	_, wf, _, _, err := workflow.PushWithTx(ctx, tx, cache, proj, data, &workflow.PushOption{
+		IsDefaultBranch:       true,
+		DisableHookManagement: dryRun,
+	}, consumer, decrypt)
+	if err != nil {
+		return err
+	}
+	return workflowtemplate.UpdateTemplateInstanceWithWorkflow(ctx, tx, *wf, *consumer, wti)
+}
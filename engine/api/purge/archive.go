@@ -0,0 +1,114 @@
+package purge
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"time"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/objectstore"
+	"github.com/ovh/cds/engine/api/project"
+	"github.com/ovh/cds/engine/api/workflow"
+	"github.com/ovh/cds/sdk"
+)
+
+// ArchiveWorkflowRun serializes a workflow run with its node runs, job infos, tests, analyses and artifacts
+// references in the shared storage, then records the archive reference in database. The artifacts themselves
+// are not archived.
+func ArchiveWorkflowRun(ctx context.Context, db gorp.SqlExecutor, sharedStorage objectstore.Driver, workflowRunID int64) (*sdk.WorkflowRunArchive, error) {
+	wr, err := workflow.LoadRunByID(db, workflowRunID, workflow.LoadRunOptions{
+		WithArtifacts:       true,
+		WithStaticFiles:     true,
+		WithTests:           true,
+		WithCoverage:        true,
+		WithVulnerabilities: true,
+		WithDeleted:         true,
+	})
+	if err != nil {
+		return nil, sdk.WrapError(err, "cannot load workflow run %d", workflowRunID)
+	}
+
+	proj, err := project.LoadProjectByWorkflowID(db, wr.WorkflowID)
+	if err != nil {
+		return nil, sdk.WrapError(err, "cannot load project for workflow %d", wr.WorkflowID)
+	}
+
+	data := sdk.WorkflowRunArchiveData{
+		Archive: sdk.NewWorkflowRunArchive(proj.Key, *wr),
+		Run:     *wr,
+	}
+	for _, nrs := range wr.WorkflowNodeRuns {
+		for _, nr := range nrs {
+			analyses, err := workflow.LoadAnalysesByNodeRunID(ctx, db, nr.ID)
+			if err != nil {
+				return nil, err
+			}
+			data.Analyses = append(data.Analyses, analyses...)
+		}
+	}
+	data.Archive.Archived = time.Now()
+
+	btes, err := json.Marshal(data)
+	if err != nil {
+		return nil, sdk.WithStack(err)
+	}
+	data.Archive.Size = int64(len(btes))
+
+	if _, err := sharedStorage.Store(data.Archive, ioutil.NopCloser(bytes.NewReader(btes))); err != nil {
+		return nil, sdk.WrapError(err, "cannot store archive of workflow run %d", workflowRunID)
+	}
+
+	if err := workflow.InsertRunArchive(db, &data.Archive); err != nil {
+		return nil, err
+	}
+
+	return &data.Archive, nil
+}
+
+// LoadArchivedWorkflowRun returns the content of an archived workflow run from the shared storage.
+func LoadArchivedWorkflowRun(ctx context.Context, db gorp.SqlExecutor, sharedStorage objectstore.Driver, workflowID, number int64) (*sdk.WorkflowRunArchiveData, error) {
+	a, err := workflow.LoadRunArchiveByNumber(ctx, db, workflowID, number)
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := sharedStorage.Fetch(ctx, *a)
+	if err != nil {
+		return nil, sdk.WrapError(err, "cannot fetch archive of workflow run %d", number)
+	}
+	defer r.Close() // nolint
+
+	var data sdk.WorkflowRunArchiveData
+	if err := json.NewDecoder(r).Decode(&data); err != nil {
+		return nil, sdk.WrapError(err, "cannot read archive of workflow run %d", number)
+	}
+	data.Archive = *a
+
+	return &data, nil
+}
+
+// DeleteWorkflowRunArchives removes the archives of a workflow from the shared storage, the archive references
+// are deleted in database with the workflow.
+func DeleteWorkflowRunArchives(ctx context.Context, db gorp.SqlExecutor, sharedStorage objectstore.Driver, workflowID int64) error {
+	archives, err := workflow.LoadAllRunArchives(ctx, db, workflowID)
+	if err != nil {
+		return err
+	}
+	if len(archives) == 0 {
+		return nil
+	}
+
+	for _, a := range archives {
+		if err := sharedStorage.Delete(ctx, a); err != nil {
+			return sdk.WrapError(err, "cannot delete archive of workflow run %d", a.Number)
+		}
+	}
+	if err := sharedStorage.DeleteContainer(ctx, archives[0].GetPath()); err != nil {
+		return sdk.WrapError(err, "cannot delete archives container of workflow %d", workflowID)
+	}
+
+	return nil
+}
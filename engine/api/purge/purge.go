@@ -18,8 +18,9 @@ import (
 	"github.com/ovh/cds/sdk/telemetry"
 )
 
-//Initialize starts goroutines for workflows
-func Initialize(ctx context.Context, store cache.Store, DBFunc func() *gorp.DbMap, sharedStorage objectstore.Driver, workflowRunsMarkToDelete, workflowRunsDeleted *stats.Int64Measure) {
+//Initialize starts goroutines for workflows, if archive is true workflow runs are archived in the shared storage
+//before being deleted.
+func Initialize(ctx context.Context, store cache.Store, DBFunc func() *gorp.DbMap, sharedStorage objectstore.Driver, archive bool, workflowRunsMarkToDelete, workflowRunsDeleted *stats.Int64Measure) {
 	tickPurge := time.NewTicker(15 * time.Minute)
 	defer tickPurge.Stop()
 
@@ -37,12 +38,12 @@ func Initialize(ctx context.Context, store cache.Store, DBFunc func() *gorp.DbMa
 			}
 
 			log.Debug("purge> Deleting all workflow run marked to delete...")
-			if err := deleteWorkflowRunsHistory(ctx, DBFunc(), store, sharedStorage, workflowRunsDeleted); err != nil {
+			if err := deleteWorkflowRunsHistory(ctx, DBFunc(), store, sharedStorage, archive, workflowRunsDeleted); err != nil {
 				log.Warning(ctx, "purge> Error on deleteWorkflowRunsHistory : %v", err)
 			}
 
 			log.Debug("purge> Deleting all workflow marked to delete....")
-			if err := workflows(ctx, DBFunc(), store, workflowRunsMarkToDelete); err != nil {
+			if err := workflows(ctx, DBFunc(), store, sharedStorage, workflowRunsMarkToDelete); err != nil {
 				log.Warning(ctx, "purge> Error on workflows : %v", err)
 			}
 		}
@@ -50,7 +51,7 @@ func Initialize(ctx context.Context, store cache.Store, DBFunc func() *gorp.DbMa
 }
 
 // workflows purges all marked workflows
-func workflows(ctx context.Context, db *gorp.DbMap, store cache.Store, workflowRunsMarkToDelete *stats.Int64Measure) error {
+func workflows(ctx context.Context, db *gorp.DbMap, store cache.Store, sharedStorage objectstore.Driver, workflowRunsMarkToDelete *stats.Int64Measure) error {
 	query := "SELECT id, project_id FROM workflow WHERE to_delete = true ORDER BY id ASC"
 	res := []struct {
 		ID        int64 `db:"id"`
@@ -123,6 +124,11 @@ func workflows(ctx context.Context, db *gorp.DbMap, store cache.Store, workflowR
 			continue
 		}
 
+		if err := DeleteWorkflowRunArchives(ctx, db, sharedStorage, w.ID); err != nil {
+			log.Error(ctx, "purge.Workflows> unable to delete run archives of workflow %d: %v", w.ID, err)
+			continue
+		}
+
 		tx, err := db.Begin()
 		if err != nil {
 			return sdk.WrapError(err, "unable to start tx")
@@ -144,14 +150,21 @@ func workflows(ctx context.Context, db *gorp.DbMap, store cache.Store, workflowR
 	return nil
 }
 
-// deleteWorkflowRunsHistory is useful to delete all the workflow run marked with to delete flag in db
-func deleteWorkflowRunsHistory(ctx context.Context, db gorp.SqlExecutor, store cache.Store, sharedStorage objectstore.Driver, workflowRunsDeleted *stats.Int64Measure) error {
+// deleteWorkflowRunsHistory is useful to delete all the workflow run marked with to delete flag in db.
+// If archive is true, runs are archived before their artifacts are deleted.
+func deleteWorkflowRunsHistory(ctx context.Context, db gorp.SqlExecutor, store cache.Store, sharedStorage objectstore.Driver, archive bool, workflowRunsDeleted *stats.Int64Measure) error {
 	var workflowRunIDs []int64
 	if _, err := db.Select(&workflowRunIDs, "SELECT id FROM workflow_run WHERE to_delete = true ORDER BY id ASC LIMIT 2000"); err != nil {
 		return err
 	}
 
 	for _, workflowRunID := range workflowRunIDs {
+		if archive {
+			if _, err := ArchiveWorkflowRun(ctx, db, sharedStorage, workflowRunID); err != nil {
+				log.Error(ctx, "deleteWorkflowRunsHistory> unable to archive workflow run %d: %v", workflowRunID, err)
+				continue
+			}
+		}
 		if err := DeleteArtifacts(ctx, db, store, sharedStorage, workflowRunID); err != nil {
 			log.Error(ctx, "DeleteArtifacts> error while deleting artifacts: %v", err)
 			continue
@@ -396,6 +396,15 @@ func (c *vcsClient) CommitsBetweenRefs(ctx context.Context, fullname, base, head
 	return commits, nil
 }
 
+func (c *vcsClient) FilesBetweenRefs(ctx context.Context, fullname, base, head string) ([]string, error) {
+	var files []string
+	path := fmt.Sprintf("/vcs/%s/repos/%s/changes?base=%s&head=%s", c.name, fullname, url.QueryEscape(base), url.QueryEscape(head))
+	if _, err := c.doJSONRequest(ctx, "GET", path, nil, &files); err != nil {
+		return nil, sdk.WrapError(err, "unable to get changed files on repository %s from %s", fullname, c.name)
+	}
+	return files, nil
+}
+
 func (c *vcsClient) Commit(ctx context.Context, fullname, hash string) (sdk.VCSCommit, error) {
 	commit := sdk.VCSCommit{}
 	path := fmt.Sprintf("/vcs/%s/repos/%s/commits/%s", c.name, fullname, hash)
@@ -55,6 +55,7 @@ type Router struct {
 	URL              string
 	Middlewares      []service.Middleware
 	PostMiddlewares  []service.Middleware
+	AuditFunc        func(ctx context.Context, req *http.Request, rc *service.HandlerConfig, statusCode int)
 	mapRouterConfigs map[string]*service.RouterConfig
 	panicked         bool
 	nbPanic          int
@@ -421,6 +422,11 @@ func (r *Router) handle(uri string, scope HandlerScope, handlers ...*service.Han
 			telemetry.RecordFloat64(ctx, ServerLatency, float64(latency)/float64(time.Millisecond))
 			telemetry.Record(ctx, ServerRequestBytes, responseWriter.reqSize)
 			telemetry.Record(ctx, ServerResponseBytes, responseWriter.respSize)
+
+			// Audit all mutating calls, with their outcome
+			if r.AuditFunc != nil && (req.Method == http.MethodPost || req.Method == http.MethodPut || req.Method == http.MethodDelete) {
+				r.AuditFunc(ctx, req, rc, responseWriter.statusCode)
+			}
 		}
 
 		telemetry.Record(r.Background, Hits, 1)
@@ -9,4 +9,5 @@ const (
 	contextJWTRaw
 	contextDate
 	contextJWTFromCookie
+	contextScopeDetails
 )
@@ -13,6 +13,7 @@ import (
 	"github.com/ovh/cds/engine/api/services"
 	"github.com/ovh/cds/engine/api/user"
 	"github.com/ovh/cds/engine/api/worker"
+	"github.com/ovh/cds/engine/cache"
 	"github.com/ovh/cds/engine/service"
 	"github.com/ovh/cds/sdk"
 	"github.com/ovh/cds/sdk/log"
@@ -23,6 +24,8 @@ const (
 	jwtCookieName  = "jwt_token"
 	xsrfHeaderName = "X-XSRF-TOKEN"
 	xsrfCookieName = "xsrf_token"
+
+	consumerIPRejectionThrottle = time.Minute
 )
 
 func (api *API) authMiddleware(ctx context.Context, w http.ResponseWriter, req *http.Request, rc *service.HandlerConfig) (context.Context, error) {
@@ -100,35 +103,20 @@ func (api *API) authMiddleware(ctx context.Context, w http.ResponseWriter, req *
 	}
 
 	if consumer != nil {
+		// Checks that the request comes from an allowed network for the consumer
+		if err := api.checkConsumerAllowedIP(ctx, req, rc.CleanURL, consumer); err != nil {
+			return ctx, err
+		}
+
 		ctx = context.WithValue(ctx, contextAPIConsumer, consumer)
 
 		// Checks scopes, one of expected scopes should be in actual scopes
-		// Actual scope empty list means wildcard scope, we don't need to check scopes
-		expectedScopes, actualScopes := rc.AllowedScopes, consumer.ScopeDetails
-		if len(expectedScopes) > 0 && len(actualScopes) > 0 {
-			var found bool
-		findScope:
-			for i := range expectedScopes {
-				for j := range actualScopes {
-					if actualScopes[j].Scope == expectedScopes[i] {
-						// Check if there are scope details, if yes we should check if current route/method is allowed in restrictions
-						if len(actualScopes[j].Endpoints) == 0 {
-							found = true
-							break findScope
-						}
-
-						// if the route is not in current consumer allowed endpoints we should not validate the scope
-						if exists, endpoint := actualScopes[j].Endpoints.FindEndpoint(rc.CleanURL); exists &&
-							len(endpoint.Methods) == 0 || endpoint.Methods.Contains(rc.Method) {
-							found = true
-							break findScope
-						}
-					}
-				}
-			}
-			if !found {
-				return ctx, sdk.WrapError(sdk.ErrUnauthorized, "token scopes doesn't match expected: %v", expectedScopes)
-			}
+		scopes, err := consumerMatchingScopes(consumer, rc)
+		if err != nil {
+			return ctx, err
+		}
+		if scopes != nil {
+			ctx = context.WithValue(ctx, contextScopeDetails, scopes)
 		}
 
 		// Check that permission are valid for current route and consumer
@@ -158,6 +146,40 @@ func (api *API) authMiddleware(ctx context.Context, w http.ResponseWriter, req *
 	return ctx, nil
 }
 
+// checkConsumerAllowedIP returns an error if the client ip is not in consumer's allowed CIDRs.
+// Rejected attempts are stored in database so they can be reviewed later, at most once per consumer and ip
+// during consumerIPRejectionThrottle.
+func (api *API) checkConsumerAllowedIP(ctx context.Context, req *http.Request, route string, consumer *sdk.AuthConsumer) error {
+	ip := api.clientIP(req)
+	if consumer.IsAllowedIP(ip) {
+		return nil
+	}
+
+	errIP := sdk.WrapError(sdk.ErrUnauthorized, "ip %s is not allowed for consumer %s", ip, consumer.ID)
+
+	lockKey := cache.Key("api:consumer:rejection", consumer.ID, ip.String())
+	ok, err := api.Cache.Lock(lockKey, consumerIPRejectionThrottle, 0, 1)
+	if err != nil {
+		log.Error(ctx, "checkConsumerAllowedIP> %v", err)
+		return errIP
+	}
+	if !ok {
+		return errIP
+	}
+
+	rejection := sdk.AuthConsumerIPRejection{
+		ConsumerID: consumer.ID,
+		IP:         ip.String(),
+		Method:     req.Method,
+		Route:      route,
+	}
+	if err := authentication.InsertConsumerIPRejection(api.mustDB(), &rejection); err != nil {
+		log.Error(ctx, "checkConsumerAllowedIP> %v", err)
+	}
+
+	return errIP
+}
+
 // Checks static tokens
 func (api *API) authStatusTokenMiddleware(ctx context.Context, w http.ResponseWriter, req *http.Request, rc *service.HandlerConfig) (context.Context, bool, error) {
 	if len(rc.AllowedTokens) == 0 {
@@ -252,3 +274,35 @@ func (api *API) xsrfMiddleware(ctx context.Context, w http.ResponseWriter, req *
 
 	return ctx, nil
 }
+
+// consumerMatchingScopes returns the consumer's scopes that allow given route, their resource filters will be checked
+// with permissions. Actual scope empty list means wildcard scope, no scope is returned as we don't need to check them.
+func consumerMatchingScopes(consumer *sdk.AuthConsumer, rc *service.HandlerConfig) ([]sdk.AuthConsumerScopeDetail, error) {
+	expectedScopes, actualScopes := rc.AllowedScopes, consumer.ScopeDetails
+	if len(expectedScopes) == 0 || len(actualScopes) == 0 {
+		return nil, nil
+	}
+
+	var matchingScopes []sdk.AuthConsumerScopeDetail
+	for i := range expectedScopes {
+		for j := range actualScopes {
+			if actualScopes[j].Scope == expectedScopes[i] {
+				// Check if there are scope details, if yes we should check if current route/method is allowed in restrictions
+				if len(actualScopes[j].Endpoints) == 0 {
+					matchingScopes = append(matchingScopes, actualScopes[j])
+					continue
+				}
+
+				// if the route is not in current consumer allowed endpoints we should not validate the scope
+				if exists, endpoint := actualScopes[j].Endpoints.FindEndpoint(rc.CleanURL); exists &&
+					len(endpoint.Methods) == 0 || endpoint.Methods.Contains(rc.Method) {
+					matchingScopes = append(matchingScopes, actualScopes[j])
+				}
+			}
+		}
+	}
+	if len(matchingScopes) == 0 {
+		return nil, sdk.WrapError(sdk.ErrUnauthorized, "token scopes doesn't match expected: %v", expectedScopes)
+	}
+	return matchingScopes, nil
+}
@@ -42,6 +42,10 @@ func permissionFunc(api *API) map[string]PermCheckFunc {
 }
 
 func (api *API) checkPermission(ctx context.Context, routeVar map[string]string, permission int) error {
+	if err := checkScopeResources(ctx, routeVar); err != nil {
+		return err
+	}
+
 	for key, value := range routeVar {
 		if permFunc, ok := permissionFunc(api)[key]; ok {
 			if err := permFunc(ctx, value, permission, routeVar); err != nil {
@@ -52,6 +56,71 @@ func (api *API) checkPermission(ctx context.Context, routeVar map[string]string,
 	return nil
 }
 
+// checkScopeResources checks that project key and workflow name from route vars are
+// allowed by at least one of the consumer's scopes that match current route.
+func checkScopeResources(ctx context.Context, routeVars map[string]string) error {
+	projectKey := routeVars["permProjectKey"]
+	if projectKey == "" {
+		projectKey = routeVars["key"]
+	}
+	workflowName := routeVars["permWorkflowName"]
+
+	if isAllowedByScopes(ctx, projectKey, workflowName) {
+		return nil
+	}
+
+	log.Debug("checkScopeResources> %s is not authorized to %s/%s by its scopes", getAPIConsumer(ctx).ID, projectKey, workflowName)
+	return sdk.WrapError(sdk.ErrForbidden, "token scopes doesn't allow resource %s/%s", projectKey, workflowName)
+}
+
+// isAllowedByScopes returns true if given project key and workflow name are allowed by
+// at least one of the consumer's scopes that match current route.
+func isAllowedByScopes(ctx context.Context, projectKey, workflowName string) bool {
+	scopes, _ := ctx.Value(contextScopeDetails).([]sdk.AuthConsumerScopeDetail)
+	if len(scopes) == 0 {
+		return true
+	}
+	for i := range scopes {
+		if scopes[i].IsAllowedResource(projectKey, workflowName) {
+			return true
+		}
+	}
+	return false
+}
+
+// filterProjectsByScopes removes projects and workflows that are not allowed by the
+// consumer's scopes, list routes have no resource in their vars to check permissions on.
+func filterProjectsByScopes(ctx context.Context, projects sdk.Projects) sdk.Projects {
+	res := make(sdk.Projects, 0, len(projects))
+	for i := range projects {
+		if !isAllowedByScopes(ctx, projects[i].Key, "") {
+			continue
+		}
+		if projects[i].Workflows != nil {
+			ws := make([]sdk.Workflow, 0, len(projects[i].Workflows))
+			for _, w := range projects[i].Workflows {
+				if isAllowedByScopes(ctx, projects[i].Key, w.Name) {
+					ws = append(ws, w)
+				}
+			}
+			projects[i].Workflows = ws
+		}
+		res = append(res, projects[i])
+	}
+	return res
+}
+
+// filterWorkflowsByScopes removes workflows that are not allowed by the consumer's scopes.
+func filterWorkflowsByScopes(ctx context.Context, ws sdk.Workflows) sdk.Workflows {
+	res := make(sdk.Workflows, 0, len(ws))
+	for i := range ws {
+		if isAllowedByScopes(ctx, ws[i].ProjectKey, ws[i].Name) {
+			res = append(res, ws[i])
+		}
+	}
+	return res
+}
+
 func (api *API) checkJobIDPermissions(ctx context.Context, jobID string, perm int, routeVars map[string]string) error {
 	ctx, end := telemetry.Span(ctx, "api.checkJobIDPermissions")
 	defer end()
@@ -75,6 +75,69 @@ func Test_authMiddleware_WithAuthConsumerDisabled(t *testing.T) {
 	assert.Error(t, err, "an error should be returned because the consumer should have been disabled")
 }
 
+func Test_authMiddleware_WithAuthConsumerAllowedCIDRs(t *testing.T) {
+	api, db, _ := newTestAPI(t)
+
+	g := assets.InsertGroup(t, db)
+	u, _ := assets.InsertLambdaUser(t, db, g)
+	localConsumer, err := authentication.LoadConsumerByTypeAndUserID(context.TODO(), db, sdk.ConsumerLocal, u.ID, authentication.LoadConsumerOptions.WithAuthentifiedUser)
+	require.NoError(t, err)
+
+	builtinConsumer, _, err := builtin.NewConsumer(context.TODO(), db, "builtin", "", localConsumer, []int64{g.ID},
+		sdk.NewAuthConsumerScopeDetails(sdk.AuthConsumerScopes...))
+	require.NoError(t, err)
+	builtinConsumer.AllowedCIDRs = []string{"10.0.0.0/8"}
+	require.NoError(t, authentication.UpdateConsumer(context.TODO(), db, builtinConsumer))
+	builtinSession, err := authentication.NewSession(context.TODO(), db, builtinConsumer, time.Second*5, false)
+	require.NoError(t, err)
+	jwt, err := authentication.NewSessionJWT(builtinSession)
+	require.NoError(t, err)
+
+	config := &service.HandlerConfig{CleanURL: "/my-route"}
+	Auth(true)(config)
+
+	req := assets.NewJWTAuthentifiedRequest(t, jwt, http.MethodGet, "", nil)
+	req.RemoteAddr = "10.1.2.3:4567"
+	w := httptest.NewRecorder()
+	_, err = api.authMiddleware(context.TODO(), w, req, config)
+	assert.NoError(t, err, "no error should be returned because the request comes from an allowed network")
+
+	req = assets.NewJWTAuthentifiedRequest(t, jwt, http.MethodGet, "", nil)
+	req.RemoteAddr = "192.168.1.2:4567"
+	w = httptest.NewRecorder()
+	_, err = api.authMiddleware(context.TODO(), w, req, config)
+	assert.Error(t, err, "an error should be returned because the request comes from a forbidden network")
+
+	// A second rejection for the same ip should not be stored again
+	req = assets.NewJWTAuthentifiedRequest(t, jwt, http.MethodGet, "", nil)
+	req.RemoteAddr = "192.168.1.2:4567"
+	w = httptest.NewRecorder()
+	_, err = api.authMiddleware(context.TODO(), w, req, config)
+	assert.Error(t, err)
+
+	rs, err := authentication.LoadConsumerIPRejectionsByConsumerID(context.TODO(), db, builtinConsumer.ID, 10)
+	require.NoError(t, err)
+	require.Len(t, rs, 1)
+	assert.Equal(t, "192.168.1.2", rs[0].IP)
+	assert.Equal(t, "/my-route", rs[0].Route)
+
+	// Only the ip added by the trusted proxy should be used, not the one set by the client
+	api.Config.Auth.ClientIPHeader = "X-Forwarded-For"
+	api.Config.Auth.ClientIPHops = 1
+	defer func() { api.Config.Auth.ClientIPHeader = "" }()
+	req = assets.NewJWTAuthentifiedRequest(t, jwt, http.MethodGet, "", nil)
+	req.RemoteAddr = "10.1.2.3:4567"
+	req.Header.Set("X-Forwarded-For", "10.0.0.1, 172.16.0.1")
+	w = httptest.NewRecorder()
+	_, err = api.authMiddleware(context.TODO(), w, req, config)
+	assert.Error(t, err, "an error should be returned because the left-most ip is set by the client")
+
+	api.Config.Auth.ClientIPHops = 2
+	w = httptest.NewRecorder()
+	_, err = api.authMiddleware(context.TODO(), w, req, config)
+	assert.NoError(t, err, "no error should be returned because the two proxies are trusted")
+}
+
 func Test_authMiddleware_WithoutAuth(t *testing.T) {
 	api, db, _ := newTestAPI(t)
 
@@ -20,8 +20,7 @@ func (api *API) tracingMiddleware(ctx context.Context, w http.ResponseWriter, re
 }
 
 func TracingPostMiddleware(ctx context.Context, w http.ResponseWriter, req *http.Request, rc *service.HandlerConfig) (context.Context, error) {
-	ctx, err := telemetry.End(ctx, w, req)
-	return ctx, err
+	return service.TracingPostMiddleware(ctx, w, req, rc)
 }
 
 func TracingMiddlewareFunc(s service.Service, db gorp.SqlExecutor, store cache.Store) service.Middleware {
@@ -35,6 +35,18 @@ func writeNoContentPostMiddleware(ctx context.Context, w http.ResponseWriter, re
 	return ctx, nil
 }
 
+// getRouteConfig returns the config of the route for given method and handler.
+func (r *Router) getRouteConfig(method string, handler service.HandlerFunc) *service.HandlerConfig {
+	p1 := reflect.ValueOf(handler()).Pointer()
+	for uri, routerConfig := range r.mapRouterConfigs {
+		rc := routerConfig.Config[method]
+		if rc != nil && strings.HasPrefix(uri, r.Prefix) && reflect.ValueOf(rc.Handler).Pointer() == p1 {
+			return rc
+		}
+	}
+	return nil
+}
+
 // GetRoute returns the routes given a handler
 func (r *Router) GetRoute(method string, handler service.HandlerFunc, vars map[string]string) string {
 	p1 := reflect.ValueOf(handler()).Pointer()
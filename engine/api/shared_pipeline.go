@@ -0,0 +1,182 @@
+package api
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/ovh/cds/engine/api/group"
+	"github.com/ovh/cds/engine/api/project"
+	"github.com/ovh/cds/engine/api/sharedpipeline"
+	"github.com/ovh/cds/engine/service"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// loadSharedPipelineGroup returns the group of a shared pipeline if the consumer can read it.
+func (api *API) loadSharedPipelineGroup(ctx context.Context, groupName string) (*sdk.Group, error) {
+	g, err := group.LoadByName(ctx, api.mustDB(), groupName, group.LoadOptions.WithMembers)
+	if err != nil {
+		return nil, err
+	}
+	if g.ID != group.SharedInfraGroup.ID && !isGroupMember(ctx, g) && !isMaintainer(ctx) {
+		return nil, sdk.WithStack(sdk.ErrForbidden)
+	}
+	return g, nil
+}
+
+func (api *API) getSharedPipelinesHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		groupIDs := append(getAPIConsumer(ctx).GetGroupIDs(), group.SharedInfraGroup.ID)
+		sps, err := sharedpipeline.LoadAllByGroupIDs(ctx, api.mustDB(), groupIDs)
+		if err != nil {
+			return err
+		}
+
+		gs, err := group.LoadAllByIDs(ctx, api.mustDB(), groupIDs)
+		if err != nil {
+			return err
+		}
+		for i := range sps {
+			for j := range gs {
+				if gs[j].ID == sps[i].GroupID {
+					sps[i].Group = &gs[j]
+					break
+				}
+			}
+		}
+
+		return service.WriteJSON(w, sps, http.StatusOK)
+	}
+}
+
+func (api *API) getSharedPipelineHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		name := vars["name"]
+
+		g, err := api.loadSharedPipelineGroup(ctx, vars["groupName"])
+		if err != nil {
+			return err
+		}
+
+		sps, err := sharedpipeline.LoadAllVersions(ctx, api.mustDB(), g.ID, name)
+		if err != nil {
+			return err
+		}
+		if len(sps) == 0 {
+			return sdk.NewErrorFrom(sdk.ErrNotFound, "cannot find shared pipeline %s/%s", g.Name, name)
+		}
+		for i := range sps {
+			sps[i].Group = g
+		}
+
+		return service.WriteJSON(w, sps, http.StatusOK)
+	}
+}
+
+func (api *API) getSharedPipelineUsageHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		name := vars["name"]
+
+		g, err := api.loadSharedPipelineGroup(ctx, vars["groupName"])
+		if err != nil {
+			return err
+		}
+
+		sp, err := sharedpipeline.LoadLatest(ctx, api.mustDB(), g.ID, name)
+		if err != nil {
+			return err
+		}
+
+		us, err := sharedpipeline.GetUsages(api.mustDB(), g.ID, sp.Name, sp.Version)
+		if err != nil {
+			return err
+		}
+
+		return service.WriteJSON(w, us, http.StatusOK)
+	}
+}
+
+func (api *API) postPipelinePublishHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		key := vars[permProjectKey]
+		pipelineName := vars["pipelineKey"]
+
+		var req sdk.SharedPipelinePublishRequest
+		if err := service.UnmarshalBody(r, &req); err != nil {
+			return err
+		}
+
+		grp, err := group.LoadByName(ctx, api.mustDB(), req.GroupName, group.LoadOptions.WithMembers)
+		if err != nil {
+			return err
+		}
+		if !isGroupAdmin(ctx, grp) && !isAdmin(ctx) {
+			return sdk.WithStack(sdk.ErrInvalidGroupAdmin)
+		}
+
+		proj, err := project.Load(ctx, api.mustDB(), key)
+		if err != nil {
+			return sdk.WrapError(err, "cannot load project %s", key)
+		}
+
+		tx, err := api.mustDB().Begin()
+		if err != nil {
+			return sdk.WithStack(err)
+		}
+		defer tx.Rollback() // nolint
+
+		sp, err := sharedpipeline.Publish(ctx, tx, *proj, pipelineName, *grp, req.Name, req.Description, getAPIConsumer(ctx))
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Commit(); err != nil {
+			return sdk.WithStack(err)
+		}
+
+		if err := sharedpipeline.NotifyNewVersion(ctx, api.mustDB(), *sp, getAPIConsumer(ctx)); err != nil {
+			log.Warning(ctx, "postPipelinePublishHandler> cannot notify usages of shared pipeline %s: %v", sp.Path(), err)
+		}
+
+		return service.WriteJSON(w, sp, http.StatusOK)
+	}
+}
+
+func (api *API) postUseSharedPipelineHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		key := vars[permProjectKey]
+
+		var req sdk.SharedPipelineUseRequest
+		if err := service.UnmarshalBody(r, &req); err != nil {
+			return err
+		}
+
+		proj, err := project.Load(ctx, api.mustDB(), key, project.LoadOptions.WithGroups)
+		if err != nil {
+			return sdk.WrapError(err, "cannot load project %s", key)
+		}
+
+		tx, err := api.mustDB().Begin()
+		if err != nil {
+			return sdk.WithStack(err)
+		}
+		defer tx.Rollback() // nolint
+
+		pip, err := sharedpipeline.Use(ctx, tx, api.Cache, *proj, req.Ref, req.Force, getAPIConsumer(ctx))
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Commit(); err != nil {
+			return sdk.WithStack(err)
+		}
+
+		return service.WriteJSON(w, pip, http.StatusOK)
+	}
+}
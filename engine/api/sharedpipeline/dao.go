@@ -0,0 +1,77 @@
+package sharedpipeline
+
+import (
+	"context"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/database/gorpmapping"
+	"github.com/ovh/cds/sdk"
+)
+
+func get(ctx context.Context, db gorp.SqlExecutor, q gorpmapping.Query) (*sdk.SharedPipeline, error) {
+	var sp sdk.SharedPipeline
+	found, err := gorpmapping.Get(ctx, db, q, &sp)
+	if err != nil {
+		return nil, sdk.WrapError(err, "cannot get shared pipeline")
+	}
+	if !found {
+		return nil, sdk.WithStack(sdk.ErrNotFound)
+	}
+	return &sp, nil
+}
+
+// LoadAllByGroupIDs returns the latest version of all shared pipelines for given groups.
+func LoadAllByGroupIDs(ctx context.Context, db gorp.SqlExecutor, groupIDs []int64) ([]sdk.SharedPipeline, error) {
+	query := gorpmapping.NewQuery(`
+		SELECT DISTINCT ON (group_id, name) * FROM shared_pipeline
+		WHERE group_id = ANY(string_to_array($1, ',')::int[])
+		ORDER BY group_id, name, version DESC
+	`).Args(gorpmapping.IDsToQueryString(groupIDs))
+	var sps []sdk.SharedPipeline
+	if err := gorpmapping.GetAll(ctx, db, query, &sps); err != nil {
+		return nil, sdk.WrapError(err, "cannot get shared pipelines")
+	}
+	return sps, nil
+}
+
+// LoadAllVersions returns all versions of a shared pipeline, latest first.
+func LoadAllVersions(ctx context.Context, db gorp.SqlExecutor, groupID int64, name string) ([]sdk.SharedPipeline, error) {
+	query := gorpmapping.NewQuery(`
+		SELECT * FROM shared_pipeline
+		WHERE group_id = $1 AND name = $2
+		ORDER BY version DESC
+	`).Args(groupID, name)
+	var sps []sdk.SharedPipeline
+	if err := gorpmapping.GetAll(ctx, db, query, &sps); err != nil {
+		return nil, sdk.WrapError(err, "cannot get shared pipeline versions")
+	}
+	return sps, nil
+}
+
+// LoadLatest returns the latest version of a shared pipeline.
+func LoadLatest(ctx context.Context, db gorp.SqlExecutor, groupID int64, name string) (*sdk.SharedPipeline, error) {
+	query := gorpmapping.NewQuery(`
+		SELECT * FROM shared_pipeline
+		WHERE group_id = $1 AND name = $2
+		ORDER BY version DESC LIMIT 1
+	`).Args(groupID, name)
+	return get(ctx, db, query)
+}
+
+// LoadByVersion returns given version of a shared pipeline, or the latest version if version is 0.
+func LoadByVersion(ctx context.Context, db gorp.SqlExecutor, groupID int64, name string, version int64) (*sdk.SharedPipeline, error) {
+	if version == 0 {
+		return LoadLatest(ctx, db, groupID, name)
+	}
+	query := gorpmapping.NewQuery(`
+		SELECT * FROM shared_pipeline
+		WHERE group_id = $1 AND name = $2 AND version = $3
+	`).Args(groupID, name, version)
+	return get(ctx, db, query)
+}
+
+// Insert a shared pipeline version.
+func Insert(db gorp.SqlExecutor, sp *sdk.SharedPipeline) error {
+	return sdk.WrapError(gorpmapping.Insert(db, sp), "unable to insert shared pipeline %s", sp.Name)
+}
@@ -0,0 +1,36 @@
+package sharedpipeline
+
+import (
+	"context"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/database/gorpmapping"
+	"github.com/ovh/cds/sdk"
+)
+
+// LoadInstanceByPipelineID returns the shared pipeline instance of a project pipeline.
+func LoadInstanceByPipelineID(ctx context.Context, db gorp.SqlExecutor, pipelineID int64) (*sdk.SharedPipelineInstance, error) {
+	query := gorpmapping.NewQuery(`
+		SELECT * FROM shared_pipeline_instance WHERE pipeline_id = $1
+	`).Args(pipelineID)
+	var i sdk.SharedPipelineInstance
+	found, err := gorpmapping.Get(ctx, db, query, &i)
+	if err != nil {
+		return nil, sdk.WrapError(err, "cannot get shared pipeline instance")
+	}
+	if !found {
+		return nil, sdk.WithStack(sdk.ErrNotFound)
+	}
+	return &i, nil
+}
+
+// InsertInstance a shared pipeline instance.
+func InsertInstance(db gorp.SqlExecutor, i *sdk.SharedPipelineInstance) error {
+	return sdk.WrapError(gorpmapping.Insert(db, i), "unable to insert shared pipeline instance")
+}
+
+// UpdateInstance a shared pipeline instance.
+func UpdateInstance(db gorp.SqlExecutor, i *sdk.SharedPipelineInstance) error {
+	return sdk.WrapError(gorpmapping.Update(db, i), "unable to update shared pipeline instance %d", i.ID)
+}
@@ -0,0 +1,13 @@
+package sharedpipeline
+
+import (
+	"github.com/ovh/cds/engine/api/database/gorpmapping"
+	"github.com/ovh/cds/sdk"
+)
+
+func init() {
+	gorpmapping.Register(
+		gorpmapping.New(sdk.SharedPipeline{}, "shared_pipeline", true, "id"),
+		gorpmapping.New(sdk.SharedPipelineInstance{}, "shared_pipeline_instance", true, "id"),
+	)
+}
@@ -0,0 +1,225 @@
+package sharedpipeline
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/event"
+	"github.com/ovh/cds/engine/api/group"
+	"github.com/ovh/cds/engine/api/pipeline"
+	"github.com/ovh/cds/engine/cache"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/exportentities"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// Publish a new version of a project pipeline as a shared pipeline of given group.
+func Publish(ctx context.Context, db gorp.SqlExecutor, proj sdk.Project, pipName string, grp sdk.Group, name, description string, u sdk.Identifiable) (*sdk.SharedPipeline, error) {
+	if name == "" {
+		name = pipName
+	}
+	if !sdk.NamePatternRegex.MatchString(name) {
+		return nil, sdk.NewErrorFrom(sdk.ErrWrongRequest, "invalid shared pipeline name, should match %s", sdk.NamePattern)
+	}
+
+	pip, err := pipeline.LoadPipeline(ctx, db, proj.Key, pipName, true)
+	if err != nil {
+		return nil, sdk.WrapError(err, "cannot load pipeline %s", pipName)
+	}
+
+	// A pipeline created from a shared pipeline can't be published again
+	if _, err := LoadInstanceByPipelineID(ctx, db, pip.ID); err == nil {
+		return nil, sdk.NewErrorFrom(sdk.ErrForbidden, "pipeline %s was created from a shared pipeline", pipName)
+	} else if !sdk.ErrorIs(err, sdk.ErrNotFound) {
+		return nil, err
+	}
+
+	e := exportentities.NewPipelineV1(*pip)
+	e.Name = name
+	btes, err := exportentities.Marshal(e, exportentities.FormatYAML)
+	if err != nil {
+		return nil, err
+	}
+
+	latest, err := LoadLatest(ctx, db, grp.ID, name)
+	if err != nil && !sdk.ErrorIs(err, sdk.ErrNotFound) {
+		return nil, err
+	}
+	var version int64 = 1
+	if latest != nil {
+		version = latest.Version + 1
+	}
+
+	sp := sdk.SharedPipeline{
+		GroupID:          grp.ID,
+		Name:             name,
+		Version:          version,
+		Description:      description,
+		Parameters:       pip.Parameter,
+		Value:            string(btes),
+		FromProjectKey:   proj.Key,
+		FromPipelineName: pip.Name,
+		Author:           u.GetUsername(),
+		Created:          time.Now(),
+	}
+	// The version is unique for the shared pipeline, one of two concurrent publications fails
+	if err := Insert(db, &sp); err != nil {
+		if sdk.ErrorIs(err, sdk.ErrConflictData) {
+			return nil, sdk.NewErrorFrom(sdk.ErrConflictData, "version %d of shared pipeline %s was published concurrently, please retry", version, name)
+		}
+		return nil, err
+	}
+	sp.Group = &grp
+
+	return &sp, nil
+}
+
+// NotifyNewVersion publishes an event for all project pipelines created from an older version of the shared pipeline.
+func NotifyNewVersion(ctx context.Context, db gorp.SqlExecutor, sp sdk.SharedPipeline, u sdk.Identifiable) error {
+	us, err := GetUsages(db, sp.GroupID, sp.Name, sp.Version)
+	if err != nil {
+		return err
+	}
+	notified := make(map[int64]struct{})
+	for _, usage := range us {
+		if _, ok := notified[usage.PipelineID]; ok || !usage.Outdated {
+			continue
+		}
+		notified[usage.PipelineID] = struct{}{}
+		event.PublishPipelineSharedVersion(ctx, usage.ProjectKey, usage.PipelineName, sp, usage.Version, u)
+	}
+	return nil
+}
+
+// hasLocalChanges returns true if the project pipeline was modified since it was created or upgraded from the shared
+// pipeline version of the instance.
+func hasLocalChanges(ctx context.Context, db gorp.SqlExecutor, instance sdk.SharedPipelineInstance) (bool, error) {
+	previous, err := LoadByVersion(ctx, db, instance.GroupID, instance.Name, instance.Version)
+	if sdk.ErrorIs(err, sdk.ErrNotFound) {
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	// Both pipelines are marshaled the same way so that only the content is compared
+	epip, err := exportentities.ParsePipeline(exportentities.FormatYAML, []byte(previous.Value))
+	if err != nil {
+		return false, err
+	}
+	expected, err := exportentities.Marshal(epip, exportentities.FormatYAML)
+	if err != nil {
+		return false, err
+	}
+
+	pip, err := pipeline.LoadPipelineByID(ctx, db, instance.PipelineID, true)
+	if err != nil {
+		return false, err
+	}
+	e := exportentities.NewPipelineV1(*pip)
+	e.Name = instance.Name
+	current, err := exportentities.Marshal(e, exportentities.FormatYAML)
+	if err != nil {
+		return false, err
+	}
+
+	return string(expected) != string(current), nil
+}
+
+// Use creates or upgrades the project pipeline for given shared pipeline reference (group/name@version). The changes
+// made to the project pipeline since it was created from the shared pipeline are only overwritten if force is true.
+func Use(ctx context.Context, db gorp.SqlExecutor, store cache.Store, proj sdk.Project, ref string, force bool, u sdk.Identifiable) (*sdk.Pipeline, error) {
+	groupName, name, version, err := sdk.ParseSharedPipelineRef(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	grp, err := group.LoadByName(ctx, db, groupName)
+	if err != nil {
+		return nil, sdk.NewErrorWithStack(err, sdk.NewErrorFrom(sdk.ErrNotFound, "cannot find shared pipeline %s", ref))
+	}
+
+	// Like actions, shared pipelines can be used by projects of the same group or are shared by shared.infra
+	allowed := grp.ID == group.SharedInfraGroup.ID
+	for i := range proj.ProjectGroups {
+		if proj.ProjectGroups[i].Group.ID == grp.ID {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		return nil, sdk.NewErrorFrom(sdk.ErrForbidden, "group %s is not a group of project %s", grp.Name, proj.Key)
+	}
+
+	sp, err := LoadByVersion(ctx, db, grp.ID, name, version)
+	if err != nil {
+		return nil, sdk.NewErrorWithStack(err, sdk.NewErrorFrom(sdk.ErrNotFound, "cannot find shared pipeline %s", ref))
+	}
+
+	epip, err := exportentities.ParsePipeline(exportentities.FormatYAML, []byte(sp.Value))
+	if err != nil {
+		return nil, err
+	}
+
+	// Check that the existing pipeline with the same name was created from this shared pipeline
+	var instance *sdk.SharedPipelineInstance
+	exist, err := pipeline.ExistPipeline(db, proj.ID, sp.Name)
+	if err != nil {
+		return nil, err
+	}
+	if exist {
+		pip, err := pipeline.LoadPipeline(ctx, db, proj.Key, sp.Name, false)
+		if err != nil {
+			return nil, err
+		}
+		instance, err = LoadInstanceByPipelineID(ctx, db, pip.ID)
+		if err != nil && !sdk.ErrorIs(err, sdk.ErrNotFound) {
+			return nil, err
+		}
+		if instance == nil || instance.GroupID != grp.ID || instance.Name != sp.Name {
+			return nil, sdk.NewErrorFrom(sdk.ErrPipelineAlreadyExists, "pipeline %s already exists in project %s and was not created from %s/%s", sp.Name, proj.Key, grp.Name, sp.Name)
+		}
+		if instance.Version == sp.Version {
+			return pip, nil
+		}
+		if !force {
+			changed, err := hasLocalChanges(ctx, db, *instance)
+			if err != nil {
+				return nil, err
+			}
+			if changed {
+				return nil, sdk.NewErrorFrom(sdk.ErrConflictData, "pipeline %s of project %s was modified since it was created from %s/%s@%d, force the upgrade to overwrite the changes", sp.Name, proj.Key, grp.Name, sp.Name, instance.Version)
+			}
+		}
+	}
+
+	pip, _, err := pipeline.ParseAndImport(ctx, db, store, proj, epip, u, pipeline.ImportOptions{Force: true})
+	if err != nil {
+		return nil, sdk.WrapError(err, "cannot import shared pipeline %s/%s@%d", grp.Name, sp.Name, sp.Version)
+	}
+
+	now := time.Now()
+	if instance == nil {
+		instance = &sdk.SharedPipelineInstance{
+			GroupID:    grp.ID,
+			Name:       sp.Name,
+			ProjectID:  proj.ID,
+			PipelineID: pip.ID,
+			Created:    now,
+		}
+	}
+	instance.Version = sp.Version
+	instance.Updated = now
+	if instance.ID == 0 {
+		err = InsertInstance(db, instance)
+	} else {
+		err = UpdateInstance(db, instance)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	log.Info(ctx, "sharedpipeline.Use> pipeline %s of project %s now uses %s/%s@%d", pip.Name, proj.Key, grp.Name, sp.Name, sp.Version)
+	return pip, nil
+}
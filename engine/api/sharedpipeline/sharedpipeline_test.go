@@ -0,0 +1,136 @@
+package sharedpipeline_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ovh/cds/engine/api/bootstrap"
+	"github.com/ovh/cds/engine/api/pipeline"
+	"github.com/ovh/cds/engine/api/sharedpipeline"
+	"github.com/ovh/cds/engine/api/test"
+	"github.com/ovh/cds/engine/api/test/assets"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/exportentities"
+)
+
+const pipelineYAML = `version: v1.0
+name: build
+parameters:
+  param1:
+    type: string
+    default: value1
+jobs:
+- job: compile
+  steps:
+  - script:
+    - echo build
+`
+
+func TestPublishAndUse(t *testing.T) {
+	db, cache := test.SetupPG(t, bootstrap.InitiliazeDB)
+	u := &sdk.AuthentifiedUser{Username: sdk.RandomString(10)}
+
+	// Both projects have the same name so they share the same group
+	name := sdk.RandomString(10)
+	key1, key2 := sdk.RandomString(10), sdk.RandomString(10)
+	proj1 := assets.InsertTestProject(t, db, cache, key1, name)
+	proj2 := assets.InsertTestProject(t, db, cache, key2, name)
+	grp := proj1.ProjectGroups[0].Group
+
+	epip, err := exportentities.ParsePipeline(exportentities.FormatYAML, []byte(pipelineYAML))
+	require.NoError(t, err)
+	_, _, err = pipeline.ParseAndImport(context.TODO(), db, cache, *proj1, epip, u, pipeline.ImportOptions{})
+	require.NoError(t, err)
+
+	sp, err := sharedpipeline.Publish(context.TODO(), db, *proj1, "build", grp, "", "my shared pipeline", u)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), sp.Version)
+	assert.Equal(t, "build", sp.Name)
+	assert.Equal(t, key1, sp.FromProjectKey)
+	assert.Equal(t, u.Username, sp.Author)
+
+	_, err = sharedpipeline.Publish(context.TODO(), db, *proj1, "build", grp, "invalid name", "", u)
+	require.Error(t, err)
+
+	// A version can't be published twice
+	dup := *sp
+	dup.ID = 0
+	require.True(t, sdk.ErrorIs(sharedpipeline.Insert(db, &dup), sdk.ErrConflictData))
+
+	ref := grp.Name + "/build"
+	pip, err := sharedpipeline.Use(context.TODO(), db, cache, *proj2, ref, false, u)
+	require.NoError(t, err)
+	assert.Equal(t, "build", pip.Name)
+
+	// A pipeline created from a shared pipeline can't be published again
+	_, err = sharedpipeline.Publish(context.TODO(), db, *proj2, "build", grp, "other", "", u)
+	require.True(t, sdk.ErrorIs(err, sdk.ErrForbidden))
+
+	// Using the same version again does nothing
+	pip2, err := sharedpipeline.Use(context.TODO(), db, cache, *proj2, ref+"@1", false, u)
+	require.NoError(t, err)
+	assert.Equal(t, pip.ID, pip2.ID)
+
+	us, err := sharedpipeline.GetUsages(db, grp.ID, "build", 1)
+	require.NoError(t, err)
+	require.Len(t, us, 1)
+	assert.Equal(t, key2, us[0].ProjectKey)
+	assert.Equal(t, pip.ID, us[0].PipelineID)
+	assert.Equal(t, int64(1), us[0].Version)
+	assert.False(t, us[0].Outdated)
+
+	// A new version makes the usage outdated, it is upgraded if the project pipeline was not modified
+	sp, err = sharedpipeline.Publish(context.TODO(), db, *proj1, "build", grp, "", "", u)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), sp.Version)
+
+	us, err = sharedpipeline.GetUsages(db, grp.ID, "build", sp.Version)
+	require.NoError(t, err)
+	require.Len(t, us, 1)
+	assert.Equal(t, int64(2), us[0].LatestVersion)
+	assert.True(t, us[0].Outdated)
+
+	_, err = sharedpipeline.Use(context.TODO(), db, cache, *proj2, ref, false, u)
+	require.NoError(t, err)
+
+	// Local changes of the project pipeline are only overwritten if forced
+	sp, err = sharedpipeline.Publish(context.TODO(), db, *proj1, "build", grp, "", "", u)
+	require.NoError(t, err)
+	require.NoError(t, pipeline.InsertStage(db, &sdk.Stage{Name: "local", PipelineID: pip.ID, BuildOrder: 10, Enabled: true}))
+
+	_, err = sharedpipeline.Use(context.TODO(), db, cache, *proj2, ref, false, u)
+	require.True(t, sdk.ErrorIs(err, sdk.ErrConflictData))
+
+	_, err = sharedpipeline.Use(context.TODO(), db, cache, *proj2, ref, true, u)
+	require.NoError(t, err)
+
+	us, err = sharedpipeline.GetUsages(db, grp.ID, "build", sp.Version)
+	require.NoError(t, err)
+	require.Len(t, us, 1)
+	assert.Equal(t, int64(3), us[0].Version)
+	assert.False(t, us[0].Outdated)
+}
+
+func TestUseForbiddenGroup(t *testing.T) {
+	db, cache := test.SetupPG(t, bootstrap.InitiliazeDB)
+	u := &sdk.AuthentifiedUser{Username: sdk.RandomString(10)}
+
+	key1, key2 := sdk.RandomString(10), sdk.RandomString(10)
+	proj1 := assets.InsertTestProject(t, db, cache, key1, key1)
+	proj2 := assets.InsertTestProject(t, db, cache, key2, key2)
+	grp := proj1.ProjectGroups[0].Group
+
+	epip, err := exportentities.ParsePipeline(exportentities.FormatYAML, []byte(pipelineYAML))
+	require.NoError(t, err)
+	_, _, err = pipeline.ParseAndImport(context.TODO(), db, cache, *proj1, epip, u, pipeline.ImportOptions{})
+	require.NoError(t, err)
+	_, err = sharedpipeline.Publish(context.TODO(), db, *proj1, "build", grp, "", "", u)
+	require.NoError(t, err)
+
+	// The group of the shared pipeline is not a group of the project
+	_, err = sharedpipeline.Use(context.TODO(), db, cache, *proj2, grp.Name+"/build", false, u)
+	require.True(t, sdk.ErrorIs(err, sdk.ErrForbidden))
+}
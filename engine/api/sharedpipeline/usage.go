@@ -0,0 +1,48 @@
+package sharedpipeline
+
+import (
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// GetUsages returns the project pipelines created from a shared pipeline, with the workflows that use them.
+func GetUsages(db gorp.SqlExecutor, groupID int64, name string, latestVersion int64) ([]sdk.UsageSharedPipeline, error) {
+	rows, err := db.Query(`
+		SELECT DISTINCT
+			project.id, project.projectKey, project.name,
+			pipeline.id, pipeline.name,
+			COALESCE(workflow.id, 0), COALESCE(workflow.name, ''),
+			shared_pipeline_instance.version
+		FROM shared_pipeline_instance
+		JOIN project ON project.id = shared_pipeline_instance.project_id
+		JOIN pipeline ON pipeline.id = shared_pipeline_instance.pipeline_id
+		LEFT JOIN w_node_context ON w_node_context.pipeline_id = pipeline.id
+		LEFT JOIN w_node ON w_node.id = w_node_context.node_id
+		LEFT JOIN workflow ON workflow.id = w_node.workflow_id
+		WHERE shared_pipeline_instance.group_id = $1 AND shared_pipeline_instance.name = $2
+		ORDER BY project.projectKey, pipeline.name, COALESCE(workflow.name, '')
+	`, groupID, name)
+	if err != nil {
+		return nil, sdk.WrapError(err, "cannot load usages for shared pipeline %s", name)
+	}
+	defer rows.Close()
+
+	us := []sdk.UsageSharedPipeline{}
+	for rows.Next() {
+		var u sdk.UsageSharedPipeline
+		if err := rows.Scan(
+			&u.ProjectID, &u.ProjectKey, &u.ProjectName,
+			&u.PipelineID, &u.PipelineName,
+			&u.WorkflowID, &u.WorkflowName,
+			&u.Version,
+		); err != nil {
+			return nil, sdk.WrapError(err, "cannot scan sql rows")
+		}
+		u.LatestVersion = latestVersion
+		u.Outdated = u.Version < latestVersion
+		us = append(us, u)
+	}
+
+	return us, nil
+}
@@ -41,8 +41,14 @@ func (api *API) Status(ctx context.Context) sdk.MonitoringStatus {
 	m.Lines = append(m.Lines, api.SharedStorage.Status(ctx))
 	m.Lines = append(m.Lines, mail.Status(ctx))
 	m.Lines = append(m.Lines, api.DBConnectionFactory.Status(ctx))
+	if api.DBConnectionFactory.HasReplica() {
+		m.Lines = append(m.Lines, api.DBConnectionFactory.ReplicaStatus(ctx))
+	}
 	m.Lines = append(m.Lines, workermodel.Status(api.mustDB()))
 	m.Lines = append(m.Lines, migrate.Status(api.mustDB()))
+	if api.LeaderElector != nil {
+		m.Lines = append(m.Lines, api.LeaderElector.Status(ctx)...)
+	}
 
 	return m
 }
@@ -223,6 +229,19 @@ func (api *API) initMetrics(ctx context.Context) error {
 		fmt.Sprintf("cds/cds-api/%s/database_conn", api.Name()),
 		"number database connections",
 		stats.UnitDimensionless)
+	api.Metrics.AuditAPICallsDropped = stats.Int64(
+		fmt.Sprintf("cds/cds-api/%s/audit_api_calls_dropped", api.Name()),
+		"number of API call audits dropped because the audit buffer is full",
+		stats.UnitDimensionless)
+
+	api.Metrics.DORADeploymentFrequency = stats.Float64("cds/cds-api/dora_deployment_frequency",
+		"number of successful deployments per day on the last 30 days", stats.UnitDimensionless)
+	api.Metrics.DORALeadTimeForChanges = stats.Float64("cds/cds-api/dora_lead_time_for_changes",
+		"median time between a commit and its successful deployment on the last 30 days", "s")
+	api.Metrics.DORAChangeFailureRate = stats.Float64("cds/cds-api/dora_change_failure_rate",
+		"ratio of failed deployments on the last 30 days", stats.UnitDimensionless)
+	api.Metrics.DORATimeToRestore = stats.Float64("cds/cds-api/dora_time_to_restore",
+		"median time between a failed deployment and the next successful one on the last 30 days", "s")
 
 	tagRange, _ = tag.NewKey("range")
 	tagStatus, _ = tag.NewKey("status")
@@ -231,6 +250,7 @@ func (api *API) initMetrics(ctx context.Context) error {
 	tagServiceName := telemetry.MustNewKey(telemetry.TagServiceName)
 	tagsRange := []tag.Key{tagRange, tagStatus}
 	tagsService = []tag.Key{tagServiceName, tagServiceType}
+	tagsDORA := []tag.Key{telemetry.MustNewKey(telemetry.TagProjectKey), telemetry.MustNewKey("application"), telemetry.MustNewKey("environment")}
 
 	err := telemetry.RegisterView(ctx,
 		telemetry.NewViewLast("cds/nb_users", api.Metrics.nbUsers, nil),
@@ -250,6 +270,11 @@ func (api *API) initMetrics(ctx context.Context) error {
 		telemetry.NewViewLast("cds/workflow_runs_mark_to_delete", api.Metrics.WorkflowRunsMarkToDelete, tagsService),
 		telemetry.NewViewCount("cds/workflow_runs_deleted", api.Metrics.WorkflowRunsDeleted, tagsService),
 		telemetry.NewViewLast("cds/database_conn", api.Metrics.DatabaseConns, tagsService),
+		telemetry.NewViewCount("cds/audit_api_calls_dropped", api.Metrics.AuditAPICallsDropped, tagsService),
+		telemetry.NewViewLastFloat64("cds/dora_deployment_frequency", api.Metrics.DORADeploymentFrequency, tagsDORA),
+		telemetry.NewViewLastFloat64("cds/dora_lead_time_for_changes_seconds", api.Metrics.DORALeadTimeForChanges, tagsDORA),
+		telemetry.NewViewLastFloat64("cds/dora_change_failure_rate", api.Metrics.DORAChangeFailureRate, tagsDORA),
+		telemetry.NewViewLastFloat64("cds/dora_time_to_restore_seconds", api.Metrics.DORATimeToRestore, tagsDORA),
 	)
 
 	api.computeMetrics(ctx)
@@ -315,8 +340,11 @@ func (api *API) computeMetrics(ctx context.Context) {
 	})
 }
 
+// metricsReplicaStaleness is the replication lag tolerated when metrics are computed from the database replica.
+const metricsReplicaStaleness = 30 * time.Second
+
 func (api *API) countMetric(ctx context.Context, v *stats.Int64Measure, query string) {
-	n, err := api.mustDB().SelectInt(query)
+	n, err := api.mustDBReplica(metricsReplicaStaleness).SelectInt(query)
 	if err != nil {
 		log.Warning(ctx, "metrics>Errors while fetching count %s: %v", query, err)
 	}
@@ -324,7 +352,7 @@ func (api *API) countMetric(ctx context.Context, v *stats.Int64Measure, query st
 }
 
 func (api *API) countMetricRange(ctx context.Context, status string, timerange string, v *stats.Int64Measure, query string, args ...interface{}) {
-	n, err := api.mustDB().SelectInt(query, args...)
+	n, err := api.mustDBReplica(metricsReplicaStaleness).SelectInt(query, args...)
 	if err != nil {
 		log.Warning(ctx, "metrics>Errors while fetching count range %s: %v", query, err)
 	}
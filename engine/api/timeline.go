@@ -29,7 +29,7 @@ func (api *API) getTimelineHandler() service.Handler {
 		// Get index of the first element to return
 		currentItem := FormInt(r, "currentItem")
 
-		// Get workflow to mute
+		// Get workflow to mute, the timeline filter is read on the primary to get the last changes of the user
 		timelineFilter, err := user.LoadTimelineFilter(api.mustDB(), consumer.AuthentifiedUser.ID)
 		if err != nil {
 			return sdk.WrapError(err, "unable to load timeline filter")
@@ -41,17 +41,19 @@ func (api *API) getTimelineHandler() service.Handler {
 			}
 		}
 
+		// Projects and workflows are read on the replica
 		var ps []sdk.Project
+		db := api.mustDBReplica(workflow.SearchReplicaStaleness)
 		if isMaintainer(ctx) {
-			ps, err = project.LoadAll(ctx, api.mustDB(), api.Cache)
+			ps, err = project.LoadAll(ctx, db, api.Cache)
 		} else {
-			ps, err = project.LoadAllByGroupIDs(ctx, api.mustDB(), api.Cache, consumer.GetGroupIDs())
+			ps, err = project.LoadAllByGroupIDs(ctx, db, api.Cache, consumer.GetGroupIDs())
 		}
 		if err != nil {
 			return err
 		}
 
-		ws, err := workflow.LoadAllNamesByProjectIDs(ctx, api.mustDB(), sdk.ProjectsToIDs(ps))
+		ws, err := workflow.LoadAllNamesByProjectIDs(ctx, db, sdk.ProjectsToIDs(ps))
 		if err != nil {
 			return err
 		}
@@ -0,0 +1,89 @@
+package usage
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/database/gorpmapping"
+	"github.com/ovh/cds/sdk"
+)
+
+type dbJobUsage sdk.JobUsage
+
+func init() {
+	gorpmapping.Register(gorpmapping.New(dbJobUsage{}, "workflow_node_run_job_usage", true, "id"))
+}
+
+// InsertJobUsage in database, a previous usage for the same job run is replaced.
+func InsertJobUsage(db gorp.SqlExecutor, u *sdk.JobUsage) error {
+	if _, err := db.Exec("DELETE FROM workflow_node_run_job_usage WHERE workflow_node_run_job_id = $1", u.WorkflowNodeJobRunID); err != nil {
+		return sdk.WrapError(err, "unable to delete usage for job run %d", u.WorkflowNodeJobRunID)
+	}
+	dbu := dbJobUsage(*u)
+	if err := gorpmapping.Insert(db, &dbu); err != nil {
+		return sdk.WrapError(err, "unable to insert usage for job run %d", u.WorkflowNodeJobRunID)
+	}
+	u.ID = dbu.ID
+	return nil
+}
+
+// LoadSummaries returns the job usages that match given filter aggregated by day, project, worker model group and
+// worker model, most recent day first.
+func LoadSummaries(ctx context.Context, db gorp.SqlExecutor, filter sdk.JobUsageFilter) ([]sdk.JobUsageSummary, error) {
+	var clauses = []string{"true"}
+	var args []interface{}
+	addClause := func(clause string, arg interface{}) {
+		args = append(args, arg)
+		clauses = append(clauses, fmt.Sprintf(clause, len(args)))
+	}
+	if !filter.Since.IsZero() {
+		addClause("workflow_node_run_job_usage.done >= $%d", filter.Since)
+	}
+	if !filter.Until.IsZero() {
+		addClause("workflow_node_run_job_usage.done <= $%d", filter.Until)
+	}
+	if filter.ProjectKey != "" {
+		addClause("project.projectkey = $%d", filter.ProjectKey)
+	}
+	if filter.GroupName != "" {
+		addClause("workflow_node_run_job_usage.model_group_name = $%d", filter.GroupName)
+	}
+	if filter.ModelName != "" {
+		addClause("workflow_node_run_job_usage.model_name = $%d", filter.ModelName)
+	}
+
+	query := `
+		SELECT date_trunc('day', workflow_node_run_job_usage.done) AS day, project.projectkey,
+			COALESCE(workflow_node_run_job_usage.model_group_name, '') AS model_group_name,
+			COALESCE(workflow_node_run_job_usage.model_name, '') AS model_name,
+			COUNT(workflow_node_run_job_usage.id) AS jobs,
+			COALESCE(SUM(GREATEST(EXTRACT(EPOCH FROM workflow_node_run_job_usage.done - workflow_node_run_job_usage.start), 0)), 0) AS wall_time,
+			COALESCE(SUM(GREATEST(EXTRACT(EPOCH FROM workflow_node_run_job_usage.start - workflow_node_run_job_usage.queued), 0)), 0) AS queue_wait_time,
+			COALESCE(SUM(workflow_node_run_job_usage.cpu_time), 0) AS cpu_time,
+			COALESCE(MAX(workflow_node_run_job_usage.memory_peak), 0) AS memory_peak
+		FROM workflow_node_run_job_usage
+		JOIN project ON project.id = workflow_node_run_job_usage.project_id
+		WHERE ` + strings.Join(clauses, " AND ") + `
+		GROUP BY 1, 2, 3, 4
+		ORDER BY 1 DESC, 2, 3, 4`
+
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, sdk.WrapError(err, "cannot load job usages")
+	}
+	defer rows.Close() // nolint
+
+	var res []sdk.JobUsageSummary
+	for rows.Next() {
+		var s sdk.JobUsageSummary
+		if err := rows.Scan(&s.Day, &s.ProjectKey, &s.ModelGroupName, &s.ModelName, &s.Jobs, &s.WallTime,
+			&s.QueueWaitTime, &s.CPUTime, &s.MemoryPeak); err != nil {
+			return nil, sdk.WrapError(err, "cannot scan job usage")
+		}
+		res = append(res, s)
+	}
+	return res, sdk.WithStack(rows.Err())
+}
@@ -0,0 +1,68 @@
+package usage_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ovh/cds/engine/api/bootstrap"
+	"github.com/ovh/cds/engine/api/test"
+	"github.com/ovh/cds/engine/api/test/assets"
+	"github.com/ovh/cds/engine/api/usage"
+	"github.com/ovh/cds/sdk"
+)
+
+func TestLoadSummaries(t *testing.T) {
+	db, cache := test.SetupPG(t, bootstrap.InitiliazeDB)
+
+	key := sdk.RandomString(10)
+	proj := assets.InsertTestProject(t, db, cache, key, key)
+
+	day := time.Now().Add(-24 * time.Hour).Truncate(24 * time.Hour).Add(12 * time.Hour)
+	cpu := func(f float64) *float64 { return &f }
+	mem := func(i int64) *int64 { return &i }
+	usages := []sdk.JobUsage{
+		{ModelName: "model-a", ModelGroupName: "group", Queued: day, Start: day.Add(time.Minute), Done: day.Add(11 * time.Minute),
+			CPUTime: cpu(30), MemoryPeak: mem(1024)},
+		{ModelName: "model-a", ModelGroupName: "group", Queued: day, Start: day.Add(3 * time.Minute), Done: day.Add(8 * time.Minute),
+			CPUTime: cpu(10), MemoryPeak: mem(2048)},
+		// Stopped before a worker took it
+		{ModelName: "model-a", ModelGroupName: "group", Status: sdk.StatusStopped, Queued: day, Start: day.Add(2 * time.Minute), Done: day.Add(2 * time.Minute)},
+		{ModelName: "model-b", ModelGroupName: "group", Queued: day, Start: day, Done: day.Add(time.Minute)},
+		{ModelName: "model-a", ModelGroupName: "group", Queued: day.Add(-48 * time.Hour), Start: day.Add(-48 * time.Hour), Done: day.Add(-48 * time.Hour)},
+	}
+	for i := range usages {
+		u := &usages[i]
+		u.ProjectID = proj.ID
+		u.WorkflowNodeJobRunID = time.Now().UnixNano()
+		u.WorkflowNodeRunID = u.WorkflowNodeJobRunID
+		u.JobName = "build"
+		if u.Status == "" {
+			u.Status = sdk.StatusSuccess
+		}
+		require.NoError(t, usage.InsertJobUsage(db, u))
+	}
+
+	ss, err := usage.LoadSummaries(context.TODO(), db, sdk.JobUsageFilter{ProjectKey: proj.Key, ModelName: "model-a", Since: day.Add(-time.Hour)})
+	require.NoError(t, err)
+	require.Len(t, ss, 1)
+	s := ss[0]
+	assert.Equal(t, proj.Key, s.ProjectKey)
+	assert.Equal(t, "group", s.ModelGroupName)
+	assert.Equal(t, "model-a", s.ModelName)
+	assert.Equal(t, int64(3), s.Jobs)
+	assert.InDelta(t, 15*60, s.WallTime, 0.001)
+	assert.InDelta(t, 6*60, s.QueueWaitTime, 0.001)
+	assert.InDelta(t, 40, s.CPUTime, 0.001)
+	assert.Equal(t, int64(2048), s.MemoryPeak)
+
+	ss, err = usage.LoadSummaries(context.TODO(), db, sdk.JobUsageFilter{ProjectKey: proj.Key})
+	require.NoError(t, err)
+	require.Len(t, ss, 3)
+	assert.Equal(t, "model-a", ss[0].ModelName)
+	assert.Equal(t, "model-b", ss[1].ModelName)
+	assert.True(t, ss[2].Day.Before(ss[0].Day))
+}
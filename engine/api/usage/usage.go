@@ -0,0 +1,46 @@
+package usage
+
+import (
+	"time"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// NewJobUsage returns the usage of given terminated job run. Worker model can be nil for jobs that were not run
+// by a worker model, resource usage can be nil if not reported by the worker.
+func NewJobUsage(projectID int64, job sdk.WorkflowNodeJobRun, model *sdk.Model, res *sdk.JobRunUsage) sdk.JobUsage {
+	u := sdk.JobUsage{
+		WorkflowNodeJobRunID: job.ID,
+		WorkflowNodeRunID:    job.WorkflowNodeRunID,
+		ProjectID:            projectID,
+		JobName:              job.Job.Action.Name,
+		Status:               job.Status,
+		ModelName:            job.Model,
+		ModelType:            job.ModelType,
+		HatcheryName:         job.HatcheryName,
+		WorkerName:           job.WorkerName,
+		Queued:               job.Queued,
+		Start:                job.Start,
+		Done:                 job.Done,
+	}
+	if model != nil {
+		u.ModelName = model.Name
+		u.ModelType = model.Type
+		if model.Group != nil {
+			u.ModelGroupName = model.Group.Name
+		}
+	}
+	if u.Done.IsZero() {
+		u.Done = time.Now()
+	}
+	// A job that was never started has no wall time but waited in queue until its end
+	if u.Start.IsZero() {
+		u.Start = u.Done
+	}
+	if res != nil {
+		cpuTime, memoryPeak := res.CPUTime, res.MemoryPeak
+		u.CPUTime = &cpuTime
+		u.MemoryPeak = &memoryPeak
+	}
+	return u
+}
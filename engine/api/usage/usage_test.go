@@ -0,0 +1,53 @@
+package usage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func TestNewJobUsage(t *testing.T) {
+	now := time.Now()
+	job := sdk.WorkflowNodeJobRun{
+		ID:                1,
+		WorkflowNodeRunID: 2,
+		Job:               sdk.ExecutedJob{Job: sdk.Job{Action: sdk.Action{Name: "build"}}},
+		Status:            sdk.StatusSuccess,
+		Queued:            now.Add(-time.Hour),
+		Start:             now.Add(-time.Minute),
+		Done:              now,
+		Model:             "my-model",
+		HatcheryName:      "my-hatchery",
+		WorkerName:        "my-worker",
+	}
+
+	u := NewJobUsage(3, job, &sdk.Model{Name: "my-model", Type: sdk.Docker, Group: &sdk.Group{Name: "my-group"}},
+		&sdk.JobRunUsage{CPUTime: 12.5, MemoryPeak: 1024})
+	assert.Equal(t, int64(1), u.WorkflowNodeJobRunID)
+	assert.Equal(t, int64(2), u.WorkflowNodeRunID)
+	assert.Equal(t, int64(3), u.ProjectID)
+	assert.Equal(t, "build", u.JobName)
+	assert.Equal(t, "my-model", u.ModelName)
+	assert.Equal(t, "my-group", u.ModelGroupName)
+	assert.Equal(t, sdk.Docker, u.ModelType)
+	assert.Equal(t, "my-hatchery", u.HatcheryName)
+	assert.Equal(t, "my-worker", u.WorkerName)
+	assert.Equal(t, job.Start, u.Start)
+	require.NotNil(t, u.CPUTime)
+	assert.Equal(t, 12.5, *u.CPUTime)
+	require.NotNil(t, u.MemoryPeak)
+	assert.Equal(t, int64(1024), *u.MemoryPeak)
+
+	// Job stopped before a worker took it
+	job.Start = time.Time{}
+	u = NewJobUsage(3, job, nil, nil)
+	assert.Equal(t, "my-model", u.ModelName)
+	assert.Empty(t, u.ModelGroupName)
+	assert.Equal(t, job.Done, u.Start)
+	assert.Nil(t, u.CPUTime)
+	assert.Nil(t, u.MemoryPeak)
+}
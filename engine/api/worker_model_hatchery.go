@@ -19,6 +19,10 @@ func (api *API) putBookWorkerModelHandler() service.Handler {
 			return sdk.WithStack(sdk.ErrForbidden)
 		}
 
+		if api.isDraining() {
+			return sdk.NewErrorFrom(sdk.ErrServiceUnavailable, "CDS maintenance drain in progress, no worker model can be registered")
+		}
+
 		vars := mux.Vars(r)
 
 		groupName := vars["permGroupName"]
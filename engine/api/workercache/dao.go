@@ -0,0 +1,153 @@
+package workercache
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/database/gorpmapping"
+	"github.com/ovh/cds/engine/api/objectstore"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+func init() {
+	gorpmapping.Register(gorpmapping.New(sdk.CacheEntry{}, "project_cache", true, "id"))
+}
+
+func get(ctx context.Context, db gorp.SqlExecutor, q gorpmapping.Query) (*sdk.CacheEntry, error) {
+	var e sdk.CacheEntry
+	found, err := gorpmapping.Get(ctx, db, q, &e)
+	if err != nil {
+		return nil, sdk.WrapError(err, "cannot get cache entry")
+	}
+	if !found {
+		return nil, sdk.WithStack(sdk.ErrNotFound)
+	}
+	return &e, nil
+}
+
+// LoadByTag returns a cache entry by its tag.
+func LoadByTag(ctx context.Context, db gorp.SqlExecutor, projectID int64, integrationName, tag string) (*sdk.CacheEntry, error) {
+	query := gorpmapping.NewQuery(`
+		SELECT * FROM project_cache
+		WHERE project_id = $1 AND integration_name = $2 AND tag = $3
+	`).Args(projectID, integrationName, tag)
+	return get(ctx, db, query)
+}
+
+// LoadByKey returns a cache entry by its key.
+func LoadByKey(ctx context.Context, db gorp.SqlExecutor, projectID int64, integrationName, key string) (*sdk.CacheEntry, error) {
+	query := gorpmapping.NewQuery(`
+		SELECT * FROM project_cache
+		WHERE project_id = $1 AND integration_name = $2 AND key = $3
+		ORDER BY created DESC LIMIT 1
+	`).Args(projectID, integrationName, key)
+	return get(ctx, db, query)
+}
+
+// LoadLatestByKeyPrefixes returns the most recent cache entry with a key that starts with
+// the first matching prefix, prefixes are checked in given order.
+func LoadLatestByKeyPrefixes(ctx context.Context, db gorp.SqlExecutor, projectID int64, integrationName string, prefixes []string) (*sdk.CacheEntry, error) {
+	for _, prefix := range prefixes {
+		if prefix == "" {
+			continue
+		}
+		query := gorpmapping.NewQuery(`
+			SELECT * FROM project_cache
+			WHERE project_id = $1 AND integration_name = $2 AND left(key, length($3)) = $3
+			ORDER BY created DESC LIMIT 1
+		`).Args(projectID, integrationName, prefix)
+		e, err := get(ctx, db, query)
+		if err != nil {
+			if sdk.ErrorIs(err, sdk.ErrNotFound) {
+				continue
+			}
+			return nil, err
+		}
+		return e, nil
+	}
+	return nil, sdk.WithStack(sdk.ErrNotFound)
+}
+
+// LoadAllByProjectID returns all cache entries for a project, least recently used first.
+func LoadAllByProjectID(ctx context.Context, db gorp.SqlExecutor, projectID int64) ([]sdk.CacheEntry, error) {
+	query := gorpmapping.NewQuery(`
+		SELECT * FROM project_cache
+		WHERE project_id = $1
+		ORDER BY last_access ASC
+	`).Args(projectID)
+	var es []sdk.CacheEntry
+	if err := gorpmapping.GetAll(ctx, db, query, &es); err != nil {
+		return nil, sdk.WrapError(err, "cannot get cache entries")
+	}
+	return es, nil
+}
+
+// Upsert inserts a cache entry or updates the existing entry with the same tag.
+func Upsert(ctx context.Context, db gorp.SqlExecutor, e *sdk.CacheEntry) error {
+	now := time.Now()
+	e.Created, e.LastAccess = now, now
+
+	old, err := LoadByTag(ctx, db, e.ProjectID, e.IntegrationName, e.Tag)
+	if err != nil && !sdk.ErrorIs(err, sdk.ErrNotFound) {
+		return err
+	}
+	if old == nil {
+		return sdk.WrapError(gorpmapping.Insert(db, e), "unable to insert cache entry %s", e.Key)
+	}
+	e.ID = old.ID
+	return sdk.WrapError(gorpmapping.Update(db, e), "unable to update cache entry %s", e.Key)
+}
+
+// UpdateLastAccess sets the last access date of a cache entry to now.
+func UpdateLastAccess(db gorp.SqlExecutor, id int64) error {
+	_, err := db.Exec("UPDATE project_cache SET last_access = $1 WHERE id = $2", time.Now(), id)
+	return sdk.WrapError(err, "unable to update cache entry %d", id)
+}
+
+// Delete a cache entry.
+func Delete(db gorp.SqlExecutor, e *sdk.CacheEntry) error {
+	return sdk.WrapError(gorpmapping.Delete(db, e), "unable to delete cache entry %d", e.ID)
+}
+
+// Evict removes least recently used caches of a project from storage and database until
+// the total size of the project caches is lower than given size. The cache entry with
+// given id is never removed.
+func Evict(ctx context.Context, db gorp.SqlExecutor, sharedStorage objectstore.Driver, projectKey string, projectID, maxSize, keepID int64) error {
+	es, err := LoadAllByProjectID(ctx, db, projectID)
+	if err != nil {
+		return err
+	}
+
+	var total int64
+	for _, e := range es {
+		total += e.Size
+	}
+
+	for i := range es {
+		if total <= maxSize {
+			break
+		}
+		e := &es[i]
+		if e.ID == keepID {
+			continue
+		}
+
+		storageDriver, err := objectstore.GetDriver(ctx, db, sharedStorage, projectKey, e.IntegrationName)
+		if err != nil {
+			return err
+		}
+		if err := storageDriver.Delete(ctx, &sdk.Cache{Name: "cache.tar", Project: projectKey, Tag: e.Tag}); err != nil {
+			log.Warning(ctx, "workercache.Evict> cannot delete cache %s for project %s: %v", e.Key, projectKey, err)
+		}
+		if err := Delete(db, e); err != nil {
+			return err
+		}
+		log.Info(ctx, "workercache.Evict> cache %s evicted for project %s (%d bytes)", e.Key, projectKey, e.Size)
+		total -= e.Size
+	}
+
+	return nil
+}
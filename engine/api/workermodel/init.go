@@ -33,6 +33,7 @@ export CDS_GRAYLOG_HOST={{.GraylogHost}}
 export CDS_GRAYLOG_PORT={{.GraylogPort}}
 export CDS_GRAYLOG_EXTRA_KEY={{.GraylogExtraKey}}
 export CDS_GRAYLOG_EXTRA_VALUE={{.GraylogExtraValue}}
+export CDS_OTLP_ENDPOINT={{.OTLPEndpoint}}
 
 # Basic build binaries
 cd $HOME
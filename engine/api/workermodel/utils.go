@@ -16,6 +16,7 @@ var defaultEnvs = map[string]string{
 	"CDS_GRAYLOG_PORT":        "{{.GraylogPort}}",
 	"CDS_GRAYLOG_EXTRA_KEY":   "{{.GraylogExtraKey}}",
 	"CDS_GRAYLOG_EXTRA_VALUE": "{{.GraylogExtraValue}}",
+	"CDS_OTLP_ENDPOINT":       "{{.OTLPEndpoint}}",
 }
 
 func mergeModelEnvsWithDefaultEnvs(m *workerModel) {
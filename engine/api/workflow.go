@@ -19,6 +19,7 @@ import (
 	"github.com/ovh/cds/engine/api/permission"
 	"github.com/ovh/cds/engine/api/pipeline"
 	"github.com/ovh/cds/engine/api/project"
+	"github.com/ovh/cds/engine/api/purge"
 	"github.com/ovh/cds/engine/api/services"
 	"github.com/ovh/cds/engine/api/workflow"
 	"github.com/ovh/cds/engine/service"
@@ -51,13 +52,14 @@ func (api *API) getWorkflowsHandler() service.Handler {
 			dao.Filters.GroupIDs = nil
 		}
 
-		ws, err := dao.LoadAll(ctx, api.mustDBWithCtx(ctx))
+		ws, err := dao.LoadAll(ctx, api.mustDBReplica(workflow.SearchReplicaStaleness).WithContext(ctx).(*gorp.DbMap))
 		if err != nil {
 			return err
 		}
+		ws = filterWorkflowsByScopes(ctx, ws)
 
 		ids := ws.IDs()
-		perms, err := permission.LoadWorkflowMaxLevelPermissionByWorkflowIDs(ctx, api.mustDB(), ids, groupIDS)
+		perms, err := permission.LoadWorkflowMaxLevelPermissionByWorkflowIDs(ctx, api.mustDBReplica(workflow.SearchReplicaStaleness), ids, groupIDS)
 		if err != nil {
 			return err
 		}
@@ -607,6 +609,11 @@ func (api *API) deleteWorkflowHandler() service.Handler {
 					return
 				}
 
+				if err := purge.DeleteWorkflowRunArchives(ctx, txg, api.SharedStorage, oldW.ID); err != nil {
+					log.Error(ctx, "deleteWorkflowHandler> unable to delete run archives: %v", err)
+					return
+				}
+
 				if err := workflow.Delete(ctx, txg, api.Cache, *p, &oldW); err != nil {
 					log.Error(ctx, "deleteWorkflowHandler> unable to delete workflow: %v", err)
 					return
@@ -770,6 +777,7 @@ func (api *API) getSearchWorkflowHandler() service.Handler {
 		if err != nil {
 			return err
 		}
+		ws = filterWorkflowsByScopes(ctx, ws)
 
 		ids := ws.IDs()
 		perms, err := permission.LoadWorkflowMaxLevelPermissionByWorkflowIDs(ctx, api.mustDB(), ids, groupIDS)
@@ -0,0 +1,227 @@
+package workflow
+
+import (
+	"context"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/action"
+	"github.com/ovh/cds/engine/api/group"
+	"github.com/ovh/cds/engine/api/pipeline"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/telemetry"
+)
+
+// ChildPipelinesLimits are the limits applied to the child pipelines added to a workflow run.
+type ChildPipelinesLimits struct {
+	MaxDepth     int64
+	MaxPipelines int64
+	MaxJobs      int64
+}
+
+// AddChildPipelines adds given pipelines to the workflow run as children of the node, child pipelines are triggered
+// when the node succeeds. A child pipeline with the same name added by a previous run of the node is replaced.
+func AddChildPipelines(ctx context.Context, db gorp.SqlExecutor, proj sdk.Project, wr *sdk.WorkflowRun, nodeID int64, pips []sdk.Pipeline, limits ChildPipelinesLimits) ([]sdk.Node, error) {
+	_, end := telemetry.Span(ctx, "workflow.AddChildPipelines")
+	defer end()
+
+	parent := wr.Workflow.WorkflowData.NodeByID(nodeID)
+	if parent == nil {
+		return nil, sdk.NewErrorFrom(sdk.ErrNotFound, "cannot find node %d in workflow run", nodeID)
+	}
+	if parent.Type != sdk.NodeTypePipeline || parent.Context == nil {
+		return nil, sdk.NewErrorFrom(sdk.ErrWrongRequest, "child pipelines can only be added to a pipeline node")
+	}
+
+	if err := checkChildPipelinesLimits(*wr, *parent, pips, limits); err != nil {
+		return nil, err
+	}
+
+	// check that action used by job can be used by pipeline's project
+	groupIDs := make([]int64, 0, len(proj.ProjectGroups)+1)
+	groupIDs = append(groupIDs, group.SharedInfraGroup.ID)
+	for i := range proj.ProjectGroups {
+		groupIDs = append(groupIDs, proj.ProjectGroups[i].Group.ID)
+	}
+
+	nodes := make([]sdk.Node, 0, len(pips))
+	for i := range pips {
+		pip := &pips[i]
+		if !sdk.NamePatternRegex.MatchString(pip.Name) {
+			return nil, sdk.NewErrorFrom(sdk.ErrInvalidName, "pipeline name '%s' should match pattern %s", pip.Name, sdk.NamePattern)
+		}
+
+		// a child pipeline with the same name can only replace a child pipeline of the same node
+		previous := childPipelineNode(*parent, pip.Name)
+		if previous == nil && wr.Workflow.WorkflowData.NodeByName(pip.Name) != nil {
+			return nil, sdk.NewErrorFrom(sdk.ErrWrongRequest, "a node named %s already exists in the workflow", pip.Name)
+		}
+
+		pipID, err := db.SelectInt("SELECT nextval('pipeline_id_seq')")
+		if err != nil {
+			return nil, sdk.WrapError(err, "cannot get pipeline id")
+		}
+		pip.ID = pipID
+		pip.ProjectID = proj.ID
+		pip.ProjectKey = proj.Key
+		if err := prepareChildPipelineJobs(ctx, db, pip, groupIDs); err != nil {
+			return nil, sdk.WrapError(err, "invalid child pipeline %s", pip.Name)
+		}
+
+		n := sdk.Node{
+			WorkflowID: wr.WorkflowID,
+			Name:       pip.Name,
+			Ref:        pip.Name,
+			Type:       sdk.NodeTypePipeline,
+			Context: &sdk.NodeContext{
+				PipelineID:             pip.ID,
+				PipelineName:           pip.Name,
+				ApplicationID:          parent.Context.ApplicationID,
+				ApplicationName:        parent.Context.ApplicationName,
+				EnvironmentID:          parent.Context.EnvironmentID,
+				EnvironmentName:        parent.Context.EnvironmentName,
+				ProjectIntegrationID:   parent.Context.ProjectIntegrationID,
+				ProjectIntegrationName: parent.Context.ProjectIntegrationName,
+				Conditions: sdk.WorkflowNodeConditions{
+					PlainConditions: []sdk.WorkflowNodeCondition{{
+						Variable: "cds.status",
+						Operator: sdk.WorkflowConditionsOperatorEquals,
+						Value:    sdk.StatusSuccess,
+					}},
+				},
+			},
+			ChildDepth: parent.ChildDepth + 1,
+		}
+
+		if previous != nil {
+			n.ID = previous.ID
+			n.Context.NodeID = n.ID
+			delete(wr.Workflow.Pipelines, previous.Context.PipelineID)
+			for j := range parent.Triggers {
+				if parent.Triggers[j].ChildNode.ID == n.ID {
+					parent.Triggers[j].ChildNode = n
+					break
+				}
+			}
+		} else {
+			nID, err := db.SelectInt("SELECT nextval('w_node_id_seq')")
+			if err != nil {
+				return nil, sdk.WrapError(err, "cannot get node id")
+			}
+			tID, err := db.SelectInt("SELECT nextval('w_node_trigger_id_seq')")
+			if err != nil {
+				return nil, sdk.WrapError(err, "cannot get node trigger id")
+			}
+			n.ID = nID
+			n.Context.NodeID = n.ID
+			parent.Triggers = append(parent.Triggers, sdk.NodeTrigger{
+				ID:             tID,
+				ParentNodeID:   parent.ID,
+				ChildNodeID:    n.ID,
+				ParentNodeName: parent.Name,
+				ChildNode:      n,
+			})
+		}
+
+		if wr.Workflow.Pipelines == nil {
+			wr.Workflow.Pipelines = make(map[int64]sdk.Pipeline)
+		}
+		wr.Workflow.Pipelines[pip.ID] = *pip
+		nodes = append(nodes, n)
+	}
+
+	if err := UpdateWorkflowRun(ctx, db, wr); err != nil {
+		return nil, err
+	}
+
+	return nodes, nil
+}
+
+// childPipelineNode returns the child pipeline node with given name added to the parent node.
+func childPipelineNode(parent sdk.Node, name string) *sdk.Node {
+	for i := range parent.Triggers {
+		n := &parent.Triggers[i].ChildNode
+		if n.ChildDepth > 0 && n.Name == name {
+			return n
+		}
+	}
+	return nil
+}
+
+// checkChildPipelinesLimits returns an error if the child pipelines of the workflow run exceed the limits once given
+// pipelines are added to the node.
+func checkChildPipelinesLimits(wr sdk.WorkflowRun, parent sdk.Node, pips []sdk.Pipeline, limits ChildPipelinesLimits) error {
+	if parent.ChildDepth+1 > limits.MaxDepth {
+		return sdk.NewErrorFrom(sdk.ErrForbidden, "child pipelines depth is limited to %d", limits.MaxDepth)
+	}
+
+	names := make(map[string]struct{}, len(pips))
+	countPipelines, countJobs := int64(len(pips)), int64(0)
+	for _, pip := range pips {
+		if _, ok := names[pip.Name]; ok {
+			return sdk.NewErrorFrom(sdk.ErrWrongRequest, "pipeline %s is given more than once", pip.Name)
+		}
+		names[pip.Name] = struct{}{}
+		for _, s := range pip.Stages {
+			countJobs += int64(len(s.Jobs))
+		}
+	}
+
+	// count child pipelines already in the workflow run, except the ones that will be replaced
+	for _, n := range wr.Workflow.WorkflowData.Array() {
+		if n.ChildDepth == 0 {
+			continue
+		}
+		if _, ok := names[n.Name]; ok && childPipelineNode(parent, n.Name) != nil {
+			continue
+		}
+		countPipelines++
+		for _, s := range wr.Workflow.Pipelines[n.Context.PipelineID].Stages {
+			countJobs += int64(len(s.Jobs))
+		}
+	}
+
+	if countPipelines > limits.MaxPipelines {
+		return sdk.NewErrorFrom(sdk.ErrForbidden, "child pipelines count is limited to %d for a workflow run", limits.MaxPipelines)
+	}
+	if countJobs > limits.MaxJobs {
+		return sdk.NewErrorFrom(sdk.ErrForbidden, "child pipelines jobs count is limited to %d for a workflow run", limits.MaxJobs)
+	}
+	return nil
+}
+
+// prepareChildPipelineJobs checks the jobs of a child pipeline and sets the identifiers of its stages and jobs, they
+// are taken from the sequences of the pipeline tables to not collide with stored pipelines and actions.
+func prepareChildPipelineJobs(ctx context.Context, db gorp.SqlExecutor, pip *sdk.Pipeline, groupIDs []int64) error {
+	for i := range pip.Stages {
+		s := &pip.Stages[i]
+		stageID, err := db.SelectInt("SELECT nextval('pipeline_stage_id_seq')")
+		if err != nil {
+			return sdk.WrapError(err, "cannot get stage id")
+		}
+		s.ID = stageID
+		s.PipelineID = pip.ID
+		for j := range s.Jobs {
+			job := &s.Jobs[j]
+			if err := pipeline.CheckJob(ctx, db, job); err != nil {
+				return err
+			}
+			if err := action.CompleteChildrenForGroupIDs(ctx, db, &job.Action, groupIDs); err != nil {
+				return err
+			}
+			jobID, err := db.SelectInt("SELECT nextval('pipeline_action_id_seq')")
+			if err != nil {
+				return sdk.WrapError(err, "cannot get job id")
+			}
+			actionID, err := db.SelectInt("SELECT nextval('action_id_seq')")
+			if err != nil {
+				return sdk.WrapError(err, "cannot get job action id")
+			}
+			job.PipelineActionID = jobID
+			job.PipelineStageID = s.ID
+			job.Action.ID = actionID
+			job.Action.Type = sdk.JoinedAction
+		}
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package workflow
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func TestCheckChildPipelinesLimits(t *testing.T) {
+	pipWithJobs := func(name string, count int) sdk.Pipeline {
+		return sdk.Pipeline{Name: name, Stages: []sdk.Stage{{Jobs: make([]sdk.Job, count)}}}
+	}
+
+	child := sdk.Node{ID: 2, Name: "build-api", ChildDepth: 1, Context: &sdk.NodeContext{PipelineID: 20}}
+	root := sdk.Node{ID: 1, Name: "root", Context: &sdk.NodeContext{PipelineID: 10}, Triggers: []sdk.NodeTrigger{{ChildNode: child}}}
+	wr := sdk.WorkflowRun{
+		Workflow: sdk.Workflow{
+			WorkflowData: sdk.WorkflowData{Node: root},
+			Pipelines: map[int64]sdk.Pipeline{
+				10: pipWithJobs("root", 1),
+				20: pipWithJobs("build-api", 3),
+			},
+		},
+	}
+	limits := ChildPipelinesLimits{MaxDepth: 1, MaxPipelines: 2, MaxJobs: 5}
+
+	// A child pipeline can't add children with depth limited to 1
+	require.Error(t, checkChildPipelinesLimits(wr, child, []sdk.Pipeline{pipWithJobs("test-api", 1)}, limits))
+
+	// The existing child pipeline is counted
+	require.NoError(t, checkChildPipelinesLimits(wr, root, []sdk.Pipeline{pipWithJobs("build-ui", 2)}, limits))
+	require.Error(t, checkChildPipelinesLimits(wr, root, []sdk.Pipeline{pipWithJobs("build-ui", 3)}, limits))
+	require.Error(t, checkChildPipelinesLimits(wr, root, []sdk.Pipeline{pipWithJobs("build-ui", 1), pipWithJobs("build-cli", 1)}, limits))
+
+	// The existing child pipeline is not counted when replaced
+	require.NoError(t, checkChildPipelinesLimits(wr, root, []sdk.Pipeline{pipWithJobs("build-api", 5)}, limits))
+
+	// The same pipeline can't be given twice
+	require.Error(t, checkChildPipelinesLimits(wr, root, []sdk.Pipeline{pipWithJobs("build-ui", 1), pipWithJobs("build-ui", 1)}, limits))
+}
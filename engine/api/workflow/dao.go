@@ -27,6 +27,9 @@ import (
 	"github.com/ovh/cds/sdk/telemetry"
 )
 
+// SearchReplicaStaleness is the replication lag tolerated when workflows are searched from the database replica.
+const SearchReplicaStaleness = 30 * time.Second
+
 type PushSecrets struct {
 	ApplicationsSecrets map[int64][]sdk.Variable
 	EnvironmentdSecrets map[int64][]sdk.Variable
@@ -270,6 +273,10 @@ func LoadByID(ctx context.Context, db gorp.SqlExecutor, store cache.Store, proj
 
 // Insert inserts a new workflow
 func Insert(ctx context.Context, db gorpmapper.SqlExecutorWithTx, store cache.Store, proj sdk.Project, w *sdk.Workflow) error {
+	return insert(ctx, db, store, proj, w, UpdateOptions{})
+}
+
+func insert(ctx context.Context, db gorpmapper.SqlExecutorWithTx, store cache.Store, proj sdk.Project, w *sdk.Workflow, opts UpdateOptions) error {
 	if err := CompleteWorkflow(ctx, db, w, proj, LoadOptions{}); err != nil {
 		return err
 	}
@@ -291,11 +298,11 @@ func Insert(ctx context.Context, db gorpmapper.SqlExecutorWithTx, store cache.St
 
 	w.LastModified = time.Now()
 	if err := db.QueryRow(`INSERT INTO workflow (
-		name, description, icon, project_id, history_length, from_repository, purge_tags, workflow_data, metadata
+		name, description, icon, project_id, history_length, from_repository, purge_tags, purge_policy, workflow_data, metadata
 	)
-	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
 	RETURNING id`,
-		w.Name, w.Description, w.Icon, w.ProjectID, w.HistoryLength, w.FromRepository, w.PurgeTags, w.WorkflowData, w.Metadata).Scan(&w.ID); err != nil {
+		w.Name, w.Description, w.Icon, w.ProjectID, w.HistoryLength, w.FromRepository, w.PurgeTags, w.PurgePolicy, w.WorkflowData, w.Metadata).Scan(&w.ID); err != nil {
 		return sdk.WrapError(err, "Unable to insert workflow %s/%s", w.ProjectKey, w.Name)
 	}
 
@@ -348,7 +355,7 @@ func Insert(ctx context.Context, db gorpmapper.SqlExecutorWithTx, store cache.St
 	}
 
 	// Manage new hooks
-	if len(w.WorkflowData.Node.Hooks) > 0 {
+	if len(w.WorkflowData.Node.Hooks) > 0 && !opts.DisableHookManagement {
 		if err := hookRegistration(ctx, db, store, proj, w, nil); err != nil {
 			return err
 		}
@@ -770,6 +777,10 @@ func CheckValidity(ctx context.Context, db gorp.SqlExecutor, w *sdk.Workflow) er
 		return sdk.NewErrorFrom(sdk.ErrWorkflowInvalid, "workflow name should match pattern %s", sdk.NamePattern)
 	}
 
+	if err := w.PurgePolicy.IsValid(); err != nil {
+		return err
+	}
+
 	//Check refs
 	for _, j := range w.WorkflowData.Joins {
 		if len(j.JoinContext) == 0 {
@@ -1046,6 +1057,35 @@ func Push(ctx context.Context, db *gorp.DbMap, store cache.Store, proj *sdk.Proj
 	opts *PushOption, u sdk.Identifiable, decryptFunc keys.DecryptFunc) ([]sdk.Message, *sdk.Workflow, *sdk.Workflow, *PushSecrets, error) {
 	ctx, end := telemetry.Span(ctx, "workflow.Push")
 	defer end()
+
+	tx, err := db.Begin()
+	if err != nil {
+		return nil, nil, nil, nil, sdk.WrapError(err, "unable to start tx")
+	}
+	defer tx.Rollback() // nolint
+
+	allMsg, wf, oldWf, allSecrets, err := PushWithTx(ctx, tx, store, proj, data, opts, u, decryptFunc)
+	if err != nil {
+		return allMsg, nil, nil, nil, err
+	}
+
+	if opts != nil && !opts.IsDefaultBranch {
+		_ = tx.Rollback()
+		log.Debug("workflow %s rollbacked because it's not coming from the default branch", wf.Name)
+	} else {
+		if err := tx.Commit(); err != nil {
+			return nil, nil, nil, nil, sdk.WithStack(err)
+		}
+
+		log.Debug("workflow %s updated", wf.Name)
+	}
+
+	return allMsg, wf, oldWf, allSecrets, nil
+}
+
+// PushWithTx push a workflow from cds files in given transaction, the transaction is not committed.
+func PushWithTx(ctx context.Context, tx gorpmapper.SqlExecutorWithTx, store cache.Store, proj *sdk.Project, data exportentities.WorkflowComponents,
+	opts *PushOption, u sdk.Identifiable, decryptFunc keys.DecryptFunc) ([]sdk.Message, *sdk.Workflow, *sdk.Workflow, *PushSecrets, error) {
 	if data.Workflow == nil {
 		return nil, nil, nil, nil, sdk.NewErrorFrom(sdk.ErrWrongRequest, "invalid given workflow components, missing workflow file")
 	}
@@ -1058,12 +1098,12 @@ func Push(ctx context.Context, db *gorp.DbMap, store cache.Store, proj *sdk.Proj
 		oldWf = &opts.OldWorkflow
 	} else {
 		// load the workflow from database if exists
-		workflowExists, err = Exists(db, proj.Key, data.Workflow.GetName())
+		workflowExists, err = Exists(tx, proj.Key, data.Workflow.GetName())
 		if err != nil {
 			return nil, nil, nil, nil, sdk.WrapError(err, "cannot check if workflow exists")
 		}
 		if workflowExists {
-			oldWf, err = Load(ctx, db, store, *proj, data.Workflow.GetName(), LoadOptions{WithIcon: true})
+			oldWf, err = Load(ctx, tx, store, *proj, data.Workflow.GetName(), LoadOptions{WithIcon: true})
 			if err != nil {
 				return nil, nil, nil, nil, sdk.WrapError(err, "unable to load existing workflow")
 			}
@@ -1075,12 +1115,6 @@ func Push(ctx context.Context, db *gorp.DbMap, store cache.Store, proj *sdk.Proj
 		return nil, nil, nil, nil, sdk.WithStack(sdk.ErrWorkflowAlreadyAsCode)
 	}
 
-	tx, err := db.Begin()
-	if err != nil {
-		return nil, nil, nil, nil, sdk.WrapError(err, "unable to start tx")
-	}
-	defer tx.Rollback() // nolint
-
 	var allMsg []sdk.Message
 	allSecrets := PushSecrets{
 		ApplicationsSecrets: make(map[int64][]sdk.Variable),
@@ -1127,11 +1161,6 @@ func Push(ctx context.Context, db *gorp.DbMap, store cache.Store, proj *sdk.Proj
 		proj.SetPipeline(*pipDB)
 	}
 
-	isDefaultBranch := true
-	if opts != nil {
-		isDefaultBranch = opts.IsDefaultBranch
-	}
-
 	var importOptions = ImportOptions{
 		Force: true,
 	}
@@ -1144,6 +1173,7 @@ func Push(ctx context.Context, db *gorp.DbMap, store cache.Store, proj *sdk.Proj
 		importOptions.RepositoryName = opts.RepositoryName
 		importOptions.RepositoryStrategy = opts.RepositoryStrategy
 		importOptions.HookUUID = opts.HookUUID
+		importOptions.DisableHookManagement = opts.DisableHookManagement
 	}
 
 	wf, msgList, err := ParseAndImport(ctx, tx, store, *proj, oldWf, data.Workflow, u, importOptions)
@@ -1170,7 +1200,7 @@ func Push(ctx context.Context, db *gorp.DbMap, store cache.Store, proj *sdk.Proj
 			if app.FromRepository != "" {
 				continue
 			}
-			appSecrets, err := LoadApplicationSecrets(db, id)
+			appSecrets, err := LoadApplicationSecrets(tx, id)
 			if err != nil {
 				return nil, nil, nil, nil, err
 			}
@@ -1180,7 +1210,7 @@ func Push(ctx context.Context, db *gorp.DbMap, store cache.Store, proj *sdk.Proj
 			if env.FromRepository != "" {
 				continue
 			}
-			secrets, err := LoadEnvironmentSecrets(db, id)
+			secrets, err := LoadEnvironmentSecrets(tx, id)
 			if err != nil {
 				return nil, nil, nil, nil, err
 			}
@@ -1196,17 +1226,6 @@ func Push(ctx context.Context, db *gorp.DbMap, store cache.Store, proj *sdk.Proj
 		wf.Applications[wf.WorkflowData.Node.Context.ApplicationID] = app
 	}
 
-	if !isDefaultBranch {
-		_ = tx.Rollback()
-		log.Debug("workflow %s rollbacked because it's not coming from the default branch", wf.Name)
-	} else {
-		if err := tx.Commit(); err != nil {
-			return nil, nil, nil, nil, sdk.WithStack(err)
-		}
-
-		log.Debug("workflow %s updated", wf.Name)
-	}
-
 	return allMsg, wf, oldWf, &allSecrets, nil
 }
 
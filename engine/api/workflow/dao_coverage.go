@@ -132,7 +132,7 @@ func (c *Coverage) PostUpdate(s gorp.SqlExecutor) error {
 }
 
 // ComputeNewReport compute trends and import new coverage report
-func ComputeNewReport(ctx context.Context, db gorpmapper.SqlExecutorWithTx, cache cache.Store, report coverage.Report, wnr *sdk.WorkflowNodeRun, proj sdk.Project) error {
+func ComputeNewReport(ctx context.Context, db gorpmapper.SqlExecutorWithTx, cache cache.Store, report coverage.Report, wnr *sdk.WorkflowNodeRun, proj sdk.Project) (sdk.WorkflowNodeRunCoverage, error) {
 	covReport := sdk.WorkflowNodeRunCoverage{
 		WorkflowID:        wnr.WorkflowID,
 		WorkflowRunID:     wnr.WorkflowRunID,
@@ -148,7 +148,7 @@ func ComputeNewReport(ctx context.Context, db gorpmapper.SqlExecutorWithTx, cach
 	// Get previous report
 	previousReport, err := loadPreviousCoverageReport(db, wnr.WorkflowID, wnr.Number, wnr.VCSRepository, wnr.VCSBranch, covReport.ApplicationID)
 	if err != nil && !sdk.ErrorIs(err, sdk.ErrNotFound) {
-		return sdk.WrapError(err, "unable to load previous report")
+		return covReport, sdk.WrapError(err, "unable to load previous report")
 	}
 	if !sdk.ErrorIs(err, sdk.ErrNotFound) {
 		// remove data we don't need
@@ -157,14 +157,14 @@ func ComputeNewReport(ctx context.Context, db gorpmapper.SqlExecutorWithTx, cach
 	}
 
 	if err := ComputeLatestDefaultBranchReport(ctx, db, cache, proj, wnr, &covReport); err != nil {
-		return sdk.WrapError(err, "Unable to get default branch coverage report")
+		return covReport, sdk.WrapError(err, "Unable to get default branch coverage report")
 	}
 
 	if err := InsertCoverage(db, covReport); err != nil {
-		return sdk.WrapError(err, "Unable to insert coverage report")
+		return covReport, sdk.WrapError(err, "Unable to insert coverage report")
 	}
 
-	return nil
+	return covReport, nil
 }
 
 // ComputeLatestDefaultBranchReport add the default branch coverage report into  the given report
@@ -196,6 +196,11 @@ func ComputeLatestDefaultBranchReport(ctx context.Context, db gorpmapper.SqlExec
 		if errD != nil && !sdk.ErrorIs(errD, sdk.ErrNotFound) {
 			return sdk.WrapError(errD, "ComputeLatestDefaultBranchReport> Cannot get latest report on default branch")
 		}
+		// compute files diff before removing data we don't need
+		covReport.Trend.FilesDiff = nil
+		if errD == nil {
+			covReport.Trend.FilesDiff = sdk.ComputeCoverageFilesDiff(covReport.Report, defaultCoverage.Report)
+		}
 		defaultCoverage.Report.Files = nil
 		covReport.Trend.DefaultBranch = defaultCoverage.Report
 	} else {
@@ -204,3 +209,50 @@ func ComputeLatestDefaultBranchReport(ctx context.Context, db gorpmapper.SqlExec
 
 	return nil
 }
+
+// SendCoveragePullRequestComment comments the pull request of the node run branch with a summary
+// of the coverage change compared to the default branch.
+func SendCoveragePullRequestComment(ctx context.Context, db gorpmapper.SqlExecutorWithTx, cache cache.Store, proj sdk.Project, wnr *sdk.WorkflowNodeRun, covReport sdk.WorkflowNodeRunCoverage) error {
+	// Nothing to compare if we are on the default branch or if there is no report for it
+	if covReport.Trend.DefaultBranch.TotalLines == 0 {
+		return nil
+	}
+
+	projectVCSServer, err := repositoriesmanager.LoadProjectVCSServerLinkByProjectKeyAndVCSServerName(ctx, db, proj.Key, wnr.VCSServer)
+	if err != nil {
+		return err
+	}
+	client, err := repositoriesmanager.AuthorizedClient(ctx, db, cache, proj.Key, projectVCSServer)
+	if err != nil {
+		return sdk.NewErrorWithStack(err, sdk.ErrNoReposManagerClientAuth)
+	}
+	vcsConf, err := repositoriesmanager.LoadByName(ctx, db, wnr.VCSServer)
+	if err != nil {
+		return err
+	}
+
+	reqComment := sdk.VCSPullRequestCommentRequest{Message: covReport.PullRequestComment(20)}
+	reqComment.Revision = wnr.VCSHash
+
+	if vcsConf.Type == "gerrit" {
+		changeID := sdk.ParameterValue(wnr.BuildParameters, "gerrit.change.id")
+		if changeID == "" {
+			return nil
+		}
+		reqComment.ChangeID = changeID
+		return client.PullRequestComment(ctx, wnr.VCSRepository, reqComment)
+	}
+
+	prs, err := client.PullRequests(ctx, wnr.VCSRepository, sdk.VCSRequestModifierWithState(sdk.VCSPullRequestStateOpen))
+	if err != nil {
+		return err
+	}
+	for _, pr := range prs {
+		if pr.Head.Branch.DisplayID == wnr.VCSBranch && !pr.Merged && !pr.Closed {
+			reqComment.ID = pr.ID
+			return client.PullRequestComment(ctx, wnr.VCSRepository, reqComment)
+		}
+	}
+
+	return nil
+}
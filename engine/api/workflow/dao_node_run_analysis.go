@@ -0,0 +1,149 @@
+package workflow
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/database/gorpmapping"
+	"github.com/ovh/cds/engine/api/repositoriesmanager"
+	"github.com/ovh/cds/engine/cache"
+	"github.com/ovh/cds/engine/gorpmapper"
+	"github.com/ovh/cds/sdk"
+)
+
+func getAnalysis(ctx context.Context, db gorp.SqlExecutor, q gorpmapping.Query) (*sdk.WorkflowNodeRunAnalysis, error) {
+	var dbAnalysis dbNodeRunAnalysis
+	found, err := gorpmapping.Get(ctx, db, q, &dbAnalysis)
+	if err != nil {
+		return nil, sdk.WrapError(err, "cannot get analysis")
+	}
+	if !found {
+		return nil, sdk.WithStack(sdk.ErrNotFound)
+	}
+	a := sdk.WorkflowNodeRunAnalysis(dbAnalysis)
+	return &a, nil
+}
+
+// LoadAnalysesByNodeRunID returns all analyses for a node run.
+func LoadAnalysesByNodeRunID(ctx context.Context, db gorp.SqlExecutor, nodeRunID int64) ([]sdk.WorkflowNodeRunAnalysis, error) {
+	query := gorpmapping.NewQuery(`
+		SELECT * FROM workflow_node_run_analysis
+		WHERE workflow_node_run_id = $1
+		ORDER BY tool
+	`).Args(nodeRunID)
+	var dbAnalyses []dbNodeRunAnalysis
+	if err := gorpmapping.GetAll(ctx, db, query, &dbAnalyses); err != nil {
+		return nil, sdk.WrapError(err, "cannot get analyses for node run %d", nodeRunID)
+	}
+	analyses := make([]sdk.WorkflowNodeRunAnalysis, len(dbAnalyses))
+	for i := range dbAnalyses {
+		analyses[i] = sdk.WorkflowNodeRunAnalysis(dbAnalyses[i])
+	}
+	return analyses, nil
+}
+
+func loadAnalysisByNodeRunIDAndTool(ctx context.Context, db gorp.SqlExecutor, nodeRunID int64, tool string) (*sdk.WorkflowNodeRunAnalysis, error) {
+	query := gorpmapping.NewQuery(`
+		SELECT * FROM workflow_node_run_analysis
+		WHERE workflow_node_run_id = $1 AND tool = $2
+	`).Args(nodeRunID, tool)
+	return getAnalysis(ctx, db, query)
+}
+
+// loadPreviousAnalysis returns the analysis of the latest run on the same branch before given node run.
+func loadPreviousAnalysis(ctx context.Context, db gorp.SqlExecutor, nr *sdk.WorkflowNodeRun, tool string) (*sdk.WorkflowNodeRunAnalysis, error) {
+	query := gorpmapping.NewQuery(`
+		SELECT * FROM workflow_node_run_analysis
+		WHERE workflow_id = $1 AND application_id = $2 AND tool = $3 AND branch = $4 AND run_number < $5
+		ORDER BY run_number DESC, workflow_node_run_id DESC
+		LIMIT 1
+	`).Args(nr.WorkflowID, nr.ApplicationID, tool, nr.VCSBranch, nr.Number)
+	return getAnalysis(ctx, db, query)
+}
+
+// loadLatestAnalysis returns the analysis of the latest run on given branch.
+func loadLatestAnalysis(ctx context.Context, db gorp.SqlExecutor, nr *sdk.WorkflowNodeRun, tool, branch string) (*sdk.WorkflowNodeRunAnalysis, error) {
+	query := gorpmapping.NewQuery(`
+		SELECT * FROM workflow_node_run_analysis
+		WHERE workflow_id = $1 AND application_id = $2 AND tool = $3 AND branch = $4
+		ORDER BY run_number DESC, workflow_node_run_id DESC
+		LIMIT 1
+	`).Args(nr.WorkflowID, nr.ApplicationID, tool, branch)
+	return getAnalysis(ctx, db, query)
+}
+
+// SaveAnalysisReport computes new and fixed findings compared to the latest analysis on the
+// default branch, then saves the analysis for the node run and tool.
+func SaveAnalysisReport(ctx context.Context, db gorpmapper.SqlExecutorWithTx, cache cache.Store, proj sdk.Project, nr *sdk.WorkflowNodeRun, workerReport sdk.AnalysisWorkerReport) (*sdk.WorkflowNodeRunAnalysis, error) {
+	if workerReport.Tool == "" {
+		return nil, sdk.NewErrorFrom(sdk.ErrWrongRequest, "missing analysis tool name")
+	}
+
+	defaultBranch := nr.VCSBranch
+	if nr.VCSServer != "" {
+		projectVCSServer, err := repositoriesmanager.LoadProjectVCSServerLinkByProjectKeyAndVCSServerName(ctx, db, proj.Key, nr.VCSServer)
+		if err != nil {
+			return nil, sdk.NewErrorWithStack(err, sdk.WrapError(sdk.ErrNoReposManagerClientAuth, "cannot get client %s %s", proj.Key, nr.VCSServer))
+		}
+		client, err := repositoriesmanager.AuthorizedClient(ctx, db, cache, proj.Key, projectVCSServer)
+		if err != nil {
+			return nil, sdk.NewErrorWithStack(err, sdk.WrapError(sdk.ErrNoReposManagerClientAuth, "cannot get repo client %s", nr.VCSServer))
+		}
+		b, err := repositoriesmanager.DefaultBranch(ctx, client, nr.VCSRepository)
+		if err != nil {
+			return nil, sdk.WrapError(err, "unable to get default branch")
+		}
+		defaultBranch = b.DisplayID
+	}
+
+	analysis := &sdk.WorkflowNodeRunAnalysis{
+		WorkflowID:        nr.WorkflowID,
+		WorkflowRunID:     nr.WorkflowRunID,
+		WorkflowNodeRunID: nr.ID,
+		ApplicationID:     nr.ApplicationID,
+		Num:               nr.Number,
+		Branch:            nr.VCSBranch,
+		Tool:              workerReport.Tool,
+		Created:           time.Now(),
+		Findings:          workerReport.Findings,
+	}
+
+	// On the default branch, findings are compared to the previous run. For other branches
+	// the latest run on the default branch is used, whatever its number.
+	var baseline *sdk.WorkflowNodeRunAnalysis
+	var err error
+	if defaultBranch == nr.VCSBranch {
+		baseline, err = loadPreviousAnalysis(ctx, db, nr, workerReport.Tool)
+	} else {
+		baseline, err = loadLatestAnalysis(ctx, db, nr, workerReport.Tool, defaultBranch)
+	}
+	if err != nil && !sdk.ErrorIs(err, sdk.ErrNotFound) {
+		return nil, err
+	}
+	var baselineFindings sdk.AnalysisFindings
+	if baseline != nil {
+		baselineFindings = baseline.Findings
+	}
+	analysis.ComputeStatus(baselineFindings)
+
+	old, err := loadAnalysisByNodeRunIDAndTool(ctx, db, nr.ID, workerReport.Tool)
+	if err != nil && !sdk.ErrorIs(err, sdk.ErrNotFound) {
+		return nil, err
+	}
+
+	dbAnalysis := dbNodeRunAnalysis(*analysis)
+	if old == nil {
+		if err := gorpmapping.Insert(db, &dbAnalysis); err != nil {
+			return nil, sdk.WrapError(err, "unable to insert analysis")
+		}
+	} else {
+		dbAnalysis.ID = old.ID
+		if err := gorpmapping.Update(db, &dbAnalysis); err != nil {
+			return nil, sdk.WrapError(err, "unable to update analysis %d", old.ID)
+		}
+	}
+	*analysis = sdk.WorkflowNodeRunAnalysis(dbAnalysis)
+	return analysis, nil
+}
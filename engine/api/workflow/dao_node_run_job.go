@@ -18,6 +18,9 @@ import (
 	"github.com/ovh/cds/sdk/telemetry"
 )
 
+// QueueReplicaStaleness is the replication lag tolerated when the queue is read by users from the database replica.
+const QueueReplicaStaleness = 5 * time.Second
+
 // QueueFilter contains all criteria used to fetch queue
 type QueueFilter struct {
 	ModelType    []string
@@ -40,6 +43,46 @@ func NewQueueFilter() QueueFilter {
 	}
 }
 
+// CountNodeJobRunsByStatus returns the number of workflow_node_run_job for each status.
+func CountNodeJobRunsByStatus(db gorp.SqlExecutor) (map[string]int64, error) {
+	rows, err := db.Query("SELECT status, COUNT(id) FROM workflow_node_run_job GROUP BY status")
+	if err != nil {
+		return nil, sdk.WrapError(err, "cannot count node job runs")
+	}
+	defer rows.Close() // nolint
+
+	res := make(map[string]int64)
+	for rows.Next() {
+		var status string
+		var count int64
+		if err := rows.Scan(&status, &count); err != nil {
+			return nil, sdk.WrapError(err, "cannot scan node job runs count")
+		}
+		res[status] = count
+	}
+	return res, sdk.WithStack(rows.Err())
+}
+
+// CountBookedNodeJobRuns returns the number of waiting node job runs booked by a hatchery, a worker is being spawned
+// for them.
+func CountBookedNodeJobRuns(db gorp.SqlExecutor, store cache.Store) (int64, error) {
+	var ids []int64
+	if _, err := db.Select(&ids, "SELECT id FROM workflow_node_run_job WHERE status = $1", sdk.StatusWaiting); err != nil {
+		return 0, sdk.WrapError(err, "cannot load waiting node job runs")
+	}
+	var count int64
+	for _, id := range ids {
+		booked, err := store.Exist(keyBookJob(id))
+		if err != nil {
+			return 0, sdk.WrapError(err, "cannot check booking of node job run %d", id)
+		}
+		if booked {
+			count++
+		}
+	}
+	return count, nil
+}
+
 // CountNodeJobRunQueue count all workflow_node_run_job accessible
 func CountNodeJobRunQueue(ctx context.Context, db gorp.SqlExecutor, store cache.Store, filter QueueFilter) (sdk.WorkflowNodeJobRunCount, error) {
 	var c sdk.WorkflowNodeJobRunCount
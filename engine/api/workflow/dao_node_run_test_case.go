@@ -0,0 +1,71 @@
+package workflow
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/database/gorpmapping"
+	"github.com/ovh/cds/sdk"
+)
+
+func getAllTestCases(ctx context.Context, db gorp.SqlExecutor, q gorpmapping.Query) ([]sdk.WorkflowNodeRunTestCase, error) {
+	var dbTestCases []dbNodeRunTestCase
+	if err := gorpmapping.GetAll(ctx, db, q, &dbTestCases); err != nil {
+		return nil, sdk.WrapError(err, "cannot get test cases")
+	}
+	testCases := make([]sdk.WorkflowNodeRunTestCase, len(dbTestCases))
+	for i := range dbTestCases {
+		testCases[i] = sdk.WorkflowNodeRunTestCase(dbTestCases[i])
+	}
+	return testCases, nil
+}
+
+// InsertTestCases saves the test case results of a node run.
+func InsertTestCases(db gorp.SqlExecutor, testCases []sdk.WorkflowNodeRunTestCase) error {
+	now := time.Now()
+	for i := range testCases {
+		testCases[i].Created = now
+		dbTestCase := dbNodeRunTestCase(testCases[i])
+		if err := gorpmapping.Insert(db, &dbTestCase); err != nil {
+			return sdk.WrapError(err, "unable to insert test case %s", testCases[i].Name)
+		}
+		testCases[i].ID = dbTestCase.ID
+	}
+	return nil
+}
+
+// LoadTestCasesByWorkflow returns the test case results of the workflow runs with a number
+// greater than given number, most recent first and at most limit results. Results can be filtered by branch and node name.
+func LoadTestCasesByWorkflow(ctx context.Context, db gorp.SqlExecutor, projectKey, workflowName, branch, nodeName string, fromNumber int64, limit int) ([]sdk.WorkflowNodeRunTestCase, error) {
+	query := gorpmapping.NewQuery(`
+		SELECT workflow_node_run_test_case.* FROM workflow_node_run_test_case
+		JOIN workflow ON workflow.id = workflow_node_run_test_case.workflow_id
+		JOIN project ON project.id = workflow.project_id
+		WHERE project.projectkey = $1 AND workflow.name = $2
+		AND ($3 = '' OR workflow_node_run_test_case.branch = $3)
+		AND ($4 = '' OR workflow_node_run_test_case.node_name = $4)
+		AND workflow_node_run_test_case.run_number > $5
+		ORDER BY workflow_node_run_test_case.run_number DESC, workflow_node_run_test_case.id DESC
+		LIMIT $6
+	`).Args(projectKey, workflowName, branch, nodeName, fromNumber, limit)
+	return getAllTestCases(ctx, db, query)
+}
+
+// LoadTestCaseHistory returns the last results of a test case, most recent first.
+func LoadTestCaseHistory(ctx context.Context, db gorp.SqlExecutor, projectKey, workflowName, nodeName, suite, name, branch string, limit int) ([]sdk.WorkflowNodeRunTestCase, error) {
+	query := gorpmapping.NewQuery(`
+		SELECT workflow_node_run_test_case.* FROM workflow_node_run_test_case
+		JOIN workflow ON workflow.id = workflow_node_run_test_case.workflow_id
+		JOIN project ON project.id = workflow.project_id
+		WHERE project.projectkey = $1 AND workflow.name = $2
+		AND workflow_node_run_test_case.node_name = $3
+		AND workflow_node_run_test_case.suite = $4
+		AND workflow_node_run_test_case.name = $5
+		AND ($6 = '' OR workflow_node_run_test_case.branch = $6)
+		ORDER BY workflow_node_run_test_case.run_number DESC, workflow_node_run_test_case.id DESC
+		LIMIT $7
+	`).Args(projectKey, workflowName, nodeName, suite, name, branch, limit)
+	return getAllTestCases(ctx, db, query)
+}
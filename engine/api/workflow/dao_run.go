@@ -23,6 +23,9 @@ import (
 	"github.com/ovh/cds/sdk/telemetry"
 )
 
+// RunsReplicaStaleness is the replication lag tolerated when workflow runs are listed from the database replica.
+const RunsReplicaStaleness = 5 * time.Second
+
 const wfRunfields string = `
 workflow_run.id,
 workflow_run.num,
@@ -594,15 +597,20 @@ func InsertRunNum(db gorp.SqlExecutor, w *sdk.Workflow, num int64) error {
 	return nil
 }
 
-func LoadCratingWorkflowRunIDs(db gorp.SqlExecutor) ([]int64, error) {
+// LoadCratingWorkflowRunIDs returns the ids of the workflow runs to craft that belong to given shards, a run belongs
+// to the shard whose index is its id modulo the total number of shards.
+func LoadCratingWorkflowRunIDs(db gorp.SqlExecutor, shards []int64, totalShards int) ([]int64, error) {
+	if totalShards < 1 {
+		totalShards = 1
+	}
 	query := `
 		SELECT id
 		FROM workflow_run
-		WHERE to_craft = true
+		WHERE to_craft = true AND id % $1 = ANY($2)
 		LIMIT 10
 	`
 	var ids []int64
-	_, err := db.Select(&ids, query)
+	_, err := db.Select(&ids, query, totalShards, pq.Int64Array(shards))
 	if err != nil {
 		return nil, sdk.WrapError(err, "unable to load crafting workflow runs")
 	}
@@ -767,6 +775,10 @@ func PurgeWorkflowRun(ctx context.Context, db gorp.SqlExecutor, wf sdk.Workflow)
 		Ids string `json:"ids" db:"ids"`
 	}{}
 
+	if !wf.PurgePolicy.IsEmpty() {
+		return purgeWorkflowRunWithPolicy(ctx, db, wf)
+	}
+
 	if wf.HistoryLength == 0 {
 		log.Debug("PurgeWorkflowRun> history length equals 0, skipping purge")
 		return nil
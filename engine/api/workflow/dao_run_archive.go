@@ -0,0 +1,81 @@
+package workflow
+
+import (
+	"context"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/database/gorpmapping"
+	"github.com/ovh/cds/sdk"
+)
+
+// InsertRunArchive inserts a workflow run archive reference, an existing reference for the same run is replaced.
+func InsertRunArchive(db gorp.SqlExecutor, a *sdk.WorkflowRunArchive) error {
+	if _, err := db.Exec("DELETE FROM workflow_run_archive WHERE workflow_id = $1 AND num = $2", a.WorkflowID, a.Number); err != nil {
+		return sdk.WrapError(err, "cannot delete workflow run archive %d for workflow %d", a.Number, a.WorkflowID)
+	}
+	dbArchive := dbRunArchive(*a)
+	if err := gorpmapping.Insert(db, &dbArchive); err != nil {
+		return sdk.WrapError(err, "cannot insert workflow run archive %d for workflow %d", a.Number, a.WorkflowID)
+	}
+	*a = sdk.WorkflowRunArchive(dbArchive)
+	return nil
+}
+
+// LoadRunArchives returns the archived runs of a workflow, latest first.
+func LoadRunArchives(ctx context.Context, db gorp.SqlExecutor, workflowID, offset, limit int64) ([]sdk.WorkflowRunArchive, error) {
+	if limit <= 0 {
+		limit = 50
+	}
+	query := gorpmapping.NewQuery(`
+		SELECT * FROM workflow_run_archive
+		WHERE workflow_id = $1
+		ORDER BY num DESC, archived DESC
+		OFFSET $2 LIMIT $3
+	`).Args(workflowID, offset, limit)
+	var dbArchives []dbRunArchive
+	if err := gorpmapping.GetAll(ctx, db, query, &dbArchives); err != nil {
+		return nil, sdk.WrapError(err, "cannot get archived runs for workflow %d", workflowID)
+	}
+	archives := make([]sdk.WorkflowRunArchive, len(dbArchives))
+	for i := range dbArchives {
+		archives[i] = sdk.WorkflowRunArchive(dbArchives[i])
+	}
+	return archives, nil
+}
+
+// LoadRunArchiveByNumber returns the archived run of a workflow with given number.
+func LoadRunArchiveByNumber(ctx context.Context, db gorp.SqlExecutor, workflowID, number int64) (*sdk.WorkflowRunArchive, error) {
+	query := gorpmapping.NewQuery(`
+		SELECT * FROM workflow_run_archive
+		WHERE workflow_id = $1 AND num = $2
+	`).Args(workflowID, number)
+	var dbArchive dbRunArchive
+	found, err := gorpmapping.Get(ctx, db, query, &dbArchive)
+	if err != nil {
+		return nil, sdk.WrapError(err, "cannot get archived run %d for workflow %d", number, workflowID)
+	}
+	if !found {
+		return nil, sdk.WithStack(sdk.ErrNotFound)
+	}
+	a := sdk.WorkflowRunArchive(dbArchive)
+	return &a, nil
+}
+
+// LoadAllRunArchives returns all the archived runs of a workflow.
+func LoadAllRunArchives(ctx context.Context, db gorp.SqlExecutor, workflowID int64) ([]sdk.WorkflowRunArchive, error) {
+	query := gorpmapping.NewQuery(`
+		SELECT * FROM workflow_run_archive
+		WHERE workflow_id = $1
+		ORDER BY num
+	`).Args(workflowID)
+	var dbArchives []dbRunArchive
+	if err := gorpmapping.GetAll(ctx, db, query, &dbArchives); err != nil {
+		return nil, sdk.WrapError(err, "cannot get archived runs for workflow %d", workflowID)
+	}
+	archives := make([]sdk.WorkflowRunArchive, len(dbArchives))
+	for i := range dbArchives {
+		archives[i] = sdk.WorkflowRunArchive(dbArchives[i])
+	}
+	return archives, nil
+}
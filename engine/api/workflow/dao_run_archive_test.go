@@ -0,0 +1,72 @@
+package workflow_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ovh/cds/engine/api/bootstrap"
+	"github.com/ovh/cds/engine/api/test"
+	"github.com/ovh/cds/engine/api/test/assets"
+	"github.com/ovh/cds/engine/api/workflow"
+	"github.com/ovh/cds/sdk"
+)
+
+func TestInsertRunArchive(t *testing.T) {
+	db, cache := test.SetupPG(t, bootstrap.InitiliazeDB)
+
+	key := sdk.RandomString(10)
+	proj := assets.InsertTestProject(t, db, cache, key, key)
+	wf := assets.InsertTestWorkflow(t, db, cache, proj, "my-workflow")
+	workflowID := wf.ID
+
+	for i := int64(1); i <= 3; i++ {
+		a := sdk.WorkflowRunArchive{
+			ProjectKey:   key,
+			WorkflowID:   workflowID,
+			WorkflowName: "my-workflow",
+			Number:       i,
+			Status:       sdk.StatusSuccess,
+			Start:        time.Now(),
+			LastModified: time.Now(),
+			Archived:     time.Now(),
+		}
+		require.NoError(t, workflow.InsertRunArchive(db, &a))
+		require.NotZero(t, a.ID)
+	}
+
+	// Archiving the same run again replaces the previous reference
+	a := sdk.WorkflowRunArchive{
+		ProjectKey:   key,
+		WorkflowID:   workflowID,
+		WorkflowName: "my-workflow",
+		Number:       2,
+		Status:       sdk.StatusFail,
+		Start:        time.Now(),
+		LastModified: time.Now(),
+		Archived:     time.Now(),
+	}
+	require.NoError(t, workflow.InsertRunArchive(db, &a))
+
+	archives, err := workflow.LoadRunArchives(context.TODO(), db, workflowID, 0, 10)
+	require.NoError(t, err)
+	require.Len(t, archives, 3)
+	require.Equal(t, int64(3), archives[0].Number)
+	require.Equal(t, int64(1), archives[2].Number)
+
+	res, err := workflow.LoadRunArchiveByNumber(context.TODO(), db, workflowID, 2)
+	require.NoError(t, err)
+	require.Equal(t, sdk.StatusFail, res.Status)
+
+	_, err = workflow.LoadRunArchiveByNumber(context.TODO(), db, workflowID, 4)
+	require.True(t, sdk.ErrorIs(err, sdk.ErrNotFound))
+
+	// Archives are deleted with their workflow
+	_, err = db.Exec("DELETE FROM workflow WHERE id = $1", workflowID)
+	require.NoError(t, err)
+	archives, err = workflow.LoadAllRunArchives(context.TODO(), db, workflowID)
+	require.NoError(t, err)
+	require.Empty(t, archives)
+}
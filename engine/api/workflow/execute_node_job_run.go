@@ -8,6 +8,8 @@ import (
 	"time"
 
 	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/usage"
 	"github.com/ovh/cds/engine/cache"
 	"github.com/ovh/cds/engine/gorpmapper"
 	"github.com/ovh/cds/sdk"
@@ -149,7 +151,9 @@ func UpdateNodeJobRunStatus(ctx context.Context, db gorpmapper.SqlExecutorWithTx
 		job.Status = status
 
 	case sdk.StatusFail, sdk.StatusSuccess, sdk.StatusDisabled, sdk.StatusSkipped, sdk.StatusStopped:
-		if currentStatus != sdk.StatusWaiting && currentStatus != sdk.StatusBuilding && status != sdk.StatusDisabled && status != sdk.StatusSkipped {
+		// Pending job runs of a job matrix can only be stopped
+		isPendingStopped := currentStatus == sdk.StatusPending && status == sdk.StatusStopped
+		if currentStatus != sdk.StatusWaiting && currentStatus != sdk.StatusBuilding && status != sdk.StatusDisabled && status != sdk.StatusSkipped && !isPendingStopped {
 			log.Debug("workflow.UpdateNodeJobRunStatus> Status is %s, cannot update %d to %s", currentStatus, job.ID, status)
 			// too late, Nate
 			return nil, nil
@@ -170,6 +174,12 @@ func UpdateNodeJobRunStatus(ctx context.Context, db gorpmapper.SqlExecutorWithTx
 		if err := UpdateWorkflowRun(ctx, db, wf); err != nil {
 			return nil, sdk.WrapError(err, "Cannot update WorkflowRun %d", wf.ID)
 		}
+
+		// Record the usage of the terminated job, it will be replaced by the one reported with the job result if any
+		u := usage.NewJobUsage(wf.ProjectID, *job, nil, nil)
+		if err := usage.InsertJobUsage(db, &u); err != nil {
+			return nil, err
+		}
 	default:
 		return nil, sdk.WithStack(fmt.Errorf("cannot update WorkflowNodeJobRun %d to status %v", job.ID, status))
 	}
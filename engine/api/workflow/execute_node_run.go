@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"reflect"
 	"strings"
 	"sync"
 	"time"
@@ -13,6 +14,7 @@ import (
 	"github.com/sirupsen/logrus"
 
 	"github.com/ovh/cds/engine/api/action"
+	"github.com/ovh/cds/engine/api/dora"
 	"github.com/ovh/cds/engine/cache"
 	"github.com/ovh/cds/engine/api/group"
 	"github.com/ovh/cds/engine/api/plugin"
@@ -219,8 +221,9 @@ func executeNodeRun(ctx context.Context, db gorpmapper.SqlExecutorWithTx, store
 			var end bool
 
 			_, next := telemetry.Span(ctx, "workflow.syncStage")
-			end, errSync := syncStage(ctx, db, store, stage)
+			r, end, errSync := syncStage(ctx, db, store, stage)
 			next()
+			report.Merge(ctx, r)
 			if errSync != nil {
 				return report, errSync
 			}
@@ -305,6 +308,16 @@ func executeNodeRun(ctx context.Context, db gorpmapper.SqlExecutorWithTx, store
 
 		// If current node has a mutex, we want to trigger another node run that can be waiting for the mutex
 		node := updatedWorkflowRun.Workflow.WorkflowData.NodeByID(workflowNodeRun.WorkflowNodeID)
+
+		// Keep track of the deployments for DORA metrics
+		isDeployment := node != nil && node.Context != nil && node.Context.ApplicationID != 0 && node.Context.EnvironmentID != 0
+		if isDeployment && dora.IsDeployment(workflowNodeRun.Status) {
+			d := dora.NewDeployment(proj.ID, updatedWorkflowRun.WorkflowID, node.Context.ApplicationID, node.Context.EnvironmentID, *workflowNodeRun)
+			if err := dora.InsertDeployment(db, &d); err != nil {
+				return nil, err
+			}
+		}
+
 		hasMutex := node != nil && node.Context != nil && node.Context.Mutex
 		if hasMutex {
 			r, err := releaseMutex(ctx, db, store, proj, updatedWorkflowRun.WorkflowID, workflowNodeRun.WorkflowNodeName)
@@ -448,123 +461,153 @@ func addJobsToQueue(ctx context.Context, db gorp.SqlExecutor, stage *sdk.Stage,
 
 	skippedOrDisabledJobs := 0
 	failedJobs := 0
+	nbJobs := 0
 	//Browse the jobs
-jobLoop:
 	for j := range stage.Jobs {
 		job := &stage.Jobs[j]
 
-		if previousStage != nil {
-			for _, rj := range previousStage.RunJobs {
-				if rj.Job.PipelineActionID == job.PipelineActionID && rj.Status != sdk.StatusFail && sdk.StatusIsTerminated(rj.Status) {
-					stage.RunJobs = append(stage.RunJobs, rj)
-					continue jobLoop
+		// A job with a matrix gives a job run for each combination of the matrix
+		combinations := []map[string]string{nil}
+		if job.Matrix != nil {
+			combinations = job.Matrix.Combinations()
+		}
+		nbJobs += len(combinations)
+		queuedCombinations := 0
+
+	combinationLoop:
+		for _, matrixValues := range combinations {
+			if previousStage != nil {
+				for _, rj := range previousStage.RunJobs {
+					if rj.Job.PipelineActionID == job.PipelineActionID && reflect.DeepEqual(rj.Job.MatrixValues, matrixValues) &&
+						rj.Status != sdk.StatusFail && sdk.StatusIsTerminated(rj.Status) {
+						stage.RunJobs = append(stage.RunJobs, rj)
+						continue combinationLoop
+					}
 				}
 			}
-		}
 
-		// errors generated in the loop will be added to job run spawn info
-		spawnErrs := sdk.MultiError{}
+			// errors generated in the loop will be added to job run spawn info
+			spawnErrs := sdk.MultiError{}
 
-		//Process variables for the jobs
-		_, next = telemetry.Span(ctx, "workflow..getNodeJobRunParameters")
-		jobParams, err := getNodeJobRunParameters(*job, nr, stage)
-		next()
-		if err != nil {
-			spawnErrs.Join(*err)
-		}
+			//Process variables for the jobs
+			_, next = telemetry.Span(ctx, "workflow..getNodeJobRunParameters")
+			jobParams, err := getNodeJobRunParameters(*job, nr, stage)
+			next()
+			if err != nil {
+				spawnErrs.Join(*err)
+			}
 
-		_, next = telemetry.Span(ctx, "workflow.processNodeJobRunRequirements")
-		jobRequirements, containsService, wm, err := processNodeJobRunRequirements(ctx, db, *job, nr, sdk.Groups(groups).ToIDs(), integrationPluginBinaries)
-		next()
-		if err != nil {
-			spawnErrs.Join(*err)
-		}
+			_, next = telemetry.Span(ctx, "workflow.processNodeJobRunRequirements")
+			jobRequirements, containsService, wm, err := processNodeJobRunRequirements(ctx, db, *job, nr, sdk.Groups(groups).ToIDs(), integrationPluginBinaries)
+			next()
+			if err != nil {
+				spawnErrs.Join(*err)
+			}
 
-		// check that children actions used by job can be used by the project
-		if err := action.CheckChildrenForGroupIDsWithLoop(ctx, db, &job.Action, sdk.Groups(groups).ToIDs()); err != nil {
-			spawnErrs.Append(err)
-		}
+			// check that children actions used by job can be used by the project
+			if err := action.CheckChildrenForGroupIDsWithLoop(ctx, db, &job.Action, sdk.Groups(groups).ToIDs()); err != nil {
+				spawnErrs.Append(err)
+			}
 
-		// add requirements in job parameters, to use them as {{.job.requirement...}} in job
-		_, next = telemetry.Span(ctx, "workflow.prepareRequirementsToNodeJobRunParameters")
-		jobParams = append(jobParams, prepareRequirementsToNodeJobRunParameters(jobRequirements)...)
-		next()
-
-		//Create the job run
-		wjob := sdk.WorkflowNodeJobRun{
-			ProjectID:                 wr.ProjectID,
-			WorkflowNodeRunID:         nr.ID,
-			Start:                     time.Time{},
-			Queued:                    time.Now(),
-			Status:                    sdk.StatusWaiting,
-			Parameters:                jobParams,
-			ExecGroups:                groups,
-			IntegrationPluginBinaries: integrationPluginBinaries,
-			Job: sdk.ExecutedJob{
-				Job: *job,
-			},
-			Header:          nr.Header,
-			ContainsService: containsService,
-		}
-		if wm != nil {
-			wjob.ModelType = wm.Type
-		}
-		wjob.Job.Job.Action.Requirements = jobRequirements // Set the interpolated requirements on the job run only
-
-		if !stage.Enabled || !wjob.Job.Enabled {
-			wjob.Status = sdk.StatusDisabled
-			skippedOrDisabledJobs++
-		} else if !conditionsOK {
-			wjob.Status = sdk.StatusSkipped
-			skippedOrDisabledJobs++
-		}
+			// add requirements in job parameters, to use them as {{.job.requirement...}} in job
+			_, next = telemetry.Span(ctx, "workflow.prepareRequirementsToNodeJobRunParameters")
+			jobParams = append(jobParams, prepareRequirementsToNodeJobRunParameters(jobRequirements)...)
+			next()
+
+			// add matrix values in job parameters, to use them as {{.cds.matrix...}} in job
+			for k, v := range matrixValues {
+				sdk.AddParameter(&jobParams, "cds.matrix."+k, sdk.StringParameter, v)
+			}
+
+			//Create the job run
+			wjob := sdk.WorkflowNodeJobRun{
+				ProjectID:                 wr.ProjectID,
+				WorkflowNodeRunID:         nr.ID,
+				Start:                     time.Time{},
+				Queued:                    time.Now(),
+				Status:                    sdk.StatusWaiting,
+				Parameters:                jobParams,
+				ExecGroups:                groups,
+				IntegrationPluginBinaries: integrationPluginBinaries,
+				Job: sdk.ExecutedJob{
+					Job: *job,
+				},
+				Header:          nr.Header,
+				ContainsService: containsService,
+			}
+			if wm != nil {
+				wjob.ModelType = wm.Type
+			}
+			wjob.Job.Job.Action.Requirements = jobRequirements // Set the interpolated requirements on the job run only
+			if matrixValues != nil {
+				wjob.Job.MatrixValues = matrixValues
+				wjob.Job.Job.Action.Name = sdk.MatrixJobName(job.Action.Name, matrixValues)
+			}
+
+			if !stage.Enabled || !wjob.Job.Enabled {
+				wjob.Status = sdk.StatusDisabled
+				skippedOrDisabledJobs++
+			} else if !conditionsOK {
+				wjob.Status = sdk.StatusSkipped
+				skippedOrDisabledJobs++
+			}
 
-		// If there is any error in the previous operation, mark the job as failed
-		if !spawnErrs.IsEmpty() {
-			failedJobs++
-			wjob.Status = sdk.StatusFail
+			// If there is any error in the previous operation, mark the job as failed
+			if !spawnErrs.IsEmpty() {
+				failedJobs++
+				wjob.Status = sdk.StatusFail
 
-			for _, e := range spawnErrs {
-				msg := sdk.SpawnMsg{
-					ID: sdk.MsgSpawnInfoJobError.ID,
+				for _, e := range spawnErrs {
+					msg := sdk.SpawnMsg{
+						ID: sdk.MsgSpawnInfoJobError.ID,
+					}
+					msg.Args = []interface{}{sdk.ExtractHTTPError(e, "").Error()}
+					wjob.SpawnInfos = append(wjob.SpawnInfos, sdk.SpawnInfo{
+						APITime:     time.Now(),
+						Message:     msg,
+						RemoteTime:  time.Now(),
+						UserMessage: msg.DefaultUserMessage(),
+					})
 				}
-				msg.Args = []interface{}{sdk.ExtractHTTPError(e, "").Error()}
-				wjob.SpawnInfos = append(wjob.SpawnInfos, sdk.SpawnInfo{
+			} else {
+				sp := sdk.SpawnMsg{ID: sdk.MsgSpawnInfoJobInQueue.ID}
+				// Combinations over the max parallel limit of the matrix will be queued by syncStage
+				if wjob.Status == sdk.StatusWaiting && job.Matrix != nil && job.Matrix.MaxParallel > 0 {
+					if queuedCombinations >= job.Matrix.MaxParallel {
+						wjob.Status = sdk.StatusPending
+						sp = sdk.SpawnMsg{ID: sdk.MsgSpawnInfoJobMatrixPending.ID, Args: []interface{}{job.Matrix.MaxParallel}}
+					} else {
+						queuedCombinations++
+					}
+				}
+				wjob.SpawnInfos = []sdk.SpawnInfo{{
 					APITime:     time.Now(),
-					Message:     msg,
+					Message:     sp,
 					RemoteTime:  time.Now(),
-					UserMessage: msg.DefaultUserMessage(),
-				})
+					UserMessage: sp.DefaultUserMessage(),
+				}}
 			}
-		} else {
-			sp := sdk.SpawnMsg{ID: sdk.MsgSpawnInfoJobInQueue.ID}
-			wjob.SpawnInfos = []sdk.SpawnInfo{{
-				APITime:     time.Now(),
-				Message:     sp,
-				RemoteTime:  time.Now(),
-				UserMessage: sp.DefaultUserMessage(),
-			}}
-		}
 
-		// insert in database
-		_, next = telemetry.Span(ctx, "workflow.insertWorkflowNodeJobRun")
-		if err := insertWorkflowNodeJobRun(db, &wjob); err != nil {
+			// insert in database
+			_, next = telemetry.Span(ctx, "workflow.insertWorkflowNodeJobRun")
+			if err := insertWorkflowNodeJobRun(db, &wjob); err != nil {
+				next()
+				return report, sdk.WrapError(err, "unable to insert in table workflow_node_run_job")
+			}
 			next()
-			return report, sdk.WrapError(err, "unable to insert in table workflow_node_run_job")
-		}
-		next()
 
-		if err := AddSpawnInfosNodeJobRun(db, wjob.WorkflowNodeRunID, wjob.ID, PrepareSpawnInfos(wjob.SpawnInfos)); err != nil {
-			return nil, sdk.WrapError(err, "cannot save spawn info job %d", wjob.ID)
-		}
+			if err := AddSpawnInfosNodeJobRun(db, wjob.WorkflowNodeRunID, wjob.ID, PrepareSpawnInfos(wjob.SpawnInfos)); err != nil {
+				return nil, sdk.WrapError(err, "cannot save spawn info job %d", wjob.ID)
+			}
 
-		//Put the job run in database
-		stage.RunJobs = append(stage.RunJobs, wjob)
+			//Put the job run in database
+			stage.RunJobs = append(stage.RunJobs, wjob)
 
-		report.Add(ctx, wjob)
+			report.Add(ctx, wjob)
+		}
 	}
 
-	if skippedOrDisabledJobs == len(stage.Jobs) {
+	if skippedOrDisabledJobs == nbJobs {
 		stage.Status = sdk.StatusSkipped
 	}
 
@@ -619,7 +662,8 @@ func getExecutablesGroups(wr *sdk.WorkflowRun, nr *sdk.WorkflowNodeRun) ([]sdk.G
 	return groups, nil
 }
 
-func syncStage(ctx context.Context, db gorp.SqlExecutor, store cache.Store, stage *sdk.Stage) (bool, error) {
+func syncStage(ctx context.Context, db gorp.SqlExecutor, store cache.Store, stage *sdk.Stage) (*ProcessorReport, bool, error) {
+	report := new(ProcessorReport)
 	stageEnd := true
 	finalStatus := sdk.StatusBuilding
 
@@ -631,7 +675,7 @@ func syncStage(ctx context.Context, db gorp.SqlExecutor, store cache.Store, stag
 		if runJob.Status == sdk.StatusBuilding || runJob.Status == sdk.StatusWaiting {
 			runJobDB, errJob := LoadNodeJobRun(ctx, db, store, runJob.ID)
 			if errJob != nil {
-				return stageEnd, errJob
+				return report, stageEnd, errJob
 			}
 
 			if runJobDB.Status == sdk.StatusBuilding || runJobDB.Status == sdk.StatusWaiting {
@@ -639,7 +683,7 @@ func syncStage(ctx context.Context, db gorp.SqlExecutor, store cache.Store, stag
 			}
 			spawnInfos, err := LoadNodeRunJobInfo(ctx, db, runJob.ID)
 			if err != nil {
-				return false, sdk.WrapError(err, "unable to load spawn infos for runJob: %d", runJob.ID)
+				return report, false, sdk.WrapError(err, "unable to load spawn infos for runJob: %d", runJob.ID)
 			}
 			runJob.SpawnInfos = spawnInfos
 
@@ -660,6 +704,20 @@ func syncStage(ctx context.Context, db gorp.SqlExecutor, store cache.Store, stag
 			}
 		}
 	}
+
+	// Queue pending matrix combinations if running ones are under the max parallel limit
+	queued, err := queuePendingMatrixJobs(ctx, db, store, stage)
+	if err != nil {
+		return report, stageEnd, err
+	}
+	for i := range queued {
+		report.Add(ctx, queued[i])
+	}
+	for indexJob := range stage.RunJobs {
+		if stage.RunJobs[indexJob].Status == sdk.StatusPending || stage.RunJobs[indexJob].Status == sdk.StatusWaiting {
+			stageEnd = false
+		}
+	}
 	log.Debug("syncStage> stage %s stageEnd:%t len(stage.RunJobs):%d", stage.Name, stageEnd, len(stage.RunJobs))
 
 	if stageEnd || len(stage.RunJobs) == 0 {
@@ -693,7 +751,65 @@ func syncStage(ctx context.Context, db gorp.SqlExecutor, store cache.Store, stag
 	}
 	log.Debug("syncStage> set stage %s from %s to %s", stage.Name, stage.Status, finalStatus)
 	stage.Status = finalStatus
-	return stageEnd, nil
+	return report, stageEnd, nil
+}
+
+// queuePendingMatrixJobs sets pending job runs of a job matrix to waiting according to the max parallel
+// limit of the matrix. It returns the job runs that were queued.
+func queuePendingMatrixJobs(ctx context.Context, db gorp.SqlExecutor, store cache.Store, stage *sdk.Stage) ([]sdk.WorkflowNodeJobRun, error) {
+	var queued []sdk.WorkflowNodeJobRun
+	for _, job := range stage.Jobs {
+		if job.Matrix == nil || job.Matrix.MaxParallel <= 0 {
+			continue
+		}
+
+		var running int
+		for _, runJob := range stage.RunJobs {
+			if runJob.Job.PipelineActionID == job.PipelineActionID && (runJob.Status == sdk.StatusWaiting || runJob.Status == sdk.StatusBuilding) {
+				running++
+			}
+		}
+
+		for indexJob := range stage.RunJobs {
+			if running >= job.Matrix.MaxParallel {
+				break
+			}
+			runJob := &stage.RunJobs[indexJob]
+			if runJob.Job.PipelineActionID != job.PipelineActionID || runJob.Status != sdk.StatusPending {
+				continue
+			}
+
+			runJobDB, err := LoadAndLockNodeJobRunWait(ctx, db, store, runJob.ID)
+			if err != nil {
+				return nil, err
+			}
+			// The job run could have been stopped
+			if runJobDB.Status != sdk.StatusPending {
+				runJob.Status = runJobDB.Status
+				continue
+			}
+
+			runJobDB.Status = sdk.StatusWaiting
+			runJobDB.Queued = time.Now()
+			if err := UpdateNodeJobRun(ctx, db, runJobDB); err != nil {
+				return nil, sdk.WrapError(err, "cannot queue job %d", runJobDB.ID)
+			}
+			sp := sdk.SpawnMsg{ID: sdk.MsgSpawnInfoJobInQueue.ID}
+			if err := AddSpawnInfosNodeJobRun(db, runJobDB.WorkflowNodeRunID, runJobDB.ID, []sdk.SpawnInfo{{
+				RemoteTime:  time.Now(),
+				Message:     sp,
+				UserMessage: sp.DefaultUserMessage(),
+			}}); err != nil {
+				return nil, err
+			}
+
+			runJob.Status = runJobDB.Status
+			runJob.Queued = runJobDB.Queued
+			queued = append(queued, *runJobDB)
+			running++
+		}
+	}
+	return queued, nil
 }
 
 // NodeBuildParametersFromRun return build parameters from previous workflow run
@@ -230,6 +230,12 @@ func (e dbWorkflowRunSecret) Canonical() gorpmapper.CanonicalForms {
 
 type dbAsCodeEvents sdk.AsCodeEvent
 
+type dbNodeRunAnalysis sdk.WorkflowNodeRunAnalysis
+
+type dbNodeRunTestCase sdk.WorkflowNodeRunTestCase
+
+type dbRunArchive sdk.WorkflowRunArchive
+
 func init() {
 	gorpmapping.Register(gorpmapping.New(Workflow{}, "workflow", true, "id"))
 	gorpmapping.Register(gorpmapping.New(Run{}, "workflow_run", true, "id"))
@@ -244,6 +250,9 @@ func init() {
 	gorpmapping.Register(gorpmapping.New(Coverage{}, "workflow_node_run_coverage", false, "workflow_id", "workflow_run_id", "workflow_node_run_id", "repository", "branch"))
 	gorpmapping.Register(gorpmapping.New(dbStaticFiles{}, "workflow_node_run_static_files", true, "id"))
 	gorpmapping.Register(gorpmapping.New(dbNodeRunVulenrabilitiesReport{}, "workflow_node_run_vulnerability", true, "id"))
+	gorpmapping.Register(gorpmapping.New(dbNodeRunAnalysis{}, "workflow_node_run_analysis", true, "id"))
+	gorpmapping.Register(gorpmapping.New(dbNodeRunTestCase{}, "workflow_node_run_test_case", true, "id"))
+	gorpmapping.Register(gorpmapping.New(dbRunArchive{}, "workflow_run_archive", true, "id"))
 	gorpmapping.Register(gorpmapping.New(dbNodeData{}, "w_node", true, "id"))
 	gorpmapping.Register(gorpmapping.New(dbNodeHookData{}, "w_node_hook", true, "id"))
 	gorpmapping.Register(gorpmapping.New(dbNodeContextData{}, "w_node_context", true, "id"))
@@ -41,6 +41,7 @@ func processWorkflowDataRun(ctx context.Context, db gorpmapper.SqlExecutorWithTx
 	if telemetry.Current(ctx).SpanContext().IsSampled() {
 		wr.Header.Set(telemetry.SampledHeader, "1")
 		wr.Header.Set(telemetry.TraceIDHeader, fmt.Sprintf("%v", telemetry.Current(ctx).SpanContext().TraceID))
+		wr.Header.Set(telemetry.SpanIDHeader, fmt.Sprintf("%v", telemetry.Current(ctx).SpanContext().SpanID))
 	}
 	//////
 
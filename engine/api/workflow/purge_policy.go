@@ -0,0 +1,95 @@
+package workflow
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// loadRunsPurgeCandidates returns the runs of a workflow that are not already marked to delete, latest first.
+func loadRunsPurgeCandidates(db gorp.SqlExecutor, workflowID int64) ([]sdk.WorkflowRunPurge, error) {
+	query := `
+		SELECT workflow_run.id, workflow_run.num, workflow_run.status, workflow_run.start, workflow_run.last_modified,
+			COALESCE((SELECT value FROM workflow_run_tag WHERE workflow_run_id = workflow_run.id AND tag = $2 LIMIT 1), '') AS branch,
+			COALESCE((SELECT value FROM workflow_run_tag WHERE workflow_run_id = workflow_run.id AND tag = $3 LIMIT 1), '') AS tag
+		FROM workflow_run
+		WHERE workflow_run.workflow_id = $1 AND workflow_run.to_delete = false
+		ORDER BY workflow_run.num DESC, workflow_run.id DESC`
+	var runs []sdk.WorkflowRunPurge
+	if _, err := db.Select(&runs, query, workflowID, tagGitBranch, tagGitTag); err != nil {
+		return nil, sdk.WrapError(err, "cannot load runs of workflow %d", workflowID)
+	}
+	return runs, nil
+}
+
+// computeRunsToPurge returns the runs that are not kept by the purge policy of the workflow, given runs should be
+// sorted latest first. Runs that are not terminated are never deleted.
+func computeRunsToPurge(wf sdk.Workflow, runs []sdk.WorkflowRunPurge, now time.Time) []sdk.WorkflowRunPurge {
+	p := wf.PurgePolicy
+	hasKeepRule := wf.HistoryLength > 0 || p.KeepLastPerBranch > 0 || p.KeepDays > 0 || p.KeepReleases
+
+	var res []sdk.WorkflowRunPurge
+	var rank int64
+	var rankByBranch = make(map[string]int64)
+	for _, r := range runs {
+		if !sdk.StatusIsTerminated(r.Status) {
+			continue
+		}
+
+		rank++
+		rankByBranch[r.Branch]++
+		age := now.Sub(r.LastModified)
+
+		switch {
+		case p.KeepReleases && r.Tag != "":
+		case p.DeleteFailedAfterDays > 0 && (r.Status == sdk.StatusFail || r.Status == sdk.StatusStopped) &&
+			age > time.Duration(p.DeleteFailedAfterDays)*24*time.Hour:
+			r.Reason = fmt.Sprintf("%s run older than %d days", r.Status, p.DeleteFailedAfterDays)
+			res = append(res, r)
+		case !hasKeepRule:
+		case p.KeepDays > 0 && age <= time.Duration(p.KeepDays)*24*time.Hour:
+		case p.KeepLastPerBranch > 0 && rankByBranch[r.Branch] <= p.KeepLastPerBranch:
+		case wf.HistoryLength > 0 && rank <= wf.HistoryLength:
+		default:
+			r.Reason = "not kept by purge policy"
+			res = append(res, r)
+		}
+	}
+
+	return res
+}
+
+// ListRunsToPurge returns the runs of a workflow that would be deleted by its purge policy.
+func ListRunsToPurge(ctx context.Context, db gorp.SqlExecutor, wf sdk.Workflow) ([]sdk.WorkflowRunPurge, error) {
+	if wf.PurgePolicy.IsEmpty() {
+		return nil, sdk.NewErrorFrom(sdk.ErrWrongRequest, "workflow %s has no purge policy", wf.Name)
+	}
+	runs, err := loadRunsPurgeCandidates(db, wf.ID)
+	if err != nil {
+		return nil, err
+	}
+	return computeRunsToPurge(wf, runs, time.Now()), nil
+}
+
+func purgeWorkflowRunWithPolicy(ctx context.Context, db gorp.SqlExecutor, wf sdk.Workflow) error {
+	runs, err := ListRunsToPurge(ctx, db, wf)
+	if err != nil {
+		return err
+	}
+	if len(runs) == 0 {
+		return nil
+	}
+
+	ids := make([]int64, len(runs))
+	for i := range runs {
+		ids[i] = runs[i].ID
+	}
+	log.Debug("purgeWorkflowRunWithPolicy> mark %d runs to delete for workflow %d", len(ids), wf.ID)
+
+	return MarkWorkflowRunsAsDelete(db, ids)
+}
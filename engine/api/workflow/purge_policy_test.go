@@ -0,0 +1,81 @@
+package workflow
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func TestComputeRunsToPurge(t *testing.T) {
+	now := time.Now()
+	day := 24 * time.Hour
+	runs := []sdk.WorkflowRunPurge{
+		{ID: 8, Number: 8, Status: sdk.StatusBuilding, Branch: "master", LastModified: now},
+		{ID: 7, Number: 7, Status: sdk.StatusSuccess, Branch: "master", LastModified: now.Add(-1 * day)},
+		{ID: 6, Number: 6, Status: sdk.StatusFail, Branch: "feat", LastModified: now.Add(-2 * day)},
+		{ID: 5, Number: 5, Status: sdk.StatusSuccess, Branch: "master", LastModified: now.Add(-3 * day)},
+		{ID: 4, Number: 4, Status: sdk.StatusSuccess, Branch: "feat", LastModified: now.Add(-4 * day)},
+		{ID: 3, Number: 3, Status: sdk.StatusSuccess, Branch: "master", Tag: "v1.0.0", LastModified: now.Add(-10 * day)},
+		{ID: 2, Number: 2, Status: sdk.StatusSuccess, Branch: "master", LastModified: now.Add(-11 * day)},
+		{ID: 1, Number: 1, Status: sdk.StatusStopped, Branch: "feat", LastModified: now.Add(-12 * day)},
+	}
+
+	ids := func(rs []sdk.WorkflowRunPurge) []int64 {
+		var res []int64
+		for _, r := range rs {
+			res = append(res, r.ID)
+		}
+		return res
+	}
+
+	tests := []struct {
+		name     string
+		wf       sdk.Workflow
+		expected []int64
+	}{
+		{
+			name:     "history length only",
+			wf:       sdk.Workflow{HistoryLength: 3},
+			expected: []int64{4, 3, 2, 1},
+		},
+		{
+			name: "keep last per branch and releases",
+			wf: sdk.Workflow{PurgePolicy: sdk.WorkflowPurgePolicy{
+				KeepLastPerBranch: 1,
+				KeepReleases:      true,
+			}},
+			expected: []int64{5, 4, 2, 1},
+		},
+		{
+			name: "keep days",
+			wf: sdk.Workflow{PurgePolicy: sdk.WorkflowPurgePolicy{
+				KeepDays: 5,
+			}},
+			expected: []int64{3, 2, 1},
+		},
+		{
+			name: "delete failed only",
+			wf: sdk.Workflow{PurgePolicy: sdk.WorkflowPurgePolicy{
+				DeleteFailedAfterDays: 1,
+			}},
+			expected: []int64{6, 1},
+		},
+		{
+			name: "failed runs deleted even if recent enough to be kept",
+			wf: sdk.Workflow{PurgePolicy: sdk.WorkflowPurgePolicy{
+				KeepDays:              30,
+				DeleteFailedAfterDays: 5,
+			}},
+			expected: []int64{1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expected, ids(computeRunsToPurge(tt.wf, runs, now)))
+		})
+	}
+}
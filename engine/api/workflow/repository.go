@@ -35,6 +35,9 @@ type PushOption struct {
 	HookUUID           string
 	Force              bool
 	OldWorkflow        sdk.Workflow
+	// DisableHookManagement prevents hooks registration on the hooks service, it should be set when the push
+	// transaction will be rolled back.
+	DisableHookManagement bool
 }
 
 // CreateFromRepository a workflow from a repository.
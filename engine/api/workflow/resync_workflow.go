@@ -33,6 +33,16 @@ func Resync(ctx context.Context, db gorp.SqlExecutor, store cache.Store, proj sd
 		}
 	}
 
+	// Child pipelines added at run time are not in the workflow, they are kept
+	for _, n := range oldNode {
+		if n.ChildDepth > 0 && n.Context != nil {
+			if wf.Pipelines == nil {
+				wf.Pipelines = make(map[int64]sdk.Pipeline)
+			}
+			wf.Pipelines[n.Context.PipelineID] = wr.Workflow.Pipelines[n.Context.PipelineID]
+		}
+	}
+
 	//Resync map
 	wr.Workflow.Pipelines = wf.Pipelines
 	wr.Workflow.Applications = wf.Applications
@@ -13,6 +13,10 @@ import (
 
 //Import is able to create a new workflow and all its components
 func Import(ctx context.Context, db gorpmapper.SqlExecutorWithTx, store cache.Store, proj sdk.Project, oldW, w *sdk.Workflow, u sdk.Identifiable, force bool, msgChan chan<- sdk.Message) error {
+	return importWorkflow(ctx, db, store, proj, oldW, w, u, force, false, msgChan)
+}
+
+func importWorkflow(ctx context.Context, db gorpmapper.SqlExecutorWithTx, store cache.Store, proj sdk.Project, oldW, w *sdk.Workflow, u sdk.Identifiable, force, disableHookManagement bool, msgChan chan<- sdk.Message) error {
 	ctx, end := telemetry.Span(ctx, "workflow.Import")
 	defer end()
 
@@ -30,7 +34,7 @@ func Import(ctx context.Context, db gorpmapper.SqlExecutorWithTx, store cache.St
 
 	// create the workflow if not exists
 	if oldW == nil {
-		if err := Insert(ctx, db, store, proj, w); err != nil {
+		if err := insert(ctx, db, store, proj, w, UpdateOptions{DisableHookManagement: disableHookManagement}); err != nil {
 			return sdk.WrapError(err, "Unable to insert workflow")
 		}
 		if msgChan != nil {
@@ -74,7 +78,7 @@ func Import(ctx context.Context, db gorpmapper.SqlExecutorWithTx, store cache.St
 	// Hook registration must only be done on default branch in case of workflow as-code
 	// The derivation branch is set in workflow parser it is not coming from the default branch
 	uptOptions := UpdateOptions{
-		DisableHookManagement: w.DerivationBranch != "",
+		DisableHookManagement: w.DerivationBranch != "" || disableHookManagement,
 	}
 
 	if err := Update(ctx, db, store, proj, w, uptOptions); err != nil {
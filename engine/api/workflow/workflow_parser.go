@@ -4,6 +4,7 @@ import (
 	"context"
 	"sync"
 
+	"github.com/ovh/cds/engine/api/sharedpipeline"
 	"github.com/ovh/cds/engine/cache"
 	"github.com/ovh/cds/engine/gorpmapper"
 	"github.com/ovh/cds/sdk"
@@ -23,6 +24,8 @@ type ImportOptions struct {
 	RepositoryName     string
 	RepositoryStrategy sdk.RepositoryStrategy
 	HookUUID           string
+	// DisableHookManagement prevents hooks registration on the hooks service.
+	DisableHookManagement bool
 }
 
 // Parse parse an exportentities.workflow and return the parsed workflow
@@ -62,6 +65,11 @@ func ParseAndImport(ctx context.Context, db gorpmapper.SqlExecutorWithTx, store
 		return nil, nil, err
 	}
 
+	// Create or upgrade the project pipelines for nodes that reference a shared pipeline
+	if err := useSharedPipelines(ctx, db, store, proj, w, opts.Force, u); err != nil {
+		return nil, nil, err
+	}
+
 	// Load deep pipelines if we come from workflow run ( so we have hook uuid ).
 	// We need deep pipelines to be able to run stages/jobs
 	if err := CompleteWorkflow(ctx, db, w, proj, LoadOptions{DeepPipeline: opts.HookUUID != ""}); err != nil {
@@ -199,7 +207,7 @@ func ParseAndImport(ctx context.Context, db gorpmapper.SqlExecutorWithTx, store
 		}
 	}(&msgList)
 
-	globalError := Import(ctx, db, store, proj, oldW, w, u, opts.Force, msgChan)
+	globalError := importWorkflow(ctx, db, store, proj, oldW, w, u, opts.Force, opts.DisableHookManagement, msgChan)
 	close(msgChan)
 	done.Wait()
 
@@ -209,3 +217,33 @@ func ParseAndImport(ctx context.Context, db gorpmapper.SqlExecutorWithTx, store
 
 	return w, msgList, globalError
 }
+
+// useSharedPipelines replaces the shared pipeline references (group/name@version) in the workflow
+// nodes by the project pipelines created from them. Local changes of the project pipelines are only
+// overwritten when the workflow import is forced.
+func useSharedPipelines(ctx context.Context, db gorpmapper.SqlExecutorWithTx, store cache.Store, proj sdk.Project, w *sdk.Workflow, force bool, u sdk.Identifiable) error {
+	pips := make(map[string]*sdk.Pipeline)
+	var err error
+	w.VisitNode(func(n *sdk.Node, w *sdk.Workflow) {
+		if err != nil || n.Context == nil || !sdk.IsSharedPipelineRef(n.Context.PipelineName) {
+			return
+		}
+		ref := n.Context.PipelineName
+		pip, ok := pips[ref]
+		if !ok {
+			pip, err = sharedpipeline.Use(ctx, db, store, proj, ref, force, u)
+			if err != nil {
+				return
+			}
+			pips[ref] = pip
+		}
+		n.Context.PipelineName = pip.Name
+		n.Context.PipelineID = pip.ID
+		// Node name is the pipeline name if not given
+		if n.Name == ref {
+			n.Name = pip.Name
+			n.Ref = pip.Name
+		}
+	})
+	return err
+}
@@ -2,14 +2,20 @@ package workflow
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/go-gorp/gorp"
+	vault "github.com/hashicorp/vault/api"
 
 	"github.com/ovh/cds/engine/api/application"
 	"github.com/ovh/cds/engine/api/environment"
+	"github.com/ovh/cds/engine/api/integration"
 	"github.com/ovh/cds/sdk"
 )
 
+// vaultClientTimeout is the timeout of requests to Vault when resolving the secrets of a job.
+const vaultClientTimeout = 10 * time.Second
+
 func RetrieveSecrets(db gorp.SqlExecutor, wf sdk.Workflow) (*PushSecrets, error) {
 	secrets := &PushSecrets{
 		ApplicationsSecrets: make(map[int64][]sdk.Variable),
@@ -110,3 +116,111 @@ func LoadEnvironmentSecrets(db gorp.SqlExecutor, id int64) ([]sdk.Variable, erro
 	}
 	return secretsVariables, nil
 }
+
+// ResolveVaultSecrets resolves vault variables from given job parameters with the project's Vault integrations.
+// It returns the parameters without vault variables and the resolved secrets that should be sent to the worker.
+func ResolveVaultSecrets(db gorp.SqlExecutor, projectID int64, params []sdk.Parameter) ([]sdk.Parameter, []sdk.Variable, error) {
+	var hasVaultVariable bool
+	for _, p := range params {
+		if p.Type == sdk.VaultVariable {
+			hasVaultVariable = true
+			break
+		}
+	}
+	if !hasVaultVariable {
+		return params, nil, nil
+	}
+
+	integrations, err := integration.LoadIntegrationsByProjectIDWithClearPassword(db, projectID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return resolveVaultSecrets(integrations, params)
+}
+
+func resolveVaultSecrets(integrations []sdk.ProjectIntegration, params []sdk.Parameter) ([]sdk.Parameter, []sdk.Variable, error) {
+	vaultIntegrations := make(map[string]sdk.ProjectIntegration)
+	for _, i := range integrations {
+		if i.Model.Name == sdk.VaultIntegrationModel {
+			vaultIntegrations[i.Name] = i
+		}
+	}
+
+	clients := make(map[string]*vault.Client)
+	res := make([]sdk.Parameter, 0, len(params))
+	var secrets []sdk.Variable
+	for _, p := range params {
+		if p.Type != sdk.VaultVariable {
+			res = append(res, p)
+			continue
+		}
+
+		ref, err := sdk.ParseVaultSecretReference(p.Value)
+		if err != nil {
+			return nil, nil, sdk.NewErrorFrom(sdk.ErrWrongRequest, "invalid vault variable %s: %v", p.Name, sdk.Cause(err))
+		}
+
+		if ref.IntegrationName == "" {
+			if len(vaultIntegrations) != 1 {
+				return nil, nil, sdk.NewErrorFrom(sdk.ErrWrongRequest, "vault variable %s should give an integration name, %d Vault integrations found on project", p.Name, len(vaultIntegrations))
+			}
+			for name := range vaultIntegrations {
+				ref.IntegrationName = name
+			}
+		}
+
+		client, ok := clients[ref.IntegrationName]
+		if !ok {
+			i, ok := vaultIntegrations[ref.IntegrationName]
+			if !ok {
+				return nil, nil, sdk.NewErrorFrom(sdk.ErrNotFound, "Vault integration %s not found for variable %s", ref.IntegrationName, p.Name)
+			}
+			client, err = vault.NewClient(&vault.Config{Address: i.Config["url"].Value})
+			if err != nil {
+				return nil, nil, sdk.WrapError(err, "cannot create Vault client for integration %s", i.Name)
+			}
+			client.SetToken(i.Config["token"].Value)
+			client.SetClientTimeout(vaultClientTimeout)
+			clients[ref.IntegrationName] = client
+		}
+
+		value, err := readVaultSecret(client, ref)
+		if err != nil {
+			return nil, nil, sdk.WrapError(err, "cannot resolve vault variable %s", p.Name)
+		}
+
+		secrets = append(secrets, sdk.Variable{
+			Name:  p.Name,
+			Type:  sdk.SecretVariable,
+			Value: value,
+		})
+	}
+
+	return res, secrets, nil
+}
+
+// readVaultSecret reads the key of a secret from Vault, both KV version 1 and 2 secret engines are supported.
+func readVaultSecret(client *vault.Client, ref sdk.VaultSecretReference) (string, error) {
+	s, err := client.Logical().Read(ref.Path)
+	if err != nil {
+		return "", sdk.WithStack(err)
+	}
+	if s == nil || s.Data == nil {
+		return "", sdk.NewErrorFrom(sdk.ErrNotFound, "no secret found at %s", ref.Path)
+	}
+
+	data := s.Data
+	// With KV version 2, values are nested in a data field next to secret's metadata
+	if nested, ok := data["data"].(map[string]interface{}); ok {
+		if _, hasMetadata := data["metadata"]; hasMetadata {
+			data = nested
+		}
+	}
+
+	value, ok := data[ref.Key]
+	if !ok || value == nil {
+		return "", sdk.NewErrorFrom(sdk.ErrNotFound, "no key %s found in secret %s", ref.Key, ref.Path)
+	}
+	return fmt.Sprintf("%v", value), nil
+}
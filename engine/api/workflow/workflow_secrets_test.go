@@ -0,0 +1,76 @@
+package workflow
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func Test_resolveVaultSecrets(t *testing.T) {
+	// Local Vault stand-in serving a KV version 1 and a KV version 2 secret
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "my-token" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		var data map[string]interface{}
+		switch r.URL.Path {
+		case "/v1/kv1/my-app":
+			data = map[string]interface{}{"password": "my-kv1-password"}
+		case "/v1/kv2/data/my-app":
+			data = map[string]interface{}{
+				"data":     map[string]interface{}{"password": "my-kv2-password"},
+				"metadata": map[string]interface{}{"version": 1},
+			}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"data": data})
+	}))
+	defer srv.Close()
+
+	vaultIntegration := sdk.ProjectIntegration{
+		Name:  "my-vault",
+		Model: sdk.VaultIntegration,
+		Config: sdk.IntegrationConfig{
+			"url":   sdk.IntegrationConfigValue{Type: sdk.IntegrationConfigTypeString, Value: srv.URL},
+			"token": sdk.IntegrationConfigValue{Type: sdk.IntegrationConfigTypePassword, Value: "my-token"},
+		},
+	}
+	otherIntegration := sdk.ProjectIntegration{Name: "my-kafka", Model: sdk.KafkaIntegration}
+
+	params := []sdk.Parameter{
+		{Name: "cds.proj.foo", Type: sdk.StringVariable, Value: "bar"},
+		{Name: "cds.proj.kv1", Type: sdk.VaultVariable, Value: "kv1/my-app#password"},
+		{Name: "cds.app.kv2", Type: sdk.VaultVariable, Value: "my-vault:kv2/data/my-app#password"},
+	}
+
+	res, secrets, err := resolveVaultSecrets([]sdk.ProjectIntegration{otherIntegration, vaultIntegration}, params)
+	require.NoError(t, err)
+	require.Equal(t, []sdk.Parameter{{Name: "cds.proj.foo", Type: sdk.StringVariable, Value: "bar"}}, res)
+	require.Equal(t, []sdk.Variable{
+		{Name: "cds.proj.kv1", Type: sdk.SecretVariable, Value: "my-kv1-password"},
+		{Name: "cds.app.kv2", Type: sdk.SecretVariable, Value: "my-kv2-password"},
+	}, secrets)
+
+	_, _, err = resolveVaultSecrets([]sdk.ProjectIntegration{vaultIntegration}, []sdk.Parameter{
+		{Name: "cds.proj.unknown", Type: sdk.VaultVariable, Value: "kv1/my-app#unknown"},
+	})
+	require.Error(t, err)
+
+	_, _, err = resolveVaultSecrets([]sdk.ProjectIntegration{vaultIntegration}, []sdk.Parameter{
+		{Name: "cds.proj.missing", Type: sdk.VaultVariable, Value: "kv1/missing#password"},
+	})
+	require.Error(t, err)
+
+	_, _, err = resolveVaultSecrets([]sdk.ProjectIntegration{otherIntegration}, []sdk.Parameter{
+		{Name: "cds.proj.kv1", Type: sdk.VaultVariable, Value: "kv1/my-app#password"},
+	})
+	require.Error(t, err)
+}
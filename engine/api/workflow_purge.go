@@ -0,0 +1,70 @@
+package api
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/ovh/cds/engine/api/project"
+	"github.com/ovh/cds/engine/api/workflow"
+	"github.com/ovh/cds/engine/service"
+	"github.com/ovh/cds/sdk"
+)
+
+// getWorkflowPurgeDryRunHandler returns the runs that would be deleted by the purge policy of the workflow.
+func (api *API) getWorkflowPurgeDryRunHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		wf, err := api.loadWorkflowForPurge(ctx, r)
+		if err != nil {
+			return err
+		}
+
+		runs, err := workflow.ListRunsToPurge(ctx, api.mustDB(), *wf)
+		if err != nil {
+			return err
+		}
+
+		return service.WriteJSON(w, runs, http.StatusOK)
+	}
+}
+
+// postWorkflowPurgeDryRunHandler returns the runs that would be deleted by given purge policy, before saving it
+// on the workflow.
+func (api *API) postWorkflowPurgeDryRunHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		var policy sdk.WorkflowPurgePolicy
+		if err := service.UnmarshalBody(r, &policy); err != nil {
+			return err
+		}
+		if err := policy.IsValid(); err != nil {
+			return err
+		}
+
+		wf, err := api.loadWorkflowForPurge(ctx, r)
+		if err != nil {
+			return err
+		}
+		wf.PurgePolicy = policy
+
+		runs, err := workflow.ListRunsToPurge(ctx, api.mustDB(), *wf)
+		if err != nil {
+			return err
+		}
+
+		return service.WriteJSON(w, runs, http.StatusOK)
+	}
+}
+
+func (api *API) loadWorkflowForPurge(ctx context.Context, r *http.Request) (*sdk.Workflow, error) {
+	vars := mux.Vars(r)
+	key := vars["key"]
+	name := vars["permWorkflowName"]
+
+	proj, err := project.Load(ctx, api.mustDB(), key)
+	if err != nil {
+		return nil, err
+	}
+
+	return workflow.Load(ctx, api.mustDB(), api.Cache, *proj, name, workflow.LoadOptions{Minimal: true})
+}
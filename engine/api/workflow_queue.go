@@ -20,12 +20,14 @@ import (
 	"github.com/ovh/cds/engine/api/project"
 	"github.com/ovh/cds/engine/api/repositoriesmanager"
 	"github.com/ovh/cds/engine/api/services"
+	"github.com/ovh/cds/engine/api/usage"
 	"github.com/ovh/cds/engine/api/worker"
 	"github.com/ovh/cds/engine/api/workermodel"
 	"github.com/ovh/cds/engine/api/workflow"
 	"github.com/ovh/cds/engine/cache"
 	"github.com/ovh/cds/engine/service"
 	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/exportentities"
 	"github.com/ovh/cds/sdk/jws"
 	"github.com/ovh/cds/sdk/log"
 	"github.com/ovh/cds/sdk/telemetry"
@@ -98,8 +100,14 @@ func (api *API) postTakeWorkflowJobHandler() service.Handler {
 			return sdk.WrapError(sdk.ErrForbidden, "worker %s (%s) is not authorized to take this job:%d execGroups:%+v", wk.Name, workerModelName, id, pbj.ExecGroups)
 		}
 
+		// Resolve vault variables before taking the job to not call Vault in the take transaction
+		params, vaultSecrets, err := workflow.ResolveVaultSecrets(api.mustDB(), p.ID, pbj.Parameters)
+		if err != nil {
+			return sdk.WrapError(err, "cannot resolve vault secrets")
+		}
+
 		pbji := &sdk.WorkflowNodeJobRunData{}
-		report, err := takeJob(ctx, api.mustDB, api.Cache, p, id, workerModelName, pbji, wk, hatcheryName)
+		report, err := takeJob(ctx, api.mustDB, api.Cache, p, id, workerModelName, pbji, wk, hatcheryName, params, vaultSecrets)
 		if err != nil {
 			return sdk.WrapError(err, "cannot takeJob nodeJobRunID:%d", id)
 		}
@@ -118,7 +126,10 @@ func (api *API) postTakeWorkflowJobHandler() service.Handler {
 	}
 }
 
-func takeJob(ctx context.Context, dbFunc func() *gorp.DbMap, store cache.Store, p *sdk.Project, id int64, workerModel string, wnjri *sdk.WorkflowNodeJobRunData, wk *sdk.Worker, hatcheryName string) (*workflow.ProcessorReport, error) {
+// takeJob takes the job for given worker, params are the job parameters without vault variables that were resolved
+// into vaultSecrets.
+func takeJob(ctx context.Context, dbFunc func() *gorp.DbMap, store cache.Store, p *sdk.Project, id int64, workerModel string, wnjri *sdk.WorkflowNodeJobRunData, wk *sdk.Worker, hatcheryName string,
+	params []sdk.Parameter, vaultSecrets []sdk.Variable) (*workflow.ProcessorReport, error) {
 	// Start a tx
 	tx, errBegin := dbFunc().Begin()
 	if errBegin != nil {
@@ -184,6 +195,10 @@ func takeJob(ctx context.Context, dbFunc func() *gorp.DbMap, store cache.Store,
 		return nil, sdk.WrapError(err, "cannot load secrets")
 	}
 
+	// Resolved vault values are only sent as secrets to be masked by the worker
+	job.Parameters = params
+	secrets = append(secrets, vaultSecrets...)
+
 	// Feed the worker
 	wnjri.ProjectKey = p.Key
 	wnjri.NodeJobRun = *job
@@ -213,6 +228,10 @@ func (api *API) postBookWorkflowJobHandler() service.Handler {
 			return sdk.WithStack(sdk.ErrForbidden)
 		}
 
+		if api.isDraining() {
+			return sdk.NewErrorFrom(sdk.ErrServiceUnavailable, "CDS maintenance drain in progress, no new job can be booked")
+		}
+
 		s, err := services.LoadByID(ctx, api.mustDB(), getAPIConsumer(ctx).Service.ID)
 		if err != nil {
 			return err
@@ -331,6 +350,128 @@ func (api *API) postVulnerabilityReportHandler() service.Handler {
 	}
 }
 
+func (api *API) postAnalysisReportHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		if isWorker := isWorker(ctx); !isWorker {
+			return sdk.WithStack(sdk.ErrForbidden)
+		}
+
+		id, err := requestVarInt(r, "permJobID")
+		if err != nil {
+			return err
+		}
+
+		nr, err := workflow.LoadNodeRunByNodeJobID(api.mustDB(), id, workflow.LoadRunOptions{
+			DisableDetailledNodeRun: true,
+		})
+		if err != nil {
+			return sdk.WrapError(err, "unable to save analysis report")
+		}
+
+		var report sdk.AnalysisWorkerReport
+		if err := service.UnmarshalBody(r, &report); err != nil {
+			return sdk.WrapError(err, "unable to read body")
+		}
+
+		p, err := project.LoadProjectByNodeJobRunID(ctx, api.mustDB(), api.Cache, id)
+		if err != nil {
+			return sdk.WrapError(err, "cannot load project by nodeJobRunID: %d", id)
+		}
+
+		tx, err := api.mustDB().Begin()
+		if err != nil {
+			return sdk.WrapError(err, "unable to start transaction")
+		}
+		defer tx.Rollback() // nolint
+
+		analysis, err := workflow.SaveAnalysisReport(ctx, tx, api.Cache, *p, nr, report)
+		if err != nil {
+			return sdk.WrapError(err, "unable to handle report")
+		}
+
+		if err := tx.Commit(); err != nil {
+			return sdk.WithStack(err)
+		}
+
+		return service.WriteJSON(w, analysis, http.StatusOK)
+	}
+}
+
+func (api *API) postChildPipelinesHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		if isWorker := isWorker(ctx); !isWorker {
+			return sdk.WithStack(sdk.ErrForbidden)
+		}
+
+		id, err := requestVarInt(r, "permJobID")
+		if err != nil {
+			return err
+		}
+
+		var req sdk.ChildPipelinesRequest
+		if err := service.UnmarshalBody(r, &req); err != nil {
+			return sdk.WrapError(err, "unable to read body")
+		}
+		if len(req.Pipelines) == 0 {
+			return sdk.NewErrorFrom(sdk.ErrWrongRequest, "no pipeline given")
+		}
+
+		pips := make([]sdk.Pipeline, len(req.Pipelines))
+		for i := range req.Pipelines {
+			payload, err := exportentities.ParsePipeline(exportentities.FormatYAML, []byte(req.Pipelines[i]))
+			if err != nil {
+				return sdk.NewErrorWithStack(err, sdk.NewErrorFrom(sdk.ErrWrongRequest, "invalid child pipeline at index %d", i))
+			}
+			pip, err := payload.Pipeline()
+			if err != nil {
+				return err
+			}
+			pips[i] = *pip
+		}
+
+		p, err := project.LoadProjectByNodeJobRunID(ctx, api.mustDB(), api.Cache, id, project.LoadOptions.WithGroups)
+		if err != nil {
+			return sdk.WrapError(err, "cannot load project by nodeJobRunID: %d", id)
+		}
+
+		tx, err := api.mustDB().Begin()
+		if err != nil {
+			return sdk.WrapError(err, "unable to start transaction")
+		}
+		defer tx.Rollback() // nolint
+
+		wr, err := workflow.LoadAndLockRunByJobID(tx, id, workflow.LoadRunOptions{})
+		if err != nil {
+			if sdk.ErrorIs(err, sdk.ErrNotFound) {
+				return sdk.NewErrorFrom(sdk.ErrLocked, "workflow run is already locked")
+			}
+			return err
+		}
+
+		nr, err := workflow.LoadNodeRunByNodeJobID(tx, id, workflow.LoadRunOptions{
+			DisableDetailledNodeRun: true,
+		})
+		if err != nil {
+			return err
+		}
+
+		nodes, err := workflow.AddChildPipelines(ctx, tx, *p, wr, nr.WorkflowNodeID, pips, workflow.ChildPipelinesLimits{
+			MaxDepth:     api.Config.ChildPipelines.MaxDepth,
+			MaxPipelines: api.Config.ChildPipelines.MaxPipelines,
+			MaxJobs:      api.Config.ChildPipelines.MaxJobs,
+		})
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Commit(); err != nil {
+			return sdk.WithStack(err)
+		}
+
+		return service.WriteJSON(w, nodes, http.StatusOK)
+	}
+}
+
 func (api *API) postSpawnInfosWorkflowJobHandler() service.Handler {
 	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
 		id, err := requestVarInt(r, "permJobID")
@@ -544,6 +685,22 @@ func (api *API) postJobResult(ctx context.Context, proj *sdk.Project, wr *sdk.Wo
 		return nil, sdk.WrapError(err, "cannot update NodeJobRun %d status", job.ID)
 	}
 
+	// Replace the job usage recorded when the job was terminated with the worker model and the resources used
+	if !job.Done.IsZero() {
+		var model *sdk.Model
+		if wr.ModelID != nil {
+			// The worker model could have been deleted while the job was running
+			model, err = workermodel.LoadByID(ctx, tx, *wr.ModelID, workermodel.LoadOptions.Default)
+			if err != nil && !sdk.ErrorIs(err, sdk.ErrNotFound) {
+				return nil, err
+			}
+		}
+		u := usage.NewJobUsage(proj.ID, *job, model, res.Usage)
+		if err := usage.InsertJobUsage(tx, &u); err != nil {
+			return nil, err
+		}
+	}
+
 	//Commit the transaction
 	if err := tx.Commit(); err != nil {
 		return nil, sdk.WrapError(err, "cannot commit tx")
@@ -738,10 +895,11 @@ func (api *API) countWorkflowJobQueueHandler() service.Handler {
 		filter.Until = &until
 
 		var count sdk.WorkflowNodeJobRunCount
+		db := api.mustDBReplica(workflow.QueueReplicaStaleness)
 		if !isMaintainer(ctx) && !isAdmin(ctx) {
-			count, err = workflow.CountNodeJobRunQueueByGroupIDs(ctx, api.mustDB(), api.Cache, filter, getAPIConsumer(ctx).GetGroupIDs())
+			count, err = workflow.CountNodeJobRunQueueByGroupIDs(ctx, db, api.Cache, filter, getAPIConsumer(ctx).GetGroupIDs())
 		} else {
-			count, err = workflow.CountNodeJobRunQueue(ctx, api.mustDB(), api.Cache, filter)
+			count, err = workflow.CountNodeJobRunQueue(ctx, db, api.Cache, filter)
 		}
 		if err != nil {
 			return sdk.WrapError(err, "Unable to count queue")
@@ -778,6 +936,11 @@ func (api *API) getWorkflowJobQueueHandler() service.Handler {
 			permissions = sdk.PermissionReadExecute
 		}
 
+		// No job is dispatched to hatcheries and workers while draining
+		if (isW || isS) && api.isDraining() {
+			return service.WriteJSON(w, []sdk.WorkflowNodeJobRun{}, http.StatusOK)
+		}
+
 		filter := workflow.NewQueueFilter()
 		filter.RatioService = ratioService
 		filter.Since = &since
@@ -789,12 +952,19 @@ func (api *API) getWorkflowJobQueueHandler() service.Handler {
 			filter.ModelType = []string{modelType}
 		}
 
+		// Workers and hatcheries book the jobs they read so they need the last state of the queue, users can read it
+		// on the replica
+		db := api.mustDB()
+		if !isW && !isS {
+			db = api.mustDBReplica(workflow.QueueReplicaStaleness)
+		}
+
 		var jobs []sdk.WorkflowNodeJobRun
 		// If the consumer is a worker, a hatchery or a non maintainer user, filter the job by its groups
 		if isW || isS || !isMaintainer(ctx) {
-			jobs, err = workflow.LoadNodeJobRunQueueByGroupIDs(ctx, api.mustDB(), api.Cache, filter, getAPIConsumer(ctx).GetGroupIDs())
+			jobs, err = workflow.LoadNodeJobRunQueueByGroupIDs(ctx, db, api.Cache, filter, getAPIConsumer(ctx).GetGroupIDs())
 		} else {
-			jobs, err = workflow.LoadNodeJobRunQueue(ctx, api.mustDB(), api.Cache, filter)
+			jobs, err = workflow.LoadNodeJobRunQueue(ctx, db, api.Cache, filter)
 		}
 		if err != nil {
 			return sdk.WrapError(err, "Unable to load queue")
@@ -883,31 +1053,48 @@ func (api *API) postWorkflowJobCoverageResultsHandler() service.Handler {
 		if err != nil {
 			return sdk.WrapError(err, "cannot load project by nodeJobRunID:%d", id)
 		}
+		var covReport sdk.WorkflowNodeRunCoverage
 		if sdk.ErrorIs(errLoad, sdk.ErrNotFound) {
-			if err := workflow.ComputeNewReport(ctx, tx, api.Cache, report, wnr, *p); err != nil {
+			covReport, err = workflow.ComputeNewReport(ctx, tx, api.Cache, report, wnr, *p)
+			if err != nil {
 				return sdk.WrapError(err, "cannot compute new coverage report")
 			}
-			if err := tx.Commit(); err != nil {
-				return sdk.WithStack(err)
+		} else {
+			// update
+			existingReport.Report = report
+			if err := workflow.ComputeLatestDefaultBranchReport(ctx, tx, api.Cache, *p, wnr, &existingReport); err != nil {
+				return sdk.WrapError(err, "cannot compute default branch coverage report")
 			}
-			return nil
-		}
-
-		// update
-		existingReport.Report = report
-		if err := workflow.ComputeLatestDefaultBranchReport(ctx, tx, api.Cache, *p, wnr, &existingReport); err != nil {
-			return sdk.WrapError(err, "cannot compute default branch coverage report")
-		}
 
-		if err := workflow.UpdateCoverage(tx, existingReport); err != nil {
-			return sdk.WrapError(err, "unable to update code coverage")
+			if err := workflow.UpdateCoverage(tx, existingReport); err != nil {
+				return sdk.WrapError(err, "unable to update code coverage")
+			}
+			covReport = existingReport
 		}
 
 		if err := tx.Commit(); err != nil {
 			return sdk.WithStack(err)
 		}
 
-		return nil
+		sdk.GoRoutine(context.Background(), fmt.Sprintf("SendCoveragePullRequestComment-%d", wnr.ID), func(ctx context.Context) {
+			tx, err := api.mustDB().Begin()
+			if err != nil {
+				log.Error(ctx, "postWorkflowJobCoverageResultsHandler> %v", sdk.WithStack(err))
+				return
+			}
+			defer tx.Rollback() // nolint
+			if err := workflow.SendCoveragePullRequestComment(ctx, tx, api.Cache, *p, wnr, covReport); err != nil {
+				log.Error(ctx, "postWorkflowJobCoverageResultsHandler> unable to send coverage pull request comment: %v", err)
+				return
+			}
+			if err := tx.Commit(); err != nil {
+				log.Error(ctx, "postWorkflowJobCoverageResultsHandler> %v", sdk.WithStack(err))
+			}
+		})
+
+		// Files details are not needed by the worker to check coverage trends
+		covReport.Report.Files = nil
+		return service.WriteJSON(w, covReport, http.StatusOK)
 	}
 }
 
@@ -945,6 +1132,11 @@ func (api *API) postWorkflowJobTestsResultsHandler() service.Handler {
 			return sdk.WrapError(err, "node run not found: %d", nodeRunJob.WorkflowNodeRunID)
 		}
 
+		// Keep test case results with their original suite names to build their history
+		if err := workflow.InsertTestCases(tx, sdk.TestCasesFromTests(*nr, new)); err != nil {
+			return err
+		}
+
 		if nr.Tests == nil {
 			nr.Tests = &venom.Tests{}
 		}
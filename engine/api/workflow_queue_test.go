@@ -1525,7 +1525,11 @@ func TestInsertNewCodeCoverageReport(t *testing.T) {
 	req := assets.NewJWTAuthentifiedRequest(t, ctx.workerToken, "POST", uri, request)
 	rec := httptest.NewRecorder()
 	router.Mux.ServeHTTP(rec, req)
-	require.Equal(t, 204, rec.Code)
+	require.Equal(t, 200, rec.Code)
+
+	var covResult sdk.WorkflowNodeRunCoverage
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &covResult))
+	require.Equal(t, coverateReportDefaultBranch.Report.CoveredLines, covResult.Trend.DefaultBranch.CoveredLines)
 
 	covDB, errL := workflow.LoadCoverageReport(db, wrToTest.WorkflowNodeRuns[w.WorkflowData.Node.ID][0].ID)
 	assert.NoError(t, errL)
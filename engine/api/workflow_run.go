@@ -90,7 +90,7 @@ func (api *API) searchWorkflowRun(w http.ResponseWriter, r *http.Request, route,
 
 	//Maximim range is set to 50
 	w.Header().Add("Accept-Range", "run 50")
-	runs, offset, limit, count, err := workflow.LoadRuns(api.mustDB(), key, name, offset, limit, mapFilters)
+	runs, offset, limit, count, err := workflow.LoadRuns(api.mustDBReplica(workflow.RunsReplicaStaleness), key, name, offset, limit, mapFilters)
 	if err != nil {
 		return sdk.WrapError(err, "Unable to load workflow runs")
 	}
@@ -812,6 +812,31 @@ func (api *API) getWorkflowNodeRunHandler() service.Handler {
 	}
 }
 
+func (api *API) getWorkflowNodeRunAnalysesHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		key := vars["key"]
+		name := vars["permWorkflowName"]
+		id, err := requestVarInt(r, "nodeRunID")
+		if err != nil {
+			return err
+		}
+
+		// Check that the node run belongs to the workflow
+		nr, err := workflow.LoadNodeRun(api.mustDB(), key, name, id, workflow.LoadRunOptions{})
+		if err != nil {
+			return sdk.WrapError(err, "unable to load node run %d", id)
+		}
+
+		analyses, err := workflow.LoadAnalysesByNodeRunID(ctx, api.mustDB(), nr.ID)
+		if err != nil {
+			return err
+		}
+
+		return service.WriteJSON(w, analyses, http.StatusOK)
+	}
+}
+
 func (api *API) postWorkflowRunHandler() service.Handler {
 	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
 		vars := mux.Vars(r)
@@ -842,6 +867,11 @@ func (api *API) postWorkflowRunHandler() service.Handler {
 			return err
 		}
 		opts.AuthConsumerID = getAPIConsumer(ctx).ID
+		if opts.Hook != nil && len(opts.Hook.TraceHeaders) > 0 {
+			opts.TraceHeaders = opts.Hook.TraceHeaders
+		} else {
+			opts.TraceHeaders = telemetry.ContextToHeaders(ctx)
+		}
 
 		// Request check
 		if opts.Manual != nil && opts.Manual.OnlyFailedJobs && opts.Manual.Resync {
@@ -0,0 +1,64 @@
+package api
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/ovh/cds/engine/api/project"
+	"github.com/ovh/cds/engine/api/purge"
+	"github.com/ovh/cds/engine/api/workflow"
+	"github.com/ovh/cds/engine/service"
+	"github.com/ovh/cds/sdk"
+)
+
+func (api *API) loadWorkflowForRunArchives(ctx context.Context, r *http.Request) (*sdk.Workflow, error) {
+	vars := mux.Vars(r)
+	key := vars["key"]
+	name := vars["permWorkflowName"]
+
+	proj, err := project.Load(ctx, api.mustDB(), key)
+	if err != nil {
+		return nil, err
+	}
+
+	return workflow.Load(ctx, api.mustDB(), api.Cache, *proj, name, workflow.LoadOptions{Minimal: true})
+}
+
+func (api *API) getWorkflowRunArchivesHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		wf, err := api.loadWorkflowForRunArchives(ctx, r)
+		if err != nil {
+			return err
+		}
+
+		archives, err := workflow.LoadRunArchives(ctx, api.mustDB(), wf.ID, int64(FormInt(r, "offset")), int64(FormInt(r, "limit")))
+		if err != nil {
+			return err
+		}
+
+		return service.WriteJSON(w, archives, http.StatusOK)
+	}
+}
+
+func (api *API) getWorkflowRunArchiveHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		number, err := requestVarInt(r, "number")
+		if err != nil {
+			return err
+		}
+
+		wf, err := api.loadWorkflowForRunArchives(ctx, r)
+		if err != nil {
+			return err
+		}
+
+		data, err := purge.LoadArchivedWorkflowRun(ctx, api.mustDB(), api.SharedStorage, wf.ID, number)
+		if err != nil {
+			return err
+		}
+
+		return service.WriteJSON(w, data, http.StatusOK)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/ovh/cds/engine/api/leader"
 	"github.com/ovh/cds/engine/api/project"
 	"github.com/ovh/cds/engine/api/workflow"
 	"github.com/ovh/cds/engine/cache"
@@ -15,7 +16,8 @@ import (
 	"go.opencensus.io/trace"
 )
 
-func (api *API) WorkflowRunCraft(ctx context.Context, tick time.Duration) error {
+// WorkflowRunCraft crafts the workflow runs of the shards owned by the current instance.
+func (api *API) WorkflowRunCraft(ctx context.Context, tick time.Duration, shards *leader.ShardSet) error {
 	ticker := time.NewTicker(tick)
 	defer ticker.Stop()
 
@@ -24,9 +26,13 @@ func (api *API) WorkflowRunCraft(ctx context.Context, tick time.Duration) error
 		case <-ctx.Done():
 			return ctx.Err()
 		case <-ticker.C:
-			ids, err := workflow.LoadCratingWorkflowRunIDs(api.mustDB())
+			indexes, total := shards.Owned()
+			if len(indexes) == 0 {
+				continue
+			}
+			ids, err := workflow.LoadCratingWorkflowRunIDs(api.mustDB(), indexes, total)
 			if err != nil {
-				log.Error(ctx, "WorkflowRunCraft> unable to start tx: %v", err)
+				log.Error(ctx, "WorkflowRunCraft> unable to load workflow runs to craft: %v", err)
 				continue
 			}
 			for _, id := range ids {
@@ -86,6 +92,15 @@ func (api *API) workflowRunCraft(ctx context.Context, id int64) error {
 		return errors.New("unable to craft workflow run without options...")
 	}
 
+	// Continue the trace of the request that triggered the run if any
+	if len(run.ToCraftOpts.TraceHeaders) > 0 {
+		var span *trace.Span
+		ctx, span = telemetry.NewWithRemoteParent(ctx, api, "api.workflowRunCraft.initWorkflowRun", run.ToCraftOpts.TraceHeaders, trace.SpanKindServer)
+		if span != nil {
+			defer span.End()
+		}
+	}
+
 	_, next = telemetry.Span(ctx, "api.workflowRunCraft.LoadProjectByID")
 	proj, err := project.LoadByID(api.mustDB(), run.ProjectID,
 		project.LoadOptions.WithVariables,
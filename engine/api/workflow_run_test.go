@@ -19,6 +19,7 @@ import (
 	"github.com/ovh/cds/engine/api/environment"
 	"github.com/ovh/cds/engine/api/group"
 	"github.com/ovh/cds/engine/api/integration"
+	"github.com/ovh/cds/engine/api/leader"
 	"github.com/ovh/cds/engine/api/pipeline"
 	"github.com/ovh/cds/engine/api/plugin"
 	"github.com/ovh/cds/engine/api/project"
@@ -1156,7 +1157,9 @@ func waitCraftinWorkflow(t *testing.T, api *API, db gorp.SqlExecutor, id int64)
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	go api.WorkflowRunCraft(ctx, 10*time.Millisecond)
+	shards := new(leader.ShardSet)
+	go shards.Run(ctx, leader.Shard{Index: 0, Total: 1})
+	go api.WorkflowRunCraft(ctx, 10*time.Millisecond, shards)
 
 	tick := time.NewTicker(1 * time.Second)
 	defer tick.Stop()
@@ -0,0 +1,87 @@
+package api
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/ovh/cds/engine/api/workflow"
+	"github.com/ovh/cds/engine/service"
+	"github.com/ovh/cds/sdk"
+)
+
+const (
+	defaultTestsSummaryRuns  = 20
+	maxTestsSummaryRuns      = 50
+	maxTestsSummaryTestCases = 10000
+	defaultTestsSummaryLimit = 10
+	defaultTestCaseHistory   = 50
+	maxTestCaseHistory       = 500
+)
+
+// getWorkflowTestsHandler returns the slowest, failing and flaky test cases of the last runs of a workflow.
+func (api *API) getWorkflowTestsHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		key := vars["key"]
+		name := vars["permWorkflowName"]
+
+		runs := int64(FormInt(r, "runs"))
+		if runs <= 0 {
+			runs = defaultTestsSummaryRuns
+		}
+		if runs > maxTestsSummaryRuns {
+			runs = maxTestsSummaryRuns
+		}
+		limit := FormInt(r, "limit")
+		if limit <= 0 {
+			limit = defaultTestsSummaryLimit
+		}
+
+		num, err := workflow.LoadCurrentRunNum(api.mustDB(), key, name)
+		if err != nil {
+			return sdk.WrapError(err, "cannot load current run num")
+		}
+
+		testCases, err := workflow.LoadTestCasesByWorkflow(ctx, api.mustDB(), key, name, FormString(r, "branch"), FormString(r, "node"), num-runs, maxTestsSummaryTestCases)
+		if err != nil {
+			return err
+		}
+
+		return service.WriteJSON(w, sdk.ComputeWorkflowTestsSummary(testCases, limit), http.StatusOK)
+	}
+}
+
+// getWorkflowTestCaseHistoryHandler returns the last results of a test case with its flaky status.
+func (api *API) getWorkflowTestCaseHistoryHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		key := vars["key"]
+		name := vars["permWorkflowName"]
+
+		nodeName := FormString(r, "node")
+		suite := FormString(r, "suite")
+		testName := FormString(r, "name")
+		if nodeName == "" || suite == "" || testName == "" {
+			return sdk.NewErrorFrom(sdk.ErrWrongRequest, "node, suite and name are mandatory")
+		}
+		limit := FormInt(r, "limit")
+		if limit <= 0 {
+			limit = defaultTestCaseHistory
+		}
+		if limit > maxTestCaseHistory {
+			limit = maxTestCaseHistory
+		}
+
+		testCases, err := workflow.LoadTestCaseHistory(ctx, api.mustDB(), key, name, nodeName, suite, testName, FormString(r, "branch"), limit)
+		if err != nil {
+			return err
+		}
+		if len(testCases) == 0 {
+			return sdk.WithStack(sdk.ErrNotFound)
+		}
+
+		return service.WriteJSON(w, sdk.ComputeTestCaseHistory(testCases), http.StatusOK)
+	}
+}
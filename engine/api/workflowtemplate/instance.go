@@ -114,6 +114,36 @@ func requestModifyDefaultNameAndRepositories(proj sdk.Project, repoURL string) T
 // CheckAndExecuteTemplate will execute the workflow template if given workflow components contains a template instance.
 // When detached is set this will not create/update any template instance in database (this is useful for workflow ascode branches).
 func CheckAndExecuteTemplate(ctx context.Context, db *gorp.DbMap, store cache.Store, consumer sdk.AuthConsumer, p sdk.Project,
+	data *exportentities.WorkflowComponents, mods ...TemplateRequestModifierFunc) ([]sdk.Message, *sdk.WorkflowTemplateInstance, error) {
+	if data.Template.From == "" {
+		return nil, nil, nil
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return nil, nil, sdk.WrapError(err, "cannot start transaction")
+	}
+	defer tx.Rollback() // nolint
+
+	allMsgs, wti, err := CheckAndExecuteTemplateWithTx(ctx, tx, store, consumer, p, data, mods...)
+	if err != nil {
+		return allMsgs, nil, err
+	}
+
+	// nothing is stored for a detached instance
+	if wti == nil || wti.Request.Detached {
+		return allMsgs, wti, nil
+	}
+
+	if err := tx.Commit(); err != nil {
+		return allMsgs, nil, sdk.WithStack(err)
+	}
+
+	return allMsgs, wti, nil
+}
+
+// CheckAndExecuteTemplateWithTx will execute the workflow template in given transaction, the transaction is not committed.
+func CheckAndExecuteTemplateWithTx(ctx context.Context, tx gorpmapper.SqlExecutorWithTx, store cache.Store, consumer sdk.AuthConsumer, p sdk.Project,
 	data *exportentities.WorkflowComponents, mods ...TemplateRequestModifierFunc) ([]sdk.Message, *sdk.WorkflowTemplateInstance, error) {
 	var allMsgs []sdk.Message
 
@@ -126,12 +156,6 @@ func CheckAndExecuteTemplate(ctx context.Context, db *gorp.DbMap, store cache.St
 		return allMsgs, nil, err
 	}
 
-	tx, err := db.Begin()
-	if err != nil {
-		return allMsgs, nil, sdk.WrapError(err, "cannot start transaction")
-	}
-	defer tx.Rollback() // nolint
-
 	// check that group exists
 	grp, err := group.LoadByName(ctx, tx, groupName)
 	if err != nil {
@@ -261,10 +285,6 @@ func CheckAndExecuteTemplate(ctx context.Context, db *gorp.DbMap, store cache.St
 		}
 	}
 
-	if err := tx.Commit(); err != nil {
-		return allMsgs, nil, sdk.WithStack(err)
-	}
-
 	// if the template was successfully executed we want to return only the a file with template instance data
 	*data = result
 	return allMsgs, wti, nil
@@ -24,12 +24,15 @@ func Key(args ...string) string {
 //Store is an interface
 type Store interface {
 	Keys(pattern string) ([]string, error)
+	ScanKeys(pattern string) ([]string, error)
 	Get(key string, value interface{}) (bool, error)
 	Set(key string, value interface{}) error
 	SetWithTTL(key string, value interface{}, ttl int) error
 	SetWithDuration(key string, value interface{}, duration time.Duration) error
 	UpdateTTL(key string, ttl int) error
+	ExpireIfEqual(key string, value interface{}, expiration time.Duration, otherKeys ...string) (bool, error)
 	Delete(key string) error
+	DeleteIfEqual(key string, value interface{}, otherKeys ...string) (bool, error)
 	DeleteAll(key string) error
 	Exist(key string) (bool, error)
 	LockStore
@@ -86,6 +86,35 @@ func (s *RedisStore) Keys(pattern string) ([]string, error) {
 	return keys, nil
 }
 
+// ScanKeys returns the keys matching given pattern, unlike Keys it iterates with SCAN
+// so that redis is not blocked while the whole keyspace is read.
+func (s *RedisStore) ScanKeys(pattern string) ([]string, error) {
+	if s.Client == nil {
+		return nil, sdk.WithStack(fmt.Errorf("redis> cannot get redis client"))
+	}
+	// A key can be returned more than once by SCAN
+	found := make(map[string]struct{})
+	var cursor uint64
+	for {
+		keys, next, err := s.Client.Scan(cursor, pattern, 100).Result()
+		if err != nil {
+			return nil, sdk.WrapError(err, "redis> cannot scan keys: %s", pattern)
+		}
+		for _, k := range keys {
+			found[k] = struct{}{}
+		}
+		if next == 0 {
+			break
+		}
+		cursor = next
+	}
+	keys := make([]string, 0, len(found))
+	for k := range found {
+		keys = append(keys, k)
+	}
+	return keys, nil
+}
+
 // Get a key from redis
 func (s *RedisStore) Get(key string, value interface{}) (bool, error) {
 	if s.Client == nil {
@@ -500,6 +529,58 @@ func (s *RedisStore) Unlock(key string) error {
 	return s.Delete(key)
 }
 
+// expireIfEqualScript sets the expiration of all the given keys if the first one holds the expected value.
+var expireIfEqualScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) ~= ARGV[1] then
+	return 0
+end
+for i = 1, #KEYS do
+	redis.call("PEXPIRE", KEYS[i], ARGV[2])
+end
+return 1`)
+
+// ExpireIfEqual atomically updates the expiration of the key and of the other given keys if the key holds given
+// value, it returns false if the value is not the expected one.
+func (s *RedisStore) ExpireIfEqual(key string, value interface{}, expiration time.Duration, otherKeys ...string) (bool, error) {
+	if s.Client == nil {
+		return false, sdk.WithStack(fmt.Errorf("redis> cannot get redis client"))
+	}
+	b, err := json.Marshal(value)
+	if err != nil {
+		return false, sdk.WithStack(err)
+	}
+	res, err := expireIfEqualScript.Run(s.Client, append([]string{key}, otherKeys...), string(b), expiration.Milliseconds()).Int()
+	if err != nil {
+		return false, sdk.WrapError(err, "redis> expire error %s", key)
+	}
+	return res == 1, nil
+}
+
+// deleteIfEqualScript deletes all the given keys if the first one holds the expected value.
+var deleteIfEqualScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) ~= ARGV[1] then
+	return 0
+end
+redis.call("DEL", unpack(KEYS))
+return 1`)
+
+// DeleteIfEqual atomically deletes the key and the other given keys if the key holds given value, it returns false
+// if the value is not the expected one.
+func (s *RedisStore) DeleteIfEqual(key string, value interface{}, otherKeys ...string) (bool, error) {
+	if s.Client == nil {
+		return false, sdk.WithStack(fmt.Errorf("redis> cannot get redis client"))
+	}
+	b, err := json.Marshal(value)
+	if err != nil {
+		return false, sdk.WithStack(err)
+	}
+	res, err := deleteIfEqualScript.Run(s.Client, append([]string{key}, otherKeys...), string(b)).Int()
+	if err != nil {
+		return false, sdk.WrapError(err, "redis> delete error %s", key)
+	}
+	return res == 1, nil
+}
+
 func (s *RedisStore) ScoredSetAppend(ctx context.Context, key string, value interface{}) error {
 	highItem, err := s.Client.ZRevRange(key, 0, 0).Result()
 	if err != nil {
@@ -101,3 +101,38 @@ func TestDequeueJSONRawMessagesWithContextMaxTimeout(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, 95, l2)
 }
+
+func TestIfEqualScripts(t *testing.T) {
+	log.SetLogger(t)
+	cfg := testConfig.LoadTestingConf(t, sdk.TypeAPI)
+	redisHost := cfg["redisHost"]
+	redisPassword := cfg["redisPassword"]
+	s, err := NewRedisStore(redisHost, redisPassword, 60)
+	require.NoError(t, err)
+
+	owner, lock := Key("test", "owner"), Key("test", "lock")
+	require.NoError(t, s.SetWithDuration(owner, "api-1", time.Minute))
+	require.NoError(t, s.SetWithDuration(lock, true, time.Minute))
+
+	keys, err := s.ScanKeys(Key("test", "*"))
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{owner, lock}, keys)
+
+	b, err := s.ExpireIfEqual(owner, "api-2", time.Hour, lock)
+	require.NoError(t, err)
+	require.False(t, b)
+	b, err = s.ExpireIfEqual(owner, "api-1", time.Hour, lock)
+	require.NoError(t, err)
+	require.True(t, b)
+	require.True(t, s.Client.TTL(lock).Val() > time.Minute)
+
+	b, err = s.DeleteIfEqual(owner, "api-2", lock)
+	require.NoError(t, err)
+	require.False(t, b)
+	b, err = s.DeleteIfEqual(owner, "api-1", lock)
+	require.NoError(t, err)
+	require.True(t, b)
+	found, err := s.Exist(lock)
+	require.NoError(t, err)
+	require.False(t, found)
+}
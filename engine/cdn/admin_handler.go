@@ -0,0 +1,53 @@
+package cdn
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/ovh/cds/engine/gorpmapper"
+	"github.com/ovh/cds/engine/service"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+func (s *Service) getDatabaseKeyRotationHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		if !s.Cfg.EnableLogProcessing {
+			return sdk.NewErrorFrom(sdk.ErrNotFound, "cdn database is not enabled")
+		}
+		rotation, err := s.Mapper.LoadLastKeyRotation(s.mustDBWithCtx(ctx))
+		if err != nil {
+			return err
+		}
+		if rotation == nil {
+			return sdk.WithStack(sdk.ErrNotFound)
+		}
+		rotation.Database = sdk.TypeCDN
+		return service.WriteJSON(w, rotation, http.StatusOK)
+	}
+}
+
+func (s *Service) postDatabaseKeyRotationHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		if !s.Cfg.EnableLogProcessing {
+			return sdk.NewErrorFrom(sdk.ErrNotFound, "cdn database is not enabled")
+		}
+		rotation, err := s.Mapper.StartKeyRotation(s.mustDBWithCtx(ctx))
+		if err != nil {
+			return err
+		}
+
+		s.runKeyRotation(s.Router.Background)
+
+		rotation.Database = sdk.TypeCDN
+		return service.WriteJSON(w, rotation, http.StatusAccepted)
+	}
+}
+
+func (s *Service) runKeyRotation(ctx context.Context) {
+	sdk.GoRoutine(ctx, "cdn-database-key-rotation", func(ctx context.Context) {
+		if err := s.Mapper.RunKeyRotation(ctx, s.mustDBWithCtx(ctx), gorpmapper.DefaultKeyRotationOptions); err != nil {
+			log.Error(ctx, "database key rotation failed: %v", err)
+		}
+	})
+}
@@ -130,6 +130,9 @@ func (s *Service) Serve(c context.Context) error {
 			s.CompleteWaitingItems(ctx)
 		})
 
+		// Resume the database key rotation if it was interrupted by a restart
+		s.runKeyRotation(ctx)
+
 		// Start CDS Backend migration
 		for _, storage := range s.Units.Storages {
 			cdsStorage, ok := storage.(*cds.CDS)
@@ -32,6 +32,7 @@ type DBConnectionFactory struct {
 	DBMaxConn        int
 	Database         *sql.DB
 	mutex            *sync.Mutex
+	replica          *replica
 }
 
 // DB returns the current sql.DB object
@@ -48,7 +49,9 @@ func (f *DBConnectionFactory) DB() *sql.DB {
 			}, "%s", err)
 			return nil
 		}
+		replica := f.replica
 		*f = *newF
+		f.replica = replica
 	}
 	if err := f.Database.Ping(); err != nil {
 		log.Error(context.TODO(), "Database> cannot ping db : %s", err)
@@ -178,6 +181,11 @@ func (f *DBConnectionFactory) Status(ctx context.Context) sdk.MonitoringStatusLi
 
 // Close closes the database, releasing any open resources.
 func (f *DBConnectionFactory) Close() error {
+	if f.replica != nil {
+		if err := f.replica.factory.Close(); err != nil {
+			log.Error(context.TODO(), "Database> cannot close replica: %v", err)
+		}
+	}
 	if f.Database != nil {
 		return f.Database.Close()
 	}
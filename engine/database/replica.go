@@ -0,0 +1,150 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/gorpmapper"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// replica is a read-only database replica with its last known replication lag.
+type replica struct {
+	factory *DBConnectionFactory
+	maxLag  time.Duration
+	// lag in nanoseconds, -1 if unknown
+	lag int64
+}
+
+// InitReplica connects the factory to a read-only replica of its database. Queries are sent to the replica only when
+// its lag is known and below maxLag, see MonitorReplicaLag.
+func (f *DBConnectionFactory) InitReplica(ctx context.Context, cfg DBReplicaConfiguration) error {
+	r, err := Init(ctx, cfg.User, f.DBRole, cfg.Password, f.DBName, f.DBSchema, cfg.Host, cfg.Port, cfg.SSLMode, f.DBConnectTimeout, f.DBTimeout, cfg.MaxConn)
+	if err != nil {
+		return sdk.WrapError(err, "cannot connect to database replica")
+	}
+	f.replica = &replica{
+		factory: r,
+		maxLag:  time.Duration(cfg.MaxLagSeconds) * time.Second,
+		lag:     -1,
+	}
+	return nil
+}
+
+// HasReplica returns true if a read-only replica is configured.
+func (f *DBConnectionFactory) HasReplica() bool {
+	return f.replica != nil
+}
+
+// ReplicaLag returns the last known replication lag, false if unknown.
+func (f *DBConnectionFactory) ReplicaLag() (time.Duration, bool) {
+	if f.replica == nil {
+		return 0, false
+	}
+	lag := atomic.LoadInt64(&f.replica.lag)
+	if lag < 0 {
+		return 0, false
+	}
+	return time.Duration(lag), true
+}
+
+// replicationLag computes the lag of a replica from its WAL receiver status, whether it replayed all the data received
+// from the primary and the time since its last replayed transaction. The lag is unknown if the WAL receiver is not
+// streaming: a disconnected replica replays all that it received and would otherwise report no lag.
+func replicationLag(receiverStatus string, replayed bool, secondsSinceReplay float64) (time.Duration, bool) {
+	if receiverStatus != "streaming" {
+		return 0, false
+	}
+	if replayed {
+		return 0, true
+	}
+	return time.Duration(secondsSinceReplay * float64(time.Second)), true
+}
+
+func (f *DBConnectionFactory) checkReplicaLag(ctx context.Context) {
+	db := f.replica.factory.DB()
+	if db == nil {
+		atomic.StoreInt64(&f.replica.lag, -1)
+		return
+	}
+	var receiverStatus string
+	var replayed bool
+	var seconds float64
+	if err := db.QueryRow(`
+		SELECT COALESCE((SELECT status FROM pg_stat_wal_receiver LIMIT 1), ''),
+			COALESCE(pg_last_wal_receive_lsn() = pg_last_wal_replay_lsn(), false),
+			COALESCE(EXTRACT(EPOCH FROM now() - pg_last_xact_replay_timestamp()), 0)`).Scan(&receiverStatus, &replayed, &seconds); err != nil {
+		log.Error(ctx, "database> unable to get replica lag: %v", err)
+		atomic.StoreInt64(&f.replica.lag, -1)
+		return
+	}
+	lag, ok := replicationLag(receiverStatus, replayed, seconds)
+	if !ok {
+		log.Warning(ctx, "database> replica is not streaming from the primary (wal receiver status: %q), queries are sent to the primary", receiverStatus)
+		atomic.StoreInt64(&f.replica.lag, -1)
+		return
+	}
+	atomic.StoreInt64(&f.replica.lag, int64(lag))
+}
+
+// MonitorReplicaLag checks the replication lag of the replica at given interval until the context is cancelled.
+func (f *DBConnectionFactory) MonitorReplicaLag(ctx context.Context, interval time.Duration) {
+	if f.replica == nil {
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	f.checkReplicaLag(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			f.checkReplicaLag(ctx)
+		}
+	}
+}
+
+// useReplica returns true if the replica can be used for a query that tolerates given staleness.
+func (f *DBConnectionFactory) useReplica(maxStaleness time.Duration) bool {
+	lag, ok := f.ReplicaLag()
+	if !ok {
+		return false
+	}
+	return lag <= f.replica.maxLag && lag <= maxStaleness
+}
+
+// GetReadDBMap returns a gorp.DbMap pointer on the replica for read only queries that tolerate given staleness. The
+// primary is returned if no replica is configured or if the replica lag exceeds the staleness or the max lag.
+func (f *DBConnectionFactory) GetReadDBMap(m *gorpmapper.Mapper, maxStaleness time.Duration) func() *gorp.DbMap {
+	return func() *gorp.DbMap {
+		if f.useReplica(maxStaleness) {
+			if db := f.replica.factory.DB(); db != nil {
+				return DBMap(m, db)
+			}
+		}
+		return DBMap(m, f.DB())
+	}
+}
+
+// ReplicaStatus returns the replica lag in a printable string.
+func (f *DBConnectionFactory) ReplicaStatus(ctx context.Context) sdk.MonitoringStatusLine {
+	if f.replica == nil {
+		return sdk.MonitoringStatusLine{Component: "Database Replica", Value: "disabled", Status: sdk.MonitoringStatusOK}
+	}
+	lag, ok := f.ReplicaLag()
+	if !ok {
+		return sdk.MonitoringStatusLine{Component: "Database Replica", Value: "lag unknown", Status: sdk.MonitoringStatusWarn}
+	}
+	status := sdk.MonitoringStatusOK
+	if lag > f.replica.maxLag {
+		status = sdk.MonitoringStatusWarn
+	}
+	return sdk.MonitoringStatusLine{Component: "Database Replica", Value: fmt.Sprintf("lag %v", lag.Round(time.Millisecond)), Status: status}
+}
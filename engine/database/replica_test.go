@@ -0,0 +1,49 @@
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func TestReplicationLag(t *testing.T) {
+	lag, ok := replicationLag("streaming", true, 42)
+	require.True(t, ok)
+	assert.Equal(t, time.Duration(0), lag)
+
+	lag, ok = replicationLag("streaming", false, 1.5)
+	require.True(t, ok)
+	assert.Equal(t, 1500*time.Millisecond, lag)
+
+	// A replica disconnected from the primary has replayed all it received but its lag is unknown
+	_, ok = replicationLag("", true, 0)
+	assert.False(t, ok)
+	_, ok = replicationLag("waiting", true, 0)
+	assert.False(t, ok)
+}
+
+func TestUseReplica(t *testing.T) {
+	f := &DBConnectionFactory{}
+	assert.False(t, f.useReplica(time.Minute))
+	_, ok := f.ReplicaLag()
+	assert.False(t, ok)
+	assert.Equal(t, "disabled", f.ReplicaStatus(context.TODO()).Value)
+
+	f.replica = &replica{maxLag: 10 * time.Second, lag: -1}
+	assert.False(t, f.useReplica(time.Minute))
+	assert.Equal(t, sdk.MonitoringStatusWarn, f.ReplicaStatus(context.TODO()).Status)
+
+	f.replica.lag = int64(2 * time.Second)
+	assert.True(t, f.useReplica(5*time.Second))
+	assert.False(t, f.useReplica(time.Second))
+	assert.Equal(t, sdk.MonitoringStatusOK, f.ReplicaStatus(context.TODO()).Status)
+
+	f.replica.lag = int64(20 * time.Second)
+	assert.False(t, f.useReplica(time.Minute))
+	assert.Equal(t, sdk.MonitoringStatusWarn, f.ReplicaStatus(context.TODO()).Status)
+}
@@ -6,19 +6,33 @@ import (
 
 // DBConfigurationWithEncryption is the exposed type for database configuration that requires encryption.
 type DBConfigurationWithEncryption struct {
-	User           string           `toml:"user" default:"cds" json:"user"`
-	Role           string           `toml:"role" default:"" commented:"true" comment:"Set a specific role to run SET ROLE for each connection" json:"role"`
-	Password       string           `toml:"password" default:"cds" json:"-"`
-	Name           string           `toml:"name" default:"cds" json:"name"`
-	Schema         string           `toml:"schema" json:"schema" comment:"Database schema name, default value is 'public'"`
-	Host           string           `toml:"host" default:"localhost" json:"host"`
-	Port           int              `toml:"port" default:"5432" json:"port"`
-	SSLMode        string           `toml:"sslmode" default:"disable" comment:"DB SSL Mode: require (default), verify-full, or disable" json:"sslmode"`
-	MaxConn        int              `toml:"maxconn" default:"20" comment:"DB Max connection" json:"maxconn"`
-	ConnectTimeout int              `toml:"connectTimeout" default:"10" comment:"Maximum wait for connection, in seconds" json:"connectTimeout"`
-	Timeout        int              `toml:"timeout" default:"3000" comment:"Statement timeout value in milliseconds" json:"timeout"`
-	SignatureKey   RollingKeyConfig `json:"-" toml:"signatureRollingKeys" comment:"Signature rolling keys" mapstructure:"signatureRollingKeys"`
-	EncryptionKey  RollingKeyConfig `json:"-" toml:"encryptionRollingKeys" comment:"Encryption rolling keys" mapstructure:"encryptionRollingKeys"`
+	User           string                 `toml:"user" default:"cds" json:"user"`
+	Role           string                 `toml:"role" default:"" commented:"true" comment:"Set a specific role to run SET ROLE for each connection" json:"role"`
+	Password       string                 `toml:"password" default:"cds" json:"-"`
+	Name           string                 `toml:"name" default:"cds" json:"name"`
+	Schema         string                 `toml:"schema" json:"schema" comment:"Database schema name, default value is 'public'"`
+	Host           string                 `toml:"host" default:"localhost" json:"host"`
+	Port           int                    `toml:"port" default:"5432" json:"port"`
+	SSLMode        string                 `toml:"sslmode" default:"disable" comment:"DB SSL Mode: require (default), verify-full, or disable" json:"sslmode"`
+	MaxConn        int                    `toml:"maxconn" default:"20" comment:"DB Max connection" json:"maxconn"`
+	ConnectTimeout int                    `toml:"connectTimeout" default:"10" comment:"Maximum wait for connection, in seconds" json:"connectTimeout"`
+	Timeout        int                    `toml:"timeout" default:"3000" comment:"Statement timeout value in milliseconds" json:"timeout"`
+	SignatureKey   RollingKeyConfig       `json:"-" toml:"signatureRollingKeys" comment:"Signature rolling keys" mapstructure:"signatureRollingKeys"`
+	EncryptionKey  RollingKeyConfig       `json:"-" toml:"encryptionRollingKeys" comment:"Encryption rolling keys" mapstructure:"encryptionRollingKeys"`
+	Replica        DBReplicaConfiguration `toml:"replica" comment:"Optional read-only replica used for heavy read queries" json:"replica" mapstructure:"replica"`
+}
+
+// DBReplicaConfiguration is the exposed type for a read-only database replica configuration, the database name,
+// schema and role are the same as on the primary.
+type DBReplicaConfiguration struct {
+	Enabled       bool   `toml:"enabled" default:"false" json:"enabled"`
+	User          string `toml:"user" default:"cds" json:"user"`
+	Password      string `toml:"password" default:"cds" json:"-"`
+	Host          string `toml:"host" default:"localhost" json:"host"`
+	Port          int    `toml:"port" default:"5432" json:"port"`
+	SSLMode       string `toml:"sslmode" default:"disable" comment:"DB SSL Mode: require (default), verify-full, or disable" json:"sslmode"`
+	MaxConn       int    `toml:"maxconn" default:"20" comment:"DB Max connection" json:"maxconn"`
+	MaxLagSeconds int    `toml:"maxLagSeconds" default:"10" comment:"Queries are sent to the primary when the replica lag exceeds this value, in seconds" json:"maxLagSeconds"`
 }
 
 // DBConfiguration is the exposed type for database configuration that is used by migrate service.
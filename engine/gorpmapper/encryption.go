@@ -28,7 +28,7 @@ func (m *Mapper) Encrypt(src interface{}, dst *[]byte, extra []interface{}) erro
 		extrabytes = append(extrabytes, btes)
 	}
 
-	btes, err := m.encryptionKey.Encrypt(clearContent, extrabytes...)
+	btes, err := m.getEncryptionKey().Encrypt(clearContent, extrabytes...)
 	if err != nil {
 		return sdk.WithStack(fmt.Errorf("unable to encrypt content: %v", err))
 	}
@@ -50,7 +50,7 @@ func (m *Mapper) Decrypt(src []byte, dest interface{}, extra []interface{}) erro
 		extrabytes = append(extrabytes, btes)
 	}
 
-	clearContent, err := m.encryptionKey.Decrypt(src, extrabytes...)
+	clearContent, err := m.getEncryptionKey().Decrypt(src, extrabytes...)
 	if err != nil {
 		return sdk.WithStack(fmt.Errorf("unable to decrypt content: %v", err))
 	}
@@ -2,7 +2,9 @@ package gorpmapper
 
 import (
 	"encoding/json"
+	"sort"
 
+	"github.com/ovh/symmecrypt"
 	// Import all symmecrypt ciphers
 	_ "github.com/ovh/symmecrypt/ciphers/aesgcm"
 	_ "github.com/ovh/symmecrypt/ciphers/aespmacsiv"
@@ -18,47 +20,114 @@ import (
 func (m *Mapper) ConfigureKeys(signatureKeys, encryptionKeys *[]keyloader.KeyConfig) error {
 	var globalErr error
 	m.once.Do(func() {
-		// Marshal the keys
-		var marshalledKeys [][]byte
-		for _, k := range *signatureKeys {
-			btes, err := json.Marshal(k)
-			if err != nil {
-				globalErr = sdk.WithStack(err)
-				return
-			}
-			marshalledKeys = append(marshalledKeys, btes)
+		m.signatureKeyConfigs = append(m.signatureKeyConfigs, *signatureKeys...)
+		m.encryptionKeyConfigs = append(m.encryptionKeyConfigs, *encryptionKeys...)
+		globalErr = m.loadKeys(nil)
+	})
+
+	return globalErr
+}
+
+// RetireKeys stops using the keys with given timestamps to read existing data. The keys used to sign and encrypt
+// data can't be retired.
+func (m *Mapper) RetireKeys(timestamps []int64) error {
+	if len(timestamps) == 0 {
+		return nil
+	}
+	return m.loadKeys(timestamps)
+}
+
+// loadKeys loads all the configured keys except the retired ones.
+func (m *Mapper) loadKeys(retired sdk.Int64Slice) error {
+	m.keysMutex.Lock()
+	defer m.keysMutex.Unlock()
+
+	signatureKeys := activeKeys(m.signatureKeyConfigs, retired)
+	encryptionKeys := activeKeys(m.encryptionKeyConfigs, retired)
+
+	// Marshal the keys
+	var marshalledKeys [][]byte
+	var signatureKeyTimestamps, encryptionKeyTimestamps []int64
+	for _, k := range signatureKeys {
+		signatureKeyTimestamps = append(signatureKeyTimestamps, k.Timestamp)
+		btes, err := json.Marshal(k)
+		if err != nil {
+			return sdk.WithStack(err)
 		}
-		for _, k := range *encryptionKeys {
-			btes, err := json.Marshal(k)
-			if err != nil {
-				globalErr = sdk.WithStack(err)
-			}
-			marshalledKeys = append(marshalledKeys, btes)
+		marshalledKeys = append(marshalledKeys, btes)
+	}
+	for _, k := range encryptionKeys {
+		encryptionKeyTimestamps = append(encryptionKeyTimestamps, k.Timestamp)
+		btes, err := json.Marshal(k)
+		if err != nil {
+			return sdk.WithStack(err)
 		}
+		marshalledKeys = append(marshalledKeys, btes)
+	}
 
-		store := configstore.NewStore()
+	store := configstore.NewStore()
 
-		var provider configstore.Provider
-		provider = func() (configstore.ItemList, error) {
-			list := configstore.ItemList{}
-			for _, btes := range marshalledKeys {
-				list.Items = append(list.Items, configstore.NewItem(keyloader.EncryptionKeyConfigName, string(btes), 99))
-			}
-			return list, nil
+	var provider configstore.Provider
+	provider = func() (configstore.ItemList, error) {
+		list := configstore.ItemList{}
+		for _, btes := range marshalledKeys {
+			list.Items = append(list.Items, configstore.NewItem(keyloader.EncryptionKeyConfigName, string(btes), 99))
 		}
-		store.RegisterProvider("fakeConfigstoreProvider", provider)
+		return list, nil
+	}
+	store.RegisterProvider("fakeConfigstoreProvider", provider)
 
-		var err error
-		m.signatureKey, err = keyloader.WatchKeyFromStore(KeySignIdentifier, store)
-		if err != nil {
-			globalErr = sdk.WithStack(err)
-		}
+	signatureKey, err := keyloader.WatchKeyFromStore(KeySignIdentifier, store)
+	if err != nil {
+		return sdk.WithStack(err)
+	}
+	encryptionKey, err := keyloader.WatchKeyFromStore(KeyEcnryptionIdentifier, store)
+	if err != nil {
+		return sdk.WithStack(err)
+	}
 
-		m.encryptionKey, err = keyloader.WatchKeyFromStore(KeyEcnryptionIdentifier, store)
-		if err != nil {
-			globalErr = sdk.WithStack(err)
+	m.signatureKey, m.encryptionKey = signatureKey, encryptionKey
+	m.signatureKeyTimestamps, m.encryptionKeyTimestamps = signatureKeyTimestamps, encryptionKeyTimestamps
+	return nil
+}
+
+// activeKeys returns the keys that are not retired. The latest key is always kept.
+func activeKeys(keys []keyloader.KeyConfig, retired sdk.Int64Slice) []keyloader.KeyConfig {
+	var latest int64
+	for _, k := range keys {
+		if k.Timestamp > latest {
+			latest = k.Timestamp
 		}
-	})
+	}
+	var res []keyloader.KeyConfig
+	for _, k := range keys {
+		if k.Timestamp == latest || !retired.Contains(k.Timestamp) {
+			res = append(res, k)
+		}
+	}
+	return res
+}
 
-	return globalErr
+func (m *Mapper) getSignatureKey() symmecrypt.Key {
+	m.keysMutex.RLock()
+	defer m.keysMutex.RUnlock()
+	return m.signatureKey
+}
+
+func (m *Mapper) getEncryptionKey() symmecrypt.Key {
+	m.keysMutex.RLock()
+	defer m.keysMutex.RUnlock()
+	return m.encryptionKey
+}
+
+// KeysTimestamps returns the sorted timestamps of configured signature and encryption keys.
+// The last timestamp of each list is the one of the key used to sign or encrypt data.
+func (m *Mapper) KeysTimestamps() (signature []int64, encryption []int64) {
+	m.keysMutex.RLock()
+	defer m.keysMutex.RUnlock()
+	signature = append(signature, m.signatureKeyTimestamps...)
+	encryption = append(encryption, m.encryptionKeyTimestamps...)
+	sort.Slice(signature, func(i, j int) bool { return signature[i] < signature[j] })
+	sort.Slice(encryption, func(i, j int) bool { return encryption[i] < encryption[j] })
+	return signature, encryption
 }
@@ -6,6 +6,7 @@ import (
 	"text/template"
 
 	"github.com/ovh/symmecrypt"
+	"github.com/ovh/symmecrypt/keyloader"
 )
 
 func New() *Mapper {
@@ -25,7 +26,14 @@ type Mapper struct {
 	}
 	encryptionKey symmecrypt.Key
 	signatureKey  symmecrypt.Key
-	once          sync.Once
+	// Configured keys, retired keys are not loaded
+	encryptionKeyConfigs []keyloader.KeyConfig
+	signatureKeyConfigs  []keyloader.KeyConfig
+	// Timestamps of all loaded keys, the latest one is used to encrypt and sign data
+	encryptionKeyTimestamps []int64
+	signatureKeyTimestamps  []int64
+	keysMutex               sync.RWMutex
+	once                    sync.Once
 }
 
 //Register intialiaze gorp mapping
@@ -0,0 +1,274 @@
+package gorpmapper
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// KeyRotationOptions allows to throttle a database key rotation.
+type KeyRotationOptions struct {
+	// BatchSize is the number of tuples rolled in a single transaction
+	BatchSize int
+	// Pause is the duration to wait between two batches
+	Pause time.Duration
+}
+
+// DefaultKeyRotationOptions are the options used when none are given.
+var DefaultKeyRotationOptions = KeyRotationOptions{
+	BatchSize: 100,
+	Pause:     time.Second,
+}
+
+// LoadLastKeyRotation returns the last database key rotation, or nil if no rotation was ever started.
+func (m *Mapper) LoadLastKeyRotation(db gorp.SqlExecutor) (*sdk.DatabaseKeyRotation, error) {
+	var r sdk.DatabaseKeyRotation
+	if err := db.SelectOne(&r, `SELECT * FROM "database_key_rotation" ORDER BY id DESC LIMIT 1`); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, sdk.WrapError(err, "cannot load last database key rotation")
+	}
+	return &r, nil
+}
+
+// StartKeyRotation creates a rotation that will re-encrypt and re-sign all the tuples with the latest configured
+// keys. If a rotation is already running, it is returned instead.
+func (m *Mapper) StartKeyRotation(db gorp.SqlExecutor) (*sdk.DatabaseKeyRotation, error) {
+	last, err := m.LoadLastKeyRotation(db)
+	if err != nil {
+		return nil, err
+	}
+	if last != nil && last.Status == sdk.DatabaseKeyRotationStatusRunning {
+		return last, nil
+	}
+
+	signature, encryption := m.KeysTimestamps()
+	if len(signature) == 0 || len(encryption) == 0 {
+		return nil, sdk.NewErrorFrom(sdk.ErrWrongRequest, "no database keys configured")
+	}
+
+	var names = append(m.ListSignedEntities(), m.ListEncryptedEntities()...)
+	sort.Strings(names)
+	var entities sdk.DatabaseKeyRotationEntities
+	for i, n := range names {
+		if i > 0 && names[i-1] == n {
+			continue
+		}
+		if err := m.checkRotationPrimaryKey(n); err != nil {
+			return nil, err
+		}
+		entities = append(entities, sdk.DatabaseKeyRotationEntity{Entity: n})
+	}
+
+	now := time.Now()
+	r := sdk.DatabaseKeyRotation{
+		Status:                 sdk.DatabaseKeyRotationStatusRunning,
+		SignatureKeyTimestamp:  signature[len(signature)-1],
+		EncryptionKeyTimestamp: encryption[len(encryption)-1],
+		Entities:               entities,
+		RetiredKeys:            sdk.Int64Slice{},
+		Started:                now,
+		LastModified:           now,
+	}
+	r.ID, err = db.SelectInt(`INSERT INTO "database_key_rotation"
+    (status, signature_key_timestamp, encryption_key_timestamp, entities, retired_keys, error, started, last_modified)
+    VALUES ($1, $2, $3, $4, $5, '', $6, $7) RETURNING id`,
+		r.Status, r.SignatureKeyTimestamp, r.EncryptionKeyTimestamp, r.Entities, r.RetiredKeys, r.Started, r.LastModified)
+	if err != nil {
+		return nil, sdk.WrapError(err, "cannot insert database key rotation")
+	}
+
+	return &r, nil
+}
+
+// RunKeyRotation rolls all the tuples of the running rotation, batch after batch. Progress is saved after each batch,
+// so calling this func again after a restart resumes the rotation where it stopped. Each batch is processed in a
+// transaction that locks the rotation, when several instances share the database only one of them rolls tuples and
+// the other ones wait for the end of the rotation. Once the rotation is done, the old keys are retired.
+func (m *Mapper) RunKeyRotation(ctx context.Context, db *gorp.DbMap, opts KeyRotationOptions) error {
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = DefaultKeyRotationOptions.BatchSize
+	}
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		stop, err := m.rollKeyRotationBatch(ctx, db, opts.BatchSize)
+		if err != nil {
+			return err
+		}
+		if stop {
+			return m.retireKeysOfLastRotation(ctx, db)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(opts.Pause):
+		}
+	}
+}
+
+// retireKeysOfLastRotation retires the old keys if the last rotation is done.
+func (m *Mapper) retireKeysOfLastRotation(ctx context.Context, db gorp.SqlExecutor) error {
+	r, err := m.LoadLastKeyRotation(db)
+	if err != nil {
+		return err
+	}
+	if r == nil || r.Status != sdk.DatabaseKeyRotationStatusDone || len(r.RetiredKeys) == 0 {
+		return nil
+	}
+	if err := m.RetireKeys(r.RetiredKeys); err != nil {
+		return err
+	}
+	log.Info(ctx, "database keys %v retired by rotation %d", r.RetiredKeys, r.ID)
+	return nil
+}
+
+// rollKeyRotationBatch rolls the next batch of tuples of the running rotation, returns true if there is nothing left
+// to do for this instance.
+func (m *Mapper) rollKeyRotationBatch(ctx context.Context, db *gorp.DbMap, batchSize int) (bool, error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return false, sdk.WithStack(err)
+	}
+	defer tx.Rollback() // nolint
+
+	var r sdk.DatabaseKeyRotation
+	if err := tx.SelectOne(&r, `SELECT * FROM "database_key_rotation" WHERE status = $1 ORDER BY id DESC LIMIT 1 FOR UPDATE SKIP LOCKED`,
+		sdk.DatabaseKeyRotationStatusRunning); err != nil {
+		if err != sql.ErrNoRows {
+			return false, sdk.WrapError(err, "cannot load running database key rotation")
+		}
+		// No running rotation, or another instance is working on it and we wait for the end of the rotation
+		running, err := tx.SelectInt(`SELECT COUNT(1) FROM "database_key_rotation" WHERE status = $1`, sdk.DatabaseKeyRotationStatusRunning)
+		if err != nil {
+			return false, sdk.WrapError(err, "cannot count running database key rotations")
+		}
+		return running == 0, nil
+	}
+
+	// Do not roll anything if this instance was not started with the keys of the rotation
+	signature, encryption := m.KeysTimestamps()
+	if len(signature) == 0 || len(encryption) == 0 ||
+		signature[len(signature)-1] != r.SignatureKeyTimestamp || encryption[len(encryption)-1] != r.EncryptionKeyTimestamp {
+		return true, sdk.WithStack(fmt.Errorf("database key rotation %d was started with other keys than the ones configured on this instance", r.ID))
+	}
+
+	idx := -1
+	for i := range r.Entities {
+		if !r.Entities[i].Done {
+			idx = i
+			break
+		}
+	}
+
+	now := time.Now()
+	if idx < 0 {
+		var retired sdk.Int64Slice
+		for _, t := range signature[:len(signature)-1] {
+			retired = append(retired, t)
+		}
+		for _, t := range encryption[:len(encryption)-1] {
+			if !retired.Contains(t) {
+				retired = append(retired, t)
+			}
+		}
+		if retired == nil {
+			retired = sdk.Int64Slice{}
+		}
+		if _, err := tx.Exec(`UPDATE "database_key_rotation" SET status = $2, retired_keys = $3, error = '', last_modified = $4, done = $4 WHERE id = $1`,
+			r.ID, sdk.DatabaseKeyRotationStatusDone, retired, now); err != nil {
+			return false, sdk.WrapError(err, "cannot update database key rotation")
+		}
+		if err := tx.Commit(); err != nil {
+			return false, sdk.WithStack(err)
+		}
+		log.Info(ctx, "database key rotation %d done, keys %v can be removed from the configuration", r.ID, retired)
+		return true, nil
+	}
+
+	progress := &r.Entities[idx]
+	pks, err := m.listPrimaryKeysAfter(tx, progress.Entity, progress.LastPK, batchSize)
+	if err != nil {
+		return false, err
+	}
+
+	signed := m.Mapping[progress.Entity].SignedEntity
+	for _, pk := range pks {
+		var err error
+		if signed {
+			err = m.RollSignedTupleByPrimaryKey(ctx, tx, progress.Entity, pk)
+		} else {
+			err = m.RollEncryptedTupleByPrimaryKey(tx, progress.Entity, pk)
+		}
+		if err != nil {
+			err = sdk.WrapError(err, "cannot roll tuple %s of entity %s", pk, progress.Entity)
+			_ = tx.Rollback()
+			if _, errU := db.Exec(`UPDATE "database_key_rotation" SET error = $2, last_modified = $3 WHERE id = $1`,
+				r.ID, err.Error(), now); errU != nil {
+				log.Error(ctx, "cannot save error for database key rotation %d: %v", r.ID, errU)
+			}
+			return false, err
+		}
+		progress.LastPK = pk
+		progress.Count++
+	}
+	progress.Done = len(pks) < batchSize
+
+	if _, err := tx.Exec(`UPDATE "database_key_rotation" SET entities = $2, error = '', last_modified = $3 WHERE id = $1`,
+		r.ID, r.Entities, now); err != nil {
+		return false, sdk.WrapError(err, "cannot update database key rotation")
+	}
+	if err := tx.Commit(); err != nil {
+		return false, sdk.WithStack(err)
+	}
+
+	return false, nil
+}
+
+// checkRotationPrimaryKey returns an error if the tuples of an entity can't be listed by primary key, rotation
+// progress is saved with a single key value so composite keys are not supported.
+func (m *Mapper) checkRotationPrimaryKey(entity string) error {
+	e, ok := m.Mapping[entity]
+	if !ok {
+		return sdk.WithStack(fmt.Errorf("unknown entity %s", entity))
+	}
+	if len(e.Keys) != 1 {
+		return sdk.WithStack(fmt.Errorf("cannot rotate keys of entity %s with composite primary key %v", entity, e.Keys))
+	}
+	return nil
+}
+
+// listPrimaryKeysAfter returns at most limit primary keys of an entity, greater than the given one. Keys are compared
+// with their native type so the primary key index is used, the given key is cast from its text representation.
+func (m *Mapper) listPrimaryKeysAfter(db gorp.SqlExecutor, entity, after string, limit int) ([]string, error) {
+	if err := m.checkRotationPrimaryKey(entity); err != nil {
+		return nil, err
+	}
+	e := m.Mapping[entity]
+
+	var pks []string
+	var err error
+	if after == "" {
+		query := fmt.Sprintf(`SELECT %s::text FROM "%s" ORDER BY %s LIMIT $1`, e.Keys[0], e.Name, e.Keys[0])
+		_, err = db.Select(&pks, query, limit)
+	} else {
+		query := fmt.Sprintf(`SELECT %s::text FROM "%s" WHERE %s > $1 ORDER BY %s LIMIT $2`, e.Keys[0], e.Name, e.Keys[0], e.Keys[0])
+		_, err = db.Select(&pks, query, after, limit)
+	}
+	if err != nil {
+		return nil, sdk.WrapError(err, "cannot list primary keys of entity %s", entity)
+	}
+	return pks, nil
+}
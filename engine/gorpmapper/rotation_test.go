@@ -0,0 +1,139 @@
+package gorpmapper_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ovh/cds/engine/database"
+	"github.com/ovh/cds/engine/gorpmapper"
+	"github.com/ovh/cds/engine/test"
+	"github.com/ovh/cds/sdk"
+)
+
+func TestKeysTimestamps(t *testing.T) {
+	m := gorpmapper.New()
+
+	sigKeys := database.RollingKeyConfig{
+		Cipher: "hmac",
+		Keys: []database.KeyConfig{
+			{Timestamp: 20, Key: "8f17c90d5306028bdf6ef66cc6da387aca9dd57a11f44e5e2752228398b7d165"},
+			{Timestamp: 10, Key: "e2a2ce8ae1e36d9e4b1a60a4ab4cb0c6a73ae50e6dbdd2e0c1c1e0f6a8f5f8f3"},
+		},
+	}
+	encryptKeys := database.RollingKeyConfig{
+		Cipher: "xchacha20-poly1305",
+		Keys: []database.KeyConfig{
+			{Timestamp: 30, Key: "fd27b8872bdefeb207bbefc1a82e94039b85d3ec68d891e22a5dcaa81542fc6b"},
+		},
+	}
+	signatureKeyConfig := sigKeys.GetKeys(gorpmapper.KeySignIdentifier)
+	encryptionKeyConfig := encryptKeys.GetKeys(gorpmapper.KeyEcnryptionIdentifier)
+	require.NoError(t, m.ConfigureKeys(&signatureKeyConfig, &encryptionKeyConfig))
+
+	signature, encryption := m.KeysTimestamps()
+	require.Equal(t, []int64{10, 20}, signature)
+	require.Equal(t, []int64{30}, encryption)
+}
+
+func TestRetireKeys(t *testing.T) {
+	m := gorpmapper.New()
+
+	sigKeys := database.RollingKeyConfig{
+		Cipher: "hmac",
+		Keys: []database.KeyConfig{
+			{Timestamp: 20, Key: "8f17c90d5306028bdf6ef66cc6da387aca9dd57a11f44e5e2752228398b7d165"},
+			{Timestamp: 10, Key: "e2a2ce8ae1e36d9e4b1a60a4ab4cb0c6a73ae50e6dbdd2e0c1c1e0f6a8f5f8f3"},
+		},
+	}
+	encryptKeys := database.RollingKeyConfig{
+		Cipher: "xchacha20-poly1305",
+		Keys: []database.KeyConfig{
+			{Timestamp: 40, Key: "fd27b8872bdefeb207bbefc1a82e94039b85d3ec68d891e22a5dcaa81542fc6b"},
+			{Timestamp: 30, Key: "2a4d6f0e1b3c5a7e9d8c6b4a2f1e3d5c7b9a8e6d4c2b1a3f5e7d9c8b6a4e2d1f"},
+		},
+	}
+	signatureKeyConfig := sigKeys.GetKeys(gorpmapper.KeySignIdentifier)
+	encryptionKeyConfig := encryptKeys.GetKeys(gorpmapper.KeyEcnryptionIdentifier)
+	require.NoError(t, m.ConfigureKeys(&signatureKeyConfig, &encryptionKeyConfig))
+
+	var encrypted []byte
+	require.NoError(t, m.Encrypt("sensitive-data", &encrypted, nil))
+
+	// The latest keys can't be retired
+	require.NoError(t, m.RetireKeys([]int64{10, 20, 30, 40}))
+	signature, encryption := m.KeysTimestamps()
+	require.Equal(t, []int64{20}, signature)
+	require.Equal(t, []int64{40}, encryption)
+
+	var decrypted string
+	require.NoError(t, m.Decrypt(encrypted, &decrypted, nil))
+	require.Equal(t, "sensitive-data", decrypted)
+}
+
+func TestKeyRotation(t *testing.T) {
+	m := gorpmapper.New()
+	m.Register(m.NewTableMapping(gorpmapper.TestEncryptedData{}, "test_encrypted_data", true, "id"))
+
+	db, _ := test.SetupPGWithMapper(t, m, sdk.TypeAPI)
+
+	_, err := db.Exec(`DELETE FROM "database_key_rotation"`)
+	require.NoError(t, err)
+
+	for i := 0; i < 5; i++ {
+		d := gorpmapper.TestEncryptedData{
+			Data:                 "data",
+			SensitiveData:        "sensitive-data",
+			AnotherSensitiveData: "another-sensitive-data",
+		}
+		require.NoError(t, m.InsertAndSign(context.TODO(), db, &d))
+	}
+
+	r, err := m.StartKeyRotation(db)
+	require.NoError(t, err)
+	require.Equal(t, sdk.DatabaseKeyRotationStatusRunning, r.Status)
+	require.Len(t, r.Entities, 1)
+
+	// Starting again returns the running rotation
+	r2, err := m.StartKeyRotation(db)
+	require.NoError(t, err)
+	require.Equal(t, r.ID, r2.ID)
+
+	require.NoError(t, m.RunKeyRotation(context.TODO(), db.DbMap, gorpmapper.KeyRotationOptions{BatchSize: 2, Pause: time.Millisecond}))
+
+	last, err := m.LoadLastKeyRotation(db)
+	require.NoError(t, err)
+	require.NotNil(t, last)
+	require.Equal(t, r.ID, last.ID)
+	require.Equal(t, sdk.DatabaseKeyRotationStatusDone, last.Status)
+	require.NotNil(t, last.Done)
+	require.True(t, last.Entities[0].Done)
+
+	var count int64
+	require.NoError(t, db.SelectOne(&count, `SELECT COUNT(*) FROM "test_encrypted_data"`))
+	require.Equal(t, count, last.Entities[0].Count)
+
+	done, total := last.Progress()
+	require.Equal(t, 1, done)
+	require.Equal(t, 1, total)
+
+	// Entities with a composite primary key can't be rotated
+	m2 := gorpmapper.New()
+	m2.Register(m2.NewTableMapping(gorpmapper.TestEncryptedData{}, "test_encrypted_data", false, "id", "data"))
+	sigKeys := database.RollingKeyConfig{
+		Cipher: "hmac",
+		Keys:   []database.KeyConfig{{Timestamp: 10, Key: "8f17c90d5306028bdf6ef66cc6da387aca9dd57a11f44e5e2752228398b7d165"}},
+	}
+	encryptKeys := database.RollingKeyConfig{
+		Cipher: "xchacha20-poly1305",
+		Keys:   []database.KeyConfig{{Timestamp: 10, Key: "fd27b8872bdefeb207bbefc1a82e94039b85d3ec68d891e22a5dcaa81542fc6b"}},
+	}
+	signatureKeyConfig := sigKeys.GetKeys(gorpmapper.KeySignIdentifier)
+	encryptionKeyConfig := encryptKeys.GetKeys(gorpmapper.KeyEcnryptionIdentifier)
+	require.NoError(t, m2.ConfigureKeys(&signatureKeyConfig, &encryptionKeyConfig))
+	_, err = m2.StartKeyRotation(db)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "composite primary key")
+}
@@ -61,7 +61,7 @@ func (m *Mapper) CheckSignature(i Canonicaller, sig []byte) (bool, error) {
 		if f == nil {
 			return false, nil
 		}
-		ok, err := m.checkSignature(i, m.signatureKey, f, sig)
+		ok, err := m.checkSignature(i, m.getSignatureKey(), f, sig)
 		if err != nil {
 			return ok, err
 		}
@@ -150,7 +150,7 @@ func (m *Mapper) sign(data Canonicaller) (string, []byte, error) {
 		return "", nil, sdk.WrapError(err, "unable to sign data")
 	}
 
-	btes, err := m.signatureKey.Encrypt(clearContent.Bytes())
+	btes, err := m.getSignatureKey().Encrypt(clearContent.Bytes())
 	if err != nil {
 		return "", nil, sdk.WithStack(fmt.Errorf("unable to encrypt content: %v", err))
 	}
@@ -90,6 +90,7 @@ func (h *HatcheryOpenstack) SpawnWorker(ctx context.Context, spawnArgs hatchery.
 		TTL:               h.Config.WorkerTTL,
 		FromWorkerImage:   withExistingImage,
 		GraylogHost:       h.Configuration().Provision.WorkerLogsOptions.Graylog.Host,
+		OTLPEndpoint:      h.Configuration().Provision.WorkerTracingOptions.OTLPEndpoint,
 		GraylogPort:       h.Configuration().Provision.WorkerLogsOptions.Graylog.Port,
 		GraylogExtraKey:   h.Configuration().Provision.WorkerLogsOptions.Graylog.ExtraKey,
 		GraylogExtraValue: h.Configuration().Provision.WorkerLogsOptions.Graylog.ExtraValue,
@@ -156,7 +156,8 @@ func (c *Common) initRouter(ctx context.Context, h hatchery.Interface) {
 	r.Background = ctx
 	r.URL = h.Configuration().URL
 	r.SetHeaderFunc = api.DefaultHeaders
-	r.Middlewares = append(r.Middlewares, service.CheckRequestSignatureMiddleware(c.ParsedAPIPublicKey))
+	r.Middlewares = append(r.Middlewares, service.CheckRequestSignatureMiddleware(c.ParsedAPIPublicKey), service.TracingMiddlewareFunc(c))
+	r.PostMiddlewares = append(r.PostMiddlewares, service.TracingPostMiddleware)
 
 	r.Handle("/mon/version", nil, r.GET(api.VersionHandler, api.Auth(false)))
 	r.Handle("/mon/status", nil, r.GET(getStatusHandler(h), api.Auth(false)))
@@ -378,6 +378,7 @@ func (h *HatcherySwarm) SpawnWorker(ctx context.Context, spawnArgs hatchery.Spaw
 		TTL:               h.Config.WorkerTTL,
 		HatcheryName:      h.Name(),
 		GraylogHost:       h.Config.Provision.WorkerLogsOptions.Graylog.Host,
+		OTLPEndpoint:      h.Config.Provision.WorkerTracingOptions.OTLPEndpoint,
 		GraylogPort:       h.Config.Provision.WorkerLogsOptions.Graylog.Port,
 		GraylogExtraKey:   h.Config.Provision.WorkerLogsOptions.Graylog.ExtraKey,
 		GraylogExtraValue: h.Config.Provision.WorkerLogsOptions.Graylog.ExtraValue,
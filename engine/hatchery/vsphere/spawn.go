@@ -196,6 +196,7 @@ func (h *HatcheryVSphere) launchScriptWorker(name string, jobID int64, token str
 		TTL:               h.Config.WorkerTTL,
 		FromWorkerImage:   true,
 		GraylogHost:       h.Configuration().Provision.WorkerLogsOptions.Graylog.Host,
+		OTLPEndpoint:      h.Configuration().Provision.WorkerTracingOptions.OTLPEndpoint,
 		GraylogPort:       h.Configuration().Provision.WorkerLogsOptions.Graylog.Port,
 		GraylogExtraKey:   h.Configuration().Provision.WorkerLogsOptions.Graylog.ExtraKey,
 		GraylogExtraValue: h.Configuration().Provision.WorkerLogsOptions.Graylog.ExtraValue,
@@ -22,6 +22,9 @@ func (h *HatcheryVSphere) getGraylogGrpcEnv(model sdk.Model) []string {
 	if h.Configuration().Provision.WorkerLogsOptions.Graylog.ExtraValue != "" {
 		env = append(env, fmt.Sprintf("export CDS_GRAYLOG_EXTRA_VALUE=%s", h.Configuration().Provision.WorkerLogsOptions.Graylog.ExtraValue))
 	}
+	if h.Configuration().Provision.WorkerTracingOptions.OTLPEndpoint != "" {
+		env = append(env, fmt.Sprintf("export CDS_OTLP_ENDPOINT=%s", h.Configuration().Provision.WorkerTracingOptions.OTLPEndpoint))
+	}
 
 	return env
 }
@@ -97,7 +97,7 @@ func (s *Service) Serve(c context.Context) error {
 	if _, err := s.Dao.store.Get(MaintenanceHookKey, &b); err != nil {
 		return fmt.Errorf("cannot get %s from redis: %v", MaintenanceHookKey, err)
 	}
-	s.Maintenance = b
+	s.setMaintenance(ctx, b)
 
 	// Listen event on maintenance state
 	go func() {
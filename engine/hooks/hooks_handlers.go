@@ -16,6 +16,7 @@ import (
 	"github.com/ovh/cds/engine/service"
 	"github.com/ovh/cds/sdk"
 	"github.com/ovh/cds/sdk/log"
+	"github.com/ovh/cds/sdk/telemetry"
 )
 
 func (s *Service) webhookHandler() service.Handler {
@@ -58,6 +59,7 @@ func (s *Service) webhookHandler() service.Handler {
 				RequestHeader: r.Header,
 				RequestURL:    r.URL.RawQuery,
 			},
+			TraceHeaders: telemetry.ContextToHeaders(ctx),
 		}
 
 		//Save the web hook execution
@@ -673,7 +675,20 @@ func (s *Service) postMaintenanceHandler() service.Handler {
 		if err := s.Dao.store.Publish(ctx, MaintenanceHookQueue, fmt.Sprintf("%v", enable)); err != nil {
 			return sdk.WrapError(err, "unable to publish maintenance state")
 		}
-		s.Maintenance = enable
+		s.setMaintenance(ctx, enable)
 		return nil
 	}
 }
+
+func (s *Service) getMaintenanceHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		size, err := s.Dao.QueueLen()
+		if err != nil {
+			return sdk.WrapError(err, "unable to get queue len")
+		}
+		return service.WriteJSON(w, sdk.MaintenanceStatus{
+			Enable:             s.Maintenance,
+			BufferedHookEvents: int64(size),
+		}, http.StatusOK)
+	}
+}
@@ -12,9 +12,10 @@ func (s *Service) initRouter(ctx context.Context) {
 	r.Background = ctx
 	r.URL = s.Cfg.URL
 	r.SetHeaderFunc = api.DefaultHeaders
-	r.Middlewares = append(r.Middlewares, service.CheckRequestSignatureMiddleware(s.ParsedAPIPublicKey))
+	r.Middlewares = append(r.Middlewares, service.CheckRequestSignatureMiddleware(s.ParsedAPIPublicKey), service.TracingMiddlewareFunc(s))
+	r.PostMiddlewares = append(r.PostMiddlewares, service.TracingPostMiddleware)
 
-	r.Handle("/admin/maintenance", nil, r.POST(s.postMaintenanceHandler))
+	r.Handle("/admin/maintenance", nil, r.GET(s.getMaintenanceHandler), r.POST(s.postMaintenanceHandler))
 	r.Handle("/mon/version", nil, r.GET(api.VersionHandler, api.Auth(false)))
 	r.Handle("/mon/status", nil, r.GET(s.statusHandler, api.Auth(false)))
 	r.Handle("/mon/metrics", nil, r.GET(service.GetPrometheustMetricsHandler(s), api.Auth(false)))
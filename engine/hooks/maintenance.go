@@ -32,7 +32,38 @@ func (s *Service) listenMaintenance(c context.Context) error {
 			if err != nil {
 				log.Warning(c, "listenMaintenance> Cannot parse value %s: %s", msg, err)
 			}
-			s.Maintenance = b
+			s.setMaintenance(c, b)
+		}
+	}
+}
+
+// setMaintenance updates the maintenance state. Hook events received during a maintenance are kept in the scheduler
+// queue, they are replayed as soon as the maintenance is disabled.
+func (s *Service) setMaintenance(ctx context.Context, enable bool) {
+	wasEnabled := s.Maintenance
+	s.Maintenance = enable
+	if wasEnabled && !enable {
+		size, err := s.Dao.QueueLen()
+		if err != nil {
+			log.Warning(ctx, "setMaintenance> Unable to get queueLen: %v", err)
+		}
+		log.Info(ctx, "setMaintenance> Maintenance disabled, replaying %d buffered hook events", size)
+	}
+}
+
+// waitMaintenance blocks until the maintenance is disabled or the given delay is elapsed.
+func (s *Service) waitMaintenance(ctx context.Context, delay time.Duration) {
+	tick := time.NewTicker(time.Second)
+	defer tick.Stop()
+	timeout := time.NewTimer(delay)
+	defer timeout.Stop()
+	for s.Maintenance {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timeout.C:
+			return
+		case <-tick.C:
 		}
 	}
 }
@@ -20,6 +20,7 @@ import (
 	"github.com/ovh/cds/sdk/cdsclient"
 	"github.com/ovh/cds/sdk/interpolate"
 	"github.com/ovh/cds/sdk/log"
+	"github.com/ovh/cds/sdk/telemetry"
 )
 
 func (s *Service) nodeRunToTask(nr sdk.WorkflowNodeRun) (sdk.Task, error) {
@@ -221,6 +222,7 @@ func (s *Service) doOutgoingWorkflowExecution(ctx context.Context, t *sdk.TaskEx
 	evt := sdk.WorkflowNodeRunHookEvent{
 		WorkflowNodeHookUUID: targetHook,
 		Payload:              payloadValues,
+		TraceHeaders:         telemetry.ContextToHeaders(ctx),
 	}
 	evt.ParentWorkflow.Key = pkey
 	evt.ParentWorkflow.Name = workflow
@@ -19,6 +19,9 @@ func fillPayload(ctx context.Context, pushEvent sdk.VCSPushEvent) map[string]str
 	payload["git.author.email"] = pushEvent.Commit.Author.Email
 	payload["git.branch"] = strings.TrimPrefix(strings.TrimPrefix(pushEvent.Branch.DisplayID, "refs/heads/"), "refs/tags/")
 	payload["git.hash"] = pushEvent.Commit.Hash
+	if pushEvent.Before != "" {
+		payload["git.hash.before"] = pushEvent.Before
+	}
 	hashShort := pushEvent.Commit.Hash
 	if len(hashShort) >= 7 {
 		hashShort = hashShort[:7]
@@ -92,27 +95,46 @@ func (s *Service) doPollerTaskExecution(ctx context.Context, task *sdk.Task, tas
 		payloadValues["payload"] = string(payload.Value)
 	}
 
-	var hookEvents []sdk.WorkflowNodeRunHookEvent
-	if len(events.PushEvents) > 0 || len(events.PullRequestEvents) > 0 {
-		i := 0
-		hookEvents = make([]sdk.WorkflowNodeRunHookEvent, len(events.PushEvents)+len(events.PullRequestEvents))
-		for _, pushEvent := range events.PushEvents {
-			payload := fillPayload(ctx, pushEvent)
-			hookEvents[i] = sdk.WorkflowNodeRunHookEvent{
-				WorkflowNodeHookUUID: task.UUID,
-				Payload:              sdk.ParametersMapMerge(payloadValues, payload),
+	// Polled events don't list changed files, they are computed by the repository manager between the event refs
+	filter := sdk.NewHookFilter(task.Config)
+	matchFilter := func(payload map[string]string, base, head string) bool {
+		if payload["git.tag"] != "" {
+			return true
+		}
+		if !filter.MatchBranch(payload["git.branch"]) {
+			log.Debug("Hooks> doPollerTaskExecution> %s ignored for branch %s", task.UUID, payload["git.branch"])
+			return false
+		}
+		if len(filter.Paths) > 0 {
+			if files, ok := s.hookChangedFiles(ctx, task.UUID, base, head); ok && !filter.MatchPaths(files) {
+				log.Debug("Hooks> doPollerTaskExecution> %s ignored as changed files don't match path filter", task.UUID)
+				return false
 			}
-			i++
 		}
+		return true
+	}
+
+	var hookEvents []sdk.WorkflowNodeRunHookEvent
+	for _, pushEvent := range events.PushEvents {
+		payload := fillPayload(ctx, pushEvent)
+		if !matchFilter(payload, pushEvent.Before, pushEvent.Commit.Hash) {
+			continue
+		}
+		hookEvents = append(hookEvents, sdk.WorkflowNodeRunHookEvent{
+			WorkflowNodeHookUUID: task.UUID,
+			Payload:              sdk.ParametersMapMerge(payloadValues, payload),
+		})
+	}
 
-		for _, pullRequestEvent := range events.PullRequestEvents {
-			payload := fillPayload(ctx, pullRequestEvent.Head)
-			hookEvents[i] = sdk.WorkflowNodeRunHookEvent{
-				WorkflowNodeHookUUID: task.UUID,
-				Payload:              sdk.ParametersMapMerge(payloadValues, payload),
-			}
-			i++
+	for _, pullRequestEvent := range events.PullRequestEvents {
+		payload := fillPayload(ctx, pullRequestEvent.Head)
+		if !matchFilter(payload, pullRequestEvent.Base.Commit.Hash, pullRequestEvent.Head.Commit.Hash) {
+			continue
 		}
+		hookEvents = append(hookEvents, sdk.WorkflowNodeRunHookEvent{
+			WorkflowNodeHookUUID: task.UUID,
+			Payload:              sdk.ParametersMapMerge(payloadValues, payload),
+		})
 	}
 
 	nextExec := fmt.Sprint(time.Now().Add(interval).Unix())
@@ -68,7 +68,7 @@ func (s *Service) retryTaskExecutionsRoutine(ctx context.Context) error {
 
 			if s.Maintenance {
 				log.Info(ctx, "retryTaskExecutionsRoutine> Maintenance enable, wait 1 minute. Queue %d", size)
-				time.Sleep(1 * time.Minute)
+				s.waitMaintenance(ctx, time.Minute)
 				continue
 			}
 
@@ -253,7 +253,7 @@ func (s *Service) dequeueTaskExecutions(ctx context.Context) error {
 
 		if s.Maintenance {
 			log.Info(ctx, "Maintenance enable, wait 1 minute. Queue %d", size)
-			time.Sleep(1 * time.Minute)
+			s.waitMaintenance(ctx, time.Minute)
 			continue
 		}
 
@@ -8,10 +8,12 @@ import (
 	"time"
 
 	"github.com/gorhill/cronexpr"
+	"go.opencensus.io/trace"
 
 	"github.com/ovh/cds/engine/cache"
 	"github.com/ovh/cds/sdk"
 	"github.com/ovh/cds/sdk/log"
+	"github.com/ovh/cds/sdk/telemetry"
 )
 
 //This are all the types
@@ -391,6 +393,13 @@ func (s *Service) doTask(ctx context.Context, t *sdk.Task, e *sdk.TaskExecution)
 		return false, nil
 	}
 
+	// Continue the trace of the incoming event if any
+	ctx, span := telemetry.NewWithRemoteParent(ctx, s, "hooks.doTask", e.TraceHeaders, trace.SpanKindServer)
+	if span != nil {
+		span.AddAttributes(trace.StringAttribute("task_uuid", t.UUID), trace.StringAttribute("task_type", e.Type))
+		defer span.End()
+	}
+
 	var hs []sdk.WorkflowNodeRunHookEvent
 	var h *sdk.WorkflowNodeRunHookEvent
 	var err error
@@ -437,6 +446,7 @@ func (s *Service) doTask(ctx context.Context, t *sdk.Task, e *sdk.TaskExecution)
 	confWorkflow := t.Config[sdk.HookConfigWorkflow]
 	var globalErr error
 	for _, hEvent := range hs {
+		hEvent.TraceHeaders = telemetry.ContextToHeaders(ctx)
 		run, err := s.Client.WorkflowRunFromHook(confProj.Value, confWorkflow.Value, hEvent)
 		if err != nil {
 			globalErr = err
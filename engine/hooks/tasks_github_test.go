@@ -6,10 +6,12 @@ import (
 	"testing"
 	"time"
 
+	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/require"
 
 	"github.com/ovh/cds/engine/api/test"
 	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/cdsclient/mock_cdsclient"
 	"github.com/ovh/cds/sdk/log"
 )
 
@@ -68,6 +70,90 @@ func Test_doWebHookExecutionTagGithub(t *testing.T) {
 	require.Equal(t, "0d1a26e67d8f5eaf1f6ba5c57fc3c7d91ac0fd1c", hs[0].Payload["git.hash"])
 }
 
+func Test_doWebHookExecutionGithubFilters(t *testing.T) {
+	log.SetLogger(t)
+	s, cancel := setupTestHookService(t)
+	defer cancel()
+
+	for _, tt := range []struct {
+		branchFilter string
+		pathFilter   string
+		triggered    bool
+	}{
+		{triggered: true},
+		{branchFilter: "master;my-*", triggered: true},
+		{branchFilter: "master;release/*"},
+		{pathFilter: "*.md", triggered: true},
+		{pathFilter: "services/**"},
+		{branchFilter: "my-branch", pathFilter: "**;!README.md"},
+	} {
+		task := &sdk.TaskExecution{
+			UUID:   sdk.RandomString(10),
+			Type:   TypeRepoManagerWebHook,
+			Config: sdk.WorkflowNodeHookConfig{},
+			WebHook: &sdk.WebHookExecution{
+				RequestBody: []byte(githubPushEvent),
+				RequestHeader: map[string][]string{
+					GithubHeader: {"push"},
+				},
+			},
+		}
+		task.Config[sdk.HookConfigBranchFilter] = sdk.WorkflowNodeHookConfigValue{Value: tt.branchFilter}
+		task.Config[sdk.HookConfigPathFilter] = sdk.WorkflowNodeHookConfigValue{Value: tt.pathFilter}
+
+		hs, err := s.doWebHookExecution(context.TODO(), task)
+		require.NoError(t, err)
+		require.Equal(t, tt.triggered, len(hs) == 1, "branch filter %q, path filter %q", tt.branchFilter, tt.pathFilter)
+	}
+}
+
+func Test_repositoryWebHookChangedFiles(t *testing.T) {
+	task := &sdk.TaskExecution{
+		WebHook: &sdk.WebHookExecution{RequestBody: []byte(githubPushEvent)},
+	}
+	files, ok := repositoryWebHookChangedFiles(task, GithubHeader)
+	require.True(t, ok)
+	require.Equal(t, []string{"README.md"}, files)
+
+	_, ok = repositoryWebHookChangedFiles(task, BitbucketHeader)
+	require.False(t, ok)
+
+	// GitHub push events list at most 20 commits, the changed files may be incomplete
+	var request GithubWebHookEvent
+	require.NoError(t, json.Unmarshal([]byte(githubPushEvent), &request))
+	for len(request.Commits) < githubPushEventMaxCommits {
+		request.Commits = append(request.Commits, request.Commits[0])
+	}
+	body, err := json.Marshal(request)
+	require.NoError(t, err)
+	task.WebHook.RequestBody = body
+	_, ok = repositoryWebHookChangedFiles(task, GithubHeader)
+	require.False(t, ok)
+}
+
+func Test_hookChangedFiles(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	m := mock_cdsclient.NewMockInterface(ctrl)
+	s := &Service{}
+	s.Client = m
+
+	m.EXPECT().HookChangedFiles("my-uuid", "9049f12", "0d1a26e").Return([]string{"README.md"}, nil)
+	files, ok := s.hookChangedFiles(context.TODO(), "my-uuid", "9049f12", "0d1a26e")
+	require.True(t, ok)
+	require.Equal(t, []string{"README.md"}, files)
+
+	m.EXPECT().HookChangedFiles("my-uuid", "9049f12", "0d1a26e").Return(nil, sdk.ErrNotImplemented)
+	_, ok = s.hookChangedFiles(context.TODO(), "my-uuid", "9049f12", "0d1a26e")
+	require.False(t, ok)
+
+	// New branch, there is no commit to compare with
+	_, ok = s.hookChangedFiles(context.TODO(), "my-uuid", "0000000000000000000000000000000000000000", "0d1a26e")
+	require.False(t, ok)
+	_, ok = s.hookChangedFiles(context.TODO(), "my-uuid", "", "0d1a26e")
+	require.False(t, ok)
+}
+
 func Test_prEvent(t *testing.T) {
 	loc, _ := time.LoadLocation("UTC")
 
@@ -57,7 +57,8 @@ func (s *Service) executeRepositoryWebHook(ctx context.Context, t *sdk.TaskExecu
 		events = strings.Split(t.Config[sdk.HookConfigEventFilter].Value, ";")
 	}
 
-	switch getRepositoryHeader(t, events) {
+	header := getRepositoryHeader(t, events)
+	switch header {
 	case GithubHeader:
 		headerValue := t.WebHook.RequestHeader[GithubHeader][0]
 		payload, err := s.generatePayloadFromGithubRequest(ctx, t, headerValue)
@@ -95,8 +96,29 @@ func (s *Service) executeRepositoryWebHook(ctx context.Context, t *sdk.TaskExecu
 		return nil, fmt.Errorf("Repository manager not found. Cannot read request body")
 	}
 
+	// Filter events on branch and changed files before a workflow run is created. When the push event doesn't
+	// list the changed files, they are computed by the repository manager between the event refs.
+	filter := sdk.NewHookFilter(t.Config)
+	files, hasFiles := repositoryWebHookChangedFiles(t, header)
+
 	hs := make([]sdk.WorkflowNodeRunHookEvent, 0, len(payloads))
 	for _, payload := range payloads {
+		branch, _ := payload[GIT_BRANCH].(string)
+		if !filter.MatchBranch(branch) {
+			log.Debug("executeRepositoryWebHook> %s ignored for branch %s", t.UUID, branch)
+			continue
+		}
+		payloadFiles, hasPayloadFiles := files, hasFiles
+		if !hasPayloadFiles && len(filter.Paths) > 0 {
+			before, _ := payload[GIT_HASH_BEFORE].(string)
+			after, _ := payload[GIT_HASH].(string)
+			payloadFiles, hasPayloadFiles = s.hookChangedFiles(ctx, t.UUID, before, after)
+		}
+		if hasPayloadFiles && !filter.MatchPaths(payloadFiles) {
+			log.Debug("executeRepositoryWebHook> %s ignored as changed files don't match path filter", t.UUID)
+			continue
+		}
+
 		h := sdk.WorkflowNodeRunHookEvent{
 			WorkflowNodeHookUUID: t.UUID,
 		}
@@ -117,6 +139,62 @@ func (s *Service) executeRepositoryWebHook(ctx context.Context, t *sdk.TaskExecu
 	return hs, nil
 }
 
+// githubPushEventMaxCommits is the maximum number of commits listed by a GitHub push event.
+const githubPushEventMaxCommits = 20
+
+// repositoryWebHookChangedFiles returns the files changed by a push event, false if the repository manager
+// doesn't send them or if the list may be incomplete.
+func repositoryWebHookChangedFiles(t *sdk.TaskExecution, header string) ([]string, bool) {
+	var files []string
+	switch header {
+	case GithubHeader:
+		var request GithubWebHookEvent
+		if err := json.Unmarshal(t.WebHook.RequestBody, &request); err != nil || len(request.Commits) == 0 ||
+			len(request.Commits) >= githubPushEventMaxCommits {
+			return nil, false
+		}
+		for _, c := range request.Commits {
+			for _, f := range c.Added {
+				files = append(files, fmt.Sprintf("%v", f))
+			}
+			for _, f := range c.Removed {
+				files = append(files, fmt.Sprintf("%v", f))
+			}
+			files = append(files, c.Modified...)
+		}
+	case GitlabHeader:
+		var request GitlabEvent
+		if err := json.Unmarshal(t.WebHook.RequestBody, &request); err != nil || len(request.Commits) == 0 ||
+			request.TotalCommitsCount > len(request.Commits) {
+			return nil, false
+		}
+		for _, c := range request.Commits {
+			files = append(files, c.Added...)
+			for _, f := range c.Removed {
+				files = append(files, fmt.Sprintf("%v", f))
+			}
+			files = append(files, c.Modified...)
+		}
+	default:
+		return nil, false
+	}
+	return files, true
+}
+
+// hookChangedFiles asks the repository manager for the files changed between two commits, false if they can't
+// be known (new branch, missing hashes or unsupported by the repository manager).
+func (s *Service) hookChangedFiles(ctx context.Context, uuid, before, after string) ([]string, bool) {
+	if before == "" || after == "" || strings.Trim(before, "0") == "" {
+		return nil, false
+	}
+	files, err := s.Client.HookChangedFiles(uuid, before, after)
+	if err != nil {
+		log.Warning(ctx, "hookChangedFiles> unable to get changed files for hook %s between %s and %s: %v", uuid, before, after, err)
+		return nil, false
+	}
+	return files, true
+}
+
 func executeWebHook(t *sdk.TaskExecution) (*sdk.WorkflowNodeRunHookEvent, error) {
 	// Prepare a struct to send to CDS API
 	h := sdk.WorkflowNodeRunHookEvent{
@@ -0,0 +1,38 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"runtime"
+	"strings"
+
+	"github.com/ovh/cds/sdk/telemetry"
+)
+
+// TracingMiddlewareFunc starts a tracing span for each handled request, child of the span propagated by the caller if any.
+func TracingMiddlewareFunc(s telemetry.Service) Middleware {
+	return func(ctx context.Context, w http.ResponseWriter, req *http.Request, rc *HandlerConfig) (context.Context, error) {
+		name := runtime.FuncForPC(reflect.ValueOf(rc.Handler).Pointer()).Name()
+		name = strings.Replace(name, ".func1", "", 1)
+
+		splittedName := strings.Split(name, ".")
+		name = splittedName[len(splittedName)-1]
+
+		opts := telemetry.Options{
+			Name: name,
+		}
+
+		ctx, err := telemetry.Start(ctx, s, w, req, opts)
+		newReq := req.WithContext(ctx)
+		*req = *newReq
+
+		return ctx, err
+	}
+}
+
+// TracingPostMiddleware ends the tracing span started by TracingMiddlewareFunc.
+func TracingPostMiddleware(ctx context.Context, w http.ResponseWriter, req *http.Request, rc *HandlerConfig) (context.Context, error) {
+	ctx, err := telemetry.End(ctx, w, req)
+	return ctx, err
+}
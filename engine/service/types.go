@@ -59,6 +59,9 @@ type HatcheryCommonConfiguration struct {
 				ExtraValue string `toml:"extraValue" comment:"value for extraKey field. For many keys: valueaaa,valuebbb" json:"-"`
 			} `toml:"graylog" json:"graylog"`
 		} `toml:"workerLogsOptions" comment:"Worker Log Configuration" json:"workerLogsOptions"`
+		WorkerTracingOptions struct {
+			OTLPEndpoint string `toml:"otlpEndpoint" comment:"OpenTelemetry collector OTLP/HTTP endpoint used by the workers to export job traces. Example: http://localhost:4318" json:"otlpEndpoint"`
+		} `toml:"workerTracingOptions" comment:"Worker Tracing Configuration" json:"workerTracingOptions"`
 	} `toml:"provision" json:"provision"`
 	LogOptions struct {
 		SpawnOptions struct {
@@ -0,0 +1,18 @@
+-- +migrate Up
+ALTER TABLE "auth_consumer" ADD COLUMN IF NOT EXISTS allowed_cidrs JSONB;
+
+CREATE TABLE IF NOT EXISTS "auth_consumer_ip_rejection" (
+    id BIGSERIAL PRIMARY KEY,
+    consumer_id VARCHAR(36) NOT NULL,
+    ip VARCHAR(64) NOT NULL,
+    method VARCHAR(10) NOT NULL,
+    route TEXT NOT NULL,
+    created TIMESTAMP WITH TIME ZONE DEFAULT LOCALTIMESTAMP
+);
+
+SELECT create_foreign_key_idx_cascade('FK_AUTH_CONSUMER_IP_REJECTION_CONSUMER', 'auth_consumer_ip_rejection', 'auth_consumer', 'consumer_id', 'id');
+SELECT create_index('auth_consumer_ip_rejection', 'IDX_AUTH_CONSUMER_IP_REJECTION_CREATED', 'consumer_id,created');
+
+-- +migrate Down
+DROP TABLE IF EXISTS "auth_consumer_ip_rejection";
+ALTER TABLE "auth_consumer" DROP COLUMN IF EXISTS allowed_cidrs;
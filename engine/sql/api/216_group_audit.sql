@@ -0,0 +1,15 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS "group_audit" (
+    id BIGSERIAL PRIMARY KEY,
+    group_id BIGINT NOT NULL,
+    group_name VARCHAR(256) NOT NULL,
+    username VARCHAR(256),
+    triggered_by VARCHAR(100),
+    event_type VARCHAR(100),
+    created TIMESTAMP WITH TIME ZONE DEFAULT LOCALTIMESTAMP
+);
+
+SELECT create_foreign_key_idx_cascade('FK_GROUP_AUDIT_GROUP', 'group_audit', 'group', 'group_id', 'id');
+
+-- +migrate Down
+DROP TABLE IF EXISTS "group_audit";
@@ -0,0 +1,29 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS "audit_api_call" (
+    id BIGSERIAL PRIMARY KEY,
+    created TIMESTAMP WITH TIME ZONE DEFAULT LOCALTIMESTAMP,
+    request_id VARCHAR(64),
+    consumer_id VARCHAR(36),
+    user_id VARCHAR(36),
+    username VARCHAR(256),
+    ip VARCHAR(64),
+    method VARCHAR(10) NOT NULL,
+    route TEXT NOT NULL,
+    handler VARCHAR(256),
+    project_key VARCHAR(256),
+    workflow_name VARCHAR(256),
+    route_vars JSONB,
+    status_code INT,
+    success BOOLEAN
+);
+
+SELECT create_index('audit_api_call', 'IDX_AUDIT_API_CALL_CREATED', 'created');
+SELECT create_index('audit_api_call', 'IDX_AUDIT_API_CALL_PROJECT_KEY', 'project_key,created');
+SELECT create_index('audit_api_call', 'IDX_AUDIT_API_CALL_USERNAME', 'username,created');
+
+-- Audit trail is append-only, updates are ignored
+CREATE RULE audit_api_call_no_update AS ON UPDATE TO "audit_api_call" DO INSTEAD NOTHING;
+
+-- +migrate Down
+DROP RULE IF EXISTS audit_api_call_no_update ON "audit_api_call";
+DROP TABLE IF EXISTS "audit_api_call";
@@ -0,0 +1,21 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS "workflow_node_run_analysis" (
+    id BIGSERIAL PRIMARY KEY,
+    workflow_id BIGINT NOT NULL,
+    workflow_run_id BIGINT NOT NULL,
+    workflow_node_run_id BIGINT NOT NULL,
+    application_id BIGINT NOT NULL DEFAULT 0,
+    run_number BIGINT NOT NULL,
+    branch VARCHAR(256) NOT NULL DEFAULT '',
+    tool VARCHAR(256) NOT NULL,
+    created TIMESTAMP WITH TIME ZONE DEFAULT LOCALTIMESTAMP,
+    findings JSONB,
+    fixed JSONB
+);
+
+SELECT create_foreign_key_idx_cascade('FK_WORKFLOW_NODE_RUN_ANALYSIS_RUN', 'workflow_node_run_analysis', 'workflow_run', 'workflow_run_id', 'id');
+SELECT create_unique_index('workflow_node_run_analysis', 'IDX_WORKFLOW_NODE_RUN_ANALYSIS_TOOL_UNIQ', 'workflow_node_run_id,tool');
+SELECT create_index('workflow_node_run_analysis', 'IDX_WORKFLOW_NODE_RUN_ANALYSIS_BRANCH', 'workflow_id,application_id,tool,branch,run_number');
+
+-- +migrate Down
+DROP TABLE IF EXISTS "workflow_node_run_analysis";
@@ -0,0 +1,23 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS "workflow_node_run_test_case" (
+    id BIGSERIAL PRIMARY KEY,
+    workflow_id BIGINT NOT NULL,
+    workflow_run_id BIGINT NOT NULL,
+    workflow_node_run_id BIGINT NOT NULL,
+    node_name VARCHAR(256) NOT NULL,
+    run_number BIGINT NOT NULL,
+    branch VARCHAR(256) NOT NULL DEFAULT '',
+    vcs_hash VARCHAR(256) NOT NULL DEFAULT '',
+    suite TEXT NOT NULL,
+    name TEXT NOT NULL,
+    status VARCHAR(32) NOT NULL,
+    duration DOUBLE PRECISION NOT NULL DEFAULT 0,
+    created TIMESTAMP WITH TIME ZONE DEFAULT LOCALTIMESTAMP
+);
+
+SELECT create_foreign_key_idx_cascade('FK_WORKFLOW_NODE_RUN_TEST_CASE_RUN', 'workflow_node_run_test_case', 'workflow_run', 'workflow_run_id', 'id');
+SELECT create_index('workflow_node_run_test_case', 'IDX_WORKFLOW_NODE_RUN_TEST_CASE_WORKFLOW', 'workflow_id,run_number');
+SELECT create_index('workflow_node_run_test_case', 'IDX_WORKFLOW_NODE_RUN_TEST_CASE_NAME', 'workflow_id,node_name,suite,name');
+
+-- +migrate Down
+DROP TABLE IF EXISTS "workflow_node_run_test_case";
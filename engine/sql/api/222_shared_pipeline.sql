@@ -0,0 +1,38 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS "shared_pipeline" (
+    id BIGSERIAL PRIMARY KEY,
+    group_id BIGINT NOT NULL,
+    name VARCHAR(256) NOT NULL,
+    version BIGINT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    parameters JSONB,
+    value TEXT NOT NULL,
+    from_project_key VARCHAR(256) NOT NULL DEFAULT '',
+    from_pipeline_name VARCHAR(256) NOT NULL DEFAULT '',
+    author VARCHAR(256) NOT NULL DEFAULT '',
+    created TIMESTAMP WITH TIME ZONE DEFAULT LOCALTIMESTAMP
+);
+
+SELECT create_foreign_key_idx_cascade('FK_SHARED_PIPELINE_GROUP', 'shared_pipeline', 'group', 'group_id', 'id');
+SELECT create_unique_index('shared_pipeline', 'IDX_SHARED_PIPELINE_VERSION_UNIQ', 'group_id,name,version');
+
+CREATE TABLE IF NOT EXISTS "shared_pipeline_instance" (
+    id BIGSERIAL PRIMARY KEY,
+    group_id BIGINT NOT NULL,
+    name VARCHAR(256) NOT NULL,
+    version BIGINT NOT NULL,
+    project_id BIGINT NOT NULL,
+    pipeline_id BIGINT NOT NULL,
+    created TIMESTAMP WITH TIME ZONE DEFAULT LOCALTIMESTAMP,
+    updated TIMESTAMP WITH TIME ZONE DEFAULT LOCALTIMESTAMP
+);
+
+SELECT create_foreign_key_idx_cascade('FK_SHARED_PIPELINE_INSTANCE_GROUP', 'shared_pipeline_instance', 'group', 'group_id', 'id');
+SELECT create_foreign_key_idx_cascade('FK_SHARED_PIPELINE_INSTANCE_PROJECT', 'shared_pipeline_instance', 'project', 'project_id', 'id');
+SELECT create_foreign_key_idx_cascade('FK_SHARED_PIPELINE_INSTANCE_PIPELINE', 'shared_pipeline_instance', 'pipeline', 'pipeline_id', 'id');
+SELECT create_unique_index('shared_pipeline_instance', 'IDX_SHARED_PIPELINE_INSTANCE_PIPELINE_UNIQ', 'pipeline_id');
+SELECT create_index('shared_pipeline_instance', 'IDX_SHARED_PIPELINE_INSTANCE_NAME', 'group_id,name');
+
+-- +migrate Down
+DROP TABLE IF EXISTS "shared_pipeline_instance";
+DROP TABLE IF EXISTS "shared_pipeline";
@@ -0,0 +1,20 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS "workflow_run_archive" (
+    id BIGSERIAL PRIMARY KEY,
+    project_key VARCHAR(256) NOT NULL,
+    workflow_id BIGINT NOT NULL,
+    workflow_name VARCHAR(256) NOT NULL,
+    num BIGINT NOT NULL,
+    version VARCHAR(256),
+    status VARCHAR(50) NOT NULL,
+    start TIMESTAMP WITH TIME ZONE NOT NULL,
+    last_modified TIMESTAMP WITH TIME ZONE NOT NULL,
+    archived TIMESTAMP WITH TIME ZONE NOT NULL,
+    size BIGINT NOT NULL DEFAULT 0
+);
+
+SELECT create_unique_index('workflow_run_archive', 'IDX_WORKFLOW_RUN_ARCHIVE_NUM_UNIQ', 'workflow_id,num');
+SELECT create_index('workflow_run_archive', 'IDX_WORKFLOW_RUN_ARCHIVE_NAME', 'project_key,workflow_name');
+
+-- +migrate Down
+DROP TABLE IF EXISTS "workflow_run_archive";
@@ -0,0 +1,5 @@
+-- +migrate Up
+ALTER TABLE "workflow" ADD COLUMN IF NOT EXISTS purge_policy JSONB;
+
+-- +migrate Down
+ALTER TABLE "workflow" DROP COLUMN IF EXISTS purge_policy;
@@ -0,0 +1,25 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS "application_deployment" (
+    id BIGSERIAL PRIMARY KEY,
+    project_id BIGINT NOT NULL,
+    application_id BIGINT NOT NULL,
+    environment_id BIGINT NOT NULL,
+    workflow_id BIGINT NOT NULL,
+    workflow_run_id BIGINT NOT NULL,
+    workflow_node_run_id BIGINT NOT NULL,
+    num BIGINT NOT NULL,
+    status VARCHAR(50) NOT NULL,
+    vcs_hash VARCHAR(256),
+    commit_date TIMESTAMP WITH TIME ZONE,
+    start TIMESTAMP WITH TIME ZONE NOT NULL,
+    done TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+SELECT create_foreign_key_idx_cascade('FK_APPLICATION_DEPLOYMENT_PROJECT', 'application_deployment', 'project', 'project_id', 'id');
+SELECT create_foreign_key_idx_cascade('FK_APPLICATION_DEPLOYMENT_APPLICATION', 'application_deployment', 'application', 'application_id', 'id');
+SELECT create_foreign_key_idx_cascade('FK_APPLICATION_DEPLOYMENT_ENVIRONMENT', 'application_deployment', 'environment', 'environment_id', 'id');
+SELECT create_unique_index('application_deployment', 'IDX_APPLICATION_DEPLOYMENT_NODE_RUN', 'workflow_node_run_id');
+CREATE INDEX IF NOT EXISTS "IDX_APPLICATION_DEPLOYMENT_DONE" ON "application_deployment" ("project_id", "done");
+
+-- +migrate Down
+DROP TABLE IF EXISTS "application_deployment";
@@ -0,0 +1,26 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS "workflow_node_run_job_usage" (
+    id BIGSERIAL PRIMARY KEY,
+    workflow_node_run_job_id BIGINT NOT NULL,
+    workflow_node_run_id BIGINT NOT NULL,
+    project_id BIGINT NOT NULL,
+    job_name VARCHAR(256) NOT NULL,
+    status VARCHAR(50) NOT NULL,
+    model_name VARCHAR(256),
+    model_group_name VARCHAR(256),
+    model_type VARCHAR(50),
+    hatchery_name VARCHAR(256),
+    worker_name VARCHAR(256),
+    queued TIMESTAMP WITH TIME ZONE NOT NULL,
+    start TIMESTAMP WITH TIME ZONE NOT NULL,
+    done TIMESTAMP WITH TIME ZONE NOT NULL,
+    cpu_time DOUBLE PRECISION,
+    memory_peak BIGINT
+);
+
+SELECT create_foreign_key_idx_cascade('FK_WORKFLOW_NODE_RUN_JOB_USAGE_PROJECT', 'workflow_node_run_job_usage', 'project', 'project_id', 'id');
+SELECT create_unique_index('workflow_node_run_job_usage', 'IDX_WORKFLOW_NODE_RUN_JOB_USAGE_JOB', 'workflow_node_run_job_id');
+CREATE INDEX IF NOT EXISTS "IDX_WORKFLOW_NODE_RUN_JOB_USAGE_DONE" ON "workflow_node_run_job_usage" ("done");
+
+-- +migrate Down
+DROP TABLE IF EXISTS "workflow_node_run_job_usage";
@@ -0,0 +1,8 @@
+-- +migrate Up
+DELETE FROM "workflow_run_archive" WHERE workflow_id NOT IN (SELECT id FROM "workflow");
+SELECT create_foreign_key_idx_cascade('FK_WORKFLOW_RUN_ARCHIVE_WORKFLOW', 'workflow_run_archive', 'workflow', 'workflow_id', 'id');
+DROP INDEX IF EXISTS "idx_workflow_run_archive_name";
+
+-- +migrate Down
+ALTER TABLE "workflow_run_archive" DROP CONSTRAINT IF EXISTS "fk_workflow_run_archive_workflow";
+SELECT create_index('workflow_run_archive', 'IDX_WORKFLOW_RUN_ARCHIVE_NAME', 'project_key,workflow_name');
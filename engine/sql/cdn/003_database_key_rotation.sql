@@ -0,0 +1,18 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS "database_key_rotation" (
+    id BIGSERIAL PRIMARY KEY,
+    status VARCHAR(32) NOT NULL,
+    signature_key_timestamp BIGINT NOT NULL,
+    encryption_key_timestamp BIGINT NOT NULL,
+    entities JSONB,
+    retired_keys JSONB,
+    error TEXT NOT NULL DEFAULT '',
+    started TIMESTAMP WITH TIME ZONE NOT NULL,
+    last_modified TIMESTAMP WITH TIME ZONE NOT NULL,
+    done TIMESTAMP WITH TIME ZONE
+);
+
+SELECT create_index('database_key_rotation', 'IDX_DATABASE_KEY_ROTATION_STATUS', 'status');
+
+-- +migrate Down
+DROP TABLE IF EXISTS "database_key_rotation";
@@ -172,3 +172,39 @@ func (client *bitbucketcloudClient) CommitsBetweenRefs(ctx context.Context, repo
 
 	return commitsResult, nil
 }
+
+// FilesBetweenRefs returns the files changed on head since base.
+func (client *bitbucketcloudClient) FilesBetweenRefs(ctx context.Context, repo, base, head string) ([]string, error) {
+	path := fmt.Sprintf("/repositories/%s/diffstat/%s..%s", repo, head, base)
+	params := url.Values{}
+	var files []string
+	nextPage := 1
+	for {
+		if ctx.Err() != nil {
+			return nil, sdk.WithStack(ctx.Err())
+		}
+
+		if nextPage != 1 {
+			params.Set("page", fmt.Sprintf("%d", nextPage))
+		}
+
+		var response DiffStats
+		if err := client.do(ctx, "GET", "core", path, params, nil, &response); err != nil {
+			return nil, sdk.WrapError(err, "unable to get diffstat")
+		}
+		for _, d := range response.Values {
+			if d.New != nil {
+				files = append(files, d.New.Path)
+			}
+			if d.Old != nil && (d.New == nil || d.Old.Path != d.New.Path) {
+				files = append(files, d.Old.Path)
+			}
+		}
+
+		if response.Next == "" {
+			break
+		}
+		nextPage++
+	}
+	return files, nil
+}
@@ -345,6 +345,25 @@ type Branch struct {
 	} `json:"target"`
 }
 
+// DiffStats is the response of the diffstat API, it gives the files changed between two refs.
+type DiffStats struct {
+	Pagelen int        `json:"pagelen"`
+	Page    int        `json:"page"`
+	Size    int64      `json:"size"`
+	Values  []DiffStat `json:"values"`
+	Next    string     `json:"next"`
+}
+
+type DiffStat struct {
+	Status string        `json:"status"`
+	Old    *DiffStatFile `json:"old"`
+	New    *DiffStatFile `json:"new"`
+}
+
+type DiffStatFile struct {
+	Path string `json:"path"`
+}
+
 type Commits struct {
 	Pagelen  int      `json:"pagelen"`
 	Page     int      `json:"page"`
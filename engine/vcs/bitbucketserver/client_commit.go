@@ -116,6 +116,43 @@ func (b *bitbucketClient) Commit(ctx context.Context, repo, hash string) (sdk.VC
 	return commit, nil
 }
 
+// FilesBetweenRefs returns the files changed on head since base.
+func (b *bitbucketClient) FilesBetweenRefs(ctx context.Context, repo, base, head string) ([]string, error) {
+	project, slug, err := getRepo(repo)
+	if err != nil {
+		return nil, sdk.WithStack(err)
+	}
+
+	path := fmt.Sprintf("/projects/%s/repos/%s/compare/changes", project, slug)
+	params := url.Values{}
+	params.Add("from", head)
+	params.Add("to", base)
+
+	var files []string
+	var response ChangesResponse
+	for {
+		if response.NextPageStart != 0 {
+			params.Set("start", fmt.Sprintf("%d", response.NextPageStart))
+		}
+
+		response = ChangesResponse{}
+		if err := b.do(ctx, "GET", "core", path, params, nil, &response, nil); err != nil {
+			return nil, sdk.WrapError(err, "unable to get changes %s", path)
+		}
+
+		for _, c := range response.Values {
+			files = append(files, c.Path.ToString)
+			if c.SrcPath != nil {
+				files = append(files, c.SrcPath.ToString)
+			}
+		}
+		if response.IsLastPage {
+			break
+		}
+	}
+	return files, nil
+}
+
 func (b *bitbucketClient) CommitsBetweenRefs(ctx context.Context, repo, base, head string) ([]sdk.VCSCommit, error) {
 	var commits []sdk.VCSCommit
 	project, slug, err := getRepo(repo)
@@ -59,6 +59,24 @@ type Author struct {
 	Slug        string `json:"slug"`
 }
 
+// ChangesResponse is the response of the compare changes API.
+type ChangesResponse struct {
+	Values        []Change `json:"values"`
+	Size          int      `json:"size"`
+	NextPageStart int      `json:"nextPageStart"`
+	IsLastPage    bool     `json:"isLastPage"`
+}
+
+// Change is a file changed between two refs, SrcPath is set for moved files.
+type Change struct {
+	Path    ChangePath  `json:"path"`
+	SrcPath *ChangePath `json:"srcPath,omitempty"`
+}
+
+type ChangePath struct {
+	ToString string `json:"toString"`
+}
+
 type CommitsResponse struct {
 	Values        []Commit `json:"values"`
 	Size          int      `json:"size"`
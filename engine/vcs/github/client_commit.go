@@ -323,3 +323,31 @@ func (g *githubClient) CommitsBetweenRefs(ctx context.Context, repo, base, head
 
 	return commits, nil
 }
+
+// githubCompareMaxFiles is the maximum number of files returned by the compare API.
+const githubCompareMaxFiles = 300
+
+func (g *githubClient) FilesBetweenRefs(ctx context.Context, repo, base, head string) ([]string, error) {
+	url := fmt.Sprintf("/repos/%s/compare/%s...%s", repo, base, head)
+	status, body, _, err := g.get(ctx, url, withoutETag)
+	if err != nil {
+		return nil, err
+	}
+	if status >= 400 {
+		return nil, sdk.NewError(sdk.ErrRepoNotFound, errorAPI(body))
+	}
+
+	var diff DiffCommits
+	if err := json.Unmarshal(body, &diff); err != nil {
+		return nil, sdk.WrapError(err, "unable to parse github compare")
+	}
+	if len(diff.Files) >= githubCompareMaxFiles {
+		return nil, sdk.NewErrorFrom(sdk.ErrNotImplemented, "too many changed files between %s and %s", base, head)
+	}
+
+	files := make([]string, 0, len(diff.Files))
+	for _, f := range diff.Files {
+		files = append(files, f.Filename)
+	}
+	return files, nil
+}
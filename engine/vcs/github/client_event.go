@@ -118,8 +118,13 @@ func (g *githubClient) PushEvents(ctx context.Context, fullname string, iEvents
 	}
 
 	lastCommitPerBranch := map[string]sdk.VCSCommit{}
+	// keep the hash of each branch before its oldest push event to compute the changes of all the events
+	firstEventPerBranch := map[string]Event{}
 	for _, e := range events {
 		branch := strings.Replace(e.Payload.Ref, "refs/heads/", "", 1)
+		if f, ok := firstEventPerBranch[branch]; !ok || e.CreatedAt.Before(f.CreatedAt.Time) {
+			firstEventPerBranch[branch] = e
+		}
 		for _, c := range e.Payload.Commits {
 			commit := sdk.VCSCommit{
 				Hash:      c.Sha,
@@ -155,6 +160,7 @@ func (g *githubClient) PushEvents(ctx context.Context, fullname string, iEvents
 			Branch: *branch,
 			Commit: c,
 			Repo:   fullname,
+			Before: firstEventPerBranch[b].Payload.Before,
 		})
 	}
 
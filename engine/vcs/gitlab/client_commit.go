@@ -105,3 +105,30 @@ func (c *gitlabClient) CommitsBetweenRefs(ctx context.Context, repo, base, head
 
 	return vcscommits, nil
 }
+
+func (c *gitlabClient) FilesBetweenRefs(ctx context.Context, repo, base, head string) ([]string, error) {
+	opt := &gitlab.CompareOptions{
+		From: &base,
+		To:   &head,
+	}
+
+	compare, _, err := c.client.Repositories.Compare(repo, opt)
+	if err != nil {
+		return nil, sdk.WithStack(err)
+	}
+	if compare == nil {
+		return nil, nil
+	}
+	if compare.CompareTimeout {
+		return nil, sdk.NewErrorFrom(sdk.ErrNotImplemented, "compare timeout between %s and %s", base, head)
+	}
+
+	var files []string
+	for _, d := range compare.Diffs {
+		files = append(files, d.NewPath)
+		if d.RenamedFile {
+			files = append(files, d.OldPath)
+		}
+	}
+	return files, nil
+}
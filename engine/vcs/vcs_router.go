@@ -2,15 +2,10 @@ package vcs
 
 import (
 	"context"
-	"net/http"
-	"reflect"
-	"runtime"
-	"strings"
 
 	"github.com/ovh/cds/engine/api"
 	"github.com/ovh/cds/engine/service"
 	"github.com/ovh/cds/sdk/log"
-	"github.com/ovh/cds/sdk/telemetry"
 )
 
 func (s *Service) initRouter(ctx context.Context) {
@@ -19,8 +14,8 @@ func (s *Service) initRouter(ctx context.Context) {
 	r.Background = ctx
 	r.URL = s.Cfg.URL
 	r.SetHeaderFunc = api.DefaultHeaders
-	r.Middlewares = append(r.Middlewares, service.CheckRequestSignatureMiddleware(s.ParsedAPIPublicKey), s.authMiddleware, TracingMiddlewareFunc(s))
-	r.PostMiddlewares = append(r.PostMiddlewares, api.TracingPostMiddleware)
+	r.Middlewares = append(r.Middlewares, service.CheckRequestSignatureMiddleware(s.ParsedAPIPublicKey), s.authMiddleware, service.TracingMiddlewareFunc(s))
+	r.PostMiddlewares = append(r.PostMiddlewares, service.TracingPostMiddleware)
 
 	r.Handle("/mon/version", nil, r.GET(api.VersionHandler, api.Auth(false)))
 	r.Handle("/mon/status", nil, r.GET(s.statusHandler, api.Auth(false)))
@@ -41,6 +36,7 @@ func (s *Service) initRouter(ctx context.Context) {
 	r.Handle("/vcs/{name}/repos/{owner}/{repo}/branches/commits", nil, r.GET(s.getCommitsHandler))
 	r.Handle("/vcs/{name}/repos/{owner}/{repo}/tags", nil, r.GET(s.getTagsHandler))
 	r.Handle("/vcs/{name}/repos/{owner}/{repo}/commits", nil, r.GET(s.getCommitsBetweenRefsHandler))
+	r.Handle("/vcs/{name}/repos/{owner}/{repo}/changes", nil, r.GET(s.getFilesBetweenRefsHandler))
 	r.Handle("/vcs/{name}/repos/{owner}/{repo}/commits/{commit}", nil, r.GET(s.getCommitHandler))
 	r.Handle("/vcs/{name}/repos/{owner}/{repo}/commits/{commit}/statuses", nil, r.GET(s.getCommitStatusHandler))
 	r.Handle("/vcs/{name}/repos/{owner}/{repo}/grant", nil, r.POST(s.postRepoGrantHandler))
@@ -55,23 +51,3 @@ func (s *Service) initRouter(ctx context.Context) {
 
 	r.Handle("/vcs/{name}/status", nil, r.POST(s.postStatusHandler))
 }
-
-func TracingMiddlewareFunc(s service.Service) service.Middleware {
-	return func(ctx context.Context, w http.ResponseWriter, req *http.Request, rc *service.HandlerConfig) (context.Context, error) {
-		name := runtime.FuncForPC(reflect.ValueOf(rc.Handler).Pointer()).Name()
-		name = strings.Replace(name, ".func1", "", 1)
-
-		splittedName := strings.Split(name, ".")
-		name = splittedName[len(splittedName)-1]
-
-		opts := telemetry.Options{
-			Name: name,
-		}
-
-		ctx, err := telemetry.Start(ctx, s, w, req, opts)
-		newReq := req.WithContext(ctx)
-		*req = *newReq
-
-		return ctx, err
-	}
-}
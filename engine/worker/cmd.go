@@ -34,6 +34,7 @@ const (
 	flagName                = "name"
 	flagModel               = "model"
 	flagHatcheryName        = "hatchery-name"
+	flagOTLPEndpoint        = "otlp-endpoint"
 )
 
 func initFlagsRun(cmd *cobra.Command) {
@@ -53,6 +54,7 @@ func initFlagsRun(cmd *cobra.Command) {
 	flags.String(flagName, "", "Name of worker")
 	flags.String(flagModel, "", "Model of worker")
 	flags.String(flagHatcheryName, "", "Hatchery Name spawing worker")
+	flags.String(flagOTLPEndpoint, "", "OpenTelemetry collector OTLP/HTTP endpoint to export job traces. Ex: --otlp-endpoint=http://localhost:4318")
 }
 
 // FlagBool replaces viper.GetBool
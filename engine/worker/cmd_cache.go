@@ -13,6 +13,7 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/spf13/afero"
 	"github.com/spf13/cobra"
 
 	"github.com/ovh/cds/engine/worker/internal"
@@ -29,6 +30,8 @@ You can access to this cache from any workflow inside a project. You just have t
 
 For example if you need a different cache for each workflow so choose a tag scoped with your workflow name and workflow version (example of tag value: {{.cds.workflow}}-{{.cds.version}})
 
+The tag can contain hashFiles("pattern", ...) calls that are replaced by a hash of the files matching the patterns, so the cache is renewed when your lockfiles change (example of tag value: maven-hashFiles("**/pom.xml")).
+
 ## Use Case
 Java Developers often use maven to manage dependencies. The mvn install command could be long because all the maven dependencies have to be downloaded on a fresh CDS Job workspace.
 With the worker cache feature, you don't have to download the dependencies if they haven't been updated since the last run of the job.
@@ -41,10 +44,10 @@ Here, an example of a script inside a CDS Job using the cache feature:
 
 	#!/bin/bash
 
-	tag=($(md5sum pom.xml))
+	tag='maven-hashFiles("pom.xml")'
 
-	# download the cache of .m2/
-	if worker cache pull $tag; then
+	# download the cache of .m2/, or the most recent maven cache if pom.xml changed
+	if worker cache pull --restore-key maven- $tag; then
 		echo ".m2/ getted from cache";
 	fi
 
@@ -63,7 +66,10 @@ Here, an example of a script inside a CDS Job using the cache feature:
 	return cmdCacheRoot
 }
 
-var cmdStorageIntegrationName string
+var (
+	cmdStorageIntegrationName string
+	cmdCacheRestoreKeys       []string
+)
 
 func cmdCachePush() *cobra.Command {
 	c := &cobra.Command{
@@ -110,8 +116,14 @@ func cachePushCmd() func(cmd *cobra.Command, args []string) {
 			sdk.Exit("worker cache push > Cannot find working directory : %s", err)
 		}
 
+		key, err := sdk.ComputeCacheKey(afero.NewOsFs(), cwd, args[0])
+		if err != nil {
+			sdk.Exit("worker cache push > Cannot compute cache key : %s", err)
+		}
+
 		c := sdk.Cache{
-			Tag:              base64.RawURLEncoding.EncodeToString([]byte(args[0])),
+			Tag:              base64.RawURLEncoding.EncodeToString([]byte(key)),
+			Key:              key,
 			Files:            files,
 			WorkingDirectory: cwd,
 			IntegrationName:  cmdStorageIntegrationName,
@@ -122,7 +134,7 @@ func cachePushCmd() func(cmd *cobra.Command, args []string) {
 			sdk.Exit("worker cache push > internal error (%s)", errMarshal)
 		}
 
-		fmt.Printf("Worker cache push in progress... (tag: %s)\n", args[0])
+		fmt.Printf("Worker cache push in progress... (tag: %s)\n", key)
 		req, errRequest := http.NewRequest(
 			"POST",
 			fmt.Sprintf("http://127.0.0.1:%d/cache/push", port),
@@ -150,7 +162,7 @@ func cachePushCmd() func(cmd *cobra.Command, args []string) {
 			sdk.Exit("Error: http code %d : %v", resp.StatusCode, cdsError)
 		}
 
-		fmt.Printf("Worker cache push with success (tag: %s)\n", args[0])
+		fmt.Printf("Worker cache push with success (tag: %s)\n", key)
 	}
 }
 
@@ -178,10 +190,15 @@ If you want to push a cache into a storage integration:
 
 	worker cache push latest --from=MyStorageIntegration {{.cds.workspace}}/pathToUpload
 
+If there is no cache for the tag, you can pull the most recent cache with a tag that starts with a restore key:
+
+	worker cache pull --restore-key go- go-hashFiles("go.sum")
+
 		`,
 		Run: cachePullCmd(),
 	}
 	c.Flags().StringVar(&cmdStorageIntegrationName, "from", "", "optional. Your storage integration name")
+	c.Flags().StringSliceVar(&cmdCacheRestoreKeys, "restore-key", nil, "optional. Tag prefix used to pull a cache if there is no cache for the tag, can be repeated")
 	return c
 }
 
@@ -206,13 +223,30 @@ func cachePullCmd() func(cmd *cobra.Command, args []string) {
 			sdk.Exit("worker cache pull > cannot get current path: %s", err)
 		}
 
-		fmt.Printf("Worker cache pull in progress... (tag: %s)\n", args[0])
+		cwd, err := os.Getwd()
+		if err != nil {
+			sdk.Exit("worker cache pull > Cannot find working directory : %s", err)
+		}
+
+		key, err := sdk.ComputeCacheKey(afero.NewOsFs(), cwd, args[0])
+		if err != nil {
+			sdk.Exit("worker cache pull > Cannot compute cache key : %s", err)
+		}
+
+		query := url.Values{}
+		query.Set("path", dir)
+		query.Set("integration", cmdStorageIntegrationName)
+		query.Set("key", key)
+		for _, k := range cmdCacheRestoreKeys {
+			query.Add("restoreKey", k)
+		}
+
+		fmt.Printf("Worker cache pull in progress... (tag: %s)\n", key)
 		req, errRequest := http.NewRequest(
 			"GET",
-			fmt.Sprintf("http://127.0.0.1:%d/cache/%s/pull?path=%s&integration=%s", port,
-				base64.RawURLEncoding.EncodeToString([]byte(args[0])),
-				url.QueryEscape(dir),
-				url.QueryEscape(cmdStorageIntegrationName)),
+			fmt.Sprintf("http://127.0.0.1:%d/cache/%s/pull?%s", port,
+				base64.RawURLEncoding.EncodeToString([]byte(key)),
+				query.Encode()),
 			nil,
 		)
 		if errRequest != nil {
@@ -236,6 +270,6 @@ func cachePullCmd() func(cmd *cobra.Command, args []string) {
 			sdk.Exit("Error: %v", cdsError)
 		}
 
-		fmt.Printf("Worker cache pull with success (tag: %s)\n", args[0])
+		fmt.Printf("Worker cache pull with success (tag: %s)\n", key)
 	}
 }
@@ -14,6 +14,7 @@ import (
 	"github.com/ovh/cds/engine/worker/internal"
 	"github.com/ovh/cds/sdk"
 	"github.com/ovh/cds/sdk/log"
+	"github.com/ovh/cds/sdk/telemetry"
 )
 
 func cmdRun() *cobra.Command {
@@ -44,6 +45,18 @@ func runCmd() func(cmd *cobra.Command, args []string) {
 			sdk.Exit("flag --booked-workflow-job-id is mandatory")
 		}
 
+		// Export traces of the job if a collector is given, only the jobs of sampled workflow runs are traced
+		if endpoint := FlagString(cmd, flagOTLPEndpoint); endpoint != "" {
+			var cfg telemetry.Configuration
+			cfg.TracingEnabled = true
+			cfg.Exporters.OTLP.Endpoint = endpoint
+			var err error
+			ctx, err = telemetry.Init(ctx, cfg, w)
+			if err != nil {
+				log.Error(ctx, "unable to init telemetry: %v", err)
+			}
+		}
+
 		ctx, cancel := context.WithCancel(ctx)
 		// Gracefully shutdown connections
 		c := make(chan os.Signal, 1)
@@ -64,7 +77,11 @@ func runCmd() func(cmd *cobra.Command, args []string) {
 			}
 		}()
 		// Start the worker
-		if err := internal.StartWorker(ctx, w, bookedWJobID); err != nil {
+		err := internal.StartWorker(ctx, w, bookedWJobID)
+		if errF := telemetry.Flush(ctx); errF != nil {
+			log.Error(ctx, "unable to flush telemetry: %v", errF)
+		}
+		if err != nil {
 			isErrWithStack := sdk.IsErrorWithStack(err)
 			fields := logrus.Fields{}
 			if isErrWithStack {
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ovh/cds/engine/worker/internal"
+	"github.com/ovh/cds/sdk"
+)
+
+var (
+	cmdSARIFFailOnSeverity string
+	cmdSARIFNewOnly        bool
+)
+
+func cmdSARIF() *cobra.Command {
+	c := &cobra.Command{
+		Use:   "sarif",
+		Short: "worker sarif [--fail-on=<severity>] [--new-only] <file>...",
+		Long: `
+Inside a job, you can import static analysis and security findings from SARIF 2.1 files:
+
+	worker sarif results/*.sarif
+
+Findings are stored on the workflow node run and compared to the latest analysis on the default branch.
+
+With --fail-on, the command exits with an error if a finding has the given severity or higher (negligible, low, medium, high or critical).
+With --new-only, only findings that don't exist on the default branch are checked.
+
+	worker sarif --fail-on=high --new-only gosec.sarif
+		`,
+		Run: sarifCmd(),
+	}
+	c.Flags().StringVar(&cmdSARIFFailOnSeverity, "fail-on", "", "optional. Minimum severity of a finding that fails the command")
+	c.Flags().BoolVar(&cmdSARIFNewOnly, "new-only", false, "optional. Only new findings compared to the default branch fail the command")
+	return c
+}
+
+func sarifCmd() func(cmd *cobra.Command, args []string) {
+	return func(cmd *cobra.Command, args []string) {
+		portS := os.Getenv(internal.WorkerServerPort)
+		if portS == "" {
+			sdk.Exit("worker sarif > %s not found, are you running inside a CDS worker job?\n", internal.WorkerServerPort)
+		}
+
+		port, errPort := strconv.Atoi(portS)
+		if errPort != nil {
+			sdk.Exit("worker sarif > cannot parse '%s' as a port number: %s\n", portS, errPort)
+		}
+
+		if len(args) == 0 {
+			sdk.Exit("worker sarif > Wrong usage: Example : worker sarif results.sarif\n")
+		}
+		if cmdSARIFFailOnSeverity != "" && sdk.SeverityLevel(cmdSARIFFailOnSeverity) == 0 {
+			sdk.Exit("worker sarif > unknown severity %q\n", cmdSARIFFailOnSeverity)
+		}
+
+		var files []string
+		for _, arg := range args {
+			matches, err := filepath.Glob(arg)
+			if err != nil {
+				sdk.Exit("worker sarif > invalid pattern %s: %s\n", arg, err)
+			}
+			files = append(files, matches...)
+		}
+		if len(files) == 0 {
+			sdk.Exit("worker sarif > no file found\n")
+		}
+
+		var failures int
+		for _, f := range files {
+			data, err := ioutil.ReadFile(f)
+			if err != nil {
+				sdk.Exit("worker sarif > cannot read file %s: %s\n", f, err)
+			}
+			reports, err := sdk.ParseSARIF(data)
+			if err != nil {
+				sdk.Exit("worker sarif > cannot parse file %s: %s\n", f, err)
+			}
+			for _, r := range reports {
+				analysis := sendAnalysis(port, r)
+				var nbNew int
+				for _, finding := range analysis.Findings {
+					if finding.Status == sdk.AnalysisFindingStatusNew {
+						nbNew++
+					}
+				}
+				fmt.Printf("%s: %d findings, %d new, %d fixed compared to default branch\n", analysis.Tool, len(analysis.Findings), nbNew, len(analysis.Fixed))
+				for _, finding := range analysis.FindingsAboveSeverity(cmdSARIFFailOnSeverity, cmdSARIFNewOnly) {
+					fmt.Printf("%s %s finding %s at %s:%d: %s\n", finding.Status, finding.Severity, finding.RuleID, finding.Path, finding.Line, finding.Message)
+					failures++
+				}
+			}
+		}
+
+		if failures > 0 {
+			sdk.Exit("worker sarif > %d findings with severity %s or higher\n", failures, cmdSARIFFailOnSeverity)
+		}
+	}
+}
+
+func sendAnalysis(port int, report sdk.AnalysisWorkerReport) sdk.WorkflowNodeRunAnalysis {
+	data, err := json.Marshal(report)
+	if err != nil {
+		sdk.Exit("worker sarif > internal error (%s)\n", err)
+	}
+
+	req, err := http.NewRequest("POST", fmt.Sprintf("http://127.0.0.1:%d/analysis", port), bytes.NewReader(data))
+	if err != nil {
+		sdk.Exit("worker sarif > cannot post analysis (Request): %s\n", err)
+	}
+
+	client := http.DefaultClient
+	client.Timeout = 5 * time.Minute
+
+	resp, err := client.Do(req)
+	if err != nil {
+		sdk.Exit("worker sarif > cannot post analysis (Do): %s\n", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		sdk.Exit("worker sarif > cannot read body: %s\n", err)
+	}
+	if resp.StatusCode >= 300 {
+		sdk.Exit("worker sarif > analysis failed: %v\n", sdk.DecodeError(body))
+	}
+
+	var analysis sdk.WorkflowNodeRunAnalysis
+	if err := json.Unmarshal(body, &analysis); err != nil {
+		sdk.Exit("worker sarif > cannot unmarshal analysis: %s\n", err)
+	}
+	return analysis
+}
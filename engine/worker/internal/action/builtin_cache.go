@@ -0,0 +1,136 @@
+package action
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/afero"
+
+	"github.com/ovh/cds/engine/worker/pkg/workerruntime"
+	"github.com/ovh/cds/sdk"
+)
+
+func RunCache(ctx context.Context, wk workerruntime.Runtime, a sdk.Action, secrets []sdk.Variable) (sdk.Result, error) {
+	res := sdk.Result{Status: sdk.StatusSuccess}
+
+	workdir, err := workerruntime.WorkingDirectory(ctx)
+	if err != nil {
+		return res, err
+	}
+
+	var abs string
+	if x, ok := wk.BaseDir().(*afero.BasePathFs); ok {
+		abs, _ = x.RealPath(workdir.Name())
+	} else {
+		abs = workdir.Name()
+	}
+
+	keyParam := strings.TrimSpace(sdk.ParameterValue(a.Parameters, "key"))
+	if keyParam == "" {
+		return res, sdk.NewError(sdk.ErrWorkerErrorCommand, fmt.Errorf("key parameter is empty. aborting"))
+	}
+	key, err := sdk.ComputeCacheKey(afero.NewOsFs(), abs, keyParam)
+	if err != nil {
+		return res, sdk.NewError(sdk.ErrWorkerErrorCommand, fmt.Errorf("cannot compute cache key: %v", err))
+	}
+
+	paths := splitLines(sdk.ParameterValue(a.Parameters, "path"))
+	if len(paths) == 0 {
+		return res, sdk.NewError(sdk.ErrWorkerErrorCommand, fmt.Errorf("path parameter is empty. aborting"))
+	}
+	restoreKeys := splitLines(sdk.ParameterValue(a.Parameters, "restoreKeys"))
+
+	integrationName := sdk.DefaultIfEmptyStorage(strings.TrimSpace(sdk.ParameterValue(a.Parameters, "destination")))
+	projectKey := sdk.ParameterValue(wk.Parameters(), "cds.project")
+
+	wk.SendLog(ctx, workerruntime.LevelInfo, fmt.Sprintf("Cache key: %s", key))
+
+	// A missing or broken cache must not fail the job, it will be saved again at the end of the job
+	var hit bool
+	c, err := wk.Client().WorkflowCacheLookup(projectKey, integrationName, key, restoreKeys)
+	if err != nil {
+		wk.SendLog(ctx, workerruntime.LevelInfo, fmt.Sprintf("No cache found for key %s", key))
+	} else {
+		if err := restoreCache(wk, projectKey, integrationName, c.Tag, abs); err != nil {
+			wk.SendLog(ctx, workerruntime.LevelWarn, fmt.Sprintf("Unable to restore cache %s: %v", c.Key, err))
+		} else {
+			hit = c.Key == key
+			wk.SendLog(ctx, workerruntime.LevelInfo, fmt.Sprintf("Cache restored from key %s", c.Key))
+		}
+	}
+
+	if hit {
+		return res, nil
+	}
+
+	wk.RegisterPostJobAction("Save cache "+key, func(ctx context.Context) error {
+		return saveCache(ctx, wk, projectKey, integrationName, key, abs, paths)
+	})
+
+	return res, nil
+}
+
+func restoreCache(wk workerruntime.Runtime, projectKey, integrationName, tag, dir string) error {
+	r, err := wk.Client().WorkflowCachePull(projectKey, integrationName, tag)
+	if err != nil {
+		return err
+	}
+	return sdk.ExtractTar(r, dir)
+}
+
+func saveCache(ctx context.Context, wk workerruntime.Runtime, projectKey, integrationName, key, dir string, paths []string) error {
+	var existingPaths []string
+	for _, p := range paths {
+		if _, err := os.Stat(filepath.Join(dir, p)); err == nil {
+			existingPaths = append(existingPaths, p)
+		}
+	}
+	if len(existingPaths) == 0 {
+		wk.SendLog(ctx, workerruntime.LevelWarn, fmt.Sprintf("No file to cache for key %s", key))
+		return nil
+	}
+
+	tmpDir, err := workerruntime.TmpDirectory(ctx)
+	if err != nil {
+		return err
+	}
+	tarF, err := afero.TempFile(wk.BaseDir(), tmpDir.Name(), "cache-")
+	if err != nil {
+		return sdk.WithStack(err)
+	}
+	defer wk.BaseDir().Remove(tarF.Name()) // nolint
+	defer tarF.Close()                     // nolint
+
+	if err := sdk.CreateTarFromPaths(afero.NewOsFs(), dir, existingPaths, tarF, nil); err != nil {
+		return sdk.WrapError(err, "cannot tar %v", existingPaths)
+	}
+	fi, err := tarF.Stat()
+	if err != nil {
+		return sdk.WithStack(err)
+	}
+	if _, err := tarF.Seek(0, 0); err != nil {
+		return sdk.WithStack(err)
+	}
+
+	tag := base64.RawURLEncoding.EncodeToString([]byte(key))
+	if err := wk.Client().WorkflowCachePush(projectKey, integrationName, tag, key, tarF, int(fi.Size())); err != nil {
+		return err
+	}
+
+	wk.SendLog(ctx, workerruntime.LevelInfo, fmt.Sprintf("Cache saved with key %s (%d bytes)", key, fi.Size()))
+	return nil
+}
+
+func splitLines(s string) []string {
+	var res []string
+	for _, l := range strings.Split(s, "\n") {
+		if l = strings.TrimSpace(l); l != "" {
+			res = append(res, l)
+		}
+	}
+	return res
+}
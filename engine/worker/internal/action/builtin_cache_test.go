@@ -0,0 +1,80 @@
+package action
+
+import (
+	"bytes"
+	"encoding/base64"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/h2non/gock.v1"
+
+	"github.com/ovh/cds/engine/worker/pkg/workerruntime"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/cdsclient"
+)
+
+func TestRunCache(t *testing.T) {
+	defer gock.Off()
+	wk, ctx := SetupTest(t)
+	wk.Params = []sdk.Parameter{
+		{Name: "cds.project", Value: "project"},
+	}
+
+	require.NoError(t, wk.BaseDir().Mkdir("tmp", os.FileMode(0755)))
+	tmpDir, err := wk.BaseDir().Open("tmp")
+	require.NoError(t, err)
+	ctx = workerruntime.SetTmpDirectory(ctx, tmpDir)
+
+	workdir, err := wk.BaseDir().(*afero.BasePathFs).RealPath(wk.workingDirectory.Name())
+	require.NoError(t, err)
+	require.NoError(t, ioutil.WriteFile(filepath.Join(workdir, "go.sum"), []byte("deps"), os.ModePerm))
+	key, err := sdk.ComputeCacheKey(afero.NewOsFs(), workdir, `go-hashFiles("go.sum")`)
+	require.NoError(t, err)
+
+	// Build the archive of an older cache restored from the restore key
+	src, err := ioutil.TempDir("", "cache")
+	require.NoError(t, err)
+	defer os.RemoveAll(src) // nolint
+	require.NoError(t, os.MkdirAll(filepath.Join(src, "vendor"), os.ModePerm))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(src, "vendor", "lib.txt"), []byte("lib"), os.ModePerm))
+	var buf bytes.Buffer
+	require.NoError(t, sdk.CreateTarFromPaths(afero.NewOsFs(), src, []string{"vendor"}, &buf, nil))
+
+	gock.New("http://lolcat.host").Get("/project/project/storage/shared.infra/cache$").
+		MatchParam("key", key).MatchParam("restoreKey", "go-").
+		Reply(200).JSON(sdk.Cache{Tag: "b2xk", Key: "go-old"})
+	gock.New("http://lolcat.host").Get("/project/project/storage/shared.infra$").
+		Times(2).Reply(200).JSON(sdk.ArtifactsStore{})
+	gock.New("http://lolcat.host").Get("/project/project/storage/shared.infra/cache/b2xk").
+		Reply(200).BodyString(buf.String())
+	gock.New("http://lolcat.host").Post("/project/project/storage/shared.infra/cache/" + base64.RawURLEncoding.EncodeToString([]byte(key))).
+		Reply(200)
+
+	gock.InterceptClient(wk.Client().(cdsclient.Raw).HTTPClient())
+	gock.InterceptClient(wk.Client().(cdsclient.Raw).HTTPSSEClient())
+
+	res, err := RunCache(ctx, wk, sdk.Action{
+		Parameters: []sdk.Parameter{
+			{Name: "key", Value: `go-hashFiles("go.sum")`},
+			{Name: "restoreKeys", Value: "go-\n"},
+			{Name: "path", Value: "vendor\nunknown"},
+		},
+	}, nil)
+	require.NoError(t, err)
+	require.Equal(t, sdk.StatusSuccess, res.Status)
+
+	content, err := ioutil.ReadFile(filepath.Join(workdir, "vendor", "lib.txt"))
+	require.NoError(t, err)
+	require.Equal(t, "lib", string(content))
+
+	// The cache was restored from a restore key, so it is saved at the end of the job
+	require.Len(t, wk.PostJobActions, 1)
+	for _, f := range wk.PostJobActions {
+		require.NoError(t, f(ctx))
+	}
+	require.True(t, gock.IsDone())
+}
@@ -0,0 +1,87 @@
+package action
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/afero"
+
+	"github.com/ovh/cds/engine/worker/pkg/workerruntime"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/exportentities"
+)
+
+func RunChildPipelines(ctx context.Context, wk workerruntime.Runtime, a sdk.Action, secrets []sdk.Variable) (sdk.Result, error) {
+	res := sdk.Result{Status: sdk.StatusFail}
+
+	p := strings.TrimSpace(sdk.ParameterValue(a.Parameters, "path"))
+	if p == "" {
+		return res, fmt.Errorf("childPipelines: path not provided")
+	}
+
+	workdir, err := workerruntime.WorkingDirectory(ctx)
+	if err != nil {
+		return res, err
+	}
+	var abs string
+	if x, ok := wk.BaseDir().(*afero.BasePathFs); ok {
+		abs, _ = x.RealPath(workdir.Name())
+	} else {
+		abs = workdir.Name()
+	}
+	if !sdk.PathIsAbs(p) {
+		p = filepath.Join(abs, p)
+	}
+
+	files, err := afero.Glob(afero.NewOsFs(), p)
+	if err != nil {
+		return res, fmt.Errorf("childPipelines: unable to find files %s: %v", p, err)
+	}
+	if len(files) == 0 {
+		return res, fmt.Errorf("childPipelines: no file found for %s", p)
+	}
+
+	// Pipelines are checked before sending them so the errors are displayed with the file name
+	var req sdk.ChildPipelinesRequest
+	for _, f := range files {
+		data, err := ioutil.ReadFile(f)
+		if err != nil {
+			return res, fmt.Errorf("childPipelines: cannot read file %s: %v", f, err)
+		}
+		format, err := exportentities.GetFormatFromPath(f)
+		if err != nil {
+			format = exportentities.FormatYAML
+		}
+		payload, err := exportentities.ParsePipeline(format, data)
+		if err != nil {
+			return res, fmt.Errorf("childPipelines: invalid pipeline file %s: %v", f, sdk.Cause(err))
+		}
+		if _, err := payload.Pipeline(); err != nil {
+			return res, fmt.Errorf("childPipelines: invalid pipeline file %s: %v", f, sdk.Cause(err))
+		}
+		btes, err := exportentities.Marshal(payload, exportentities.FormatYAML)
+		if err != nil {
+			return res, fmt.Errorf("childPipelines: cannot marshal pipeline file %s: %v", f, err)
+		}
+		req.Pipelines = append(req.Pipelines, string(btes))
+	}
+
+	jobID, err := workerruntime.JobID(ctx)
+	if err != nil {
+		return res, err
+	}
+
+	nodes, err := wk.Client().QueueAddChildPipelines(ctx, jobID, req)
+	if err != nil {
+		return res, fmt.Errorf("childPipelines: cannot add child pipelines: %v", err)
+	}
+	for _, n := range nodes {
+		wk.SendLog(ctx, workerruntime.LevelInfo, fmt.Sprintf("Child pipeline %s added", n.Name))
+	}
+
+	res.Status = sdk.StatusSuccess
+	return res, nil
+}
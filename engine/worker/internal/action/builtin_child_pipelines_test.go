@@ -0,0 +1,68 @@
+package action
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/h2non/gock.v1"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/cdsclient"
+)
+
+const childPipeline = `version: v1.0
+name: build-api
+jobs:
+- job: Build
+  steps:
+  - script:
+    - make build
+`
+
+func TestRunChildPipelines(t *testing.T) {
+	defer gock.Off()
+	wk, ctx := SetupTest(t)
+
+	workdir, err := wk.BaseDir().(*afero.BasePathFs).RealPath(wk.workingDirectory.Name())
+	require.NoError(t, err)
+	require.NoError(t, os.MkdirAll(filepath.Join(workdir, "pipelines"), os.ModePerm))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(workdir, "pipelines", "api.yml"), []byte(childPipeline), os.ModePerm))
+
+	// Use a dedicated matcher, AddMatcher would change the default one used by the other tests
+	gock.New("http://lolcat.host").Post("/queue/workflows/666/pipelines$").
+		SetMatcher(gock.NewMatcher()).
+		AddMatcher(func(r *http.Request, _ *gock.Request) (bool, error) {
+			var req sdk.ChildPipelinesRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				return false, err
+			}
+			return len(req.Pipelines) == 1, nil
+		}).
+		Reply(200).JSON([]sdk.Node{{Name: "build-api", ChildDepth: 1}})
+	gock.InterceptClient(wk.Client().(cdsclient.Raw).HTTPClient())
+
+	res, err := RunChildPipelines(ctx, wk, sdk.Action{
+		Parameters: []sdk.Parameter{
+			{Name: "path", Value: "pipelines/*.yml"},
+		},
+	}, nil)
+	require.NoError(t, err)
+	require.Equal(t, sdk.StatusSuccess, res.Status)
+	require.True(t, gock.IsDone())
+
+	// An invalid pipeline file is not sent
+	require.NoError(t, ioutil.WriteFile(filepath.Join(workdir, "pipelines", "invalid.yml"), []byte("version: v2.0\nname: invalid\n"), os.ModePerm))
+	res, err = RunChildPipelines(ctx, wk, sdk.Action{
+		Parameters: []sdk.Parameter{
+			{Name: "path", Value: "pipelines/*.yml"},
+		},
+	}, nil)
+	require.Error(t, err)
+	require.Equal(t, sdk.StatusFail, res.Status)
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"path/filepath"
 	"strconv"
+	"strings"
 
 	coverage "github.com/sguiheux/go-coverage"
 	"github.com/spf13/afero"
@@ -26,16 +27,17 @@ func RunParseCoverageResultAction(ctx context.Context, wk workerruntime.Runtime,
 		return res, fmt.Errorf("coverage parser: format not provided")
 	}
 
-	var minReq float64
-	minimum := sdk.ParameterValue(a.Parameters, "minimum")
-	if minimum == "" {
-		minReq = -1
-	} else {
-		f, errMin := strconv.ParseFloat(minimum, 64)
-		if errMin != nil {
-			return res, fmt.Errorf("coverage parser: wrong value for 'minimum': %s", errMin)
-		}
-		minReq = f
+	minReq, err := coverageThreshold(a.Parameters, "minimum")
+	if err != nil {
+		return res, err
+	}
+	minBranchReq, err := coverageThreshold(a.Parameters, "minimumBranch")
+	if err != nil {
+		return res, err
+	}
+	maxDrop, err := coverageThreshold(a.Parameters, "maximumDrop")
+	if err != nil {
+		return res, err
 	}
 
 	var parserMode coverage.CoverageMode
@@ -80,17 +82,56 @@ func RunParseCoverageResultAction(ctx context.Context, wk workerruntime.Runtime,
 		return res, err
 	}
 
-	if err := wk.Client().QueueSendCoverage(ctx, jobID, report); err != nil {
+	cov, err := wk.Client().QueueSendCoverage(ctx, jobID, report)
+	if err != nil {
 		return res, fmt.Errorf("coverage parser: failed to send coverage details: %s", err)
 	}
 
-	if minReq > 0 {
-		covPercent := (float64(report.CoveredLines) / float64(report.TotalLines)) * 100
-		if covPercent < minReq {
-			return res, fmt.Errorf("coverage: minimum coverage failed: %.2f%% < %.2f%%", covPercent, minReq)
+	lines := sdk.CoveragePercent(report.CoveredLines, report.TotalLines)
+	branches := sdk.CoveragePercent(report.CoveredBranches, report.TotalBranches)
+	defaultLines := sdk.CoveragePercent(cov.Trend.DefaultBranch.CoveredLines, cov.Trend.DefaultBranch.TotalLines)
+
+	summary := fmt.Sprintf("Line coverage: %.2f%%", lines)
+	if branches >= 0 {
+		summary += fmt.Sprintf(", branch coverage: %.2f%%", branches)
+	}
+	if defaultLines >= 0 {
+		summary += fmt.Sprintf(", default branch line coverage: %.2f%% (%+.2f%%)", defaultLines, lines-defaultLines)
+	}
+	wk.SendLog(ctx, workerruntime.LevelInfo, summary)
+	for _, f := range cov.Trend.FilesDiff {
+		if f.Delta < 0 {
+			wk.SendLog(ctx, workerruntime.LevelInfo, fmt.Sprintf("Coverage of %s dropped: %.2f%% -> %.2f%%", f.Path, f.DefaultLines, f.Lines))
 		}
 	}
 
+	var failures []string
+	if minReq > 0 && lines < minReq {
+		failures = append(failures, fmt.Sprintf("line coverage %.2f%% < %.2f%%", lines, minReq))
+	}
+	if minBranchReq > 0 && branches >= 0 && branches < minBranchReq {
+		failures = append(failures, fmt.Sprintf("branch coverage %.2f%% < %.2f%%", branches, minBranchReq))
+	}
+	if maxDrop >= 0 && defaultLines >= 0 && defaultLines-lines > maxDrop {
+		failures = append(failures, fmt.Sprintf("line coverage dropped by %.2f%% compared to default branch (%.2f%% -> %.2f%%), maximum allowed drop is %.2f%%", defaultLines-lines, defaultLines, lines, maxDrop))
+	}
+	if len(failures) > 0 {
+		return res, fmt.Errorf("coverage: minimum coverage failed: %s", strings.Join(failures, ", "))
+	}
+
 	res.Status = sdk.StatusSuccess
 	return res, nil
 }
+
+// coverageThreshold returns the value of a threshold parameter, -1 if not set.
+func coverageThreshold(params []sdk.Parameter, name string) (float64, error) {
+	v := strings.TrimSpace(sdk.ParameterValue(params, name))
+	if v == "" {
+		return -1, nil
+	}
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		return -1, fmt.Errorf("coverage parser: wrong value for '%s': %s", name, err)
+	}
+	return f, nil
+}
@@ -217,6 +217,42 @@ func TestRunCoverageMinimumFail(t *testing.T) {
 	assert.Equal(t, sdk.StatusFail, res.Status)
 }
 
+func TestRunCoverageTrendFail(t *testing.T) {
+	defer gock.Off()
+
+	wk, ctx := SetupTest(t)
+	assert.NoError(t, ioutil.WriteFile("results.xml", []byte(cobertura_result), os.ModePerm))
+	defer os.RemoveAll("results.xml")
+
+	fiPath, err := filepath.Abs("results.xml")
+	require.NoError(t, err)
+
+	gock.New("http://lolcat.host").Post("/queue/workflows/666/coverage").
+		Reply(200).JSON(sdk.WorkflowNodeRunCoverage{
+		Trend: sdk.WorkflowNodeRunCoverageTrends{
+			DefaultBranch: coverage.Report{TotalLines: 8, CoveredLines: 8},
+		},
+	})
+
+	gock.InterceptClient(wk.Client().(cdsclient.Raw).HTTPClient())
+	gock.InterceptClient(wk.Client().(cdsclient.Raw).HTTPSSEClient())
+	res, err := RunParseCoverageResultAction(ctx, wk,
+		sdk.Action{
+			Parameters: []sdk.Parameter{
+				{Name: "path", Value: fiPath},
+				{Name: "format", Value: "cobertura"},
+				{Name: "minimum", Value: "50"},
+				{Name: "minimumBranch", Value: "60"},
+				{Name: "maximumDrop", Value: "5"},
+			},
+		}, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "branch coverage 50.00% < 60.00%")
+	assert.Contains(t, err.Error(), "line coverage dropped by 25.00% compared to default branch (100.00% -> 75.00%)")
+	assert.NotContains(t, err.Error(), "line coverage 75.00%")
+	assert.Equal(t, sdk.StatusFail, res.Status)
+}
+
 const cobertura_result = `<?xml version="1.0" ?>
 <!DOCTYPE coverage SYSTEM "http://cobertura.sourceforge.net/xml/coverage-04.dtd">
 <coverage lines-valid="8"  lines-covered="6"  line-rate="1"  branches-valid="4"  branches-covered="2"  branch-rate="1"  timestamp="1394890504210" complexity="0" version="0.1">
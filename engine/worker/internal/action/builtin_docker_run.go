@@ -0,0 +1,198 @@
+package action
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/afero"
+
+	"github.com/ovh/cds/engine/worker/pkg/workerruntime"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// dockerBinary returns the container runtime CLI used to run containers, any docker
+// compatible CLI can be set with CDS_WORKER_DOCKER_BINARY.
+func dockerBinary() string {
+	if b := os.Getenv("CDS_WORKER_DOCKER_BINARY"); b != "" {
+		return b
+	}
+	return "docker"
+}
+
+func RunDockerRun(ctx context.Context, wk workerruntime.Runtime, a sdk.Action, secrets []sdk.Variable) (sdk.Result, error) {
+	res := sdk.Result{Status: sdk.StatusSuccess}
+
+	image := strings.TrimSpace(sdk.ParameterValue(a.Parameters, "image"))
+	if image == "" {
+		return res, sdk.NewError(sdk.ErrWorkerErrorCommand, fmt.Errorf("image parameter is empty. aborting"))
+	}
+	workspace := strings.TrimSpace(sdk.ParameterValue(a.Parameters, "workspace"))
+	if workspace == "" {
+		workspace = "/workspace"
+	}
+
+	env, err := parseDockerRunEnv(sdk.ParameterValue(a.Parameters, "env"))
+	if err != nil {
+		return res, sdk.NewError(sdk.ErrWorkerErrorCommand, err)
+	}
+
+	workdir, err := workerruntime.WorkingDirectory(ctx)
+	if err != nil {
+		return res, err
+	}
+	var abs string
+	if x, ok := wk.BaseDir().(*afero.BasePathFs); ok {
+		abs, _ = x.RealPath(workdir.Name())
+	} else {
+		abs = workdir.Name()
+	}
+	abs, err = filepath.Abs(abs)
+	if err != nil {
+		return res, sdk.WithStack(err)
+	}
+
+	// CDS variables and step variables are given by name to the container, so their values
+	// (that can be secrets) are not visible in the command line
+	environ := append(wk.Environ(), env...)
+	var envNames []string
+	seen := make(map[string]struct{})
+	for i, e := range environ {
+		name := strings.SplitN(e, "=", 2)[0]
+		if _, ok := seen[name]; ok {
+			continue
+		}
+		if strings.HasPrefix(name, "CDS_") || i >= len(environ)-len(env) {
+			seen[name] = struct{}{}
+			envNames = append(envNames, name)
+		}
+	}
+
+	jobID, _ := workerruntime.JobID(ctx)
+	name := fmt.Sprintf("cds-job-%d-%s", jobID, sdk.RandomString(8))
+	args := dockerRunArgs(name, image, dockerRunUser(), strings.TrimSpace(sdk.ParameterValue(a.Parameters, "entrypoint")), abs, workspace, envNames,
+		splitLines(sdk.ParameterValue(a.Parameters, "command")))
+
+	// The container is never removed automatically, so its removal is done even if the job is canceled
+	defer removeContainer(ctx, wk, name)
+
+	wk.SendLog(ctx, workerruntime.LevelInfo, fmt.Sprintf("Running container %s from image %s", name, image))
+	log.Info(ctx, "RunDockerRun> Running command %s %s", dockerBinary(), strings.Join(args, " "))
+
+	cmd := exec.CommandContext(ctx, dockerBinary(), args...)
+	cmd.Dir = abs
+	cmd.Env = environ
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return res, sdk.WithStack(err)
+	}
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return res, sdk.WithStack(err)
+	}
+
+	if err := cmd.Start(); err != nil {
+		return res, sdk.NewError(sdk.ErrWorkerErrorCommand, fmt.Errorf("unable to start %s: %v", dockerBinary(), err))
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		sendReaderLogs(ctx, wk, workerruntime.LevelInfo, stdout)
+	}()
+	go func() {
+		defer wg.Done()
+		sendReaderLogs(ctx, wk, workerruntime.LevelWarn, stderr)
+	}()
+	wg.Wait()
+
+	if err := cmd.Wait(); err != nil {
+		if ctx.Err() != nil {
+			return res, fmt.Errorf("CDS Worker execution canceled")
+		}
+		exitErr, ok := err.(*exec.ExitError)
+		if !ok {
+			return res, sdk.NewError(sdk.ErrWorkerErrorCommand, fmt.Errorf("container failure: %v", err))
+		}
+		switch code := exitErr.ExitCode(); code {
+		case 125:
+			return res, sdk.NewError(sdk.ErrWorkerErrorCommand, fmt.Errorf("unable to run container from image %s", image))
+		case 126, 127:
+			return res, sdk.NewError(sdk.ErrWorkerErrorCommand, fmt.Errorf("unable to execute container command (exit code %d)", code))
+		default:
+			return res, sdk.NewError(sdk.ErrWorkerErrorCommand, fmt.Errorf("container exited with code %d", code))
+		}
+	}
+
+	return res, nil
+}
+
+// dockerRunUser returns the uid:gid of the worker, so files written by the container in the
+// workspace are owned by the worker. It is empty if ids are not available (ex: on windows).
+func dockerRunUser() string {
+	uid, gid := os.Getuid(), os.Getgid()
+	if uid < 0 || gid < 0 {
+		return ""
+	}
+	return fmt.Sprintf("%d:%d", uid, gid)
+}
+
+// dockerRunArgs returns the arguments of the docker run command.
+func dockerRunArgs(name, image, user, entrypoint, workdir, workspace string, envNames, command []string) []string {
+	args := []string{"run", "--name", name, "-v", workdir + ":" + workspace, "-w", workspace}
+	if user != "" {
+		args = append(args, "-u", user)
+	}
+	for _, e := range envNames {
+		args = append(args, "-e", e)
+	}
+	if entrypoint != "" {
+		args = append(args, "--entrypoint", entrypoint)
+	}
+	args = append(args, image)
+	return append(args, command...)
+}
+
+// parseDockerRunEnv returns the environment variables of the env parameter, one by line.
+func parseDockerRunEnv(s string) ([]string, error) {
+	env := splitLines(s)
+	for _, e := range env {
+		if !strings.Contains(e, "=") || strings.HasPrefix(e, "=") {
+			return nil, fmt.Errorf("invalid environment variable %q, expected KEY=VALUE", e)
+		}
+	}
+	return env, nil
+}
+
+func sendReaderLogs(ctx context.Context, wk workerruntime.Runtime, level workerruntime.Level, r io.Reader) {
+	reader := bufio.NewReader(r)
+	for {
+		line, err := reader.ReadString('\n')
+		if line != "" {
+			wk.SendLog(ctx, level, line)
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+func removeContainer(ctx context.Context, wk workerruntime.Runtime, name string) {
+	// Use a new context as the job context can be canceled
+	rmCtx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	if out, err := exec.CommandContext(rmCtx, dockerBinary(), "rm", "-f", name).CombinedOutput(); err != nil {
+		log.Warning(ctx, "RunDockerRun> unable to remove container %s: %v: %s", name, err, string(out))
+		wk.SendLog(ctx, workerruntime.LevelWarn, fmt.Sprintf("Unable to remove container %s", name))
+	}
+}
@@ -0,0 +1,93 @@
+package action
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func Test_dockerRunArgs(t *testing.T) {
+	args := dockerRunArgs("cds-job-1-abc", "golang:1.14", "1000:1000", "", "/tmp/job/run", "/workspace", []string{"CDS_JOB", "GOFLAGS"}, []string{"go", "test", "./..."})
+	require.Equal(t, []string{
+		"run", "--name", "cds-job-1-abc", "-v", "/tmp/job/run:/workspace", "-w", "/workspace", "-u", "1000:1000",
+		"-e", "CDS_JOB", "-e", "GOFLAGS",
+		"golang:1.14", "go", "test", "./...",
+	}, args)
+
+	args = dockerRunArgs("cds-job-1-abc", "alpine", "", "/bin/sh", "/tmp/job/run", "/src", nil, nil)
+	require.Equal(t, []string{"run", "--name", "cds-job-1-abc", "-v", "/tmp/job/run:/src", "-w", "/src", "--entrypoint", "/bin/sh", "alpine"}, args)
+}
+
+func TestRunDockerRun(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.SkipNow()
+	}
+	wk, ctx := SetupTest(t)
+
+	// Fake container runtime that logs its arguments and fails the container
+	dir, err := ioutil.TempDir("", "docker")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir) // nolint
+	argsFile := filepath.Join(dir, "args")
+	binary := filepath.Join(dir, "docker")
+	require.NoError(t, ioutil.WriteFile(binary, []byte(`#!/bin/sh
+echo "$@" >> `+argsFile+`
+if [ "$1" = "run" ]; then
+  echo "hello from $MY_VAR"
+  echo "warning" >&2
+  exit 3
+fi
+`), 0755))
+	os.Setenv("CDS_WORKER_DOCKER_BINARY", binary) // nolint
+	defer os.Unsetenv("CDS_WORKER_DOCKER_BINARY") // nolint
+
+	_, err = RunDockerRun(ctx, wk, sdk.Action{
+		Parameters: []sdk.Parameter{
+			{Name: "image", Value: "alpine"},
+			{Name: "command", Value: "echo\nhello"},
+			{Name: "env", Value: "MY_VAR=container"},
+		},
+	}, nil)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "container exited with code 3")
+
+	require.Contains(t, wk.logBuffer.String(), "SendLog> [INFO] hello from container")
+	require.Contains(t, wk.logBuffer.String(), "SendLog> [WARN] warning")
+
+	btes, err := ioutil.ReadFile(argsFile)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(btes)), "\n")
+	require.Len(t, lines, 2)
+
+	workdir, err := wk.BaseDir().(*afero.BasePathFs).RealPath(wk.workingDirectory.Name())
+	require.NoError(t, err)
+	workdir, err = filepath.Abs(workdir)
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(lines[0], "run --name cds-job-666-"), lines[0])
+	require.Contains(t, lines[0], "-v "+workdir+":/workspace -w /workspace")
+	require.Contains(t, lines[0], fmt.Sprintf("-u %d:%d", os.Getuid(), os.Getgid()))
+	require.Contains(t, lines[0], "-e MY_VAR")
+	require.True(t, strings.HasSuffix(lines[0], "alpine echo hello"), lines[0])
+
+	// The container is removed
+	name := strings.Split(lines[0], " ")[2]
+	require.Equal(t, "rm -f "+name, lines[1])
+}
+
+func Test_parseDockerRunEnv(t *testing.T) {
+	env, err := parseDockerRunEnv("A=1\n\nB=foo=bar\n")
+	require.NoError(t, err)
+	require.Equal(t, []string{"A=1", "B=foo=bar"}, env)
+
+	_, err = parseDockerRunEnv("A")
+	require.Error(t, err)
+}
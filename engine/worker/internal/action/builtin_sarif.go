@@ -0,0 +1,123 @@
+package action
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/afero"
+
+	"github.com/ovh/cds/engine/worker/pkg/workerruntime"
+	"github.com/ovh/cds/sdk"
+)
+
+func RunSARIF(ctx context.Context, wk workerruntime.Runtime, a sdk.Action, secrets []sdk.Variable) (sdk.Result, error) {
+	res := sdk.Result{Status: sdk.StatusFail}
+
+	p := strings.TrimSpace(sdk.ParameterValue(a.Parameters, "path"))
+	if p == "" {
+		return res, fmt.Errorf("sarif: path not provided")
+	}
+	failOnSeverity := strings.TrimSpace(sdk.ParameterValue(a.Parameters, "failOnSeverity"))
+	if failOnSeverity != "" && sdk.SeverityLevel(failOnSeverity) == 0 {
+		return res, fmt.Errorf("sarif: unknown severity %q", failOnSeverity)
+	}
+	newOnly := sdk.ParameterValue(a.Parameters, "newOnly") == "true"
+
+	workdir, err := workerruntime.WorkingDirectory(ctx)
+	if err != nil {
+		return res, err
+	}
+	var abs string
+	if x, ok := wk.BaseDir().(*afero.BasePathFs); ok {
+		abs, _ = x.RealPath(workdir.Name())
+	} else {
+		abs = workdir.Name()
+	}
+	if !sdk.PathIsAbs(p) {
+		p = filepath.Join(abs, p)
+	}
+
+	files, err := afero.Glob(afero.NewOsFs(), p)
+	if err != nil {
+		return res, fmt.Errorf("sarif: unable to find files %s: %v", p, err)
+	}
+	if len(files) == 0 {
+		return res, fmt.Errorf("sarif: no file found for %s", p)
+	}
+
+	jobID, err := workerruntime.JobID(ctx)
+	if err != nil {
+		return res, err
+	}
+
+	var failures sdk.AnalysisFindings
+	for _, f := range files {
+		data, err := ioutil.ReadFile(f)
+		if err != nil {
+			return res, fmt.Errorf("sarif: cannot read file %s: %v", f, err)
+		}
+		reports, err := sdk.ParseSARIF(data)
+		if err != nil {
+			return res, fmt.Errorf("sarif: cannot parse file %s: %v", f, err)
+		}
+		for _, r := range reports {
+			analysis, err := wk.Client().QueueSendAnalysis(ctx, jobID, r)
+			if err != nil {
+				return res, fmt.Errorf("sarif: failed to send %s findings: %v", r.Tool, err)
+			}
+			logAnalysis(ctx, wk, *analysis)
+			failures = append(failures, analysis.FindingsAboveSeverity(failOnSeverity, newOnly)...)
+		}
+	}
+
+	if len(failures) > 0 {
+		kind := "findings"
+		if newOnly {
+			kind = "new findings"
+		}
+		return res, fmt.Errorf("sarif: %d %s with severity %s or higher", len(failures), kind, failOnSeverity)
+	}
+
+	res.Status = sdk.StatusSuccess
+	return res, nil
+}
+
+func logAnalysis(ctx context.Context, wk workerruntime.Runtime, a sdk.WorkflowNodeRunAnalysis) {
+	summary := a.Summary()
+	severities := make([]string, 0, len(summary))
+	for s := range summary {
+		severities = append(severities, s)
+	}
+	sort.Slice(severities, func(i, j int) bool { return sdk.SeverityLevel(severities[i]) > sdk.SeverityLevel(severities[j]) })
+	counts := make([]string, len(severities))
+	for i, s := range severities {
+		counts[i] = fmt.Sprintf("%d %s", summary[s], s)
+	}
+
+	var nbNew int
+	for _, f := range a.Findings {
+		if f.Status == sdk.AnalysisFindingStatusNew {
+			nbNew++
+		}
+	}
+
+	msg := fmt.Sprintf("%s: %d findings", a.Tool, len(a.Findings))
+	if len(counts) > 0 {
+		msg += " (" + strings.Join(counts, ", ") + ")"
+	}
+	msg += fmt.Sprintf(", %d new, %d fixed compared to default branch", nbNew, len(a.Fixed))
+	wk.SendLog(ctx, workerruntime.LevelInfo, msg)
+
+	for _, f := range a.Findings {
+		if f.Status == sdk.AnalysisFindingStatusNew {
+			wk.SendLog(ctx, workerruntime.LevelInfo, fmt.Sprintf("New %s finding %s at %s:%d: %s", f.Severity, f.RuleID, f.Path, f.Line, f.Message))
+		}
+	}
+	for _, f := range a.Fixed {
+		wk.SendLog(ctx, workerruntime.LevelInfo, fmt.Sprintf("Fixed %s finding %s at %s:%d", f.Severity, f.RuleID, f.Path, f.Line))
+	}
+}
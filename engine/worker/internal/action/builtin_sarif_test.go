@@ -0,0 +1,66 @@
+package action
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/h2non/gock.v1"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/cdsclient"
+)
+
+const sarifResult = `{
+  "version": "2.1.0",
+  "runs": [{
+    "tool": {"driver": {"name": "gosec"}},
+    "results": [
+      {"ruleId": "G101", "level": "error", "message": {"text": "Potential hardcoded credentials"},
+       "locations": [{"physicalLocation": {"artifactLocation": {"uri": "main.go"}, "region": {"startLine": 12}}}]}
+    ]
+  }]
+}`
+
+func TestRunSARIF(t *testing.T) {
+	defer gock.Off()
+	wk, ctx := SetupTest(t)
+
+	workdir, err := wk.BaseDir().(*afero.BasePathFs).RealPath(wk.workingDirectory.Name())
+	require.NoError(t, err)
+	require.NoError(t, ioutil.WriteFile(filepath.Join(workdir, "gosec.sarif"), []byte(sarifResult), os.ModePerm))
+
+	// The finding already exists on the default branch
+	gock.New("http://lolcat.host").Post("/queue/workflows/666/analysis").Times(2).
+		Reply(200).JSON(sdk.WorkflowNodeRunAnalysis{
+		Tool: "gosec",
+		Findings: sdk.AnalysisFindings{
+			{RuleID: "G101", Severity: sdk.SeverityHigh, Status: sdk.AnalysisFindingStatusExisting},
+		},
+	})
+	gock.InterceptClient(wk.Client().(cdsclient.Raw).HTTPClient())
+
+	res, err := RunSARIF(ctx, wk, sdk.Action{
+		Parameters: []sdk.Parameter{
+			{Name: "path", Value: "*.sarif"},
+			{Name: "failOnSeverity", Value: sdk.SeverityHigh},
+			{Name: "newOnly", Value: "true"},
+		},
+	}, nil)
+	require.NoError(t, err)
+	require.Equal(t, sdk.StatusSuccess, res.Status)
+
+	res, err = RunSARIF(ctx, wk, sdk.Action{
+		Parameters: []sdk.Parameter{
+			{Name: "path", Value: "*.sarif"},
+			{Name: "failOnSeverity", Value: sdk.SeverityHigh},
+		},
+	}, nil)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "sarif: 1 findings with severity high or higher")
+	require.Equal(t, sdk.StatusFail, res.Status)
+	require.True(t, gock.IsDone())
+}
@@ -27,6 +27,7 @@ type TestWorker struct {
 	keyDirectory     *afero.BasePathFile
 	client           cdsclient.WorkerInterface
 	Params           []sdk.Parameter
+	PostJobActions   map[string]workerruntime.PostJobAction
 	logBuffer        bytes.Buffer
 }
 
@@ -59,6 +60,13 @@ func (_ *TestWorker) HTTPPort() int32 {
 	return 0
 }
 
+func (w *TestWorker) RegisterPostJobAction(name string, f workerruntime.PostJobAction) {
+	if w.PostJobActions == nil {
+		w.PostJobActions = make(map[string]workerruntime.PostJobAction)
+	}
+	w.PostJobActions[name] = f
+}
+
 func (_ *TestWorker) Name() string {
 	return "test"
 }
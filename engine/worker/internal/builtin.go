@@ -25,6 +25,10 @@ func init() {
 	mapBuiltinActions[sdk.CoverageAction] = action.RunParseCoverageResultAction
 	mapBuiltinActions[sdk.ServeStaticFiles] = action.RunServeStaticFiles
 	mapBuiltinActions[sdk.InstallKeyAction] = action.RunInstallKey
+	mapBuiltinActions[sdk.CacheAction] = action.RunCache
+	mapBuiltinActions[sdk.SARIFAction] = action.RunSARIF
+	mapBuiltinActions[sdk.DockerRunAction] = action.RunDockerRun
+	mapBuiltinActions[sdk.ChildPipelinesAction] = action.RunChildPipelines
 }
 
 func (w *CurrentWorker) runBuiltin(ctx context.Context, a sdk.Action, secrets []sdk.Variable) sdk.Result {
@@ -0,0 +1,47 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/ovh/cds/engine/worker/pkg/workerruntime"
+	"github.com/ovh/cds/sdk"
+)
+
+func analysisHandler(ctx context.Context, wk *CurrentWorker) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Body == nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		defer r.Body.Close()
+
+		data, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		var report sdk.AnalysisWorkerReport
+		if err := json.Unmarshal(data, &report); err != nil {
+			writeError(w, r, sdk.NewErrorWithStack(err, sdk.ErrWrongRequest))
+			return
+		}
+
+		jobID, err := workerruntime.JobID(wk.currentJob.context)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		analysis, err := wk.Client().QueueSendAnalysis(wk.currentJob.context, jobID, report)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		writeJSON(w, analysis, http.StatusOK)
+	}
+}
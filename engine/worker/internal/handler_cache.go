@@ -1,15 +1,11 @@
 package internal
 
 import (
-	"archive/tar"
 	"context"
 	"encoding/json"
 	"fmt"
-	"io"
 	"io/ioutil"
 	"net/http"
-	"os"
-	"path/filepath"
 	"time"
 
 	"github.com/gorilla/mux"
@@ -106,7 +102,7 @@ func cachePushHandler(ctx context.Context, wk *CurrentWorker) http.HandlerFunc {
 			if _, err := tarF.Seek(0, 0); err != nil {
 				errPush = err
 			} else {
-				if errPush = wk.client.WorkflowCachePush(projectKey, sdk.DefaultIfEmptyStorage(c.IntegrationName), c.Tag, tarF, int(tarInfo.Size())); errPush == nil {
+				if errPush = wk.client.WorkflowCachePush(projectKey, sdk.DefaultIfEmptyStorage(c.IntegrationName), c.Tag, c.Key, tarF, int(tarInfo.Size())); errPush == nil {
 					return
 				}
 			}
@@ -130,7 +126,27 @@ func cachePullHandler(ctx context.Context, wk *CurrentWorker) http.HandlerFunc {
 		integrationName := sdk.DefaultIfEmptyStorage(req.FormValue("integration"))
 		params := wk.currentJob.wJob.Parameters
 		projectKey := sdk.ParameterValue(params, "cds.project")
-		r, err := wk.client.WorkflowCachePull(projectKey, integrationName, vars["ref"])
+
+		// If restore keys are given, pull the cache for the key or the most recent cache matching a restore key
+		ref := vars["ref"]
+		if restoreKeys := req.Form["restoreKey"]; len(restoreKeys) > 0 {
+			key := req.FormValue("key")
+			if key == "" {
+				key = ref
+			}
+			c, err := wk.client.WorkflowCacheLookup(projectKey, integrationName, key, restoreKeys)
+			if err != nil {
+				err = sdk.Error{
+					Message: "worker cache pull > Cannot find cache: " + err.Error(),
+					Status:  http.StatusNotFound,
+				}
+				writeError(w, req, err)
+				return
+			}
+			ref = c.Tag
+		}
+
+		r, err := wk.client.WorkflowCachePull(projectKey, integrationName, ref)
 		if err != nil {
 			err = sdk.Error{
 				Message: "worker cache pull > Cannot pull cache: " + err.Error(),
@@ -142,94 +158,13 @@ func cachePullHandler(ctx context.Context, wk *CurrentWorker) http.HandlerFunc {
 
 		log.Debug("cachePullHandler> Start read cache tar")
 
-		tr := tar.NewReader(r)
-		for {
-			header, errH := tr.Next()
-			if errH == io.EOF {
-				break
-			}
-
-			if errH != nil {
-				errH = sdk.Error{
-					Message: "worker cache pull > Unable to read tar file: " + errH.Error(),
-					Status:  http.StatusBadRequest,
-				}
-				writeJSON(w, errH, http.StatusBadRequest)
-				return
-			}
-
-			if header == nil {
-				continue
-			}
-
-			log.Debug("cachePullHandler> Tar contains file %s", header.Name)
-
-			// the target location where the dir/file should be created
-			target := filepath.Join(path, header.Name)
-
-			// check the file type
-			switch header.Typeflag {
-			// if its a dir and it doesn't exist create it
-			case tar.TypeDir:
-				if _, err := os.Stat(target); err != nil {
-					if err := os.MkdirAll(target, 0755); err != nil {
-						err = sdk.Error{
-							Message: "worker cache pull > Unable to mkdir all files : " + err.Error(),
-							Status:  http.StatusInternalServerError,
-						}
-						writeJSON(w, err, http.StatusInternalServerError)
-						return
-					}
-				}
-			case tar.TypeSymlink:
-				if err := os.Symlink(header.Linkname, target); err != nil {
-					err = sdk.Error{
-						Message: "worker cache pull > Unable to create symlink: " + err.Error(),
-						Status:  http.StatusInternalServerError,
-					}
-					writeJSON(w, err, http.StatusInternalServerError)
-					return
-				}
-
-				// if it's a file create it
-			case tar.TypeReg, tar.TypeLink:
-				// if directory of file does not exist, create it before
-				if _, err := os.Stat(filepath.Dir(target)); err != nil {
-					if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
-						err = sdk.Error{
-							Message: "worker cache pull > Unable to mkdir all files : " + err.Error(),
-							Status:  http.StatusInternalServerError,
-						}
-						writeJSON(w, err, http.StatusInternalServerError)
-						return
-					}
-				}
-
-				log.Debug("cachePullHandler> Create file at %s", target)
-
-				f, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY, os.FileMode(header.Mode))
-				if err != nil {
-					sdkErr := sdk.Error{
-						Message: "worker cache pull > Unable to open file: " + err.Error(),
-						Status:  http.StatusInternalServerError,
-					}
-					writeJSON(w, sdkErr, sdkErr.Status)
-					return
-				}
-
-				// copy over contents
-				if _, err := io.Copy(f, tr); err != nil {
-					_ = f.Close()
-					sdkErr := sdk.Error{
-						Message: "worker cache pull > Cannot copy content file: " + err.Error(),
-						Status:  http.StatusInternalServerError,
-					}
-					writeJSON(w, sdkErr, sdkErr.Status)
-					return
-				}
-
-				_ = f.Close()
+		if err := sdk.ExtractTar(r, path); err != nil {
+			err = sdk.Error{
+				Message: "worker cache pull > Unable to extract cache: " + err.Error(),
+				Status:  http.StatusInternalServerError,
 			}
+			writeError(w, req, err)
+			return
 		}
 	}
 }
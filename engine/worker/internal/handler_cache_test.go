@@ -93,8 +93,8 @@ func Test_cachePushPullHandler(t *testing.T) {
 
 	var generatedTar bytes.Buffer
 	var retryPush int
-	m.EXPECT().WorkflowCachePush("myProject", "shared.infra", "myTag", gomock.Any(), gomock.Any()).DoAndReturn(
-		func(projectKey, integrationName, ref string, tarContent io.Reader, size int) error {
+	m.EXPECT().WorkflowCachePush("myProject", "shared.infra", "myTag", "myKey", gomock.Any(), gomock.Any()).DoAndReturn(
+		func(projectKey, integrationName, ref, key string, tarContent io.Reader, size int) error {
 			retryPush++
 			if retryPush == 1 {
 				partialRead := make([]byte, 10)
@@ -117,6 +117,7 @@ func Test_cachePushPullHandler(t *testing.T) {
 	// Send cash push request for two files, one relative to workspace and another absolute to test basedir.
 	buf, err := json.Marshal(sdk.Cache{
 		Tag:              "myTag",
+		Key:              "myKey",
 		WorkingDirectory: wdPushAbs,
 		Files: []string{
 			"relative.txt",
@@ -175,3 +176,50 @@ func Test_cachePushPullHandler(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, "absolute", string(btsAbsolute))
 }
+
+func Test_cachePullHandlerWithRestoreKey(t *testing.T) {
+	fs := afero.NewOsFs()
+	basedir := "test-" + test.GetTestName(t) + "-" + sdk.RandomString(10) + "-" + fmt.Sprintf("%d", time.Now().Unix())
+	require.NoError(t, fs.MkdirAll(basedir, os.FileMode(0755)))
+
+	wk := &CurrentWorker{
+		basedir: afero.NewBasePathFs(fs, basedir),
+	}
+	wk.currentJob.wJob = &sdk.WorkflowNodeJobRun{
+		Parameters: []sdk.Parameter{{
+			Name:  "cds.project",
+			Value: "myProject",
+		}},
+	}
+
+	src, err := filepath.Abs(afero.FullBaseFsPath(wk.basedir.(*afero.BasePathFs), "/src"))
+	require.NoError(t, err)
+	require.NoError(t, os.MkdirAll(src, os.FileMode(0755)))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(src, "old.txt"), []byte("old"), os.FileMode(0755)))
+	var tarContent bytes.Buffer
+	require.NoError(t, sdk.CreateTarFromPaths(fs, src, []string{"old.txt"}, &tarContent, nil))
+
+	ctrl := gomock.NewController(t)
+	m := mock_cdsclient.NewMockWorkerInterface(ctrl)
+	wk.client = m
+
+	m.EXPECT().WorkflowCacheLookup("myProject", "shared.infra", "npm-123", []string{"npm-"}).
+		Return(&sdk.Cache{Tag: "bnBtLTAwMA", Key: "npm-000"}, nil)
+	m.EXPECT().WorkflowCachePull("myProject", "shared.infra", "bnBtLTAwMA").
+		Return(&tarContent, nil)
+
+	pullPath, err := filepath.Abs(afero.FullBaseFsPath(wk.basedir.(*afero.BasePathFs), "/pull"))
+	require.NoError(t, err)
+	reqPull, err := http.NewRequest(http.MethodGet, "/cache/bnBtLTEyMw/pull?key=npm-123&restoreKey=npm-&path="+pullPath, nil)
+	require.NoError(t, err)
+
+	router := mux.NewRouter()
+	router.HandleFunc("/cache/{ref}/pull", cachePullHandler(context.Background(), wk))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, reqPull)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	btsOld, err := ioutil.ReadFile(filepath.Join(pullPath, "old.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "old", string(btsOld))
+}
@@ -46,6 +46,7 @@ func (w *CurrentWorker) Serve(c context.Context) error {
 	log.Info(c, "Export variable HTTP server: %s", listener.Addr().String())
 	r := mux.NewRouter()
 
+	r.HandleFunc("/analysis", LogMiddleware(analysisHandler(c, w)))
 	r.HandleFunc("/artifacts", LogMiddleware(artifactsHandler(c, w)))
 	r.HandleFunc("/cache/{ref}/pull", LogMiddleware(cachePullHandler(c, w)))
 	r.HandleFunc("/cache/push", LogMiddleware(cachePushHandler(c, w)))
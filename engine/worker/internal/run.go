@@ -17,6 +17,7 @@ import (
 	"github.com/ovh/cds/sdk"
 	"github.com/ovh/cds/sdk/interpolate"
 	"github.com/ovh/cds/sdk/log"
+	"github.com/ovh/cds/sdk/telemetry"
 )
 
 func processVariablesAndParameters(action *sdk.Action, jobParameters []sdk.Parameter, jobSecrets []sdk.Variable) error {
@@ -129,21 +130,30 @@ func (w *CurrentWorker) runJob(ctx context.Context, a *sdk.Action, jobID int64,
 		log.Info(ctx, "runJob> job %s (%d)", a.Name, jobID)
 	}()
 
+	w.currentJob.postJobActions = nil
+
 	var nDisabled, nCriticalFailed int
 	for jobStepIndex, step := range a.Actions {
 		// Reset step log line to 0
 		w.stepLogLine = 0
 
 		ctx = workerruntime.SetStepOrder(ctx, jobStepIndex)
-		if step.StepName != "" {
-			ctx = workerruntime.SetStepName(ctx, step.StepName)
-		} else {
-			ctx = workerruntime.SetStepName(ctx, step.Name)
+		stepName := step.StepName
+		if stepName == "" {
+			stepName = step.Name
 		}
+		ctx = workerruntime.SetStepName(ctx, stepName)
+
+		// Each step is traced as a child of the job span
+		stepCtx, endStep := telemetry.Span(ctx, "worker.step",
+			telemetry.Tag("step_order", jobStepIndex),
+			telemetry.Tag("step_name", stepName),
+		)
 
-		if err := w.updateStepStatus(ctx, jobID, jobStepIndex, sdk.StatusBuilding); err != nil {
+		if err := w.updateStepStatus(stepCtx, jobID, jobStepIndex, sdk.StatusBuilding); err != nil {
 			jobResult.Status = sdk.StatusFail
 			jobResult.Reason = fmt.Sprintf("Cannot update step (%d) status (%s): %v", jobStepIndex, sdk.StatusBuilding, err)
+			endStep()
 			return jobResult
 		}
 		var stepResult = sdk.Result{
@@ -151,7 +161,7 @@ func (w *CurrentWorker) runJob(ctx context.Context, a *sdk.Action, jobID int64,
 			BuildID: jobID,
 		}
 		if nCriticalFailed == 0 || step.AlwaysExecuted {
-			stepResult = w.runAction(ctx, step, jobID, secrets, step.Name)
+			stepResult = w.runAction(stepCtx, step, jobID, secrets, step.Name)
 
 			// Check if all newVariables are in currentJob.params
 			// variable can be add in w.currentJob.newVariables by worker command export
@@ -180,11 +190,24 @@ func (w *CurrentWorker) runJob(ctx context.Context, a *sdk.Action, jobID int64,
 				}
 			}
 		}
-		if err := w.updateStepStatus(ctx, jobID, jobStepIndex, stepResult.Status); err != nil {
+		if err := w.updateStepStatus(stepCtx, jobID, jobStepIndex, stepResult.Status); err != nil {
 			jobResult.Status = sdk.StatusFail
 			jobResult.Reason = fmt.Sprintf("Cannot update step (%d) status (%s): %v", jobStepIndex, sdk.StatusBuilding, err)
+			endStep()
 			return jobResult
 		}
+		endStep()
+	}
+
+	// Post job actions are registered by steps (ex: cache save) and only executed for a successful job,
+	// an error is logged but doesn't change the job status
+	if nCriticalFailed == 0 {
+		for _, p := range w.currentJob.postJobActions {
+			w.SendLog(ctx, workerruntime.LevelInfo, fmt.Sprintf("Starting post job action \"%s\"", p.name))
+			if err := p.f(ctx); err != nil {
+				w.SendLog(ctx, workerruntime.LevelWarn, fmt.Sprintf("Post job action \"%s\" failed: %v", p.name, err))
+			}
+		}
 	}
 
 	// Propagate new variables from steps to jobs result
@@ -6,14 +6,32 @@ import (
 	"strings"
 	"time"
 
+	"go.opencensus.io/trace"
+
 	"github.com/ovh/cds/engine/worker/pkg/workerruntime"
 	"github.com/ovh/cds/sdk"
 	"github.com/ovh/cds/sdk/jws"
 	"github.com/ovh/cds/sdk/log"
 	"github.com/ovh/cds/sdk/log/hook"
+	"github.com/ovh/cds/sdk/telemetry"
 )
 
 func (w *CurrentWorker) Take(ctx context.Context, job sdk.WorkflowNodeJobRun) error {
+	// The job span continues the trace of the workflow run, all the calls to the API are propagated from it
+	ctx, span := telemetry.NewWithRemoteParent(ctx, w, "worker.Take", job.Header, trace.SpanKindServer)
+	if span != nil {
+		pkey, _ := job.Header.Get(sdk.ProjectKeyHeader)
+		wf, _ := job.Header.Get(sdk.WorkflowHeader)
+		run, _ := job.Header.Get(sdk.WorkflowRunHeader)
+		span.AddAttributes(
+			telemetry.Tag(telemetry.TagProjectKey, pkey),
+			telemetry.Tag(telemetry.TagWorkflow, wf),
+			telemetry.Tag(telemetry.TagWorkflowRun, run),
+			telemetry.Tag(telemetry.TagWorkflowNodeJobRun, job.ID),
+		)
+		defer span.End()
+	}
+
 	ctxQueueTakeJob, cancelQueueTakeJob := context.WithTimeout(ctx, 20*time.Second)
 	defer cancelQueueTakeJob()
 	info, err := w.client.QueueTakeJob(ctxQueueTakeJob, job)
@@ -120,8 +138,10 @@ func (w *CurrentWorker) Take(ctx context.Context, job sdk.WorkflowNodeJobRun) er
 	}(cancel, job.ID, tick)
 
 	//Run !
+	cpuTimeAtStart, _, _ := processUsage()
 	res := w.ProcessJob(*info)
 	tick.Stop()
+	res.Usage = jobRunUsage(cpuTimeAtStart)
 
 	res.RemoteTime = time.Now()
 	res.Duration = sdk.Round(time.Since(start), time.Second).String()
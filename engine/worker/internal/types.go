@@ -47,17 +47,18 @@ type CurrentWorker struct {
 		model       string
 	}
 	currentJob struct {
-		wJob         *sdk.WorkflowNodeJobRun
-		newVariables []sdk.Variable
-		params       []sdk.Parameter
-		secrets      []sdk.Variable
-		context      context.Context
-		signer       jose.Signer
-		projectKey   string
-		workflowName string
-		workflowID   int64
-		runID        int64
-		nodeRunName  string
+		wJob           *sdk.WorkflowNodeJobRun
+		newVariables   []sdk.Variable
+		params         []sdk.Parameter
+		secrets        []sdk.Variable
+		context        context.Context
+		signer         jose.Signer
+		projectKey     string
+		workflowName   string
+		workflowID     int64
+		runID          int64
+		nodeRunName    string
+		postJobActions []postJobAction
 	}
 	status struct {
 		Name   string `json:"name"`
@@ -163,6 +164,10 @@ func (wk *CurrentWorker) Name() string {
 	return wk.status.Name
 }
 
+func (wk *CurrentWorker) Type() string {
+	return "worker"
+}
+
 func (wk *CurrentWorker) Client() cdsclient.WorkerInterface {
 	return wk.client
 }
@@ -241,3 +246,13 @@ func (w *CurrentWorker) Blur(i interface{}) error {
 func (w *CurrentWorker) HTTPPort() int32 {
 	return w.httpPort
 }
+
+type postJobAction struct {
+	name string
+	f    workerruntime.PostJobAction
+}
+
+// RegisterPostJobAction adds an action to execute at the end of the current job if all steps succeeded.
+func (w *CurrentWorker) RegisterPostJobAction(name string, f workerruntime.PostJobAction) {
+	w.currentJob.postJobActions = append(w.currentJob.postJobActions, postJobAction{name: name, f: f})
+}
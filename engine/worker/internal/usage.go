@@ -0,0 +1,20 @@
+package internal
+
+import (
+	"time"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// jobRunUsage returns the resource usage of the worker and its child processes since given CPU time, nil if it is
+// not available on the current platform.
+func jobRunUsage(cpuTimeAtStart time.Duration) *sdk.JobRunUsage {
+	cpuTime, memoryPeak, ok := processUsage()
+	if !ok {
+		return nil
+	}
+	return &sdk.JobRunUsage{
+		CPUTime:    (cpuTime - cpuTimeAtStart).Seconds(),
+		MemoryPeak: memoryPeak,
+	}
+}
@@ -0,0 +1,34 @@
+// +build !windows
+
+package internal
+
+import (
+	"runtime"
+	"syscall"
+	"time"
+)
+
+// processUsage returns the CPU time spent by the worker and its terminated child processes, and the maximum
+// resident set size in bytes of the worker or of one of its child processes.
+func processUsage() (time.Duration, int64, bool) {
+	var self, children syscall.Rusage
+	if err := syscall.Getrusage(syscall.RUSAGE_SELF, &self); err != nil {
+		return 0, 0, false
+	}
+	if err := syscall.Getrusage(syscall.RUSAGE_CHILDREN, &children); err != nil {
+		return 0, 0, false
+	}
+
+	cpuTime := time.Duration(self.Utime.Nano() + self.Stime.Nano() + children.Utime.Nano() + children.Stime.Nano())
+
+	memoryPeak := int64(self.Maxrss)
+	if int64(children.Maxrss) > memoryPeak {
+		memoryPeak = int64(children.Maxrss)
+	}
+	// Maximum resident set size is given in bytes on darwin and in kilobytes elsewhere
+	if runtime.GOOS != "darwin" {
+		memoryPeak *= 1024
+	}
+
+	return cpuTime, memoryPeak, true
+}
@@ -0,0 +1,10 @@
+// +build windows
+
+package internal
+
+import "time"
+
+// processUsage is not implemented on windows.
+func processUsage() (time.Duration, int64, bool) {
+	return 0, 0, false
+}
@@ -0,0 +1,116 @@
+package localexec
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/ovh/venom"
+	coverage "github.com/sguiheux/go-coverage"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/cdsclient"
+)
+
+// localClient replaces the CDS API for builtin actions: artifacts are stored in a local
+// directory, one sub directory by tag, and tests and coverage reports are kept in the
+// current job result. Other methods are not available for a local execution.
+type localClient struct {
+	cdsclient.WorkerInterface
+	mutex        sync.Mutex
+	artifactsDir string
+	result       *JobResult
+}
+
+func (c *localClient) QueueArtifactUpload(ctx context.Context, projectKey, integrationName string, nodeJobRunID int64, tag, filePath string) (bool, time.Duration, error) {
+	t0 := time.Now()
+	src, err := os.Open(filePath)
+	if err != nil {
+		return false, 0, sdk.WithStack(err)
+	}
+	defer src.Close() // nolint
+	fi, err := src.Stat()
+	if err != nil {
+		return false, 0, sdk.WithStack(err)
+	}
+
+	dir := filepath.Join(c.artifactsDir, tag)
+	if err := os.MkdirAll(dir, os.FileMode(0755)); err != nil {
+		return false, 0, sdk.WithStack(err)
+	}
+	dst, err := os.OpenFile(filepath.Join(dir, filepath.Base(filePath)), os.O_RDWR|os.O_CREATE|os.O_TRUNC, fi.Mode())
+	if err != nil {
+		return false, 0, sdk.WithStack(err)
+	}
+	defer dst.Close() // nolint
+	if _, err := io.Copy(dst, src); err != nil {
+		return false, 0, sdk.WithStack(err)
+	}
+	return false, time.Since(t0), nil
+}
+
+func (c *localClient) WorkflowRunArtifacts(projectKey string, name string, number int64) ([]sdk.WorkflowNodeRunArtifact, error) {
+	tags, err := ioutil.ReadDir(c.artifactsDir)
+	if err != nil {
+		return nil, sdk.WithStack(err)
+	}
+	var arts []sdk.WorkflowNodeRunArtifact
+	for _, tag := range tags {
+		if !tag.IsDir() {
+			continue
+		}
+		files, err := ioutil.ReadDir(filepath.Join(c.artifactsDir, tag.Name()))
+		if err != nil {
+			return nil, sdk.WithStack(err)
+		}
+		for _, f := range files {
+			if f.IsDir() {
+				continue
+			}
+			arts = append(arts, sdk.WorkflowNodeRunArtifact{
+				Name:    f.Name(),
+				Tag:     tag.Name(),
+				Size:    f.Size(),
+				Perm:    uint32(f.Mode().Perm()),
+				Created: f.ModTime(),
+			})
+		}
+	}
+	return arts, nil
+}
+
+func (c *localClient) WorkflowNodeRunArtifactDownload(projectKey string, name string, a sdk.WorkflowNodeRunArtifact, w io.Writer) error {
+	f, err := os.Open(filepath.Join(c.artifactsDir, a.Tag, a.Name))
+	if err != nil {
+		return sdk.WithStack(err)
+	}
+	defer f.Close() // nolint
+	_, err = io.Copy(w, f)
+	return sdk.WithStack(err)
+}
+
+func (c *localClient) QueueSendUnitTests(ctx context.Context, id int64, report venom.Tests) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.result.Tests == nil {
+		c.result.Tests = &venom.Tests{}
+	}
+	c.result.Tests.Total += report.Total
+	c.result.Tests.TotalOK += report.TotalOK
+	c.result.Tests.TotalKO += report.TotalKO
+	c.result.Tests.TotalSkipped += report.TotalSkipped
+	c.result.Tests.TestSuites = append(c.result.Tests.TestSuites, report.TestSuites...)
+	return nil
+}
+
+func (c *localClient) QueueSendCoverage(ctx context.Context, id int64, report coverage.Report) (*sdk.WorkflowNodeRunCoverage, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.result.Coverage = &report
+	// There is no default branch to compare with for a local execution
+	return &sdk.WorkflowNodeRunCoverage{Report: report}, nil
+}
@@ -0,0 +1,375 @@
+// Package localexec runs a CDS pipeline on the local machine without any CDS API.
+// Builtin steps are executed with the worker implementations, artifacts are stored
+// in a local directory and tests and coverage reports are kept in jobs results.
+package localexec
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ovh/venom"
+	coverage "github.com/sguiheux/go-coverage"
+	"github.com/spf13/afero"
+
+	"github.com/ovh/cds/engine/worker/internal/action"
+	"github.com/ovh/cds/engine/worker/pkg/workerruntime"
+	"github.com/ovh/cds/sdk"
+	sdkaction "github.com/ovh/cds/sdk/action"
+	"github.com/ovh/cds/sdk/interpolate"
+)
+
+type builtinAction func(context.Context, workerruntime.Runtime, sdk.Action, []sdk.Variable) (sdk.Result, error)
+
+// Builtin actions that can be executed without a CDS API.
+var mapBuiltinActions = map[string]builtinAction{
+	sdk.ScriptAction:     action.RunScriptAction,
+	sdk.ArtifactUpload:   action.RunArtifactUpload,
+	sdk.ArtifactDownload: action.RunArtifactDownload,
+	sdk.JUnitAction:      action.RunParseJunitTestResultAction,
+	sdk.CoverageAction:   action.RunParseCoverageResultAction,
+	sdk.DockerRunAction:  action.RunDockerRun,
+}
+
+// Options for a local pipeline execution.
+type Options struct {
+	// Workspace is the directory where jobs working directories are created.
+	Workspace string
+	// ArtifactsDir is the directory where uploaded artifacts are stored.
+	ArtifactsDir string
+	// DockerImage, if set, is used to execute script steps in a docker container.
+	DockerImage string
+	// Parameters overrides pipeline parameters values.
+	Parameters map[string]string
+	// Output receives execution logs.
+	Output io.Writer
+}
+
+// StepResult is the result of a step execution.
+type StepResult struct {
+	Name     string        `json:"name" cli:"name"`
+	Status   string        `json:"status" cli:"status"`
+	Reason   string        `json:"reason,omitempty" cli:"reason"`
+	Duration time.Duration `json:"duration" cli:"duration"`
+}
+
+// JobResult is the result of a job execution.
+type JobResult struct {
+	Stage    string           `json:"stage" cli:"stage"`
+	Name     string           `json:"name" cli:"name"`
+	Status   string           `json:"status" cli:"status"`
+	Reason   string           `json:"reason,omitempty" cli:"reason"`
+	Duration time.Duration    `json:"duration" cli:"duration"`
+	Steps    []StepResult     `json:"steps"`
+	Tests    *venom.Tests     `json:"tests,omitempty"`
+	Coverage *coverage.Report `json:"coverage,omitempty"`
+}
+
+// FullName returns the job name prefixed by its stage name if any.
+func (r JobResult) FullName() string {
+	if r.Stage == "" {
+		return r.Name
+	}
+	return r.Stage + "/" + r.Name
+}
+
+// Run executes given pipeline stage by stage, jobs of a stage are executed one after the
+// other. Stages after a failed stage are not executed and their jobs are set to never built.
+func Run(ctx context.Context, pip sdk.Pipeline, opts Options) ([]JobResult, error) {
+	if opts.Output == nil {
+		opts.Output = os.Stdout
+	}
+	workspace, err := filepath.Abs(opts.Workspace)
+	if err != nil {
+		return nil, sdk.WithStack(err)
+	}
+	artifactsDir, err := filepath.Abs(opts.ArtifactsDir)
+	if err != nil {
+		return nil, sdk.WithStack(err)
+	}
+	if err := os.MkdirAll(artifactsDir, os.FileMode(0755)); err != nil {
+		return nil, sdk.WrapError(err, "cannot create artifacts directory %s", artifactsDir)
+	}
+
+	for name := range opts.Parameters {
+		if sdk.ParameterFind(pip.Parameter, name) == nil {
+			return nil, sdk.NewErrorFrom(sdk.ErrWrongRequest, "unknown pipeline parameter %s", name)
+		}
+	}
+
+	stages := make([]sdk.Stage, len(pip.Stages))
+	copy(stages, pip.Stages)
+	sort.SliceStable(stages, func(i, j int) bool { return stages[i].BuildOrder < stages[j].BuildOrder })
+
+	var results []JobResult
+	var failed bool
+	var jobID int64
+	for _, s := range stages {
+		// Results of the stage are the ones appended from this index
+		first := len(results)
+		for _, j := range s.Jobs {
+			jobID++
+			res := JobResult{
+				Stage:  s.Name,
+				Name:   j.Action.Name,
+				Status: sdk.StatusNeverBuilt,
+			}
+			switch {
+			case failed:
+			case !s.Enabled || !j.Enabled:
+				res.Status = sdk.StatusDisabled
+			default:
+				fmt.Fprintf(opts.Output, "Starting job %s\n", res.FullName())
+				e := executor{
+					opts:         opts,
+					workspace:    workspace,
+					artifactsDir: artifactsDir,
+					pipeline:     pip,
+					stage:        s,
+					job:          j,
+					jobID:        jobID,
+					result:       &res,
+				}
+				if err := e.run(ctx); err != nil {
+					res.Status = sdk.StatusFail
+					res.Reason = err.Error()
+				}
+				fmt.Fprintf(opts.Output, "End of job %s: %s (%s)\n", res.FullName(), res.Status, sdk.Round(res.Duration, time.Second))
+			}
+			results = append(results, res)
+		}
+		for _, r := range results[first:] {
+			if r.Status == sdk.StatusFail {
+				failed = true
+			}
+		}
+	}
+
+	return results, nil
+}
+
+type executor struct {
+	opts         Options
+	workspace    string
+	artifactsDir string
+	pipeline     sdk.Pipeline
+	stage        sdk.Stage
+	job          sdk.Job
+	jobID        int64
+	result       *JobResult
+}
+
+func (e *executor) run(ctx context.Context) error {
+	t0 := time.Now()
+	defer func() { e.result.Duration = time.Since(t0) }()
+
+	// Each job is executed in its own directory, files are shared between jobs with artifacts
+	basedir := filepath.Join(e.workspace, fmt.Sprintf("%d-%s", e.jobID, slug(e.job.Action.Name)))
+	if err := os.MkdirAll(basedir, os.FileMode(0755)); err != nil {
+		return sdk.WrapError(err, "cannot create job directory %s", basedir)
+	}
+	fs := afero.NewBasePathFs(afero.NewOsFs(), basedir)
+
+	var dirs = map[string]afero.File{}
+	for _, d := range []string{"run", "keys", "tmp"} {
+		if err := fs.MkdirAll(d, os.FileMode(0755)); err != nil {
+			return sdk.WrapError(err, "cannot create directory %s", d)
+		}
+		f, err := fs.Open(d)
+		if err != nil {
+			return sdk.WithStack(err)
+		}
+		defer f.Close() // nolint
+		dirs[d] = f
+	}
+	workdir := filepath.Join(basedir, "run")
+
+	wk := &localWorker{
+		fs:     fs,
+		output: e.opts.Output,
+		params: e.parameters(workdir),
+	}
+	wk.client = &localClient{
+		artifactsDir: e.artifactsDir,
+		result:       e.result,
+	}
+
+	ctx = workerruntime.SetJobID(ctx, e.jobID)
+	ctx = workerruntime.SetWorkingDirectory(ctx, dirs["run"])
+	ctx = workerruntime.SetKeysDirectory(ctx, dirs["keys"])
+	ctx = workerruntime.SetTmpDirectory(ctx, dirs["tmp"])
+
+	var nDisabled, nCriticalFailed int
+	for i, step := range e.job.Action.Actions {
+		stepName := step.StepName
+		if stepName == "" {
+			stepName = step.Name
+		}
+		ctx := workerruntime.SetStepOrder(ctx, i)
+		ctx = workerruntime.SetStepName(ctx, stepName)
+		wk.step = stepName
+
+		stepResult := StepResult{Name: stepName, Status: sdk.StatusNeverBuilt}
+		if nCriticalFailed == 0 || step.AlwaysExecuted {
+			t0 := time.Now()
+			res := e.runStep(ctx, wk, step, basedir, workdir)
+			stepResult.Status, stepResult.Reason, stepResult.Duration = res.Status, res.Reason, time.Since(t0)
+			fmt.Fprintf(e.opts.Output, "End of step %q: %s (%s)\n", stepName, res.Status, sdk.Round(stepResult.Duration, time.Second))
+
+			for _, v := range res.NewVariables {
+				// append the new variable from a step to the following steps
+				wk.params = append(wk.params, v.ToParameter(""))
+			}
+
+			switch res.Status {
+			case sdk.StatusDisabled:
+				nDisabled++
+			case sdk.StatusFail:
+				if !step.Optional {
+					nCriticalFailed++
+				}
+			}
+		}
+		e.result.Steps = append(e.result.Steps, stepResult)
+	}
+
+	e.result.Status = sdk.StatusSuccess
+	if nDisabled >= len(e.job.Action.Actions) {
+		e.result.Status = sdk.StatusDisabled
+	}
+	if nCriticalFailed > 0 {
+		e.result.Status = sdk.StatusFail
+	}
+	return nil
+}
+
+func (e *executor) runStep(ctx context.Context, wk *localWorker, step sdk.Action, basedir, workdir string) sdk.Result {
+	if !step.Enabled {
+		return sdk.Result{Status: sdk.StatusDisabled}
+	}
+
+	f, ok := mapBuiltinActions[step.Name]
+	if step.Type != sdk.BuiltinAction || !ok {
+		res := sdk.Result{
+			Status: sdk.StatusFail,
+			Reason: fmt.Sprintf("action %s is not supported by local execution", step.Name),
+		}
+		wk.SendLog(ctx, workerruntime.LevelError, res.Reason)
+		return res
+	}
+
+	wk.SendLog(ctx, workerruntime.LevelInfo, fmt.Sprintf("Starting step %q", wk.step))
+
+	step.Parameters = withDefaultParameters(step.Name, step.Parameters)
+	paramsMap := sdk.ParametersToMap(wk.params)
+	for i := range step.Parameters {
+		var err error
+		step.Parameters[i].Value, err = interpolate.Do(step.Parameters[i].Value, paramsMap)
+		if err != nil {
+			return sdk.Result{
+				Status: sdk.StatusFail,
+				Reason: fmt.Sprintf("unable to interpolate action parameters: %v", err),
+			}
+		}
+	}
+
+	if step.Name == sdk.ScriptAction && e.opts.DockerImage != "" {
+		p := sdk.ParameterFind(step.Parameters, "script")
+		p.Value = dockerScript(p.Value, e.opts.DockerImage, basedir, workdir, wk.envNames())
+	}
+
+	res, err := f(ctx, wk, step, nil)
+	if err != nil {
+		res.Status = sdk.StatusFail
+		res.Reason = err.Error()
+		wk.SendLog(ctx, workerruntime.LevelError, res.Reason)
+	}
+	return res
+}
+
+// parameters returns the job parameters, values for pipeline parameters can be overridden
+// in execution options.
+func (e *executor) parameters(workdir string) []sdk.Parameter {
+	var params []sdk.Parameter
+	for _, p := range e.pipeline.Parameter {
+		value := p.Value
+		if v, ok := e.opts.Parameters[p.Name]; ok {
+			value = v
+		}
+		sdk.AddParameter(&params, "cds.pip."+p.Name, p.Type, value)
+	}
+	sdk.AddParameter(&params, "cds.project", sdk.StringParameter, "local")
+	sdk.AddParameter(&params, "cds.workflow", sdk.StringParameter, e.pipeline.Name)
+	sdk.AddParameter(&params, "cds.pipeline", sdk.StringParameter, e.pipeline.Name)
+	sdk.AddParameter(&params, "cds.stage", sdk.StringParameter, e.stage.Name)
+	sdk.AddParameter(&params, "cds.job", sdk.StringParameter, e.job.Action.Name)
+	sdk.AddParameter(&params, "cds.run.number", sdk.StringParameter, "1")
+	sdk.AddParameter(&params, "cds.version", sdk.StringParameter, "1")
+	sdk.AddParameter(&params, "cds.workspace", sdk.StringParameter, workdir)
+	sdk.AddParameter(&params, "cds.worker", sdk.StringParameter, "local")
+	return params
+}
+
+// withDefaultParameters adds the missing parameters of a builtin action with their
+// default values, like the API does when a pipeline is imported.
+func withDefaultParameters(name string, params []sdk.Parameter) []sdk.Parameter {
+	res := append([]sdk.Parameter{}, params...)
+	for _, m := range sdkaction.List {
+		if m.Action.Name != name {
+			continue
+		}
+		for _, p := range m.Action.Parameters {
+			if sdk.ParameterFind(res, p.Name) == nil {
+				// default value for parameter type list is the first item ("aa;bb;cc" -> "aa")
+				if p.Type == sdk.ListParameter && strings.Contains(p.Value, ";") {
+					p.Value = strings.Split(p.Value, ";")[0]
+				}
+				res = append(res, p)
+			}
+		}
+	}
+	return res
+}
+
+// dockerScript returns a script that runs given script content in a container of given image.
+// The job directory is mounted at the same path in the container so paths in parameters
+// and in the script stay valid.
+func dockerScript(content, image, basedir, workdir string, envNames []string) string {
+	shell := "/bin/sh -e"
+	if strings.HasPrefix(content, "#!") {
+		t := strings.SplitN(content, "\n", 2)
+		shell = strings.TrimRight(strings.TrimPrefix(t[0], "#!"), " \t\r\n")
+		if len(strings.Split(shell, " ")) == 1 && strings.HasSuffix(shell, "sh") {
+			shell += " -e"
+		}
+		content = ""
+		if len(t) > 1 {
+			content = t[1]
+		}
+	}
+
+	args := []string{"docker", "run", "--rm", "-v", basedir + ":" + basedir, "-w", workdir}
+	if uid, gid := os.Getuid(), os.Getgid(); uid >= 0 && gid >= 0 {
+		args = append(args, "-u", fmt.Sprintf("%d:%d", uid, gid))
+	}
+	for _, n := range envNames {
+		args = append(args, "-e", n)
+	}
+	args = append(args, image, shell)
+
+	return "#!" + strings.Join(args, " ") + "\n" + content
+}
+
+func slug(s string) string {
+	return strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '-' || r == '_' {
+			return r
+		}
+		return '_'
+	}, s)
+}
@@ -0,0 +1,106 @@
+package localexec
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/exportentities"
+)
+
+func TestRun(t *testing.T) {
+	dir, err := ioutil.TempDir("", "localexec")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir) // nolint
+
+	pipYAML := `version: v1.0
+name: build
+parameters:
+  greeting:
+    type: string
+    default: hello
+stages:
+- Build
+- Test
+- Deploy
+jobs:
+- job: Compile
+  stage: Build
+  steps:
+  - script:
+    - echo "{{.cds.pip.greeting}} from $CDS_JOB" > result.txt
+    - echo '<testsuites><testsuite name="suite"><testcase name="ok"></testcase></testsuite></testsuites>' > report.xml
+  - artifactUpload:
+      path: result.txt
+  - jUnitReport: report.xml
+- job: Check
+  stage: Test
+  steps:
+  - artifactDownload:
+      path: downloads
+  - script:
+    - cat downloads/result.txt
+    - grep "bonjour from Compile" downloads/result.txt
+- job: Fail
+  stage: Test
+  steps:
+  - script: exit 1
+  - name: always
+    script: echo always
+    always_executed: true
+- job: Deploy
+  stage: Deploy
+  steps:
+  - script: echo deploy
+`
+	payload, err := exportentities.ParsePipeline(exportentities.FormatYAML, []byte(pipYAML))
+	require.NoError(t, err)
+	pip, err := payload.Pipeline()
+	require.NoError(t, err)
+
+	var out bytes.Buffer
+	res, err := Run(context.TODO(), *pip, Options{
+		Workspace:    filepath.Join(dir, "workspace"),
+		ArtifactsDir: filepath.Join(dir, "artifacts"),
+		Parameters:   map[string]string{"greeting": "bonjour"},
+		Output:       &out,
+	})
+	require.NoError(t, err)
+	t.Log(out.String())
+
+	require.Len(t, res, 4)
+	require.Equal(t, sdk.StatusSuccess, res[0].Status)
+	require.Len(t, res[0].Steps, 3)
+	require.NotNil(t, res[0].Tests)
+	require.Equal(t, 1, res[0].Tests.Total)
+	require.Equal(t, sdk.StatusSuccess, res[1].Status)
+	require.Equal(t, sdk.StatusFail, res[2].Status)
+	require.Equal(t, sdk.StatusFail, res[2].Steps[0].Status)
+	require.Equal(t, sdk.StatusSuccess, res[2].Steps[1].Status)
+	require.Equal(t, sdk.StatusNeverBuilt, res[3].Status)
+	require.FileExists(t, filepath.Join(dir, "artifacts", "1", "result.txt"))
+	require.Contains(t, out.String(), "[Script] bonjour from Compile")
+
+	_, err = Run(context.TODO(), *pip, Options{
+		Workspace:    filepath.Join(dir, "workspace"),
+		ArtifactsDir: filepath.Join(dir, "artifacts"),
+		Parameters:   map[string]string{"unknown": "value"},
+		Output:       &out,
+	})
+	require.Error(t, err)
+}
+
+func Test_dockerScript(t *testing.T) {
+	s := dockerScript("#!/bin/bash\necho foo", "golang:1.14", "/tmp/job", "/tmp/job/run", []string{"CI", "CDS_JOB"})
+	require.Contains(t, s, "-v /tmp/job:/tmp/job -w /tmp/job/run")
+	require.Contains(t, s, "-e CI -e CDS_JOB golang:1.14 /bin/bash -e\necho foo")
+
+	s = dockerScript("echo foo", "alpine", "/tmp/job", "/tmp/job/run", nil)
+	require.Contains(t, s, "alpine /bin/sh -e\necho foo")
+}
@@ -0,0 +1,106 @@
+package localexec
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/spf13/afero"
+
+	"github.com/ovh/cds/engine/worker/pkg/workerruntime"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/cdsclient"
+)
+
+// localWorker is the worker runtime given to builtin actions for a local execution.
+type localWorker struct {
+	fs     afero.Fs
+	mutex  sync.Mutex
+	output io.Writer
+	client *localClient
+	params []sdk.Parameter
+	step   string
+}
+
+var _ workerruntime.Runtime = new(localWorker)
+
+func (*localWorker) Name() string { return "local" }
+
+func (*localWorker) Register(ctx context.Context) error { return nil }
+
+func (*localWorker) Take(ctx context.Context, job sdk.WorkflowNodeJobRun) error { return nil }
+
+func (*localWorker) ProcessJob(job sdk.WorkflowNodeJobRunData) sdk.Result { return sdk.Result{} }
+
+func (*localWorker) Unregister(ctx context.Context) error { return nil }
+
+func (wk *localWorker) SendLog(ctx context.Context, level workerruntime.Level, format string) {
+	// Some builtin actions send logs from several goroutines
+	wk.mutex.Lock()
+	defer wk.mutex.Unlock()
+	for _, line := range strings.Split(strings.TrimRight(format, "\n"), "\n") {
+		if level == workerruntime.LevelInfo {
+			fmt.Fprintf(wk.output, "[%s] %s\n", wk.step, line)
+		} else {
+			fmt.Fprintf(wk.output, "[%s] %s: %s\n", wk.step, level, line)
+		}
+	}
+}
+
+func (*localWorker) InstallKey(key sdk.Variable) (*workerruntime.KeyResponse, error) {
+	return nil, sdk.NewErrorFrom(sdk.ErrNotImplemented, "keys can't be installed by local execution")
+}
+
+func (*localWorker) InstallKeyTo(key sdk.Variable, destinationPath string) (*workerruntime.KeyResponse, error) {
+	return nil, sdk.NewErrorFrom(sdk.ErrNotImplemented, "keys can't be installed by local execution")
+}
+
+func (wk *localWorker) Client() cdsclient.WorkerInterface { return wk.client }
+
+func (wk *localWorker) BaseDir() afero.Fs { return wk.fs }
+
+// Environ returns the local environment without CDS technical variables, with
+// job parameters as environment variables.
+func (wk *localWorker) Environ() []string {
+	var env []string
+	for _, e := range os.Environ() {
+		if !strings.HasPrefix(e, "CDS_") {
+			env = append(env, e)
+		}
+	}
+	return append(env, wk.jobEnv()...)
+}
+
+func (wk *localWorker) jobEnv() []string {
+	env := []string{"CI=1"}
+	for _, p := range wk.params {
+		env = append(env, sdk.EnvVartoENV(p)...)
+		envName := strings.Replace(p.Name, ".", "_", -1)
+		envName = strings.Replace(envName, "-", "_", -1)
+		envName = strings.ToUpper(envName)
+		env = append(env, fmt.Sprintf("%s=%s", envName, p.Value))
+	}
+	return env
+}
+
+// envNames returns the names of environment variables set from job parameters.
+func (wk *localWorker) envNames() []string {
+	var names []string
+	for _, e := range wk.jobEnv() {
+		names = append(names, strings.SplitN(e, "=", 2)[0])
+	}
+	return names
+}
+
+// Blur does nothing as a local execution has no secrets.
+func (*localWorker) Blur(interface{}) error { return nil }
+
+func (*localWorker) HTTPPort() int32 { return 0 }
+
+// RegisterPostJobAction does nothing as no builtin action that needs it can run locally.
+func (*localWorker) RegisterPostJobAction(name string, f workerruntime.PostJobAction) {}
+
+func (wk *localWorker) Parameters() []sdk.Parameter { return wk.params }
@@ -47,6 +47,9 @@ type CDSVersionSet struct {
 
 type Level string
 
+// PostJobAction is a function executed by the worker at the end of a successful job.
+type PostJobAction func(ctx context.Context) error
+
 type (
 	contextKey int
 )
@@ -79,6 +82,7 @@ type Runtime interface {
 	Blur(interface{}) error
 	HTTPPort() int32
 	Parameters() []sdk.Parameter
+	RegisterPostJobAction(name string, f PostJobAction)
 }
 
 func JobID(ctx context.Context) (int64, error) {
@@ -25,6 +25,10 @@ const (
 	CheckoutApplicationAction = "CheckoutApplication"
 	DeployApplicationAction   = "DeployApplication"
 	InstallKeyAction          = "InstallKey"
+	CacheAction               = "Cache"
+	SARIFAction               = "SARIF"
+	DockerRunAction           = "DockerRun"
+	ChildPipelinesAction      = "ChildPipelines"
 
 	DefaultGitCloneParameterTagValue = "{{.git.tag}}"
 )
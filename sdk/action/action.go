@@ -14,14 +14,18 @@ import (
 var List = []Manifest{
 	ArtifactDownload,
 	ArtifactUpload,
+	Cache,
 	CheckoutApplication,
+	ChildPipelines,
 	Coverage,
 	DeployApplication,
+	DockerRun,
 	GitClone,
 	GitTag,
 	InstallKey,
 	JUnit,
 	Release,
+	SARIF,
 	Script,
 	ServeStaticFiles,
 }
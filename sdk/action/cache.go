@@ -0,0 +1,61 @@
+package action
+
+import (
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/exportentities"
+)
+
+// Cache action definition.
+var Cache = Manifest{
+	Action: sdk.Action{
+		Name: sdk.CacheAction,
+		Description: `CDS Builtin Action.
+Restore a cache of files and save it at the end of the job.
+
+The cache is restored from the given key, if there is no cache for this key the most recent cache that starts with one of the restore keys is restored.
+If the cache was not restored from the exact key, it is saved with the given key at the end of the job if all steps succeeded.
+
+The key can contain hashFiles("pattern", ...) calls, replaced by the hash of the files matching the patterns in the workspace, so the cache is renewed when your lockfiles change.`,
+		Parameters: []sdk.Parameter{
+			{
+				Name:        "key",
+				Description: `Key of the cache, example: go-hashFiles("go.sum").`,
+				Type:        sdk.StringParameter,
+			},
+			{
+				Name:        "restoreKeys",
+				Description: `(optional) Key prefixes used to restore a cache if there is no cache for the key, one by line.`,
+				Type:        sdk.TextParameter,
+			},
+			{
+				Name:        "path",
+				Description: `Paths of files and directories to cache, relative to the workspace, one by line.`,
+				Type:        sdk.TextParameter,
+			},
+			{
+				Name:        "destination",
+				Description: "(optional) Storage integration name of the cache.",
+				Type:        sdk.StringParameter,
+				Advanced:    true,
+			},
+		},
+	},
+	Example: exportentities.PipelineV1{
+		Version: exportentities.PipelineVersion1,
+		Name:    "Pipeline1",
+		Stages:  []string{"Stage1"},
+		Jobs: []exportentities.Job{{
+			Name:  "Job1",
+			Stage: "Stage1",
+			Steps: []exportentities.Step{
+				{
+					Cache: &exportentities.StepCache{
+						Key:         `go-hashFiles("go.sum")`,
+						RestoreKeys: "go-",
+						Path:        ".cache/go-build",
+					},
+				},
+			},
+		}},
+	},
+}
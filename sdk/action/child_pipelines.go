@@ -0,0 +1,44 @@
+package action
+
+import (
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/exportentities"
+)
+
+// ChildPipelines action definition.
+var ChildPipelines = Manifest{
+	Action: sdk.Action{
+		Name: sdk.ChildPipelinesAction,
+		Description: `CDS Builtin Action.
+Add pipelines generated in the workspace as children of the current pipeline in the running workflow.
+
+Pipeline files use the same format as exported pipelines. Added pipelines are triggered with the same application and environment when the current pipeline succeeds.
+A child pipeline can also add child pipelines, the depth, number of pipelines and number of jobs are limited by the CDS administrator.`,
+		Parameters: []sdk.Parameter{
+			{
+				Name:        "path",
+				Description: `Path of the pipeline files, glob patterns are allowed.`,
+				Type:        sdk.StringParameter,
+			},
+		},
+	},
+	Example: exportentities.PipelineV1{
+		Version: exportentities.PipelineVersion1,
+		Name:    "Pipeline1",
+		Stages:  []string{"Stage1"},
+		Jobs: []exportentities.Job{{
+			Name:  "Job1",
+			Stage: "Stage1",
+			Steps: []exportentities.Step{
+				{
+					Script: []string{"./generate-pipelines.sh > ./pipelines/services.yml"},
+				},
+				{
+					ChildPipelines: &exportentities.StepChildPipelines{
+						Path: "./pipelines/*.yml",
+					},
+				},
+			},
+		}},
+	},
+}
@@ -13,7 +13,9 @@ var Coverage = Manifest{
 Parse given file to extract coverage results.
 
 Coverage report will be linked to the application from the pipeline context.
-You will be able to see the coverage history in the application home page.`,
+You will be able to see the coverage history in the application home page.
+
+The step fails if the line or branch coverage is lower than the given minimums, or if the line coverage dropped more than the given maximum compared to the default branch.`,
 		Parameters: []sdk.Parameter{
 			{
 				Name:        "format",
@@ -28,7 +30,19 @@ You will be able to see the coverage history in the application home page.`,
 			},
 			{
 				Name:        "minimum",
-				Description: `Minimum percentage of coverage required (-1 means no minimum).`,
+				Description: `Minimum percentage of line coverage required (-1 means no minimum).`,
+				Type:        sdk.NumberParameter,
+				Advanced:    true,
+			},
+			{
+				Name:        "minimumBranch",
+				Description: `Minimum percentage of branch coverage required (-1 means no minimum).`,
+				Type:        sdk.NumberParameter,
+				Advanced:    true,
+			},
+			{
+				Name:        "maximumDrop",
+				Description: `Maximum drop of line coverage in percentage points compared to the default branch (-1 means no maximum).`,
 				Type:        sdk.NumberParameter,
 				Advanced:    true,
 			},
@@ -44,9 +58,10 @@ You will be able to see the coverage history in the application home page.`,
 			Steps: []exportentities.Step{
 				{
 					Coverage: &exportentities.StepCoverage{
-						Format:  "cobertura",
-						Minimum: "60",
-						Path:    "./coverage.xml",
+						Format:      "cobertura",
+						Minimum:     "60",
+						MaximumDrop: "1",
+						Path:        "./coverage.xml",
 					},
 				},
 			},
@@ -0,0 +1,67 @@
+package action
+
+import (
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/exportentities"
+)
+
+// DockerRun action definition.
+var DockerRun = Manifest{
+	Action: sdk.Action{
+		Name: sdk.DockerRunAction,
+		Description: `CDS Builtin Action.
+Run a container with the local container runtime of the worker.
+
+The job working directory is mounted in the container, so files created by the previous steps are available and files created in the container can be used by the next steps.
+Container logs are streamed in the step logs, the step fails if the container exit code is not 0. The container is removed at the end of the step.`,
+		Parameters: []sdk.Parameter{
+			{
+				Name:        "image",
+				Description: `Image of the container, example: golang:1.14.`,
+				Type:        sdk.StringParameter,
+			},
+			{
+				Name:        "command",
+				Description: `(optional) Command arguments of the container, one by line.`,
+				Type:        sdk.TextParameter,
+			},
+			{
+				Name:        "entrypoint",
+				Description: `(optional) Override the entrypoint of the image.`,
+				Type:        sdk.StringParameter,
+				Advanced:    true,
+			},
+			{
+				Name:        "env",
+				Description: `(optional) Environment variables of the container as KEY=VALUE, one by line. CDS variables are always available.`,
+				Type:        sdk.TextParameter,
+				Advanced:    true,
+			},
+			{
+				Name:        "workspace",
+				Description: `(optional) Path where the job working directory is mounted in the container, it is also the container working directory.`,
+				Type:        sdk.StringParameter,
+				Value:       "/workspace",
+				Advanced:    true,
+			},
+		},
+	},
+	Example: exportentities.PipelineV1{
+		Version: exportentities.PipelineVersion1,
+		Name:    "Pipeline1",
+		Stages:  []string{"Stage1"},
+		Jobs: []exportentities.Job{{
+			Name:  "Job1",
+			Stage: "Stage1",
+			Steps: []exportentities.Step{
+				{
+					DockerRun: &exportentities.StepDockerRun{
+						Image:   "node:14",
+						Command: "npm\nci",
+						Env:     "NODE_ENV=test",
+					},
+				},
+			},
+		}},
+	},
+}
@@ -0,0 +1,57 @@
+package action
+
+import (
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/exportentities"
+)
+
+// SARIF action definition.
+var SARIF = Manifest{
+	Action: sdk.Action{
+		Name: sdk.SARIFAction,
+		Description: `CDS Builtin Action.
+Parse SARIF 2.1 files to import static analysis and security findings.
+
+Findings are compared to the latest analysis on the default branch to compute new and fixed findings.
+The step fails if a finding has a severity greater than or equal to the given severity.`,
+		Parameters: []sdk.Parameter{
+			{
+				Name:        "path",
+				Description: `Path of the SARIF files, glob patterns are allowed.`,
+				Type:        sdk.StringParameter,
+			},
+			{
+				Name:        "failOnSeverity",
+				Description: `(optional) Minimum severity of a finding that fails the step.`,
+				Type:        sdk.ListParameter,
+				Value:       ";negligible;low;medium;high;critical",
+				Advanced:    true,
+			},
+			{
+				Name:        "newOnly",
+				Description: `Only new findings compared to the default branch can fail the step.`,
+				Type:        sdk.BooleanParameter,
+				Value:       "false",
+				Advanced:    true,
+			},
+		},
+	},
+	Example: exportentities.PipelineV1{
+		Version: exportentities.PipelineVersion1,
+		Name:    "Pipeline1",
+		Stages:  []string{"Stage1"},
+		Jobs: []exportentities.Job{{
+			Name:  "Job1",
+			Stage: "Stage1",
+			Steps: []exportentities.Step{
+				{
+					SARIF: &exportentities.StepSARIF{
+						Path:           "./results/*.sarif",
+						FailOnSeverity: "high",
+						NewOnly:        "true",
+					},
+				},
+			},
+		}},
+	},
+}
@@ -2,6 +2,9 @@ package sdk
 
 import (
 	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
 	"time"
 
 	"github.com/go-gorp/gorp"
@@ -55,6 +58,14 @@ type AuditWorkflowTemplateInstance struct {
 	DataAfter                  string `json:"data_after" db:"data_after"`
 }
 
+// AuditGroup represents an audit data on a group.
+type AuditGroup struct {
+	AuditCommon
+	GroupID   int64  `json:"group_id" db:"group_id"`
+	GroupName string `json:"group_name" db:"group_name"`
+	Username  string `json:"username" db:"username"`
+}
+
 // AuditAction represents an audit data on a action.
 type AuditAction struct {
 	AuditCommon
@@ -63,3 +74,52 @@ type AuditAction struct {
 	DataBefore string `json:"data_before" db:"data_before"`
 	DataAfter  string `json:"data_after" db:"data_after"`
 }
+
+// AuditAPICall represents an audit data on a mutating API call (POST, PUT or DELETE).
+type AuditAPICall struct {
+	ID           int64            `json:"id" db:"id" cli:"id"`
+	Created      time.Time        `json:"created" db:"created" cli:"created"`
+	RequestID    string           `json:"request_id" db:"request_id"`
+	ConsumerID   string           `json:"consumer_id,omitempty" db:"consumer_id" cli:"consumer_id"`
+	UserID       string           `json:"user_id,omitempty" db:"user_id"`
+	Username     string           `json:"username,omitempty" db:"username" cli:"username"`
+	IP           string           `json:"ip" db:"ip" cli:"ip"`
+	Method       string           `json:"method" db:"method" cli:"method"`
+	Route        string           `json:"route" db:"route" cli:"route"`
+	Handler      string           `json:"handler" db:"handler"`
+	ProjectKey   string           `json:"project_key,omitempty" db:"project_key" cli:"project_key"`
+	WorkflowName string           `json:"workflow_name,omitempty" db:"workflow_name" cli:"workflow_name"`
+	RouteVars    AuditAPICallVars `json:"route_vars,omitempty" db:"route_vars"`
+	StatusCode   int              `json:"status_code" db:"status_code" cli:"status_code"`
+	Success      bool             `json:"success" db:"success" cli:"success"`
+}
+
+// AuditAPICallVars contains route variables (resource identifiers) for an API call.
+type AuditAPICallVars map[string]string
+
+// Value returns driver.Value from AuditAPICallVars.
+func (a AuditAPICallVars) Value() (driver.Value, error) {
+	j, err := json.Marshal(a)
+	return j, WrapError(err, "cannot marshal AuditAPICallVars")
+}
+
+// Scan AuditAPICallVars.
+func (a *AuditAPICallVars) Scan(src interface{}) error {
+	if src == nil {
+		return nil
+	}
+	source, ok := src.([]byte)
+	if !ok {
+		return WithStack(fmt.Errorf("type assertion .([]byte) failed (%T)", src))
+	}
+	return WrapError(json.Unmarshal(source, a), "cannot unmarshal AuditAPICallVars")
+}
+
+// AuditAPICallFilter is used to query API call audits.
+type AuditAPICallFilter struct {
+	ProjectKey string
+	Username   string
+	Since      time.Time
+	Until      time.Time
+	Limit      int
+}
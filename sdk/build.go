@@ -36,6 +36,8 @@ type ExecutedJob struct {
 	Reason     string       `json:"reason" db:"-"`
 	WorkerName string       `json:"worker_name" db:"-"`
 	WorkerID   string       `json:"worker_id" db:"-"`
+	// MatrixValues contains the values of the matrix combination for a job run created from a job matrix
+	MatrixValues map[string]string `json:"matrix_values,omitempty" db:"-"`
 }
 
 // ExecutedJobSummary is a light representation of ExecutedJob for CDS event
@@ -2,12 +2,17 @@ package sdk
 
 import (
 	"archive/tar"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"io"
 	"net/url"
 	"os"
 	"path/filepath"
+	"regexp"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/spf13/afero"
 )
@@ -21,11 +26,191 @@ type Cache struct {
 	TmpURL          string `json:"tmp_url"`
 	SecretKey       string `json:"secret_key"`
 	IntegrationName string `json:"integration_name"`
+	Key             string `json:"key,omitempty"`
+	Size            int64  `json:"size,omitempty"`
 
 	Files            []string `json:"files"`
 	WorkingDirectory string   `json:"working_directory"`
 }
 
+// CacheEntry describes a cache stored for a project, used to find caches by key prefix
+// and to evict least recently used caches.
+type CacheEntry struct {
+	ID              int64     `json:"id" db:"id" cli:"-"`
+	ProjectID       int64     `json:"project_id" db:"project_id" cli:"-"`
+	IntegrationName string    `json:"integration_name" db:"integration_name" cli:"integration"`
+	Tag             string    `json:"tag" db:"tag" cli:"-"`
+	Key             string    `json:"key" db:"key" cli:"key,key"`
+	Size            int64     `json:"size" db:"size" cli:"size"`
+	Created         time.Time `json:"created" db:"created" cli:"created"`
+	LastAccess      time.Time `json:"last_access" db:"last_access" cli:"last_access"`
+}
+
+var cacheKeyHashFilesRegex = regexp.MustCompile(`hashFiles\(((?:[^)"']|"[^"]*"|'[^']*')*)\)`)
+
+// ComputeCacheKey returns the cache key for given expression, each hashFiles("pattern", ...)
+// call is replaced by the sha256 of the files from given directory that match the patterns.
+// Patterns are quoted and relative to the directory, "**" matches any number of directories
+// and "{a,b}" matches one of the alternatives.
+func ComputeCacheKey(fs afero.Fs, dir, key string) (string, error) {
+	var errCompute error
+	res := cacheKeyHashFilesRegex.ReplaceAllStringFunc(key, func(call string) string {
+		args, err := parseHashFilesArgs(cacheKeyHashFilesRegex.FindStringSubmatch(call)[1])
+		if err != nil {
+			errCompute = err
+			return ""
+		}
+		var patterns []string
+		for _, arg := range args {
+			for _, p := range expandBraces(arg) {
+				if p != "" {
+					patterns = append(patterns, filepath.ToSlash(p))
+				}
+			}
+		}
+		h, err := hashFiles(fs, dir, patterns)
+		if err != nil {
+			errCompute = err
+		}
+		return h
+	})
+	return res, errCompute
+}
+
+// parseHashFilesArgs returns the arguments of a hashFiles call, each one is quoted with
+// simple or double quotes and arguments are separated by commas.
+func parseHashFilesArgs(s string) ([]string, error) {
+	var args []string
+	for {
+		s = strings.TrimSpace(s)
+		if s == "" {
+			return args, nil
+		}
+		quote := s[0]
+		if quote != '"' && quote != '\'' {
+			return nil, NewErrorFrom(ErrWrongRequest, "invalid hashFiles argument %s, patterns must be quoted", s)
+		}
+		end := strings.IndexByte(s[1:], quote)
+		if end < 0 {
+			return nil, NewErrorFrom(ErrWrongRequest, "invalid hashFiles argument %s, missing closing quote", s)
+		}
+		args = append(args, s[1:end+1])
+		s = strings.TrimSpace(s[end+2:])
+		if s == "" {
+			return args, nil
+		}
+		if s[0] != ',' {
+			return nil, NewErrorFrom(ErrWrongRequest, "invalid hashFiles arguments, expected a comma before %s", s)
+		}
+		s = s[1:]
+	}
+}
+
+// expandBraces returns the patterns described by a pattern with "{a,b}" alternatives, that
+// can be nested. A pattern with unbalanced braces is returned as is.
+func expandBraces(pattern string) []string {
+	start := strings.IndexByte(pattern, '{')
+	if start < 0 {
+		return []string{pattern}
+	}
+	var alternatives []string
+	depth, last := 0, start+1
+	for i := start; i < len(pattern); i++ {
+		switch pattern[i] {
+		case '{':
+			depth++
+		case ',':
+			if depth == 1 {
+				alternatives = append(alternatives, pattern[last:i])
+				last = i + 1
+			}
+		case '}':
+			depth--
+			if depth > 0 {
+				continue
+			}
+			alternatives = append(alternatives, pattern[last:i])
+			var res []string
+			for _, a := range alternatives {
+				res = append(res, expandBraces(pattern[:start]+a+pattern[i+1:])...)
+			}
+			return res
+		}
+	}
+	return []string{pattern}
+}
+
+func hashFiles(fs afero.Fs, dir string, patterns []string) (string, error) {
+	if len(patterns) == 0 {
+		return "", NewErrorFrom(ErrWrongRequest, "hashFiles needs at least one file pattern")
+	}
+
+	var files []string
+	err := afero.Walk(fs, dir, func(path string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !fi.Mode().IsRegular() {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		for _, p := range patterns {
+			if matchPathPattern(strings.Split(p, "/"), strings.Split(rel, "/")) {
+				files = append(files, rel)
+				break
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return "", WrapError(err, "cannot walk directory %s", dir)
+	}
+	if len(files) == 0 {
+		return "", NewErrorFrom(ErrNotFound, "no file matches %s", strings.Join(patterns, ", "))
+	}
+	sort.Strings(files)
+
+	h := sha256.New()
+	for _, f := range files {
+		fi, err := fs.Open(filepath.Join(dir, filepath.FromSlash(f)))
+		if err != nil {
+			return "", WithStack(err)
+		}
+		_, _ = io.WriteString(h, f+"\x00")
+		_, err = io.Copy(h, fi)
+		_ = fi.Close()
+		if err != nil {
+			return "", WithStack(err)
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func matchPathPattern(pattern, name []string) bool {
+	if len(pattern) == 0 {
+		return len(name) == 0
+	}
+	if pattern[0] == "**" {
+		for i := 0; i <= len(name); i++ {
+			if matchPathPattern(pattern[1:], name[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(name) == 0 {
+		return false
+	}
+	if ok, _ := filepath.Match(pattern[0], name[0]); !ok {
+		return false
+	}
+	return matchPathPattern(pattern[1:], name[1:])
+}
+
 //GetName returns the name the artifact
 func (c *Cache) GetName() string {
 	return c.Name
@@ -132,3 +317,50 @@ func CreateTarFromPaths(fs afero.Fs, cwd string, paths []string, w io.Writer, op
 
 	return nil
 }
+
+// ExtractTar writes the content of given tar reader in given directory.
+func ExtractTar(r io.Reader, dir string) error {
+	tr := tar.NewReader(r)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return NewErrorWithStack(err, NewErrorFrom(ErrWrongRequest, "unable to read tar file"))
+		}
+
+		// the target location where the dir/file should be created
+		target := filepath.Join(dir, header.Name)
+		if rel, err := filepath.Rel(dir, target); err != nil || strings.HasPrefix(rel, "..") {
+			return NewErrorFrom(ErrWrongRequest, "invalid file path %s in tar file", header.Name)
+		}
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0755); err != nil {
+				return WrapError(err, "unable to create directory %s", target)
+			}
+		case tar.TypeSymlink:
+			if err := os.Symlink(header.Linkname, target); err != nil {
+				return WrapError(err, "unable to create symlink %s", target)
+			}
+		case tar.TypeReg, tar.TypeLink:
+			// if directory of file does not exist, create it before
+			if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				return WrapError(err, "unable to create directory %s", filepath.Dir(target))
+			}
+			f, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, os.FileMode(header.Mode))
+			if err != nil {
+				return WrapError(err, "unable to open file %s", target)
+			}
+			if _, err := io.Copy(f, tr); err != nil {
+				_ = f.Close()
+				return WrapError(err, "unable to write file %s", target)
+			}
+			if err := f.Close(); err != nil {
+				return WithStack(err)
+			}
+		}
+	}
+}
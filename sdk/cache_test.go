@@ -0,0 +1,88 @@
+package sdk
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+func TestComputeCacheKey(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "/src/go.sum", []byte("foo"), 0644))
+	require.NoError(t, afero.WriteFile(fs, "/src/ui/package-lock.json", []byte("bar"), 0644))
+	require.NoError(t, afero.WriteFile(fs, "/src/ui/lib/package-lock.json", []byte("baz"), 0644))
+
+	key, err := ComputeCacheKey(fs, "/src", "static-key")
+	require.NoError(t, err)
+	require.Equal(t, "static-key", key)
+
+	goKey, err := ComputeCacheKey(fs, "/src", `go-hashFiles("go.sum")`)
+	require.NoError(t, err)
+	require.Regexp(t, "^go-[a-f0-9]{64}$", goKey)
+
+	npmKey, err := ComputeCacheKey(fs, "/src", `npm-hashFiles('**/package-lock.json')`)
+	require.NoError(t, err)
+	require.Regexp(t, "^npm-[a-f0-9]{64}$", npmKey)
+
+	// Key changes when a matching file changes
+	require.NoError(t, afero.WriteFile(fs, "/src/ui/lib/package-lock.json", []byte("updated"), 0644))
+	updatedKey, err := ComputeCacheKey(fs, "/src", `npm-hashFiles('**/package-lock.json')`)
+	require.NoError(t, err)
+	require.NotEqual(t, npmKey, updatedKey)
+
+	sameGoKey, err := ComputeCacheKey(fs, "/src", `go-hashFiles("go.sum", "unknown.sum")`)
+	require.NoError(t, err)
+	require.Equal(t, goKey, sameGoKey)
+
+	_, err = ComputeCacheKey(fs, "/src", `hashFiles("unknown")`)
+	require.Error(t, err)
+
+	// Commas in a quoted pattern are not argument separators
+	require.NoError(t, afero.WriteFile(fs, "/src/a/a.lock", []byte("a"), 0644))
+	require.NoError(t, afero.WriteFile(fs, "/src/b/b.lock", []byte("b"), 0644))
+	bracesKey, err := ComputeCacheKey(fs, "/src", `hashFiles("{a,b}/*.lock")`)
+	require.NoError(t, err)
+	listKey, err := ComputeCacheKey(fs, "/src", `hashFiles("a/*.lock", 'b/*.lock')`)
+	require.NoError(t, err)
+	require.Equal(t, listKey, bracesKey)
+	aKey, err := ComputeCacheKey(fs, "/src", `hashFiles("a/*.lock")`)
+	require.NoError(t, err)
+	require.NotEqual(t, aKey, bracesKey)
+
+	_, err = ComputeCacheKey(fs, "/src", `hashFiles(go.sum)`)
+	require.Error(t, err)
+	_, err = ComputeCacheKey(fs, "/src", `hashFiles("go.sum" "a/*.lock")`)
+	require.Error(t, err)
+}
+
+func Test_expandBraces(t *testing.T) {
+	require.Equal(t, []string{"go.sum"}, expandBraces("go.sum"))
+	require.Equal(t, []string{"a/*.lock", "b/*.lock"}, expandBraces("{a,b}/*.lock"))
+	require.Equal(t, []string{"a/x", "a/y", "b"}, expandBraces("{a/{x,y},b}"))
+	require.Equal(t, []string{"{a,b"}, expandBraces("{a,b"))
+}
+
+func TestExtractTar(t *testing.T) {
+	src, err := ioutil.TempDir("", "cache-src")
+	require.NoError(t, err)
+	defer os.RemoveAll(src) // nolint
+	dst, err := ioutil.TempDir("", "cache-dst")
+	require.NoError(t, err)
+	defer os.RemoveAll(dst) // nolint
+
+	require.NoError(t, os.MkdirAll(filepath.Join(src, "vendor", "lib"), 0755))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(src, "vendor", "lib", "file.txt"), []byte("content"), 0644))
+
+	var buf bytes.Buffer
+	require.NoError(t, CreateTarFromPaths(afero.NewOsFs(), src, []string{"vendor"}, &buf, nil))
+	require.NoError(t, ExtractTar(&buf, dst))
+
+	content, err := ioutil.ReadFile(filepath.Join(dst, "vendor", "lib", "file.txt"))
+	require.NoError(t, err)
+	require.Equal(t, "content", string(content))
+}
@@ -3,9 +3,12 @@ package cdsclient
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/url"
+	"strconv"
+	"time"
 
 	"github.com/ovh/cds/sdk"
 )
@@ -33,6 +36,69 @@ func (c *client) AdminCDSMigrationCancel(id int64) error {
 	return err
 }
 
+func (c *client) AdminAuthGroupSync(consumerType sdk.AuthConsumerType, dryRun bool) (sdk.GroupSyncReport, error) {
+	var report sdk.GroupSyncReport
+	path := fmt.Sprintf("/admin/auth/%s/group/sync", consumerType)
+	if dryRun {
+		if _, err := c.GetJSON(context.Background(), path, &report); err != nil {
+			return report, err
+		}
+		return report, nil
+	}
+	if _, err := c.PostJSON(context.Background(), path, nil, &report); err != nil {
+		return report, err
+	}
+	return report, nil
+}
+
+func (c *client) AdminAuditAPICalls(filter sdk.AuditAPICallFilter) ([]sdk.AuditAPICall, error) {
+	var mods []RequestModifier
+	if filter.ProjectKey != "" {
+		mods = append(mods, WithQueryParameter("project", filter.ProjectKey))
+	}
+	if filter.Username != "" {
+		mods = append(mods, WithQueryParameter("username", filter.Username))
+	}
+	if !filter.Since.IsZero() {
+		mods = append(mods, WithQueryParameter("since", filter.Since.Format(time.RFC3339)))
+	}
+	if !filter.Until.IsZero() {
+		mods = append(mods, WithQueryParameter("until", filter.Until.Format(time.RFC3339)))
+	}
+	if filter.Limit > 0 {
+		mods = append(mods, WithQueryParameter("limit", strconv.Itoa(filter.Limit)))
+	}
+	var as []sdk.AuditAPICall
+	if _, err := c.GetJSON(context.Background(), "/admin/audit/api", &as, mods...); err != nil {
+		return nil, err
+	}
+	return as, nil
+}
+
+func (c *client) AdminJobUsages(filter sdk.JobUsageFilter) ([]sdk.JobUsageSummary, error) {
+	var mods []RequestModifier
+	if filter.ProjectKey != "" {
+		mods = append(mods, WithQueryParameter("project", filter.ProjectKey))
+	}
+	if filter.GroupName != "" {
+		mods = append(mods, WithQueryParameter("group", filter.GroupName))
+	}
+	if filter.ModelName != "" {
+		mods = append(mods, WithQueryParameter("model", filter.ModelName))
+	}
+	if !filter.Since.IsZero() {
+		mods = append(mods, WithQueryParameter("since", filter.Since.Format(time.RFC3339)))
+	}
+	if !filter.Until.IsZero() {
+		mods = append(mods, WithQueryParameter("until", filter.Until.Format(time.RFC3339)))
+	}
+	var ss []sdk.JobUsageSummary
+	if _, err := c.GetJSON(context.Background(), "/admin/usage", &ss, mods...); err != nil {
+		return nil, err
+	}
+	return ss, nil
+}
+
 func (c *client) AdminCDSMigrationReset(id int64) error {
 	_, _, _, err := c.Request(context.Background(), "POST", fmt.Sprintf("/admin/cds/migration/%d/todo", id), nil)
 	return err
@@ -184,3 +250,29 @@ func (c *client) AdminDatabaseRollAllEncryptedEntities() error {
 	}
 	return nil
 }
+
+func (c *client) AdminDatabaseKeyRotationStatus(serviceType string) (sdk.DatabaseKeyRotation, error) {
+	var res sdk.DatabaseKeyRotation
+	if serviceType != "" && serviceType != sdk.TypeAPI {
+		btes, err := c.ServiceCallGET(serviceType, "/admin/database/rotation")
+		if err != nil {
+			return res, err
+		}
+		return res, sdk.WithStack(json.Unmarshal(btes, &res))
+	}
+	_, err := c.GetJSON(context.Background(), "/admin/database/rotation", &res)
+	return res, err
+}
+
+func (c *client) AdminDatabaseKeyRotationStart(serviceType string) (sdk.DatabaseKeyRotation, error) {
+	var res sdk.DatabaseKeyRotation
+	if serviceType != "" && serviceType != sdk.TypeAPI {
+		btes, err := c.ServiceCallPOST(serviceType, "/admin/database/rotation", nil)
+		if err != nil {
+			return res, err
+		}
+		return res, sdk.WithStack(json.Unmarshal(btes, &res))
+	}
+	_, err := c.PostJSON(context.Background(), "/admin/database/rotation", nil, &res)
+	return res, err
+}
@@ -76,6 +76,21 @@ func (c *client) AuthConsumerCreateForUser(username string, request sdk.AuthCons
 	return consumer, err
 }
 
+func (c *client) AuthConsumerSetAllowedCIDRs(username, id string, cidrs []string) (sdk.AuthConsumer, error) {
+	var consumer sdk.AuthConsumer
+	request := sdk.AuthConsumerAllowedCIDRsRequest{AllowedCIDRs: cidrs}
+	_, _, _, err := c.RequestJSON(context.Background(), "PUT", "/user/"+username+"/auth/consumer/"+id+"/cidr", request, &consumer)
+	return consumer, err
+}
+
+func (c *client) AuthConsumerIPRejectionList(username, id string) ([]sdk.AuthConsumerIPRejection, error) {
+	var rs []sdk.AuthConsumerIPRejection
+	if _, err := c.GetJSON(context.Background(), "/user/"+username+"/auth/consumer/"+id+"/rejection", &rs); err != nil {
+		return nil, err
+	}
+	return rs, nil
+}
+
 func (c *client) AuthSessionListByUser(username string) (sdk.AuthSessions, error) {
 	var sessions sdk.AuthSessions
 	if _, err := c.GetJSON(context.Background(), "/user/"+username+"/auth/session", &sessions); err != nil {
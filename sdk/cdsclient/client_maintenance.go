@@ -3,9 +3,26 @@ package cdsclient
 import (
 	"context"
 	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/ovh/cds/sdk"
 )
 
 func (c *client) Maintenance(enable bool, hooks bool) error {
 	_, err := c.PostJSON(context.Background(), fmt.Sprintf("/admin/maintenance?enable=%v&withHook=%v", enable, hooks), nil, nil)
 	return err
 }
+
+func (c *client) MaintenanceDrain(maxWait time.Duration, hooks bool) error {
+	_, err := c.PostJSON(context.Background(), fmt.Sprintf("/admin/maintenance?enable=true&drain=true&maxWait=%s&withHook=%v", url.QueryEscape(maxWait.String()), hooks), nil, nil)
+	return err
+}
+
+func (c *client) MaintenanceStatus() (sdk.MaintenanceStatus, error) {
+	var status sdk.MaintenanceStatus
+	if _, err := c.GetJSON(context.Background(), "/admin/maintenance", &status); err != nil {
+		return status, err
+	}
+	return status, nil
+}
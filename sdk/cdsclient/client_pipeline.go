@@ -32,3 +32,35 @@ func (c *client) PipelineList(projectKey string) ([]sdk.Pipeline, error) {
 	}
 	return pipelines, nil
 }
+
+func (c *client) PipelinePublish(projectKey, name string, req sdk.SharedPipelinePublishRequest) (*sdk.SharedPipeline, error) {
+	var sp sdk.SharedPipeline
+	if _, err := c.PostJSON(context.Background(), "/project/"+projectKey+"/pipeline/"+url.QueryEscape(name)+"/publish", req, &sp); err != nil {
+		return nil, err
+	}
+	return &sp, nil
+}
+
+func (c *client) PipelineUseShared(projectKey string, req sdk.SharedPipelineUseRequest) (*sdk.Pipeline, error) {
+	var pip sdk.Pipeline
+	if _, err := c.PostJSON(context.Background(), "/project/"+projectKey+"/sharedPipeline", req, &pip); err != nil {
+		return nil, err
+	}
+	return &pip, nil
+}
+
+func (c *client) SharedPipelineList() ([]sdk.SharedPipeline, error) {
+	var sps []sdk.SharedPipeline
+	if _, err := c.GetJSON(context.Background(), "/sharedPipeline", &sps); err != nil {
+		return nil, err
+	}
+	return sps, nil
+}
+
+func (c *client) SharedPipelineUsage(groupName, name string) ([]sdk.UsageSharedPipeline, error) {
+	var us []sdk.UsageSharedPipeline
+	if _, err := c.GetJSON(context.Background(), "/sharedPipeline/"+url.QueryEscape(groupName)+"/"+url.QueryEscape(name)+"/usage", &us); err != nil {
+		return nil, err
+	}
+	return us, nil
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"net/http"
 	"net/url"
 
 	"github.com/ovh/cds/sdk"
@@ -79,3 +80,35 @@ func (c *client) ProjectGroupsImport(projectKey string, content io.Reader, mods
 
 	return proj, nil
 }
+
+func (c *client) ProjectExport(projectKey, passphrase string, mods ...RequestModifier) ([]byte, error) {
+	path := fmt.Sprintf("/project/%s/export", projectKey)
+	mods = append(mods, SetHeader(sdk.ProjectBundlePassphraseHeader, passphrase))
+	btes, _, _, err := c.Request(context.Background(), "POST", path, nil, mods...)
+	if err != nil {
+		return nil, err
+	}
+	return btes, nil
+}
+
+func (c *client) ProjectImport(projectKey, passphrase string, content io.Reader, dryRun bool, mods ...RequestModifier) (sdk.ProjectBundleImportResult, error) {
+	var res sdk.ProjectBundleImportResult
+
+	path := fmt.Sprintf("/project/%s/import/bundle", projectKey)
+	mods = append(mods, SetHeader(sdk.ProjectBundlePassphraseHeader, passphrase), func(r *http.Request) {
+		r.Header.Set("Content-Type", "application/tar")
+	})
+	if dryRun {
+		mods = append(mods, WithQueryParameter("dryRun", "true"))
+	}
+	btes, _, _, err := c.Request(context.Background(), "POST", path, content, mods...)
+	if err != nil {
+		return res, err
+	}
+
+	if err := json.Unmarshal(btes, &res); err != nil {
+		return res, err
+	}
+
+	return res, nil
+}
@@ -245,10 +245,21 @@ func (c *client) QueueSendResult(ctx context.Context, id int64, res sdk.Result)
 	return err
 }
 
-func (c *client) QueueSendCoverage(ctx context.Context, id int64, report coverage.Report) error {
+// QueueSendCoverage sends a coverage report and returns the coverage of the node run with trends.
+func (c *client) QueueSendCoverage(ctx context.Context, id int64, report coverage.Report) (*sdk.WorkflowNodeRunCoverage, error) {
 	path := fmt.Sprintf("/queue/workflows/%d/coverage", id)
-	_, err := c.PostJSON(ctx, path, report, nil)
-	return err
+	res, _, _, err := c.RequestJSON(ctx, http.MethodPost, path, report, nil)
+	if err != nil {
+		return nil, err
+	}
+	var cov sdk.WorkflowNodeRunCoverage
+	// Older API versions don't return the coverage
+	if len(res) > 0 {
+		if err := json.Unmarshal(res, &cov); err != nil {
+			return nil, sdk.WithStack(err)
+		}
+	}
+	return &cov, nil
 }
 
 func (c *client) QueueSendUnitTests(ctx context.Context, id int64, report venom.Tests) error {
@@ -269,6 +280,27 @@ func (c *client) QueueSendVulnerability(ctx context.Context, id int64, report sd
 	return err
 }
 
+// QueueSendAnalysis sends the findings of an analysis tool and returns them with their status
+// compared to the default branch.
+func (c *client) QueueSendAnalysis(ctx context.Context, id int64, report sdk.AnalysisWorkerReport) (*sdk.WorkflowNodeRunAnalysis, error) {
+	path := fmt.Sprintf("/queue/workflows/%d/analysis", id)
+	var analysis sdk.WorkflowNodeRunAnalysis
+	if _, err := c.PostJSON(ctx, path, report, &analysis); err != nil {
+		return nil, err
+	}
+	return &analysis, nil
+}
+
+// QueueAddChildPipelines adds pipelines as children of the pipeline of the job and returns the added nodes.
+func (c *client) QueueAddChildPipelines(ctx context.Context, id int64, req sdk.ChildPipelinesRequest) ([]sdk.Node, error) {
+	path := fmt.Sprintf("/queue/workflows/%d/pipelines", id)
+	var nodes []sdk.Node
+	if _, err := c.PostJSON(ctx, path, req, &nodes); err != nil {
+		return nil, err
+	}
+	return nodes, nil
+}
+
 func (c *client) QueueSendStepResult(ctx context.Context, id int64, res sdk.StepStatus) error {
 	path := fmt.Sprintf("/queue/workflows/%d/step", id)
 	_, err := c.PostJSON(ctx, path, res, nil)
@@ -150,6 +150,46 @@ func (c *client) WorkflowRunList(projectKey string, workflowName string, offset,
 	return runs, nil
 }
 
+func (c *client) WorkflowRunArchiveList(projectKey string, workflowName string, offset, limit int64) ([]sdk.WorkflowRunArchive, error) {
+	if offset < 0 {
+		offset = 0
+	}
+	if limit == 0 {
+		limit = 50
+	}
+
+	url := fmt.Sprintf("/project/%s/workflows/%s/archives?offset=%d&limit=%d", projectKey, workflowName, offset, limit)
+	archives := []sdk.WorkflowRunArchive{}
+	if _, err := c.GetJSON(context.Background(), url, &archives); err != nil {
+		return nil, err
+	}
+	return archives, nil
+}
+
+func (c *client) WorkflowRunArchiveGet(projectKey string, workflowName string, number int64) (*sdk.WorkflowRunArchiveData, error) {
+	url := fmt.Sprintf("/project/%s/workflows/%s/archives/%d", projectKey, workflowName, number)
+	var data sdk.WorkflowRunArchiveData
+	if _, err := c.GetJSON(context.Background(), url, &data); err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+func (c *client) WorkflowPurgeDryRun(projectKey string, workflowName string, policy *sdk.WorkflowPurgePolicy) ([]sdk.WorkflowRunPurge, error) {
+	url := fmt.Sprintf("/project/%s/workflows/%s/purge/dryrun", projectKey, workflowName)
+	runs := []sdk.WorkflowRunPurge{}
+	if policy == nil {
+		if _, err := c.GetJSON(context.Background(), url, &runs); err != nil {
+			return nil, err
+		}
+		return runs, nil
+	}
+	if _, err := c.PostJSON(context.Background(), url, policy, &runs); err != nil {
+		return nil, err
+	}
+	return runs, nil
+}
+
 func (c *client) WorkflowRunsAndNodesIDs(projectKey string) ([]sdk.WorkflowNodeRunIdentifiers, error) {
 	url := fmt.Sprintf("/project/%s/workflows/runs/nodes/ids", projectKey)
 	var resp []sdk.WorkflowNodeRunIdentifiers
@@ -182,6 +222,38 @@ func (c *client) WorkflowNodeRun(projectKey string, workflowName string, number
 	return &run, nil
 }
 
+func (c *client) WorkflowNodeRunAnalyses(projectKey string, workflowName string, number int64, nodeRunID int64) ([]sdk.WorkflowNodeRunAnalysis, error) {
+	url := fmt.Sprintf("/project/%s/workflows/%s/runs/%d/nodes/%d/analyses", projectKey, workflowName, number, nodeRunID)
+	var analyses []sdk.WorkflowNodeRunAnalysis
+	if _, err := c.GetJSON(context.Background(), url, &analyses); err != nil {
+		return nil, err
+	}
+	return analyses, nil
+}
+
+func (c *client) WorkflowTests(projectKey string, workflowName string, mods ...RequestModifier) (*sdk.WorkflowTestsSummary, error) {
+	url := fmt.Sprintf("/project/%s/workflows/%s/tests", projectKey, workflowName)
+	var summary sdk.WorkflowTestsSummary
+	if _, err := c.GetJSON(context.Background(), url, &summary, mods...); err != nil {
+		return nil, err
+	}
+	return &summary, nil
+}
+
+func (c *client) WorkflowTestCaseHistory(projectKey string, workflowName string, nodeName, suite, testName string, mods ...RequestModifier) (*sdk.TestCaseHistory, error) {
+	url := fmt.Sprintf("/project/%s/workflows/%s/tests/history", projectKey, workflowName)
+	mods = append(mods,
+		WithQueryParameter("node", nodeName),
+		WithQueryParameter("suite", suite),
+		WithQueryParameter("name", testName),
+	)
+	var history sdk.TestCaseHistory
+	if _, err := c.GetJSON(context.Background(), url, &history, mods...); err != nil {
+		return nil, err
+	}
+	return &history, nil
+}
+
 func (c *client) WorkflowRunNumberGet(projectKey string, workflowName string) (*sdk.WorkflowRunNumber, error) {
 	url := fmt.Sprintf("/project/%s/workflows/%s/runs/num", projectKey, workflowName)
 	runNumber := sdk.WorkflowRunNumber{}
@@ -340,24 +412,28 @@ func (c *client) WorkflowNodeStop(projectKey string, workflowName string, number
 	return nodeRun, nil
 }
 
-func (c *client) WorkflowCachePush(projectKey, integrationName, ref string, tarContent io.Reader, size int) error {
+// WorkflowCachePush uploads a cache with given tag, the cache key is sent
+// apart because the tag is an encoded form of the key that can't be guessed.
+func (c *client) WorkflowCachePush(projectKey, integrationName, ref, key string, tarContent io.Reader, size int) error {
 	store := new(sdk.ArtifactsStore)
 	uri := fmt.Sprintf("/project/%s/storage/%s", projectKey, integrationName)
 	_, _ = c.GetJSON(context.Background(), uri, store)
+	params := url.Values{}
+	params.Set("key", key)
 	if store.TemporaryURLSupported {
-		return c.workflowCachePushIndirectUpload(projectKey, integrationName, ref, tarContent, size)
+		return c.workflowCachePushIndirectUpload(projectKey, integrationName, ref, params, tarContent, size)
 	}
-	return c.workflowCachePushDirectUpload(projectKey, integrationName, ref, tarContent)
+	return c.workflowCachePushDirectUpload(projectKey, integrationName, ref, params, tarContent)
 }
 
-func (c *client) workflowCachePushDirectUpload(projectKey, integrationName, ref string, tarContent io.Reader) error {
+func (c *client) workflowCachePushDirectUpload(projectKey, integrationName, ref string, params url.Values, tarContent io.Reader) error {
 	mods := []RequestModifier{
 		(func(r *http.Request) {
 			r.Header.Set("Content-Type", "application/tar")
 		}),
 	}
 
-	uri := fmt.Sprintf("/project/%s/storage/%s/cache/%s", projectKey, integrationName, ref)
+	uri := fmt.Sprintf("/project/%s/storage/%s/cache/%s?%s", projectKey, integrationName, ref, params.Encode())
 	_, _, code, err := c.Stream(context.Background(), "POST", uri, tarContent, true, mods...)
 	if err != nil {
 		return err
@@ -370,9 +446,9 @@ func (c *client) workflowCachePushDirectUpload(projectKey, integrationName, ref
 	return nil
 }
 
-func (c *client) workflowCachePushIndirectUpload(projectKey, integrationName, ref string, tarContent io.Reader, size int) error {
+func (c *client) workflowCachePushIndirectUpload(projectKey, integrationName, ref string, params url.Values, tarContent io.Reader, size int) error {
 	uri := fmt.Sprintf("/project/%s/storage/%s/cache/%s/url", projectKey, integrationName, ref)
-	cacheObj := sdk.Cache{}
+	cacheObj := sdk.Cache{Size: int64(size)}
 	code, err := c.PostJSON(context.Background(), uri, cacheObj, &cacheObj)
 	if err != nil {
 		return err
@@ -382,7 +458,19 @@ func (c *client) workflowCachePushIndirectUpload(projectKey, integrationName, re
 		return fmt.Errorf("HTTP Code %d", code)
 	}
 
-	return c.workflowCachePushIndirectUploadPost(cacheObj.TmpURL, tarContent, size)
+	if err := c.workflowCachePushIndirectUploadPost(cacheObj.TmpURL, tarContent, size); err != nil {
+		return err
+	}
+
+	// Register the uploaded cache
+	code, err = c.PostJSON(context.Background(), uri+"/callback?"+params.Encode(), nil, nil)
+	if err != nil {
+		return err
+	}
+	if code >= 400 {
+		return fmt.Errorf("HTTP Code %d", code)
+	}
+	return nil
 }
 
 func (c *client) workflowCachePushIndirectUploadPost(url string, tarContent io.Reader, size int) error {
@@ -426,6 +514,21 @@ func (c *client) workflowCachePushIndirectUploadPost(url string, tarContent io.R
 	return globalErr
 }
 
+// WorkflowCacheLookup returns the cache for given key, or if not found the most recent cache
+// with a key that starts with one of the restore keys.
+func (c *client) WorkflowCacheLookup(projectKey, integrationName, key string, restoreKeys []string) (*sdk.Cache, error) {
+	params := url.Values{}
+	params.Set("key", key)
+	for _, k := range restoreKeys {
+		params.Add("restoreKey", k)
+	}
+	var cacheObj sdk.Cache
+	if _, err := c.GetJSON(context.Background(), fmt.Sprintf("/project/%s/storage/%s/cache?%s", projectKey, integrationName, params.Encode()), &cacheObj); err != nil {
+		return nil, err
+	}
+	return &cacheObj, nil
+}
+
 func (c *client) WorkflowCachePull(projectKey, integrationName, ref string) (io.Reader, error) {
 	uri := fmt.Sprintf("/project/%s/storage/%s", projectKey, integrationName)
 	store := new(sdk.ArtifactsStore)
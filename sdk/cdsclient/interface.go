@@ -44,9 +44,14 @@ type Admin interface {
 	AdminDatabaseListEncryptedEntities() ([]string, error)
 	AdminDatabaseRollEncryptedEntity(e string) error
 	AdminDatabaseRollAllEncryptedEntities() error
+	AdminDatabaseKeyRotationStatus(serviceType string) (sdk.DatabaseKeyRotation, error)
+	AdminDatabaseKeyRotationStart(serviceType string) (sdk.DatabaseKeyRotation, error)
 	AdminCDSMigrationList() ([]sdk.Migration, error)
 	AdminCDSMigrationCancel(id int64) error
 	AdminCDSMigrationReset(id int64) error
+	AdminAuthGroupSync(consumerType sdk.AuthConsumerType, dryRun bool) (sdk.GroupSyncReport, error)
+	AdminAuditAPICalls(filter sdk.AuditAPICallFilter) ([]sdk.AuditAPICall, error)
+	AdminJobUsages(filter sdk.JobUsageFilter) ([]sdk.JobUsageSummary, error)
 	Features() ([]sdk.Feature, error)
 	FeatureCreate(f sdk.Feature) error
 	FeatureDelete(name string) error
@@ -197,11 +202,17 @@ type PipelineClient interface {
 	PipelineDelete(projectKey, name string) error
 	PipelineCreate(projectKey string, pip *sdk.Pipeline) error
 	PipelineList(projectKey string) ([]sdk.Pipeline, error)
+	PipelinePublish(projectKey, name string, req sdk.SharedPipelinePublishRequest) (*sdk.SharedPipeline, error)
+	PipelineUseShared(projectKey string, req sdk.SharedPipelineUseRequest) (*sdk.Pipeline, error)
+	SharedPipelineList() ([]sdk.SharedPipeline, error)
+	SharedPipelineUsage(groupName, name string) ([]sdk.UsageSharedPipeline, error)
 }
 
 // MaintenanceClient manage maintenance mode on CDS
 type MaintenanceClient interface {
 	Maintenance(enable bool, hooks bool) error
+	MaintenanceDrain(maxWait time.Duration, hooks bool) error
+	MaintenanceStatus() (sdk.MaintenanceStatus, error)
 }
 
 // ProjectClient exposes project related functions
@@ -222,6 +233,8 @@ type ProjectClient interface {
 	ProjectIntegrationDelete(projectKey string, integrationName string) error
 	ProjectRepositoryManagerList(projectKey string) ([]sdk.ProjectVCSServer, error)
 	ProjectRepositoryManagerDelete(projectKey string, repoManagerName string, force bool) error
+	ProjectExport(projectKey, passphrase string, mods ...RequestModifier) ([]byte, error)
+	ProjectImport(projectKey, passphrase string, content io.Reader, dryRun bool, mods ...RequestModifier) (sdk.ProjectBundleImportResult, error)
 }
 
 // ProjectKeysClient exposes project keys related functions
@@ -251,10 +264,12 @@ type QueueClient interface {
 	QueueJobRelease(ctx context.Context, id int64) error
 	QueueJobInfo(ctx context.Context, id int64) (*sdk.WorkflowNodeJobRun, error)
 	QueueJobSendSpawnInfo(ctx context.Context, id int64, in []sdk.SpawnInfo) error
-	QueueSendCoverage(ctx context.Context, id int64, report coverage.Report) error
+	QueueSendCoverage(ctx context.Context, id int64, report coverage.Report) (*sdk.WorkflowNodeRunCoverage, error)
 	QueueSendUnitTests(ctx context.Context, id int64, report venom.Tests) error
 	QueueSendLogs(ctx context.Context, id int64, log sdk.Log) error
 	QueueSendVulnerability(ctx context.Context, id int64, report sdk.VulnerabilityWorkerReport) error
+	QueueSendAnalysis(ctx context.Context, id int64, report sdk.AnalysisWorkerReport) (*sdk.WorkflowNodeRunAnalysis, error)
+	QueueAddChildPipelines(ctx context.Context, id int64, req sdk.ChildPipelinesRequest) ([]sdk.Node, error)
 	QueueSendStepResult(ctx context.Context, id int64, res sdk.StepStatus) error
 	QueueSendResult(ctx context.Context, id int64, res sdk.Result) error
 	QueueArtifactUpload(ctx context.Context, projectKey, integrationName string, nodeJobRunID int64, tag, filePath string) (bool, time.Duration, error)
@@ -295,6 +310,7 @@ type WorkerClient interface {
 
 // HookClient exposes functions used for hooks services
 type HookClient interface {
+	HookChangedFiles(uuid, base, head string) ([]string, error)
 	PollVCSEvents(uuid string, workflowID int64, vcsServer string, timestamp int64) (events sdk.RepositoryEvents, interval time.Duration, err error)
 	VCSConfiguration() (map[string]sdk.VCSConfiguration, error)
 }
@@ -321,6 +337,9 @@ type WorkflowClient interface {
 	WorkflowRunResync(projectKey string, workflowName string, number int64) (*sdk.WorkflowRun, error)
 	WorkflowRunSearch(projectKey string, offset, limit int64, filter ...Filter) ([]sdk.WorkflowRun, error)
 	WorkflowRunList(projectKey string, workflowName string, offset, limit int64) ([]sdk.WorkflowRun, error)
+	WorkflowRunArchiveList(projectKey string, workflowName string, offset, limit int64) ([]sdk.WorkflowRunArchive, error)
+	WorkflowRunArchiveGet(projectKey string, workflowName string, number int64) (*sdk.WorkflowRunArchiveData, error)
+	WorkflowPurgeDryRun(projectKey string, workflowName string, policy *sdk.WorkflowPurgePolicy) ([]sdk.WorkflowRunPurge, error)
 	WorkflowRunArtifacts(projectKey string, name string, number int64) ([]sdk.WorkflowNodeRunArtifact, error)
 	WorkflowRunFromHook(projectKey string, workflowName string, hook sdk.WorkflowNodeRunHookEvent) (*sdk.WorkflowRun, error)
 	WorkflowRunFromManual(projectKey string, workflowName string, manual sdk.WorkflowNodeRunManual, number, fromNodeID int64) (*sdk.WorkflowRun, error)
@@ -329,13 +348,17 @@ type WorkflowClient interface {
 	WorkflowStop(projectKey string, workflowName string, number int64) (*sdk.WorkflowRun, error)
 	WorkflowNodeStop(projectKey string, workflowName string, number, fromNodeID int64) (*sdk.WorkflowNodeRun, error)
 	WorkflowNodeRun(projectKey string, name string, number int64, nodeRunID int64) (*sdk.WorkflowNodeRun, error)
+	WorkflowNodeRunAnalyses(projectKey string, name string, number int64, nodeRunID int64) ([]sdk.WorkflowNodeRunAnalysis, error)
+	WorkflowTests(projectKey string, name string, mods ...RequestModifier) (*sdk.WorkflowTestsSummary, error)
+	WorkflowTestCaseHistory(projectKey string, name string, nodeName, suite, testName string, mods ...RequestModifier) (*sdk.TestCaseHistory, error)
 	WorkflowNodeRunArtifactDownload(projectKey string, name string, a sdk.WorkflowNodeRunArtifact, w io.Writer) error
 	WorkflowNodeRunJobStep(projectKey string, workflowName string, number int64, nodeRunID, job int64, step int) (*sdk.BuildState, error)
 	WorkflowNodeRunJobServiceLog(projectKey string, workflowName string, number int64, nodeRunID, job int64) ([]sdk.ServiceLog, error)
 	WorkflowNodeRunRelease(projectKey string, workflowName string, runNumber int64, nodeRunID int64, release sdk.WorkflowNodeRunRelease) error
 	WorkflowAllHooksList() ([]sdk.NodeHook, error)
-	WorkflowCachePush(projectKey, integrationName, ref string, tarContent io.Reader, size int) error
+	WorkflowCachePush(projectKey, integrationName, ref, key string, tarContent io.Reader, size int) error
 	WorkflowCachePull(projectKey, integrationName, ref string) (io.Reader, error)
+	WorkflowCacheLookup(projectKey, integrationName, key string, restoreKeys []string) (*sdk.Cache, error)
 	WorkflowTransformAsCode(projectKey, workflowName, branch, message string) (*sdk.Operation, error)
 }
 
@@ -403,8 +426,9 @@ type WorkerInterface interface {
 	ServiceClient
 	WorkerClient
 	WorkflowRunArtifacts(projectKey string, name string, number int64) ([]sdk.WorkflowNodeRunArtifact, error)
-	WorkflowCachePush(projectKey, integrationName, ref string, tarContent io.Reader, size int) error
+	WorkflowCachePush(projectKey, integrationName, ref, key string, tarContent io.Reader, size int) error
 	WorkflowCachePull(projectKey, integrationName, ref string) (io.Reader, error)
+	WorkflowCacheLookup(projectKey, integrationName, key string, restoreKeys []string) (*sdk.Cache, error)
 	WorkflowRunSearch(projectKey string, offset, limit int64, filter ...Filter) ([]sdk.WorkflowRun, error)
 	WorkflowNodeRunArtifactDownload(projectKey string, name string, a sdk.WorkflowNodeRunArtifact, w io.Writer) error
 	WorkflowNodeRunRelease(projectKey string, workflowName string, runNumber int64, nodeRunID int64, release sdk.WorkflowNodeRunRelease) error
@@ -563,6 +587,8 @@ type AuthClient interface {
 	AuthConsumerDelete(username, id string) error
 	AuthConsumerRegen(username, id string) (sdk.AuthConsumerCreateResponse, error)
 	AuthConsumerCreateForUser(username string, request sdk.AuthConsumer) (sdk.AuthConsumerCreateResponse, error)
+	AuthConsumerSetAllowedCIDRs(username, id string, cidrs []string) (sdk.AuthConsumer, error)
+	AuthConsumerIPRejectionList(username, id string) ([]sdk.AuthConsumerIPRejection, error)
 	AuthSessionListByUser(username string) (sdk.AuthSessions, error)
 	AuthSessionDelete(username, id string) error
 	AuthMe() (sdk.AuthCurrentConsumerResponse, error)
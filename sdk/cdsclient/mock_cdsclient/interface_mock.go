@@ -343,6 +343,36 @@ func (mr *MockAdminMockRecorder) AdminDatabaseRollAllEncryptedEntities() *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdminDatabaseRollAllEncryptedEntities", reflect.TypeOf((*MockAdmin)(nil).AdminDatabaseRollAllEncryptedEntities))
 }
 
+// AdminDatabaseKeyRotationStatus mocks base method
+func (m *MockAdmin) AdminDatabaseKeyRotationStatus(serviceType string) (sdk.DatabaseKeyRotation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AdminDatabaseKeyRotationStatus", serviceType)
+	ret0, _ := ret[0].(sdk.DatabaseKeyRotation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AdminDatabaseKeyRotationStatus indicates an expected call of AdminDatabaseKeyRotationStatus
+func (mr *MockAdminMockRecorder) AdminDatabaseKeyRotationStatus(serviceType interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdminDatabaseKeyRotationStatus", reflect.TypeOf((*MockAdmin)(nil).AdminDatabaseKeyRotationStatus), serviceType)
+}
+
+// AdminDatabaseKeyRotationStart mocks base method
+func (m *MockAdmin) AdminDatabaseKeyRotationStart(serviceType string) (sdk.DatabaseKeyRotation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AdminDatabaseKeyRotationStart", serviceType)
+	ret0, _ := ret[0].(sdk.DatabaseKeyRotation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AdminDatabaseKeyRotationStart indicates an expected call of AdminDatabaseKeyRotationStart
+func (mr *MockAdminMockRecorder) AdminDatabaseKeyRotationStart(serviceType interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdminDatabaseKeyRotationStart", reflect.TypeOf((*MockAdmin)(nil).AdminDatabaseKeyRotationStart), serviceType)
+}
+
 // AdminCDSMigrationList mocks base method
 func (m *MockAdmin) AdminCDSMigrationList() ([]sdk.Migration, error) {
 	m.ctrl.T.Helper()
@@ -386,6 +416,51 @@ func (mr *MockAdminMockRecorder) AdminCDSMigrationReset(id interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdminCDSMigrationReset", reflect.TypeOf((*MockAdmin)(nil).AdminCDSMigrationReset), id)
 }
 
+// AdminAuthGroupSync mocks base method
+func (m *MockAdmin) AdminAuthGroupSync(consumerType sdk.AuthConsumerType, dryRun bool) (sdk.GroupSyncReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AdminAuthGroupSync", consumerType, dryRun)
+	ret0, _ := ret[0].(sdk.GroupSyncReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AdminAuthGroupSync indicates an expected call of AdminAuthGroupSync
+func (mr *MockAdminMockRecorder) AdminAuthGroupSync(consumerType, dryRun interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdminAuthGroupSync", reflect.TypeOf((*MockAdmin)(nil).AdminAuthGroupSync), consumerType, dryRun)
+}
+
+// AdminAuditAPICalls mocks base method
+func (m *MockAdmin) AdminAuditAPICalls(filter sdk.AuditAPICallFilter) ([]sdk.AuditAPICall, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AdminAuditAPICalls", filter)
+	ret0, _ := ret[0].([]sdk.AuditAPICall)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AdminAuditAPICalls indicates an expected call of AdminAuditAPICalls
+func (mr *MockAdminMockRecorder) AdminAuditAPICalls(filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdminAuditAPICalls", reflect.TypeOf((*MockAdmin)(nil).AdminAuditAPICalls), filter)
+}
+
+// AdminJobUsages mocks base method
+func (m *MockAdmin) AdminJobUsages(filter sdk.JobUsageFilter) ([]sdk.JobUsageSummary, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AdminJobUsages", filter)
+	ret0, _ := ret[0].([]sdk.JobUsageSummary)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AdminJobUsages indicates an expected call of AdminJobUsages
+func (mr *MockAdminMockRecorder) AdminJobUsages(filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdminJobUsages", reflect.TypeOf((*MockAdmin)(nil).AdminJobUsages), filter)
+}
+
 // Features mocks base method
 func (m *MockAdmin) Features() ([]sdk.Feature, error) {
 	m.ctrl.T.Helper()
@@ -2337,6 +2412,66 @@ func (mr *MockPipelineClientMockRecorder) PipelineList(projectKey interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PipelineList", reflect.TypeOf((*MockPipelineClient)(nil).PipelineList), projectKey)
 }
 
+// PipelinePublish mocks base method
+func (m *MockPipelineClient) PipelinePublish(projectKey, name string, req sdk.SharedPipelinePublishRequest) (*sdk.SharedPipeline, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PipelinePublish", projectKey, name, req)
+	ret0, _ := ret[0].(*sdk.SharedPipeline)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PipelinePublish indicates an expected call of PipelinePublish
+func (mr *MockPipelineClientMockRecorder) PipelinePublish(projectKey, name, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PipelinePublish", reflect.TypeOf((*MockPipelineClient)(nil).PipelinePublish), projectKey, name, req)
+}
+
+// PipelineUseShared mocks base method
+func (m *MockPipelineClient) PipelineUseShared(projectKey string, req sdk.SharedPipelineUseRequest) (*sdk.Pipeline, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PipelineUseShared", projectKey, req)
+	ret0, _ := ret[0].(*sdk.Pipeline)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PipelineUseShared indicates an expected call of PipelineUseShared
+func (mr *MockPipelineClientMockRecorder) PipelineUseShared(projectKey, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PipelineUseShared", reflect.TypeOf((*MockPipelineClient)(nil).PipelineUseShared), projectKey, req)
+}
+
+// SharedPipelineList mocks base method
+func (m *MockPipelineClient) SharedPipelineList() ([]sdk.SharedPipeline, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SharedPipelineList")
+	ret0, _ := ret[0].([]sdk.SharedPipeline)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SharedPipelineList indicates an expected call of SharedPipelineList
+func (mr *MockPipelineClientMockRecorder) SharedPipelineList() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SharedPipelineList", reflect.TypeOf((*MockPipelineClient)(nil).SharedPipelineList))
+}
+
+// SharedPipelineUsage mocks base method
+func (m *MockPipelineClient) SharedPipelineUsage(groupName, name string) ([]sdk.UsageSharedPipeline, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SharedPipelineUsage", groupName, name)
+	ret0, _ := ret[0].([]sdk.UsageSharedPipeline)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SharedPipelineUsage indicates an expected call of SharedPipelineUsage
+func (mr *MockPipelineClientMockRecorder) SharedPipelineUsage(groupName, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SharedPipelineUsage", reflect.TypeOf((*MockPipelineClient)(nil).SharedPipelineUsage), groupName, name)
+}
+
 // MockMaintenanceClient is a mock of MaintenanceClient interface
 type MockMaintenanceClient struct {
 	ctrl     *gomock.Controller
@@ -2374,6 +2509,35 @@ func (mr *MockMaintenanceClientMockRecorder) Maintenance(enable, hooks interface
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Maintenance", reflect.TypeOf((*MockMaintenanceClient)(nil).Maintenance), enable, hooks)
 }
 
+// MaintenanceDrain mocks base method
+func (m *MockMaintenanceClient) MaintenanceDrain(maxWait time.Duration, hooks bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MaintenanceDrain", maxWait, hooks)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MaintenanceDrain indicates an expected call of MaintenanceDrain
+func (mr *MockMaintenanceClientMockRecorder) MaintenanceDrain(maxWait, hooks interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MaintenanceDrain", reflect.TypeOf((*MockMaintenanceClient)(nil).MaintenanceDrain), maxWait, hooks)
+}
+
+// MaintenanceStatus mocks base method
+func (m *MockMaintenanceClient) MaintenanceStatus() (sdk.MaintenanceStatus, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MaintenanceStatus")
+	ret0, _ := ret[0].(sdk.MaintenanceStatus)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MaintenanceStatus indicates an expected call of MaintenanceStatus
+func (mr *MockMaintenanceClientMockRecorder) MaintenanceStatus() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MaintenanceStatus", reflect.TypeOf((*MockMaintenanceClient)(nil).MaintenanceStatus))
+}
+
 // MockProjectClient is a mock of ProjectClient interface
 type MockProjectClient struct {
 	ctrl     *gomock.Controller
@@ -2750,6 +2914,46 @@ func (mr *MockProjectClientMockRecorder) ProjectRepositoryManagerDelete(projectK
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProjectRepositoryManagerDelete", reflect.TypeOf((*MockProjectClient)(nil).ProjectRepositoryManagerDelete), projectKey, repoManagerName, force)
 }
 
+// ProjectExport mocks base method
+func (m *MockProjectClient) ProjectExport(projectKey, passphrase string, mods ...cdsclient.RequestModifier) ([]byte, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{projectKey, passphrase}
+	for _, a := range mods {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ProjectExport", varargs...)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ProjectExport indicates an expected call of ProjectExport
+func (mr *MockProjectClientMockRecorder) ProjectExport(projectKey, passphrase interface{}, mods ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{projectKey, passphrase}, mods...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProjectExport", reflect.TypeOf((*MockProjectClient)(nil).ProjectExport), varargs...)
+}
+
+// ProjectImport mocks base method
+func (m *MockProjectClient) ProjectImport(projectKey, passphrase string, content io.Reader, dryRun bool, mods ...cdsclient.RequestModifier) (sdk.ProjectBundleImportResult, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{projectKey, passphrase, content, dryRun}
+	for _, a := range mods {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ProjectImport", varargs...)
+	ret0, _ := ret[0].(sdk.ProjectBundleImportResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ProjectImport indicates an expected call of ProjectImport
+func (mr *MockProjectClientMockRecorder) ProjectImport(projectKey, passphrase, content, dryRun interface{}, mods ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{projectKey, passphrase, content, dryRun}, mods...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProjectImport", reflect.TypeOf((*MockProjectClient)(nil).ProjectImport), varargs...)
+}
+
 // MockProjectKeysClient is a mock of ProjectKeysClient interface
 type MockProjectKeysClient struct {
 	ctrl     *gomock.Controller
@@ -3071,11 +3275,12 @@ func (mr *MockQueueClientMockRecorder) QueueJobSendSpawnInfo(ctx, id, in interfa
 }
 
 // QueueSendCoverage mocks base method
-func (m *MockQueueClient) QueueSendCoverage(ctx context.Context, id int64, report coverage.Report) error {
+func (m *MockQueueClient) QueueSendCoverage(ctx context.Context, id int64, report coverage.Report) (*sdk.WorkflowNodeRunCoverage, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "QueueSendCoverage", ctx, id, report)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret0, _ := ret[0].(*sdk.WorkflowNodeRunCoverage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // QueueSendCoverage indicates an expected call of QueueSendCoverage
@@ -3126,6 +3331,36 @@ func (mr *MockQueueClientMockRecorder) QueueSendVulnerability(ctx, id, report in
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueueSendVulnerability", reflect.TypeOf((*MockQueueClient)(nil).QueueSendVulnerability), ctx, id, report)
 }
 
+// QueueSendAnalysis mocks base method
+func (m *MockQueueClient) QueueSendAnalysis(ctx context.Context, id int64, report sdk.AnalysisWorkerReport) (*sdk.WorkflowNodeRunAnalysis, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QueueSendAnalysis", ctx, id, report)
+	ret0, _ := ret[0].(*sdk.WorkflowNodeRunAnalysis)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QueueSendAnalysis indicates an expected call of QueueSendAnalysis
+func (mr *MockQueueClientMockRecorder) QueueSendAnalysis(ctx, id, report interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueueSendAnalysis", reflect.TypeOf((*MockQueueClient)(nil).QueueSendAnalysis), ctx, id, report)
+}
+
+// QueueAddChildPipelines mocks base method
+func (m *MockQueueClient) QueueAddChildPipelines(ctx context.Context, id int64, req sdk.ChildPipelinesRequest) ([]sdk.Node, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QueueAddChildPipelines", ctx, id, req)
+	ret0, _ := ret[0].([]sdk.Node)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QueueAddChildPipelines indicates an expected call of QueueAddChildPipelines
+func (mr *MockQueueClientMockRecorder) QueueAddChildPipelines(ctx, id, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueueAddChildPipelines", reflect.TypeOf((*MockQueueClient)(nil).QueueAddChildPipelines), ctx, id, req)
+}
+
 // QueueSendStepResult mocks base method
 func (m *MockQueueClient) QueueSendStepResult(ctx context.Context, id int64, res sdk.StepStatus) error {
 	m.ctrl.T.Helper()
@@ -3612,6 +3847,21 @@ func (m *MockHookClient) EXPECT() *MockHookClientMockRecorder {
 	return m.recorder
 }
 
+// HookChangedFiles mocks base method
+func (m *MockHookClient) HookChangedFiles(uuid, base, head string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HookChangedFiles", uuid, base, head)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HookChangedFiles indicates an expected call of HookChangedFiles
+func (mr *MockHookClientMockRecorder) HookChangedFiles(uuid, base, head interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HookChangedFiles", reflect.TypeOf((*MockHookClient)(nil).HookChangedFiles), uuid, base, head)
+}
+
 // PollVCSEvents mocks base method
 func (m *MockHookClient) PollVCSEvents(uuid string, workflowID int64, vcsServer string, timestamp int64) (sdk.RepositoryEvents, time.Duration, error) {
 	m.ctrl.T.Helper()
@@ -3941,6 +4191,51 @@ func (mr *MockWorkflowClientMockRecorder) WorkflowRunList(projectKey, workflowNa
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WorkflowRunList", reflect.TypeOf((*MockWorkflowClient)(nil).WorkflowRunList), projectKey, workflowName, offset, limit)
 }
 
+// WorkflowRunArchiveList mocks base method
+func (m *MockWorkflowClient) WorkflowRunArchiveList(projectKey, workflowName string, offset, limit int64) ([]sdk.WorkflowRunArchive, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WorkflowRunArchiveList", projectKey, workflowName, offset, limit)
+	ret0, _ := ret[0].([]sdk.WorkflowRunArchive)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WorkflowRunArchiveList indicates an expected call of WorkflowRunArchiveList
+func (mr *MockWorkflowClientMockRecorder) WorkflowRunArchiveList(projectKey, workflowName, offset, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WorkflowRunArchiveList", reflect.TypeOf((*MockWorkflowClient)(nil).WorkflowRunArchiveList), projectKey, workflowName, offset, limit)
+}
+
+// WorkflowRunArchiveGet mocks base method
+func (m *MockWorkflowClient) WorkflowRunArchiveGet(projectKey, workflowName string, number int64) (*sdk.WorkflowRunArchiveData, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WorkflowRunArchiveGet", projectKey, workflowName, number)
+	ret0, _ := ret[0].(*sdk.WorkflowRunArchiveData)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WorkflowRunArchiveGet indicates an expected call of WorkflowRunArchiveGet
+func (mr *MockWorkflowClientMockRecorder) WorkflowRunArchiveGet(projectKey, workflowName, number interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WorkflowRunArchiveGet", reflect.TypeOf((*MockWorkflowClient)(nil).WorkflowRunArchiveGet), projectKey, workflowName, number)
+}
+
+// WorkflowPurgeDryRun mocks base method
+func (m *MockWorkflowClient) WorkflowPurgeDryRun(projectKey, workflowName string, policy *sdk.WorkflowPurgePolicy) ([]sdk.WorkflowRunPurge, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WorkflowPurgeDryRun", projectKey, workflowName, policy)
+	ret0, _ := ret[0].([]sdk.WorkflowRunPurge)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WorkflowPurgeDryRun indicates an expected call of WorkflowPurgeDryRun
+func (mr *MockWorkflowClientMockRecorder) WorkflowPurgeDryRun(projectKey, workflowName, policy interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WorkflowPurgeDryRun", reflect.TypeOf((*MockWorkflowClient)(nil).WorkflowPurgeDryRun), projectKey, workflowName, policy)
+}
+
 // WorkflowRunArtifacts mocks base method
 func (m *MockWorkflowClient) WorkflowRunArtifacts(projectKey, name string, number int64) ([]sdk.WorkflowNodeRunArtifact, error) {
 	m.ctrl.T.Helper()
@@ -4060,6 +4355,61 @@ func (mr *MockWorkflowClientMockRecorder) WorkflowNodeRun(projectKey, name, numb
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WorkflowNodeRun", reflect.TypeOf((*MockWorkflowClient)(nil).WorkflowNodeRun), projectKey, name, number, nodeRunID)
 }
 
+// WorkflowNodeRunAnalyses mocks base method
+func (m *MockWorkflowClient) WorkflowNodeRunAnalyses(projectKey, name string, number, nodeRunID int64) ([]sdk.WorkflowNodeRunAnalysis, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WorkflowNodeRunAnalyses", projectKey, name, number, nodeRunID)
+	ret0, _ := ret[0].([]sdk.WorkflowNodeRunAnalysis)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WorkflowNodeRunAnalyses indicates an expected call of WorkflowNodeRunAnalyses
+func (mr *MockWorkflowClientMockRecorder) WorkflowNodeRunAnalyses(projectKey, name, number, nodeRunID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WorkflowNodeRunAnalyses", reflect.TypeOf((*MockWorkflowClient)(nil).WorkflowNodeRunAnalyses), projectKey, name, number, nodeRunID)
+}
+
+// WorkflowTests mocks base method
+func (m *MockWorkflowClient) WorkflowTests(projectKey, name string, mods ...cdsclient.RequestModifier) (*sdk.WorkflowTestsSummary, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{projectKey, name}
+	for _, a := range mods {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "WorkflowTests", varargs...)
+	ret0, _ := ret[0].(*sdk.WorkflowTestsSummary)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WorkflowTests indicates an expected call of WorkflowTests
+func (mr *MockWorkflowClientMockRecorder) WorkflowTests(projectKey, name interface{}, mods ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{projectKey, name}, mods...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WorkflowTests", reflect.TypeOf((*MockWorkflowClient)(nil).WorkflowTests), varargs...)
+}
+
+// WorkflowTestCaseHistory mocks base method
+func (m *MockWorkflowClient) WorkflowTestCaseHistory(projectKey, name, nodeName, suite, testName string, mods ...cdsclient.RequestModifier) (*sdk.TestCaseHistory, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{projectKey, name, nodeName, suite, testName}
+	for _, a := range mods {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "WorkflowTestCaseHistory", varargs...)
+	ret0, _ := ret[0].(*sdk.TestCaseHistory)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WorkflowTestCaseHistory indicates an expected call of WorkflowTestCaseHistory
+func (mr *MockWorkflowClientMockRecorder) WorkflowTestCaseHistory(projectKey, name, nodeName, suite, testName interface{}, mods ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{projectKey, name, nodeName, suite, testName}, mods...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WorkflowTestCaseHistory", reflect.TypeOf((*MockWorkflowClient)(nil).WorkflowTestCaseHistory), varargs...)
+}
+
 // WorkflowNodeRunArtifactDownload mocks base method
 func (m *MockWorkflowClient) WorkflowNodeRunArtifactDownload(projectKey, name string, a sdk.WorkflowNodeRunArtifact, w io.Writer) error {
 	m.ctrl.T.Helper()
@@ -4134,17 +4484,17 @@ func (mr *MockWorkflowClientMockRecorder) WorkflowAllHooksList() *gomock.Call {
 }
 
 // WorkflowCachePush mocks base method
-func (m *MockWorkflowClient) WorkflowCachePush(projectKey, integrationName, ref string, tarContent io.Reader, size int) error {
+func (m *MockWorkflowClient) WorkflowCachePush(projectKey, integrationName, ref, key string, tarContent io.Reader, size int) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "WorkflowCachePush", projectKey, integrationName, ref, tarContent, size)
+	ret := m.ctrl.Call(m, "WorkflowCachePush", projectKey, integrationName, ref, key, tarContent, size)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // WorkflowCachePush indicates an expected call of WorkflowCachePush
-func (mr *MockWorkflowClientMockRecorder) WorkflowCachePush(projectKey, integrationName, ref, tarContent, size interface{}) *gomock.Call {
+func (mr *MockWorkflowClientMockRecorder) WorkflowCachePush(projectKey, integrationName, ref, key, tarContent, size interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WorkflowCachePush", reflect.TypeOf((*MockWorkflowClient)(nil).WorkflowCachePush), projectKey, integrationName, ref, tarContent, size)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WorkflowCachePush", reflect.TypeOf((*MockWorkflowClient)(nil).WorkflowCachePush), projectKey, integrationName, ref, key, tarContent, size)
 }
 
 // WorkflowCachePull mocks base method
@@ -4162,6 +4512,21 @@ func (mr *MockWorkflowClientMockRecorder) WorkflowCachePull(projectKey, integrat
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WorkflowCachePull", reflect.TypeOf((*MockWorkflowClient)(nil).WorkflowCachePull), projectKey, integrationName, ref)
 }
 
+// WorkflowCacheLookup mocks base method
+func (m *MockWorkflowClient) WorkflowCacheLookup(projectKey, integrationName, key string, restoreKeys []string) (*sdk.Cache, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WorkflowCacheLookup", projectKey, integrationName, key, restoreKeys)
+	ret0, _ := ret[0].(*sdk.Cache)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WorkflowCacheLookup indicates an expected call of WorkflowCacheLookup
+func (mr *MockWorkflowClientMockRecorder) WorkflowCacheLookup(projectKey, integrationName, key, restoreKeys interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WorkflowCacheLookup", reflect.TypeOf((*MockWorkflowClient)(nil).WorkflowCacheLookup), projectKey, integrationName, key, restoreKeys)
+}
+
 // WorkflowTransformAsCode mocks base method
 func (m *MockWorkflowClient) WorkflowTransformAsCode(projectKey, workflowName, branch, message string) (*sdk.Operation, error) {
 	m.ctrl.T.Helper()
@@ -4705,6 +5070,36 @@ func (mr *MockInterfaceMockRecorder) AuthConsumerCreateForUser(username, request
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AuthConsumerCreateForUser", reflect.TypeOf((*MockInterface)(nil).AuthConsumerCreateForUser), username, request)
 }
 
+// AuthConsumerSetAllowedCIDRs mocks base method
+func (m *MockInterface) AuthConsumerSetAllowedCIDRs(username, id string, cidrs []string) (sdk.AuthConsumer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AuthConsumerSetAllowedCIDRs", username, id, cidrs)
+	ret0, _ := ret[0].(sdk.AuthConsumer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AuthConsumerSetAllowedCIDRs indicates an expected call of AuthConsumerSetAllowedCIDRs
+func (mr *MockInterfaceMockRecorder) AuthConsumerSetAllowedCIDRs(username, id, cidrs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AuthConsumerSetAllowedCIDRs", reflect.TypeOf((*MockInterface)(nil).AuthConsumerSetAllowedCIDRs), username, id, cidrs)
+}
+
+// AuthConsumerIPRejectionList mocks base method
+func (m *MockInterface) AuthConsumerIPRejectionList(username, id string) ([]sdk.AuthConsumerIPRejection, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AuthConsumerIPRejectionList", username, id)
+	ret0, _ := ret[0].([]sdk.AuthConsumerIPRejection)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AuthConsumerIPRejectionList indicates an expected call of AuthConsumerIPRejectionList
+func (mr *MockInterfaceMockRecorder) AuthConsumerIPRejectionList(username, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AuthConsumerIPRejectionList", reflect.TypeOf((*MockInterface)(nil).AuthConsumerIPRejectionList), username, id)
+}
+
 // AuthSessionListByUser mocks base method
 func (m *MockInterface) AuthSessionListByUser(username string) (sdk.AuthSessions, error) {
 	m.ctrl.T.Helper()
@@ -5021,6 +5416,36 @@ func (mr *MockInterfaceMockRecorder) AdminDatabaseRollAllEncryptedEntities() *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdminDatabaseRollAllEncryptedEntities", reflect.TypeOf((*MockInterface)(nil).AdminDatabaseRollAllEncryptedEntities))
 }
 
+// AdminDatabaseKeyRotationStatus mocks base method
+func (m *MockInterface) AdminDatabaseKeyRotationStatus(serviceType string) (sdk.DatabaseKeyRotation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AdminDatabaseKeyRotationStatus", serviceType)
+	ret0, _ := ret[0].(sdk.DatabaseKeyRotation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AdminDatabaseKeyRotationStatus indicates an expected call of AdminDatabaseKeyRotationStatus
+func (mr *MockInterfaceMockRecorder) AdminDatabaseKeyRotationStatus(serviceType interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdminDatabaseKeyRotationStatus", reflect.TypeOf((*MockInterface)(nil).AdminDatabaseKeyRotationStatus), serviceType)
+}
+
+// AdminDatabaseKeyRotationStart mocks base method
+func (m *MockInterface) AdminDatabaseKeyRotationStart(serviceType string) (sdk.DatabaseKeyRotation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AdminDatabaseKeyRotationStart", serviceType)
+	ret0, _ := ret[0].(sdk.DatabaseKeyRotation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AdminDatabaseKeyRotationStart indicates an expected call of AdminDatabaseKeyRotationStart
+func (mr *MockInterfaceMockRecorder) AdminDatabaseKeyRotationStart(serviceType interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdminDatabaseKeyRotationStart", reflect.TypeOf((*MockInterface)(nil).AdminDatabaseKeyRotationStart), serviceType)
+}
+
 // AdminCDSMigrationList mocks base method
 func (m *MockInterface) AdminCDSMigrationList() ([]sdk.Migration, error) {
 	m.ctrl.T.Helper()
@@ -5064,6 +5489,51 @@ func (mr *MockInterfaceMockRecorder) AdminCDSMigrationReset(id interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdminCDSMigrationReset", reflect.TypeOf((*MockInterface)(nil).AdminCDSMigrationReset), id)
 }
 
+// AdminAuthGroupSync mocks base method
+func (m *MockInterface) AdminAuthGroupSync(consumerType sdk.AuthConsumerType, dryRun bool) (sdk.GroupSyncReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AdminAuthGroupSync", consumerType, dryRun)
+	ret0, _ := ret[0].(sdk.GroupSyncReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AdminAuthGroupSync indicates an expected call of AdminAuthGroupSync
+func (mr *MockInterfaceMockRecorder) AdminAuthGroupSync(consumerType, dryRun interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdminAuthGroupSync", reflect.TypeOf((*MockInterface)(nil).AdminAuthGroupSync), consumerType, dryRun)
+}
+
+// AdminAuditAPICalls mocks base method
+func (m *MockInterface) AdminAuditAPICalls(filter sdk.AuditAPICallFilter) ([]sdk.AuditAPICall, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AdminAuditAPICalls", filter)
+	ret0, _ := ret[0].([]sdk.AuditAPICall)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AdminAuditAPICalls indicates an expected call of AdminAuditAPICalls
+func (mr *MockInterfaceMockRecorder) AdminAuditAPICalls(filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdminAuditAPICalls", reflect.TypeOf((*MockInterface)(nil).AdminAuditAPICalls), filter)
+}
+
+// AdminJobUsages mocks base method
+func (m *MockInterface) AdminJobUsages(filter sdk.JobUsageFilter) ([]sdk.JobUsageSummary, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AdminJobUsages", filter)
+	ret0, _ := ret[0].([]sdk.JobUsageSummary)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AdminJobUsages indicates an expected call of AdminJobUsages
+func (mr *MockInterfaceMockRecorder) AdminJobUsages(filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdminJobUsages", reflect.TypeOf((*MockInterface)(nil).AdminJobUsages), filter)
+}
+
 // Features mocks base method
 func (m *MockInterface) Features() ([]sdk.Feature, error) {
 	m.ctrl.T.Helper()
@@ -6376,6 +6846,35 @@ func (mr *MockInterfaceMockRecorder) Maintenance(enable, hooks interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Maintenance", reflect.TypeOf((*MockInterface)(nil).Maintenance), enable, hooks)
 }
 
+// MaintenanceDrain mocks base method
+func (m *MockInterface) MaintenanceDrain(maxWait time.Duration, hooks bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MaintenanceDrain", maxWait, hooks)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MaintenanceDrain indicates an expected call of MaintenanceDrain
+func (mr *MockInterfaceMockRecorder) MaintenanceDrain(maxWait, hooks interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MaintenanceDrain", reflect.TypeOf((*MockInterface)(nil).MaintenanceDrain), maxWait, hooks)
+}
+
+// MaintenanceStatus mocks base method
+func (m *MockInterface) MaintenanceStatus() (sdk.MaintenanceStatus, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MaintenanceStatus")
+	ret0, _ := ret[0].(sdk.MaintenanceStatus)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MaintenanceStatus indicates an expected call of MaintenanceStatus
+func (mr *MockInterfaceMockRecorder) MaintenanceStatus() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MaintenanceStatus", reflect.TypeOf((*MockInterface)(nil).MaintenanceStatus))
+}
+
 // PipelineGet mocks base method
 func (m *MockInterface) PipelineGet(projectKey, name string, mods ...cdsclient.RequestModifier) (*sdk.Pipeline, error) {
 	m.ctrl.T.Helper()
@@ -6439,6 +6938,66 @@ func (mr *MockInterfaceMockRecorder) PipelineList(projectKey interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PipelineList", reflect.TypeOf((*MockInterface)(nil).PipelineList), projectKey)
 }
 
+// PipelinePublish mocks base method
+func (m *MockInterface) PipelinePublish(projectKey, name string, req sdk.SharedPipelinePublishRequest) (*sdk.SharedPipeline, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PipelinePublish", projectKey, name, req)
+	ret0, _ := ret[0].(*sdk.SharedPipeline)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PipelinePublish indicates an expected call of PipelinePublish
+func (mr *MockInterfaceMockRecorder) PipelinePublish(projectKey, name, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PipelinePublish", reflect.TypeOf((*MockInterface)(nil).PipelinePublish), projectKey, name, req)
+}
+
+// PipelineUseShared mocks base method
+func (m *MockInterface) PipelineUseShared(projectKey string, req sdk.SharedPipelineUseRequest) (*sdk.Pipeline, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PipelineUseShared", projectKey, req)
+	ret0, _ := ret[0].(*sdk.Pipeline)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PipelineUseShared indicates an expected call of PipelineUseShared
+func (mr *MockInterfaceMockRecorder) PipelineUseShared(projectKey, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PipelineUseShared", reflect.TypeOf((*MockInterface)(nil).PipelineUseShared), projectKey, req)
+}
+
+// SharedPipelineList mocks base method
+func (m *MockInterface) SharedPipelineList() ([]sdk.SharedPipeline, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SharedPipelineList")
+	ret0, _ := ret[0].([]sdk.SharedPipeline)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SharedPipelineList indicates an expected call of SharedPipelineList
+func (mr *MockInterfaceMockRecorder) SharedPipelineList() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SharedPipelineList", reflect.TypeOf((*MockInterface)(nil).SharedPipelineList))
+}
+
+// SharedPipelineUsage mocks base method
+func (m *MockInterface) SharedPipelineUsage(groupName, name string) ([]sdk.UsageSharedPipeline, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SharedPipelineUsage", groupName, name)
+	ret0, _ := ret[0].([]sdk.UsageSharedPipeline)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SharedPipelineUsage indicates an expected call of SharedPipelineUsage
+func (mr *MockInterfaceMockRecorder) SharedPipelineUsage(groupName, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SharedPipelineUsage", reflect.TypeOf((*MockInterface)(nil).SharedPipelineUsage), groupName, name)
+}
+
 // IntegrationModelList mocks base method
 func (m *MockInterface) IntegrationModelList() ([]sdk.IntegrationModel, error) {
 	m.ctrl.T.Helper()
@@ -6864,6 +7423,46 @@ func (mr *MockInterfaceMockRecorder) ProjectRepositoryManagerDelete(projectKey,
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProjectRepositoryManagerDelete", reflect.TypeOf((*MockInterface)(nil).ProjectRepositoryManagerDelete), projectKey, repoManagerName, force)
 }
 
+// ProjectExport mocks base method
+func (m *MockInterface) ProjectExport(projectKey, passphrase string, mods ...cdsclient.RequestModifier) ([]byte, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{projectKey, passphrase}
+	for _, a := range mods {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ProjectExport", varargs...)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ProjectExport indicates an expected call of ProjectExport
+func (mr *MockInterfaceMockRecorder) ProjectExport(projectKey, passphrase interface{}, mods ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{projectKey, passphrase}, mods...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProjectExport", reflect.TypeOf((*MockInterface)(nil).ProjectExport), varargs...)
+}
+
+// ProjectImport mocks base method
+func (m *MockInterface) ProjectImport(projectKey, passphrase string, content io.Reader, dryRun bool, mods ...cdsclient.RequestModifier) (sdk.ProjectBundleImportResult, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{projectKey, passphrase, content, dryRun}
+	for _, a := range mods {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ProjectImport", varargs...)
+	ret0, _ := ret[0].(sdk.ProjectBundleImportResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ProjectImport indicates an expected call of ProjectImport
+func (mr *MockInterfaceMockRecorder) ProjectImport(projectKey, passphrase, content, dryRun interface{}, mods ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{projectKey, passphrase, content, dryRun}, mods...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProjectImport", reflect.TypeOf((*MockInterface)(nil).ProjectImport), varargs...)
+}
+
 // QueueWorkflowNodeJobRun mocks base method
 func (m *MockInterface) QueueWorkflowNodeJobRun(status ...string) ([]sdk.WorkflowNodeJobRun, error) {
 	m.ctrl.T.Helper()
@@ -6986,11 +7585,12 @@ func (mr *MockInterfaceMockRecorder) QueueJobSendSpawnInfo(ctx, id, in interface
 }
 
 // QueueSendCoverage mocks base method
-func (m *MockInterface) QueueSendCoverage(ctx context.Context, id int64, report coverage.Report) error {
+func (m *MockInterface) QueueSendCoverage(ctx context.Context, id int64, report coverage.Report) (*sdk.WorkflowNodeRunCoverage, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "QueueSendCoverage", ctx, id, report)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret0, _ := ret[0].(*sdk.WorkflowNodeRunCoverage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // QueueSendCoverage indicates an expected call of QueueSendCoverage
@@ -7041,6 +7641,36 @@ func (mr *MockInterfaceMockRecorder) QueueSendVulnerability(ctx, id, report inte
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueueSendVulnerability", reflect.TypeOf((*MockInterface)(nil).QueueSendVulnerability), ctx, id, report)
 }
 
+// QueueSendAnalysis mocks base method
+func (m *MockInterface) QueueSendAnalysis(ctx context.Context, id int64, report sdk.AnalysisWorkerReport) (*sdk.WorkflowNodeRunAnalysis, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QueueSendAnalysis", ctx, id, report)
+	ret0, _ := ret[0].(*sdk.WorkflowNodeRunAnalysis)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QueueSendAnalysis indicates an expected call of QueueSendAnalysis
+func (mr *MockInterfaceMockRecorder) QueueSendAnalysis(ctx, id, report interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueueSendAnalysis", reflect.TypeOf((*MockInterface)(nil).QueueSendAnalysis), ctx, id, report)
+}
+
+// QueueAddChildPipelines mocks base method
+func (m *MockInterface) QueueAddChildPipelines(ctx context.Context, id int64, req sdk.ChildPipelinesRequest) ([]sdk.Node, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QueueAddChildPipelines", ctx, id, req)
+	ret0, _ := ret[0].([]sdk.Node)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QueueAddChildPipelines indicates an expected call of QueueAddChildPipelines
+func (mr *MockInterfaceMockRecorder) QueueAddChildPipelines(ctx, id, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueueAddChildPipelines", reflect.TypeOf((*MockInterface)(nil).QueueAddChildPipelines), ctx, id, req)
+}
+
 // QueueSendStepResult mocks base method
 func (m *MockInterface) QueueSendStepResult(ctx context.Context, id int64, res sdk.StepStatus) error {
 	m.ctrl.T.Helper()
@@ -7784,6 +8414,51 @@ func (mr *MockInterfaceMockRecorder) WorkflowRunList(projectKey, workflowName, o
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WorkflowRunList", reflect.TypeOf((*MockInterface)(nil).WorkflowRunList), projectKey, workflowName, offset, limit)
 }
 
+// WorkflowRunArchiveList mocks base method
+func (m *MockInterface) WorkflowRunArchiveList(projectKey, workflowName string, offset, limit int64) ([]sdk.WorkflowRunArchive, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WorkflowRunArchiveList", projectKey, workflowName, offset, limit)
+	ret0, _ := ret[0].([]sdk.WorkflowRunArchive)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WorkflowRunArchiveList indicates an expected call of WorkflowRunArchiveList
+func (mr *MockInterfaceMockRecorder) WorkflowRunArchiveList(projectKey, workflowName, offset, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WorkflowRunArchiveList", reflect.TypeOf((*MockInterface)(nil).WorkflowRunArchiveList), projectKey, workflowName, offset, limit)
+}
+
+// WorkflowRunArchiveGet mocks base method
+func (m *MockInterface) WorkflowRunArchiveGet(projectKey, workflowName string, number int64) (*sdk.WorkflowRunArchiveData, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WorkflowRunArchiveGet", projectKey, workflowName, number)
+	ret0, _ := ret[0].(*sdk.WorkflowRunArchiveData)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WorkflowRunArchiveGet indicates an expected call of WorkflowRunArchiveGet
+func (mr *MockInterfaceMockRecorder) WorkflowRunArchiveGet(projectKey, workflowName, number interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WorkflowRunArchiveGet", reflect.TypeOf((*MockInterface)(nil).WorkflowRunArchiveGet), projectKey, workflowName, number)
+}
+
+// WorkflowPurgeDryRun mocks base method
+func (m *MockInterface) WorkflowPurgeDryRun(projectKey, workflowName string, policy *sdk.WorkflowPurgePolicy) ([]sdk.WorkflowRunPurge, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WorkflowPurgeDryRun", projectKey, workflowName, policy)
+	ret0, _ := ret[0].([]sdk.WorkflowRunPurge)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WorkflowPurgeDryRun indicates an expected call of WorkflowPurgeDryRun
+func (mr *MockInterfaceMockRecorder) WorkflowPurgeDryRun(projectKey, workflowName, policy interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WorkflowPurgeDryRun", reflect.TypeOf((*MockInterface)(nil).WorkflowPurgeDryRun), projectKey, workflowName, policy)
+}
+
 // WorkflowRunArtifacts mocks base method
 func (m *MockInterface) WorkflowRunArtifacts(projectKey, name string, number int64) ([]sdk.WorkflowNodeRunArtifact, error) {
 	m.ctrl.T.Helper()
@@ -7903,6 +8578,61 @@ func (mr *MockInterfaceMockRecorder) WorkflowNodeRun(projectKey, name, number, n
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WorkflowNodeRun", reflect.TypeOf((*MockInterface)(nil).WorkflowNodeRun), projectKey, name, number, nodeRunID)
 }
 
+// WorkflowNodeRunAnalyses mocks base method
+func (m *MockInterface) WorkflowNodeRunAnalyses(projectKey, name string, number, nodeRunID int64) ([]sdk.WorkflowNodeRunAnalysis, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WorkflowNodeRunAnalyses", projectKey, name, number, nodeRunID)
+	ret0, _ := ret[0].([]sdk.WorkflowNodeRunAnalysis)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WorkflowNodeRunAnalyses indicates an expected call of WorkflowNodeRunAnalyses
+func (mr *MockInterfaceMockRecorder) WorkflowNodeRunAnalyses(projectKey, name, number, nodeRunID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WorkflowNodeRunAnalyses", reflect.TypeOf((*MockInterface)(nil).WorkflowNodeRunAnalyses), projectKey, name, number, nodeRunID)
+}
+
+// WorkflowTests mocks base method
+func (m *MockInterface) WorkflowTests(projectKey, name string, mods ...cdsclient.RequestModifier) (*sdk.WorkflowTestsSummary, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{projectKey, name}
+	for _, a := range mods {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "WorkflowTests", varargs...)
+	ret0, _ := ret[0].(*sdk.WorkflowTestsSummary)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WorkflowTests indicates an expected call of WorkflowTests
+func (mr *MockInterfaceMockRecorder) WorkflowTests(projectKey, name interface{}, mods ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{projectKey, name}, mods...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WorkflowTests", reflect.TypeOf((*MockInterface)(nil).WorkflowTests), varargs...)
+}
+
+// WorkflowTestCaseHistory mocks base method
+func (m *MockInterface) WorkflowTestCaseHistory(projectKey, name, nodeName, suite, testName string, mods ...cdsclient.RequestModifier) (*sdk.TestCaseHistory, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{projectKey, name, nodeName, suite, testName}
+	for _, a := range mods {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "WorkflowTestCaseHistory", varargs...)
+	ret0, _ := ret[0].(*sdk.TestCaseHistory)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WorkflowTestCaseHistory indicates an expected call of WorkflowTestCaseHistory
+func (mr *MockInterfaceMockRecorder) WorkflowTestCaseHistory(projectKey, name, nodeName, suite, testName interface{}, mods ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{projectKey, name, nodeName, suite, testName}, mods...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WorkflowTestCaseHistory", reflect.TypeOf((*MockInterface)(nil).WorkflowTestCaseHistory), varargs...)
+}
+
 // WorkflowNodeRunArtifactDownload mocks base method
 func (m *MockInterface) WorkflowNodeRunArtifactDownload(projectKey, name string, a sdk.WorkflowNodeRunArtifact, w io.Writer) error {
 	m.ctrl.T.Helper()
@@ -7977,17 +8707,17 @@ func (mr *MockInterfaceMockRecorder) WorkflowAllHooksList() *gomock.Call {
 }
 
 // WorkflowCachePush mocks base method
-func (m *MockInterface) WorkflowCachePush(projectKey, integrationName, ref string, tarContent io.Reader, size int) error {
+func (m *MockInterface) WorkflowCachePush(projectKey, integrationName, ref, key string, tarContent io.Reader, size int) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "WorkflowCachePush", projectKey, integrationName, ref, tarContent, size)
+	ret := m.ctrl.Call(m, "WorkflowCachePush", projectKey, integrationName, ref, key, tarContent, size)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // WorkflowCachePush indicates an expected call of WorkflowCachePush
-func (mr *MockInterfaceMockRecorder) WorkflowCachePush(projectKey, integrationName, ref, tarContent, size interface{}) *gomock.Call {
+func (mr *MockInterfaceMockRecorder) WorkflowCachePush(projectKey, integrationName, ref, key, tarContent, size interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WorkflowCachePush", reflect.TypeOf((*MockInterface)(nil).WorkflowCachePush), projectKey, integrationName, ref, tarContent, size)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WorkflowCachePush", reflect.TypeOf((*MockInterface)(nil).WorkflowCachePush), projectKey, integrationName, ref, key, tarContent, size)
 }
 
 // WorkflowCachePull mocks base method
@@ -8005,6 +8735,21 @@ func (mr *MockInterfaceMockRecorder) WorkflowCachePull(projectKey, integrationNa
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WorkflowCachePull", reflect.TypeOf((*MockInterface)(nil).WorkflowCachePull), projectKey, integrationName, ref)
 }
 
+// WorkflowCacheLookup mocks base method
+func (m *MockInterface) WorkflowCacheLookup(projectKey, integrationName, key string, restoreKeys []string) (*sdk.Cache, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WorkflowCacheLookup", projectKey, integrationName, key, restoreKeys)
+	ret0, _ := ret[0].(*sdk.Cache)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WorkflowCacheLookup indicates an expected call of WorkflowCacheLookup
+func (mr *MockInterfaceMockRecorder) WorkflowCacheLookup(projectKey, integrationName, key, restoreKeys interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WorkflowCacheLookup", reflect.TypeOf((*MockInterface)(nil).WorkflowCacheLookup), projectKey, integrationName, key, restoreKeys)
+}
+
 // WorkflowTransformAsCode mocks base method
 func (m *MockInterface) WorkflowTransformAsCode(projectKey, workflowName, branch, message string) (*sdk.Operation, error) {
 	m.ctrl.T.Helper()
@@ -8080,6 +8825,21 @@ func (mr *MockInterfaceMockRecorder) MonErrorsGet(requestID interface{}) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MonErrorsGet", reflect.TypeOf((*MockInterface)(nil).MonErrorsGet), requestID)
 }
 
+// HookChangedFiles mocks base method
+func (m *MockInterface) HookChangedFiles(uuid, base, head string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HookChangedFiles", uuid, base, head)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HookChangedFiles indicates an expected call of HookChangedFiles
+func (mr *MockInterfaceMockRecorder) HookChangedFiles(uuid, base, head interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HookChangedFiles", reflect.TypeOf((*MockInterface)(nil).HookChangedFiles), uuid, base, head)
+}
+
 // PollVCSEvents mocks base method
 func (m *MockInterface) PollVCSEvents(uuid string, workflowID int64, vcsServer string, timestamp int64) (sdk.RepositoryEvents, time.Duration, error) {
 	m.ctrl.T.Helper()
@@ -8564,11 +9324,12 @@ func (mr *MockWorkerInterfaceMockRecorder) QueueJobSendSpawnInfo(ctx, id, in int
 }
 
 // QueueSendCoverage mocks base method
-func (m *MockWorkerInterface) QueueSendCoverage(ctx context.Context, id int64, report coverage.Report) error {
+func (m *MockWorkerInterface) QueueSendCoverage(ctx context.Context, id int64, report coverage.Report) (*sdk.WorkflowNodeRunCoverage, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "QueueSendCoverage", ctx, id, report)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret0, _ := ret[0].(*sdk.WorkflowNodeRunCoverage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // QueueSendCoverage indicates an expected call of QueueSendCoverage
@@ -8619,6 +9380,36 @@ func (mr *MockWorkerInterfaceMockRecorder) QueueSendVulnerability(ctx, id, repor
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueueSendVulnerability", reflect.TypeOf((*MockWorkerInterface)(nil).QueueSendVulnerability), ctx, id, report)
 }
 
+// QueueSendAnalysis mocks base method
+func (m *MockWorkerInterface) QueueSendAnalysis(ctx context.Context, id int64, report sdk.AnalysisWorkerReport) (*sdk.WorkflowNodeRunAnalysis, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QueueSendAnalysis", ctx, id, report)
+	ret0, _ := ret[0].(*sdk.WorkflowNodeRunAnalysis)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QueueSendAnalysis indicates an expected call of QueueSendAnalysis
+func (mr *MockWorkerInterfaceMockRecorder) QueueSendAnalysis(ctx, id, report interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueueSendAnalysis", reflect.TypeOf((*MockWorkerInterface)(nil).QueueSendAnalysis), ctx, id, report)
+}
+
+// QueueAddChildPipelines mocks base method
+func (m *MockWorkerInterface) QueueAddChildPipelines(ctx context.Context, id int64, req sdk.ChildPipelinesRequest) ([]sdk.Node, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QueueAddChildPipelines", ctx, id, req)
+	ret0, _ := ret[0].([]sdk.Node)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QueueAddChildPipelines indicates an expected call of QueueAddChildPipelines
+func (mr *MockWorkerInterfaceMockRecorder) QueueAddChildPipelines(ctx, id, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueueAddChildPipelines", reflect.TypeOf((*MockWorkerInterface)(nil).QueueAddChildPipelines), ctx, id, req)
+}
+
 // QueueSendStepResult mocks base method
 func (m *MockWorkerInterface) QueueSendStepResult(ctx context.Context, id int64, res sdk.StepStatus) error {
 	m.ctrl.T.Helper()
@@ -8992,17 +9783,17 @@ func (mr *MockWorkerInterfaceMockRecorder) WorkflowRunArtifacts(projectKey, name
 }
 
 // WorkflowCachePush mocks base method
-func (m *MockWorkerInterface) WorkflowCachePush(projectKey, integrationName, ref string, tarContent io.Reader, size int) error {
+func (m *MockWorkerInterface) WorkflowCachePush(projectKey, integrationName, ref, key string, tarContent io.Reader, size int) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "WorkflowCachePush", projectKey, integrationName, ref, tarContent, size)
+	ret := m.ctrl.Call(m, "WorkflowCachePush", projectKey, integrationName, ref, key, tarContent, size)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // WorkflowCachePush indicates an expected call of WorkflowCachePush
-func (mr *MockWorkerInterfaceMockRecorder) WorkflowCachePush(projectKey, integrationName, ref, tarContent, size interface{}) *gomock.Call {
+func (mr *MockWorkerInterfaceMockRecorder) WorkflowCachePush(projectKey, integrationName, ref, key, tarContent, size interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WorkflowCachePush", reflect.TypeOf((*MockWorkerInterface)(nil).WorkflowCachePush), projectKey, integrationName, ref, tarContent, size)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WorkflowCachePush", reflect.TypeOf((*MockWorkerInterface)(nil).WorkflowCachePush), projectKey, integrationName, ref, key, tarContent, size)
 }
 
 // WorkflowCachePull mocks base method
@@ -9020,6 +9811,21 @@ func (mr *MockWorkerInterfaceMockRecorder) WorkflowCachePull(projectKey, integra
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WorkflowCachePull", reflect.TypeOf((*MockWorkerInterface)(nil).WorkflowCachePull), projectKey, integrationName, ref)
 }
 
+// WorkflowCacheLookup mocks base method
+func (m *MockWorkerInterface) WorkflowCacheLookup(projectKey, integrationName, key string, restoreKeys []string) (*sdk.Cache, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WorkflowCacheLookup", projectKey, integrationName, key, restoreKeys)
+	ret0, _ := ret[0].(*sdk.Cache)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WorkflowCacheLookup indicates an expected call of WorkflowCacheLookup
+func (mr *MockWorkerInterfaceMockRecorder) WorkflowCacheLookup(projectKey, integrationName, key, restoreKeys interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WorkflowCacheLookup", reflect.TypeOf((*MockWorkerInterface)(nil).WorkflowCacheLookup), projectKey, integrationName, key, restoreKeys)
+}
+
 // WorkflowRunSearch mocks base method
 func (m *MockWorkerInterface) WorkflowRunSearch(projectKey string, offset, limit int64, filter ...cdsclient.Filter) ([]sdk.WorkflowRun, error) {
 	m.ctrl.T.Helper()
@@ -9713,6 +10519,36 @@ func (mr *MockAuthClientMockRecorder) AuthConsumerCreateForUser(username, reques
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AuthConsumerCreateForUser", reflect.TypeOf((*MockAuthClient)(nil).AuthConsumerCreateForUser), username, request)
 }
 
+// AuthConsumerSetAllowedCIDRs mocks base method
+func (m *MockAuthClient) AuthConsumerSetAllowedCIDRs(username, id string, cidrs []string) (sdk.AuthConsumer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AuthConsumerSetAllowedCIDRs", username, id, cidrs)
+	ret0, _ := ret[0].(sdk.AuthConsumer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AuthConsumerSetAllowedCIDRs indicates an expected call of AuthConsumerSetAllowedCIDRs
+func (mr *MockAuthClientMockRecorder) AuthConsumerSetAllowedCIDRs(username, id, cidrs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AuthConsumerSetAllowedCIDRs", reflect.TypeOf((*MockAuthClient)(nil).AuthConsumerSetAllowedCIDRs), username, id, cidrs)
+}
+
+// AuthConsumerIPRejectionList mocks base method
+func (m *MockAuthClient) AuthConsumerIPRejectionList(username, id string) ([]sdk.AuthConsumerIPRejection, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AuthConsumerIPRejectionList", username, id)
+	ret0, _ := ret[0].([]sdk.AuthConsumerIPRejection)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AuthConsumerIPRejectionList indicates an expected call of AuthConsumerIPRejectionList
+func (mr *MockAuthClientMockRecorder) AuthConsumerIPRejectionList(username, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AuthConsumerIPRejectionList", reflect.TypeOf((*MockAuthClient)(nil).AuthConsumerIPRejectionList), username, id)
+}
+
 // AuthSessionListByUser mocks base method
 func (m *MockAuthClient) AuthSessionListByUser(username string) (sdk.AuthSessions, error) {
 	m.ctrl.T.Helper()
@@ -0,0 +1,99 @@
+package sdk
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	coverage "github.com/sguiheux/go-coverage"
+)
+
+// CoverageFileDiff represents the line coverage change of a file compared to the default branch.
+type CoverageFileDiff struct {
+	Path         string  `json:"path"`
+	Lines        float64 `json:"lines"`
+	DefaultLines float64 `json:"default_lines"`
+	Delta        float64 `json:"delta"`
+	New          bool    `json:"new,omitempty"`
+}
+
+// CoveragePercent returns the percentage of covered elements, -1 if there is no element.
+func CoveragePercent(covered, total int) float64 {
+	if total == 0 {
+		return -1
+	}
+	return float64(covered) / float64(total) * 100
+}
+
+// ComputeCoverageFilesDiff returns the files for which line coverage changed compared to
+// the default branch report, biggest drops first.
+func ComputeCoverageFilesDiff(current, defaultBranch coverage.Report) []CoverageFileDiff {
+	defaultFiles := make(map[string]coverage.FileReport, len(defaultBranch.Files))
+	for _, f := range defaultBranch.Files {
+		defaultFiles[f.Path] = f
+	}
+
+	var diffs []CoverageFileDiff
+	for _, f := range current.Files {
+		if f.TotalLines == 0 {
+			continue
+		}
+		d := CoverageFileDiff{
+			Path:  f.Path,
+			Lines: CoveragePercent(f.CoveredLines, f.TotalLines),
+		}
+		df, ok := defaultFiles[f.Path]
+		if !ok || df.TotalLines == 0 {
+			d.New = true
+			d.Delta = d.Lines
+		} else {
+			d.DefaultLines = CoveragePercent(df.CoveredLines, df.TotalLines)
+			d.Delta = d.Lines - d.DefaultLines
+		}
+		// Ignore rounding errors
+		if math.Abs(d.Delta) < 0.01 && !d.New {
+			continue
+		}
+		diffs = append(diffs, d)
+	}
+
+	sort.SliceStable(diffs, func(i, j int) bool {
+		if diffs[i].Delta != diffs[j].Delta {
+			return diffs[i].Delta < diffs[j].Delta
+		}
+		return diffs[i].Path < diffs[j].Path
+	})
+	return diffs
+}
+
+// PullRequestComment returns a markdown summary of the coverage change compared to the default branch.
+func (c WorkflowNodeRunCoverage) PullRequestComment(maxFiles int) string {
+	current := CoveragePercent(c.Report.CoveredLines, c.Report.TotalLines)
+	def := CoveragePercent(c.Trend.DefaultBranch.CoveredLines, c.Trend.DefaultBranch.TotalLines)
+
+	var b strings.Builder
+	b.WriteString("### Code coverage\n\n")
+	if def < 0 {
+		b.WriteString(fmt.Sprintf("Line coverage: **%.2f%%**\n", current))
+		return b.String()
+	}
+	b.WriteString(fmt.Sprintf("Line coverage: **%.2f%%** (%+.2f%% compared to default branch: %.2f%%)\n", current, current-def, def))
+
+	if len(c.Trend.FilesDiff) == 0 {
+		return b.String()
+	}
+	b.WriteString("\n| File | Coverage | Change |\n|---|---|---|\n")
+	for i, f := range c.Trend.FilesDiff {
+		if i == maxFiles {
+			b.WriteString(fmt.Sprintf("\n%d more file(s) changed.\n", len(c.Trend.FilesDiff)-maxFiles))
+			break
+		}
+		if f.New {
+			b.WriteString(fmt.Sprintf("| %s | %.2f%% | new |\n", f.Path, f.Lines))
+			continue
+		}
+		b.WriteString(fmt.Sprintf("| %s | %.2f%% | %+.2f%% |\n", f.Path, f.Lines, f.Delta))
+	}
+	return b.String()
+}
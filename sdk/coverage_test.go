@@ -0,0 +1,45 @@
+package sdk
+
+import (
+	"testing"
+
+	coverage "github.com/sguiheux/go-coverage"
+	"github.com/stretchr/testify/require"
+)
+
+func TestComputeCoverageFilesDiff(t *testing.T) {
+	current := coverage.Report{Files: []coverage.FileReport{
+		{Path: "a.go", TotalLines: 10, CoveredLines: 5},
+		{Path: "b.go", TotalLines: 10, CoveredLines: 10},
+		{Path: "c.go", TotalLines: 4, CoveredLines: 1},
+		{Path: "d.go", TotalLines: 10, CoveredLines: 2},
+	}}
+	defaultBranch := coverage.Report{Files: []coverage.FileReport{
+		{Path: "a.go", TotalLines: 10, CoveredLines: 8},
+		{Path: "b.go", TotalLines: 10, CoveredLines: 10},
+		{Path: "d.go", TotalLines: 10, CoveredLines: 1},
+	}}
+
+	diffs := ComputeCoverageFilesDiff(current, defaultBranch)
+	require.Len(t, diffs, 3)
+	require.Equal(t, "a.go", diffs[0].Path)
+	require.InDelta(t, -30, diffs[0].Delta, 0.001)
+	require.Equal(t, "d.go", diffs[1].Path)
+	require.InDelta(t, 10, diffs[1].Delta, 0.001)
+	require.Equal(t, "c.go", diffs[2].Path)
+	require.True(t, diffs[2].New)
+
+	cov := WorkflowNodeRunCoverage{
+		Report: coverage.Report{TotalLines: 34, CoveredLines: 18},
+		Trend: WorkflowNodeRunCoverageTrends{
+			DefaultBranch: coverage.Report{TotalLines: 30, CoveredLines: 19},
+			FilesDiff:     diffs,
+		},
+	}
+	comment := cov.PullRequestComment(2)
+	require.Contains(t, comment, "Line coverage: **52.94%** (-10.39% compared to default branch: 63.33%)")
+	require.Contains(t, comment, "| a.go | 50.00% | -30.00% |")
+	require.Contains(t, comment, "| d.go | 20.00% | +10.00% |")
+	require.Contains(t, comment, "1 more file(s) changed.")
+	require.NotContains(t, comment, "c.go")
+}
@@ -1,6 +1,9 @@
 package sdk
 
 import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
 	"time"
 )
 
@@ -20,3 +23,65 @@ type CanonicalFormUsage struct {
 }
 
 type CanonicalFormUsageResume map[string][]CanonicalFormUsage
+
+// Database key rotation status.
+const (
+	DatabaseKeyRotationStatusRunning = "Running"
+	DatabaseKeyRotationStatusDone    = "Done"
+)
+
+// DatabaseKeyRotation represents the re-encryption and re-signature of all the tuples of a database
+// with the latest configured keys. Progress is persisted after each batch so the rotation can be resumed.
+type DatabaseKeyRotation struct {
+	ID                     int64                       `json:"id" db:"id" cli:"id,key"`
+	Database               string                      `json:"database" db:"-" cli:"database"`
+	Status                 string                      `json:"status" db:"status" cli:"status"`
+	SignatureKeyTimestamp  int64                       `json:"signature_key_timestamp" db:"signature_key_timestamp" cli:"signature_key"`
+	EncryptionKeyTimestamp int64                       `json:"encryption_key_timestamp" db:"encryption_key_timestamp" cli:"encryption_key"`
+	Entities               DatabaseKeyRotationEntities `json:"entities" db:"entities" cli:"-"`
+	RetiredKeys            Int64Slice                  `json:"retired_keys" db:"retired_keys" cli:"retired_keys"`
+	Error                  string                      `json:"error,omitempty" db:"error" cli:"error"`
+	Started                time.Time                   `json:"started" db:"started" cli:"started"`
+	LastModified           time.Time                   `json:"last_modified" db:"last_modified" cli:"last_modified"`
+	Done                   *time.Time                  `json:"done,omitempty" db:"done" cli:"done"`
+}
+
+// Progress returns the number of rotated entities and the total number of entities.
+func (r DatabaseKeyRotation) Progress() (int, int) {
+	var done int
+	for _, e := range r.Entities {
+		if e.Done {
+			done++
+		}
+	}
+	return done, len(r.Entities)
+}
+
+// DatabaseKeyRotationEntity is the rotation progress of an entity.
+type DatabaseKeyRotationEntity struct {
+	Entity string `json:"entity" cli:"entity,key"`
+	LastPK string `json:"last_pk,omitempty" cli:"last_pk"`
+	Count  int64  `json:"count" cli:"count"`
+	Done   bool   `json:"done" cli:"done"`
+}
+
+// DatabaseKeyRotationEntities type used for database json storage.
+type DatabaseKeyRotationEntities []DatabaseKeyRotationEntity
+
+// Scan rotation entities.
+func (e *DatabaseKeyRotationEntities) Scan(src interface{}) error {
+	if src == nil {
+		return nil
+	}
+	source, ok := src.([]byte)
+	if !ok {
+		return WithStack(errors.New("type assertion .([]byte) failed"))
+	}
+	return WrapError(json.Unmarshal(source, e), "cannot unmarshal DatabaseKeyRotationEntities")
+}
+
+// Value returns driver.Value from rotation entities.
+func (e DatabaseKeyRotationEntities) Value() (driver.Value, error) {
+	j, err := json.Marshal(e)
+	return j, WrapError(err, "cannot marshal DatabaseKeyRotationEntities")
+}
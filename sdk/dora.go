@@ -0,0 +1,54 @@
+package sdk
+
+import (
+	"time"
+)
+
+// ApplicationDeployment is the result of a workflow node run that deployed an application on an environment.
+type ApplicationDeployment struct {
+	ID                int64      `json:"id" db:"id"`
+	ProjectID         int64      `json:"project_id" db:"project_id"`
+	ApplicationID     int64      `json:"application_id" db:"application_id"`
+	EnvironmentID     int64      `json:"environment_id" db:"environment_id"`
+	WorkflowID        int64      `json:"workflow_id" db:"workflow_id"`
+	WorkflowRunID     int64      `json:"workflow_run_id" db:"workflow_run_id"`
+	WorkflowNodeRunID int64      `json:"workflow_node_run_id" db:"workflow_node_run_id"`
+	Number            int64      `json:"num" db:"num"`
+	Status            string     `json:"status" db:"status"`
+	VCSHash           string     `json:"vcs_hash,omitempty" db:"vcs_hash"`
+	CommitDate        *time.Time `json:"commit_date,omitempty" db:"commit_date"`
+	Start             time.Time  `json:"start" db:"start"`
+	Done              time.Time  `json:"done" db:"done"`
+	// Aggregate data
+	ProjectKey      string `json:"project_key,omitempty" db:"-"`
+	ApplicationName string `json:"application_name,omitempty" db:"-"`
+	EnvironmentName string `json:"environment_name,omitempty" db:"-"`
+}
+
+// ApplicationDeploymentFilter is used to load the deployments of a project, an application or an environment.
+type ApplicationDeploymentFilter struct {
+	ProjectID     int64
+	ApplicationID int64
+	EnvironmentID int64
+	From          time.Time
+	To            time.Time
+}
+
+// DORAMetrics are the four key metrics of the software delivery performance computed on a time range, see
+// https://www.devops-research.com/research.html
+type DORAMetrics struct {
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+	// Number of terminated deployments, successful or failed
+	Deployments       int64 `json:"deployments"`
+	FailedDeployments int64 `json:"failed_deployments"`
+	// Number of successful deployments per day
+	DeploymentFrequency float64 `json:"deployment_frequency"`
+	// Median time between the commit and its successful deployment in seconds
+	LeadTimeForChanges float64 `json:"lead_time_for_changes"`
+	// Ratio of failed deployments
+	ChangeFailureRate float64 `json:"change_failure_rate"`
+	// Median time between a failed deployment and the next successful deployment on the same application and
+	// environment in seconds
+	TimeToRestore float64 `json:"time_to_restore"`
+}
@@ -0,0 +1,6 @@
+package sdk
+
+// EventAuditAPICall represents the event sent for each audited API call
+type EventAuditAPICall struct {
+	AuditAPICall
+}
@@ -106,3 +106,12 @@ type EventPipelineJobDelete struct {
 	StageBuildOrder int    `json:"stage_build_order"`
 	JobName         string `json:"job_name"`
 }
+
+// EventPipelineSharedVersion represents the event when a new version of the shared pipeline
+// a project pipeline was created from is published.
+type EventPipelineSharedVersion struct {
+	GroupName      string `json:"group_name"`
+	Name           string `json:"name"`
+	Version        int64  `json:"version"`
+	CurrentVersion int64  `json:"current_version"`
+}
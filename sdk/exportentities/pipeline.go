@@ -39,14 +39,15 @@ type Stage struct {
 
 // Job represents exported sdk.Job
 type Job struct {
-	Name           string        `json:"job,omitempty" yaml:"job,omitempty" jsonschema_description:"The name of the job."`
-	Stage          string        `json:"stage,omitempty" yaml:"stage,omitempty" jsonschema_description:"The name of the stage for the job."`
-	Description    string        `json:"description,omitempty" yaml:"description,omitempty" jsonschema_description:"The description of the job."`
-	Enabled        *bool         `json:"enabled,omitempty" yaml:"enabled,omitempty" jsonschema_description:"Job is enabled by default, you can set this option to disable a job."`
-	Steps          []Step        `json:"steps,omitempty" yaml:"steps,omitempty" jsonschema_description:"The list of steps for the job."`
-	Requirements   []Requirement `json:"requirements,omitempty" yaml:"requirements,omitempty" jsonschema_description:"The list of requirements for the jobs."`
-	Optional       *bool         `json:"optional,omitempty" yaml:"optional,omitempty" jsonschema_description:"Set this option to ignore job's errors."`
-	AlwaysExecuted *bool         `json:"always_executed,omitempty" yaml:"always_executed,omitempty" jsonschema_description:"Set this option to execute the job even if a previous step failed."`
+	Name           string         `json:"job,omitempty" yaml:"job,omitempty" jsonschema_description:"The name of the job."`
+	Stage          string         `json:"stage,omitempty" yaml:"stage,omitempty" jsonschema_description:"The name of the stage for the job."`
+	Description    string         `json:"description,omitempty" yaml:"description,omitempty" jsonschema_description:"The description of the job."`
+	Enabled        *bool          `json:"enabled,omitempty" yaml:"enabled,omitempty" jsonschema_description:"Job is enabled by default, you can set this option to disable a job."`
+	Steps          []Step         `json:"steps,omitempty" yaml:"steps,omitempty" jsonschema_description:"The list of steps for the job."`
+	Requirements   []Requirement  `json:"requirements,omitempty" yaml:"requirements,omitempty" jsonschema_description:"The list of requirements for the jobs."`
+	Optional       *bool          `json:"optional,omitempty" yaml:"optional,omitempty" jsonschema_description:"Set this option to ignore job's errors."`
+	AlwaysExecuted *bool          `json:"always_executed,omitempty" yaml:"always_executed,omitempty" jsonschema_description:"Set this option to execute the job even if a previous step failed."`
+	Matrix         *sdk.JobMatrix `json:"matrix,omitempty" yaml:"matrix,omitempty" jsonschema_description:"The matrix of values for which the job will be executed, values are available as {{.cds.matrix.AXIS}}."`
 }
 
 // Requirement represents an exported sdk.Requirement
@@ -165,6 +166,7 @@ func newJob(j sdk.Job) Job {
 	jo.Steps = newSteps(j.Action)
 	jo.Description = j.Action.Description
 	jo.Requirements = newRequirements(j.Action.Requirements)
+	jo.Matrix = j.Matrix
 	return jo
 }
 
@@ -261,6 +263,7 @@ func computeJob(name string, j Job) (*sdk.Job, error) {
 		return nil, err
 	}
 	job.Action.Actions = children
+	job.Matrix = j.Matrix
 
 	return &job, nil
 }
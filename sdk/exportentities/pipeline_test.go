@@ -540,6 +540,37 @@ jobs:
 	assert.Len(t, p.Stages[0].Jobs[0].Action.Requirements, 2)
 }
 
+func Test_ImportPipelineWithMatrix(t *testing.T) {
+	in := `name: build
+jobs:
+- job: test
+  matrix:
+    axes:
+      go: ["1.13", "1.14"]
+      os: [linux, windows]
+    exclude:
+    - go: "1.13"
+      os: windows
+    max_parallel: 2
+  steps:
+  - script: echo {{.cds.matrix.go}}
+`
+
+	payload := &exportentities.PipelineV1{}
+	test.NoError(t, yaml.Unmarshal([]byte(in), payload))
+
+	p, err := payload.Pipeline()
+	test.NoError(t, err)
+
+	m := p.Stages[0].Jobs[0].Matrix
+	assert.NotNil(t, m)
+	assert.Equal(t, 2, m.MaxParallel)
+	assert.Len(t, m.Combinations(), 3)
+
+	exported := exportentities.NewPipelineV1(*p)
+	assert.Equal(t, m, exported.Jobs[0].Matrix)
+}
+
 func Test_ImportPipelineWithGitClone(t *testing.T) {
 	in := `name: build-all-images
 jobs:
@@ -0,0 +1,104 @@
+package exportentities
+
+import (
+	"github.com/ovh/cds/sdk"
+)
+
+// ProjectVersion1 is the version of the project export format.
+const ProjectVersion1 = "v1.0"
+
+// Project represents exported sdk.Project settings, secrets values are encrypted.
+type Project struct {
+	Version      string                        `json:"version,omitempty" yaml:"version,omitempty"`
+	Key          string                        `json:"key" yaml:"key"`
+	Name         string                        `json:"name" yaml:"name"`
+	Description  string                        `json:"description,omitempty" yaml:"description,omitempty"`
+	Metadata     map[string]string             `json:"metadata,omitempty" yaml:"metadata,omitempty"`
+	Variables    map[string]VariableValue      `json:"variables,omitempty" yaml:"variables,omitempty"`
+	Keys         map[string]KeyValue           `json:"keys,omitempty" yaml:"keys,omitempty"`
+	Integrations map[string]ProjectIntegration `json:"integrations,omitempty" yaml:"integrations,omitempty"`
+	Permissions  map[string]int                `json:"permissions,omitempty" yaml:"permissions,omitempty"`
+	// Salt is used with the passphrase of the bundle to derive the key that encrypts the secrets.
+	Salt string `json:"salt,omitempty" yaml:"salt,omitempty"`
+}
+
+// ProjectIntegration represents an exported sdk.ProjectIntegration.
+type ProjectIntegration struct {
+	Model  string                   `json:"model" yaml:"model"`
+	Config map[string]VariableValue `json:"config,omitempty" yaml:"config,omitempty"`
+}
+
+// NewProject returns an exportable project from an sdk.Project, variables and integrations secrets should
+// already be encrypted.
+func NewProject(p sdk.Project, keys []EncryptedKey) Project {
+	proj := Project{
+		Version:     ProjectVersion1,
+		Key:         p.Key,
+		Name:        p.Name,
+		Description: p.Description,
+	}
+
+	if len(p.Metadata) > 0 {
+		proj.Metadata = make(map[string]string, len(p.Metadata))
+		for k, v := range p.Metadata {
+			proj.Metadata[k] = v
+		}
+	}
+
+	if len(p.Variables) > 0 {
+		proj.Variables = make(map[string]VariableValue, len(p.Variables))
+		for _, v := range p.Variables {
+			proj.Variables[v.Name] = VariableValue{
+				Type:  v.Type,
+				Value: v.Value,
+			}
+		}
+	}
+
+	if len(keys) > 0 {
+		proj.Keys = make(map[string]KeyValue, len(keys))
+		for _, k := range keys {
+			proj.Keys[k.Name] = KeyValue{
+				Type:  k.Type,
+				Value: k.Content,
+			}
+		}
+	}
+
+	if len(p.Integrations) > 0 {
+		proj.Integrations = make(map[string]ProjectIntegration, len(p.Integrations))
+		for _, i := range p.Integrations {
+			it := ProjectIntegration{Model: i.Model.Name}
+			if len(i.Config) > 0 {
+				it.Config = make(map[string]VariableValue, len(i.Config))
+				for k, v := range i.Config {
+					it.Config[k] = VariableValue{
+						Type:  v.Type,
+						Value: v.Value,
+					}
+				}
+			}
+			proj.Integrations[i.Name] = it
+		}
+	}
+
+	if len(p.ProjectGroups) > 0 {
+		proj.Permissions = make(map[string]int, len(p.ProjectGroups))
+		for _, gp := range p.ProjectGroups {
+			proj.Permissions[gp.Group.Name] = gp.Permission
+		}
+	}
+
+	return proj
+}
+
+// ProjectBundle contains all the entities of a project.
+type ProjectBundle struct {
+	Project      Project
+	Applications []Application
+	Environments []Environment
+	Pipelines    []PipelineV1
+	// Workflows only contain the workflow or the template instance, all applications, environments and
+	// pipelines are exported at the project level.
+	Workflows []WorkflowComponents
+}
@@ -0,0 +1,169 @@
+package exportentities
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"path"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// Files and directories of a project bundle tar.
+const (
+	ProjectBundleProjectFile     = "project.yml"
+	ProjectBundleApplicationsDir = "applications"
+	ProjectBundleEnvironmentsDir = "environments"
+	ProjectBundlePipelinesDir    = "pipelines"
+	ProjectBundleWorkflowsDir    = "workflows"
+)
+
+// TarProjectBundle returns a tar containing all files for a project.
+func TarProjectBundle(ctx context.Context, b ProjectBundle, writer io.Writer) error {
+	tw := tar.NewWriter(writer)
+	defer func() {
+		if err := tw.Close(); err != nil {
+			log.Error(ctx, "%v", sdk.WrapError(err, "unable to close tar writer"))
+		}
+	}()
+
+	if err := writeTarYAML(tw, ProjectBundleProjectFile, b.Project); err != nil {
+		return err
+	}
+	for _, a := range b.Applications {
+		if err := writeTarYAML(tw, path.Join(ProjectBundleApplicationsDir, fmt.Sprintf(PullApplicationName, a.Name)), a); err != nil {
+			return err
+		}
+	}
+	for _, e := range b.Environments {
+		if err := writeTarYAML(tw, path.Join(ProjectBundleEnvironmentsDir, fmt.Sprintf(PullEnvironmentName, e.Name)), e); err != nil {
+			return err
+		}
+	}
+	for _, p := range b.Pipelines {
+		if err := writeTarYAML(tw, path.Join(ProjectBundlePipelinesDir, fmt.Sprintf(PullPipelineName, p.Name)), p); err != nil {
+			return err
+		}
+	}
+	for _, w := range b.Workflows {
+		switch {
+		case w.Template.Name != "":
+			if err := writeTarYAML(tw, path.Join(ProjectBundleWorkflowsDir, fmt.Sprintf(PullWorkflowName, w.Template.Name)), w.Template); err != nil {
+				return err
+			}
+		case w.Workflow != nil:
+			if err := writeTarYAML(tw, path.Join(ProjectBundleWorkflowsDir, fmt.Sprintf(PullWorkflowName, w.Workflow.GetName())), w.Workflow); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func writeTarYAML(tw *tar.Writer, name string, i interface{}) error {
+	bs, err := yaml.Marshal(i)
+	if err != nil {
+		return sdk.WithStack(err)
+	}
+	if err := tw.WriteHeader(&tar.Header{
+		Name: name,
+		Mode: 0644,
+		Size: int64(len(bs)),
+	}); err != nil {
+		return sdk.WrapError(err, "unable to write header for %s", name)
+	}
+	if _, err := tw.Write(bs); err != nil {
+		return sdk.WrapError(err, "unable to write value for %s", name)
+	}
+	return nil
+}
+
+// UntarProjectBundle returns a project bundle from given tar.
+func UntarProjectBundle(ctx context.Context, tr *tar.Reader) (ProjectBundle, error) {
+	var res ProjectBundle
+	var hasProject bool
+	mError := new(sdk.MultiError)
+
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return res, sdk.NewErrorWithStack(err, sdk.NewErrorFrom(sdk.ErrWrongRequest, "unable to read tar file"))
+		}
+
+		log.Debug("UntarProjectBundle> Reading %s", hdr.Name)
+
+		buff := new(bytes.Buffer)
+		if _, err := io.Copy(buff, tr); err != nil {
+			return res, sdk.NewErrorWithStack(err, sdk.NewErrorFrom(sdk.ErrWrongRequest, "unable to read tar file"))
+		}
+
+		format, err := GetFormatFromPath(hdr.Name)
+		if err != nil {
+			return res, err
+		}
+
+		b := buff.Bytes()
+		name := path.Clean(hdr.Name)
+		switch dir := path.Dir(name); {
+		case name == ProjectBundleProjectFile:
+			if err := Unmarshal(b, format, &res.Project); err != nil {
+				mError.Append(sdk.NewErrorFrom(err, "unable to unmarshal project %s", hdr.Name))
+				continue
+			}
+			hasProject = true
+		case dir == ProjectBundleApplicationsDir:
+			var app Application
+			if err := Unmarshal(b, format, &app); err != nil {
+				mError.Append(sdk.NewErrorFrom(err, "unable to unmarshal application %s", hdr.Name))
+				continue
+			}
+			res.Applications = append(res.Applications, app)
+		case dir == ProjectBundleEnvironmentsDir:
+			var env Environment
+			if err := Unmarshal(b, format, &env); err != nil {
+				mError.Append(sdk.NewErrorFrom(err, "unable to unmarshal environment %s", hdr.Name))
+				continue
+			}
+			res.Environments = append(res.Environments, env)
+		case dir == ProjectBundlePipelinesDir:
+			var pip PipelineV1
+			if err := Unmarshal(b, format, &pip); err != nil {
+				mError.Append(sdk.NewErrorFrom(err, "unable to unmarshal pipeline %s", hdr.Name))
+				continue
+			}
+			res.Pipelines = append(res.Pipelines, pip)
+		case dir == ProjectBundleWorkflowsDir:
+			var tmp TemplateInstance
+			if UnmarshalStrict(b, format, &tmp) == nil && tmp.From != "" {
+				res.Workflows = append(res.Workflows, WorkflowComponents{Template: tmp})
+				continue
+			}
+			w, err := UnmarshalWorkflow(b, format)
+			if err != nil {
+				mError.Append(sdk.NewErrorFrom(err, "unable to unmarshal workflow %s", hdr.Name))
+				continue
+			}
+			res.Workflows = append(res.Workflows, WorkflowComponents{Workflow: w})
+		default:
+			mError.Append(sdk.NewErrorFrom(sdk.ErrWrongRequest, "unexpected file %s in project bundle", hdr.Name))
+		}
+	}
+
+	if !mError.IsEmpty() {
+		return res, sdk.NewError(sdk.ErrWrongRequest, mError)
+	}
+	if !hasProject {
+		return res, sdk.NewErrorFrom(sdk.ErrWrongRequest, "missing %s file in project bundle", ProjectBundleProjectFile)
+	}
+
+	return res, nil
+}
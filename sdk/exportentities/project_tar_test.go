@@ -0,0 +1,69 @@
+package exportentities_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/exportentities"
+	v2 "github.com/ovh/cds/sdk/exportentities/v2"
+)
+
+func TestTarAndUntarProjectBundle(t *testing.T) {
+	b := exportentities.ProjectBundle{
+		Project: exportentities.Project{
+			Version: exportentities.ProjectVersion1,
+			Key:     "PROJ",
+			Name:    "My project",
+			Variables: map[string]exportentities.VariableValue{
+				"foo": {Type: sdk.StringVariable, Value: "bar"},
+			},
+			Permissions: map[string]int{"my-group": sdk.PermissionReadWriteExecute},
+			Salt:        "c2FsdA==",
+		},
+		Applications: []exportentities.Application{{Name: "my-app"}},
+		Environments: []exportentities.Environment{{Name: "my-env"}},
+		Pipelines:    []exportentities.PipelineV1{{Name: "my-pipeline"}},
+		Workflows: []exportentities.WorkflowComponents{
+			{Workflow: v2.Workflow{Version: exportentities.WorkflowVersion2, Name: "my-workflow", Workflow: map[string]v2.NodeEntry{"my-pipeline": {PipelineName: "my-pipeline"}}}},
+			{Template: exportentities.TemplateInstance{Name: "my-templated-workflow", From: "my-group/my-template"}},
+		},
+	}
+
+	buf := new(bytes.Buffer)
+	require.NoError(t, exportentities.TarProjectBundle(context.TODO(), b, buf))
+
+	res, err := exportentities.UntarProjectBundle(context.TODO(), tar.NewReader(buf))
+	require.NoError(t, err)
+	assert.Equal(t, b.Project, res.Project)
+	require.Len(t, res.Applications, 1)
+	assert.Equal(t, "my-app", res.Applications[0].Name)
+	require.Len(t, res.Environments, 1)
+	assert.Equal(t, "my-env", res.Environments[0].Name)
+	require.Len(t, res.Pipelines, 1)
+	assert.Equal(t, "my-pipeline", res.Pipelines[0].Name)
+	require.Len(t, res.Workflows, 2)
+	require.NotNil(t, res.Workflows[0].Workflow)
+	assert.Equal(t, "my-workflow", res.Workflows[0].Workflow.GetName())
+	assert.Equal(t, "my-templated-workflow", res.Workflows[1].Template.Name)
+	assert.Equal(t, "my-group/my-template", res.Workflows[1].Template.From)
+}
+
+func TestUntarProjectBundleWithoutProject(t *testing.T) {
+	out := new(bytes.Buffer)
+	tw := tar.NewWriter(out)
+	btes := []byte("name: my-app\n")
+	require.NoError(t, tw.WriteHeader(&tar.Header{Name: "applications/my-app.app.yml", Mode: 0644, Size: int64(len(btes))}))
+	_, err := tw.Write(btes)
+	require.NoError(t, err)
+	require.NoError(t, tw.Close())
+
+	_, err = exportentities.UntarProjectBundle(context.TODO(), tar.NewReader(out))
+	require.Error(t, err)
+	assert.True(t, sdk.ErrorIs(err, sdk.ErrWrongRequest))
+}
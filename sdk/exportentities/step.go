@@ -49,6 +49,74 @@ func newStep(act sdk.Action) Step {
 			if minimum != nil {
 				s.Coverage.Minimum = minimum.Value
 			}
+			minimumBranch := sdk.ParameterFind(act.Parameters, "minimumBranch")
+			if minimumBranch != nil {
+				s.Coverage.MinimumBranch = minimumBranch.Value
+			}
+			maximumDrop := sdk.ParameterFind(act.Parameters, "maximumDrop")
+			if maximumDrop != nil {
+				s.Coverage.MaximumDrop = maximumDrop.Value
+			}
+		case sdk.SARIFAction:
+			s.SARIF = &StepSARIF{}
+			path := sdk.ParameterFind(act.Parameters, "path")
+			if path != nil {
+				s.SARIF.Path = path.Value
+			}
+			failOnSeverity := sdk.ParameterFind(act.Parameters, "failOnSeverity")
+			if failOnSeverity != nil {
+				s.SARIF.FailOnSeverity = failOnSeverity.Value
+			}
+			newOnly := sdk.ParameterFind(act.Parameters, "newOnly")
+			if newOnly != nil {
+				s.SARIF.NewOnly = newOnly.Value
+			}
+		case sdk.DockerRunAction:
+			s.DockerRun = &StepDockerRun{}
+			image := sdk.ParameterFind(act.Parameters, "image")
+			if image != nil {
+				s.DockerRun.Image = image.Value
+			}
+			command := sdk.ParameterFind(act.Parameters, "command")
+			if command != nil {
+				s.DockerRun.Command = command.Value
+			}
+			entrypoint := sdk.ParameterFind(act.Parameters, "entrypoint")
+			if entrypoint != nil {
+				s.DockerRun.Entrypoint = entrypoint.Value
+			}
+			env := sdk.ParameterFind(act.Parameters, "env")
+			if env != nil {
+				s.DockerRun.Env = env.Value
+			}
+			workspace := sdk.ParameterFind(act.Parameters, "workspace")
+			if workspace != nil && workspace.Value != "/workspace" {
+				s.DockerRun.Workspace = workspace.Value
+			}
+		case sdk.ChildPipelinesAction:
+			s.ChildPipelines = &StepChildPipelines{}
+			path := sdk.ParameterFind(act.Parameters, "path")
+			if path != nil {
+				s.ChildPipelines.Path = path.Value
+			}
+		case sdk.CacheAction:
+			s.Cache = &StepCache{}
+			key := sdk.ParameterFind(act.Parameters, "key")
+			if key != nil {
+				s.Cache.Key = key.Value
+			}
+			restoreKeys := sdk.ParameterFind(act.Parameters, "restoreKeys")
+			if restoreKeys != nil {
+				s.Cache.RestoreKeys = restoreKeys.Value
+			}
+			path := sdk.ParameterFind(act.Parameters, "path")
+			if path != nil {
+				s.Cache.Path = path.Value
+			}
+			destination := sdk.ParameterFind(act.Parameters, "destination")
+			if destination != nil {
+				s.Cache.Destination = destination.Value
+			}
 		case sdk.ArtifactDownload:
 			s.ArtifactDownload = &StepArtifactDownload{}
 			path := sdk.ParameterFind(act.Parameters, "path")
@@ -247,9 +315,40 @@ type StepCustom map[string]StepParameters
 
 // StepCoverage represents exported coverage step.
 type StepCoverage struct {
-	Format  string `json:"format,omitempty" yaml:"format,omitempty"`
-	Minimum string `json:"minimum,omitempty" yaml:"minimum,omitempty"`
-	Path    string `json:"path,omitempty" yaml:"path,omitempty"`
+	Format        string `json:"format,omitempty" yaml:"format,omitempty"`
+	Minimum       string `json:"minimum,omitempty" yaml:"minimum,omitempty"`
+	MinimumBranch string `json:"minimumBranch,omitempty" yaml:"minimumBranch,omitempty"`
+	MaximumDrop   string `json:"maximumDrop,omitempty" yaml:"maximumDrop,omitempty"`
+	Path          string `json:"path,omitempty" yaml:"path,omitempty"`
+}
+
+// StepCache represents exported cache step.
+type StepCache struct {
+	Destination string `json:"destination,omitempty" yaml:"destination,omitempty"`
+	Key         string `json:"key,omitempty" yaml:"key,omitempty" jsonschema:"required"`
+	Path        string `json:"path,omitempty" yaml:"path,omitempty" jsonschema:"required"`
+	RestoreKeys string `json:"restoreKeys,omitempty" yaml:"restoreKeys,omitempty"`
+}
+
+// StepSARIF represents exported SARIF step.
+type StepSARIF struct {
+	FailOnSeverity string `json:"failOnSeverity,omitempty" yaml:"failOnSeverity,omitempty"`
+	NewOnly        string `json:"newOnly,omitempty" yaml:"newOnly,omitempty"`
+	Path           string `json:"path,omitempty" yaml:"path,omitempty" jsonschema:"required"`
+}
+
+// StepDockerRun represents exported docker run step.
+type StepDockerRun struct {
+	Command    string `json:"command,omitempty" yaml:"command,omitempty"`
+	Entrypoint string `json:"entrypoint,omitempty" yaml:"entrypoint,omitempty"`
+	Env        string `json:"env,omitempty" yaml:"env,omitempty"`
+	Image      string `json:"image,omitempty" yaml:"image,omitempty" jsonschema:"required"`
+	Workspace  string `json:"workspace,omitempty" yaml:"workspace,omitempty"`
+}
+
+// StepChildPipelines represents exported child pipelines step.
+type StepChildPipelines struct {
+	Path string `json:"path,omitempty" yaml:"path,omitempty" jsonschema:"required"`
 }
 
 // StepArtifactDownload represents exported artifact download step.
@@ -330,6 +429,10 @@ type Step struct {
 	StepCustom       `json:"-" yaml:",inline"`
 	Script           interface{}           `json:"script,omitempty" yaml:"script,omitempty" jsonschema:"oneof_type=string;array,oneof_required=actionScript" jsonschema_description:"Script.\nhttps://ovh.github.io/cds/docs/actions/builtin-script"`
 	Coverage         *StepCoverage         `json:"coverage,omitempty" yaml:"coverage,omitempty" jsonschema:"oneof_required=actionCoverage" jsonschema_description:"Parse coverage report.\nhttps://ovh.github.io/cds/docs/actions/builtin-coverage"`
+	Cache            *StepCache            `json:"cache,omitempty" yaml:"cache,omitempty" jsonschema:"oneof_required=actionCache" jsonschema_description:"Restore a cache and save it at the end of the job.\nhttps://ovh.github.io/cds/docs/actions/builtin-cache"`
+	SARIF            *StepSARIF            `json:"sarif,omitempty" yaml:"sarif,omitempty" jsonschema:"oneof_required=actionSARIF" jsonschema_description:"Import static analysis and security findings from SARIF files.\nhttps://ovh.github.io/cds/docs/actions/builtin-sarif"`
+	DockerRun        *StepDockerRun        `json:"dockerRun,omitempty" yaml:"dockerRun,omitempty" jsonschema:"oneof_required=actionDockerRun" jsonschema_description:"Run a container with the workspace mounted.\nhttps://ovh.github.io/cds/docs/actions/builtin-dockerrun"`
+	ChildPipelines   *StepChildPipelines   `json:"childPipelines,omitempty" yaml:"childPipelines,omitempty" jsonschema:"oneof_required=actionChildPipelines" jsonschema_description:"Add pipelines generated in the workspace as children of the current pipeline.\nhttps://ovh.github.io/cds/docs/actions/builtin-childpipelines"`
 	ArtifactDownload *StepArtifactDownload `json:"artifactDownload,omitempty" yaml:"artifactDownload,omitempty" jsonschema:"oneof_required=actionArtifactDownload" jsonschema_description:"Download artifacts in workspace.\nhttps://ovh.github.io/cds/docs/actions/builtin-artifact-download"`
 	ArtifactUpload   *StepArtifactUpload   `json:"artifactUpload,omitempty" yaml:"artifactUpload,omitempty" jsonschema:"oneof_required=actionArtifactUpload" jsonschema_description:"Upload artifacts from workspace.\nhttps://ovh.github.io/cds/docs/actions/builtin-artifact-upload"`
 	ServeStaticFiles *StepServeStaticFiles `json:"serveStaticFiles,omitempty" yaml:"serveStaticFiles,omitempty" jsonschema:"oneof_required=actionServeStaticFiles" jsonschema_description:"Serve static files.\nhttps://ovh.github.io/cds/docs/actions/builtin-serve-static-files"`
@@ -456,6 +559,18 @@ func (s Step) IsValid() bool {
 	if s.isCoverage() {
 		count++
 	}
+	if s.isCache() {
+		count++
+	}
+	if s.isSARIF() {
+		count++
+	}
+	if s.isDockerRun() {
+		count++
+	}
+	if s.isChildPipelines() {
+		count++
+	}
 	if s.isScript() {
 		count++
 	}
@@ -493,6 +608,14 @@ func (s Step) toAction() (*sdk.Action, error) {
 		a = s.asDeployApplication()
 	} else if s.isCoverage() {
 		a, err = s.asCoverage()
+	} else if s.isCache() {
+		a, err = s.asCache()
+	} else if s.isSARIF() {
+		a, err = s.asSARIF()
+	} else if s.isDockerRun() {
+		a, err = s.asDockerRun()
+	} else if s.isChildPipelines() {
+		a, err = s.asChildPipelines()
 	} else if s.isScript() {
 		a, err = s.asScript()
 	} else {
@@ -638,6 +761,70 @@ func (s Step) asCoverage() (sdk.Action, error) {
 	return a, nil
 }
 
+func (s Step) isCache() bool { return s.Cache != nil }
+
+func (s Step) asCache() (sdk.Action, error) {
+	var a sdk.Action
+	m, err := stepToMap(s.Cache)
+	if err != nil {
+		return a, err
+	}
+	a = sdk.Action{
+		Name:       sdk.CacheAction,
+		Type:       sdk.BuiltinAction,
+		Parameters: sdk.ParametersFromMap(m),
+	}
+	return a, nil
+}
+
+func (s Step) isSARIF() bool { return s.SARIF != nil }
+
+func (s Step) asSARIF() (sdk.Action, error) {
+	var a sdk.Action
+	m, err := stepToMap(s.SARIF)
+	if err != nil {
+		return a, err
+	}
+	a = sdk.Action{
+		Name:       sdk.SARIFAction,
+		Type:       sdk.BuiltinAction,
+		Parameters: sdk.ParametersFromMap(m),
+	}
+	return a, nil
+}
+
+func (s Step) isDockerRun() bool { return s.DockerRun != nil }
+
+func (s Step) asDockerRun() (sdk.Action, error) {
+	var a sdk.Action
+	m, err := stepToMap(s.DockerRun)
+	if err != nil {
+		return a, err
+	}
+	a = sdk.Action{
+		Name:       sdk.DockerRunAction,
+		Type:       sdk.BuiltinAction,
+		Parameters: sdk.ParametersFromMap(m),
+	}
+	return a, nil
+}
+
+func (s Step) isChildPipelines() bool { return s.ChildPipelines != nil }
+
+func (s Step) asChildPipelines() (sdk.Action, error) {
+	var a sdk.Action
+	m, err := stepToMap(s.ChildPipelines)
+	if err != nil {
+		return a, err
+	}
+	a = sdk.Action{
+		Name:       sdk.ChildPipelinesAction,
+		Type:       sdk.BuiltinAction,
+		Parameters: sdk.ParametersFromMap(m),
+	}
+	return a, nil
+}
+
 func (s Step) isDeploy() bool { return s.Deploy != nil }
 
 func (s Step) asDeployApplication() sdk.Action {
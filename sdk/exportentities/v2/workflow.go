@@ -23,11 +23,12 @@ type Workflow struct {
 	Hooks    map[string][]HookEntry `json:"hooks,omitempty" yaml:"hooks,omitempty" jsonschema_description:"Workflow hooks list."`
 
 	// extra workflow data
-	Permissions   map[string]int      `json:"permissions,omitempty" yaml:"permissions,omitempty" jsonschema_description:"The permissions for the workflow (ex: myGroup: 7).\nhttps://ovh.github.io/cds/docs/concepts/permissions"`
-	Metadata      map[string]string   `json:"metadata,omitempty" yaml:"metadata,omitempty"`
-	PurgeTags     []string            `json:"purge_tags,omitempty" yaml:"purge_tags,omitempty"`
-	Notifications []NotificationEntry `json:"notifications,omitempty" yaml:"notifications,omitempty"` // This is used when the workflow have only one pipeline
-	HistoryLength *int64              `json:"history_length,omitempty" yaml:"history_length,omitempty"`
+	Permissions   map[string]int           `json:"permissions,omitempty" yaml:"permissions,omitempty" jsonschema_description:"The permissions for the workflow (ex: myGroup: 7).\nhttps://ovh.github.io/cds/docs/concepts/permissions"`
+	Metadata      map[string]string        `json:"metadata,omitempty" yaml:"metadata,omitempty"`
+	PurgeTags     []string                 `json:"purge_tags,omitempty" yaml:"purge_tags,omitempty"`
+	PurgePolicy   *sdk.WorkflowPurgePolicy `json:"purge_policy,omitempty" yaml:"purge_policy,omitempty" jsonschema_description:"Rules to keep runs when purging the workflow history, other runs beyond history_length are deleted."`
+	Notifications []NotificationEntry      `json:"notifications,omitempty" yaml:"notifications,omitempty"` // This is used when the workflow have only one pipeline
+	HistoryLength *int64                   `json:"history_length,omitempty" yaml:"history_length,omitempty"`
 }
 
 // NodeEntry represents a node as code
@@ -118,6 +119,10 @@ func NewWorkflow(ctx context.Context, w sdk.Workflow, version string, opts ...Ex
 	}
 
 	exportedWorkflow.PurgeTags = w.PurgeTags
+	if !w.PurgePolicy.IsEmpty() {
+		policy := w.PurgePolicy
+		exportedWorkflow.PurgePolicy = &policy
+	}
 
 	nodes := w.WorkflowData.Array()
 
@@ -377,6 +382,9 @@ func (w Workflow) GetWorkflow() (*sdk.Workflow, error) {
 		return nil, sdk.WrapError(err, "unable to check dependencies")
 	}
 	wf.PurgeTags = w.PurgeTags
+	if w.PurgePolicy != nil {
+		wf.PurgePolicy = *w.PurgePolicy
+	}
 	if len(w.Metadata) > 0 {
 		wf.Metadata = make(map[string]string, len(w.Metadata))
 		for k, v := range w.Metadata {
@@ -0,0 +1,128 @@
+package sdk
+
+import (
+	"sort"
+	"strings"
+)
+
+// GroupSyncConfiguration describes how external groups given by an auth driver
+// are synchronized with CDS groups.
+type GroupSyncConfiguration struct {
+	Enabled         bool               `toml:"enabled" default:"false" json:"enabled"`
+	DryRun          bool               `toml:"dryRun" default:"false" comment:"If true, changes are computed and logged but not applied" json:"dryRun"`
+	CreateGroups    bool               `toml:"createGroups" default:"false" comment:"Create mapped CDS groups if they don't exist" json:"createGroups"`
+	IntervalMinutes int64              `toml:"intervalMinutes" default:"0" comment:"Periodic synchronization interval in minutes for drivers that support it (LDAP), 0 means synchronization at signin only" json:"intervalMinutes"`
+	Mappings        []GroupSyncMapping `toml:"mappings" comment:"List of mappings between external groups and CDS groups" json:"mappings"`
+}
+
+// GroupSyncMapping maps an external group to a CDS group. If admin is true,
+// members of the external group will be set as admin of the CDS group.
+type GroupSyncMapping struct {
+	External string `toml:"external" json:"external"`
+	Group    string `toml:"group" json:"group"`
+	Admin    bool   `toml:"admin" json:"admin"`
+}
+
+// IsValid returns an error if the configuration is not valid.
+func (c GroupSyncConfiguration) IsValid() error {
+	if !c.Enabled {
+		return nil
+	}
+	if c.IntervalMinutes < 0 {
+		return NewErrorFrom(ErrWrongRequest, "invalid group sync interval %d", c.IntervalMinutes)
+	}
+	for _, m := range c.Mappings {
+		if m.External == "" {
+			return NewErrorFrom(ErrWrongRequest, "invalid group sync mapping, external group should be given")
+		}
+		if err := (Group{Name: m.Group}).IsValid(); err != nil {
+			return NewErrorFrom(ErrWrongRequest, "invalid group sync mapping for external group %s: %v", m.External, err)
+		}
+	}
+	return nil
+}
+
+// ManagedGroups returns the sorted names of CDS groups that are managed by the mappings.
+func (c GroupSyncConfiguration) ManagedGroups() []string {
+	m := make(map[string]struct{}, len(c.Mappings))
+	for _, mapping := range c.Mappings {
+		m[mapping.Group] = struct{}{}
+	}
+	res := make([]string, 0, len(m))
+	for name := range m {
+		res = append(res, name)
+	}
+	sort.Strings(res)
+	return res
+}
+
+// ComputeGroups returns for given external groups the CDS groups that a user should
+// be member of, with the admin flag as value. External groups are compared case insensitively.
+func (c GroupSyncConfiguration) ComputeGroups(externalGroups []string) map[string]bool {
+	res := make(map[string]bool)
+	for _, mapping := range c.Mappings {
+		for _, ext := range externalGroups {
+			if !strings.EqualFold(ext, mapping.External) {
+				continue
+			}
+			res[mapping.Group] = res[mapping.Group] || mapping.Admin
+		}
+	}
+	return res
+}
+
+// GroupSyncChangeType is the type of change applied by a group synchronization.
+type GroupSyncChangeType string
+
+// Group sync change types.
+const (
+	GroupSyncChangeCreateGroup  GroupSyncChangeType = "create-group"
+	GroupSyncChangeAddMember    GroupSyncChangeType = "add-member"
+	GroupSyncChangeRemoveMember GroupSyncChangeType = "remove-member"
+	GroupSyncChangeSetAdmin     GroupSyncChangeType = "set-admin"
+	GroupSyncChangeUnsetAdmin   GroupSyncChangeType = "unset-admin"
+)
+
+// GroupSyncChange describes a change on a group for a user.
+type GroupSyncChange struct {
+	Type      GroupSyncChangeType `json:"type" cli:"type"`
+	GroupName string              `json:"group_name" cli:"group_name"`
+	Username  string              `json:"username" cli:"username"`
+	Skipped   string              `json:"skipped,omitempty" cli:"skipped"`
+}
+
+// GroupSyncReport contains all changes computed by a group synchronization.
+type GroupSyncReport struct {
+	ConsumerType AuthConsumerType  `json:"consumer_type"`
+	DryRun       bool              `json:"dry_run"`
+	Changes      []GroupSyncChange `json:"changes"`
+	Errors       []string          `json:"errors,omitempty"`
+}
+
+// ComputeGroupSyncChanges returns the changes to apply on user's groups. Wanted and current
+// memberships are given as maps of group name to admin flag, existing contains names of all
+// managed groups that already exist. Only managed groups are considered.
+func ComputeGroupSyncChanges(username string, managed []string, wanted, current map[string]bool, existing map[string]struct{}) []GroupSyncChange {
+	var changes []GroupSyncChange
+	for _, name := range managed {
+		wantedAdmin, isWanted := wanted[name]
+		currentAdmin, isMember := current[name]
+		switch {
+		case isWanted && !isMember:
+			if _, ok := existing[name]; !ok {
+				changes = append(changes, GroupSyncChange{Type: GroupSyncChangeCreateGroup, GroupName: name, Username: username})
+			}
+			changes = append(changes, GroupSyncChange{Type: GroupSyncChangeAddMember, GroupName: name, Username: username})
+			if wantedAdmin {
+				changes = append(changes, GroupSyncChange{Type: GroupSyncChangeSetAdmin, GroupName: name, Username: username})
+			}
+		case isWanted && isMember && wantedAdmin && !currentAdmin:
+			changes = append(changes, GroupSyncChange{Type: GroupSyncChangeSetAdmin, GroupName: name, Username: username})
+		case isWanted && isMember && !wantedAdmin && currentAdmin:
+			changes = append(changes, GroupSyncChange{Type: GroupSyncChangeUnsetAdmin, GroupName: name, Username: username})
+		case !isWanted && isMember:
+			changes = append(changes, GroupSyncChange{Type: GroupSyncChangeRemoveMember, GroupName: name, Username: username})
+		}
+	}
+	return changes
+}
@@ -0,0 +1,47 @@
+package sdk_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func TestGroupSyncConfigurationComputeGroups(t *testing.T) {
+	cfg := sdk.GroupSyncConfiguration{
+		Enabled: true,
+		Mappings: []sdk.GroupSyncMapping{
+			{External: "devs", Group: "team-a"},
+			{External: "team-a-leads", Group: "team-a", Admin: true},
+			{External: "ops", Group: "team-ops"},
+		},
+	}
+	assert.NoError(t, cfg.IsValid())
+	assert.Equal(t, []string{"team-a", "team-ops"}, cfg.ManagedGroups())
+
+	assert.Equal(t, map[string]bool{"team-a": false}, cfg.ComputeGroups([]string{"DEVS", "unknown"}))
+	assert.Equal(t, map[string]bool{"team-a": true, "team-ops": false}, cfg.ComputeGroups([]string{"team-a-leads", "devs", "ops"}))
+	assert.Empty(t, cfg.ComputeGroups(nil))
+
+	cfg.Mappings = append(cfg.Mappings, sdk.GroupSyncMapping{External: "", Group: "team-b"})
+	assert.Error(t, cfg.IsValid())
+}
+
+func TestComputeGroupSyncChanges(t *testing.T) {
+	managed := []string{"a", "b", "c", "d", "e"}
+	wanted := map[string]bool{"a": false, "b": true, "c": false, "e": true}
+	current := map[string]bool{"b": false, "c": true, "d": false, "other": true}
+	existing := map[string]struct{}{"b": {}, "c": {}, "d": {}, "e": {}}
+
+	changes := sdk.ComputeGroupSyncChanges("john", managed, wanted, current, existing)
+	assert.Equal(t, []sdk.GroupSyncChange{
+		{Type: sdk.GroupSyncChangeCreateGroup, GroupName: "a", Username: "john"},
+		{Type: sdk.GroupSyncChangeAddMember, GroupName: "a", Username: "john"},
+		{Type: sdk.GroupSyncChangeSetAdmin, GroupName: "b", Username: "john"},
+		{Type: sdk.GroupSyncChangeUnsetAdmin, GroupName: "c", Username: "john"},
+		{Type: sdk.GroupSyncChangeRemoveMember, GroupName: "d", Username: "john"},
+		{Type: sdk.GroupSyncChangeAddMember, GroupName: "e", Username: "john"},
+		{Type: sdk.GroupSyncChangeSetAdmin, GroupName: "e", Username: "john"},
+	}, changes)
+}
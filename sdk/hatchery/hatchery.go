@@ -141,7 +141,11 @@ func Create(ctx context.Context, h Interface) error {
 			var traceEnded *struct{}
 			currentCtx, currentCancel := context.WithTimeout(ctx, 10*time.Minute)
 			if val, has := j.Header.Get(telemetry.SampledHeader); has && val == "1" {
-				currentCtx, _ = telemetry.New(currentCtx, h, "hatchery.JobReceive", trace.AlwaysSample(), trace.SpanKindServer)
+				var span *trace.Span
+				currentCtx, span = telemetry.NewWithRemoteParent(currentCtx, h, "hatchery.JobReceive", j.Header, trace.SpanKindServer)
+				if span != nil {
+					currentCtx = context.WithValue(currentCtx, telemetry.ContextMainSpan, span)
+				}
 
 				r, _ := j.Header.Get(sdk.WorkflowRunHeader)
 				w, _ := j.Header.Get(sdk.WorkflowHeader)
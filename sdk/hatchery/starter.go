@@ -148,8 +148,13 @@ func spawnWorkerForJob(ctx context.Context, h Interface, j workerStarterRequest)
 	bookedInfos, err := h.CDSClient().QueueJobBook(ctxQueueJobBook, j.id)
 	if err != nil {
 		next()
-		// perhaps already booked by another hatchery
-		log.Info(ctx, "hatchery> spawnWorkerForJob> %d - cannot book job %d: %s", j.timestamp, j.id, err)
+		if sdk.ErrorIs(err, sdk.ErrServiceUnavailable) {
+			// CDS is in maintenance drain, no worker should be spawned
+			log.Info(ctx, "hatchery> spawnWorkerForJob> %d - cannot book job %d, maintenance in progress: %s", j.timestamp, j.id, err)
+		} else {
+			// perhaps already booked by another hatchery
+			log.Info(ctx, "hatchery> spawnWorkerForJob> %d - cannot book job %d: %s", j.timestamp, j.id, err)
+		}
 		cancel()
 		return false
 	}
@@ -213,6 +218,11 @@ func spawnWorkerForJob(ctx context.Context, h Interface, j workerStarterRequest)
 		return false
 	}
 
+	telemetry.Record(telemetry.ContextWithTag(ctxJob,
+		telemetry.TagProjectKey, bookedInfos.ProjectKey,
+		telemetry.TagWorkerModel, modelName,
+	), GetMetrics().SpawnedWorkersByProject, 1)
+
 	ctxSendSpawnInfo, next = telemetry.Span(ctxJob, "hatchery.SendSpawnInfo", telemetry.Tag("msg", sdk.MsgSpawnInfoHatcheryStartsSuccessfully.ID))
 	SendSpawnInfo(ctxSendSpawnInfo, h, j.id, sdk.SpawnMsg{
 		ID: sdk.MsgSpawnInfoHatcheryStartsSuccessfully.ID,
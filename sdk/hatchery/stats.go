@@ -28,6 +28,7 @@ func initMetrics(ctx context.Context) error {
 		metrics.Jobs = stats.Int64("cds/jobs", "number of analyzed jobs", stats.UnitDimensionless)
 		metrics.JobsWebsocket = stats.Int64("cds/jobs_websocket", "number of analyzed jobs from SSE", stats.UnitDimensionless)
 		metrics.SpawnedWorkers = stats.Int64("cds/spawned_workers", "number of spawned workers", stats.UnitDimensionless)
+		metrics.SpawnedWorkersByProject = stats.Int64("cds/spawned_workers_by_project", "number of spawned workers for the jobs of a project", stats.UnitDimensionless)
 		metrics.PendingWorkers = stats.Int64("cds/pending_workers", "number of pending workers", stats.UnitDimensionless)
 		metrics.RegisteringWorkers = stats.Int64("cds/registering_workers", "number of registering workers", stats.UnitDimensionless)
 		metrics.WaitingWorkers = stats.Int64("cds/waiting_workers", "number of waiting workers", stats.UnitDimensionless)
@@ -36,10 +37,12 @@ func initMetrics(ctx context.Context) error {
 		metrics.DisabledWorkers = stats.Int64("cds/disabled_workers", "number of disabled workers", stats.UnitDimensionless)
 
 		tags := []tag.Key{telemetry.MustNewKey(telemetry.TagServiceType), telemetry.MustNewKey(telemetry.TagServiceName)}
+		tagsProject := append(tags, telemetry.MustNewKey(telemetry.TagProjectKey), telemetry.MustNewKey(telemetry.TagWorkerModel))
 		err = telemetry.RegisterView(ctx,
 			telemetry.NewViewCount("cds/hatchery/jobs_count", metrics.Jobs, tags),
 			telemetry.NewViewCount("cds/hatchery/jobs_websocket_count", metrics.JobsWebsocket, tags),
 			telemetry.NewViewCount("cds/hatchery/spawned_worker_count", metrics.SpawnedWorkers, tags),
+			telemetry.NewViewCount("cds/hatchery/spawned_worker_by_project_count", metrics.SpawnedWorkersByProject, tagsProject),
 			telemetry.NewViewLast("cds/hatchery/pending_workers", metrics.PendingWorkers, tags),
 			telemetry.NewViewLast("cds/hatchery/registering_workers", metrics.RegisteringWorkers, tags),
 			telemetry.NewViewLast("cds/hatchery/waiting_workers", metrics.WaitingWorkers, tags),
@@ -94,15 +94,16 @@ type InterfaceWithModels interface {
 }
 
 type Metrics struct {
-	Jobs               *stats.Int64Measure
-	JobsWebsocket      *stats.Int64Measure
-	SpawnedWorkers     *stats.Int64Measure
-	PendingWorkers     *stats.Int64Measure
-	RegisteringWorkers *stats.Int64Measure
-	CheckingWorkers    *stats.Int64Measure
-	WaitingWorkers     *stats.Int64Measure
-	BuildingWorkers    *stats.Int64Measure
-	DisabledWorkers    *stats.Int64Measure
+	Jobs                    *stats.Int64Measure
+	JobsWebsocket           *stats.Int64Measure
+	SpawnedWorkers          *stats.Int64Measure
+	SpawnedWorkersByProject *stats.Int64Measure
+	PendingWorkers          *stats.Int64Measure
+	RegisteringWorkers      *stats.Int64Measure
+	CheckingWorkers         *stats.Int64Measure
+	WaitingWorkers          *stats.Int64Measure
+	BuildingWorkers         *stats.Int64Measure
+	DisabledWorkers         *stats.Int64Measure
 }
 
 type JobIdentifiers struct {
@@ -19,6 +19,8 @@ const (
 	HookConfigWebHookID           = "webHookID"
 	HookConfigVCSServer           = "vcsServer"
 	HookConfigEventFilter         = "eventFilter"
+	HookConfigBranchFilter        = "branchFilter"
+	HookConfigPathFilter          = "pathFilter"
 	HookConfigRepoFullName        = "repoFullName"
 	HookConfigModelType           = "model_type"
 	HookConfigModelName           = "model_name"
@@ -150,6 +152,16 @@ var (
 				Configurable: true,
 				Type:         HookConfigTypeMultiChoice,
 			},
+			HookConfigBranchFilter: {
+				Value:        "",
+				Configurable: true,
+				Type:         HookConfigTypeString,
+			},
+			HookConfigPathFilter: {
+				Value:        "",
+				Configurable: true,
+				Type:         HookConfigTypeString,
+			},
 		},
 	}
 
@@ -165,6 +177,11 @@ var (
 				Configurable: true,
 				Type:         HookConfigTypeString,
 			},
+			HookConfigBranchFilter: {
+				Value:        "",
+				Configurable: true,
+				Type:         HookConfigTypeString,
+			},
 		},
 	}
 
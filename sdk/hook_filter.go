@@ -0,0 +1,108 @@
+package sdk
+
+import (
+	"regexp"
+	"strings"
+)
+
+// HookFilter filters the repository events that trigger a workflow on branch names and changed files.
+// Filters are glob patterns separated by ';', '*' matches any characters except '/', '**' matches
+// any characters and a pattern starting with '!' excludes matching values.
+type HookFilter struct {
+	Branches []string
+	Paths    []string
+}
+
+// NewHookFilter returns the filters configured on a repository webhook or a git poller.
+func NewHookFilter(cfg WorkflowNodeHookConfig) HookFilter {
+	return HookFilter{
+		Branches: splitHookFilter(cfg[HookConfigBranchFilter].Value),
+		Paths:    splitHookFilter(cfg[HookConfigPathFilter].Value),
+	}
+}
+
+func splitHookFilter(s string) []string {
+	var res []string
+	for _, p := range strings.Split(s, ";") {
+		if p = strings.TrimSpace(p); p != "" {
+			res = append(res, p)
+		}
+	}
+	return res
+}
+
+// MatchBranch returns true if given branch matches the branch filter. Events without branch, like tags, always match.
+func (f HookFilter) MatchBranch(branch string) bool {
+	if len(f.Branches) == 0 || branch == "" {
+		return true
+	}
+	return matchGlobs(f.Branches, []string{branch})
+}
+
+// MatchPaths returns true if one of the changed files matches the path filter.
+func (f HookFilter) MatchPaths(files []string) bool {
+	if len(f.Paths) == 0 {
+		return true
+	}
+	return matchGlobs(f.Paths, files)
+}
+
+// matchGlobs returns true if one of the values matches an including pattern and no excluding pattern. If there
+// are only excluding patterns, any value that is not excluded matches.
+func matchGlobs(patterns, values []string) bool {
+	var includes, excludes []*regexp.Regexp
+	for _, p := range patterns {
+		if strings.HasPrefix(p, "!") {
+			excludes = append(excludes, globToRegexp(strings.TrimPrefix(p, "!")))
+		} else {
+			includes = append(includes, globToRegexp(p))
+		}
+	}
+
+	matchAny := func(res []*regexp.Regexp, v string) bool {
+		for _, r := range res {
+			if r.MatchString(v) {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, v := range values {
+		if matchAny(excludes, v) {
+			continue
+		}
+		if len(includes) == 0 || matchAny(includes, v) {
+			return true
+		}
+	}
+	return false
+}
+
+func globToRegexp(pattern string) *regexp.Regexp {
+	var b strings.Builder
+	b.WriteString("^")
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; c {
+		case '*':
+			if i+1 < len(pattern) && pattern[i+1] == '*' {
+				i++
+				// "**/" also matches files at the root of the directory
+				if i+1 < len(pattern) && pattern[i+1] == '/' {
+					i++
+					b.WriteString("(.*/)?")
+				} else {
+					b.WriteString(".*")
+				}
+			} else {
+				b.WriteString("[^/]*")
+			}
+		case '?':
+			b.WriteString("[^/]")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	b.WriteString("$")
+	return regexp.MustCompile(b.String())
+}
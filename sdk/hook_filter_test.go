@@ -0,0 +1,38 @@
+package sdk
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHookFilter(t *testing.T) {
+	f := NewHookFilter(WorkflowNodeHookConfig{
+		HookConfigBranchFilter: {Value: "master; release/*"},
+		HookConfigPathFilter:   {Value: "services/api/**;**/go.mod;!**/*.md"},
+	})
+
+	require.True(t, f.MatchBranch("master"))
+	require.True(t, f.MatchBranch("release/1.0"))
+	require.False(t, f.MatchBranch("release/1.0/fix"))
+	require.False(t, f.MatchBranch("feature/foo"))
+	require.True(t, f.MatchBranch(""))
+
+	require.True(t, f.MatchPaths([]string{"services/api/main.go"}))
+	require.True(t, f.MatchPaths([]string{"go.mod"}))
+	require.True(t, f.MatchPaths([]string{"services/ui/go.mod"}))
+	require.False(t, f.MatchPaths([]string{"services/api/README.md"}))
+	require.False(t, f.MatchPaths([]string{"services/ui/main.go"}))
+	require.True(t, f.MatchPaths([]string{"services/ui/main.go", "services/api/main.go"}))
+	require.False(t, f.MatchPaths(nil))
+
+	// Only excluding patterns
+	f = NewHookFilter(WorkflowNodeHookConfig{HookConfigPathFilter: {Value: "!docs/**"}})
+	require.True(t, f.MatchPaths([]string{"docs/index.md", "main.go"}))
+	require.False(t, f.MatchPaths([]string{"docs/index.md"}))
+
+	// No filter
+	f = NewHookFilter(WorkflowNodeHookConfig{})
+	require.True(t, f.MatchBranch("feature/foo"))
+	require.True(t, f.MatchPaths(nil))
+}
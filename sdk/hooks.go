@@ -28,6 +28,7 @@ type TaskExecution struct {
 	ScheduledTask       *ScheduledTaskExecution `json:"scheduled_task,omitempty" cli:"-"`
 	GerritEvent         *GerritEventExecution   `json:"gerrit,omitempty" cli:"-"`
 	Status              string                  `json:"status" cli:"status"`
+	TraceHeaders        map[string]string       `json:"trace_headers,omitempty" cli:"-"`
 }
 
 // GerritEventExecution contains specific data for a gerrit event execution
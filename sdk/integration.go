@@ -12,6 +12,7 @@ const (
 	RabbitMQIntegrationModel      = "RabbitMQ"
 	OpenstackIntegrationModel     = "Openstack"
 	AWSIntegrationModel           = "AWS"
+	VaultIntegrationModel         = "Vault"
 	DefaultStorageIntegrationName = "shared.infra"
 )
 
@@ -22,6 +23,7 @@ var (
 		&RabbitMQIntegration,
 		&OpenstackIntegration,
 		&AWSIntegration,
+		&VaultIntegration,
 	}
 	// KafkaIntegration represents a kafka integration
 	KafkaIntegration = IntegrationModel{
@@ -140,6 +142,23 @@ var (
 		Disabled: false,
 		Hook:     false,
 	}
+	// VaultIntegration represents a HashiCorp Vault integration used to resolve vault variables
+	VaultIntegration = IntegrationModel{
+		Name:       VaultIntegrationModel,
+		Author:     "CDS",
+		Identifier: "github.com/ovh/cds/integration/builtin/vault",
+		Icon:       "",
+		DefaultConfig: IntegrationConfig{
+			"url": IntegrationConfigValue{
+				Type: IntegrationConfigTypeString,
+			},
+			"token": IntegrationConfigValue{
+				Type: IntegrationConfigTypePassword,
+			},
+		},
+		Disabled: false,
+		Hook:     false,
+	}
 )
 
 // IntegrationType represents all different type of integrations
@@ -1,5 +1,17 @@
 package sdk
 
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// JobMatrixMaxCombinations is the maximum number of job runs that can be created from a job matrix, excluded
+// combinations are counted.
+const JobMatrixMaxCombinations = 256
+
 // Job is the element of a stage
 type Job struct {
 	PipelineActionID int64                  `json:"pipeline_action_id"`
@@ -8,6 +20,7 @@ type Job struct {
 	LastModified     int64                  `json:"last_modified"`
 	Action           Action                 `json:"action"`
 	Warnings         []PipelineBuildWarning `json:"warnings"`
+	Matrix           *JobMatrix             `json:"matrix,omitempty"`
 }
 
 // IsValid returns job's validity.
@@ -16,5 +29,190 @@ func (j Job) IsValid() error {
 		return NewErrorFrom(ErrWrongRequest, "invalid given stage id")
 	}
 
+	if j.Matrix != nil {
+		if err := j.Matrix.IsValid(); err != nil {
+			return err
+		}
+	}
+
 	return j.Action.IsValid()
 }
+
+// JobMatrix describes the combinations of values for which a job will be executed.
+// Each combination of axes values gives a job run, exclude entries remove matching
+// combinations and include entries add extra combinations.
+type JobMatrix struct {
+	Axes        map[string][]string `json:"axes,omitempty" yaml:"axes,omitempty" jsonschema_description:"The values for each axis of the matrix."`
+	Include     []map[string]string `json:"include,omitempty" yaml:"include,omitempty" jsonschema_description:"Extra combinations to execute."`
+	Exclude     []map[string]string `json:"exclude,omitempty" yaml:"exclude,omitempty" jsonschema_description:"Combinations to remove from the matrix, a combination is removed if it matches all given values."`
+	MaxParallel int                 `json:"max_parallel,omitempty" yaml:"max_parallel,omitempty" jsonschema_description:"The maximum number of combinations that can be executed at the same time, 0 means no limit."`
+}
+
+// Value returns driver.Value from job matrix.
+func (m JobMatrix) Value() (driver.Value, error) {
+	j, err := json.Marshal(m)
+	return j, WrapError(err, "cannot marshal JobMatrix")
+}
+
+// Scan job matrix.
+func (m *JobMatrix) Scan(src interface{}) error {
+	if src == nil {
+		return nil
+	}
+	source, ok := src.([]byte)
+	if !ok {
+		return WithStack(fmt.Errorf("type assertion .([]byte) failed (%T)", src))
+	}
+	return WrapError(json.Unmarshal(source, m), "cannot unmarshal JobMatrix")
+}
+
+// IsValid returns an error if the matrix is not valid.
+func (m JobMatrix) IsValid() error {
+	if len(m.Axes) == 0 && len(m.Include) == 0 {
+		return NewErrorFrom(ErrWrongRequest, "invalid job matrix, at least one axis or include should be given")
+	}
+	if m.MaxParallel < 0 {
+		return NewErrorFrom(ErrWrongRequest, "invalid job matrix max parallel %d", m.MaxParallel)
+	}
+	for name, values := range m.Axes {
+		if !NamePatternRegex.MatchString(name) {
+			return NewErrorFrom(ErrWrongRequest, "invalid job matrix axis name %q, should match %s", name, NamePattern)
+		}
+		if len(values) == 0 {
+			return NewErrorFrom(ErrWrongRequest, "invalid job matrix axis %s, at least one value should be given", name)
+		}
+		unique := make(map[string]struct{}, len(values))
+		for _, v := range values {
+			if _, ok := unique[v]; ok {
+				return NewErrorFrom(ErrWrongRequest, "invalid job matrix axis %s, value %q is duplicated", name, v)
+			}
+			unique[v] = struct{}{}
+		}
+	}
+	for _, c := range append(append([]map[string]string{}, m.Include...), m.Exclude...) {
+		if len(c) == 0 {
+			return NewErrorFrom(ErrWrongRequest, "invalid job matrix, include and exclude entries should not be empty")
+		}
+		for name := range c {
+			if !NamePatternRegex.MatchString(name) {
+				return NewErrorFrom(ErrWrongRequest, "invalid job matrix axis name %q, should match %s", name, NamePattern)
+			}
+		}
+	}
+	// Check the number of combinations before exclusions without building them to prevent huge allocations
+	n := len(m.Include)
+	if len(m.Axes) > 0 {
+		product := 1
+		for _, values := range m.Axes {
+			product *= len(values)
+			if product > JobMatrixMaxCombinations {
+				break
+			}
+		}
+		n += product
+	}
+	if n > JobMatrixMaxCombinations {
+		return NewErrorFrom(ErrWrongRequest, "invalid job matrix, combinations exceed the limit of %d", JobMatrixMaxCombinations)
+	}
+	if len(m.Combinations()) == 0 {
+		return NewErrorFrom(ErrWrongRequest, "invalid job matrix, all combinations are excluded")
+	}
+	return nil
+}
+
+// Combinations returns all combinations of axes values for the matrix, ordered by axis names
+// then by values order, followed by included combinations.
+func (m JobMatrix) Combinations() []map[string]string {
+	names := make([]string, 0, len(m.Axes))
+	for name := range m.Axes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var res []map[string]string
+	if len(names) > 0 {
+		res = []map[string]string{{}}
+		for _, name := range names {
+			next := make([]map[string]string, 0, len(res)*len(m.Axes[name]))
+			for _, c := range res {
+				for _, v := range m.Axes[name] {
+					nc := make(map[string]string, len(c)+1)
+					for k := range c {
+						nc[k] = c[k]
+					}
+					nc[name] = v
+					next = append(next, nc)
+				}
+			}
+			res = next
+		}
+	}
+
+	filtered := res[:0]
+	for _, c := range res {
+		if !matrixCombinationMatchesOne(c, m.Exclude) {
+			filtered = append(filtered, c)
+		}
+	}
+	res = filtered
+
+	for _, inc := range m.Include {
+		var found bool
+		for _, c := range res {
+			if matrixCombinationEqual(c, inc) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			res = append(res, inc)
+		}
+	}
+
+	return res
+}
+
+func matrixCombinationMatchesOne(c map[string]string, filters []map[string]string) bool {
+	for _, f := range filters {
+		match := true
+		for k, v := range f {
+			if c[k] != v {
+				match = false
+				break
+			}
+		}
+		if match {
+			return true
+		}
+	}
+	return false
+}
+
+func matrixCombinationEqual(a, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, v := range a {
+		if bv, ok := b[k]; !ok || bv != v {
+			return false
+		}
+	}
+	return true
+}
+
+// MatrixJobName returns the name of a job run for given job name and matrix values.
+func MatrixJobName(name string, values map[string]string) string {
+	if len(values) == 0 {
+		return name
+	}
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	parts := make([]string, len(keys))
+	for i, k := range keys {
+		parts[i] = k + ":" + values[k]
+	}
+	return fmt.Sprintf("%s (%s)", name, strings.Join(parts, ", "))
+}
@@ -0,0 +1,51 @@
+package sdk
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestJobMatrixCombinations(t *testing.T) {
+	m := JobMatrix{
+		Axes: map[string][]string{
+			"os": {"linux", "windows"},
+			"go": {"1.13", "1.14"},
+		},
+		Exclude: []map[string]string{
+			{"os": "windows", "go": "1.13"},
+		},
+		Include: []map[string]string{
+			{"os": "darwin", "go": "1.14"},
+			{"os": "linux", "go": "1.14"},
+		},
+	}
+	require.NoError(t, m.IsValid())
+	require.Equal(t, []map[string]string{
+		{"go": "1.13", "os": "linux"},
+		{"go": "1.14", "os": "linux"},
+		{"go": "1.14", "os": "windows"},
+		{"go": "1.14", "os": "darwin"},
+	}, m.Combinations())
+
+	require.Equal(t, "build (go:1.14, os:darwin)", MatrixJobName("build", m.Combinations()[3]))
+	require.Equal(t, "build", MatrixJobName("build", nil))
+}
+
+func TestJobMatrixIsValid(t *testing.T) {
+	require.Error(t, JobMatrix{}.IsValid())
+	require.Error(t, JobMatrix{Axes: map[string][]string{"go": {}}}.IsValid())
+	require.Error(t, JobMatrix{Axes: map[string][]string{"go": {"1.14", "1.14"}}}.IsValid())
+	require.Error(t, JobMatrix{Axes: map[string][]string{"my axis": {"1.14"}}}.IsValid())
+	require.Error(t, JobMatrix{Axes: map[string][]string{"go": {"1.14"}}, MaxParallel: -1}.IsValid())
+	require.Error(t, JobMatrix{Axes: map[string][]string{"go": {"1.14"}}, Exclude: []map[string]string{{"go": "1.14"}}}.IsValid())
+	require.Error(t, JobMatrix{Axes: map[string][]string{"go": {"1.14"}}, Include: []map[string]string{{}}}.IsValid())
+
+	values := make([]string, JobMatrixMaxCombinations+1)
+	for i := range values {
+		values[i] = string(rune('a'+i%26)) + string(rune('a'+i/26))
+	}
+	require.Error(t, JobMatrix{Axes: map[string][]string{"v": values}}.IsValid())
+	require.Error(t, JobMatrix{Axes: map[string][]string{"a": values, "b": values, "c": values, "d": values, "e": values, "f": values, "g": values, "h": values}}.IsValid())
+	require.NoError(t, JobMatrix{Include: []map[string]string{{"go": "1.14"}}, MaxParallel: 1}.IsValid())
+}
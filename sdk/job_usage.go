@@ -0,0 +1,57 @@
+package sdk
+
+import (
+	"time"
+)
+
+// JobRunUsage is the resource usage of a job run as reported by the worker.
+type JobRunUsage struct {
+	// Total CPU time (user and system) spent by the processes started by the worker in seconds
+	CPUTime float64 `json:"cpu_time"`
+	// Maximum resident set size of the processes started by the worker in bytes
+	MemoryPeak int64 `json:"memory_peak"`
+}
+
+// JobUsage is the accounting data recorded for a terminated workflow node job run.
+type JobUsage struct {
+	ID                   int64     `json:"id" db:"id"`
+	WorkflowNodeJobRunID int64     `json:"workflow_node_run_job_id" db:"workflow_node_run_job_id"`
+	WorkflowNodeRunID    int64     `json:"workflow_node_run_id" db:"workflow_node_run_id"`
+	ProjectID            int64     `json:"project_id" db:"project_id"`
+	JobName              string    `json:"job_name" db:"job_name"`
+	Status               string    `json:"status" db:"status"`
+	ModelName            string    `json:"model_name,omitempty" db:"model_name"`
+	ModelGroupName       string    `json:"model_group_name,omitempty" db:"model_group_name"`
+	ModelType            string    `json:"model_type,omitempty" db:"model_type"`
+	HatcheryName         string    `json:"hatchery_name,omitempty" db:"hatchery_name"`
+	WorkerName           string    `json:"worker_name,omitempty" db:"worker_name"`
+	Queued               time.Time `json:"queued" db:"queued"`
+	Start                time.Time `json:"start" db:"start"`
+	Done                 time.Time `json:"done" db:"done"`
+	// Nil if not reported by the worker
+	CPUTime    *float64 `json:"cpu_time,omitempty" db:"cpu_time"`
+	MemoryPeak *int64   `json:"memory_peak,omitempty" db:"memory_peak"`
+}
+
+// JobUsageFilter is used to load aggregated job usages.
+type JobUsageFilter struct {
+	ProjectKey string
+	GroupName  string
+	ModelName  string
+	Since      time.Time
+	Until      time.Time
+}
+
+// JobUsageSummary is the usage of the jobs of a project, for a worker model, aggregated by day. Durations are in
+// seconds.
+type JobUsageSummary struct {
+	Day            time.Time `json:"day" cli:"day"`
+	ProjectKey     string    `json:"project_key" cli:"project"`
+	ModelGroupName string    `json:"model_group_name" cli:"group"`
+	ModelName      string    `json:"model_name" cli:"model"`
+	Jobs           int64     `json:"jobs" cli:"jobs"`
+	WallTime       float64   `json:"wall_time" cli:"wall_time"`
+	QueueWaitTime  float64   `json:"queue_wait_time" cli:"queue_wait_time"`
+	CPUTime        float64   `json:"cpu_time" cli:"cpu_time"`
+	MemoryPeak     int64     `json:"memory_peak" cli:"memory_peak"`
+}
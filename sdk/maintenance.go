@@ -1,6 +1,39 @@
 package sdk
 
+import "time"
+
 const (
-	MaintenanceAPIKey    string = "cds_maintenance_api"
-	MaintenanceQueueName string = "cds_maintenance"
+	MaintenanceAPIKey      string = "cds_maintenance_api"
+	MaintenanceDrainAPIKey string = "cds_maintenance_drain_api"
+	MaintenanceQueueName   string = "cds_maintenance"
 )
+
+// MaintenanceDrain is set when a maintenance is enabled in drain mode: no new job is dispatched and running jobs
+// are given until the deadline to finish. The deadline is advisory: jobs still running when it is reached are not
+// stopped by CDS, they are interrupted by the restart that follows the drain.
+type MaintenanceDrain struct {
+	Since    time.Time `json:"since"`
+	Deadline time.Time `json:"deadline"`
+}
+
+// MaintenanceStatus reports the maintenance state and the remaining work.
+type MaintenanceStatus struct {
+	Enable bool              `json:"enable"`
+	Drain  *MaintenanceDrain `json:"drain,omitempty"`
+	// Jobs still running on workers
+	BuildingJobs int64 `json:"building_jobs"`
+	// Jobs waiting in queue, they will be dispatched once maintenance is disabled
+	WaitingJobs int64 `json:"waiting_jobs"`
+	// Waiting jobs already booked by a hatchery that is spawning a worker for them, included in WaitingJobs
+	BookedJobs int64 `json:"booked_jobs"`
+	// Hook events buffered by hooks services, they will be replayed once maintenance is disabled
+	BufferedHookEvents int64 `json:"buffered_hook_events"`
+}
+
+// Drained returns true if no job is running or booked by a hatchery anymore, or if the drain deadline is reached.
+func (s MaintenanceStatus) Drained() bool {
+	if s.Drain == nil {
+		return false
+	}
+	return s.BuildingJobs+s.BookedJobs == 0 || !time.Now().Before(s.Drain.Deadline)
+}
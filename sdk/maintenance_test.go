@@ -0,0 +1,28 @@
+package sdk
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMaintenanceStatusDrained(t *testing.T) {
+	now := time.Now()
+
+	s := MaintenanceStatus{Enable: true, BuildingJobs: 0}
+	assert.False(t, s.Drained(), "not drained without drain mode")
+
+	s.Drain = &MaintenanceDrain{Since: now, Deadline: now.Add(time.Hour)}
+	assert.True(t, s.Drained(), "drained when no job is running")
+
+	s.BookedJobs = 1
+	assert.False(t, s.Drained(), "not drained while jobs are booked by a hatchery")
+
+	s.BookedJobs = 0
+	s.BuildingJobs = 2
+	assert.False(t, s.Drained(), "not drained while jobs are running")
+
+	s.Drain.Deadline = now.Add(-time.Minute)
+	assert.True(t, s.Drained(), "drained when deadline is reached")
+}
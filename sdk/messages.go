@@ -63,6 +63,7 @@ var (
 	MsgSpawnInfoDeprecatedModel             = &Message{"MsgSpawnInfoDeprecatedModel", trad{FR: "⚠ Attention vous utilisez un worker model (%s) déprécié", EN: "⚠ Pay attention you are using a deprecated worker model (%s)"}, nil, RunInfoTypeWarning}
 	MsgSpawnInfoWorkerEnd                   = &Message{"MsgSpawnInfoWorkerEnd", trad{FR: "✓ Le worker %s a terminé et a passé %s à travailler sur les étapes", EN: "✓ Worker %s finished working on this job and took %s to work on the steps"}, nil, RunInfoTypInfo}
 	MsgSpawnInfoJobInQueue                  = &Message{"MsgSpawnInfoJobInQueue", trad{FR: "✓ Le job a été mis en file d'attente", EN: "✓ Job has been queued"}, nil, RunInfoTypInfo}
+	MsgSpawnInfoJobMatrixPending            = &Message{"MsgSpawnInfoJobMatrixPending", trad{FR: "Le job sera mis en file d'attente quand moins de %d combinaisons de la matrice seront en cours", EN: "Job will be queued when less than %d matrix combinations are running"}, nil, RunInfoTypInfo}
 	MsgSpawnInfoJobTaken                    = &Message{"MsgSpawnInfoJobTaken", trad{FR: "Le job %s a été pris par le worker %s", EN: "Job %s was taken by worker %s"}, nil, RunInfoTypInfo}
 	MsgSpawnInfoJobTakenWorkerVersion       = &Message{"MsgSpawnInfoJobTakenWorkerVersion", trad{FR: "Worker %s version:%s os:%s arch:%s", EN: "Worker %s version:%s os:%s arch:%s"}, nil, RunInfoTypInfo}
 	MsgSpawnInfoWorkerForJob                = &Message{"MsgSpawnInfoWorkerForJob", trad{FR: "Ce worker %s a été créé pour lancer ce job", EN: "This worker %s was created to take this action"}, nil, RunInfoTypInfo}
@@ -136,6 +137,7 @@ var Messages = map[string]*Message{
 	MsgSpawnInfoDeprecatedModel.ID:             MsgSpawnInfoDeprecatedModel,
 	MsgSpawnInfoWorkerEnd.ID:                   MsgSpawnInfoWorkerEnd,
 	MsgSpawnInfoJobInQueue.ID:                  MsgSpawnInfoJobInQueue,
+	MsgSpawnInfoJobMatrixPending.ID:            MsgSpawnInfoJobMatrixPending,
 	MsgSpawnInfoJobTaken.ID:                    MsgSpawnInfoJobTaken,
 	MsgSpawnInfoJobTakenWorkerVersion.ID:       MsgSpawnInfoJobTakenWorkerVersion,
 	MsgSpawnInfoWorkerForJob.ID:                MsgSpawnInfoWorkerForJob,
@@ -49,3 +49,9 @@ type PipelineAction struct {
 	Args            []Parameter `json:"args"`
 	PipelineStageID int64       `json:"pipeline_stage_id"`
 }
+
+// ChildPipelinesRequest is sent by the worker to add pipelines generated in the workspace as children of the
+// pipeline of the job. Pipelines are given in the exported YAML format.
+type ChildPipelinesRequest struct {
+	Pipelines []string `json:"pipelines"`
+}
@@ -0,0 +1,23 @@
+package sdk
+
+// Actions of a project bundle import change.
+const (
+	ProjectBundleChangeCreate = "create"
+	ProjectBundleChangeUpdate = "update"
+)
+
+// ProjectBundleChange is a change made on a project by the import of a project bundle.
+type ProjectBundleChange struct {
+	Type   string `json:"type" cli:"type"`
+	Name   string `json:"name" cli:"name"`
+	Action string `json:"action" cli:"action"`
+}
+
+// ProjectBundleImportResult is returned by the import of a project bundle, with dry run no change
+// is saved.
+type ProjectBundleImportResult struct {
+	Key      string                `json:"key"`
+	DryRun   bool                  `json:"dry_run"`
+	Changes  []ProjectBundleChange `json:"changes"`
+	Warnings []string              `json:"warnings,omitempty"`
+}
@@ -12,4 +12,7 @@ const (
 	ResponseTemplateGroupNameHeader = "X-Api-Template-Group-Name"
 	// ResponseTemplateSlugHeader is used as HTTP header
 	ResponseTemplateSlugHeader = "X-Api-Template-Slug"
+
+	// ProjectBundlePassphraseHeader is used as HTTP header to give the passphrase of a project bundle
+	ProjectBundlePassphraseHeader = "X-Api-Project-Bundle-Passphrase"
 )
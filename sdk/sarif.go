@@ -0,0 +1,295 @@
+package sdk
+
+import (
+	"crypto/sha256"
+	"database/sql/driver"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Analysis finding status compared to the default branch.
+const (
+	AnalysisFindingStatusNew      = "new"
+	AnalysisFindingStatusExisting = "existing"
+	AnalysisFindingStatusFixed    = "fixed"
+)
+
+// AnalysisFinding is a static analysis or security finding.
+type AnalysisFinding struct {
+	RuleID      string `json:"rule_id" cli:"rule"`
+	Severity    string `json:"severity" cli:"severity"`
+	Message     string `json:"message" cli:"message"`
+	Path        string `json:"path,omitempty" cli:"path"`
+	Line        int64  `json:"line,omitempty" cli:"line"`
+	Fingerprint string `json:"fingerprint" cli:"-"`
+	Status      string `json:"status,omitempty" cli:"status"`
+}
+
+// AnalysisFindings is a list of findings stored as JSON.
+type AnalysisFindings []AnalysisFinding
+
+// Value returns driver.Value from AnalysisFindings.
+func (a AnalysisFindings) Value() (driver.Value, error) {
+	j, err := json.Marshal(a)
+	return j, WrapError(err, "cannot marshal AnalysisFindings")
+}
+
+// Scan AnalysisFindings.
+func (a *AnalysisFindings) Scan(src interface{}) error {
+	if src == nil {
+		return nil
+	}
+	source, ok := src.([]byte)
+	if !ok {
+		return WithStack(fmt.Errorf("type assertion .([]byte) failed (%T)", src))
+	}
+	return WrapError(json.Unmarshal(source, a), "cannot unmarshal AnalysisFindings")
+}
+
+// AnalysisWorkerReport is sent by the worker for each analysis tool report.
+type AnalysisWorkerReport struct {
+	Tool     string           `json:"tool"`
+	Findings AnalysisFindings `json:"findings"`
+}
+
+// WorkflowNodeRunAnalysis contains the findings of an analysis tool for a node run, and the
+// findings fixed compared to the default branch.
+type WorkflowNodeRunAnalysis struct {
+	ID                int64            `json:"id" db:"id"`
+	WorkflowID        int64            `json:"workflow_id" db:"workflow_id"`
+	WorkflowRunID     int64            `json:"workflow_run_id" db:"workflow_run_id"`
+	WorkflowNodeRunID int64            `json:"workflow_node_run_id" db:"workflow_node_run_id"`
+	ApplicationID     int64            `json:"application_id" db:"application_id"`
+	Num               int64            `json:"num" db:"run_number"`
+	Branch            string           `json:"branch" db:"branch"`
+	Tool              string           `json:"tool" db:"tool"`
+	Created           time.Time        `json:"created" db:"created"`
+	Findings          AnalysisFindings `json:"findings" db:"findings"`
+	Fixed             AnalysisFindings `json:"fixed" db:"fixed"`
+}
+
+// Summary returns the number of findings by severity.
+func (a WorkflowNodeRunAnalysis) Summary() map[string]int64 {
+	res := make(map[string]int64)
+	for _, f := range a.Findings {
+		res[f.Severity]++
+	}
+	return res
+}
+
+// ComputeStatus sets the status of the findings and the fixed findings compared to given baseline.
+func (a *WorkflowNodeRunAnalysis) ComputeStatus(baseline AnalysisFindings) {
+	current := make(map[string]struct{}, len(a.Findings))
+	for _, f := range a.Findings {
+		current[f.Fingerprint] = struct{}{}
+	}
+	previous := make(map[string]struct{}, len(baseline))
+	for _, f := range baseline {
+		previous[f.Fingerprint] = struct{}{}
+	}
+
+	for i := range a.Findings {
+		if _, ok := previous[a.Findings[i].Fingerprint]; ok {
+			a.Findings[i].Status = AnalysisFindingStatusExisting
+		} else {
+			a.Findings[i].Status = AnalysisFindingStatusNew
+		}
+	}
+
+	a.Fixed = nil
+	for _, f := range baseline {
+		if _, ok := current[f.Fingerprint]; !ok {
+			f.Status = AnalysisFindingStatusFixed
+			a.Fixed = append(a.Fixed, f)
+		}
+	}
+}
+
+// FindingsAboveSeverity returns the findings with a severity greater than or equal to given
+// severity, only new findings are returned if newOnly is true.
+func (a WorkflowNodeRunAnalysis) FindingsAboveSeverity(severity string, newOnly bool) AnalysisFindings {
+	level := SeverityLevel(severity)
+	if level == 0 {
+		return nil
+	}
+	var res AnalysisFindings
+	for _, f := range a.Findings {
+		if newOnly && f.Status != AnalysisFindingStatusNew {
+			continue
+		}
+		if SeverityLevel(f.Severity) >= level {
+			res = append(res, f)
+		}
+	}
+	return res
+}
+
+// SeverityLevel returns a comparable level for a vulnerability severity, 0 for unknown severities.
+func SeverityLevel(severity string) int {
+	switch ToVulnerabilitySeverity(severity) {
+	case SeverityNegligible:
+		return 1
+	case SeverityLow:
+		return 2
+	case SeverityMedium:
+		return 3
+	case SeverityHigh:
+		return 4
+	case SeverityCritical:
+		return 5
+	case SeverityDefcon1:
+		return 6
+	}
+	return 0
+}
+
+// SARIF 2.1 log format, only the fields used by CDS are described.
+// https://docs.oasis-open.org/sarif/sarif/v2.1.0/sarif-v2.1.0.html
+type sarifLog struct {
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool struct {
+		Driver struct {
+			Name  string      `json:"name"`
+			Rules []sarifRule `json:"rules"`
+		} `json:"driver"`
+	} `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifRule struct {
+	ID                   string       `json:"id"`
+	ShortDescription     sarifMessage `json:"shortDescription"`
+	DefaultConfiguration struct {
+		Level string `json:"level"`
+	} `json:"defaultConfiguration"`
+	Properties map[string]interface{} `json:"properties"`
+}
+
+type sarifResult struct {
+	RuleID    string       `json:"ruleId"`
+	RuleIndex *int         `json:"ruleIndex"`
+	Level     string       `json:"level"`
+	Message   sarifMessage `json:"message"`
+	Locations []struct {
+		PhysicalLocation struct {
+			ArtifactLocation struct {
+				URI string `json:"uri"`
+			} `json:"artifactLocation"`
+			Region struct {
+				StartLine int64 `json:"startLine"`
+			} `json:"region"`
+		} `json:"physicalLocation"`
+	} `json:"locations"`
+	Fingerprints        map[string]string `json:"fingerprints"`
+	PartialFingerprints map[string]string `json:"partialFingerprints"`
+}
+
+// ParseSARIF returns analysis reports, one by tool, from a SARIF 2.1 file content.
+func ParseSARIF(data []byte) ([]AnalysisWorkerReport, error) {
+	var l sarifLog
+	if err := json.Unmarshal(data, &l); err != nil {
+		return nil, NewErrorWithStack(err, NewErrorFrom(ErrWrongRequest, "invalid SARIF file"))
+	}
+	if !strings.HasPrefix(l.Version, "2.1") {
+		return nil, NewErrorFrom(ErrWrongRequest, "unsupported SARIF version %q", l.Version)
+	}
+
+	reports := make([]AnalysisWorkerReport, 0, len(l.Runs))
+	for _, run := range l.Runs {
+		rules := make(map[string]sarifRule, len(run.Tool.Driver.Rules))
+		for _, r := range run.Tool.Driver.Rules {
+			rules[r.ID] = r
+		}
+
+		report := AnalysisWorkerReport{Tool: run.Tool.Driver.Name}
+		for _, res := range run.Results {
+			ruleID := res.RuleID
+			if ruleID == "" && res.RuleIndex != nil && *res.RuleIndex >= 0 && *res.RuleIndex < len(run.Tool.Driver.Rules) {
+				ruleID = run.Tool.Driver.Rules[*res.RuleIndex].ID
+			}
+			rule := rules[ruleID]
+
+			f := AnalysisFinding{
+				RuleID:   ruleID,
+				Severity: sarifSeverity(res.Level, rule),
+				Message:  res.Message.Text,
+			}
+			if f.Message == "" {
+				f.Message = rule.ShortDescription.Text
+			}
+			if len(res.Locations) > 0 {
+				f.Path = res.Locations[0].PhysicalLocation.ArtifactLocation.URI
+				f.Line = res.Locations[0].PhysicalLocation.Region.StartLine
+			}
+			f.Fingerprint = sarifFingerprint(report.Tool, f, res)
+			report.Findings = append(report.Findings, f)
+		}
+		reports = append(reports, report)
+	}
+
+	return reports, nil
+}
+
+// sarifSeverity uses the security severity property if set (CVSS score), else the level of the result or the rule.
+func sarifSeverity(level string, rule sarifRule) string {
+	if v, ok := rule.Properties["security-severity"]; ok {
+		if score, err := strconv.ParseFloat(fmt.Sprintf("%v", v), 64); err == nil {
+			switch {
+			case score >= 9:
+				return SeverityCritical
+			case score >= 7:
+				return SeverityHigh
+			case score >= 4:
+				return SeverityMedium
+			case score > 0:
+				return SeverityLow
+			}
+			return SeverityNegligible
+		}
+	}
+
+	if level == "" {
+		level = rule.DefaultConfiguration.Level
+	}
+	switch level {
+	case "error":
+		return SeverityHigh
+	case "note":
+		return SeverityLow
+	case "none":
+		return SeverityNegligible
+	}
+	// warning is the default level
+	return SeverityMedium
+}
+
+// sarifFingerprint returns a stable identifier for a result, line numbers are ignored
+// because they change with unrelated modifications of the file.
+func sarifFingerprint(tool string, f AnalysisFinding, res sarifResult) string {
+	for _, fps := range []map[string]string{res.Fingerprints, res.PartialFingerprints} {
+		if len(fps) == 0 {
+			continue
+		}
+		keys := make([]string, 0, len(fps))
+		for k := range fps {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		return keys[0] + ":" + fps[keys[0]]
+	}
+	h := sha256.Sum256([]byte(strings.Join([]string{tool, f.RuleID, f.Path, f.Message}, "\x00")))
+	return hex.EncodeToString(h[:])
+}
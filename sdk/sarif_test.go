@@ -0,0 +1,72 @@
+package sdk
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const sarifContent = `{
+  "version": "2.1.0",
+  "runs": [{
+    "tool": {"driver": {"name": "gosec", "rules": [
+      {"id": "G101", "shortDescription": {"text": "Hardcoded credentials"}, "properties": {"security-severity": "9.1"}},
+      {"id": "G104", "defaultConfiguration": {"level": "note"}}
+    ]}},
+    "results": [
+      {"ruleId": "G101", "message": {"text": "Potential hardcoded credentials"},
+       "locations": [{"physicalLocation": {"artifactLocation": {"uri": "main.go"}, "region": {"startLine": 12}}}]},
+      {"ruleIndex": 1, "message": {"text": "Errors unhandled"}, "partialFingerprints": {"primaryLocationLineHash": "abc"}},
+      {"ruleId": "G999", "level": "error", "message": {"text": "Unknown rule"}},
+      {"ruleIndex": -1, "level": "warning", "message": {"text": "No rule"}}
+    ]
+  }]
+}`
+
+func TestParseSARIF(t *testing.T) {
+	reports, err := ParseSARIF([]byte(sarifContent))
+	require.NoError(t, err)
+	require.Len(t, reports, 1)
+	require.Equal(t, "gosec", reports[0].Tool)
+	fs := reports[0].Findings
+	require.Len(t, fs, 4)
+
+	require.Equal(t, "G101", fs[0].RuleID)
+	require.Equal(t, SeverityCritical, fs[0].Severity)
+	require.Equal(t, "main.go", fs[0].Path)
+	require.Equal(t, int64(12), fs[0].Line)
+	require.Len(t, fs[0].Fingerprint, 64)
+
+	require.Equal(t, "G104", fs[1].RuleID)
+	require.Equal(t, SeverityLow, fs[1].Severity)
+	require.Equal(t, "primaryLocationLineHash:abc", fs[1].Fingerprint)
+
+	require.Equal(t, SeverityHigh, fs[2].Severity)
+
+	require.Equal(t, "", fs[3].RuleID)
+
+	_, err = ParseSARIF([]byte(`{"version": "1.0.0"}`))
+	require.Error(t, err)
+}
+
+func TestWorkflowNodeRunAnalysisComputeStatus(t *testing.T) {
+	a := WorkflowNodeRunAnalysis{Findings: AnalysisFindings{
+		{RuleID: "A", Fingerprint: "1", Severity: SeverityHigh},
+		{RuleID: "B", Fingerprint: "2", Severity: SeverityLow},
+	}}
+	a.ComputeStatus(AnalysisFindings{
+		{RuleID: "A", Fingerprint: "1"},
+		{RuleID: "C", Fingerprint: "3"},
+	})
+	require.Equal(t, AnalysisFindingStatusExisting, a.Findings[0].Status)
+	require.Equal(t, AnalysisFindingStatusNew, a.Findings[1].Status)
+	require.Len(t, a.Fixed, 1)
+	require.Equal(t, "C", a.Fixed[0].RuleID)
+	require.Equal(t, map[string]int64{SeverityHigh: 1, SeverityLow: 1}, a.Summary())
+	require.True(t, SeverityLevel(SeverityCritical) > SeverityLevel(SeverityHigh))
+
+	require.Len(t, a.FindingsAboveSeverity(SeverityLow, false), 2)
+	require.Len(t, a.FindingsAboveSeverity(SeverityLow, true), 1)
+	require.Len(t, a.FindingsAboveSeverity(SeverityHigh, true), 0)
+	require.Len(t, a.FindingsAboveSeverity("", false), 0)
+}
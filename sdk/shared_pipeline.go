@@ -0,0 +1,120 @@
+package sdk
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SharedPipeline is a version of a pipeline published by a group to be used in other projects.
+type SharedPipeline struct {
+	ID               int64                    `json:"id" db:"id" cli:"-"`
+	GroupID          int64                    `json:"group_id" db:"group_id" cli:"-"`
+	Name             string                   `json:"name" db:"name" cli:"name,key"`
+	Version          int64                    `json:"version" db:"version" cli:"version"`
+	Description      string                   `json:"description" db:"description" cli:"description"`
+	Parameters       SharedPipelineParameters `json:"parameters" db:"parameters" cli:"-"`
+	Value            string                   `json:"value" db:"value" cli:"-"`
+	FromProjectKey   string                   `json:"from_project_key" db:"from_project_key" cli:"from_project"`
+	FromPipelineName string                   `json:"from_pipeline_name" db:"from_pipeline_name" cli:"from_pipeline"`
+	Author           string                   `json:"author" db:"author" cli:"author"`
+	Created          time.Time                `json:"created" db:"created" cli:"created"`
+	// aggregates
+	Group *Group `json:"group,omitempty" db:"-" cli:"-"`
+}
+
+// Path returns the reference of the shared pipeline version, as group/name@version.
+func (s SharedPipeline) Path() string {
+	if s.Group == nil {
+		return fmt.Sprintf("%s@%d", s.Name, s.Version)
+	}
+	return fmt.Sprintf("%s/%s@%d", s.Group.Name, s.Name, s.Version)
+}
+
+// SharedPipelineParameters are the parameters of a shared pipeline stored as JSON.
+type SharedPipelineParameters []Parameter
+
+// Value returns driver.Value from shared pipeline parameters.
+func (p SharedPipelineParameters) Value() (driver.Value, error) {
+	j, err := json.Marshal(p)
+	return j, WrapError(err, "cannot marshal SharedPipelineParameters")
+}
+
+// Scan shared pipeline parameters.
+func (p *SharedPipelineParameters) Scan(src interface{}) error {
+	if src == nil {
+		return nil
+	}
+	source, ok := src.([]byte)
+	if !ok {
+		return WithStack(fmt.Errorf("type assertion .([]byte) failed (%T)", src))
+	}
+	return WrapError(json.Unmarshal(source, p), "cannot unmarshal SharedPipelineParameters")
+}
+
+// SharedPipelinePublishRequest is used to publish a new version of a project pipeline.
+type SharedPipelinePublishRequest struct {
+	GroupName   string `json:"group_name"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// SharedPipelineUseRequest is used to use a shared pipeline in a project.
+type SharedPipelineUseRequest struct {
+	Ref string `json:"ref"`
+	// Force overwrites the changes made to the project pipeline since it was created from the shared pipeline
+	Force bool `json:"force"`
+}
+
+// IsSharedPipelineRef returns true if given pipeline name is a reference to a shared pipeline.
+func IsSharedPipelineRef(name string) bool {
+	return strings.Contains(name, "/")
+}
+
+// ParseSharedPipelineRef parses a shared pipeline reference group/name@version, version
+// is optional and 0 is returned to use the latest version.
+func ParseSharedPipelineRef(ref string) (string, string, int64, error) {
+	pathWithVersion := strings.SplitN(ref, "@", 2)
+	path := strings.Split(pathWithVersion[0], "/")
+	if len(path) != 2 || path[0] == "" || path[1] == "" {
+		return "", "", 0, NewErrorFrom(ErrWrongRequest, "invalid shared pipeline reference %q, expected group/name@version", ref)
+	}
+	var version int64
+	if len(pathWithVersion) > 1 && pathWithVersion[1] != "latest" {
+		var err error
+		version, err = strconv.ParseInt(pathWithVersion[1], 10, 64)
+		if err != nil || version <= 0 {
+			return "", "", 0, NewErrorFrom(ErrWrongRequest, "invalid shared pipeline version %q", pathWithVersion[1])
+		}
+	}
+	return path[0], path[1], version, nil
+}
+
+// SharedPipelineInstance links a project pipeline to the version of the shared pipeline it was created from.
+type SharedPipelineInstance struct {
+	ID         int64     `json:"id" db:"id"`
+	GroupID    int64     `json:"group_id" db:"group_id"`
+	Name       string    `json:"name" db:"name"`
+	Version    int64     `json:"version" db:"version"`
+	ProjectID  int64     `json:"project_id" db:"project_id"`
+	PipelineID int64     `json:"pipeline_id" db:"pipeline_id"`
+	Created    time.Time `json:"created" db:"created"`
+	Updated    time.Time `json:"updated" db:"updated"`
+}
+
+// UsageSharedPipeline represents a project pipeline created from a shared pipeline and the workflows using it.
+type UsageSharedPipeline struct {
+	ProjectID     int64  `json:"project_id" cli:"-"`
+	ProjectKey    string `json:"project_key" cli:"project"`
+	ProjectName   string `json:"project_name" cli:"-"`
+	PipelineID    int64  `json:"pipeline_id" cli:"-"`
+	PipelineName  string `json:"pipeline_name" cli:"pipeline"`
+	WorkflowID    int64  `json:"workflow_id,omitempty" cli:"-"`
+	WorkflowName  string `json:"workflow_name,omitempty" cli:"workflow"`
+	Version       int64  `json:"version" cli:"version"`
+	LatestVersion int64  `json:"latest_version" cli:"latest_version"`
+	Outdated      bool   `json:"outdated" cli:"outdated"`
+}
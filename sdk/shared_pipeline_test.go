@@ -0,0 +1,39 @@
+package sdk
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSharedPipelineRef(t *testing.T) {
+	tests := []struct {
+		ref     string
+		group   string
+		name    string
+		version int64
+		err     bool
+	}{
+		{ref: "shared.infra/build", group: "shared.infra", name: "build"},
+		{ref: "shared.infra/build@latest", group: "shared.infra", name: "build"},
+		{ref: "my-group/deploy@3", group: "my-group", name: "deploy", version: 3},
+		{ref: "build", err: true},
+		{ref: "group/", err: true},
+		{ref: "a/b/c", err: true},
+		{ref: "group/build@0", err: true},
+		{ref: "group/build@v1", err: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.ref, func(t *testing.T) {
+			group, name, version, err := ParseSharedPipelineRef(tt.ref)
+			if tt.err {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.group, group)
+			require.Equal(t, tt.name, name)
+			require.Equal(t, tt.version, version)
+		})
+	}
+}
@@ -0,0 +1,407 @@
+package telemetry
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/trace"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// OTLPExporter exports the OpenCensus spans and views to an OpenTelemetry collector with the OTLP/HTTP JSON protocol.
+// Spans and views are buffered and sent at each flush period, or when the span buffer is full.
+type OTLPExporter struct {
+	endpoint   string
+	headers    map[string]string
+	resource   otlpResource
+	client     *http.Client
+	maxSpans   int
+	mutex      sync.Mutex
+	spans      []otlpSpan
+	metrics    map[string]otlpMetric
+	metricKeys []string
+	// full is signaled to the flusher when the span buffer is full
+	full chan struct{}
+}
+
+// NewOTLPExporter returns an exporter that sends data to given collector endpoint (ex: http://localhost:4318).
+func NewOTLPExporter(endpoint, serviceName string, headers map[string]string) *OTLPExporter {
+	return &OTLPExporter{
+		endpoint: strings.TrimSuffix(endpoint, "/"),
+		headers:  headers,
+		resource: otlpResource{Attributes: []otlpAttribute{
+			newOTLPAttribute("service.name", serviceName),
+			newOTLPAttribute("service.version", sdk.VERSION),
+		}},
+		client:   &http.Client{Timeout: 10 * time.Second},
+		maxSpans: 512,
+		metrics:  make(map[string]otlpMetric),
+		full:     make(chan struct{}, 1),
+	}
+}
+
+type otlpAnyValue struct {
+	StringValue *string  `json:"stringValue,omitempty"`
+	BoolValue   *bool    `json:"boolValue,omitempty"`
+	IntValue    *string  `json:"intValue,omitempty"`
+	DoubleValue *float64 `json:"doubleValue,omitempty"`
+}
+
+type otlpAttribute struct {
+	Key   string       `json:"key"`
+	Value otlpAnyValue `json:"value"`
+}
+
+func newOTLPAttribute(key string, value interface{}) otlpAttribute {
+	a := otlpAttribute{Key: key}
+	switch v := value.(type) {
+	case bool:
+		a.Value.BoolValue = &v
+	case int64:
+		s := strconv.FormatInt(v, 10)
+		a.Value.IntValue = &s
+	case float64:
+		a.Value.DoubleValue = &v
+	default:
+		s := fmt.Sprintf("%v", v)
+		a.Value.StringValue = &s
+	}
+	return a
+}
+
+type otlpResource struct {
+	Attributes []otlpAttribute `json:"attributes"`
+}
+
+type otlpScope struct {
+	Name string `json:"name"`
+}
+
+type otlpStatus struct {
+	Code    int    `json:"code"`
+	Message string `json:"message,omitempty"`
+}
+
+type otlpEvent struct {
+	TimeUnixNano string          `json:"timeUnixNano"`
+	Name         string          `json:"name"`
+	Attributes   []otlpAttribute `json:"attributes,omitempty"`
+}
+
+type otlpSpan struct {
+	TraceID           string          `json:"traceId"`
+	SpanID            string          `json:"spanId"`
+	ParentSpanID      string          `json:"parentSpanId,omitempty"`
+	Name              string          `json:"name"`
+	Kind              int             `json:"kind"`
+	StartTimeUnixNano string          `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string          `json:"endTimeUnixNano"`
+	Attributes        []otlpAttribute `json:"attributes,omitempty"`
+	Events            []otlpEvent     `json:"events,omitempty"`
+	Status            otlpStatus      `json:"status"`
+}
+
+type otlpScopeSpans struct {
+	Scope otlpScope  `json:"scope"`
+	Spans []otlpSpan `json:"spans"`
+}
+
+type otlpResourceSpans struct {
+	Resource   otlpResource     `json:"resource"`
+	ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+}
+
+type otlpTracesRequest struct {
+	ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+}
+
+type otlpNumberDataPoint struct {
+	Attributes        []otlpAttribute `json:"attributes,omitempty"`
+	StartTimeUnixNano string          `json:"startTimeUnixNano,omitempty"`
+	TimeUnixNano      string          `json:"timeUnixNano"`
+	AsInt             *string         `json:"asInt,omitempty"`
+	AsDouble          *float64        `json:"asDouble,omitempty"`
+}
+
+type otlpHistogramDataPoint struct {
+	Attributes        []otlpAttribute `json:"attributes,omitempty"`
+	StartTimeUnixNano string          `json:"startTimeUnixNano,omitempty"`
+	TimeUnixNano      string          `json:"timeUnixNano"`
+	Count             string          `json:"count"`
+	Sum               float64         `json:"sum"`
+	BucketCounts      []string        `json:"bucketCounts"`
+	ExplicitBounds    []float64       `json:"explicitBounds"`
+}
+
+type otlpSum struct {
+	DataPoints             []otlpNumberDataPoint `json:"dataPoints"`
+	AggregationTemporality int                   `json:"aggregationTemporality"`
+	IsMonotonic            bool                  `json:"isMonotonic"`
+}
+
+type otlpGauge struct {
+	DataPoints []otlpNumberDataPoint `json:"dataPoints"`
+}
+
+type otlpHistogram struct {
+	DataPoints             []otlpHistogramDataPoint `json:"dataPoints"`
+	AggregationTemporality int                      `json:"aggregationTemporality"`
+}
+
+type otlpMetric struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description,omitempty"`
+	Unit        string         `json:"unit,omitempty"`
+	Sum         *otlpSum       `json:"sum,omitempty"`
+	Gauge       *otlpGauge     `json:"gauge,omitempty"`
+	Histogram   *otlpHistogram `json:"histogram,omitempty"`
+}
+
+type otlpScopeMetrics struct {
+	Scope   otlpScope    `json:"scope"`
+	Metrics []otlpMetric `json:"metrics"`
+}
+
+type otlpResourceMetrics struct {
+	Resource     otlpResource       `json:"resource"`
+	ScopeMetrics []otlpScopeMetrics `json:"scopeMetrics"`
+}
+
+type otlpMetricsRequest struct {
+	ResourceMetrics []otlpResourceMetrics `json:"resourceMetrics"`
+}
+
+// OTLP constants, see https://github.com/open-telemetry/opentelemetry-proto
+const (
+	otlpSpanKindInternal = 1
+	otlpSpanKindServer   = 2
+	otlpSpanKindClient   = 3
+
+	otlpStatusCodeOK    = 1
+	otlpStatusCodeError = 2
+
+	otlpAggregationTemporalityCumulative = 2
+
+	otlpScopeName = "github.com/ovh/cds"
+)
+
+func unixNano(t time.Time) string {
+	return strconv.FormatInt(t.UnixNano(), 10)
+}
+
+func spanDataToOTLP(sd *trace.SpanData) otlpSpan {
+	s := otlpSpan{
+		TraceID:           sd.TraceID.String(),
+		SpanID:            sd.SpanID.String(),
+		Name:              sd.Name,
+		StartTimeUnixNano: unixNano(sd.StartTime),
+		EndTimeUnixNano:   unixNano(sd.EndTime),
+		Status:            otlpStatus{Code: otlpStatusCodeOK, Message: sd.Status.Message},
+	}
+	if sd.ParentSpanID != (trace.SpanID{}) {
+		s.ParentSpanID = sd.ParentSpanID.String()
+	}
+	switch sd.SpanKind {
+	case trace.SpanKindServer:
+		s.Kind = otlpSpanKindServer
+	case trace.SpanKindClient:
+		s.Kind = otlpSpanKindClient
+	default:
+		s.Kind = otlpSpanKindInternal
+	}
+	if sd.Status.Code != trace.StatusCodeOK {
+		s.Status.Code = otlpStatusCodeError
+	}
+	for k, v := range sd.Attributes {
+		s.Attributes = append(s.Attributes, newOTLPAttribute(k, v))
+	}
+	for _, a := range sd.Annotations {
+		e := otlpEvent{TimeUnixNano: unixNano(a.Time), Name: a.Message}
+		for k, v := range a.Attributes {
+			e.Attributes = append(e.Attributes, newOTLPAttribute(k, v))
+		}
+		s.Events = append(s.Events, e)
+	}
+	return s
+}
+
+func viewDataToOTLP(vd *view.Data) otlpMetric {
+	m := otlpMetric{
+		Name:        vd.View.Name,
+		Description: vd.View.Description,
+	}
+	if vd.View.Measure != nil {
+		m.Unit = vd.View.Measure.Unit()
+	}
+
+	for _, row := range vd.Rows {
+		var attrs []otlpAttribute
+		for _, t := range row.Tags {
+			attrs = append(attrs, newOTLPAttribute(t.Key.Name(), t.Value))
+		}
+		start, end := unixNano(vd.Start), unixNano(vd.End)
+
+		switch x := row.Data.(type) {
+		case *view.CountData:
+			if m.Sum == nil {
+				m.Sum = &otlpSum{AggregationTemporality: otlpAggregationTemporalityCumulative, IsMonotonic: true}
+			}
+			v := strconv.FormatInt(x.Value, 10)
+			m.Sum.DataPoints = append(m.Sum.DataPoints, otlpNumberDataPoint{Attributes: attrs, StartTimeUnixNano: start, TimeUnixNano: end, AsInt: &v})
+		case *view.SumData:
+			if m.Sum == nil {
+				m.Sum = &otlpSum{AggregationTemporality: otlpAggregationTemporalityCumulative}
+			}
+			v := x.Value
+			m.Sum.DataPoints = append(m.Sum.DataPoints, otlpNumberDataPoint{Attributes: attrs, StartTimeUnixNano: start, TimeUnixNano: end, AsDouble: &v})
+		case *view.LastValueData:
+			if m.Gauge == nil {
+				m.Gauge = &otlpGauge{}
+			}
+			v := x.Value
+			m.Gauge.DataPoints = append(m.Gauge.DataPoints, otlpNumberDataPoint{Attributes: attrs, TimeUnixNano: end, AsDouble: &v})
+		case *view.DistributionData:
+			if m.Histogram == nil {
+				m.Histogram = &otlpHistogram{AggregationTemporality: otlpAggregationTemporalityCumulative}
+			}
+			dp := otlpHistogramDataPoint{
+				Attributes:        attrs,
+				StartTimeUnixNano: start,
+				TimeUnixNano:      end,
+				Count:             strconv.FormatInt(x.Count, 10),
+				Sum:               x.Sum(),
+				ExplicitBounds:    vd.View.Aggregation.Buckets,
+			}
+			for _, c := range x.CountPerBucket {
+				dp.BucketCounts = append(dp.BucketCounts, strconv.FormatInt(c, 10))
+			}
+			m.Histogram.DataPoints = append(m.Histogram.DataPoints, dp)
+		}
+	}
+	return m
+}
+
+// ExportSpan implements trace.Exporter.
+func (e *OTLPExporter) ExportSpan(sd *trace.SpanData) {
+	e.mutex.Lock()
+	e.spans = append(e.spans, spanDataToOTLP(sd))
+	full := len(e.spans) >= e.maxSpans
+	e.mutex.Unlock()
+
+	// Wake up the flusher, a flush is already pending if the channel is not empty
+	if full {
+		select {
+		case e.full <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// ExportView implements view.Exporter, only the last data of each view is kept until the next flush.
+func (e *OTLPExporter) ExportView(vd *view.Data) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	if _, ok := e.metrics[vd.View.Name]; !ok {
+		e.metricKeys = append(e.metricKeys, vd.View.Name)
+	}
+	e.metrics[vd.View.Name] = viewDataToOTLP(vd)
+}
+
+// Flush sends all the buffered spans and views to the collector. Views are sent even if the spans could not be sent.
+func (e *OTLPExporter) Flush(ctx context.Context) error {
+	e.mutex.Lock()
+	spans := e.spans
+	e.spans = nil
+	metrics := make([]otlpMetric, 0, len(e.metricKeys))
+	for _, k := range e.metricKeys {
+		metrics = append(metrics, e.metrics[k])
+	}
+	e.metrics = make(map[string]otlpMetric)
+	e.metricKeys = nil
+	e.mutex.Unlock()
+
+	var tracesErr error
+	if len(spans) > 0 {
+		req := otlpTracesRequest{ResourceSpans: []otlpResourceSpans{{
+			Resource:   e.resource,
+			ScopeSpans: []otlpScopeSpans{{Scope: otlpScope{Name: otlpScopeName}, Spans: spans}},
+		}}}
+		tracesErr = e.post(ctx, "/v1/traces", req)
+	}
+
+	if len(metrics) > 0 {
+		req := otlpMetricsRequest{ResourceMetrics: []otlpResourceMetrics{{
+			Resource:     e.resource,
+			ScopeMetrics: []otlpScopeMetrics{{Scope: otlpScope{Name: otlpScopeName}, Metrics: metrics}},
+		}}}
+		if err := e.post(ctx, "/v1/metrics", req); err != nil {
+			if tracesErr != nil {
+				return sdk.WithStack(fmt.Errorf("%v, %v", tracesErr, err))
+			}
+			return err
+		}
+	}
+
+	return tracesErr
+}
+
+func (e *OTLPExporter) post(ctx context.Context, path string, body interface{}) error {
+	btes, err := json.Marshal(body)
+	if err != nil {
+		return sdk.WithStack(err)
+	}
+	req, err := http.NewRequest(http.MethodPost, e.endpoint+path, bytes.NewReader(btes))
+	if err != nil {
+		return sdk.WithStack(err)
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range e.headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return sdk.WrapError(err, "unable to send data to OTLP collector")
+	}
+	defer resp.Body.Close() // nolint
+	if resp.StatusCode >= 300 {
+		return sdk.WithStack(fmt.Errorf("OTLP collector %s returned HTTP %d", e.endpoint+path, resp.StatusCode))
+	}
+	return nil
+}
+
+// Run flushes the exporter at given period or when the span buffer is full until the context is cancelled, then
+// flushes a last time. It is the only flusher of the exporter.
+func (e *OTLPExporter) Run(ctx context.Context, period time.Duration) {
+	ticker := time.NewTicker(period)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			flushCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			if err := e.Flush(flushCtx); err != nil {
+				log.Error(ctx, "telemetry.OTLPExporter> unable to flush: %v", err)
+			}
+			cancel()
+			return
+		case <-ticker.C:
+			if err := e.Flush(ctx); err != nil {
+				log.Error(ctx, "telemetry.OTLPExporter> unable to flush: %v", err)
+			}
+		case <-e.full:
+			if err := e.Flush(ctx); err != nil {
+				log.Error(ctx, "telemetry.OTLPExporter> unable to flush: %v", err)
+			}
+		}
+	}
+}
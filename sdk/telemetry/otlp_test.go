@@ -0,0 +1,235 @@
+package telemetry
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+	"go.opencensus.io/trace"
+)
+
+// otlpCollector is a local OpenTelemetry collector stand-in that records the received requests.
+type otlpCollector struct {
+	mutex   sync.Mutex
+	traces  []otlpTracesRequest
+	metrics []otlpMetricsRequest
+	headers []http.Header
+}
+
+func (c *otlpCollector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	btes, _ := ioutil.ReadAll(r.Body)
+	c.headers = append(c.headers, r.Header)
+	switch r.URL.Path {
+	case "/v1/traces":
+		var req otlpTracesRequest
+		if err := json.Unmarshal(btes, &req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		c.traces = append(c.traces, req)
+	case "/v1/metrics":
+		var req otlpMetricsRequest
+		if err := json.Unmarshal(btes, &req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		c.metrics = append(c.metrics, req)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func TestOTLPExporterSpans(t *testing.T) {
+	collector := new(otlpCollector)
+	srv := httptest.NewServer(collector)
+	defer srv.Close()
+
+	e := NewOTLPExporter(srv.URL+"/", "hooks/my-hooks", map[string]string{"X-Token": "secret"})
+
+	parent := trace.SpanContext{TraceID: trace.TraceID{1, 2, 3}, SpanID: trace.SpanID{4, 5, 6}, TraceOptions: 1}
+	now := time.Now()
+	e.ExportSpan(&trace.SpanData{
+		SpanContext:  trace.SpanContext{TraceID: parent.TraceID, SpanID: trace.SpanID{7, 8, 9}, TraceOptions: 1},
+		ParentSpanID: parent.SpanID,
+		SpanKind:     trace.SpanKindServer,
+		Name:         "hooks.doTask",
+		StartTime:    now,
+		EndTime:      now.Add(time.Second),
+		Attributes:   map[string]interface{}{"task_uuid": "abc", "retry": int64(2)},
+		Status:       trace.Status{Code: 2, Message: "unknown"},
+	})
+
+	require.NoError(t, e.Flush(context.Background()))
+
+	require.Len(t, collector.traces, 1)
+	require.Len(t, collector.metrics, 0)
+	assert.Equal(t, "secret", collector.headers[0].Get("X-Token"))
+
+	rs := collector.traces[0].ResourceSpans
+	require.Len(t, rs, 1)
+	require.Len(t, rs[0].Resource.Attributes, 2)
+	assert.Equal(t, "service.name", rs[0].Resource.Attributes[0].Key)
+	assert.Equal(t, "hooks/my-hooks", *rs[0].Resource.Attributes[0].Value.StringValue)
+
+	require.Len(t, rs[0].ScopeSpans, 1)
+	require.Len(t, rs[0].ScopeSpans[0].Spans, 1)
+	s := rs[0].ScopeSpans[0].Spans[0]
+	assert.Equal(t, "01020300000000000000000000000000", s.TraceID)
+	assert.Equal(t, "0708090000000000", s.SpanID)
+	assert.Equal(t, "0405060000000000", s.ParentSpanID)
+	assert.Equal(t, "hooks.doTask", s.Name)
+	assert.Equal(t, otlpSpanKindServer, s.Kind)
+	assert.Equal(t, otlpStatusCodeError, s.Status.Code)
+	assert.Len(t, s.Attributes, 2)
+
+	// Nothing is sent if the buffer is empty
+	require.NoError(t, e.Flush(context.Background()))
+	require.Len(t, collector.traces, 1)
+}
+
+func TestOTLPExporterViews(t *testing.T) {
+	collector := new(otlpCollector)
+	srv := httptest.NewServer(collector)
+	defer srv.Close()
+
+	e := NewOTLPExporter(srv.URL, "api/my-api", nil)
+
+	k := MustNewKey("status")
+	m := stats.Int64("test/otlp_measure", "a measure", stats.UnitDimensionless)
+	count := &view.View{Name: "test/otlp_count", Measure: m, Aggregation: view.Count(), TagKeys: []tag.Key{k}}
+	distribution := &view.View{Name: "test/otlp_distribution", Measure: m, Aggregation: view.Distribution(1, 10)}
+
+	now := time.Now()
+	e.ExportView(&view.Data{View: count, Start: now, End: now, Rows: []*view.Row{
+		{Tags: []tag.Tag{{Key: k, Value: "Success"}}, Data: &view.CountData{Value: 3}},
+	}})
+	e.ExportView(&view.Data{View: distribution, Start: now, End: now, Rows: []*view.Row{
+		{Data: &view.DistributionData{Count: 2, Mean: 5, CountPerBucket: []int64{1, 1, 0}}},
+	}})
+	// Only the last data of a view is exported
+	e.ExportView(&view.Data{View: count, Start: now, End: now, Rows: []*view.Row{
+		{Tags: []tag.Tag{{Key: k, Value: "Success"}}, Data: &view.CountData{Value: 5}},
+	}})
+
+	require.NoError(t, e.Flush(context.Background()))
+
+	require.Len(t, collector.metrics, 1)
+	metrics := collector.metrics[0].ResourceMetrics[0].ScopeMetrics[0].Metrics
+	require.Len(t, metrics, 2)
+
+	assert.Equal(t, "test/otlp_count", metrics[0].Name)
+	require.NotNil(t, metrics[0].Sum)
+	assert.True(t, metrics[0].Sum.IsMonotonic)
+	require.Len(t, metrics[0].Sum.DataPoints, 1)
+	assert.Equal(t, "5", *metrics[0].Sum.DataPoints[0].AsInt)
+	assert.Equal(t, "status", metrics[0].Sum.DataPoints[0].Attributes[0].Key)
+
+	assert.Equal(t, "test/otlp_distribution", metrics[1].Name)
+	require.NotNil(t, metrics[1].Histogram)
+	require.Len(t, metrics[1].Histogram.DataPoints, 1)
+	dp := metrics[1].Histogram.DataPoints[0]
+	assert.Equal(t, "2", dp.Count)
+	assert.Equal(t, float64(10), dp.Sum)
+	assert.Equal(t, []string{"1", "1", "0"}, dp.BucketCounts)
+	assert.Equal(t, []float64{1, 10}, dp.ExplicitBounds)
+}
+
+func TestOTLPExporterCollectorError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	e := NewOTLPExporter(srv.URL, "api/my-api", nil)
+	e.ExportSpan(&trace.SpanData{Name: "test"})
+	assert.Error(t, e.Flush(context.Background()))
+}
+
+func TestOTLPExporterMetricsSentOnTracesError(t *testing.T) {
+	collector := new(otlpCollector)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v1/traces" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		collector.ServeHTTP(w, r)
+	}))
+	defer srv.Close()
+
+	e := NewOTLPExporter(srv.URL, "api/my-api", nil)
+	e.ExportSpan(&trace.SpanData{Name: "test"})
+	e.ExportView(&view.Data{View: &view.View{Name: "my-view", Aggregation: view.Count()}, Start: time.Now(), End: time.Now()})
+	assert.Error(t, e.Flush(context.Background()))
+
+	collector.mutex.Lock()
+	defer collector.mutex.Unlock()
+	require.Len(t, collector.metrics, 1)
+}
+
+func TestOTLPExporterRunFlushesFullBuffer(t *testing.T) {
+	collector := new(otlpCollector)
+	srv := httptest.NewServer(collector)
+	defer srv.Close()
+
+	e := NewOTLPExporter(srv.URL, "api/my-api", nil)
+	e.maxSpans = 2
+	for i := 0; i < 5; i++ {
+		e.ExportSpan(&trace.SpanData{Name: "test"})
+	}
+	// Only one flush is pending whatever the number of spans exported
+	require.Len(t, e.full, 1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go e.Run(ctx, time.Hour)
+
+	require.Eventually(t, func() bool {
+		collector.mutex.Lock()
+		defer collector.mutex.Unlock()
+		return len(collector.traces) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	collector.mutex.Lock()
+	defer collector.mutex.Unlock()
+	assert.Len(t, collector.traces[0].ResourceSpans[0].ScopeSpans[0].Spans, 5)
+}
+
+func TestSpanContextHeaders(t *testing.T) {
+	sc := trace.SpanContext{TraceID: trace.TraceID{1, 2, 3}, SpanID: trace.SpanID{4, 5, 6}, TraceOptions: 1}
+
+	headers := SpanContextToHeaders(sc)
+	assert.Equal(t, "00-01020300000000000000000000000000-0405060000000000-01", headers["Traceparent"])
+	assert.Equal(t, "01020300000000000000000000000000", headers["X-B3-Traceid"])
+
+	res, ok := SpanContextFromHeaders(headers)
+	require.True(t, ok)
+	assert.Equal(t, sc.TraceID, res.TraceID)
+	assert.Equal(t, sc.SpanID, res.SpanID)
+	assert.True(t, res.IsSampled())
+
+	// B3 only headers, as stored in workflow run headers
+	res, ok = SpanContextFromHeaders(map[string]string{
+		TraceIDHeader: "01020300000000000000000000000000",
+		SpanIDHeader:  "0405060000000000",
+		SampledHeader: "1",
+	})
+	require.True(t, ok)
+	assert.Equal(t, sc.TraceID, res.TraceID)
+	assert.Equal(t, sc.SpanID, res.SpanID)
+
+	_, ok = SpanContextFromHeaders(nil)
+	assert.False(t, ok)
+	_, ok = SpanContextFromHeaders(map[string]string{"foo": "bar"})
+	assert.False(t, ok)
+}
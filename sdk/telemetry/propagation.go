@@ -0,0 +1,97 @@
+package telemetry
+
+import (
+	"context"
+	"net/http"
+
+	"go.opencensus.io/trace"
+	"go.opencensus.io/trace/propagation"
+)
+
+// W3C trace context header, see https://www.w3.org/TR/trace-context/
+const (
+	TraceParentHeader = "traceparent"
+)
+
+// HTTPFormat propagates span contexts with several formats. The span context is written with all the formats
+// and read from the first format that finds it.
+type HTTPFormat struct {
+	formats []propagation.HTTPFormat
+}
+
+var _ propagation.HTTPFormat = new(HTTPFormat)
+
+// SpanContextFromRequest extracts a span context from incoming requests.
+func (f *HTTPFormat) SpanContextFromRequest(req *http.Request) (trace.SpanContext, bool) {
+	for _, format := range f.formats {
+		if sc, ok := format.SpanContextFromRequest(req); ok {
+			return sc, true
+		}
+	}
+	return trace.SpanContext{}, false
+}
+
+// SpanContextToRequest modifies the given request to include span context headers.
+func (f *HTTPFormat) SpanContextToRequest(sc trace.SpanContext, req *http.Request) {
+	for _, format := range f.formats {
+		format.SpanContextToRequest(sc, req)
+	}
+}
+
+// SpanContextToHeaders returns the propagation headers of given span context. They can be stored to continue
+// the trace asynchronously, see SpanContextFromHeaders.
+func SpanContextToHeaders(sc trace.SpanContext) map[string]string {
+	req := &http.Request{Header: http.Header{}}
+	DefaultFormat.SpanContextToRequest(sc, req)
+	headers := make(map[string]string, len(req.Header))
+	for k := range req.Header {
+		headers[k] = req.Header.Get(k)
+	}
+	return headers
+}
+
+// SpanContextFromHeaders returns the span context stored in given propagation headers.
+func SpanContextFromHeaders(headers map[string]string) (trace.SpanContext, bool) {
+	if len(headers) == 0 {
+		return trace.SpanContext{}, false
+	}
+	req := &http.Request{Header: http.Header{}}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	return DefaultFormat.SpanContextFromRequest(req)
+}
+
+// ContextToHeaders returns the propagation headers of the span context found in given context, nil if none.
+func ContextToHeaders(ctx context.Context) map[string]string {
+	if span := trace.FromContext(ctx); span != nil {
+		return SpanContextToHeaders(span.SpanContext())
+	}
+	if sc, ok := ContextToSpanContext(ctx); ok {
+		return SpanContextToHeaders(sc)
+	}
+	return nil
+}
+
+// NewWithRemoteParent may start a tracing span, child of the span found in given propagation headers. A new
+// root span is started if there is no parent.
+func NewWithRemoteParent(ctx context.Context, s Service, name string, headers map[string]string, spanKind int) (context.Context, *trace.Span) {
+	parent, ok := SpanContextFromHeaders(headers)
+	if !ok {
+		return New(ctx, s, name, nil, spanKind)
+	}
+	if TraceExporter(ctx) == nil {
+		return ctx, nil
+	}
+	var opts = []trace.StartOption{trace.WithSpanKind(spanKind)}
+	if parent.IsSampled() {
+		opts = append(opts, trace.WithSampler(trace.AlwaysSample()))
+	}
+	ctx, span := trace.StartSpanWithRemoteParent(ctx, name, parent, opts...)
+	ctx = SpanContextToContext(ctx, span.SpanContext())
+	ctx = ContextWithTag(ctx,
+		TagServiceType, s.Type(),
+		TagServiceName, s.Name(),
+	)
+	return ctx, span
+}
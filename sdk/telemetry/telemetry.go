@@ -57,23 +57,49 @@ func Init(ctx context.Context, cfg Configuration, s Service) (context.Context, e
 		TagServiceName, s.Name(),
 	)
 
+	var otlpExporter *OTLPExporter
+	if cfg.Exporters.OTLP.Endpoint != "" {
+		log.Info(ctx, "observability> initializing OTLP exporter for %s/%s", s.Type(), s.Name())
+		otlpExporter = NewOTLPExporter(cfg.Exporters.OTLP.Endpoint, serviceName(s), cfg.Exporters.OTLP.Headers)
+		if cfg.Exporters.OTLP.ExportPeriod <= 0 {
+			cfg.Exporters.OTLP.ExportPeriod = 10
+		}
+		go otlpExporter.Run(ctx, time.Duration(cfg.Exporters.OTLP.ExportPeriod)*time.Second)
+	}
+
 	if cfg.TracingEnabled {
+		samplingProbability := cfg.Exporters.Jaeger.SamplingProbability
+		if cfg.Exporters.OTLP.SamplingProbability > samplingProbability {
+			samplingProbability = cfg.Exporters.OTLP.SamplingProbability
+		}
 		trace.ApplyConfig(
 			trace.Config{
-				DefaultSampler: trace.ProbabilitySampler(cfg.Exporters.Jaeger.SamplingProbability),
+				DefaultSampler: trace.ProbabilitySampler(samplingProbability),
 			},
 		)
-		log.Info(ctx, "observability> initializing jaeger exporter for %s/%s", s.Type(), s.Name())
-		e, err := jaeger.NewExporter(jaeger.Options{
-			Endpoint:          cfg.Exporters.Jaeger.HTTPCollectorEndpoint, //"http://localhost:14268"
-			CollectorEndpoint: cfg.Exporters.Jaeger.CollectorEndpoint,
-			ServiceName:       serviceName(s),
-		})
-		if err != nil {
-			return ctx, sdk.WithStack(err)
+		if cfg.Exporters.Jaeger.HTTPCollectorEndpoint != "" || cfg.Exporters.Jaeger.CollectorEndpoint != "" {
+			log.Info(ctx, "observability> initializing jaeger exporter for %s/%s", s.Type(), s.Name())
+			e, err := jaeger.NewExporter(jaeger.Options{
+				Endpoint:          cfg.Exporters.Jaeger.HTTPCollectorEndpoint, //"http://localhost:14268"
+				CollectorEndpoint: cfg.Exporters.Jaeger.CollectorEndpoint,
+				ServiceName:       serviceName(s),
+			})
+			if err != nil {
+				return ctx, sdk.WithStack(err)
+			}
+			trace.RegisterExporter(e)
+			ctx = context.WithValue(ctx, contextTraceExporter, e)
+		}
+		if otlpExporter != nil {
+			trace.RegisterExporter(otlpExporter)
+			if TraceExporter(ctx) == nil {
+				ctx = context.WithValue(ctx, contextTraceExporter, otlpExporter)
+			}
 		}
-		trace.RegisterExporter(e)
-		ctx = context.WithValue(ctx, contextTraceExporter, e)
+	}
+
+	if otlpExporter != nil && cfg.MetricsEnabled {
+		view.RegisterExporter(otlpExporter)
 	}
 
 	if cfg.MetricsEnabled {
@@ -113,6 +139,7 @@ const (
 	TagPipeline           = "pipeline"
 	TagPipelineDeep       = "pipeline_deep"
 	TagWorker             = "worker"
+	TagWorkerModel        = "worker_model"
 	TagPermission         = "permission"
 )
 
@@ -158,3 +185,15 @@ func MustNewKey(s string) tag.Key {
 	}
 	return k
 }
+
+// Flush sends the data buffered by the OTLP exporter of given context if any. It should be called before a short
+// lived process exits.
+func Flush(ctx context.Context) error {
+	if e, ok := TraceExporter(ctx).(*OTLPExporter); ok {
+		// The given context may already be cancelled
+		flushCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		return e.Flush(flushCtx)
+	}
+	return nil
+}
@@ -6,6 +6,7 @@ import (
 
 	"contrib.go.opencensus.io/exporter/prometheus"
 	"go.opencensus.io/plugin/ochttp/propagation/b3"
+	"go.opencensus.io/plugin/ochttp/propagation/tracecontext"
 	"go.opencensus.io/stats/view"
 	"go.opencensus.io/trace/propagation"
 )
@@ -20,7 +21,9 @@ const (
 )
 
 // DefaultFormat used by observability as: observability.DefaultFormat.SpanContextToRequest
-var DefaultFormat propagation.HTTPFormat = &b3.HTTPFormat{}
+var DefaultFormat propagation.HTTPFormat = &HTTPFormat{
+	formats: []propagation.HTTPFormat{&tracecontext.HTTPFormat{}, &b3.HTTPFormat{}},
+}
 
 // Configuration is the global tracing configuration
 type Configuration struct {
@@ -35,6 +38,12 @@ type Configuration struct {
 		Prometheus struct {
 			ReporteringPeriod int `toml:"ReporteringPeriod" default:"10" json:"reporteringPeriod"`
 		} `json:"prometheus"`
+		OTLP struct {
+			Endpoint            string            `toml:"endpoint" default:"" comment:"OpenTelemetry collector OTLP/HTTP endpoint (ex: http://localhost:4318), traces and metrics are exported if set" json:"endpoint"`
+			Headers             map[string]string `toml:"headers" comment:"Headers added to each request sent to the collector" json:"-"`
+			SamplingProbability float64           `toml:"samplingProbability" json:"samplingProbability"`
+			ExportPeriod        int               `toml:"exportPeriod" default:"10" comment:"Export period in seconds" json:"exportPeriod"`
+		} `json:"otlp"`
 	} `json:"exporter"`
 }
 
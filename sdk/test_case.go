@@ -0,0 +1,200 @@
+package sdk
+
+import (
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/ovh/venom"
+)
+
+// Test case status.
+const (
+	TestCaseStatusSuccess = "success"
+	TestCaseStatusFailure = "failure"
+	TestCaseStatusSkipped = "skipped"
+)
+
+// A test case is flaky if it passed and failed on the same commit, or if its status
+// changed in at least FlakyTestFlipRate of its last runs (with at least FlakyTestMinRuns runs).
+const (
+	FlakyTestMinRuns  = 5
+	FlakyTestFlipRate = 0.3
+)
+
+// WorkflowNodeRunTestCase is the result of a test case for a node run.
+type WorkflowNodeRunTestCase struct {
+	ID                int64     `json:"id" db:"id" cli:"-"`
+	WorkflowID        int64     `json:"workflow_id" db:"workflow_id" cli:"-"`
+	WorkflowRunID     int64     `json:"workflow_run_id" db:"workflow_run_id" cli:"-"`
+	WorkflowNodeRunID int64     `json:"workflow_node_run_id" db:"workflow_node_run_id" cli:"-"`
+	NodeName          string    `json:"node_name" db:"node_name" cli:"node"`
+	Num               int64     `json:"num" db:"run_number" cli:"num"`
+	Branch            string    `json:"branch" db:"branch" cli:"branch"`
+	Hash              string    `json:"hash" db:"vcs_hash" cli:"hash"`
+	Suite             string    `json:"suite" db:"suite" cli:"suite"`
+	Name              string    `json:"name" db:"name" cli:"name"`
+	Status            string    `json:"status" db:"status" cli:"status"`
+	Duration          float64   `json:"duration" db:"duration" cli:"duration"`
+	Created           time.Time `json:"created" db:"created" cli:"created"`
+}
+
+// TestCasesFromTests returns the test case results of given tests for a node run.
+func TestCasesFromTests(nr WorkflowNodeRun, tests venom.Tests) []WorkflowNodeRunTestCase {
+	var res []WorkflowNodeRunTestCase
+	for _, ts := range tests.TestSuites {
+		for _, tc := range ts.TestCases {
+			name := tc.Name
+			if tc.Classname != "" && tc.Classname != ts.Name {
+				name = tc.Classname + "." + tc.Name
+			}
+			status := TestCaseStatusSuccess
+			if len(tc.Errors) > 0 || len(tc.Failures) > 0 {
+				status = TestCaseStatusFailure
+			} else if len(tc.Skipped) > 0 {
+				status = TestCaseStatusSkipped
+			}
+			duration, _ := strconv.ParseFloat(tc.Time, 64)
+			res = append(res, WorkflowNodeRunTestCase{
+				WorkflowID:        nr.WorkflowID,
+				WorkflowRunID:     nr.WorkflowRunID,
+				WorkflowNodeRunID: nr.ID,
+				NodeName:          nr.WorkflowNodeName,
+				Num:               nr.Number,
+				Branch:            nr.VCSBranch,
+				Hash:              nr.VCSHash,
+				Suite:             ts.Name,
+				Name:              name,
+				Status:            status,
+				Duration:          duration,
+			})
+		}
+	}
+	return res
+}
+
+// TestCaseHistory contains the statistics of a test case on its last runs.
+type TestCaseHistory struct {
+	NodeName        string                    `json:"node_name" cli:"node"`
+	Suite           string                    `json:"suite" cli:"suite"`
+	Name            string                    `json:"name" cli:"name"`
+	Runs            int                       `json:"runs" cli:"runs"`
+	Failures        int                       `json:"failures" cli:"failures"`
+	LastStatus      string                    `json:"last_status" cli:"last_status"`
+	AverageDuration float64                   `json:"average_duration" cli:"average_duration"`
+	FlipRate        float64                   `json:"flip_rate" cli:"flip_rate"`
+	Flaky           bool                      `json:"flaky" cli:"flaky"`
+	FlakyReason     string                    `json:"flaky_reason,omitempty" cli:"flaky_reason"`
+	History         []WorkflowNodeRunTestCase `json:"history,omitempty" cli:"-"`
+}
+
+// ComputeTestCaseHistory returns the statistics of a test case from its results, most recent first.
+func ComputeTestCaseHistory(results []WorkflowNodeRunTestCase) TestCaseHistory {
+	var h TestCaseHistory
+	if len(results) == 0 {
+		return h
+	}
+	h.NodeName, h.Suite, h.Name = results[0].NodeName, results[0].Suite, results[0].Name
+	h.LastStatus = results[0].Status
+	h.History = results
+
+	var executed []WorkflowNodeRunTestCase
+	var totalDuration float64
+	for _, r := range results {
+		if r.Status == TestCaseStatusSkipped {
+			continue
+		}
+		executed = append(executed, r)
+		totalDuration += r.Duration
+		if r.Status == TestCaseStatusFailure {
+			h.Failures++
+		}
+	}
+	h.Runs = len(executed)
+	if h.Runs == 0 {
+		return h
+	}
+	h.AverageDuration = totalDuration / float64(h.Runs)
+
+	var flips int
+	statusByHash := make(map[string]string)
+	for i, r := range executed {
+		if i > 0 && r.Status != executed[i-1].Status {
+			flips++
+		}
+		if r.Hash == "" {
+			continue
+		}
+		if s, ok := statusByHash[r.Hash]; ok && s != r.Status && !h.Flaky {
+			h.Flaky = true
+			h.FlakyReason = "passed and failed on commit " + r.Hash
+		}
+		statusByHash[r.Hash] = r.Status
+	}
+	if h.Runs > 1 {
+		h.FlipRate = float64(flips) / float64(h.Runs-1)
+	}
+	if !h.Flaky && h.Runs >= FlakyTestMinRuns && h.FlipRate >= FlakyTestFlipRate {
+		h.Flaky = true
+		h.FlakyReason = "status changed in " + strconv.Itoa(flips) + " of the last " + strconv.Itoa(h.Runs) + " runs"
+	}
+	return h
+}
+
+// WorkflowTestsSummary contains the slowest, failing and flaky test cases of a workflow.
+type WorkflowTestsSummary struct {
+	Slowest []TestCaseHistory `json:"slowest"`
+	Failing []TestCaseHistory `json:"failing"`
+	Flaky   []TestCaseHistory `json:"flaky"`
+}
+
+// ComputeWorkflowTestsSummary groups given results, most recent first, by test case and returns
+// at most limit test cases for each category. History of test cases is not returned.
+func ComputeWorkflowTestsSummary(results []WorkflowNodeRunTestCase, limit int) WorkflowTestsSummary {
+	type key struct{ node, suite, name string }
+	var keys []key
+	byKey := make(map[key][]WorkflowNodeRunTestCase)
+	for _, r := range results {
+		k := key{r.NodeName, r.Suite, r.Name}
+		if _, ok := byKey[k]; !ok {
+			keys = append(keys, k)
+		}
+		byKey[k] = append(byKey[k], r)
+	}
+
+	s := WorkflowTestsSummary{
+		Slowest: []TestCaseHistory{},
+		Failing: []TestCaseHistory{},
+		Flaky:   []TestCaseHistory{},
+	}
+	for _, k := range keys {
+		h := ComputeTestCaseHistory(byKey[k])
+		h.History = nil
+		if h.Runs == 0 {
+			continue
+		}
+		s.Slowest = append(s.Slowest, h)
+		if h.LastStatus == TestCaseStatusFailure {
+			s.Failing = append(s.Failing, h)
+		}
+		if h.Flaky {
+			s.Flaky = append(s.Flaky, h)
+		}
+	}
+
+	sort.SliceStable(s.Slowest, func(i, j int) bool { return s.Slowest[i].AverageDuration > s.Slowest[j].AverageDuration })
+	sort.SliceStable(s.Failing, func(i, j int) bool { return s.Failing[i].Failures > s.Failing[j].Failures })
+	sort.SliceStable(s.Flaky, func(i, j int) bool { return s.Flaky[i].FlipRate > s.Flaky[j].FlipRate })
+	if limit > 0 {
+		if len(s.Slowest) > limit {
+			s.Slowest = s.Slowest[:limit]
+		}
+		if len(s.Failing) > limit {
+			s.Failing = s.Failing[:limit]
+		}
+		if len(s.Flaky) > limit {
+			s.Flaky = s.Flaky[:limit]
+		}
+	}
+	return s
+}
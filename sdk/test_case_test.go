@@ -0,0 +1,76 @@
+package sdk
+
+import (
+	"testing"
+
+	"github.com/ovh/venom"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTestCasesFromTests(t *testing.T) {
+	nr := WorkflowNodeRun{ID: 3, WorkflowID: 1, WorkflowRunID: 2, WorkflowNodeName: "build", Number: 12, VCSBranch: "master", VCSHash: "abc"}
+	cases := TestCasesFromTests(nr, venom.Tests{TestSuites: []venom.TestSuite{{
+		Name: "pkg",
+		TestCases: []venom.TestCase{
+			{Name: "TestA", Classname: "pkg", Time: "1.5"},
+			{Name: "TestB", Classname: "pkg.sub", Failures: []venom.Failure{{Value: "boom"}}},
+			{Name: "TestC", Skipped: []venom.Skipped{{Value: "skip"}}},
+		},
+	}}})
+	require.Len(t, cases, 3)
+	require.Equal(t, "TestA", cases[0].Name)
+	require.Equal(t, TestCaseStatusSuccess, cases[0].Status)
+	require.Equal(t, 1.5, cases[0].Duration)
+	require.Equal(t, "build", cases[0].NodeName)
+	require.Equal(t, "abc", cases[0].Hash)
+	require.Equal(t, "pkg.sub.TestB", cases[1].Name)
+	require.Equal(t, TestCaseStatusFailure, cases[1].Status)
+	require.Equal(t, TestCaseStatusSkipped, cases[2].Status)
+}
+
+func TestComputeTestCaseHistory(t *testing.T) {
+	// Same commit, different results
+	h := ComputeTestCaseHistory([]WorkflowNodeRunTestCase{
+		{Name: "TestA", Hash: "abc", Status: TestCaseStatusSuccess, Duration: 1},
+		{Name: "TestA", Hash: "abc", Status: TestCaseStatusFailure, Duration: 3},
+		{Name: "TestA", Hash: "def", Status: TestCaseStatusSkipped},
+	})
+	require.Equal(t, 2, h.Runs)
+	require.Equal(t, 1, h.Failures)
+	require.Equal(t, 2.0, h.AverageDuration)
+	require.Equal(t, TestCaseStatusSuccess, h.LastStatus)
+	require.True(t, h.Flaky)
+	require.Contains(t, h.FlakyReason, "abc")
+
+	// High flip rate on different commits
+	var results []WorkflowNodeRunTestCase
+	for i, s := range []string{TestCaseStatusSuccess, TestCaseStatusFailure, TestCaseStatusSuccess, TestCaseStatusSuccess, TestCaseStatusFailure} {
+		results = append(results, WorkflowNodeRunTestCase{Name: "TestB", Hash: string(rune('a' + i)), Status: s})
+	}
+	h = ComputeTestCaseHistory(results)
+	require.Equal(t, 0.75, h.FlipRate)
+	require.True(t, h.Flaky)
+
+	// Stable test
+	h = ComputeTestCaseHistory(results[2:4])
+	require.False(t, h.Flaky)
+	require.Equal(t, 0.0, h.FlipRate)
+}
+
+func TestComputeWorkflowTestsSummary(t *testing.T) {
+	s := ComputeWorkflowTestsSummary([]WorkflowNodeRunTestCase{
+		{Suite: "s", Name: "TestA", Hash: "2", Status: TestCaseStatusFailure, Duration: 1},
+		{Suite: "s", Name: "TestB", Hash: "2", Status: TestCaseStatusSuccess, Duration: 5},
+		{Suite: "s", Name: "TestC", Hash: "2", Status: TestCaseStatusSuccess, Duration: 2},
+		{Suite: "s", Name: "TestA", Hash: "1", Status: TestCaseStatusSuccess, Duration: 1},
+		{Suite: "s", Name: "TestC", Hash: "2", Status: TestCaseStatusFailure, Duration: 2},
+	}, 2)
+	require.Len(t, s.Slowest, 2)
+	require.Equal(t, "TestB", s.Slowest[0].Name)
+	require.Equal(t, "TestC", s.Slowest[1].Name)
+	require.Len(t, s.Failing, 1)
+	require.Equal(t, "TestA", s.Failing[0].Name)
+	require.Len(t, s.Flaky, 1)
+	require.Equal(t, "TestC", s.Flaky[0].Name)
+	require.Nil(t, s.Slowest[0].History)
+}
@@ -4,7 +4,10 @@ import (
 	"context"
 	"database/sql/driver"
 	json "encoding/json"
+	"fmt"
+	"net"
 	"net/http"
+	"strings"
 	"time"
 
 	jwt "github.com/dgrijalva/jwt-go"
@@ -29,6 +32,13 @@ type AuthDriverWithSigninStateToken interface {
 	CheckSigninStateToken(AuthConsumerSigninRequest) error
 }
 
+// AuthDriverWithUserGroups is implemented by drivers that can list the external
+// groups of a user without a signin request (used for periodic group synchronization).
+type AuthDriverWithUserGroups interface {
+	AuthDriver
+	GetUserGroups(ctx context.Context, username string) ([]string, error)
+}
+
 type AuthDriverSigningRedirect struct {
 	Method      string            `json:"method"`
 	URL         string            `json:"url"`
@@ -86,10 +96,35 @@ type AuthConsumerScopeEndpoint struct {
 	Methods StringSlice `json:"methods,omitempty"`
 }
 
-// AuthConsumerScopeDetail contains all endpoints for a scope.
+// AuthConsumerScopeDetail contains all endpoints for a scope and optional
+// resource filters (project keys and workflow names).
 type AuthConsumerScopeDetail struct {
-	Scope     AuthConsumerScope          `json:"scope"`
-	Endpoints AuthConsumerScopeEndpoints `json:"endpoints,omitempty"`
+	Scope         AuthConsumerScope          `json:"scope"`
+	Endpoints     AuthConsumerScopeEndpoints `json:"endpoints,omitempty"`
+	ProjectKeys   StringSlice                `json:"project_keys,omitempty"`
+	WorkflowNames StringSlice                `json:"workflow_names,omitempty"`
+}
+
+// String returns the detail as printed before resource filters were added when
+// there is no filter, so the canonical form of existing consumers is unchanged.
+func (d AuthConsumerScopeDetail) String() string {
+	if len(d.ProjectKeys) == 0 && len(d.WorkflowNames) == 0 {
+		return fmt.Sprintf("{%v %v}", d.Scope, d.Endpoints)
+	}
+	return fmt.Sprintf("{%v %v %v %v}", d.Scope, d.Endpoints, d.ProjectKeys, d.WorkflowNames)
+}
+
+// IsAllowedResource returns true if given project key and workflow name match
+// scope's resource filters. Empty filters means all resources are allowed and
+// empty values are not checked (ex: a route without workflow name).
+func (d AuthConsumerScopeDetail) IsAllowedResource(projectKey, workflowName string) bool {
+	if projectKey != "" && len(d.ProjectKeys) > 0 && !d.ProjectKeys.Contains(projectKey) {
+		return false
+	}
+	if workflowName != "" && len(d.WorkflowNames) > 0 && !d.WorkflowNames.Contains(workflowName) {
+		return false
+	}
+	return true
 }
 
 // IsValid returns validity for scope.
@@ -181,6 +216,23 @@ func (s AuthConsumerScopeDetails) IsValid() error {
 				}
 			}
 		}
+
+		// Check that resource filters are not empty and unique
+		for _, filter := range []struct {
+			name   string
+			values StringSlice
+		}{{"project key", detail.ProjectKeys}, {"workflow name", detail.WorkflowNames}} {
+			mValue := map[string]struct{}{}
+			for _, v := range filter.values {
+				if v == "" {
+					return NewErrorFrom(ErrWrongRequest, "invalid empty %s for scope %s in given details", filter.name, detail.Scope)
+				}
+				if _, ok := mValue[v]; ok {
+					return NewErrorFrom(ErrWrongRequest, "duplicated %s %s for scope %s in given details", filter.name, v, detail.Scope)
+				}
+				mValue[v] = struct{}{}
+			}
+		}
 	}
 
 	return nil
@@ -255,6 +307,7 @@ type AuthDriverUserInfo struct {
 	Fullname   string
 	Email      string
 	MFA        bool
+	Groups     []string
 }
 
 // AuthCurrentConsumerResponse describe the current consumer and the current session
@@ -392,6 +445,7 @@ type AuthConsumer struct {
 	IssuedAt           time.Time                `json:"issued_at" cli:"issued_at" db:"issued_at"`
 	Disabled           bool                     `json:"disabled" cli:"disabled" db:"disabled"`
 	Warnings           AuthConsumerWarnings     `json:"warnings,omitempty" db:"warnings"`
+	AllowedCIDRs       StringSlice              `json:"allowed_cidrs,omitempty" cli:"allowed_cidrs" db:"allowed_cidrs"`
 	// aggregates
 	AuthentifiedUser *AuthentifiedUser `json:"user,omitempty" db:"-"`
 	Groups           Groups            `json:"groups,omitempty" db:"-"`
@@ -409,6 +463,10 @@ func (c AuthConsumer) IsValid(scopeDetails AuthConsumerScopeDetails) error {
 		return err
 	}
 
+	if err := IsValidCIDRs(c.AllowedCIDRs); err != nil {
+		return err
+	}
+
 	mEndpoints := scopeDetails.ToEndpointsMap()
 
 	for _, s := range c.ScopeDetails {
@@ -449,6 +507,77 @@ func (c AuthConsumer) GetGroupIDs() []int64 {
 	return groupIDs
 }
 
+// IsAllowedIP returns true if given ip matches one of the allowed CIDRs
+// for the consumer. An empty list means that any ip is allowed.
+func (c AuthConsumer) IsAllowedIP(ip net.IP) bool {
+	if len(c.AllowedCIDRs) == 0 {
+		return true
+	}
+	if ip == nil {
+		return false
+	}
+	for _, cidr := range c.AllowedCIDRs {
+		_, ipNet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			continue
+		}
+		if ipNet.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckChildAllowedCIDRs returns an error if given CIDRs are less restrictive
+// than the consumer ones. A consumer restricted to some networks can't create or
+// update a consumer that could be used outside of these networks.
+func (c AuthConsumer) CheckChildAllowedCIDRs(cidrs []string) error {
+	if len(c.AllowedCIDRs) == 0 {
+		return nil
+	}
+	if len(cidrs) == 0 {
+		return NewErrorFrom(ErrWrongRequest, "allowed CIDRs should be given as current consumer is restricted to %s", strings.Join(c.AllowedCIDRs, ", "))
+	}
+	for _, cidr := range cidrs {
+		_, ipNet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return NewErrorFrom(ErrWrongRequest, "invalid given CIDR %s", cidr)
+		}
+		ones, _ := ipNet.Mask.Size()
+		var found bool
+		for _, parentCIDR := range c.AllowedCIDRs {
+			_, parentNet, err := net.ParseCIDR(parentCIDR)
+			if err != nil {
+				continue
+			}
+			parentOnes, _ := parentNet.Mask.Size()
+			if parentNet.Contains(ipNet.IP) && parentOnes <= ones {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return NewErrorFrom(ErrWrongRequest, "given CIDR %s is not included in current consumer allowed CIDRs", cidr)
+		}
+	}
+	return nil
+}
+
+// IsValidCIDRs returns an error if one of given values is not a valid CIDR.
+func IsValidCIDRs(cidrs []string) error {
+	m := make(map[string]struct{}, len(cidrs))
+	for _, cidr := range cidrs {
+		if _, _, err := net.ParseCIDR(cidr); err != nil {
+			return NewErrorFrom(ErrWrongRequest, "invalid given CIDR %s", cidr)
+		}
+		if _, ok := m[cidr]; ok {
+			return NewErrorFrom(ErrWrongRequest, "duplicated CIDR %s in given values", cidr)
+		}
+		m[cidr] = struct{}{}
+	}
+	return nil
+}
+
 func (c AuthConsumer) Admin() bool {
 	return c.AuthentifiedUser.Ring == UserRingAdmin
 }
@@ -478,6 +607,22 @@ func (c AuthConsumer) GetFullname() string {
 	return c.AuthentifiedUser.GetFullname()
 }
 
+// AuthConsumerIPRejection stores an authentication attempt for a consumer
+// that was rejected because the client ip is not in the allowed CIDRs.
+type AuthConsumerIPRejection struct {
+	ID         int64     `json:"id" cli:"id,key" db:"id"`
+	ConsumerID string    `json:"consumer_id" cli:"consumer_id" db:"consumer_id"`
+	IP         string    `json:"ip" cli:"ip" db:"ip"`
+	Method     string    `json:"method" cli:"method" db:"method"`
+	Route      string    `json:"route" cli:"route" db:"route"`
+	Created    time.Time `json:"created" cli:"created" db:"created"`
+}
+
+// AuthConsumerAllowedCIDRsRequest is used to update the allowed CIDRs of a consumer.
+type AuthConsumerAllowedCIDRsRequest struct {
+	AllowedCIDRs StringSlice `json:"allowed_cidrs"`
+}
+
 // AuthSessions gives functions for auth session slice.
 type AuthSessions []AuthSession
 
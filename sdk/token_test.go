@@ -1,6 +1,8 @@
 package sdk_test
 
 import (
+	"fmt"
+	"net"
 	"net/http"
 	"testing"
 
@@ -96,6 +98,26 @@ func TestAuthConsumerScopeDetailsIsValid(t *testing.T) {
 			},
 			Error: true,
 		},
+		{
+			Name: "Duplicate project keys should generate an error",
+			Details: sdk.AuthConsumerScopeDetails{
+				{
+					Scope:       sdk.AuthConsumerScopeRun,
+					ProjectKeys: []string{"PROJ", "PROJ"},
+				},
+			},
+			Error: true,
+		},
+		{
+			Name: "Empty workflow name should generate an error",
+			Details: sdk.AuthConsumerScopeDetails{
+				{
+					Scope:         sdk.AuthConsumerScopeRun,
+					WorkflowNames: []string{""},
+				},
+			},
+			Error: true,
+		},
 	}
 
 	for _, c := range cases {
@@ -109,3 +131,99 @@ func TestAuthConsumerScopeDetailsIsValid(t *testing.T) {
 		})
 	}
 }
+
+func TestAuthConsumerScopeDetailIsAllowedResource(t *testing.T) {
+	d := sdk.AuthConsumerScopeDetail{
+		Scope:         sdk.AuthConsumerScopeRun,
+		ProjectKeys:   []string{"PROJ"},
+		WorkflowNames: []string{"my-workflow"},
+	}
+	assert.True(t, d.IsAllowedResource("PROJ", "my-workflow"))
+	assert.True(t, d.IsAllowedResource("PROJ", ""))
+	assert.True(t, d.IsAllowedResource("", ""))
+	assert.False(t, d.IsAllowedResource("PROJ", "other-workflow"))
+	assert.False(t, d.IsAllowedResource("OTHER", "my-workflow"))
+
+	d = sdk.AuthConsumerScopeDetail{Scope: sdk.AuthConsumerScopeRun}
+	assert.True(t, d.IsAllowedResource("OTHER", "other-workflow"))
+}
+
+func TestAuthConsumerScopeDetailString(t *testing.T) {
+	// Same struct as before resource filters were added, used to sign existing consumers
+	type legacyScopeDetail struct {
+		Scope     sdk.AuthConsumerScope
+		Endpoints sdk.AuthConsumerScopeEndpoints
+	}
+	endpoints := sdk.AuthConsumerScopeEndpoints{{Route: "/project/{permProjectKey}", Methods: []string{http.MethodGet}}}
+
+	ds := sdk.AuthConsumerScopeDetails{
+		{Scope: sdk.AuthConsumerScopeUser},
+		{Scope: sdk.AuthConsumerScopeProject, Endpoints: endpoints},
+	}
+	legacy := []legacyScopeDetail{
+		{Scope: sdk.AuthConsumerScopeUser},
+		{Scope: sdk.AuthConsumerScopeProject, Endpoints: endpoints},
+	}
+	assert.Equal(t, fmt.Sprint(legacy), fmt.Sprint(ds))
+
+	d := sdk.AuthConsumerScopeDetail{Scope: sdk.AuthConsumerScopeRun, ProjectKeys: []string{"PROJ"}}
+	assert.Equal(t, "{Run [] [PROJ] []}", fmt.Sprint(d))
+}
+
+func TestAuthConsumerIsAllowedIP(t *testing.T) {
+	cases := []struct {
+		Name    string
+		CIDRs   []string
+		IP      string
+		Allowed bool
+	}{
+		{
+			Name:    "No CIDR should allow any ip",
+			IP:      "192.168.1.12",
+			Allowed: true,
+		},
+		{
+			Name:    "Ip in given CIDR should be allowed",
+			CIDRs:   []string{"10.0.0.0/8", "192.168.1.0/24"},
+			IP:      "192.168.1.12",
+			Allowed: true,
+		},
+		{
+			Name:    "Ip not in given CIDR should be rejected",
+			CIDRs:   []string{"10.0.0.0/8", "192.168.1.0/24"},
+			IP:      "192.168.2.12",
+			Allowed: false,
+		},
+		{
+			Name:    "Ipv6 in given CIDR should be allowed",
+			CIDRs:   []string{"2001:db8::/32"},
+			IP:      "2001:db8::1",
+			Allowed: true,
+		},
+		{
+			Name:    "Invalid ip should be rejected",
+			CIDRs:   []string{"10.0.0.0/8"},
+			IP:      "invalid",
+			Allowed: false,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			consumer := sdk.AuthConsumer{AllowedCIDRs: c.CIDRs}
+			assert.Equal(t, c.Allowed, consumer.IsAllowedIP(net.ParseIP(c.IP)))
+		})
+	}
+}
+
+func TestAuthConsumerCheckChildAllowedCIDRs(t *testing.T) {
+	parent := sdk.AuthConsumer{AllowedCIDRs: []string{"10.0.0.0/8"}}
+	assert.NoError(t, parent.CheckChildAllowedCIDRs([]string{"10.1.0.0/16", "10.2.3.4/32"}))
+	assert.Error(t, parent.CheckChildAllowedCIDRs(nil))
+	assert.Error(t, parent.CheckChildAllowedCIDRs([]string{"0.0.0.0/0"}))
+	assert.Error(t, parent.CheckChildAllowedCIDRs([]string{"192.168.0.0/16"}))
+
+	assert.NoError(t, sdk.AuthConsumer{}.CheckChildAllowedCIDRs(nil))
+	assert.Error(t, sdk.IsValidCIDRs([]string{"10.0.0.1"}))
+	assert.Error(t, sdk.IsValidCIDRs([]string{"10.0.0.0/8", "10.0.0.0/8"}))
+}
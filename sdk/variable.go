@@ -124,6 +124,9 @@ const (
 	BooleanVariable    = "boolean"
 	NumberVariable     = "number"
 	RepositoryVariable = "repository"
+	// VaultVariable is a reference to a secret stored in a Vault project integration,
+	// its value is resolved when a job is taken by a worker.
+	VaultVariable = "vault"
 )
 
 var (
@@ -134,6 +137,7 @@ var (
 		StringVariable,
 		BooleanVariable,
 		NumberVariable,
+		VaultVariable,
 	}
 
 	BasicVariableNames = []string{
@@ -181,6 +185,33 @@ func NeedPlaceholder(t string) bool {
 	}
 }
 
+// VaultSecretReference is the parsed value of a vault variable, formatted as
+// [integrationName:]path#key. If the integration name is omitted, the project
+// should have only one Vault integration.
+type VaultSecretReference struct {
+	IntegrationName string
+	Path            string
+	Key             string
+}
+
+// ParseVaultSecretReference returns the reference for given vault variable value.
+func ParseVaultSecretReference(s string) (VaultSecretReference, error) {
+	var ref VaultSecretReference
+	i := strings.LastIndex(s, "#")
+	if i < 0 {
+		return ref, NewErrorFrom(ErrWrongRequest, "invalid vault reference %q, format should be [integration:]path#key", s)
+	}
+	ref.Path, ref.Key = s[:i], s[i+1:]
+	if j := strings.Index(ref.Path, ":"); j >= 0 {
+		ref.IntegrationName, ref.Path = ref.Path[:j], ref.Path[j+1:]
+	}
+	ref.Path = strings.Trim(ref.Path, "/")
+	if ref.Path == "" || ref.Key == "" {
+		return ref, NewErrorFrom(ErrWrongRequest, "invalid vault reference %q, format should be [integration:]path#key", s)
+	}
+	return ref, nil
+}
+
 // VariableFind return a variable given its name if it exists in array
 func VariableFind(vars []Variable, s string) *Variable {
 	for _, v := range vars {
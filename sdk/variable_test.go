@@ -58,3 +58,33 @@ func Test_EnvVartoENV(t *testing.T) {
 		})
 	}
 }
+
+func TestParseVaultSecretReference(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    sdk.VaultSecretReference
+		wantErr bool
+	}{
+		{value: "secret/data/my-app#password", want: sdk.VaultSecretReference{Path: "secret/data/my-app", Key: "password"}},
+		{value: "my-vault:/secret/my-app#token", want: sdk.VaultSecretReference{IntegrationName: "my-vault", Path: "secret/my-app", Key: "token"}},
+		{value: "secret/my-app", wantErr: true},
+		{value: "my-vault:#token", wantErr: true},
+		{value: "secret/my-app#", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := sdk.ParseVaultSecretReference(tt.value)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ParseVaultSecretReference(%q) expected error", tt.value)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseVaultSecretReference(%q) unexpected error: %v", tt.value, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseVaultSecretReference(%q) = %+v, want %+v", tt.value, got, tt.want)
+		}
+	}
+}
@@ -219,6 +219,7 @@ type VCSAuthorizedClientCommon interface {
 	Commits(ctx context.Context, repo, branch, since, until string) ([]VCSCommit, error)
 	Commit(ctx context.Context, repo, hash string) (VCSCommit, error)
 	CommitsBetweenRefs(ctx context.Context, repo, base, head string) ([]VCSCommit, error)
+	FilesBetweenRefs(ctx context.Context, repo, base, head string) ([]string, error)
 
 	// PullRequests
 	PullRequest(ctx context.Context, repo string, id int) (VCSPullRequest, error)
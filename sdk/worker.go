@@ -55,7 +55,9 @@ type WorkerArgs struct {
 	GraylogPort       int    `json:"graylog_port"`
 	GraylogExtraKey   string `json:"graylog_extra_key"`
 	GraylogExtraValue string `json:"graylog_extra_value"`
-	WorkerBinary      string
+	//Tracing params
+	OTLPEndpoint string `json:"otlp_endpoint"`
+	WorkerBinary string
 }
 
 // TemplateEnvs return envs interpolated with worker arguments
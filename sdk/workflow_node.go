@@ -28,6 +28,8 @@ type Node struct {
 	JoinContext         []NodeJoin        `json:"parents" db:"-"`
 	Hooks               []NodeHook        `json:"hooks" db:"-"`
 	Groups              []GroupPermission `json:"groups,omitempty" db:"-"`
+	// ChildDepth is only set on child pipelines added at run time, 1 for children of a pipeline of the workflow
+	ChildDepth int64 `json:"child_depth,omitempty" db:"-"`
 }
 
 func (n Node) GetHook(UUID string) *NodeHook {
@@ -0,0 +1,65 @@
+package sdk
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// WorkflowPurgePolicy describes which runs of a workflow are kept by the purge. When a policy is set, the last
+// HistoryLength runs of the workflow are kept, with all the runs kept by a rule of the policy. Other runs are deleted.
+type WorkflowPurgePolicy struct {
+	// KeepLastPerBranch is the number of runs kept for each git branch
+	KeepLastPerBranch int64 `json:"keep_last_per_branch,omitempty" yaml:"keep_last_per_branch,omitempty" jsonschema_description:"Number of runs kept for each git branch."`
+	// KeepReleases keeps all the runs triggered on a git tag
+	KeepReleases bool `json:"keep_releases,omitempty" yaml:"keep_releases,omitempty" jsonschema_description:"Keep all the runs triggered on a git tag."`
+	// KeepDays keeps all the runs younger than given number of days
+	KeepDays int64 `json:"keep_days,omitempty" yaml:"keep_days,omitempty" jsonschema_description:"Keep all the runs younger than given number of days."`
+	// DeleteFailedAfterDays deletes failed runs older than given number of days, even if kept by another rule
+	// except KeepReleases
+	DeleteFailedAfterDays int64 `json:"delete_failed_after_days,omitempty" yaml:"delete_failed_after_days,omitempty" jsonschema_description:"Delete failed runs older than given number of days, even if kept by another rule except keep_releases."`
+}
+
+// IsEmpty returns true if no rule is set in the policy.
+func (p WorkflowPurgePolicy) IsEmpty() bool {
+	return p == WorkflowPurgePolicy{}
+}
+
+// IsValid returns an error if the policy contains negative values.
+func (p WorkflowPurgePolicy) IsValid() error {
+	if p.KeepLastPerBranch < 0 || p.KeepDays < 0 || p.DeleteFailedAfterDays < 0 {
+		return NewErrorFrom(ErrWorkflowInvalid, "invalid purge policy: values should be positive")
+	}
+	return nil
+}
+
+// Value returns driver.Value from WorkflowPurgePolicy.
+func (p WorkflowPurgePolicy) Value() (driver.Value, error) {
+	j, err := json.Marshal(p)
+	return j, WrapError(err, "cannot marshal WorkflowPurgePolicy")
+}
+
+// Scan WorkflowPurgePolicy.
+func (p *WorkflowPurgePolicy) Scan(src interface{}) error {
+	if src == nil {
+		return nil
+	}
+	source, ok := src.([]byte)
+	if !ok {
+		return WithStack(fmt.Errorf("type assertion .([]byte) failed (%T)", src))
+	}
+	return WrapError(json.Unmarshal(source, p), "cannot unmarshal WorkflowPurgePolicy")
+}
+
+// WorkflowRunPurge is a workflow run that would be deleted by the purge.
+type WorkflowRunPurge struct {
+	ID           int64     `json:"id" db:"id" cli:"-"`
+	Number       int64     `json:"num" db:"num" cli:"num,key"`
+	Status       string    `json:"status" db:"status" cli:"status"`
+	Branch       string    `json:"branch,omitempty" db:"branch" cli:"branch"`
+	Tag          string    `json:"tag,omitempty" db:"tag" cli:"tag"`
+	Start        time.Time `json:"start" db:"start" cli:"start"`
+	LastModified time.Time `json:"last_modified" db:"last_modified" cli:"last_modified"`
+	Reason       string    `json:"reason,omitempty" db:"-" cli:"reason"`
+}
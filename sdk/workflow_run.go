@@ -94,6 +94,8 @@ type WorkflowRunPostHandlerOption struct {
 	Number         *int64                    `json:"number,omitempty"`
 	FromNodeIDs    []int64                   `json:"from_nodes,omitempty"`
 	AuthConsumerID string                    `json:"auth_consumer,omitempty"`
+	// TraceHeaders contains the propagation headers of the span that triggered the run
+	TraceHeaders map[string]string `json:"trace_headers,omitempty"`
 }
 
 // Value returns driver.Value from WorkflowRunPostHandlerOption.
@@ -352,8 +354,9 @@ type WorkflowNodeRunCoverage struct {
 
 // WorkflowNodeRunCoverageTrends represents code coverage trend with current branch and default branch
 type WorkflowNodeRunCoverageTrends struct {
-	CurrentBranch coverage.Report `json:"current_branch_report"`
-	DefaultBranch coverage.Report `json:"default_branch_report"`
+	CurrentBranch coverage.Report    `json:"current_branch_report"`
+	DefaultBranch coverage.Report    `json:"default_branch_report"`
+	FilesDiff     []CoverageFileDiff `json:"files_diff,omitempty"`
 }
 
 // WorkflowNodeTriggerRun Represent the state of a trigger
@@ -504,6 +507,7 @@ type WorkflowNodeRunHookEvent struct {
 		Run       int64  `json:"run" db:"-"`
 		HookRunID string `hook_run_id:"uuid" db:"-"`
 	} `json:"parent_workflow" db:"-"`
+	TraceHeaders map[string]string `json:"trace_headers,omitempty" db:"-"`
 }
 
 //WorkflowNodeRunManual is an instanc of event received on a hook
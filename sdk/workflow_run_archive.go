@@ -0,0 +1,53 @@
+package sdk
+
+import (
+	"fmt"
+	"time"
+)
+
+// WorkflowRunArchive references a workflow run that was serialized in the storage before being purged.
+type WorkflowRunArchive struct {
+	ID           int64     `json:"id" db:"id" cli:"-"`
+	ProjectKey   string    `json:"project_key" db:"project_key" cli:"-"`
+	WorkflowID   int64     `json:"workflow_id" db:"workflow_id" cli:"-"`
+	WorkflowName string    `json:"workflow_name" db:"workflow_name" cli:"-"`
+	Number       int64     `json:"num" db:"num" cli:"num,key"`
+	Version      *string   `json:"version,omitempty" db:"version" cli:"version"`
+	Status       string    `json:"status" db:"status" cli:"status"`
+	Start        time.Time `json:"start" db:"start" cli:"start"`
+	LastModified time.Time `json:"last_modified" db:"last_modified" cli:"last_modified"`
+	Archived     time.Time `json:"archived" db:"archived" cli:"archived"`
+	Size         int64     `json:"size" db:"size" cli:"size"`
+}
+
+// NewWorkflowRunArchive returns the archive reference of given workflow run.
+func NewWorkflowRunArchive(projectKey string, wr WorkflowRun) WorkflowRunArchive {
+	return WorkflowRunArchive{
+		ProjectKey:   projectKey,
+		WorkflowID:   wr.WorkflowID,
+		WorkflowName: wr.Workflow.Name,
+		Number:       wr.Number,
+		Version:      wr.Version,
+		Status:       wr.Status,
+		Start:        wr.Start,
+		LastModified: wr.LastModified,
+	}
+}
+
+// GetName returns the name of the archive file in the storage.
+func (a WorkflowRunArchive) GetName() string {
+	return fmt.Sprintf("%d.json", a.Number)
+}
+
+// GetPath returns the container of the archive file in the storage, all the archives of a workflow share
+// the same container.
+func (a WorkflowRunArchive) GetPath() string {
+	return fmt.Sprintf("workflow-run-archive-%d", a.WorkflowID)
+}
+
+// WorkflowRunArchiveData is the content of a workflow run archive, it can only be read.
+type WorkflowRunArchiveData struct {
+	Archive  WorkflowRunArchive        `json:"archive"`
+	Run      WorkflowRun               `json:"run"`
+	Analyses []WorkflowNodeRunAnalysis `json:"analyses,omitempty"`
+}
@@ -12,6 +12,15 @@ export class Coverage {
 export class CoverageTrend {
     current_branch_report: CoverageReport;
     default_branch_report: CoverageReport;
+    files_diff: Array<CoverageFileDiff>;
+}
+
+export class CoverageFileDiff {
+    path: string;
+    lines: number;
+    default_lines: number;
+    delta: number;
+    new: boolean;
 }
 
 export class CoverageReport {
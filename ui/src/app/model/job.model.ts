@@ -10,6 +10,8 @@ export class Job {
     warnings: Array<ActionWarning>;
     worker_name: string;
     worker_id: string;
+    matrix: JobMatrix;
+    matrix_values: { [axis: string]: string };
 
     // UI parameter
     hasChanged: boolean;
@@ -22,6 +24,13 @@ export class Job {
     }
 }
 
+export class JobMatrix {
+    axes: { [axis: string]: Array<string> };
+    include: Array<{ [axis: string]: string }>;
+    exclude: Array<{ [axis: string]: string }>;
+    max_parallel: number;
+}
+
 export class StepStatus {
     step_order: number;
     status: string;
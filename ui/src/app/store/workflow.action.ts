@@ -69,7 +69,7 @@ export class SelectWorkflowNodeRun {
 
 export class SelectWorkflowNodeRunJob {
     static readonly type = '[Workflow] Select Workflow Node Job Run';
-    constructor(public payload: { jobID: number}) { }
+    constructor(public payload: { jobID: number, runJobID?: number }) { }
 }
 
 export class UpdateWorkflowRunList {
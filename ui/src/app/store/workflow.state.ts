@@ -1246,7 +1246,10 @@ export class WorkflowState {
                     sidebar: WorkflowSidebarMode.RUN_NODE
                 });
                 if (stateNR.workflowNodeJobRun) {
-                    ctx.dispatch(new SelectWorkflowNodeRunJob({ jobID: stateNR.workflowNodeJobRun.job.pipeline_action_id }));
+                    ctx.dispatch(new SelectWorkflowNodeRunJob({
+                        jobID: stateNR.workflowNodeJobRun.job.pipeline_action_id,
+                        runJobID: stateNR.workflowNodeJobRun.job.matrix_values ? stateNR.workflowNodeJobRun.id : null
+                    }));
                 }
             }));
     }
@@ -1339,7 +1342,9 @@ export class WorkflowState {
                 if (s.run_jobs) {
                     for (let j = 0; j < s.run_jobs.length; j++) {
                         let rj = s.run_jobs[j];
-                        if (rj.job.pipeline_action_id === action.payload.jobID) {
+                        // Job runs created from a job matrix share the same pipeline action id
+                        if (rj.job.pipeline_action_id === action.payload.jobID &&
+                            (!action.payload.runJobID || rj.id === action.payload.runJobID)) {
                             ctx.setState({
                                 ...state,
                                 workflowNodeJobRun: rj
@@ -38,6 +38,8 @@ export class WorkflowRunNodePipelineComponent implements OnInit, OnDestroy {
     jobTime: Map<number, string>;
     mapJobStatus: Map<number, { status: string, warnings: number, start: string, done: string }>
         = new Map<number, { status: string, warnings: number, start: string, done: string }>();
+    // Job runs created from a job matrix, by pipeline action id
+    mapMatrixRunJobs: Map<number, Array<WorkflowNodeJobRun>> = new Map<number, Array<WorkflowNodeJobRun>>();
 
     queryParamsSub: Subscription;
     pipelineStatusEnum = PipelineStatus;
@@ -45,6 +47,7 @@ export class WorkflowRunNodePipelineComponent implements OnInit, OnDestroy {
     currentNodeRunID: number;
     currentNodeRunNum: number;
     currentJob: Job;
+    currentRunJobID: number;
 
     displayServiceLogs = false;
     durationIntervalID: number;
@@ -60,6 +63,20 @@ export class WorkflowRunNodePipelineComponent implements OnInit, OnDestroy {
         this.workflowName = (<WorkflowStateModel>this._store.selectSnapshot(WorkflowState)).workflowRun.workflow.name;
     }
 
+    /**
+     * Compute the status of a job matrix from the status of its job runs
+     */
+    static matrixStatus(runJobs: Array<WorkflowNodeJobRun>): string {
+        const priorities = [PipelineStatus.FAIL, PipelineStatus.BUILDING, PipelineStatus.WAITING, PipelineStatus.PENDING,
+            PipelineStatus.STOPPED, PipelineStatus.SUCCESS, PipelineStatus.SKIPPED, PipelineStatus.DISABLED];
+        for (let i = 0; i < priorities.length; i++) {
+            if (runJobs.find(rj => rj.status === priorities[i])) {
+                return priorities[i];
+            }
+        }
+        return runJobs[0].status;
+    }
+
     ngOnInit() {
         this.nodeJobRunSubs = this.nodeJobRun$.subscribe(rj => {
             if (!rj && !this.currentJob) {
@@ -70,10 +87,12 @@ export class WorkflowRunNodePipelineComponent implements OnInit, OnDestroy {
                 this._cd.markForCheck();
                 return;
             }
-            if (rj && this.currentJob && rj.job.pipeline_action_id === this.currentJob.pipeline_action_id) {
+            if (rj && this.currentJob && rj.job.pipeline_action_id === this.currentJob.pipeline_action_id &&
+                rj.id === this.currentRunJobID) {
                 return;
             }
             this.currentJob = rj.job;
+            this.currentRunJobID = rj.id;
             this._cd.markForCheck();
         });
         this.nodeRunSubs = this.nodeRun$.subscribe(nr => {
@@ -112,18 +131,53 @@ export class WorkflowRunNodePipelineComponent implements OnInit, OnDestroy {
         this.selectJob(jobID);
     }
 
-    selectJob(jobID: number): void {
-        if (this.currentJob && jobID === this.currentJob.pipeline_action_id) {
+    selectMatrixRunJob(event: Event, rj: WorkflowNodeJobRun) {
+        event.stopPropagation();
+        let queryParams = cloneDeep(this._route.snapshot.queryParams);
+        queryParams['stageId'] = null;
+        queryParams['actionId'] = null;
+        queryParams['stepOrder'] = null;
+        queryParams['line'] = null;
+        this._router.navigate(['.'], { relativeTo: this._route, queryParams, fragment: null });
+        this.selectJob(rj.job.pipeline_action_id, rj.id);
+    }
+
+    selectJob(jobID: number, runJobID?: number): void {
+        if (this.currentJob && jobID === this.currentJob.pipeline_action_id && (!runJobID || runJobID === this.currentRunJobID)) {
             return;
         }
-        this._store.dispatch(new SelectWorkflowNodeRunJob({jobID: jobID}));
+        this._store.dispatch(new SelectWorkflowNodeRunJob({jobID, runJobID}));
+    }
+
+    matrixLabel(rj: WorkflowNodeJobRun): string {
+        return Object.keys(rj.job.matrix_values).sort().map(k => k + ':' + rj.job.matrix_values[k]).join(', ');
     }
 
+
     refreshNodeRun(data: WorkflowNodeRun): boolean {
         let refresh = false;
         let currentNodeJobRun = (<WorkflowStateModel>this._store.selectSnapshot(WorkflowState)).workflowNodeJobRun;
 
         if (data.stages) {
+            // Group job runs created from a job matrix
+            let matrixRunJobs = new Map<number, Array<WorkflowNodeJobRun>>();
+            data.stages.forEach(s => {
+                if (s.run_jobs) {
+                    s.run_jobs.filter(rj => rj.job.matrix_values).forEach(rj => {
+                        let runJobs = matrixRunJobs.get(rj.job.pipeline_action_id) || [];
+                        runJobs.push(rj);
+                        matrixRunJobs.set(rj.job.pipeline_action_id, runJobs);
+                    });
+                }
+            });
+            matrixRunJobs.forEach((runJobs, jobID) => {
+                let previous = this.mapMatrixRunJobs.get(jobID);
+                if (!previous || previous.length !== runJobs.length || previous.some((rj, i) => rj.status !== runJobs[i].status)) {
+                    refresh = true;
+                }
+            });
+            this.mapMatrixRunJobs = matrixRunJobs;
+
             data.stages.forEach((s, sIndex) => {
                 // Test Job status
                 if (s.run_jobs) {
@@ -140,19 +194,27 @@ export class WorkflowRunNodePipelineComponent implements OnInit, OnDestroy {
                             });
                         }
 
-                        // Update job status
+                        // Update job status, for a job matrix the status is computed from all its job runs
+                        let status = rj.status;
+                        let start = rj.start;
+                        let done = rj.done;
+                        let runJobs = matrixRunJobs.get(rj.job.pipeline_action_id);
+                        if (runJobs) {
+                            status = WorkflowRunNodePipelineComponent.matrixStatus(runJobs);
+                            start = runJobs.map(r => r.start).filter(d => !!d).sort()[0] || start;
+                            done = runJobs.map(r => r.done).filter(d => !!d).sort().reverse()[0] || done;
+                        }
                         let jobStatusItem = this.mapJobStatus.get(rj.job.pipeline_action_id);
-                        if (!jobStatusItem || jobStatusItem.status !== rj.status) {
+                        if (!jobStatusItem || jobStatusItem.status !== status) {
                             refresh = true;
-                            this.mapJobStatus.set(rj.job.pipeline_action_id,
-                                { status: rj.status, warnings, start: rj.start, done: rj.done });
+                            this.mapJobStatus.set(rj.job.pipeline_action_id, { status, warnings, start, done });
                         }
 
                         if (!currentNodeJobRun && sIndex === 0 && rjIndex === 0 && !this._route.snapshot.queryParams['actionId']) {
                             refresh = true;
                             this.selectJob(s.jobs[0].pipeline_action_id);
                         } else if (currentNodeJobRun && currentNodeJobRun.job.pipeline_action_id === this.currentJob.pipeline_action_id) {
-                            this.selectJob(this.currentJob.pipeline_action_id);
+                            this.selectJob(this.currentJob.pipeline_action_id, this.currentJob.matrix_values ? this.currentRunJobID : null);
                         } else if (this._route.snapshot.queryParams['actionId'] &&
                             this._route.snapshot.queryParams['actionId'] === rj.job.pipeline_action_id.toString()) {
                             this.selectJob(rj.job.pipeline_action_id);